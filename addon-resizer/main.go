@@ -39,20 +39,25 @@ const noValue = "MISSING"
 var (
 	// Flags to define the resource requirements.
 	baseCPU              = flag.String("cpu", noValue, "The base CPU resource requirement.")
-	cpuPerNode           = flag.String("extra-cpu", "0", "The amount of CPU to add per node.")
+	cpuPerNode           = flag.String("extra-cpu", "0", "The amount of CPU to add per node, or per unit of the dimension.")
 	baseMemory           = flag.String("memory", noValue, "The base memory resource requirement.")
-	memoryPerNode        = flag.String("extra-memory", "0Mi", "The amount of memory to add per node.")
+	memoryPerNode        = flag.String("extra-memory", "0Mi", "The amount of memory to add per node, or per unit of the dimension.")
 	baseStorage          = flag.String("storage", noValue, "The base storage resource requirement.")
-	storagePerNode       = flag.String("extra-storage", "0Gi", "The amount of storage to add per node.")
+	storagePerNode       = flag.String("extra-storage", "0Gi", "The amount of storage to add per node, or per unit of the dimension.")
 	scaleDownDelay       = flag.Duration("scale-down-delay", time.Duration(0), "The time to wait after the addon-resizer start or last scaling operation before the scale down can be performed.")
 	scaleUpDelay         = flag.Duration("scale-up-delay", time.Duration(0), "The time to wait after the addon-resizer start or last scaling operation before the scale up can be performed.")
 	recommendationOffset = flag.Int("recommendation-offset", 10, "A number from range 0-100. When the dependent's resources are rewritten, they are set to the closer end of the range defined by this percentage threshold.")
 	acceptanceOffset     = flag.Int("acceptance-offset", 20, "A number from range 0-100. The dependent's resources are rewritten when they deviate from expected by a percentage that is higher than this threshold. Can't be lower than recommendation-offset.")
+	estimatorConfigMap   = flag.String("estimator-config-map", "", "The name of a ConfigMap in the namespace of the ward holding a piecewise estimator config under the estimator.yaml key. Changes to the ConfigMap are applied without restarting. Replaces the cpu, memory and storage flags.")
+	// Flags to define the cluster size the resources scale with.
+	dimension    = flag.String("dimension", string(nanny.NodesDimension), "What the resources scale with: nodes, pods, containers, namespaces or custom-metric.")
+	customMetric = flag.String("custom-metric", "", "The path of an object metric below /apis/custom.metrics.k8s.io/v1beta2 the resources scale with when dimension is custom-metric, e.g. namespaces/monitoring/services/prometheus/active-series. Values of all matching objects are summed up.")
 	// Flags to identify the container to nanny.
 	podNamespace  = flag.String("namespace", os.Getenv("MY_POD_NAMESPACE"), "The namespace of the ward. This defaults to the nanny pod's own namespace.")
-	deployment    = flag.String("deployment", "", "The name of the deployment being monitored. This is required.")
+	deployment    = flag.String("deployment", "", "The name of the deployment being monitored. This is required unless targets-config is set.")
 	podName       = flag.String("pod", os.Getenv("MY_POD_NAME"), "The name of the pod to watch. This defaults to the nanny's own pod.")
 	containerName = flag.String("container", "pod-nanny", "The name of the container to watch. This defaults to the nanny itself.")
+	targetsConfig = flag.String("targets-config", "", "The path of a file listing Deployments, DaemonSets and StatefulSets with the piecewise estimator configs of their containers to nanny. Replaces the deployment, pod, container, estimator-config-map, cpu, memory and storage flags.")
 	// Flags to control runtime behavior.
 	pollPeriodMillis = flag.Int("poll-period", 10000, "The time, in milliseconds, to poll the dependent container.")
 )
@@ -114,18 +119,22 @@ func main() {
 	flag.Parse()
 
 	// Perform further validation of flags.
-	if *deployment == "" {
-		log.Fatal("Must specify a deployment.")
+	if *deployment == "" && *targetsConfig == "" {
+		log.Fatal("Must specify a deployment or a targets config.")
 	}
 
 	checkPercentageFlagBounds("recommendation-offset", *recommendationOffset)
 	checkPercentageFlagBounds("acceptance-offset", *acceptanceOffset)
 
+	clusterSize := nanny.Dimension{Kind: nanny.DimensionKind(*dimension), CustomMetric: *customMetric}
+	if err := clusterSize.Validate(); err != nil {
+		log.Fatal(err)
+	}
+
 	pollPeriod := time.Duration(int64(*pollPeriodMillis) * int64(time.Millisecond))
 	log.Infof("Version: %s", nanny.AddonResizerVersion)
 	log.Infof("Poll period: %+v", pollPeriod)
-	log.Infof("Watching namespace: %s, pod: %s, container: %s.", *podNamespace, *podName, *containerName)
-	log.Infof("cpu: %s, extra_cpu: %s, memory: %s, extra_memory: %s, storage: %s, extra_storage: %s", *baseCPU, *cpuPerNode, *baseMemory, *memoryPerNode, *baseStorage, *storagePerNode)
+	log.Infof("Dimension: %s", clusterSize)
 	log.Infof("Accepted range +/-%d%%", *acceptanceOffset)
 	log.Infof("Recommended range +/-%d%%", *recommendationOffset)
 
@@ -137,7 +146,19 @@ func main() {
 		kubeClient = GetClientOrDie()
 	}
 
-	k8s := nanny.NewKubernetesClient(kubeClient, *podNamespace, *deployment, *podName, *containerName)
+	if *targetsConfig != "" {
+		runController(kubeClient, clusterSize, pollPeriod)
+		return
+	}
+
+	log.Infof("Watching namespace: %s, pod: %s, container: %s.", *podNamespace, *podName, *containerName)
+	if *estimatorConfigMap != "" {
+		log.Infof("Estimator config map: %s", *estimatorConfigMap)
+	} else {
+		log.Infof("cpu: %s, extra_cpu: %s, memory: %s, extra_memory: %s, storage: %s, extra_storage: %s", *baseCPU, *cpuPerNode, *baseMemory, *memoryPerNode, *baseStorage, *storagePerNode)
+	}
+
+	k8s := nanny.NewKubernetesClient(kubeClient, *podNamespace, *deployment, *podName, *containerName, clusterSize)
 
 	var resources []nanny.Resource
 
@@ -166,7 +187,19 @@ func main() {
 		})
 	}
 
-	log.Infof("Resources: %+v", resources)
+	var est nanny.ResourceEstimator
+	var configMapEstimator *nanny.ConfigMapEstimator
+	if *estimatorConfigMap != "" {
+		configMapEstimator = nanny.NewConfigMapEstimator(kubeClient, *podNamespace, *estimatorConfigMap, int64(*acceptanceOffset), int64(*recommendationOffset))
+		est = configMapEstimator
+	} else {
+		log.Infof("Resources: %+v", resources)
+		est = nanny.Estimator{
+			AcceptanceOffset:     int64(*acceptanceOffset),
+			RecommendationOffset: int64(*recommendationOffset),
+			Resources:            resources,
+		}
+	}
 
 	// handle termination info
 	ch := make(chan os.Signal, 1)
@@ -175,18 +208,38 @@ func main() {
 		<-ch
 		log.Infof("Received termination, signaling shutdown")
 		k8s.Stop()
+		if configMapEstimator != nil {
+			configMapEstimator.Stop()
+		}
 		os.Exit(0)
 	}()
 
 	// Begin nannying.
 	nanny.PollAPIServer(
 		k8s,
-		nanny.Estimator{
-			AcceptanceOffset:     int64(*acceptanceOffset),
-			RecommendationOffset: int64(*recommendationOffset),
-			Resources:            resources,
-		},
+		est,
 		pollPeriod,
 		*scaleDownDelay,
 		*scaleUpDelay)
 }
+
+// runController nannies the targets listed in the targets config file.
+func runController(kubeClient kubernetes.Interface, clusterSize nanny.Dimension, pollPeriod time.Duration) {
+	controller, err := nanny.NewController(kubeClient, *targetsConfig, clusterSize, int64(*acceptanceOffset), int64(*recommendationOffset))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// handle termination info
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ch
+		log.Infof("Received termination, signaling shutdown")
+		controller.Stop()
+		os.Exit(0)
+	}()
+
+	// Begin nannying.
+	controller.Run(pollPeriod, *scaleDownDelay, *scaleUpDelay)
+}
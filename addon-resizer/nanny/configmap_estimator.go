@@ -0,0 +1,115 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"fmt"
+	"sync"
+
+	kube_client "k8s.io/client-go/kubernetes"
+	v1lister "k8s.io/client-go/listers/core/v1"
+
+	log "github.com/golang/glog"
+)
+
+// ConfigMapKey is the key of the ConfigMap data holding the PiecewiseConfig.
+const ConfigMapKey = "estimator.yaml"
+
+// ConfigMapEstimator is a PiecewiseEstimator configured from a ConfigMap. The
+// configuration is reloaded whenever the ConfigMap changes. If the new
+// configuration is invalid, the last valid one is kept.
+type ConfigMapEstimator struct {
+	lister               v1lister.ConfigMapNamespaceLister
+	name                 string
+	acceptanceOffset     int64
+	recommendationOffset int64
+	stopChannel          chan<- struct{}
+
+	mutex sync.Mutex
+	// ResourceVersion of the last ConfigMap loaded, valid or not.
+	resourceVersion string
+	estimator       *PiecewiseEstimator
+}
+
+// NewConfigMapEstimator gives a ConfigMapEstimator configured from the ConfigMap
+// with the given name and namespace.
+func NewConfigMapEstimator(kubeClient kube_client.Interface, namespace, name string, acceptanceOffset, recommendationOffset int64) *ConfigMapEstimator {
+	lister, stopCh := newConfigMapListerByNamespace(kubeClient, namespace)
+	estimator := newConfigMapEstimator(lister, name, acceptanceOffset, recommendationOffset)
+	estimator.stopChannel = stopCh
+	return estimator
+}
+
+func newConfigMapEstimator(lister v1lister.ConfigMapNamespaceLister, name string, acceptanceOffset, recommendationOffset int64) *ConfigMapEstimator {
+	return &ConfigMapEstimator{
+		lister:               lister,
+		name:                 name,
+		acceptanceOffset:     acceptanceOffset,
+		recommendationOffset: recommendationOffset,
+	}
+}
+
+// Stop stops watching the ConfigMap.
+func (e *ConfigMapEstimator) Stop() {
+	if e.stopChannel != nil {
+		e.stopChannel <- struct{}{}
+	}
+}
+
+// Computes the acceptable and recommended resource ranges for a cluster of the
+// specified size with the current configuration. Returns nil if no valid
+// configuration was loaded yet.
+func (e *ConfigMapEstimator) scale(size uint64) *EstimatorResult {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	if err := e.reload(); err != nil {
+		log.Errorf("Cannot reload estimator config from ConfigMap %s: %v", e.name, err)
+	}
+	if e.estimator == nil {
+		return nil
+	}
+	return e.estimator.scale(size)
+}
+
+// reload loads the configuration from the ConfigMap if it changed.
+func (e *ConfigMapEstimator) reload() error {
+	configMap, err := e.lister.Get(e.name)
+	if err != nil {
+		return err
+	}
+	if configMap.ResourceVersion == e.resourceVersion {
+		return nil
+	}
+	e.resourceVersion = configMap.ResourceVersion
+
+	data, found := configMap.Data[ConfigMapKey]
+	if !found {
+		return fmt.Errorf("key %s not found", ConfigMapKey)
+	}
+	config, err := ParsePiecewiseConfig([]byte(data))
+	if err != nil {
+		return err
+	}
+	e.estimator = &PiecewiseEstimator{
+		PiecewiseConfig:      *config,
+		AcceptanceOffset:     e.acceptanceOffset,
+		RecommendationOffset: e.recommendationOffset,
+	}
+	log.Infof("Loaded estimator config from ConfigMap %s version %s: %s", e.name, configMap.ResourceVersion, jsonOrValue(config))
+	return nil
+}
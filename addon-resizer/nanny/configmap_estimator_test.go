@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"testing"
+
+	api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func setTestConfigMap(t *testing.T, store cache.Store, resourceVersion, config string) {
+	configMap := &api.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       "kube-system",
+			Name:            "estimator",
+			ResourceVersion: resourceVersion,
+		},
+		Data: map[string]string{ConfigMapKey: config},
+	}
+	if err := store.Update(configMap); err != nil {
+		t.Fatalf("Cannot update ConfigMap: %v", err)
+	}
+}
+
+func TestConfigMapEstimatorReload(t *testing.T) {
+	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	e := newConfigMapEstimator(v1lister.NewConfigMapLister(store).ConfigMaps("kube-system"), "estimator", 0, 0)
+
+	// No estimation before the ConfigMap exists.
+	if got := e.scale(10); got != nil {
+		t.Errorf("scale without ConfigMap got %+v, want nil", got)
+	}
+
+	setTestConfigMap(t, store, "1", testPiecewiseConfig)
+	verifyRange(t, num(), "RecommendedRange", e.scale(10).RecommendedRange,
+		ResourceListPair{testResources("200m", "100Mi"), testResources("200m", "100Mi")})
+
+	// Changes are applied without recreating the estimator.
+	setTestConfigMap(t, store, "2", "resources:\n  cpu:\n  - from: 0\n    base: 500m\n  memory:\n  - from: 0\n    base: 1Gi")
+	verifyRange(t, num(), "RecommendedRange", e.scale(10).RecommendedRange,
+		ResourceListPair{testResources("500m", "1Gi"), testResources("500m", "1Gi")})
+
+	// An invalid config keeps the last valid one.
+	setTestConfigMap(t, store, "3", "resources: {}")
+	verifyRange(t, num(), "RecommendedRange", e.scale(10).RecommendedRange,
+		ResourceListPair{testResources("500m", "1Gi"), testResources("500m", "1Gi")})
+}
@@ -0,0 +1,258 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	log "github.com/golang/glog"
+	api "k8s.io/api/core/v1"
+	kube_client "k8s.io/client-go/kubernetes"
+)
+
+// Controller nannies the containers of several target workloads. The cluster
+// size is measured once per poll, and each target keeps its own scale up and
+// scale down delay state. The targets are reloaded from the config file
+// whenever it changes. If the new config is invalid, the last valid one is
+// kept.
+type Controller struct {
+	kubeClient           kube_client.Interface
+	configPath           string
+	acceptanceOffset     int64
+	recommendationOffset int64
+	clusterSize          func() (uint64, error)
+	size                 measuredSize
+	targets              []*targetState
+	// workloads creates workloads backed by listers shared by the targets of
+	// the same kind in a namespace. Listers of namespaces without targets
+	// anymore keep running until the Controller is stopped.
+	workloads    map[listerKey]func(name string) workload
+	stopChannels []chan<- struct{}
+	// configData is the content of the last config file loaded, valid or not.
+	configData []byte
+}
+
+type targetState struct {
+	target     Target
+	workload   workload
+	containers []containerState
+	lastChange time.Time
+}
+
+type containerState struct {
+	name      string
+	client    KubernetesClient
+	estimator ResourceEstimator
+}
+
+type listerKey struct {
+	kind      WorkloadKind
+	namespace string
+}
+
+// NewController gives a Controller nannying the targets of the config file at
+// configPath, with resources scaling with the cluster size in the given
+// dimension.
+func NewController(kubeClient kube_client.Interface, configPath string, dimension Dimension, acceptanceOffset, recommendationOffset int64) (*Controller, error) {
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read targets config: %v", err)
+	}
+	config, err := ParseTargetsConfig(data)
+	if err != nil {
+		return nil, err
+	}
+	sizer := newClusterSizer(kubeClient, dimension)
+	c := &Controller{
+		kubeClient:           kubeClient,
+		configPath:           configPath,
+		acceptanceOffset:     acceptanceOffset,
+		recommendationOffset: recommendationOffset,
+		clusterSize:          sizer.ClusterSize,
+		workloads:            make(map[listerKey]func(name string) workload),
+		stopChannels:         sizer.stopChannels,
+		configData:           data,
+	}
+	c.setTargets(config, time.Now())
+	return c, nil
+}
+
+// reloadConfig sets the targets of the config file if it changed since it was
+// last loaded.
+func (c *Controller) reloadConfig(now time.Time) {
+	if c.configPath == "" {
+		return
+	}
+	data, err := ioutil.ReadFile(c.configPath)
+	if err != nil {
+		log.Errorf("Cannot read targets config, keeping the current targets: %v", err)
+		return
+	}
+	if bytes.Equal(data, c.configData) {
+		return
+	}
+	c.configData = data
+	config, err := ParseTargetsConfig(data)
+	if err != nil {
+		log.Errorf("Cannot reload targets config, keeping the current targets: %v", err)
+		return
+	}
+	log.Infof("Reloaded targets config")
+	c.setTargets(config, now)
+}
+
+// setTargets replaces the targets of the Controller with those of the config.
+// Targets that were already nannied keep their scale up and scale down delay
+// state, new targets start their delays now.
+func (c *Controller) setTargets(config *TargetsConfig, now time.Time) {
+	previous := make(map[string]*targetState, len(c.targets))
+	for _, t := range c.targets {
+		previous[t.target.String()] = t
+	}
+	c.targets = nil
+	for _, target := range config.Targets {
+		key := listerKey{kind: target.Kind, namespace: target.Namespace}
+		newWorkload, found := c.workloads[key]
+		if !found {
+			var stopCh chan<- struct{}
+			newWorkload, stopCh = newWorkloadFactory(c.kubeClient, target.Kind, target.Namespace)
+			c.stopChannels = append(c.stopChannels, stopCh)
+			c.workloads[key] = newWorkload
+		}
+		state := c.addTarget(target, newWorkload(target.Name), c.acceptanceOffset, c.recommendationOffset)
+		if p, found := previous[target.String()]; found {
+			state.lastChange = p.lastChange
+		} else {
+			log.Infof("Watching %s", target)
+			state.lastChange = now
+		}
+	}
+}
+
+// newWorkloadFactory gives a function creating workloads of the given kind in
+// the namespace, backed by a shared lister.
+func newWorkloadFactory(kubeClient kube_client.Interface, kind WorkloadKind, namespace string) (func(name string) workload, chan<- struct{}) {
+	switch kind {
+	case DaemonSetKind:
+		lister, stopCh := newDaemonSetListerByNamespace(kubeClient, namespace)
+		client := kubeClient.AppsV1().DaemonSets(namespace)
+		return func(name string) workload {
+			return &daemonSetWorkload{name: name, lister: lister, client: client}
+		}, stopCh
+	case StatefulSetKind:
+		lister, stopCh := newStatefulSetListerByNamespace(kubeClient, namespace)
+		client := kubeClient.AppsV1().StatefulSets(namespace)
+		return func(name string) workload {
+			return &statefulSetWorkload{name: name, lister: lister, client: client}
+		}, stopCh
+	default:
+		lister, stopCh := newDeploymentListerByNamespace(kubeClient, namespace)
+		client := kubeClient.AppsV1().Deployments(namespace)
+		return func(name string) workload {
+			return &deploymentWorkload{name: name, lister: lister, client: client}
+		}, stopCh
+	}
+}
+
+func (c *Controller) addTarget(target Target, w workload, acceptanceOffset, recommendationOffset int64) *targetState {
+	state := &targetState{target: target, workload: w}
+	for _, container := range target.Containers {
+		state.containers = append(state.containers, containerState{
+			name: container.Name,
+			client: &workloadClient{
+				target:    target,
+				container: container.Name,
+				workload:  w,
+				size:      &c.size,
+			},
+			estimator: PiecewiseEstimator{
+				PiecewiseConfig:      container.PiecewiseConfig,
+				AcceptanceOffset:     acceptanceOffset,
+				RecommendationOffset: recommendationOffset,
+			},
+		})
+	}
+	c.targets = append(c.targets, state)
+	return state
+}
+
+// Stop stops the listers of the Controller.
+func (c *Controller) Stop() {
+	for _, ch := range c.stopChannels {
+		ch <- struct{}{}
+	}
+}
+
+// Run periodically measures the cluster size and updates the containers of all
+// targets whose resources are out of the acceptable range.
+func (c *Controller) Run(pollPeriod, scaleDownDelay, scaleUpDelay time.Duration) {
+	for i := 0; true; i++ {
+		if i != 0 {
+			// Sleep for the poll period.
+			time.Sleep(pollPeriod)
+		}
+		c.poll(time.Now(), scaleDownDelay, scaleUpDelay)
+	}
+}
+
+// poll reloads the targets config if it changed, measures the cluster size and
+// updates the resources of the containers of each target. All containers of a
+// target that need an update are updated together, in a single update of the
+// workload, which restarts the scale up and scale down delay of the target.
+func (c *Controller) poll(now time.Time, scaleDownDelay, scaleUpDelay time.Duration) {
+	c.reloadConfig(now)
+	c.size.size, c.size.err = c.clusterSize()
+	for _, t := range c.targets {
+		log.V(4).Infof("Checking %s", t.target)
+		updates := make(map[string]*api.ResourceRequirements)
+		for _, container := range t.containers {
+			resources, result := estimateResources(container.client, container.estimator, now, t.lastChange, scaleDownDelay, scaleUpDelay)
+			if result == overwrite {
+				updates[container.name] = resources
+			}
+		}
+		if len(updates) == 0 {
+			continue
+		}
+		if err := t.updateContainers(updates); err != nil {
+			log.Errorf("Cannot update %s: %v", t.target, err)
+			continue
+		}
+		t.lastChange = now
+	}
+}
+
+// updateContainers sets the resources of the given containers in the pod
+// template of the target.
+func (t *targetState) updateContainers(resources map[string]*api.ResourceRequirements) error {
+	return t.workload.updatePodTemplate(func(template *api.PodTemplateSpec) error {
+		updated := 0
+		for i, container := range template.Spec.Containers {
+			if r, found := resources[container.Name]; found {
+				template.Spec.Containers[i].Resources = *r
+				updated++
+			}
+		}
+		if updated != len(resources) {
+			return fmt.Errorf("%d of %d containers to update were not found", len(resources)-updated, len(resources))
+		}
+		return nil
+	})
+}
@@ -0,0 +1,233 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	api "k8s.io/api/core/v1"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeWorkload struct {
+	template api.PodTemplateSpec
+	updates  int
+}
+
+func (w *fakeWorkload) podTemplate() (*api.PodTemplateSpec, error) {
+	return &w.template, nil
+}
+
+func (w *fakeWorkload) updatePodTemplate(update func(*api.PodTemplateSpec) error) error {
+	template := *w.template.DeepCopy()
+	if err := update(&template); err != nil {
+		return err
+	}
+	w.template = template
+	w.updates++
+	return nil
+}
+
+func newFakeWorkload(containers ...string) *fakeWorkload {
+	w := &fakeWorkload{}
+	for _, name := range containers {
+		w.template.Spec.Containers = append(w.template.Spec.Containers, api.Container{
+			Name: name,
+			Resources: api.ResourceRequirements{
+				Limits:   testResources("100m", "100Mi"),
+				Requests: testResources("100m", "100Mi"),
+			},
+		})
+	}
+	return w
+}
+
+func containerResources(t *testing.T, w *fakeWorkload, name string) api.ResourceList {
+	for _, container := range w.template.Spec.Containers {
+		if container.Name == name {
+			return container.Resources.Requests
+		}
+	}
+	t.Fatalf("container %s not found", name)
+	return nil
+}
+
+func TestControllerPoll(t *testing.T) {
+	config, err := ParseTargetsConfig([]byte(testTargetsConfig))
+	if err != nil {
+		t.Fatalf("ParseTargetsConfig returned error: %v", err)
+	}
+	// Both containers of the Deployment and the DaemonSet need a scale up at
+	// size 10.
+	config.Targets[0].Containers[0].Resources["memory"] = testPiecewiseResources(t, "100m", "100Mi")["memory"]
+	config.Targets[0].Containers[1].Resources = testPiecewiseResources(t, "100m", "200Mi")
+	config.Targets[1].Containers[0].Resources = testPiecewiseResources(t, "100m", "200Mi")
+	metricsServer := newFakeWorkload("metrics-server", "metrics-server-nanny")
+	fluentd := newFakeWorkload("fluentd")
+
+	c := &Controller{clusterSize: func() (uint64, error) { return 10, nil }}
+	c.addTarget(config.Targets[0], metricsServer, 0, 0)
+	c.addTarget(config.Targets[1], fluentd, 0, 0)
+	start := time.Now()
+	for _, target := range c.targets {
+		target.lastChange = start
+	}
+	scaleUpDelay := time.Minute
+
+	// Within the scale up delay nothing is updated.
+	c.poll(start.Add(time.Second), time.Minute, scaleUpDelay)
+	if metricsServer.updates != 0 || fluentd.updates != 0 {
+		t.Errorf("got %d and %d updates within scale up delay, want none", metricsServer.updates, fluentd.updates)
+	}
+
+	// After the delay, all containers of each target are updated at once.
+	c.poll(start.Add(2*time.Minute), time.Minute, scaleUpDelay)
+	if metricsServer.updates != 1 || fluentd.updates != 1 {
+		t.Errorf("got %d and %d updates after scale up delay, want 1 each", metricsServer.updates, fluentd.updates)
+	}
+	if got, want := containerResources(t, metricsServer, "metrics-server")["cpu"], testResources("200m", "0")["cpu"]; got.Cmp(want) != 0 {
+		t.Errorf("got metrics-server cpu %s, want %s", got.String(), want.String())
+	}
+	if got, want := containerResources(t, metricsServer, "metrics-server-nanny")["memory"], testResources("0", "200Mi")["memory"]; got.Cmp(want) != 0 {
+		t.Errorf("got metrics-server-nanny memory %s, want %s", got.String(), want.String())
+	}
+	if got, want := containerResources(t, fluentd, "fluentd")["memory"], testResources("0", "200Mi")["memory"]; got.Cmp(want) != 0 {
+		t.Errorf("got fluentd memory %s, want %s", got.String(), want.String())
+	}
+
+	// Nothing is left to update.
+	c.poll(start.Add(4*time.Minute), time.Minute, scaleUpDelay)
+	if metricsServer.updates != 1 || fluentd.updates != 1 {
+		t.Errorf("got %d and %d updates, want 1 each", metricsServer.updates, fluentd.updates)
+	}
+}
+
+func TestControllerReloadConfig(t *testing.T) {
+	file, err := ioutil.TempFile("", "targets")
+	if err != nil {
+		t.Fatalf("TempFile returned error: %v", err)
+	}
+	defer os.Remove(file.Name())
+	writeConfig := func(data string) {
+		if err := ioutil.WriteFile(file.Name(), []byte(data), 0644); err != nil {
+			t.Fatalf("WriteFile returned error: %v", err)
+		}
+	}
+	config, err := ParseTargetsConfig([]byte(testTargetsConfig))
+	if err != nil {
+		t.Fatalf("ParseTargetsConfig returned error: %v", err)
+	}
+	metricsServer := newFakeWorkload("metrics-server", "metrics-server-nanny")
+	fluentd := newFakeWorkload("fluentd")
+	c := &Controller{
+		configPath:  file.Name(),
+		clusterSize: func() (uint64, error) { return 0, errListersNotSynced },
+		workloads: map[listerKey]func(name string) workload{
+			{kind: DeploymentKind, namespace: "kube-system"}: func(string) workload { return metricsServer },
+			{kind: DaemonSetKind, namespace: "kube-system"}:  func(string) workload { return fluentd },
+		},
+	}
+	start := time.Now()
+
+	// Only the DaemonSet is listed at first.
+	writeConfig("targets:\n" + testTargetsConfig[strings.Index(testTargetsConfig, "- kind: DaemonSet"):])
+	c.poll(start, 0, 0)
+	if len(c.targets) != 1 || c.targets[0].target.String() != config.Targets[1].String() {
+		t.Fatalf("got targets %v, want only %s", c.targets, config.Targets[1])
+	}
+
+	// The Deployment is added, and the DaemonSet keeps its delay state.
+	writeConfig(testTargetsConfig)
+	c.poll(start.Add(time.Minute), 0, 0)
+	if len(c.targets) != 2 {
+		t.Fatalf("got %d targets, want 2", len(c.targets))
+	}
+	for _, target := range c.targets {
+		want := start
+		if target.target.Kind == DeploymentKind {
+			want = start.Add(time.Minute)
+		}
+		if !target.lastChange.Equal(want) {
+			t.Errorf("got last change %v for %s, want %v", target.lastChange, target.target, want)
+		}
+	}
+
+	// An invalid config keeps the last valid one.
+	writeConfig("targets: [")
+	c.poll(start.Add(2*time.Minute), 0, 0)
+	if len(c.targets) != 2 {
+		t.Errorf("got %d targets after invalid config, want 2", len(c.targets))
+	}
+}
+
+func TestControllerPollClusterSizeNotSynced(t *testing.T) {
+	config, err := ParseTargetsConfig([]byte(testTargetsConfig))
+	if err != nil {
+		t.Fatalf("ParseTargetsConfig returned error: %v", err)
+	}
+	fluentd := newFakeWorkload("fluentd")
+	synced := false
+	c := &Controller{clusterSize: func() (uint64, error) {
+		if !synced {
+			return 0, errListersNotSynced
+		}
+		return 0, nil
+	}}
+	c.addTarget(config.Targets[1], fluentd, 0, 0)
+
+	c.poll(time.Now(), 0, 0)
+	if fluentd.updates != 0 {
+		t.Errorf("got %d updates before listers synced, want none", fluentd.updates)
+	}
+
+	// Once the listers synced, a cluster size of 0 is used.
+	synced = true
+	c.poll(time.Now(), 0, 0)
+	if fluentd.updates != 1 {
+		t.Errorf("got %d updates with cluster size 0, want 1", fluentd.updates)
+	}
+}
+
+func TestClusterSizerNotSynced(t *testing.T) {
+	synced := false
+	sizer := &clusterSizer{
+		dimension:       Dimension{Kind: NamespacesDimension},
+		namespaceLister: v1lister.NewNamespaceLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		hasSynced:       func() bool { return synced },
+	}
+	if _, err := sizer.ClusterSize(); err != errListersNotSynced {
+		t.Errorf("got error %v before listers synced, want %v", err, errListersNotSynced)
+	}
+	synced = true
+	size, err := sizer.ClusterSize()
+	if err != nil || size != 0 {
+		t.Errorf("got size %d and error %v, want 0 and no error", size, err)
+	}
+}
+
+func testPiecewiseResources(t *testing.T, cpu, memory string) map[api.ResourceName][]Step {
+	config, err := ParsePiecewiseConfig([]byte("resources:\n  cpu:\n  - from: 0\n    base: " + cpu + "\n  memory:\n  - from: 0\n    base: " + memory))
+	if err != nil {
+		t.Fatalf("ParsePiecewiseConfig returned error: %v", err)
+	}
+	return config.Resources
+}
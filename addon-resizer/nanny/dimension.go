@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// DimensionKind is what is counted to get the size of the cluster.
+type DimensionKind string
+
+const (
+	// NodesDimension counts the nodes of the cluster.
+	NodesDimension DimensionKind = "nodes"
+	// PodsDimension counts the pods of the cluster that are not terminated.
+	PodsDimension DimensionKind = "pods"
+	// ContainersDimension counts the containers of the pods of the cluster that
+	// are not terminated.
+	ContainersDimension DimensionKind = "containers"
+	// NamespacesDimension counts the namespaces of the cluster.
+	NamespacesDimension DimensionKind = "namespaces"
+	// CustomMetricDimension reads the size of the cluster from the custom
+	// metrics API.
+	CustomMetricDimension DimensionKind = "custom-metric"
+)
+
+// customMetricsAPIPath is the path of the custom metrics API the custom metric
+// of a Dimension is read from.
+const customMetricsAPIPath = "/apis/custom.metrics.k8s.io/v1beta2"
+
+// Dimension is the measure of the cluster size the resources of the dependent
+// container scale with.
+type Dimension struct {
+	Kind DimensionKind
+	// CustomMetric is the path of an object metric below the custom metrics API,
+	// e.g. namespaces/monitoring/services/prometheus/active-series. The values of
+	// all objects are summed up when the path matches several objects. Only used
+	// with CustomMetricDimension.
+	CustomMetric string
+}
+
+// Validate checks that the dimension is supported.
+func (d Dimension) Validate() error {
+	switch d.Kind {
+	case NodesDimension, PodsDimension, ContainersDimension, NamespacesDimension:
+		return nil
+	case CustomMetricDimension:
+		if d.CustomMetric == "" {
+			return fmt.Errorf("a custom metric is required with the %s dimension", CustomMetricDimension)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported dimension %q", d.Kind)
+	}
+}
+
+func (d Dimension) String() string {
+	if d.Kind == CustomMetricDimension {
+		return fmt.Sprintf("%s %s", d.Kind, d.CustomMetric)
+	}
+	return string(d.Kind)
+}
+
+// metricValueList is the part of a MetricValueList of the custom metrics API
+// needed to get the cluster size.
+type metricValueList struct {
+	Items []struct {
+		Value resource.Quantity `json:"value"`
+	} `json:"items"`
+}
+
+// parseCustomMetricValue returns the sum of the values of a MetricValueList,
+// rounded up.
+func parseCustomMetricValue(data []byte) (uint64, error) {
+	var list metricValueList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return 0, fmt.Errorf("cannot parse custom metric values: %v", err)
+	}
+	if len(list.Items) == 0 {
+		return 0, fmt.Errorf("no custom metric values found")
+	}
+	var sum resource.Quantity
+	for _, item := range list.Items {
+		sum.Add(item.Value)
+	}
+	if sum.Sign() < 0 {
+		return 0, fmt.Errorf("negative custom metric value %s", sum.String())
+	}
+	return uint64(sum.Value()), nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"testing"
+)
+
+func TestParseCustomMetricValue(t *testing.T) {
+	testCases := []struct {
+		name    string
+		data    string
+		want    uint64
+		wantErr bool
+	}{
+		{"single", `{"kind":"MetricValueList","items":[{"metric":{"name":"series"},"value":"1500"}]}`, 1500, false},
+		{"sum", `{"items":[{"value":"1500m"},{"value":"2"}]}`, 4, false},
+		{"empty", `{"items":[]}`, 0, true},
+		{"negative", `{"items":[{"value":"-1"}]}`, 0, true},
+		{"invalid", `not json`, 0, true},
+	}
+	for _, tc := range testCases {
+		got, err := parseCustomMetricValue([]byte(tc.data))
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseCustomMetricValue(%s) got error %v, want error: %v", tc.name, err, tc.wantErr)
+		}
+		if got != tc.want {
+			t.Errorf("parseCustomMetricValue(%s) got %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDimensionValidate(t *testing.T) {
+	testCases := []struct {
+		dimension Dimension
+		wantErr   bool
+	}{
+		{Dimension{Kind: NodesDimension}, false},
+		{Dimension{Kind: ContainersDimension}, false},
+		{Dimension{Kind: CustomMetricDimension, CustomMetric: "namespaces/monitoring/services/prometheus/active-series"}, false},
+		{Dimension{Kind: CustomMetricDimension}, true},
+		{Dimension{Kind: "cores"}, true},
+	}
+	for _, tc := range testCases {
+		if err := tc.dimension.Validate(); (err != nil) != tc.wantErr {
+			t.Errorf("Validate(%s) got error %v, want error: %v", tc.dimension, err, tc.wantErr)
+		}
+	}
+}
@@ -26,7 +26,8 @@ import (
 	log "github.com/golang/glog"
 )
 
-// Resource defines the name of a resource, the quantity, and the marginal value.
+// Resource defines the name of a resource, the quantity, and the marginal value
+// per node, or per unit of the cluster size Dimension.
 type Resource struct {
 	Base, ExtraPerNode resource.Quantity
 	Name               api.ResourceName
@@ -58,56 +59,60 @@ type Estimator struct {
 	RecommendationOffset int64
 }
 
-// Returns the node count that is offset/100 away from nodeCount rounded to the
+// Returns the cluster size that is offset/100 away from size rounded to the
 // nearest integer using the rounder function.
-func getOffsetNodeCount(nodeCount uint64, offset int64, rounder func(float64) float64) uint64 {
-	return uint64(int64(nodeCount) + int64(rounder(float64(nodeCount)*float64(offset)/100)))
+func getOffsetSize(size uint64, offset int64, rounder func(float64) float64) uint64 {
+	return uint64(int64(size) + int64(rounder(float64(size)*float64(offset)/100)))
 }
 
 // Returns a ResourceListPair representing the intervals describing the set
 // of valid values for each of the given resources. The lower bound of each
-// interval is computed using the node count equal to numNodes +
-// floor(numNodes * -offset/100). The uppoer bound of each interval is computed
-// using the node count equal to numNodes + ceil(numNodes * offset/100). Note
+// interval is computed using the cluster size equal to size +
+// floor(size * -offset/100). The upper bound of each interval is computed
+// using the cluster size equal to size + ceil(size * offset/100). Note
 // the ordering of the elements of the lower and upper fields is significant.
 // Element N of each field represents the lower and upper bounds, respectively,
 // of the interval for the resource with index N in res.
-func nodesAndOffsetToRange(numNodes uint64, offset int64, res []Resource) ResourceListPair {
-	numNodesMin := getOffsetNodeCount(numNodes, -offset, math.Floor)
-	numNodesMax := getOffsetNodeCount(numNodes, offset, math.Ceil)
+func sizeAndOffsetToRange(size uint64, offset int64, calculate func(size uint64) api.ResourceList) ResourceListPair {
 	return ResourceListPair{
-		lower: calculateResources(numNodesMin, res),
-		upper: calculateResources(numNodesMax, res),
+		lower: calculate(getOffsetSize(size, -offset, math.Floor)),
+		upper: calculate(getOffsetSize(size, offset, math.Ceil)),
 	}
 }
 
 // Computes the acceptable and recommended resource ranges relative to the base
-// resource values for a cluster with the specified number of nodes.
-func (e Estimator) scaleWithNodes(numNodes uint64) *EstimatorResult {
+// resource values for a cluster of the specified size.
+func (e Estimator) scale(size uint64) *EstimatorResult {
+	calculate := func(size uint64) api.ResourceList {
+		return calculateResources(size, e.Resources)
+	}
 	return &EstimatorResult{
-		RecommendedRange: nodesAndOffsetToRange(numNodes, e.RecommendationOffset, e.Resources),
-		AcceptableRange:  nodesAndOffsetToRange(numNodes, e.AcceptanceOffset, e.Resources),
+		RecommendedRange: sizeAndOffsetToRange(size, e.RecommendationOffset, calculate),
+		AcceptableRange:  sizeAndOffsetToRange(size, e.AcceptanceOffset, calculate),
 	}
 }
 
 // Returns a ResourceList containing the resource value for each type of
-// resource given the specified number of nodes and base resource value.
-func calculateResources(numNodes uint64, resources []Resource) api.ResourceList {
+// resource given the specified cluster size and base resource value.
+func calculateResources(size uint64, resources []Resource) api.ResourceList {
 	resourceList := make(api.ResourceList)
 	for _, r := range resources {
-		// Since we want to enable passing values smaller than e.g. 1 millicore per node,
-		// we need to have some more hacky solution here than operating on MilliValues.
-		perNodeString := r.ExtraPerNode.String()
-		var perNode float64
-		read, _ := fmt.Sscanf(perNodeString, "%f", &perNode)
-		overhead := resource.MustParse(fmt.Sprintf("%f%s", perNode*float64(numNodes), perNodeString[read:]))
-
 		newRes := r.Base
-		newRes.Add(overhead)
+		newRes.Add(multiplyQuantity(r.ExtraPerNode, size))
 
-		log.V(4).Infof("New requirement for resource %s with %d nodes is %s", r.Name, numNodes, newRes.String())
+		log.V(4).Infof("New requirement for resource %s with cluster size %d is %s", r.Name, size, newRes.String())
 
 		resourceList[r.Name] = newRes
 	}
 	return resourceList
 }
+
+// Returns the quantity multiplied by the given number of units.
+func multiplyQuantity(perUnit resource.Quantity, units uint64) resource.Quantity {
+	// Since we want to enable passing values smaller than e.g. 1 millicore per node,
+	// we need to have some more hacky solution here than operating on MilliValues.
+	perUnitString := perUnit.String()
+	var perUnitValue float64
+	read, _ := fmt.Sscanf(perUnitString, "%f", &perUnitValue)
+	return resource.MustParse(fmt.Sprintf("%f%s", perUnitValue*float64(units), perUnitString[read:]))
+}
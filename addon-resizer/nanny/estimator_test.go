@@ -362,7 +362,7 @@ func TestEstimateResources(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		got := tc.e.scaleWithNodes(tc.numNodes)
+		got := tc.e.scale(tc.numNodes)
 		want := &tc.estimatorResult
 		verifyRange(t, tc.lineNum, "AcceptableRange", got.AcceptableRange, want.AcceptableRange)
 		verifyRange(t, tc.lineNum, "RecommendedRange", got.RecommendedRange, want.RecommendedRange)
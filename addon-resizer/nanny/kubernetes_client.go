@@ -17,6 +17,7 @@ limitations under the License.
 package nanny
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -31,8 +32,91 @@ import (
 	"k8s.io/client-go/tools/cache"
 )
 
+// errListersNotSynced is returned when the cluster size is measured with
+// listers that have not completed their initial list yet.
+var errListersNotSynced = errors.New("listers have not synced yet")
+
+// clusterSizer measures the cluster size in a dimension.
+type clusterSizer struct {
+	kubeClient      kube_client.Interface
+	dimension       Dimension
+	nodeLister      v1lister.NodeLister
+	podLister       v1lister.PodLister
+	namespaceLister v1lister.NamespaceLister
+	// hasSynced tells whether the lister of the dimension has completed its
+	// initial list. It is nil for dimensions that are not measured with a
+	// lister.
+	hasSynced    cache.InformerSynced
+	stopChannels []chan<- struct{}
+}
+
+func newClusterSizer(kubeClient kube_client.Interface, dimension Dimension) *clusterSizer {
+	sizer := &clusterSizer{
+		kubeClient: kubeClient,
+		dimension:  dimension,
+	}
+	var stopCh chan<- struct{}
+	switch dimension.Kind {
+	case NodesDimension:
+		sizer.nodeLister, sizer.hasSynced, stopCh = newReadyNodeLister(kubeClient)
+		sizer.stopChannels = append(sizer.stopChannels, stopCh)
+	case PodsDimension, ContainersDimension:
+		sizer.podLister, sizer.hasSynced, stopCh = newPodLister(kubeClient)
+		sizer.stopChannels = append(sizer.stopChannels, stopCh)
+	case NamespacesDimension:
+		sizer.namespaceLister, sizer.hasSynced, stopCh = newNamespaceLister(kubeClient)
+		sizer.stopChannels = append(sizer.stopChannels, stopCh)
+	}
+	return sizer
+}
+
+func (s *clusterSizer) Stop() {
+	for _, ch := range s.stopChannels {
+		ch <- struct{}{}
+	}
+}
+
+func (s *clusterSizer) ClusterSize() (uint64, error) {
+	if s.hasSynced != nil && !s.hasSynced() {
+		return 0, errListersNotSynced
+	}
+	switch s.dimension.Kind {
+	case NodesDimension:
+		nodes, err := s.nodeLister.List(labels.Everything())
+		return uint64(len(nodes)), err
+	case PodsDimension, ContainersDimension:
+		pods, err := s.podLister.List(labels.Everything())
+		if err != nil {
+			return 0, err
+		}
+		var count uint64
+		for _, pod := range pods {
+			if pod.Status.Phase == core.PodSucceeded || pod.Status.Phase == core.PodFailed {
+				continue
+			}
+			if s.dimension.Kind == PodsDimension {
+				count++
+			} else {
+				count += uint64(len(pod.Spec.Containers))
+			}
+		}
+		return count, nil
+	case NamespacesDimension:
+		namespaces, err := s.namespaceLister.List(labels.Everything())
+		return uint64(len(namespaces)), err
+	case CustomMetricDimension:
+		data, err := s.kubeClient.Discovery().RESTClient().Get().AbsPath(customMetricsAPIPath, s.dimension.CustomMetric).DoRaw()
+		if err != nil {
+			return 0, fmt.Errorf("cannot get custom metric %s: %v", s.dimension.CustomMetric, err)
+		}
+		return parseCustomMetricValue(data)
+	default:
+		return 0, fmt.Errorf("unsupported dimension %q", s.dimension.Kind)
+	}
+}
+
 type kubernetesClient struct {
-	nodeLister       v1lister.NodeLister
+	sizer            *clusterSizer
 	podLister        v1lister.PodNamespaceLister
 	deploymentLister v1appslister.DeploymentNamespaceLister
 	deploymentClient kube_client_apps.DeploymentInterface
@@ -43,13 +127,11 @@ type kubernetesClient struct {
 	stopChannels     []chan<- struct{}
 }
 
-// NewKubernetesClient gives a KubernetesClient with the given dependencies.
-func NewKubernetesClient(kubeClient kube_client.Interface, namespace, deployment, pod, container string) KubernetesClient {
+// NewKubernetesClient gives a KubernetesClient with the given dependencies,
+// measuring the cluster size in the given dimension.
+func NewKubernetesClient(kubeClient kube_client.Interface, namespace, deployment, pod, container string, dimension Dimension) KubernetesClient {
 	stops := []chan<- struct{}{}
 
-	nodeLister, stopCh := newReadyNodeLister(kubeClient)
-	stops = append(stops, stopCh)
-
 	podLister, stopCh := newPodListerByNamespace(kubeClient, namespace)
 	stops = append(stops, stopCh)
 
@@ -57,11 +139,11 @@ func NewKubernetesClient(kubeClient kube_client.Interface, namespace, deployment
 	stops = append(stops, stopCh)
 
 	result := &kubernetesClient{
+		sizer:            newClusterSizer(kubeClient, dimension),
 		namespace:        namespace,
 		deployment:       deployment,
 		pod:              pod,
 		container:        container,
-		nodeLister:       nodeLister,
 		podLister:        podLister,
 		deploymentLister: deploymentLister,
 		deploymentClient: kubeClient.AppsV1().Deployments(namespace),
@@ -71,14 +153,14 @@ func NewKubernetesClient(kubeClient kube_client.Interface, namespace, deployment
 }
 
 func (k *kubernetesClient) Stop() {
+	k.sizer.Stop()
 	for _, ch := range k.stopChannels {
 		ch <- struct{}{}
 	}
 }
 
-func (k *kubernetesClient) CountNodes() (uint64, error) {
-	nodes, err := k.nodeLister.List(labels.Everything())
-	return uint64(len(nodes)), err
+func (k *kubernetesClient) ClusterSize() (uint64, error) {
+	return k.sizer.ClusterSize()
 }
 
 func (k *kubernetesClient) ContainerResources() (*core.ResourceRequirements, error) {
@@ -116,14 +198,53 @@ func (k *kubernetesClient) UpdateDeployment(resources *core.ResourceRequirements
 	return fmt.Errorf("container %s was not found in the deployment %s in namespace %s", k.container, k.deployment, k.namespace)
 }
 
-func newReadyNodeLister(kubeClient kube_client.Interface) (v1lister.NodeLister, chan<- struct{}) {
+func newReadyNodeLister(kubeClient kube_client.Interface) (v1lister.NodeLister, cache.InformerSynced, chan<- struct{}) {
 	stopChannel := make(chan struct{})
 	listWatcher := cache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "nodes", core.NamespaceAll, fields.Everything())
 	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
 	nodeLister := v1lister.NewNodeLister(store)
 	reflector := cache.NewReflector(listWatcher, &core.Node{}, store, time.Hour)
 	go reflector.Run(stopChannel)
-	return nodeLister, stopChannel
+	return nodeLister, reflectorSynced(reflector), stopChannel
+}
+
+func newPodLister(kubeClient kube_client.Interface) (v1lister.PodLister, cache.InformerSynced, chan<- struct{}) {
+	stopChannel := make(chan struct{})
+	listWatcher := cache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "pods", core.NamespaceAll, fields.Everything())
+	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	lister := v1lister.NewPodLister(store)
+	reflector := cache.NewReflector(listWatcher, &core.Pod{}, store, time.Hour)
+	go reflector.Run(stopChannel)
+	return lister, reflectorSynced(reflector), stopChannel
+}
+
+func newNamespaceLister(kubeClient kube_client.Interface) (v1lister.NamespaceLister, cache.InformerSynced, chan<- struct{}) {
+	stopChannel := make(chan struct{})
+	listWatcher := cache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "namespaces", core.NamespaceAll, fields.Everything())
+	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	lister := v1lister.NewNamespaceLister(store)
+	reflector := cache.NewReflector(listWatcher, &core.Namespace{}, store, time.Hour)
+	go reflector.Run(stopChannel)
+	return lister, reflectorSynced(reflector), stopChannel
+}
+
+// reflectorSynced tells whether the reflector has completed its initial list.
+func reflectorSynced(reflector *cache.Reflector) cache.InformerSynced {
+	return func() bool {
+		return reflector.LastSyncResourceVersion() != ""
+	}
+}
+
+func newConfigMapListerByNamespace(kubeClient kube_client.Interface, namespace string) (v1lister.ConfigMapNamespaceLister,
+	chan<- struct{}) {
+	stopChannel := make(chan struct{})
+	listWatcher := cache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "configmaps", namespace, fields.Everything())
+	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	lister := v1lister.NewConfigMapLister(store)
+	reflector := cache.NewReflector(listWatcher, &core.ConfigMap{}, store, time.Hour)
+	go reflector.Run(stopChannel)
+	nsLister := lister.ConfigMaps(namespace)
+	return nsLister, stopChannel
 }
 
 func newPodListerByNamespace(kubeClient kube_client.Interface, namespace string) (v1lister.PodNamespaceLister,
@@ -149,3 +270,27 @@ func newDeploymentListerByNamespace(kubeClient kube_client.Interface, namespace
 	nsLister := lister.Deployments(namespace)
 	return nsLister, stopChannel
 }
+
+func newDaemonSetListerByNamespace(kubeClient kube_client.Interface, namespace string) (v1appslister.DaemonSetNamespaceLister,
+	chan<- struct{}) {
+	stopChannel := make(chan struct{})
+	listWatcher := cache.NewListWatchFromClient(kubeClient.AppsV1().RESTClient(), "daemonsets", namespace, fields.Everything())
+	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	lister := v1appslister.NewDaemonSetLister(store)
+	reflector := cache.NewReflector(listWatcher, &apps.DaemonSet{}, store, time.Hour)
+	go reflector.Run(stopChannel)
+	nsLister := lister.DaemonSets(namespace)
+	return nsLister, stopChannel
+}
+
+func newStatefulSetListerByNamespace(kubeClient kube_client.Interface, namespace string) (v1appslister.StatefulSetNamespaceLister,
+	chan<- struct{}) {
+	stopChannel := make(chan struct{})
+	listWatcher := cache.NewListWatchFromClient(kubeClient.AppsV1().RESTClient(), "statefulsets", namespace, fields.Everything())
+	store := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	lister := v1appslister.NewStatefulSetLister(store)
+	reflector := cache.NewReflector(listWatcher, &apps.StatefulSet{}, store, time.Hour)
+	go reflector.Run(stopChannel)
+	nsLister := lister.StatefulSets(namespace)
+	return nsLister, stopChannel
+}
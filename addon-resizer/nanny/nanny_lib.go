@@ -85,19 +85,22 @@ func shouldOverwriteResources(estimatorResult *EstimatorResult, limits, reqs api
 
 // KubernetesClient is an object that performs the nanny's requisite interactions with Kubernetes.
 type KubernetesClient interface {
-	CountNodes() (uint64, error)
+	// ClusterSize returns the size of the cluster in the dimension the resources
+	// of the dependent container scale with.
+	ClusterSize() (uint64, error)
 	ContainerResources() (*api.ResourceRequirements, error)
 	UpdateDeployment(resources *api.ResourceRequirements) error
 	Stop()
 }
 
 // ResourceEstimator estimates ResourceRequirements for a given criteria. Returned value is a list
-// with acceptable values. First element on that list is the recommended one.
+// with acceptable values. First element on that list is the recommended one. It is nil if no
+// estimation is available.
 type ResourceEstimator interface {
-	scaleWithNodes(numNodes uint64) *EstimatorResult
+	scale(size uint64) *EstimatorResult
 }
 
-// PollAPIServer periodically counts the number of nodes, estimates the expected
+// PollAPIServer periodically measures the cluster size, estimates the expected
 // ResourceRequirements, compares them to the actual ResourceRequirements, and
 // updates the deployment with the expected ResourceRequirements if necessary.
 func PollAPIServer(k8s KubernetesClient, est ResourceEstimator, pollPeriod, scaleDownDelay, scaleUpDelay time.Duration) {
@@ -116,56 +119,73 @@ func PollAPIServer(k8s KubernetesClient, est ResourceEstimator, pollPeriod, scal
 	}
 }
 
-// updateResources counts the number of nodes, estimates the expected
+// updateResources measures the cluster size, estimates the expected
 // ResourceRequirements, compares them to the actual ResourceRequirements, and
 // updates the deployment with the expected ResourceRequirements if necessary.
 // It returns overwrite if deployment has been updated, postpone if the change
 // could not be applied due to scale up/down delay and noChange if the estimated
 // expected ResourceRequirements are in line with the actual ResourceRequirements.
 func updateResources(k8s KubernetesClient, est ResourceEstimator, now, lastChange time.Time, scaleDownDelay, scaleUpDelay time.Duration, prevResult updateResult) updateResult {
-
-	// Query the apiserver for the number of nodes.
-	num, err := k8s.CountNodes()
-	if num == 0 {
-		log.V(2).Info("No nodes found, probably listers have not synced yet. Skipping current check.")
+	overwriteResReq, result := estimateResources(k8s, est, now, lastChange, scaleDownDelay, scaleUpDelay)
+	if result != overwrite {
+		return result
+	}
+	if err := k8s.UpdateDeployment(overwriteResReq); err != nil {
+		log.Error(err)
 		return noChange
 	}
+	return overwrite
+}
+
+// estimateResources measures the cluster size, estimates the expected
+// ResourceRequirements and compares them to the actual ResourceRequirements.
+// It returns the ResourceRequirements to set and overwrite if they need to be
+// updated, postpone if the change could not be applied due to scale up/down
+// delay and noChange if the estimated expected ResourceRequirements are in line
+// with the actual ResourceRequirements.
+func estimateResources(k8s KubernetesClient, est ResourceEstimator, now, lastChange time.Time, scaleDownDelay, scaleUpDelay time.Duration) (*api.ResourceRequirements, updateResult) {
+
+	// Query the apiserver for the cluster size.
+	num, err := k8s.ClusterSize()
+	if err == errListersNotSynced {
+		log.V(2).Info("Listers have not synced yet. Skipping current check.")
+		return nil, noChange
+	}
 	if err != nil {
 		log.Error(err)
-		return noChange
+		return nil, noChange
 	}
-	log.V(4).Infof("The number of nodes is %d", num)
+	log.V(4).Infof("The cluster size is %d", num)
 
 	// Query the apiserver for this pod's information.
 	resources, err := k8s.ContainerResources()
 	if err != nil {
 		log.Errorf("Error while querying apiserver for resources: %v", err)
-		return noChange
+		return nil, noChange
 	}
 
 	// Get the expected resource limits.
-	estimation := est.scaleWithNodes(num)
+	estimation := est.scale(num)
+	if estimation == nil {
+		log.V(2).Info("No estimation available. Skipping current check.")
+		return nil, noChange
+	}
 
 	// If there's a difference, go ahead and set the new values.
 	overwriteResReq, op := shouldOverwriteResources(estimation, resources.Limits, resources.Requests)
 	if overwriteResReq == nil {
 		log.V(4).Infof("Resources are within the expected limits. Actual: %+v, accepted range: %+v", jsonOrValue(*resources), jsonOrValue(estimation.AcceptableRange))
-		return noChange
+		return nil, noChange
 	}
 
 	if (op == scaleDown && now.Before(lastChange.Add(scaleDownDelay))) ||
 		(op == scaleUp && now.Before(lastChange.Add(scaleUpDelay))) {
 		log.Infof("Resources are not within the expected limits, Actual: %+v, accepted range: %+v. Skipping resource update because of scale up/down delay", jsonOrValue(*resources), jsonOrValue(estimation.AcceptableRange))
-		return postpone
+		return nil, postpone
 	}
 
 	log.Infof("Resources are not within the expected limits, updating the deployment. Actual: %+v New: %+v", *resources, jsonOrValue(*overwriteResReq))
-	if err := k8s.UpdateDeployment(overwriteResReq); err != nil {
-		log.Error(err)
-		return noChange
-	}
-	lastChange = time.Now()
-	return overwrite
+	return overwriteResReq, overwrite
 }
 
 func jsonOrValue(val interface{}) interface{} {
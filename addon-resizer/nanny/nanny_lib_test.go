@@ -276,6 +276,8 @@ func TestUpdateResources(t *testing.T) {
 		{bigCPU, standardRecommended, oneMinuteAgo, noDelay, oneMinuteDelay, &api.ResourceRequirements{aboveStandard, aboveStandard}, overwrite},
 		{smallCPU, standardRecommended, oneHourAgo, oneMinuteDelay, noDelay, &api.ResourceRequirements{belowStandard, belowStandard}, overwrite},
 		{bigCPU, standardRecommended, oneHourAgo, noDelay, oneMinuteDelay, &api.ResourceRequirements{aboveStandard, aboveStandard}, overwrite},
+		// No estimation available
+		{smallCPU, nil, oneHourAgo, noDelay, noDelay, nil, noChange},
 	}
 	for i, tc := range testCases {
 		k8s := newFakeKubernetesClient(10, tc.res, tc.res)
@@ -306,7 +308,7 @@ func newFakeKubernetesClient(nodes uint64, limits, reqs api.ResourceList) *fakeK
 	}
 }
 
-func (f *fakeKubernetesClient) CountNodes() (uint64, error) {
+func (f *fakeKubernetesClient) ClusterSize() (uint64, error) {
 	return f.nodes, nil
 }
 
@@ -332,6 +334,6 @@ func newFakeResourceEstimator(result *EstimatorResult) *fakeResourceEstimator {
 	}
 }
 
-func (f *fakeResourceEstimator) scaleWithNodes(numNodes uint64) *EstimatorResult {
+func (f *fakeResourceEstimator) scale(size uint64) *EstimatorResult {
 	return f.result
 }
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	api "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	log "github.com/golang/glog"
+)
+
+// Step is a piece of a piecewise linear function of the cluster size. It
+// applies to cluster sizes from From up to the From of the next step.
+type Step struct {
+	// From is the smallest cluster size the step applies to.
+	From uint64 `json:"from"`
+	// Base is the resource value for a cluster of size From.
+	Base resource.Quantity `json:"base"`
+	// ExtraPerUnit is the amount of the resource to add per unit of the cluster
+	// size above From.
+	ExtraPerUnit resource.Quantity `json:"extraPerUnit,omitempty"`
+}
+
+// PiecewiseConfig is the configuration of a PiecewiseEstimator.
+type PiecewiseConfig struct {
+	// Resources maps the monitored resources to their steps, sorted by From.
+	// The first step of each resource must start from 0.
+	Resources map[api.ResourceName][]Step `json:"resources"`
+}
+
+// PiecewiseEstimator estimates resource requirements with a piecewise linear
+// function of the cluster size for each resource. Steps without ExtraPerUnit
+// make it a step function.
+type PiecewiseEstimator struct {
+	PiecewiseConfig
+	// Percentage offset defining acceptable resource range.
+	AcceptanceOffset int64
+	// Percentage offset defining recommended resource range.
+	RecommendationOffset int64
+}
+
+// ParsePiecewiseConfig parses and validates a PiecewiseConfig in YAML or JSON.
+func ParsePiecewiseConfig(data []byte) (*PiecewiseConfig, error) {
+	config := &PiecewiseConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("cannot parse estimator config: %v", err)
+	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+func (c *PiecewiseConfig) validate() error {
+	if len(c.Resources) == 0 {
+		return fmt.Errorf("estimator config has no resources")
+	}
+	for name, steps := range c.Resources {
+		if len(steps) == 0 {
+			return fmt.Errorf("resource %s has no steps", name)
+		}
+		if steps[0].From != 0 {
+			return fmt.Errorf("first step of resource %s must start from 0, starts from %d", name, steps[0].From)
+		}
+		for i := 1; i < len(steps); i++ {
+			if steps[i].From <= steps[i-1].From {
+				return fmt.Errorf("steps of resource %s must be sorted by increasing from, %d follows %d", name, steps[i].From, steps[i-1].From)
+			}
+		}
+	}
+	return nil
+}
+
+// Computes the acceptable and recommended resource ranges for a cluster of the
+// specified size.
+func (e PiecewiseEstimator) scale(size uint64) *EstimatorResult {
+	return &EstimatorResult{
+		RecommendedRange: sizeAndOffsetToRange(size, e.RecommendationOffset, e.calculateResources),
+		AcceptableRange:  sizeAndOffsetToRange(size, e.AcceptanceOffset, e.calculateResources),
+	}
+}
+
+// Returns a ResourceList containing the value of the step matching the cluster
+// size for each resource.
+func (e PiecewiseEstimator) calculateResources(size uint64) api.ResourceList {
+	resourceList := make(api.ResourceList)
+	for name, steps := range e.Resources {
+		// Index of the first step starting above size.
+		i := sort.Search(len(steps), func(i int) bool { return steps[i].From > size })
+		if i == 0 {
+			continue
+		}
+		step := steps[i-1]
+		newRes := step.Base
+		newRes.Add(multiplyQuantity(step.ExtraPerUnit, size-step.From))
+
+		log.V(4).Infof("New requirement for resource %s with cluster size %d is %s", name, size, newRes.String())
+
+		resourceList[name] = newRes
+	}
+	return resourceList
+}
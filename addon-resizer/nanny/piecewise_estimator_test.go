@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"testing"
+
+	api "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const testPiecewiseConfig = `
+resources:
+  cpu:
+  - from: 0
+    base: 100m
+    extraPerUnit: 10m
+  - from: 100
+    base: 1
+  memory:
+  - from: 0
+    base: 100Mi
+  - from: 50
+    base: 200Mi
+    extraPerUnit: 1Mi
+`
+
+func testResources(cpu, memory string) api.ResourceList {
+	return api.ResourceList{
+		"cpu":    resource.MustParse(cpu),
+		"memory": resource.MustParse(memory),
+	}
+}
+
+func TestPiecewiseEstimator(t *testing.T) {
+	config, err := ParsePiecewiseConfig([]byte(testPiecewiseConfig))
+	if err != nil {
+		t.Fatalf("ParsePiecewiseConfig returned error: %v", err)
+	}
+	e := PiecewiseEstimator{
+		PiecewiseConfig:      *config,
+		AcceptanceOffset:     20,
+		RecommendationOffset: 10,
+	}
+	exact := PiecewiseEstimator{PiecewiseConfig: *config}
+
+	testCases := []struct {
+		lineNum         int
+		e               ResourceEstimator
+		size            uint64
+		estimatorResult EstimatorResult
+	}{
+		{num(), exact, 0, EstimatorResult{
+			ResourceListPair{testResources("100m", "100Mi"), testResources("100m", "100Mi")},
+			ResourceListPair{testResources("100m", "100Mi"), testResources("100m", "100Mi")},
+		}},
+		{num(), exact, 10, EstimatorResult{
+			ResourceListPair{testResources("200m", "100Mi"), testResources("200m", "100Mi")},
+			ResourceListPair{testResources("200m", "100Mi"), testResources("200m", "100Mi")},
+		}},
+		{num(), exact, 100, EstimatorResult{
+			ResourceListPair{testResources("1", "250Mi"), testResources("1", "250Mi")},
+			ResourceListPair{testResources("1", "250Mi"), testResources("1", "250Mi")},
+		}},
+		{num(), e, 60, EstimatorResult{
+			ResourceListPair{testResources("640m", "204Mi"), testResources("760m", "216Mi")},
+			ResourceListPair{testResources("580m", "100Mi"), testResources("820m", "222Mi")},
+		}},
+	}
+
+	for _, tc := range testCases {
+		got := tc.e.scale(tc.size)
+		want := &tc.estimatorResult
+		verifyRange(t, tc.lineNum, "AcceptableRange", got.AcceptableRange, want.AcceptableRange)
+		verifyRange(t, tc.lineNum, "RecommendedRange", got.RecommendedRange, want.RecommendedRange)
+	}
+}
+
+func TestParsePiecewiseConfigErrors(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config string
+	}{
+		{"invalid", "resources: ["},
+		{"no resources", "resources: {}"},
+		{"no steps", "resources:\n  cpu: []"},
+		{"not from zero", "resources:\n  cpu:\n  - from: 1\n    base: 1"},
+		{"unsorted", "resources:\n  cpu:\n  - from: 0\n    base: 1\n  - from: 10\n    base: 2\n  - from: 10\n    base: 3"},
+		{"invalid quantity", "resources:\n  cpu:\n  - from: 0\n    base: one"},
+	}
+	for _, tc := range testCases {
+		if _, err := ParsePiecewiseConfig([]byte(tc.config)); err == nil {
+			t.Errorf("ParsePiecewiseConfig accepted config %q", tc.name)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"fmt"
+
+	"github.com/ghodss/yaml"
+)
+
+// WorkloadKind is the kind of a workload whose containers are nannied.
+type WorkloadKind string
+
+const (
+	// DeploymentKind is the kind of Deployments.
+	DeploymentKind WorkloadKind = "Deployment"
+	// DaemonSetKind is the kind of DaemonSets.
+	DaemonSetKind WorkloadKind = "DaemonSet"
+	// StatefulSetKind is the kind of StatefulSets.
+	StatefulSetKind WorkloadKind = "StatefulSet"
+)
+
+// TargetsConfig is the configuration of a Controller.
+type TargetsConfig struct {
+	// Targets are the workloads to nanny.
+	Targets []Target `json:"targets"`
+}
+
+// Target is a workload with containers to nanny.
+type Target struct {
+	Kind      WorkloadKind `json:"kind"`
+	Namespace string       `json:"namespace"`
+	Name      string       `json:"name"`
+	// Containers are the containers of the pod template to nanny, each with
+	// its own estimator config.
+	Containers []TargetContainer `json:"containers"`
+}
+
+// TargetContainer is a container of a Target with the config of the
+// PiecewiseEstimator of its resources.
+type TargetContainer struct {
+	Name string `json:"name"`
+	PiecewiseConfig
+}
+
+// String returns the kind, namespace and name of the target.
+func (t Target) String() string {
+	return fmt.Sprintf("%s %s/%s", t.Kind, t.Namespace, t.Name)
+}
+
+// ParseTargetsConfig parses and validates a TargetsConfig in YAML or JSON.
+func ParseTargetsConfig(data []byte) (*TargetsConfig, error) {
+	config := &TargetsConfig{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("cannot parse targets config: %v", err)
+	}
+	if len(config.Targets) == 0 {
+		return nil, fmt.Errorf("targets config has no targets")
+	}
+	targets := make(map[string]bool)
+	for _, target := range config.Targets {
+		switch target.Kind {
+		case DeploymentKind, DaemonSetKind, StatefulSetKind:
+		default:
+			return nil, fmt.Errorf("unsupported kind %q of target %s/%s, must be %s, %s or %s", target.Kind, target.Namespace, target.Name, DeploymentKind, DaemonSetKind, StatefulSetKind)
+		}
+		if target.Namespace == "" || target.Name == "" {
+			return nil, fmt.Errorf("target %s must have a namespace and a name", target)
+		}
+		if targets[target.String()] {
+			return nil, fmt.Errorf("target %s is listed more than once", target)
+		}
+		targets[target.String()] = true
+		if len(target.Containers) == 0 {
+			return nil, fmt.Errorf("target %s has no containers", target)
+		}
+		containers := make(map[string]bool)
+		for _, container := range target.Containers {
+			if container.Name == "" {
+				return nil, fmt.Errorf("containers of target %s must have a name", target)
+			}
+			if containers[container.Name] {
+				return nil, fmt.Errorf("container %s of target %s is listed more than once", container.Name, target)
+			}
+			containers[container.Name] = true
+			if err := container.validate(); err != nil {
+				return nil, fmt.Errorf("container %s of target %s: %v", container.Name, target, err)
+			}
+		}
+	}
+	return config, nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"testing"
+)
+
+const testTargetsConfig = `
+targets:
+- kind: Deployment
+  namespace: kube-system
+  name: metrics-server
+  containers:
+  - name: metrics-server
+    resources:
+      cpu:
+      - from: 0
+        base: 100m
+        extraPerUnit: 10m
+  - name: metrics-server-nanny
+    resources:
+      memory:
+      - from: 0
+        base: 100Mi
+- kind: DaemonSet
+  namespace: kube-system
+  name: fluentd
+  containers:
+  - name: fluentd
+    resources:
+      memory:
+      - from: 0
+        base: 200Mi
+`
+
+func TestParseTargetsConfig(t *testing.T) {
+	config, err := ParseTargetsConfig([]byte(testTargetsConfig))
+	if err != nil {
+		t.Fatalf("ParseTargetsConfig returned error: %v", err)
+	}
+	if len(config.Targets) != 2 {
+		t.Fatalf("got %d targets, want 2", len(config.Targets))
+	}
+	target := config.Targets[0]
+	if got, want := target.String(), "Deployment kube-system/metrics-server"; got != want {
+		t.Errorf("got target %s, want %s", got, want)
+	}
+	if len(target.Containers) != 2 {
+		t.Fatalf("got %d containers, want 2", len(target.Containers))
+	}
+	steps := target.Containers[0].Resources["cpu"]
+	if len(steps) != 1 || steps[0].ExtraPerUnit.String() != "10m" {
+		t.Errorf("got cpu steps %+v, want one step with extraPerUnit 10m", steps)
+	}
+	if got, want := config.Targets[1].Kind, DaemonSetKind; got != want {
+		t.Errorf("got kind %s, want %s", got, want)
+	}
+}
+
+func TestParseTargetsConfigErrors(t *testing.T) {
+	const container = "\n  containers:\n  - name: c\n    resources:\n      cpu:\n      - from: 0\n        base: 100m"
+	testCases := []struct {
+		lineNum int
+		config  string
+	}{
+		{num(), "targets: ["},
+		{num(), "targets: []"},
+		{num(), "targets:\n- kind: ReplicaSet\n  namespace: ns\n  name: a" + container},
+		{num(), "targets:\n- kind: Deployment\n  name: a" + container},
+		{num(), "targets:\n- kind: Deployment\n  namespace: ns\n  name: a" + container + "\n- kind: Deployment\n  namespace: ns\n  name: a" + container},
+		{num(), "targets:\n- kind: Deployment\n  namespace: ns\n  name: a"},
+		{num(), "targets:\n- kind: Deployment\n  namespace: ns\n  name: a\n  containers:\n  - resources:\n      cpu:\n      - from: 0\n        base: 100m"},
+		{num(), "targets:\n- kind: Deployment\n  namespace: ns\n  name: a" + container + "\n  - name: c\n    resources:\n      cpu:\n      - from: 0\n        base: 100m"},
+		{num(), "targets:\n- kind: StatefulSet\n  namespace: ns\n  name: a\n  containers:\n  - name: c\n    resources:\n      cpu:\n      - from: 5\n        base: 100m"},
+	}
+	for _, tc := range testCases {
+		if _, err := ParseTargetsConfig([]byte(tc.config)); err == nil {
+			t.Errorf("ParseTargetsConfig at line %d returned no error", tc.lineNum)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nanny
+
+import (
+	"fmt"
+
+	core "k8s.io/api/core/v1"
+	kube_client_apps "k8s.io/client-go/kubernetes/typed/apps/v1"
+	v1appslister "k8s.io/client-go/listers/apps/v1"
+)
+
+// workload reads and updates the pod template of a Deployment, DaemonSet or
+// StatefulSet.
+type workload interface {
+	podTemplate() (*core.PodTemplateSpec, error)
+	// updatePodTemplate replaces the pod template with the one modified by
+	// update.
+	updatePodTemplate(update func(*core.PodTemplateSpec) error) error
+}
+
+type deploymentWorkload struct {
+	name   string
+	lister v1appslister.DeploymentNamespaceLister
+	client kube_client_apps.DeploymentInterface
+}
+
+func (w *deploymentWorkload) podTemplate() (*core.PodTemplateSpec, error) {
+	dep, err := w.lister.Get(w.name)
+	if err != nil {
+		return nil, err
+	}
+	return &dep.Spec.Template, nil
+}
+
+func (w *deploymentWorkload) updatePodTemplate(update func(*core.PodTemplateSpec) error) error {
+	dep, err := w.lister.Get(w.name)
+	if err != nil {
+		return err
+	}
+	dep = dep.DeepCopy()
+	if err := update(&dep.Spec.Template); err != nil {
+		return err
+	}
+	_, err = w.client.Update(dep)
+	return err
+}
+
+type daemonSetWorkload struct {
+	name   string
+	lister v1appslister.DaemonSetNamespaceLister
+	client kube_client_apps.DaemonSetInterface
+}
+
+func (w *daemonSetWorkload) podTemplate() (*core.PodTemplateSpec, error) {
+	ds, err := w.lister.Get(w.name)
+	if err != nil {
+		return nil, err
+	}
+	return &ds.Spec.Template, nil
+}
+
+func (w *daemonSetWorkload) updatePodTemplate(update func(*core.PodTemplateSpec) error) error {
+	ds, err := w.lister.Get(w.name)
+	if err != nil {
+		return err
+	}
+	ds = ds.DeepCopy()
+	if err := update(&ds.Spec.Template); err != nil {
+		return err
+	}
+	_, err = w.client.Update(ds)
+	return err
+}
+
+type statefulSetWorkload struct {
+	name   string
+	lister v1appslister.StatefulSetNamespaceLister
+	client kube_client_apps.StatefulSetInterface
+}
+
+func (w *statefulSetWorkload) podTemplate() (*core.PodTemplateSpec, error) {
+	sts, err := w.lister.Get(w.name)
+	if err != nil {
+		return nil, err
+	}
+	return &sts.Spec.Template, nil
+}
+
+func (w *statefulSetWorkload) updatePodTemplate(update func(*core.PodTemplateSpec) error) error {
+	sts, err := w.lister.Get(w.name)
+	if err != nil {
+		return err
+	}
+	sts = sts.DeepCopy()
+	if err := update(&sts.Spec.Template); err != nil {
+		return err
+	}
+	_, err = w.client.Update(sts)
+	return err
+}
+
+// measuredSize is the cluster size measured once per poll and shared by the
+// clients of all targets.
+type measuredSize struct {
+	size uint64
+	err  error
+}
+
+// workloadClient is a KubernetesClient for a container of a target workload.
+// Unlike kubernetesClient, it reads the resources of the container from the
+// pod template of the workload rather than from a running pod.
+type workloadClient struct {
+	target    Target
+	container string
+	workload  workload
+	size      *measuredSize
+}
+
+func (w *workloadClient) ClusterSize() (uint64, error) {
+	return w.size.size, w.size.err
+}
+
+func (w *workloadClient) ContainerResources() (*core.ResourceRequirements, error) {
+	template, err := w.workload.podTemplate()
+	if err != nil {
+		return nil, err
+	}
+	for _, container := range template.Spec.Containers {
+		if container.Name == w.container {
+			return &container.Resources, nil
+		}
+	}
+	return nil, fmt.Errorf("container %s was not found in %s", w.container, w.target)
+}
+
+func (w *workloadClient) UpdateDeployment(resources *core.ResourceRequirements) error {
+	return w.workload.updatePodTemplate(func(template *core.PodTemplateSpec) error {
+		for i, container := range template.Spec.Containers {
+			if container.Name == w.container {
+				template.Spec.Containers[i].Resources = *resources
+				return nil
+			}
+		}
+		return fmt.Errorf("container %s was not found in %s", w.container, w.target)
+	})
+}
+
+// Stop does nothing, the listers are shared by the targets and stopped by the
+// Controller.
+func (w *workloadClient) Stop() {}
@@ -58,6 +58,8 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/scheduling"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization/usage"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -184,6 +186,17 @@ func (b *AutoscalerBuilder) Build(ctx context.Context) (core.Autoscaler, *loop.L
 	opts.ScaleUpFailuresRegistry = scaleupfailures.NewRegistry()
 	opts.LoopStartObservers = append(opts.LoopStartObservers, opts.ScaleUpFailuresRegistry)
 
+	if mode := utilization.Mode(autoscalingOptions.ScaleDownUtilizationMode); mode != "" && mode != utilization.ModeRequests {
+		var usageProvider usage.Provider
+		if autoscalingOptions.ScaleDownUsageSource == usage.SourcePrometheus {
+			usageProvider = usage.NewPrometheusProvider(autoscalingOptions.ScaleDownUsagePrometheusURL, autoscalingOptions.ScaleDownUsagePercentile, autoscalingOptions.ScaleDownUsageWindow)
+		} else {
+			usageProvider = usage.NewMetricsAPIProvider(b.kubeClient.Discovery().RESTClient(), autoscalingOptions.ScaleDownUsagePercentile, autoscalingOptions.ScaleDownUsageWindow)
+		}
+		opts.UsageProvider = usageProvider
+		opts.LoopStartObservers = append(opts.LoopStartObservers, usageProvider)
+	}
+
 	var provisioningRequestInjector *provreq.ProvisioningRequestPodsInjector
 	if autoscalingOptions.ProvisioningRequestEnabled {
 		injector, provreqProcessor, err := b.buildProvisioningRequest(ctx, autoscalingOptions, &opts, podListProcessor)
@@ -175,6 +175,17 @@ var ErrAlreadyExist = errors.NewAutoscalerError(errors.InternalError, "Already e
 // configuration that is not supported by cloudprovider.
 var ErrIllegalConfiguration = errors.NewAutoscalerError(errors.InternalError, "Configuration not allowed by cloud provider")
 
+// ScaleUpError is returned by IncreaseSize and AtomicIncreaseSize to report
+// why the requested instances couldn't be provisioned. The node group is backed
+// off with ErrorInfo instead of a generic cloud provider error.
+type ScaleUpError struct {
+	ErrorInfo InstanceErrorInfo
+}
+
+func (e *ScaleUpError) Error() string {
+	return fmt.Sprintf("%s: %s", e.ErrorInfo.ErrorCode, e.ErrorInfo.ErrorMessage)
+}
+
 // NodeGroup contains configuration info and functions to control a set
 // of nodes that have the same capacity and set of labels.
 type NodeGroup interface {
@@ -200,7 +211,8 @@ type NodeGroup interface {
 	// Implementation is optional. Implementation of this method generally requires external cloud provider support
 	// for atomically requesting multiple instances. If implemented, CA will take advantage of the method while scaling up
 	// BestEffortAtomicScaleUp ProvisioningClass, guaranteeing that all instances required for such a
-	// ProvisioningRequest are provisioned atomically.
+	// ProvisioningRequest are provisioned atomically. A *ScaleUpError can be returned to report the reason of the
+	// failure, which is used to back off the node group.
 	AtomicIncreaseSize(delta int) error
 
 	// DeleteNodes deletes nodes from this node group. Error is returned either on
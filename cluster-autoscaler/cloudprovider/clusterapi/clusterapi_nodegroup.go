@@ -32,6 +32,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/clusterapi/pricing"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 )
 
@@ -370,6 +371,7 @@ func (ng *nodegroup) TemplateNodeInfo() (*framework.NodeInfo, error) {
 	node.Status.Allocatable = capacity
 	node.Status.Conditions = cloudprovider.BuildReadyConditions()
 	node.Spec.Taints = ng.scalableResource.Taints()
+	node.Annotations = pricing.PriceAnnotations(ng.scalableResource.unstructured.GetAnnotations())
 
 	node.Labels, err = ng.buildTemplateLabels(nodeName, nsi)
 	if err != nil {
@@ -421,6 +423,11 @@ func (ng *nodegroup) buildTemplateLabels(nodeName string, nsi *corev1.NodeSystem
 	return labels, nil
 }
 
+// PriceAnnotations returns the price annotations of the scalable resource.
+func (ng *nodegroup) PriceAnnotations() map[string]string {
+	return pricing.PriceAnnotations(ng.scalableResource.unstructured.GetAnnotations())
+}
+
 // Exist checks if the node group really exists on the cloud nodegroup
 // side. Allows to tell the theoretical node group from the real one.
 // Implementation required.
@@ -33,6 +33,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/clusterapi/pricing"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	gpuapis "k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
 	"k8s.io/client-go/tools/cache"
@@ -1510,6 +1511,7 @@ func TestNodeGroupTemplateNodeInfo(t *testing.T) {
 		expectedTaints        []corev1.Taint
 		expectedResourceSlice testResourceSlice
 		expectedCSINode       *storagev1.CSINode
+		expectedAnnotations   map[string]string
 	}
 
 	testCases := []struct {
@@ -1755,6 +1757,31 @@ func TestNodeGroupTemplateNodeInfo(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "When the NodeGroup can scale from zero and price annotations are defined, they appear in the node template",
+			nodeGroupAnnotations: map[string]string{
+				memoryKey:                    "2048Mi",
+				cpuKey:                       "2",
+				pricing.PerHourAnnotation:    "0.096",
+				pricing.CPUPerHourAnnotation: "0.02",
+			},
+			config: testCaseConfig{
+				expectedCapacity: map[corev1.ResourceName]int64{
+					corev1.ResourceCPU:    2,
+					corev1.ResourceMemory: 2048 * 1024 * 1024,
+					corev1.ResourcePods:   110,
+				},
+				expectedNodeLabels: map[string]string{
+					"kubernetes.io/os":       "linux",
+					"kubernetes.io/arch":     "amd64",
+					"kubernetes.io/hostname": "random value",
+				},
+				expectedAnnotations: map[string]string{
+					pricing.PerHourAnnotation:    "0.096",
+					pricing.CPUPerHourAnnotation: "0.02",
+				},
+			},
+		},
 	}
 
 	test := func(t *testing.T, testConfig *TestConfig, config testCaseConfig) {
@@ -1856,6 +1883,10 @@ func TestNodeGroupTemplateNodeInfo(t *testing.T) {
 		if !reflect.DeepEqual(config.expectedTaints, nodeInfo.Node().Spec.Taints) {
 			t.Errorf("Expected node taints %+v, but got %+v", config.expectedTaints, nodeInfo.Node().Spec.Taints)
 		}
+
+		if !reflect.DeepEqual(config.expectedAnnotations, nodeInfo.Node().Annotations) {
+			t.Errorf("Expected node annotations %+v, but got %+v", config.expectedAnnotations, nodeInfo.Node().Annotations)
+		}
 	}
 
 	for _, tc := range testCases {
@@ -33,6 +33,8 @@ import (
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/builder"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/clusterapi/pricing"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/pricelist"
 	coreoptions "k8s.io/autoscaler/cluster-autoscaler/core/options"
 	"k8s.io/autoscaler/cluster-autoscaler/processors/scaledowncandidates"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
@@ -58,6 +60,7 @@ type provider struct {
 	controller      *machineController
 	providerName    string
 	resourceLimiter *cloudprovider.ResourceLimiter
+	priceList       pricelist.Source
 }
 
 func (p *provider) Name() string {
@@ -101,8 +104,10 @@ func (p *provider) HasInstance(node *corev1.Node) (bool, error) {
 	return false, fmt.Errorf("machine not found for node %s: %v", node.Name, err)
 }
 
-func (*provider) Pricing() (cloudprovider.PricingModel, errors.AutoscalerError) {
-	return nil, cloudprovider.ErrNotImplemented
+// Pricing returns a pricing model based on the price annotations of the
+// scalable resources and the optional price list.
+func (p *provider) Pricing() (cloudprovider.PricingModel, errors.AutoscalerError) {
+	return pricing.NewPriceModel(p.NodeGroupForNode, p.priceList), nil
 }
 
 func (*provider) GetAvailableMachineTypes() ([]string, error) {
@@ -153,11 +158,13 @@ func newProvider(
 	name string,
 	rl *cloudprovider.ResourceLimiter,
 	controller *machineController,
+	priceList pricelist.Source,
 ) cloudprovider.CloudProvider {
 	return &provider{
 		providerName:    name,
 		resourceLimiter: rl,
 		controller:      controller,
+		priceList:       priceList,
 	}
 }
 
@@ -228,5 +235,5 @@ func BuildClusterAPI(opts *coreoptions.AutoscalerOptions, do cloudprovider.NodeG
 		klog.Fatal(err)
 	}
 
-	return newProvider(cloudprovider.ClusterAPIProviderName, rl, controller)
+	return newProvider(cloudprovider.ClusterAPIProviderName, rl, controller, opts.PriceListSource)
 }
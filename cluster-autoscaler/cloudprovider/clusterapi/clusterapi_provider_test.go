@@ -33,7 +33,7 @@ func TestProviderConstructorProperties(t *testing.T) {
 	controller := NewTestMachineController(t)
 	defer controller.Stop()
 
-	provider := newProvider(cloudprovider.ClusterAPIProviderName, &resourceLimits, controller.machineController)
+	provider := newProvider(cloudprovider.ClusterAPIProviderName, &resourceLimits, controller.machineController, nil)
 	if actual := provider.Name(); actual != cloudprovider.ClusterAPIProviderName {
 		t.Errorf("expected %q, got %q", cloudprovider.ClusterAPIProviderName, actual)
 	}
@@ -47,8 +47,12 @@ func TestProviderConstructorProperties(t *testing.T) {
 		t.Errorf("expected %+v, got %+v", resourceLimits, rl)
 	}
 
-	if _, err := provider.Pricing(); err != cloudprovider.ErrNotImplemented {
-		t.Errorf("expected an error")
+	pricingModel, err := provider.Pricing()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if pricingModel == nil {
+		t.Errorf("expected a pricing model")
 	}
 
 	machineTypes, err := provider.GetAvailableMachineTypes()
@@ -123,7 +127,7 @@ func BenchmarkNodeGroups(b *testing.B) {
 		b.Fatalf("unexpected error: %v", err)
 	}
 
-	provider := newProvider(cloudprovider.ClusterAPIProviderName, &resourceLimits, controller.machineController)
+	provider := newProvider(cloudprovider.ClusterAPIProviderName, &resourceLimits, controller.machineController, nil)
 	if actual := provider.Name(); actual != cloudprovider.ClusterAPIProviderName {
 		b.Errorf("expected %q, got %q", cloudprovider.ClusterAPIProviderName, actual)
 	}
@@ -260,7 +264,7 @@ func TestNodeGroups(t *testing.T) {
 			}
 		}
 
-		provider := newProvider(cloudprovider.ClusterAPIProviderName, &resourceLimits, controller.machineController)
+		provider := newProvider(cloudprovider.ClusterAPIProviderName, &resourceLimits, controller.machineController, nil)
 		if actual := provider.Name(); actual != cloudprovider.ClusterAPIProviderName {
 			t.Errorf("expected %q, got %q", cloudprovider.ClusterAPIProviderName, actual)
 		}
@@ -0,0 +1,192 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pricing implements a PricingModel for providers that scale
+// MachineSets and MachineDeployments, like the Cluster API and OpenShift
+// Machine API providers. Prices are read from annotations on the scalable
+// resources, with the price list passed to the autoscaler as fallback.
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	klog "k8s.io/klog/v2"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/pricelist"
+)
+
+const (
+	pricePrefix = "price.cluster-autoscaler.kubernetes.io/"
+
+	// PerHourAnnotation is the annotation on a scalable resource holding the
+	// price of one of its nodes per hour.
+	PerHourAnnotation = pricePrefix + "per-hour"
+	// CPUPerHourAnnotation is the annotation on a scalable resource holding
+	// the price of one CPU of its nodes per hour.
+	CPUPerHourAnnotation = pricePrefix + "cpu-per-hour"
+	// MemoryGiBPerHourAnnotation is the annotation on a scalable resource
+	// holding the price of one GiB of memory of its nodes per hour.
+	MemoryGiBPerHourAnnotation = pricePrefix + "memory-gib-per-hour"
+	// GPUPerHourAnnotation is the annotation on a scalable resource holding
+	// the price of one GPU of its nodes per hour.
+	GPUPerHourAnnotation = pricePrefix + "gpu-per-hour"
+)
+
+var priceAnnotations = []string{PerHourAnnotation, CPUPerHourAnnotation, MemoryGiBPerHourAnnotation, GPUPerHourAnnotation}
+
+// PricedNodeGroup is implemented by node groups whose scalable resource can
+// carry price annotations.
+type PricedNodeGroup interface {
+	// PriceAnnotations returns the price annotations of the scalable resource.
+	PriceAnnotations() map[string]string
+}
+
+// PriceAnnotations returns the price annotations among the given annotations.
+// Providers copy them to template nodes, which don't belong to a scalable
+// resource yet. It returns nil if there are none.
+func PriceAnnotations(annotations map[string]string) map[string]string {
+	var result map[string]string
+	for _, key := range priceAnnotations {
+		if value, found := annotations[key]; found {
+			if result == nil {
+				result = map[string]string{}
+			}
+			result[key] = value
+		}
+	}
+	return result
+}
+
+// PriceModel implements cloudprovider.PricingModel.
+type PriceModel struct {
+	nodeGroupForNode func(*apiv1.Node) (cloudprovider.NodeGroup, error)
+	priceList        *pricelist.Model
+}
+
+var _ cloudprovider.PricingModel = (*PriceModel)(nil)
+
+// NewPriceModel returns a PriceModel. nodeGroupForNode is used to find the
+// price annotations of a node, source can be nil.
+func NewPriceModel(nodeGroupForNode func(*apiv1.Node) (cloudprovider.NodeGroup, error), source pricelist.Source) *PriceModel {
+	return &PriceModel{
+		nodeGroupForNode: nodeGroupForNode,
+		priceList:        pricelist.NewModel(source),
+	}
+}
+
+// NodePrice returns a price of running the given node for a given period of time.
+// The price is taken from, in that order:
+//   - the per-hour annotation of the node's scalable resource,
+//   - the per-resource annotations of the scalable resource, with list prices
+//     for the resources without an annotation,
+//   - the price list, which prices the node by instance type or resources.
+//
+// Prices of preemptible nodes are discounted with the preemptible multiplier
+// of the price list, whatever their source.
+func (m *PriceModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	annotations := m.annotationsForNode(node)
+	if perHour, found, err := parsePrice(annotations, PerHourAnnotation); err != nil {
+		return 0, err
+	} else if found {
+		preemptibleMultiplier, err := m.priceList.PreemptibleMultiplier(node.Labels)
+		if err != nil {
+			return 0, err
+		}
+		return perHour * preemptibleMultiplier * getHours(startTime, endTime), nil
+	}
+
+	hasResourceAnnotations := false
+	for _, key := range []string{CPUPerHourAnnotation, MemoryGiBPerHourAnnotation, GPUPerHourAnnotation} {
+		if _, found := annotations[key]; found {
+			hasResourceAnnotations = true
+		}
+	}
+	if !hasResourceAnnotations {
+		return m.priceList.NodePrice(node, startTime, endTime)
+	}
+
+	rates, err := m.priceList.ResourceRates(node.Labels)
+	if err != nil {
+		return 0, err
+	}
+	for key, target := range map[string]*float64{
+		CPUPerHourAnnotation:       &rates.CPU,
+		MemoryGiBPerHourAnnotation: &rates.MemoryGiB,
+		GPUPerHourAnnotation:       &rates.GPU,
+	} {
+		value, found, err := parsePrice(annotations, key)
+		if err != nil {
+			return 0, err
+		}
+		if found {
+			*target = value
+		}
+	}
+	preemptibleMultiplier, err := m.priceList.PreemptibleMultiplier(node.Labels)
+	if err != nil {
+		return 0, err
+	}
+	return rates.Price(node.Status.Capacity) * preemptibleMultiplier * getHours(startTime, endTime), nil
+}
+
+// PodPrice returns a theoretical minimum price of running a pod for a given
+// period of time on a perfectly matching machine, using list prices.
+func (m *PriceModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	return m.priceList.PodPrice(pod, startTime, endTime)
+}
+
+// annotationsForNode returns the price annotations of the node, or of its
+// scalable resource. Template nodes carry the annotations themselves.
+func (m *PriceModel) annotationsForNode(node *apiv1.Node) map[string]string {
+	if annotations := PriceAnnotations(node.Annotations); len(annotations) > 0 {
+		return annotations
+	}
+	if m.nodeGroupForNode == nil {
+		return nil
+	}
+	nodeGroup, err := m.nodeGroupForNode(node)
+	if err != nil {
+		klog.V(4).Infof("Failed to find node group of node %s for pricing: %v", node.Name, err)
+		return nil
+	}
+	if priced, ok := nodeGroup.(PricedNodeGroup); ok {
+		return priced.PriceAnnotations()
+	}
+	return nil
+}
+
+func parsePrice(values map[string]string, key string) (float64, bool, error) {
+	value, found := values[key]
+	if !found {
+		return 0, false, nil
+	}
+	price, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || price < 0 {
+		return 0, false, fmt.Errorf("invalid price %q for %s", value, key)
+	}
+	return price, true, nil
+}
+
+func getHours(startTime time.Time, endTime time.Time) float64 {
+	minutes := math.Ceil(float64(endTime.Sub(startTime)) / float64(time.Minute))
+	return minutes / 60.0
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricing
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/pricelist"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+)
+
+type pricedNodeGroup struct {
+	cloudprovider.NodeGroup
+	annotations map[string]string
+}
+
+func (ng *pricedNodeGroup) PriceAnnotations() map[string]string {
+	return ng.annotations
+}
+
+func testNode(instanceType string, cpu int64, memGiB int64, gpus int64) *apiv1.Node {
+	node := BuildTestNode("node", cpu*1000, memGiB*units.GiB)
+	if gpus > 0 {
+		node.Status.Capacity[gpu.ResourceNvidiaGPU] = *resource.NewQuantity(gpus, resource.DecimalSI)
+	}
+	if instanceType != "" {
+		node.Labels = map[string]string{apiv1.LabelInstanceTypeStable: instanceType}
+	}
+	return node
+}
+
+// Default prices of the price list.
+const (
+	defaultCPUPerHour       = 0.033174
+	defaultMemoryGiBPerHour = 0.004446
+	defaultGPUPerHour       = 0.700
+)
+
+type staticSource struct {
+	priceList *pricelist.PriceList
+}
+
+func (s *staticSource) PriceList() (*pricelist.PriceList, error) {
+	return s.priceList, nil
+}
+
+func newTestSource(t *testing.T, data string) pricelist.Source {
+	priceList, err := pricelist.Parse([]byte(data))
+	if err != nil {
+		t.Fatalf("invalid test price list: %v", err)
+	}
+	return &staticSource{priceList: priceList}
+}
+
+func TestNodePrice(t *testing.T) {
+	start := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(2 * time.Hour)
+
+	for _, tc := range []struct {
+		name        string
+		node        *apiv1.Node
+		labels      map[string]string
+		nodeGroup   cloudprovider.NodeGroup
+		prices      string
+		expected    float64
+		expectedErr bool
+	}{
+		{
+			name:     "default resource prices",
+			node:     testNode("", 2, 4, 1),
+			expected: 2 * (2*defaultCPUPerHour + 4*defaultMemoryGiBPerHour + defaultGPUPerHour),
+		},
+		{
+			name:      "per-hour annotation of the node group",
+			node:      testNode("m5.large", 2, 4, 0),
+			nodeGroup: &pricedNodeGroup{annotations: map[string]string{PerHourAnnotation: "0.5"}},
+			prices:    "instanceTypes: {m5.large: 0.1}",
+			expected:  1,
+		},
+		{
+			name:      "resource annotations take precedence over the instance type",
+			node:      testNode("m5.large", 2, 4, 0),
+			nodeGroup: &pricedNodeGroup{annotations: map[string]string{CPUPerHourAnnotation: "0.1"}},
+			prices:    "{instanceTypes: {m5.large: 0.1}, resources: {memoryGiB: 0.01}}",
+			expected:  2 * (2*0.1 + 4*0.01),
+		},
+		{
+			name:     "instance type price",
+			node:     testNode("m5.large", 2, 4, 0),
+			prices:   "instanceTypes: {m5.large: 0.096}",
+			expected: 2 * 0.096,
+		},
+		{
+			name:     "unknown instance type falls back to resource prices",
+			node:     testNode("m5.xlarge", 2, 4, 0),
+			prices:   "{instanceTypes: {m5.large: 0.096}, resources: {cpu: 0.02, memoryGiB: 0.005}}",
+			expected: 2 * (2*0.02 + 4*0.005),
+		},
+		{
+			name:     "preemptible instance type",
+			node:     testNode("m5.large", 2, 4, 0),
+			labels:   map[string]string{"cluster.x-k8s.io/interruptible": ""},
+			prices:   "{instanceTypes: {m5.large: 0.1}, preemptible: {multiplier: 0.3}}",
+			expected: 2 * 0.1 * 0.3,
+		},
+		{
+			name:     "preemptible resources",
+			node:     testNode("", 2, 4, 0),
+			labels:   map[string]string{"machine.openshift.io/interruptible-instance": ""},
+			prices:   "preemptible: {multiplier: 0.25}",
+			expected: 2 * (2*defaultCPUPerHour + 4*defaultMemoryGiBPerHour) * 0.25,
+		},
+		{
+			name:      "preemptible per-hour annotation",
+			node:      testNode("", 2, 4, 0),
+			labels:    map[string]string{"cluster.x-k8s.io/interruptible": ""},
+			nodeGroup: &pricedNodeGroup{annotations: map[string]string{PerHourAnnotation: "0.25"}},
+			prices:    "preemptible: {multiplier: 0.3}",
+			expected:  2 * 0.25 * 0.3,
+		},
+		{
+			name:      "preemptible resource annotations",
+			node:      testNode("", 2, 4, 0),
+			labels:    map[string]string{"machine.openshift.io/interruptible-instance": ""},
+			nodeGroup: &pricedNodeGroup{annotations: map[string]string{CPUPerHourAnnotation: "0.1", MemoryGiBPerHourAnnotation: "0.01"}},
+			prices:    "preemptible: {multiplier: 0.5}",
+			expected:  2 * (2*0.1 + 4*0.01) * 0.5,
+		},
+		{
+			name:      "per-hour annotation of a regular node",
+			node:      testNode("", 2, 4, 0),
+			nodeGroup: &pricedNodeGroup{annotations: map[string]string{PerHourAnnotation: "0.25"}},
+			prices:    "preemptible: {multiplier: 0.3}",
+			expected:  0.5,
+		},
+		{
+			name:        "invalid annotation",
+			node:        testNode("", 2, 4, 0),
+			nodeGroup:   &pricedNodeGroup{annotations: map[string]string{PerHourAnnotation: "cheap"}},
+			expectedErr: true,
+		},
+		{
+			name:      "resource annotations use the zone prices of the list",
+			node:      testNode("", 2, 4, 0),
+			labels:    map[string]string{apiv1.LabelTopologyZone: "zone-a"},
+			nodeGroup: &pricedNodeGroup{annotations: map[string]string{GPUPerHourAnnotation: "1"}},
+			prices:    "{resources: {cpu: 0.02}, zones: {zone-a: {multiplier: 2}}}",
+			expected:  2 * (2*0.04 + 4*2*defaultMemoryGiBPerHour),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for key, value := range tc.labels {
+				if tc.node.Labels == nil {
+					tc.node.Labels = map[string]string{}
+				}
+				tc.node.Labels[key] = value
+			}
+			nodeGroupForNode := func(*apiv1.Node) (cloudprovider.NodeGroup, error) {
+				return tc.nodeGroup, nil
+			}
+			var source pricelist.Source
+			if tc.prices != "" {
+				source = newTestSource(t, tc.prices)
+			}
+			model := NewPriceModel(nodeGroupForNode, source)
+
+			price, err := model.NodePrice(tc.node, start, end)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.expected, price, 1e-9)
+		})
+	}
+}
+
+func TestNodePriceTemplateNode(t *testing.T) {
+	node := testNode("m5.large", 2, 4, 0)
+	node.Annotations = PriceAnnotations(map[string]string{PerHourAnnotation: "0.3", "unrelated": "value"})
+	assert.Equal(t, map[string]string{PerHourAnnotation: "0.3"}, node.Annotations)
+
+	model := NewPriceModel(func(*apiv1.Node) (cloudprovider.NodeGroup, error) {
+		return nil, errors.New("template nodes don't belong to a node group")
+	}, nil)
+	start := time.Now()
+	price, err := model.NodePrice(node, start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.3, price, 1e-9)
+}
+
+func TestPodPrice(t *testing.T) {
+	pod := BuildTestPod("pod", 500, 2*units.GiB)
+	start := time.Now()
+
+	model := NewPriceModel(nil, newTestSource(t, "resources: {cpu: 0.04}"))
+	price, err := model.PodPrice(pod, start, start.Add(90*time.Minute))
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.5*(0.5*0.04+2*defaultMemoryGiBPerHour), price, 1e-9)
+}
+
+func TestPriceAnnotations(t *testing.T) {
+	assert.Nil(t, PriceAnnotations(nil))
+	assert.Nil(t, PriceAnnotations(map[string]string{"foo": "bar"}))
+	assert.Equal(t, map[string]string{
+		CPUPerHourAnnotation:       "0.1",
+		MemoryGiBPerHourAnnotation: "0.01",
+	}, PriceAnnotations(map[string]string{
+		CPUPerHourAnnotation:       "0.1",
+		MemoryGiBPerHourAnnotation: "0.01",
+		"foo":                      "bar",
+	}))
+}
+
+func TestGetHours(t *testing.T) {
+	start := time.Now()
+	assert.Equal(t, 1.0, getHours(start, start.Add(time.Hour)))
+	assert.True(t, math.Abs(getHours(start, start.Add(30*time.Second))-1.0/60) < 1e-9)
+}
@@ -0,0 +1,520 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	klog "k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/clusterapi/pricing"
+)
+
+const (
+	// autoprovisioningCatalogEnvVar is the environment variable holding the
+	// name of the autoprovisioning catalog ConfigMap in the Machine API
+	// namespace. Autoprovisioning is disabled if it isn't set.
+	autoprovisioningCatalogEnvVar = "OPENSHIFT_AUTOPROVISIONING_CATALOG_CONFIGMAP"
+	// autoprovisioningCatalogKey is the key of the catalog in the ConfigMap.
+	autoprovisioningCatalogKey = "catalog.yaml"
+
+	// autoprovisionedAnnotationKey marks the MachineSets created by the
+	// autoscaler. Only those are deleted once they are scaled to zero.
+	autoprovisionedAnnotationKey = "machine.openshift.io/cluster-api-autoscaler-autoprovisioned"
+	// machineSetLabelKey is the label linking a MachineSet to its Machines.
+	machineSetLabelKey = "machine.openshift.io/cluster-api-machineset"
+
+	// autoprovisionedNodeGroupMinAge keeps new MachineSets from being
+	// deleted before their first scale-up had a chance to happen.
+	autoprovisionedNodeGroupMinAge = 10 * time.Minute
+
+	// Machine names get a five character suffix and are used as label values,
+	// which are limited to 63 characters.
+	maxAutoprovisionedNameLength = 57
+)
+
+var configMapResource = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+// autoprovisioningCatalog lists the MachineSets that can be cloned to create
+// new node groups, and the machine types each of them can be cloned with.
+type autoprovisioningCatalog struct {
+	Templates []autoprovisioningTemplate `json:"templates"`
+}
+
+// autoprovisioningTemplate is a MachineSet approved as template for new node
+// groups. The paths are dot separated fields of the MachineSet, usually in
+// its provider spec, that are set to the machine type and the zone.
+type autoprovisioningTemplate struct {
+	MachineSet      string                        `json:"machineSet"`
+	MachineTypePath string                        `json:"machineTypePath"`
+	ZonePath        string                        `json:"zonePath,omitempty"`
+	Zones           []string                      `json:"zones,omitempty"`
+	MaxSize         int                           `json:"maxSize"`
+	MachineTypes    []autoprovisioningMachineType `json:"machineTypes"`
+}
+
+// autoprovisioningMachineType holds the capacity of a machine type, which
+// the autoscaler needs to scale the new MachineSets from zero.
+type autoprovisioningMachineType struct {
+	Name         string `json:"name"`
+	CPU          string `json:"cpu"`
+	Memory       string `json:"memory"`
+	GPU          string `json:"gpu,omitempty"`
+	GPUType      string `json:"gpuType,omitempty"`
+	PricePerHour string `json:"pricePerHour,omitempty"`
+}
+
+func parseAutoprovisioningCatalog(data string) (*autoprovisioningCatalog, error) {
+	catalog := &autoprovisioningCatalog{}
+	if err := yaml.UnmarshalStrict([]byte(data), catalog); err != nil {
+		return nil, err
+	}
+
+	for _, t := range catalog.Templates {
+		if t.MachineSet == "" || t.MachineTypePath == "" {
+			return nil, fmt.Errorf("templates need a machineSet and a machineTypePath")
+		}
+		if t.MaxSize <= 0 {
+			return nil, fmt.Errorf("template %s: maxSize must be positive", t.MachineSet)
+		}
+		if len(t.Zones) > 0 && t.ZonePath == "" {
+			return nil, fmt.Errorf("template %s: zones require a zonePath", t.MachineSet)
+		}
+		for _, mt := range t.MachineTypes {
+			if mt.Name == "" {
+				return nil, fmt.Errorf("template %s: machine types need a name", t.MachineSet)
+			}
+			for key, value := range map[string]string{"cpu": mt.CPU, "memory": mt.Memory} {
+				if _, err := resource.ParseQuantity(value); err != nil {
+					return nil, fmt.Errorf("template %s: invalid %s %q of machine type %s: %v", t.MachineSet, key, value, mt.Name, err)
+				}
+			}
+			if mt.GPU != "" {
+				if _, err := strconv.ParseInt(mt.GPU, 10, 0); err != nil {
+					return nil, fmt.Errorf("template %s: invalid gpu %q of machine type %s: %v", t.MachineSet, mt.GPU, mt.Name, err)
+				}
+			}
+		}
+	}
+
+	return catalog, nil
+}
+
+// machineTypes returns the machine types of all templates.
+func (c *autoprovisioningCatalog) machineTypes() []string {
+	if c == nil {
+		return []string{}
+	}
+
+	seen := map[string]bool{}
+	result := []string{}
+	for _, t := range c.Templates {
+		for _, mt := range t.MachineTypes {
+			if !seen[mt.Name] {
+				seen[mt.Name] = true
+				result = append(result, mt.Name)
+			}
+		}
+	}
+	return result
+}
+
+// find returns the first template offering the machine type in the given
+// zone, and the zone to use. An empty zone matches any template.
+func (c *autoprovisioningCatalog) find(machineType, zone string) (*autoprovisioningTemplate, *autoprovisioningMachineType, string, error) {
+	if c == nil {
+		return nil, nil, "", fmt.Errorf("no autoprovisioning catalog, machine type %s is not available", machineType)
+	}
+
+	for i := range c.Templates {
+		t := &c.Templates[i]
+		for j := range t.MachineTypes {
+			if t.MachineTypes[j].Name != machineType {
+				continue
+			}
+			if templateZone, ok := t.zoneFor(zone); ok {
+				return t, &t.MachineTypes[j], templateZone, nil
+			}
+		}
+	}
+
+	if zone != "" {
+		return nil, nil, "", fmt.Errorf("machine type %s is not available in zone %s", machineType, zone)
+	}
+	return nil, nil, "", fmt.Errorf("machine type %s is not available", machineType)
+}
+
+// zoneFor returns the zone the template should be cloned with. Templates
+// without zones keep their own zone, which can't be matched to a request.
+func (t *autoprovisioningTemplate) zoneFor(requested string) (string, bool) {
+	if requested == "" {
+		if len(t.Zones) > 0 {
+			return t.Zones[0], true
+		}
+		return "", true
+	}
+	for _, zone := range t.Zones {
+		if zone == requested {
+			return zone, true
+		}
+	}
+	return "", false
+}
+
+// refreshAutoprovisioningCatalog reads the catalog ConfigMap. The last valid
+// catalog is kept if it can't be read or parsed, so that a broken ConfigMap
+// doesn't stop the autoscaler.
+func (c *machineController) refreshAutoprovisioningCatalog() {
+	if c.autoprovisioningCatalogName == "" {
+		return
+	}
+
+	u, err := c.managementClient.Resource(configMapResource).Namespace(machineAPINamespace).Get(context.TODO(), c.autoprovisioningCatalogName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		if c.autoprovisioningCatalog != nil {
+			klog.Warningf("Autoprovisioning catalog %s/%s was deleted, disabling autoprovisioning", machineAPINamespace, c.autoprovisioningCatalogName)
+		}
+		c.autoprovisioningCatalog = nil
+		return
+	}
+	if err != nil {
+		klog.Warningf("Failed to get autoprovisioning catalog %s/%s: %v", machineAPINamespace, c.autoprovisioningCatalogName, err)
+		return
+	}
+
+	data, _, err := unstructured.NestedStringMap(u.Object, "data")
+	if err != nil {
+		klog.Warningf("Failed to read autoprovisioning catalog %s/%s: %v", machineAPINamespace, c.autoprovisioningCatalogName, err)
+		return
+	}
+	catalog, err := parseAutoprovisioningCatalog(data[autoprovisioningCatalogKey])
+	if err != nil {
+		klog.Warningf("Invalid autoprovisioning catalog %s/%s: %v", machineAPINamespace, c.autoprovisioningCatalogName, err)
+		return
+	}
+	c.autoprovisioningCatalog = catalog
+}
+
+// newAutoprovisionedNodeGroup returns a node group for a clone of the
+// catalog template offering the machine type. The MachineSet is created
+// by Create, unless a clone for the same request already exists.
+func (c *machineController) newAutoprovisionedNodeGroup(machineType string, labels, systemLabels map[string]string, taints []corev1.Taint) (*nodegroup, error) {
+	allLabels := make(map[string]string, len(labels)+len(systemLabels))
+	for k, v := range systemLabels {
+		allLabels[k] = v
+	}
+	for k, v := range labels {
+		allLabels[k] = v
+	}
+
+	zone := allLabels[corev1.LabelTopologyZone]
+	if zone == "" {
+		zone = allLabels[corev1.LabelFailureDomainBetaZone]
+	}
+	delete(allLabels, corev1.LabelTopologyZone)
+	delete(allLabels, corev1.LabelFailureDomainBetaZone)
+
+	t, mt, zone, err := c.autoprovisioningCatalog.find(machineType, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := c.findMachineSet(machineAPINamespace + "/" + t.MachineSet)
+	if err != nil {
+		return nil, err
+	}
+	if template == nil {
+		return nil, fmt.Errorf("template MachineSet %s/%s of machine type %s not found", machineAPINamespace, t.MachineSet, machineType)
+	}
+
+	name := autoprovisionedMachineSetName(t.MachineSet, machineType, zone, allLabels, taints)
+	existing, err := c.findMachineSet(machineAPINamespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		ng, err := newNodeGroupFromScalableResource(c, existing)
+		if err != nil {
+			return nil, err
+		}
+		if ng == nil {
+			return nil, fmt.Errorf("MachineSet %s/%s exists but isn't a node group", machineAPINamespace, name)
+		}
+		return ng, nil
+	}
+
+	machineSet, err := newAutoprovisionedMachineSet(template, name, t, mt, zone, allLabels, taints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to clone template MachineSet %s/%s: %v", machineAPINamespace, t.MachineSet, err)
+	}
+	scalableResource, err := newUnstructuredScalableResource(c, machineSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nodegroup{
+		machineController: c,
+		scalableResource:  scalableResource,
+	}, nil
+}
+
+// deleteEmptyAutoprovisionedNodeGroups deletes the autoprovisioned
+// MachineSets that were scaled to zero. Core autoscaler only deletes node
+// groups with a node group manager, so the provider cleans up after itself.
+func (c *machineController) deleteEmptyAutoprovisionedNodeGroups(now time.Time) {
+	scalableResources, err := c.listScalableResources()
+	if err != nil {
+		klog.Warningf("Failed to list MachineSets for autoprovisioning clean up: %v", err)
+		return
+	}
+
+	for _, r := range scalableResources {
+		if r.GetAnnotations()[autoprovisionedAnnotationKey] != "true" || !isScalableResourceMachineAPIAuthoritative(r) {
+			continue
+		}
+		if now.Sub(r.GetCreationTimestamp().Time) < autoprovisionedNodeGroupMinAge {
+			continue
+		}
+		if replicas, found, err := unstructured.NestedInt64(r.Object, "spec", "replicas"); err != nil || !found || replicas > 0 {
+			continue
+		}
+
+		ng, err := newNodeGroupFromScalableResource(c, r)
+		if err != nil || ng == nil {
+			continue
+		}
+		if err := ng.Delete(); err != nil {
+			klog.Warningf("Failed to delete empty autoprovisioned node group %s: %v", ng.Id(), err)
+			continue
+		}
+		klog.Infof("Deleted empty autoprovisioned node group %s", ng.Id())
+	}
+}
+
+// newAutoprovisionedMachineSet clones the template MachineSet with the
+// machine type, zone, labels and taints of a new node group.
+func newAutoprovisionedMachineSet(template *unstructured.Unstructured, name string, t *autoprovisioningTemplate, mt *autoprovisioningMachineType, zone string, labels map[string]string, taints []corev1.Taint) (*unstructured.Unstructured, error) {
+	spec, found, err := unstructured.NestedMap(template.Object, "spec")
+	if err != nil || !found {
+		return nil, fmt.Errorf("template has no spec")
+	}
+
+	u := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	u.SetAPIVersion(template.GetAPIVersion())
+	u.SetKind(template.GetKind())
+	u.SetName(name)
+	u.SetNamespace(template.GetNamespace())
+	u.SetLabels(template.GetLabels())
+	u.SetAnnotations(autoprovisionedAnnotations(template.GetAnnotations(), t, mt, zone))
+
+	if err := unstructured.SetNestedField(u.Object, int64(0), "spec", "replicas"); err != nil {
+		return nil, err
+	}
+	if err := setTemplatePath(u, t.MachineTypePath, mt.Name); err != nil {
+		return nil, err
+	}
+	if zone != "" {
+		if err := setTemplatePath(u, t.ZonePath, zone); err != nil {
+			return nil, err
+		}
+	}
+
+	// The selector keeps the labels of the template, which makes it match
+	// the template's Machines too unless it is narrowed to the new name.
+	for _, fields := range [][]string{{"spec", "selector", "matchLabels"}, {"spec", "template", "metadata", "labels"}} {
+		if err := setNestedStringMapEntry(u, machineSetLabelKey, name, fields...); err != nil {
+			return nil, err
+		}
+	}
+	for k, v := range labels {
+		if err := setNestedStringMapEntry(u, k, v, "spec", "template", "spec", "metadata", "labels"); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(taints) > 0 {
+		existing, _, err := unstructured.NestedSlice(u.Object, "spec", "template", "spec", "taints")
+		if err != nil {
+			return nil, err
+		}
+		merged := make([]interface{}, 0, len(existing)+len(taints))
+		for _, e := range existing {
+			if et := unstructuredToTaint(e); et != nil && containsTaint(taints, et) {
+				continue
+			}
+			merged = append(merged, e)
+		}
+		for _, taint := range taints {
+			merged = append(merged, map[string]interface{}{
+				"key":    taint.Key,
+				"value":  taint.Value,
+				"effect": string(taint.Effect),
+			})
+		}
+		if err := unstructured.SetNestedSlice(u.Object, merged, "spec", "template", "spec", "taints"); err != nil {
+			return nil, err
+		}
+	}
+
+	return u, nil
+}
+
+// autoprovisionedAnnotations returns the annotations of the template with
+// the scaling bounds and capacity of the new node group.
+func autoprovisionedAnnotations(templateAnnotations map[string]string, t *autoprovisioningTemplate, mt *autoprovisioningMachineType, zone string) map[string]string {
+	annotations := make(map[string]string, len(templateAnnotations))
+	for k, v := range templateAnnotations {
+		annotations[k] = v
+	}
+	for _, key := range []string{
+		corev1.LastAppliedConfigAnnotation,
+		cpuKey, memoryKey, gpuCountKey, gpuTypeKey,
+		deprecatedCpuKey, deprecatedMemoryKey, deprecatedGpuCountKey,
+		pricing.PerHourAnnotation,
+	} {
+		delete(annotations, key)
+	}
+
+	annotations[autoprovisionedAnnotationKey] = "true"
+	annotations[nodeGroupMinSizeAnnotationKey] = "0"
+	annotations[nodeGroupMaxSizeAnnotationKey] = strconv.Itoa(t.MaxSize)
+	annotations[cpuKey] = mt.CPU
+	annotations[memoryKey] = mt.Memory
+	if mt.GPU != "" {
+		annotations[gpuCountKey] = mt.GPU
+	}
+	if mt.GPUType != "" {
+		annotations[gpuTypeKey] = mt.GPUType
+	}
+	if mt.PricePerHour != "" {
+		annotations[pricing.PerHourAnnotation] = mt.PricePerHour
+	}
+
+	// The instance type and zone labels are set by the cloud on the real
+	// nodes, so they are only added to the template node labels.
+	templateLabels := map[string]string{}
+	if value, found := annotations[labelsKey]; found {
+		for _, label := range strings.Split(value, ",") {
+			if k, v, found := strings.Cut(label, "="); found {
+				templateLabels[k] = v
+			}
+		}
+	}
+	templateLabels[corev1.LabelInstanceTypeStable] = mt.Name
+	if zone != "" {
+		templateLabels[corev1.LabelTopologyZone] = zone
+	}
+	keys := make([]string, 0, len(templateLabels))
+	for k := range templateLabels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, k+"="+templateLabels[k])
+	}
+	annotations[labelsKey] = strings.Join(pairs, ",")
+
+	return annotations
+}
+
+// autoprovisionedMachineSetName returns a name derived from the request, so
+// that asking twice for the same node group yields the same MachineSet.
+func autoprovisionedMachineSetName(templateName, machineType, zone string, labels map[string]string, taints []corev1.Taint) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	hash := fnv.New32a()
+	fmt.Fprintf(hash, "%s\x00%s\x00%s", templateName, machineType, zone)
+	for _, k := range keys {
+		fmt.Fprintf(hash, "\x00%s=%s", k, labels[k])
+	}
+	// The same taints in a different order give the same name.
+	sortedTaints := append([]corev1.Taint(nil), taints...)
+	sort.Slice(sortedTaints, func(i, j int) bool {
+		a, b := sortedTaints[i], sortedTaints[j]
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		if a.Value != b.Value {
+			return a.Value < b.Value
+		}
+		return a.Effect < b.Effect
+	})
+	for _, taint := range sortedTaints {
+		fmt.Fprintf(hash, "\x00%s", taint.ToString())
+	}
+	suffix := fmt.Sprintf("-%08x", hash.Sum32())
+
+	prefix := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		return '-'
+	}, strings.ToLower(templateName+"-"+machineType))
+	if len(prefix) > maxAutoprovisionedNameLength-len(suffix) {
+		prefix = prefix[:maxAutoprovisionedNameLength-len(suffix)]
+	}
+	return strings.TrimRight(prefix, "-") + suffix
+}
+
+// setTemplatePath sets a dot separated string field that must already exist
+// in the template, which catches paths not matching the platform.
+func setTemplatePath(u *unstructured.Unstructured, path, value string) error {
+	fields := strings.Split(path, ".")
+	if _, found, err := unstructured.NestedString(u.Object, fields...); err != nil || !found {
+		return fmt.Errorf("string field %s not found", path)
+	}
+	return unstructured.SetNestedField(u.Object, value, fields...)
+}
+
+func setNestedStringMapEntry(u *unstructured.Unstructured, key, value string, fields ...string) error {
+	m, _, err := unstructured.NestedStringMap(u.Object, fields...)
+	if err != nil {
+		return err
+	}
+	if m == nil {
+		m = map[string]string{}
+	}
+	m[key] = value
+	return unstructured.SetNestedStringMap(u.Object, m, fields...)
+}
+
+func containsTaint(taints []corev1.Taint, taint *corev1.Taint) bool {
+	for i := range taints {
+		if taints[i].MatchTaint(taint) {
+			return true
+		}
+	}
+	return false
+}
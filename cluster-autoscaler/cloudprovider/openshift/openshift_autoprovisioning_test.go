@@ -0,0 +1,389 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package openshift
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+)
+
+const testAutoprovisioningCatalog = `
+templates:
+- machineSet: template-0
+  machineTypePath: spec.template.spec.providerSpec.value.instanceType
+  zonePath: spec.template.spec.providerSpec.value.placement.availabilityZone
+  zones: [us-east-1a, us-east-1b]
+  maxSize: 5
+  machineTypes:
+  - name: m5.xlarge
+    cpu: "4"
+    memory: 16Gi
+  - name: p3.2xlarge
+    cpu: "8"
+    memory: 61Gi
+    gpu: "1"
+`
+
+func createAutoprovisioningCatalog(t *testing.T, controller *testMachineController, catalog string) {
+	t.Helper()
+
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      "catalog",
+			"namespace": machineAPINamespace,
+		},
+		"data": map[string]interface{}{
+			autoprovisioningCatalogKey: catalog,
+		},
+	}}
+	client := controller.managementClient.Resource(configMapResource).Namespace(machineAPINamespace)
+	if _, err := client.Get(context.TODO(), "catalog", metav1.GetOptions{}); err == nil {
+		if _, err := client.Update(context.TODO(), configMap, metav1.UpdateOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return
+	}
+	if _, err := client.Create(context.TODO(), configMap, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// newAutoprovisioningTestProvider returns a provider with the test catalog
+// and a template MachineSet with one node, which isn't a node group itself.
+func newAutoprovisioningTestProvider(t *testing.T) (*provider, *testMachineController, *TestConfig) {
+	t.Helper()
+
+	controller := NewTestMachineController(t)
+	template := NewTestConfigBuilder().ForMachineSet().WithNamePrefix("template").WithNodeCount(1).Build()
+	setNestedField(t, template.machineSet.Object, "m5.large", "spec", "template", "spec", "providerSpec", "value", "instanceType")
+	setNestedField(t, template.machineSet.Object, "us-east-1a", "spec", "template", "spec", "providerSpec", "value", "placement", "availabilityZone")
+	if err := controller.AddTestConfigs(template); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	createAutoprovisioningCatalog(t, controller, testAutoprovisioningCatalog)
+	controller.autoprovisioningCatalogName = "catalog"
+
+	p := newProvider(&cloudprovider.ResourceLimiter{}, controller.machineController, nil, nil).(*provider)
+	if err := p.Refresh(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return p, controller, template
+}
+
+func TestParseAutoprovisioningCatalog(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "valid catalog",
+			data: testAutoprovisioningCatalog,
+		},
+		{
+			name: "empty catalog",
+			data: "",
+		},
+		{
+			name:    "unknown field",
+			data:    "templates:\n- machineSet: a\n  machineTypePath: spec.a\n  maxSize: 1\n  foo: bar\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing machine type path",
+			data:    "templates:\n- machineSet: a\n  maxSize: 1\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing max size",
+			data:    "templates:\n- machineSet: a\n  machineTypePath: spec.a\n",
+			wantErr: true,
+		},
+		{
+			name:    "zones without zone path",
+			data:    "templates:\n- machineSet: a\n  machineTypePath: spec.a\n  maxSize: 1\n  zones: [a]\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid memory",
+			data:    "templates:\n- machineSet: a\n  machineTypePath: spec.a\n  maxSize: 1\n  machineTypes:\n  - name: b\n    cpu: \"1\"\n    memory: lots\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid gpu",
+			data:    "templates:\n- machineSet: a\n  machineTypePath: spec.a\n  maxSize: 1\n  machineTypes:\n  - name: b\n    cpu: \"1\"\n    memory: 1Gi\n    gpu: half\n",
+			wantErr: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseAutoprovisioningCatalog(tc.data)
+			if tc.wantErr != (err != nil) {
+				t.Errorf("expected error %t, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAutoprovisionedMachineSetName(t *testing.T) {
+	taints := []corev1.Taint{{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule}}
+	name := autoprovisionedMachineSetName("worker-us-east-1a", "m5.xlarge", "us-east-1b", map[string]string{"team": "a"}, taints)
+
+	if !strings.HasPrefix(name, "worker-us-east-1a-m5-xlarge-") {
+		t.Errorf("unexpected name %q", name)
+	}
+	if again := autoprovisionedMachineSetName("worker-us-east-1a", "m5.xlarge", "us-east-1b", map[string]string{"team": "a"}, taints); again != name {
+		t.Errorf("expected %q, got %q", name, again)
+	}
+	if other := autoprovisionedMachineSetName("worker-us-east-1a", "m5.xlarge", "us-east-1b", map[string]string{"team": "b"}, taints); other == name {
+		t.Errorf("expected a different name for different labels, got %q", other)
+	}
+	if other := autoprovisionedMachineSetName("worker-us-east-1a", "m5.xlarge", "us-east-1b", map[string]string{"team": "a"}, nil); other == name {
+		t.Errorf("expected a different name for different taints, got %q", other)
+	}
+
+	gpuTaints := []corev1.Taint{
+		{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoSchedule},
+		{Key: "dedicated", Value: "gpu", Effect: corev1.TaintEffectNoExecute},
+		{Key: "accelerator", Value: "nvidia", Effect: corev1.TaintEffectNoSchedule},
+	}
+	reversedTaints := []corev1.Taint{gpuTaints[2], gpuTaints[1], gpuTaints[0]}
+	if a, b := autoprovisionedMachineSetName("worker", "p3.2xlarge", "", nil, gpuTaints), autoprovisionedMachineSetName("worker", "p3.2xlarge", "", nil, reversedTaints); a != b {
+		t.Errorf("expected the same name for taints in a different order, got %q and %q", a, b)
+	}
+
+	long := autoprovisionedMachineSetName(strings.Repeat("a", 80), "Standard_D4s_v3", "", nil, nil)
+	if len(long) > maxAutoprovisionedNameLength {
+		t.Errorf("expected at most %d characters, got %q", maxAutoprovisionedNameLength, long)
+	}
+	if short := autoprovisionedMachineSetName("worker", "Standard_D4s_v3", "", nil, nil); !strings.HasPrefix(short, "worker-standard-d4s-v3-") {
+		t.Errorf("unexpected name %q", short)
+	}
+}
+
+func TestNodeGroupAutoprovisioning(t *testing.T) {
+	p, controller, template := newAutoprovisioningTestProvider(t)
+	defer controller.Stop()
+
+	machineTypes, err := p.GetAvailableMachineTypes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []string{"m5.xlarge", "p3.2xlarge"}; !reflect.DeepEqual(machineTypes, expected) {
+		t.Errorf("expected %v, got %v", expected, machineTypes)
+	}
+
+	if _, err := p.NewNodeGroup("c5.large", nil, nil, nil, nil); err == nil {
+		t.Error("expected an error for a machine type not in the catalog")
+	}
+	if _, err := p.NewNodeGroup("m5.xlarge", nil, map[string]string{corev1.LabelTopologyZone: "us-west-2a"}, nil, nil); err == nil {
+		t.Error("expected an error for a zone not in the catalog")
+	}
+
+	labels := map[string]string{"team": "a"}
+	systemLabels := map[string]string{corev1.LabelTopologyZone: "us-east-1b"}
+	taints := []corev1.Taint{{Key: "dedicated", Value: "a", Effect: corev1.TaintEffectNoSchedule}}
+	ng, err := p.NewNodeGroup("m5.xlarge", labels, systemLabels, taints, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ng.Exist() {
+		t.Error("expected the node group not to exist before Create")
+	}
+	if !ng.Autoprovisioned() {
+		t.Error("expected the node group to be autoprovisioned")
+	}
+	if ng.MinSize() != 0 || ng.MaxSize() != 5 {
+		t.Errorf("expected min 0 and max 5, got %d and %d", ng.MinSize(), ng.MaxSize())
+	}
+
+	nodeInfo, err := ng.TemplateNodeInfo()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	node := nodeInfo.Node()
+	if cpu := node.Status.Capacity[corev1.ResourceCPU]; cpu.Cmp(resource.MustParse("4")) != 0 {
+		t.Errorf("expected 4 CPUs, got %v", cpu.String())
+	}
+	if memory := node.Status.Capacity[corev1.ResourceMemory]; memory.Cmp(resource.MustParse("16Gi")) != 0 {
+		t.Errorf("expected 16Gi of memory, got %v", memory.String())
+	}
+	for key, value := range map[string]string{
+		"team":                         "a",
+		corev1.LabelTopologyZone:       "us-east-1b",
+		corev1.LabelInstanceTypeStable: "m5.xlarge",
+	} {
+		if node.Labels[key] != value {
+			t.Errorf("expected label %s=%s, got %q", key, value, node.Labels[key])
+		}
+	}
+	if !reflect.DeepEqual(node.Spec.Taints, taints) {
+		t.Errorf("expected taints %v, got %v", taints, node.Spec.Taints)
+	}
+
+	created, err := ng.Create()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created.Exist() || created.Id() != ng.Id() {
+		t.Errorf("expected created node group %s to exist, got %s", ng.Id(), created.Id())
+	}
+	if _, err := created.Create(); err != cloudprovider.ErrAlreadyExist {
+		t.Errorf("expected %v, got %v", cloudprovider.ErrAlreadyExist, err)
+	}
+
+	name := created.(*nodegroup).scalableResource.Name()
+	machineSet, err := controller.managementClient.Resource(controller.machineSetResource).Namespace(machineAPINamespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for value, fields := range map[string][]string{
+		"m5.xlarge":  {"spec", "template", "spec", "providerSpec", "value", "instanceType"},
+		"us-east-1b": {"spec", "template", "spec", "providerSpec", "value", "placement", "availabilityZone"},
+		name:         {"spec", "selector", "matchLabels", machineSetLabelKey},
+	} {
+		if actual, _, _ := unstructured.NestedString(machineSet.Object, fields...); actual != value {
+			t.Errorf("expected %s to be %q, got %q", strings.Join(fields, "."), value, actual)
+		}
+	}
+	if replicas, _, _ := unstructured.NestedInt64(machineSet.Object, "spec", "replicas"); replicas != 0 {
+		t.Errorf("expected 0 replicas, got %d", replicas)
+	}
+	if instanceType, _, _ := unstructured.NestedString(template.machineSet.Object, "spec", "template", "spec", "providerSpec", "value", "instanceType"); instanceType != "m5.large" {
+		t.Errorf("expected the template to be unchanged, got instance type %q", instanceType)
+	}
+
+	// Once the informer has seen the MachineSet, asking for the same node
+	// group returns the existing one, without the template's machines.
+	if err := wait.PollUntilContextTimeout(context.Background(), time.Millisecond, fifteenSecondDuration, true, func(_ context.Context) (bool, error) {
+		existing, err := controller.findMachineSet(machineAPINamespace + "/" + name)
+		return existing != nil, err
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again, err := p.NewNodeGroup("m5.xlarge", labels, systemLabels, taints, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !again.Exist() || again.Id() != ng.Id() {
+		t.Errorf("expected existing node group %s, got %s", ng.Id(), again.Id())
+	}
+	nodes, err := again.Nodes()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nodes) != 0 {
+		t.Errorf("expected no nodes, got %v", nodes)
+	}
+
+	if err := created.IncreaseSize(1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := created.Delete(); err == nil {
+		t.Error("expected an error deleting a node group with replicas")
+	}
+	if err := created.(*nodegroup).scalableResource.SetSize(0); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := created.Delete(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := controller.managementClient.Resource(controller.machineSetResource).Namespace(machineAPINamespace).Get(context.TODO(), name, metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Errorf("expected MachineSet %s to be deleted, got %v", name, err)
+	}
+}
+
+func TestProviderRefreshAutoprovisioningCatalog(t *testing.T) {
+	p, controller, _ := newAutoprovisioningTestProvider(t)
+	defer controller.Stop()
+
+	// An invalid catalog keeps the last valid one.
+	createAutoprovisioningCatalog(t, controller, "templates: [")
+	if err := p.Refresh(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if machineTypes, _ := p.GetAvailableMachineTypes(); len(machineTypes) != 2 {
+		t.Errorf("expected 2 machine types, got %v", machineTypes)
+	}
+
+	if err := controller.managementClient.Resource(configMapResource).Namespace(machineAPINamespace).Delete(context.TODO(), "catalog", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := p.Refresh(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if machineTypes, _ := p.GetAvailableMachineTypes(); len(machineTypes) != 0 {
+		t.Errorf("expected no machine types, got %v", machineTypes)
+	}
+}
+
+func TestProviderRefreshDeletesEmptyAutoprovisionedNodeGroups(t *testing.T) {
+	controller := NewTestMachineController(t)
+	defer controller.Stop()
+
+	annotations := map[string]string{
+		nodeGroupMinSizeAnnotationKey: "0",
+		nodeGroupMaxSizeAnnotationKey: "3",
+		cpuKey:                        "2",
+		memoryKey:                     "8Gi",
+	}
+	autoprovisioned := map[string]string{autoprovisionedAnnotationKey: "true"}
+
+	empty := NewTestConfigBuilder().ForMachineSet().WithAnnotations(annotations).WithAnnotations(autoprovisioned).Build()
+	scaledUp := NewTestConfigBuilder().ForMachineSet().WithNodeCount(1).WithAnnotations(annotations).WithAnnotations(autoprovisioned).Build()
+	recent := NewTestConfigBuilder().ForMachineSet().WithAnnotations(annotations).WithAnnotations(autoprovisioned).Build()
+	recent.machineSet.SetCreationTimestamp(metav1.Now())
+	notAutoprovisioned := NewTestConfigBuilder().ForMachineSet().WithAnnotations(annotations).Build()
+	if err := controller.AddTestConfigs(empty, scaledUp, recent, notAutoprovisioned); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	p := newProvider(&cloudprovider.ResourceLimiter{}, controller.machineController, nil, nil)
+	if err := p.Refresh(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		config  *TestConfig
+		deleted bool
+	}{
+		{empty, true},
+		{scaledUp, false},
+		{recent, false},
+		{notAutoprovisioned, false},
+	} {
+		_, err := controller.managementClient.Resource(controller.machineSetResource).Namespace(machineAPINamespace).Get(context.TODO(), tc.config.machineSet.GetName(), metav1.GetOptions{})
+		if deleted := apierrors.IsNotFound(err); deleted != tc.deleted {
+			t.Errorf("expected MachineSet %s deleted to be %t, got %v", tc.config.machineSet.GetName(), tc.deleted, err)
+		}
+	}
+}
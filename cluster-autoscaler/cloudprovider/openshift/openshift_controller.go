@@ -21,6 +21,7 @@ import (
 	"path"
 	"strings"
 	"sync"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -54,6 +55,19 @@ const (
 	machinePhaseFailed     = "Failed"
 )
 
+const (
+	machinePhaseProvisioned = "Provisioned"
+	machinePhaseRunning     = "Running"
+
+	// defaultAtomicScaleUpTimeout is how long AtomicIncreaseSize waits for
+	// the new Machines of a MachineSet to be provisioned, unless the
+	// MachineSet overrides it with atomicScaleUpTimeoutAnnotationKey.
+	defaultAtomicScaleUpTimeout = 5 * time.Minute
+	// defaultAtomicScaleUpPollInterval is how often AtomicIncreaseSize
+	// checks the new Machines of a MachineSet.
+	defaultAtomicScaleUpPollInterval = 5 * time.Second
+)
+
 // machineController watches for Nodes, Machines, and MachineSets
 // as they are added, updated and deleted on the
 // cluster. Additionally, it adds indices to the node informers to
@@ -72,6 +86,14 @@ type machineController struct {
 	machineSetResource        schema.GroupVersionResource
 	machineResource           schema.GroupVersionResource
 	accessLock                sync.Mutex
+	// atomicScaleUpTimeout and atomicScaleUpPollInterval control how
+	// AtomicIncreaseSize waits for new Machines.
+	atomicScaleUpTimeout      time.Duration
+	atomicScaleUpPollInterval time.Duration
+	// autoprovisioningCatalogName is the name of the autoprovisioning
+	// catalog ConfigMap, autoprovisioningCatalog its last valid content.
+	autoprovisioningCatalogName string
+	autoprovisioningCatalog     *autoprovisioningCatalog
 	// stopChannel is used for running the shared informers, and for starting
 	// informers associated with infrastructure machine templates that are
 	// discovered during operation.
@@ -370,6 +392,8 @@ func newMachineController(
 		managementDiscoveryClient: managementDiscoveryClient,
 		machineSetResource:        gvrMachineSet,
 		machineResource:           gvrMachine,
+		atomicScaleUpTimeout:      defaultAtomicScaleUpTimeout,
+		atomicScaleUpPollInterval: defaultAtomicScaleUpPollInterval,
 		stopChannel:               stopChannel,
 	}, nil
 }
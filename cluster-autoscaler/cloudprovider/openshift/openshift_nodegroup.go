@@ -17,6 +17,7 @@ limitations under the License.
 package openshift
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"strconv"
@@ -27,11 +28,14 @@ import (
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/clusterapi/pricing"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 )
 
@@ -42,11 +46,18 @@ const (
 	// best practices documentation for large clusters.
 	// see https://kubernetes.io/docs/setup/best-practices/cluster-large/
 	defaultMaxPods = 110
+
+	// machineErrorReasonInsufficientResources is the Machine API error
+	// reason of Machines that failed because of quota or capacity.
+	machineErrorReasonInsufficientResources = "InsufficientResources"
 )
 
 type nodegroup struct {
 	machineController *machineController
 	scalableResource  *unstructuredScalableResource
+	// exists is false for autoprovisioned node groups whose MachineSet
+	// hasn't been created yet.
+	exists bool
 }
 
 var _ cloudprovider.NodeGroup = (*nodegroup)(nil)
@@ -92,9 +103,121 @@ func (ng *nodegroup) IncreaseSize(delta int) error {
 	return ng.scalableResource.SetSize(size + delta)
 }
 
-// AtomicIncreaseSize is not implemented.
+// AtomicIncreaseSize increases the size of the node group by delta and
+// waits until all the new Machines are provisioned. If one of them fails,
+// or they are not all provisioned before the timeout, the new Machines are
+// marked for deletion and the replicas are set back to their previous
+// value. The returned *cloudprovider.ScaleUpError describes the failure.
+//
+// Like the Azure implementation, this blocks for up to the atomic scale-up
+// timeout, which is what provides the all-or-nothing guarantee.
 func (ng *nodegroup) AtomicIncreaseSize(delta int) error {
-	return cloudprovider.ErrNotImplemented
+	if delta <= 0 {
+		return fmt.Errorf("size increase must be positive")
+	}
+
+	size, err := ng.scalableResource.Replicas()
+	if err != nil {
+		return err
+	}
+	if size+delta > ng.MaxSize() {
+		return fmt.Errorf("size increase too large - desired:%d max:%d", size+delta, ng.MaxSize())
+	}
+
+	machines, err := ng.machineController.listMachinesForScalableResource(ng.scalableResource.unstructured)
+	if err != nil {
+		return err
+	}
+	existingMachines := make(map[string]bool, len(machines))
+	for _, machine := range machines {
+		existingMachines[machine.GetName()] = true
+	}
+
+	if err := ng.scalableResource.SetSize(size + delta); err != nil {
+		return err
+	}
+
+	timeout := atomicScaleUpTimeout(ng.scalableResource.unstructured.GetAnnotations(), ng.machineController.atomicScaleUpTimeout)
+	var errorInfo *cloudprovider.InstanceErrorInfo
+	provisioned := 0
+	pollErr := wait.PollUntilContextTimeout(context.TODO(), ng.machineController.atomicScaleUpPollInterval, timeout, true, func(_ context.Context) (bool, error) {
+		newMachines, err := ng.newMachines(existingMachines)
+		if err != nil {
+			return false, err
+		}
+
+		provisioned = 0
+		for _, machine := range newMachines {
+			if info := machineProvisioningErrorInfo(machine); info != nil {
+				errorInfo = info
+				return true, nil
+			}
+			if isMachineProvisioned(machine) {
+				provisioned++
+			}
+		}
+		return provisioned >= delta, nil
+	})
+
+	if errorInfo == nil {
+		if pollErr == nil {
+			klog.V(4).Infof("%s: AtomicIncreaseSize: %d machines provisioned", ng.Id(), delta)
+			return nil
+		}
+
+		errorInfo = &cloudprovider.InstanceErrorInfo{
+			ErrorClass:   cloudprovider.OtherErrorClass,
+			ErrorCode:    "AtomicScaleUpFailed",
+			ErrorMessage: pollErr.Error(),
+		}
+		if wait.Interrupted(pollErr) {
+			errorInfo.ErrorCode = "AtomicScaleUpTimeout"
+			errorInfo.ErrorMessage = fmt.Sprintf("%d of %d machines provisioned in %v", provisioned, delta, timeout)
+		}
+	}
+
+	scaleUpErr := &cloudprovider.ScaleUpError{ErrorInfo: *errorInfo}
+	klog.Warningf("%s: AtomicIncreaseSize failed, rolling back to %d replicas: %v", ng.Id(), size, scaleUpErr)
+	if err := ng.rollBackIncreaseSize(size, existingMachines); err != nil {
+		return fmt.Errorf("%w; failed to roll back node group %s: %v", scaleUpErr, ng.Id(), err)
+	}
+	return scaleUpErr
+}
+
+// newMachines returns the machines of the node group that are not in
+// existingMachines and are not being deleted.
+func (ng *nodegroup) newMachines(existingMachines map[string]bool) ([]*unstructured.Unstructured, error) {
+	machines, err := ng.machineController.listMachinesForScalableResource(ng.scalableResource.unstructured)
+	if err != nil {
+		return nil, err
+	}
+
+	var newMachines []*unstructured.Unstructured
+	for _, machine := range machines {
+		if !existingMachines[machine.GetName()] && machine.GetDeletionTimestamp().IsZero() {
+			newMachines = append(newMachines, machine)
+		}
+	}
+	return newMachines, nil
+}
+
+// rollBackIncreaseSize sets the replicas back to size. The machines created
+// since the scale-up are marked for deletion first, so that the MachineSet
+// deletes them rather than the machines in existingMachines.
+func (ng *nodegroup) rollBackIncreaseSize(size int, existingMachines map[string]bool) error {
+	ng.machineController.accessLock.Lock()
+	defer ng.machineController.accessLock.Unlock()
+
+	machines, err := ng.newMachines(existingMachines)
+	if err != nil {
+		return err
+	}
+	for _, machine := range machines {
+		if err := ng.scalableResource.MarkMachineForDeletion(machine); err != nil {
+			return err
+		}
+	}
+	return ng.scalableResource.SetSize(size)
 }
 
 // DeleteNodes deletes nodes from this node group. Error is returned
@@ -369,6 +492,7 @@ func (ng *nodegroup) TemplateNodeInfo() (*framework.NodeInfo, error) {
 	node.Status.Allocatable = capacity
 	node.Status.Conditions = cloudprovider.BuildReadyConditions()
 	node.Spec.Taints = ng.scalableResource.Taints()
+	node.Annotations = pricing.PriceAnnotations(ng.scalableResource.unstructured.GetAnnotations())
 
 	node.Labels, err = ng.buildTemplateLabels(nodeName)
 	if err != nil {
@@ -405,11 +529,16 @@ func (ng *nodegroup) buildTemplateLabels(nodeName string) (map[string]string, er
 	return labels, nil
 }
 
+// PriceAnnotations returns the price annotations of the MachineSet.
+func (ng *nodegroup) PriceAnnotations() map[string]string {
+	return pricing.PriceAnnotations(ng.scalableResource.unstructured.GetAnnotations())
+}
+
 // Exist checks if the node group really exists on the cloud nodegroup
 // side. Allows to tell the theoretical node group from the real one.
 // Implementation required.
 func (ng *nodegroup) Exist() bool {
-	return true
+	return ng.exists
 }
 
 // Create creates the node group on the cloud nodegroup side.
@@ -418,21 +547,62 @@ func (ng *nodegroup) Create() (cloudprovider.NodeGroup, error) {
 	if ng.Exist() {
 		return nil, cloudprovider.ErrAlreadyExist
 	}
-	return nil, cloudprovider.ErrNotImplemented
+
+	u, err := ng.machineController.managementClient.Resource(ng.machineController.machineSetResource).
+		Namespace(ng.scalableResource.Namespace()).
+		Create(context.TODO(), ng.scalableResource.unstructured, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MachineSet %s: %v", ng.Id(), err)
+	}
+	klog.Infof("Created autoprovisioned MachineSet %s", ng.Id())
+
+	created, err := newNodeGroupFromScalableResource(ng.machineController, u)
+	if err != nil {
+		return nil, err
+	}
+	if created == nil {
+		return nil, fmt.Errorf("created MachineSet %s isn't a node group", ng.Id())
+	}
+	return created, nil
 }
 
 // Delete deletes the node group on the cloud nodegroup side. This will
 // be executed only for autoprovisioned node groups, once their size
 // drops to 0. Implementation optional.
 func (ng *nodegroup) Delete() error {
-	return cloudprovider.ErrNotImplemented
+	if !ng.Autoprovisioned() {
+		return fmt.Errorf("node group %s wasn't autoprovisioned and can't be deleted", ng.Id())
+	}
+
+	replicas, err := ng.scalableResource.Replicas()
+	if err != nil {
+		return err
+	}
+	if replicas > 0 {
+		return fmt.Errorf("node group %s still has %d replicas", ng.Id(), replicas)
+	}
+	machines, err := ng.machineController.listMachinesForScalableResource(ng.scalableResource.unstructured)
+	if err != nil {
+		return err
+	}
+	if len(machines) > 0 {
+		return fmt.Errorf("node group %s still has %d machines", ng.Id(), len(machines))
+	}
+
+	err = ng.machineController.managementClient.Resource(ng.machineController.machineSetResource).
+		Namespace(ng.scalableResource.Namespace()).
+		Delete(context.TODO(), ng.scalableResource.Name(), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete MachineSet %s: %v", ng.Id(), err)
+	}
+	return nil
 }
 
 // Autoprovisioned returns true if the node group is autoprovisioned.
 // An autoprovisioned group was created by CA and can be deleted when
 // scaled to 0.
 func (ng *nodegroup) Autoprovisioned() bool {
-	return false
+	return ng.scalableResource.unstructured.GetAnnotations()[autoprovisionedAnnotationKey] == "true"
 }
 
 // GetOptions returns NodeGroupAutoscalingOptions that should be used for this particular
@@ -492,6 +662,7 @@ func newNodeGroupFromScalableResource(controller *machineController, unstructure
 	return &nodegroup{
 		machineController: controller,
 		scalableResource:  scalableResource,
+		exists:            true,
 	}, nil
 }
 
@@ -18,6 +18,7 @@ package openshift
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path"
 	"sort"
@@ -189,7 +190,7 @@ func TestNodeGroupNewNodeGroupConstructor(t *testing.T) {
 			t.Error("expected error")
 		}
 
-		if err := ng.Delete(); err != cloudprovider.ErrNotImplemented {
+		if err := ng.Delete(); err == nil {
 			t.Error("expected error")
 		}
 
@@ -380,6 +381,188 @@ func TestNodeGroupIncreaseSize(t *testing.T) {
 	})
 }
 
+func TestNodeGroupAtomicIncreaseSize(t *testing.T) {
+	type testCase struct {
+		description       string
+		delta             int
+		machineStatus     map[string]interface{}
+		expectedReplicas  int32
+		expectedErrorInfo *cloudprovider.InstanceErrorInfo
+		expectedErr       bool
+	}
+
+	const initial = 3
+
+	annotations := map[string]string{
+		nodeGroupMinSizeAnnotationKey: "1",
+		nodeGroupMaxSizeAnnotationKey: "10",
+	}
+
+	// provisionMachines acts as the MachineSet controller: it waits for the
+	// replicas to be increased and creates the missing machines. The last one
+	// gets the given status, the others are provisioned.
+	provisionMachines := func(t *testing.T, controller *testMachineController, testConfig *TestConfig, delta int, status map[string]interface{}) []string {
+		t.Helper()
+
+		err := wait.PollUntilContextTimeout(context.TODO(), 10*time.Millisecond, fifteenSecondDuration, true, func(_ context.Context) (bool, error) {
+			ms, err := controller.managementClient.Resource(controller.machineSetResource).Namespace(testConfig.machineSet.GetNamespace()).
+				Get(context.TODO(), testConfig.machineSet.GetName(), metav1.GetOptions{})
+			if err != nil {
+				return false, err
+			}
+			replicas, _, err := unstructured.NestedInt64(ms.UnstructuredContent(), "spec", "replicas")
+			return replicas == int64(initial+delta), err
+		})
+		if err != nil {
+			t.Errorf("replicas were not increased: %v", err)
+			return nil
+		}
+
+		var names []string
+		for i := 0; i < delta; i++ {
+			machine := testConfig.machines[0].DeepCopy()
+			machine.SetName(fmt.Sprintf("%s-new-%d", machine.GetName(), i))
+			machine.SetResourceVersion("")
+			unstructured.RemoveNestedField(machine.Object, "spec", "providerID")
+			machine.Object["status"] = map[string]interface{}{"phase": machinePhaseProvisioned}
+			if i == delta-1 {
+				machine.Object["status"] = status
+			}
+			if err := controller.CreateResource(controller.machineInformer, controller.machineResource, machine); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			names = append(names, machine.GetName())
+		}
+		return names
+	}
+
+	test := func(t *testing.T, tc *testCase) {
+		controller := NewTestMachineController(t)
+		defer controller.Stop()
+		controller.atomicScaleUpTimeout = 500 * time.Millisecond
+		controller.atomicScaleUpPollInterval = 10 * time.Millisecond
+
+		testConfig := NewTestConfigBuilder().
+			ForMachineSet().
+			WithNodeCount(initial).
+			WithAnnotations(annotations).
+			Build()
+		controller.AddTestConfigs(testConfig)
+
+		nodegroups, err := controller.nodeGroups()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if l := len(nodegroups); l != 1 {
+			t.Fatalf("expected 1 nodegroup, got %d", l)
+		}
+		ng := nodegroups[0].(*nodegroup)
+
+		newMachines := make(chan []string, 1)
+		if tc.machineStatus != nil {
+			go func() {
+				newMachines <- provisionMachines(t, controller, testConfig, tc.delta, tc.machineStatus)
+			}()
+		} else {
+			close(newMachines)
+		}
+
+		err = ng.AtomicIncreaseSize(tc.delta)
+		names := <-newMachines
+		switch {
+		case tc.expectedErrorInfo != nil:
+			var scaleUpErr *cloudprovider.ScaleUpError
+			if !errors.As(err, &scaleUpErr) {
+				t.Fatalf("expected a ScaleUpError, got %v", err)
+			}
+			assert.Equal(t, tc.expectedErrorInfo.ErrorClass, scaleUpErr.ErrorInfo.ErrorClass)
+			assert.Equal(t, tc.expectedErrorInfo.ErrorCode, scaleUpErr.ErrorInfo.ErrorCode)
+			assert.Contains(t, scaleUpErr.ErrorInfo.ErrorMessage, tc.expectedErrorInfo.ErrorMessage)
+		case tc.expectedErr:
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		default:
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		replicas, err := ng.scalableResource.Replicas()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if replicas != int(tc.expectedReplicas) {
+			t.Errorf("expected %v replicas, got %v", tc.expectedReplicas, replicas)
+		}
+
+		// On rollback the new machines, and only them, are marked for deletion.
+		rolledBack := tc.expectedErrorInfo != nil
+		for _, name := range names {
+			machine, err := controller.managementClient.Resource(controller.machineResource).Namespace(testConfig.namespace).
+				Get(context.TODO(), name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, found := machine.GetAnnotations()[machineDeleteAnnotationKey]; found != rolledBack {
+				t.Errorf("expected annotation %q on machine %s: %v", machineDeleteAnnotationKey, name, rolledBack)
+			}
+		}
+		for _, machine := range testConfig.machines {
+			machine, err := controller.managementClient.Resource(controller.machineResource).Namespace(testConfig.namespace).
+				Get(context.TODO(), machine.GetName(), metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if _, found := machine.GetAnnotations()[machineDeleteAnnotationKey]; found {
+				t.Errorf("unexpected annotation %q on machine %s", machineDeleteAnnotationKey, machine.GetName())
+			}
+		}
+	}
+
+	testCases := []testCase{{
+		description:      "all machines are provisioned",
+		delta:            2,
+		machineStatus:    map[string]interface{}{"phase": machinePhaseProvisioned},
+		expectedReplicas: initial + 2,
+	}, {
+		description: "a machine fails because of insufficient resources",
+		delta:       2,
+		machineStatus: map[string]interface{}{
+			"phase":        machinePhaseFailed,
+			"errorReason":  machineErrorReasonInsufficientResources,
+			"errorMessage": "quota exceeded",
+		},
+		expectedReplicas: initial,
+		expectedErrorInfo: &cloudprovider.InstanceErrorInfo{
+			ErrorClass:   cloudprovider.OutOfResourcesErrorClass,
+			ErrorCode:    machineErrorReasonInsufficientResources,
+			ErrorMessage: "quota exceeded",
+		},
+	}, {
+		description:      "machines are not provisioned in time",
+		delta:            1,
+		machineStatus:    map[string]interface{}{},
+		expectedReplicas: initial,
+		expectedErrorInfo: &cloudprovider.InstanceErrorInfo{
+			ErrorClass:   cloudprovider.OtherErrorClass,
+			ErrorCode:    "AtomicScaleUpTimeout",
+			ErrorMessage: "0 of 1 machines provisioned",
+		},
+	}, {
+		description:      "increase above the max size",
+		delta:            8,
+		expectedReplicas: initial,
+		expectedErr:      true,
+	}}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			test(t, &tc)
+		})
+	}
+}
+
 func TestNodeGroupDecreaseTargetSize(t *testing.T) {
 	type testCase struct {
 		description                         string
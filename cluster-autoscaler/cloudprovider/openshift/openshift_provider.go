@@ -22,6 +22,7 @@ import (
 	"os"
 	"path"
 	"reflect"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -38,6 +39,8 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/builder"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/clusterapi"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/clusterapi/pricing"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/pricelist"
 	coreoptions "k8s.io/autoscaler/cluster-autoscaler/core/options"
 	caserrors "k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
@@ -62,17 +65,20 @@ type provider struct {
 	controller         *machineController
 	resourceLimiter    *cloudprovider.ResourceLimiter
 	clusterapiProvider cloudprovider.CloudProvider
+	priceList          pricelist.Source
 }
 
 func newProvider(
 	rl *cloudprovider.ResourceLimiter,
 	controller *machineController,
 	clusterapiProvider cloudprovider.CloudProvider,
+	priceList pricelist.Source,
 ) cloudprovider.CloudProvider {
 	return &provider{
 		resourceLimiter:    rl,
 		controller:         controller,
 		clusterapiProvider: clusterapiProvider,
+		priceList:          priceList,
 	}
 }
 
@@ -141,29 +147,44 @@ func (p *provider) HasInstance(node *corev1.Node) (bool, error) {
 	return false, fmt.Errorf("machine not found for node %s: %v", node.Name, err)
 }
 
-func (*provider) Pricing() (cloudprovider.PricingModel, caserrors.AutoscalerError) {
-	return nil, cloudprovider.ErrNotImplemented
+// Pricing returns a pricing model based on the price annotations of the
+// MachineSets and the optional price list. Nodes of Cluster API
+// authoritative resources are priced from the annotations of those resources.
+func (p *provider) Pricing() (cloudprovider.PricingModel, caserrors.AutoscalerError) {
+	return pricing.NewPriceModel(p.NodeGroupForNode, p.priceList), nil
 }
 
-func (*provider) GetAvailableMachineTypes() ([]string, error) {
-	return []string{}, nil
+// GetAvailableMachineTypes returns the machine types of the autoprovisioning
+// catalog.
+func (p *provider) GetAvailableMachineTypes() ([]string, error) {
+	return p.controller.autoprovisioningCatalog.machineTypes(), nil
 }
 
-func (*provider) NewNodeGroup(
+// NewNodeGroup returns a node group cloning the catalog template that offers
+// the machine type. It has to be created with Create.
+func (p *provider) NewNodeGroup(
 	machineType string,
 	labels map[string]string,
 	systemLabels map[string]string,
 	taints []corev1.Taint,
 	extraResources map[string]resource.Quantity,
 ) (cloudprovider.NodeGroup, error) {
-	return nil, cloudprovider.ErrNotImplemented
+	ng, err := p.controller.newAutoprovisionedNodeGroup(machineType, labels, systemLabels, taints)
+	if err != nil {
+		return nil, err
+	}
+	return ng, nil
 }
 
 func (*provider) Cleanup() error {
 	return nil
 }
 
+// Refresh reloads the autoprovisioning catalog and deletes the empty
+// autoprovisioned node groups.
 func (p *provider) Refresh() error {
+	p.controller.refreshAutoprovisioningCatalog()
+	p.controller.deleteEmptyAutoprovisionedNodeGroups(time.Now())
 	return nil
 }
 
@@ -262,6 +283,8 @@ func BuildOpenShift(opts *coreoptions.AutoscalerOptions, do cloudprovider.NodeGr
 		klog.Fatal(err)
 	}
 
+	controller.autoprovisioningCatalogName = os.Getenv(autoprovisioningCatalogEnvVar)
+
 	if err := controller.run(); err != nil {
 		klog.Fatal(err)
 	}
@@ -270,5 +293,6 @@ func BuildOpenShift(opts *coreoptions.AutoscalerOptions, do cloudprovider.NodeGr
 		rl,
 		controller,
 		clusterapiProvider,
+		opts.PriceListSource,
 	)
 }
@@ -32,7 +32,7 @@ func TestProviderConstructorProperties(t *testing.T) {
 	controller := NewTestMachineController(t)
 	defer controller.Stop()
 
-	provider := newProvider(&resourceLimits, controller.machineController, nil)
+	provider := newProvider(&resourceLimits, controller.machineController, nil, nil)
 	if actual := provider.Name(); actual != cloudprovider.OpenShiftProviderName {
 		t.Errorf("expected %q, got %q", cloudprovider.OpenShiftProviderName, actual)
 	}
@@ -46,8 +46,12 @@ func TestProviderConstructorProperties(t *testing.T) {
 		t.Errorf("expected %+v, got %+v", resourceLimits, rl)
 	}
 
-	if _, err := provider.Pricing(); err != cloudprovider.ErrNotImplemented {
-		t.Errorf("expected an error")
+	pricingModel, err := provider.Pricing()
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if pricingModel == nil {
+		t.Errorf("expected a pricing model")
 	}
 
 	machineTypes, err := provider.GetAvailableMachineTypes()
@@ -120,7 +124,7 @@ func BenchmarkNodeGroups(b *testing.B) {
 		b.Fatalf("unexpected error: %v", err)
 	}
 
-	provider := newProvider(&resourceLimits, controller.machineController, nil)
+	provider := newProvider(&resourceLimits, controller.machineController, nil, nil)
 	if actual := provider.Name(); actual != cloudprovider.ClusterAPIProviderName {
 		b.Errorf("expected %q, got %q", cloudprovider.ClusterAPIProviderName, actual)
 	}
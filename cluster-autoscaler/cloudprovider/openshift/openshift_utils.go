@@ -23,6 +23,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"k8s.io/klog/v2"
 
@@ -48,6 +49,9 @@ const (
 	nodeGroupMinSizeAnnotationKey        = "machine.openshift.io/cluster-api-autoscaler-node-group-min-size"
 	nodeGroupMaxSizeAnnotationKey        = "machine.openshift.io/cluster-api-autoscaler-node-group-max-size"
 	nodeGroupAutoscalingOptionsKeyPrefix = "machine.openshift.io/autoscaling-options-"
+	// atomicScaleUpTimeoutAnnotationKey is the key used in MachineSet annotations
+	// to override how long an atomic scale-up waits for the new Machines.
+	atomicScaleUpTimeoutAnnotationKey = "machine.openshift.io/cluster-api-autoscaler-atomic-scale-up-timeout"
 	// machineDeleteAnnotationKey is the annotation used by cluster-api to indicate
 	// that a machine should be deleted.
 	machineDeleteAnnotationKey    = "machine.openshift.io/delete-machine"
@@ -183,6 +187,22 @@ func autoscalingOptions(annotations map[string]string) map[string]string {
 	return options
 }
 
+// atomicScaleUpTimeout returns the duration encoded in the annotation keyed
+// by atomicScaleUpTimeoutAnnotationKey, or defaultTimeout if the annotation
+// doesn't exist or its value is not a positive duration.
+func atomicScaleUpTimeout(annotations map[string]string, defaultTimeout time.Duration) time.Duration {
+	val, found := annotations[atomicScaleUpTimeoutAnnotationKey]
+	if !found {
+		return defaultTimeout
+	}
+	timeout, err := time.ParseDuration(val)
+	if err != nil || timeout <= 0 {
+		klog.Warningf("invalid value %q of annotation %s, using %v", val, atomicScaleUpTimeoutAnnotationKey, defaultTimeout)
+		return defaultTimeout
+	}
+	return timeout
+}
+
 // maxSize returns the maximum value encoded in the annotations keyed
 // by nodeGroupMaxSizeAnnotationKey. Returns errMissingMaxAnnotation
 // if the annotation doesn't exist or errInvalidMaxAnnotation if the
@@ -468,3 +488,44 @@ func filterClusterAPIAuthoritativeResources(mapiGroups []cloudprovider.NodeGroup
 
 	return capiAuthoritativeGroups
 }
+
+// isMachineProvisioned returns true if the instance of the machine has been
+// created by the infrastructure provider.
+func isMachineProvisioned(machine *unstructured.Unstructured) bool {
+	phase, _, _ := unstructured.NestedString(machine.UnstructuredContent(), "status", "phase")
+	if phase == machinePhaseProvisioned || phase == machinePhaseRunning {
+		return true
+	}
+
+	if _, found, _ := unstructured.NestedFieldNoCopy(machine.UnstructuredContent(), "status", "nodeRef"); found {
+		return true
+	}
+
+	providerID, _, _ := unstructured.NestedString(machine.UnstructuredContent(), "spec", "providerID")
+	return providerID != ""
+}
+
+// machineProvisioningErrorInfo returns the error info of a machine that
+// failed, or nil if it hasn't.
+func machineProvisioningErrorInfo(machine *unstructured.Unstructured) *cloudprovider.InstanceErrorInfo {
+	phase, _, _ := unstructured.NestedString(machine.UnstructuredContent(), "status", "phase")
+	if phase != machinePhaseFailed {
+		return nil
+	}
+
+	errorInfo := &cloudprovider.InstanceErrorInfo{
+		ErrorClass:   cloudprovider.OtherErrorClass,
+		ErrorCode:    "ProvisioningFailed",
+		ErrorMessage: fmt.Sprintf("Machine %s failed to provision", machine.GetName()),
+	}
+	if reason, found, _ := unstructured.NestedString(machine.UnstructuredContent(), "status", "errorReason"); found && reason != "" {
+		errorInfo.ErrorCode = reason
+		if reason == machineErrorReasonInsufficientResources {
+			errorInfo.ErrorClass = cloudprovider.OutOfResourcesErrorClass
+		}
+	}
+	if message, found, _ := unstructured.NestedString(machine.UnstructuredContent(), "status", "errorMessage"); found && message != "" {
+		errorInfo.ErrorMessage = fmt.Sprintf("Machine %s failed to provision: %s", machine.GetName(), message)
+	}
+	return errorInfo
+}
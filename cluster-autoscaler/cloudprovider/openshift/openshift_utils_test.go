@@ -22,6 +22,7 @@ import (
 	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -501,6 +502,36 @@ func TestParseMaxPodsCapacity(t *testing.T) {
 	}
 }
 
+func TestAtomicScaleUpTimeout(t *testing.T) {
+	defaultTimeout := 5 * time.Minute
+	for _, tc := range []struct {
+		description     string
+		annotations     map[string]string
+		expectedTimeout time.Duration
+	}{{
+		description:     "nil annotations",
+		expectedTimeout: defaultTimeout,
+	}, {
+		description:     "valid duration",
+		annotations:     map[string]string{atomicScaleUpTimeoutAnnotationKey: "15m"},
+		expectedTimeout: 15 * time.Minute,
+	}, {
+		description:     "bad duration",
+		annotations:     map[string]string{atomicScaleUpTimeoutAnnotationKey: "not-a-duration"},
+		expectedTimeout: defaultTimeout,
+	}, {
+		description:     "negative duration",
+		annotations:     map[string]string{atomicScaleUpTimeoutAnnotationKey: "-1m"},
+		expectedTimeout: defaultTimeout,
+	}} {
+		t.Run(tc.description, func(t *testing.T) {
+			if got := atomicScaleUpTimeout(tc.annotations, defaultTimeout); got != tc.expectedTimeout {
+				t.Errorf("expected %v, got %v", tc.expectedTimeout, got)
+			}
+		})
+	}
+}
+
 func TestClusterNameFromResource(t *testing.T) {
 	for _, tc := range []struct {
 		name     string
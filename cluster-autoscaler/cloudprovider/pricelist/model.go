@@ -0,0 +1,161 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricelist
+
+import (
+	"math"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/gpu"
+	podutils "k8s.io/autoscaler/cluster-autoscaler/utils/pod"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+)
+
+// Model implements cloudprovider.PricingModel with the price list of a
+// Source. Nodes are priced by their instance type if it is listed, and by
+// their resources otherwise. Instance types and zones are read from the
+// well-known node labels, which are also set on template nodes.
+type Model struct {
+	source Source
+}
+
+// Rates are prices of resources per hour.
+type Rates struct {
+	CPU       float64
+	MemoryGiB float64
+	GPU       float64
+}
+
+var _ cloudprovider.PricingModel = (*Model)(nil)
+
+// NewModel returns a Model reading prices from the source. A nil source
+// prices everything at the default prices.
+func NewModel(source Source) *Model {
+	return &Model{source: source}
+}
+
+// NodePrice returns a price of running the given node for a given period of time.
+func (m *Model) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	priceList, err := m.priceList()
+	if err != nil {
+		return 0, err
+	}
+
+	zone := getZone(node.Labels)
+	price, found := 0.0, false
+	if instanceType, ok := getInstanceType(node.Labels); ok {
+		price, found = priceList.instanceTypePrice(instanceType, zone)
+	}
+	if !found {
+		price = priceList.resourceRates(zone).Price(node.Status.Capacity)
+	}
+	price *= priceList.preemptibleMultiplier(node.Labels)
+	return price * getHours(startTime, endTime), nil
+}
+
+// PodPrice returns a theoretical minimum price of running a pod for a given
+// period of time on a perfectly matching machine, at the base resource prices.
+func (m *Model) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	priceList, err := m.priceList()
+	if err != nil {
+		return 0, err
+	}
+	return priceList.resourceRates("").Price(podutils.PodRequests(pod)) * getHours(startTime, endTime), nil
+}
+
+// ResourceRates returns the resource prices in the zone of a node with the
+// given labels, for providers that override some of them.
+func (m *Model) ResourceRates(labels map[string]string) (Rates, error) {
+	priceList, err := m.priceList()
+	if err != nil {
+		return Rates{}, err
+	}
+	return priceList.resourceRates(getZone(labels)), nil
+}
+
+// PreemptibleMultiplier returns the multiplier of the price of a node with
+// the given labels, 1 if it isn't preemptible, for providers that price
+// nodes themselves.
+func (m *Model) PreemptibleMultiplier(labels map[string]string) (float64, error) {
+	priceList, err := m.priceList()
+	if err != nil {
+		return 0, err
+	}
+	return priceList.preemptibleMultiplier(labels), nil
+}
+
+func (m *Model) priceList() (*PriceList, error) {
+	if m.source == nil {
+		return &PriceList{}, nil
+	}
+	return m.source.PriceList()
+}
+
+// WithFallback returns a cloud provider using the model for pricing when the
+// given provider doesn't implement it. Everything else is delegated.
+func WithFallback(provider cloudprovider.CloudProvider, model cloudprovider.PricingModel) cloudprovider.CloudProvider {
+	return &fallbackCloudProvider{CloudProvider: provider, model: model}
+}
+
+type fallbackCloudProvider struct {
+	cloudprovider.CloudProvider
+	model cloudprovider.PricingModel
+}
+
+// Pricing returns the pricing model of the wrapped provider, or the
+// fallback model if it doesn't have one.
+func (p *fallbackCloudProvider) Pricing() (cloudprovider.PricingModel, errors.AutoscalerError) {
+	model, err := p.CloudProvider.Pricing()
+	if err == cloudprovider.ErrNotImplemented {
+		return p.model, nil
+	}
+	return model, err
+}
+
+// Price returns the price per hour of the resources at the rates.
+func (r Rates) Price(resources apiv1.ResourceList) float64 {
+	cpu := resources[apiv1.ResourceCPU]
+	mem := resources[apiv1.ResourceMemory]
+	gpus := resources[gpu.ResourceNvidiaGPU]
+	return float64(cpu.MilliValue())/1000.0*r.CPU +
+		float64(mem.Value())/float64(units.GiB)*r.MemoryGiB +
+		float64(gpus.MilliValue())/1000.0*r.GPU
+}
+
+func getInstanceType(labels map[string]string) (string, bool) {
+	if instanceType, found := labels[apiv1.LabelInstanceTypeStable]; found {
+		return instanceType, true
+	}
+	instanceType, found := labels[apiv1.LabelInstanceType]
+	return instanceType, found
+}
+
+func getZone(labels map[string]string) string {
+	if zone, found := labels[apiv1.LabelTopologyZone]; found {
+		return zone
+	}
+	return labels[apiv1.LabelFailureDomainBetaZone]
+}
+
+func getHours(startTime time.Time, endTime time.Time) float64 {
+	minutes := math.Ceil(float64(endTime.Sub(startTime)) / float64(time.Minute))
+	return minutes / 60.0
+}
@@ -0,0 +1,280 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricelist
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
+)
+
+const testPriceList = `
+instanceTypes:
+  m5.large: 0.1
+resources:
+  cpu: 0.04
+  memoryGiB: 0.005
+preemptible:
+  multiplier: 0.25
+zones:
+  zone-b:
+    multiplier: 2
+    instanceTypes:
+      m5.xlarge: 0.3
+    resources:
+      cpu: 0.05
+`
+
+type staticSource struct {
+	priceList *PriceList
+	err       error
+}
+
+func (s *staticSource) PriceList() (*PriceList, error) {
+	return s.priceList, s.err
+}
+
+func testModel(t *testing.T) *Model {
+	priceList, err := Parse([]byte(testPriceList))
+	assert.NoError(t, err)
+	return NewModel(&staticSource{priceList: priceList})
+}
+
+func testNode(labels map[string]string, cpu int64, memoryGiB int64) *apiv1.Node {
+	node := BuildTestNode("node", cpu*1000, memoryGiB*units.GiB)
+	node.Labels = labels
+	return node
+}
+
+func TestNodePrice(t *testing.T) {
+	model := testModel(t)
+	startTime := time.Now()
+	endTime := startTime.Add(time.Hour)
+
+	gpuNode := testNode(map[string]string{}, 4, 16)
+	AddGpusToNode(gpuNode, 2)
+
+	for _, tc := range []struct {
+		name  string
+		node  *apiv1.Node
+		price float64
+	}{
+		{
+			name:  "listed instance type",
+			node:  testNode(map[string]string{apiv1.LabelInstanceTypeStable: "m5.large"}, 2, 8),
+			price: 0.1,
+		},
+		{
+			name:  "deprecated instance type label",
+			node:  testNode(map[string]string{apiv1.LabelInstanceType: "m5.large"}, 2, 8),
+			price: 0.1,
+		},
+		{
+			name:  "unlisted instance type uses resources",
+			node:  testNode(map[string]string{apiv1.LabelInstanceTypeStable: "c5.large"}, 2, 4),
+			price: 2*0.04 + 4*0.005,
+		},
+		{
+			name:  "default gpu price",
+			node:  gpuNode,
+			price: 4*0.04 + 16*0.005 + 2*defaultGPUPrice,
+		},
+		{
+			name:  "zone multiplier on listed instance type",
+			node:  testNode(map[string]string{apiv1.LabelInstanceTypeStable: "m5.large", apiv1.LabelTopologyZone: "zone-b"}, 2, 8),
+			price: 0.2,
+		},
+		{
+			name:  "zone instance type override",
+			node:  testNode(map[string]string{apiv1.LabelInstanceTypeStable: "m5.xlarge", apiv1.LabelFailureDomainBetaZone: "zone-b"}, 4, 16),
+			price: 0.3,
+		},
+		{
+			name:  "zone resource override and multiplier",
+			node:  testNode(map[string]string{apiv1.LabelTopologyZone: "zone-b"}, 2, 4),
+			price: 2*0.05 + 4*0.005*2,
+		},
+		{
+			name:  "unknown zone",
+			node:  testNode(map[string]string{apiv1.LabelInstanceTypeStable: "m5.large", apiv1.LabelTopologyZone: "zone-c"}, 2, 8),
+			price: 0.1,
+		},
+		{
+			name:  "preemptible",
+			node:  testNode(map[string]string{apiv1.LabelInstanceTypeStable: "m5.large", "karpenter.sh/capacity-type": "spot"}, 2, 8),
+			price: 0.1 * 0.25,
+		},
+		{
+			name:  "not preemptible label value",
+			node:  testNode(map[string]string{apiv1.LabelInstanceTypeStable: "m5.large", "karpenter.sh/capacity-type": "on-demand"}, 2, 8),
+			price: 0.1,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			price, err := model.NodePrice(tc.node, startTime, endTime)
+			assert.NoError(t, err)
+			assert.InDelta(t, tc.price, price, 1e-9)
+		})
+	}
+}
+
+func TestNodePricePeriod(t *testing.T) {
+	model := testModel(t)
+	node := testNode(map[string]string{apiv1.LabelInstanceTypeStable: "m5.large"}, 2, 8)
+	startTime := time.Now()
+
+	price, err := model.NodePrice(node, startTime, startTime.Add(90*time.Minute))
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.15, price, 1e-9)
+}
+
+func TestPreemptibleLabels(t *testing.T) {
+	priceList, err := Parse([]byte("preemptible:\n  multiplier: 0.5\n  labels: [spot]\n"))
+	assert.NoError(t, err)
+	model := NewModel(&staticSource{priceList: priceList})
+	startTime := time.Now()
+	endTime := startTime.Add(time.Hour)
+
+	regular, err := model.NodePrice(testNode(map[string]string{"cloud.google.com/gke-spot": "true"}, 1, 1), startTime, endTime)
+	assert.NoError(t, err)
+	spot, err := model.NodePrice(testNode(map[string]string{"spot": "yes"}, 1, 1), startTime, endTime)
+	assert.NoError(t, err)
+	assert.InDelta(t, regular*0.5, spot, 1e-9)
+}
+
+func TestPodPrice(t *testing.T) {
+	model := testModel(t)
+	startTime := time.Now()
+
+	pod := BuildTestPod("pod", 1500, 2*units.GiB)
+	price, err := model.PodPrice(pod, startTime, startTime.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.5*0.04+2*0.005, price, 1e-9)
+}
+
+func TestModelSourceError(t *testing.T) {
+	model := NewModel(&staticSource{err: fmt.Errorf("no price list")})
+	startTime := time.Now()
+
+	_, err := model.NodePrice(testNode(nil, 1, 1), startTime, startTime.Add(time.Hour))
+	assert.Error(t, err)
+	_, err = model.PodPrice(BuildTestPod("pod", 100, 100), startTime, startTime.Add(time.Hour))
+	assert.Error(t, err)
+}
+
+func TestModelWithoutSource(t *testing.T) {
+	model := NewModel(nil)
+	startTime := time.Now()
+
+	price, err := model.NodePrice(testNode(nil, 2, 4), startTime, startTime.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.InDelta(t, 2*defaultCPUPrice+4*defaultMemoryGiBPrice, price, 1e-9)
+}
+
+func TestDefaultPreemptibleMultiplier(t *testing.T) {
+	priceList, err := Parse([]byte("instanceTypes:\n  m5.large: 0.1\n"))
+	assert.NoError(t, err)
+	model := NewModel(&staticSource{priceList: priceList})
+	startTime := time.Now()
+	endTime := startTime.Add(time.Hour)
+
+	for _, labels := range []map[string]string{
+		{apiv1.LabelInstanceTypeStable: "m5.large", "eks.amazonaws.com/capacityType": "SPOT"},
+		{"cluster.x-k8s.io/interruptible": ""},
+	} {
+		regularLabels := map[string]string{}
+		for key, value := range labels {
+			if key == apiv1.LabelInstanceTypeStable {
+				regularLabels[key] = value
+			}
+		}
+		regular, err := model.NodePrice(testNode(regularLabels, 2, 4), startTime, endTime)
+		assert.NoError(t, err)
+		spot, err := model.NodePrice(testNode(labels, 2, 4), startTime, endTime)
+		assert.NoError(t, err)
+		assert.InDelta(t, regular*defaultPreemptibleMultiplier, spot, 1e-9)
+		assert.Less(t, spot, regular/4)
+	}
+}
+
+func TestResourceRates(t *testing.T) {
+	model := testModel(t)
+
+	rates, err := model.ResourceRates(map[string]string{apiv1.LabelTopologyZone: "zone-b"})
+	assert.NoError(t, err)
+	assert.InDelta(t, 0.05, rates.CPU, 1e-9)
+	assert.InDelta(t, 0.01, rates.MemoryGiB, 1e-9)
+	assert.InDelta(t, 2*defaultGPUPrice, rates.GPU, 1e-9)
+
+	rates, err = model.ResourceRates(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, Rates{CPU: 0.04, MemoryGiB: 0.005, GPU: defaultGPUPrice}, rates)
+}
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{name: "valid", data: testPriceList},
+		{name: "empty", data: ""},
+		{name: "json", data: `{"instanceTypes": {"n1-standard-1": 0.0475}}`},
+		{name: "unknown field", data: "instanceType:\n  a: 1\n", wantErr: true},
+		{name: "negative instance type price", data: "instanceTypes:\n  a: -1\n", wantErr: true},
+		{name: "negative resource price", data: "resources:\n  cpu: -1\n", wantErr: true},
+		{name: "negative preemptible multiplier", data: "preemptible:\n  multiplier: -1\n", wantErr: true},
+		{name: "invalid preemptible label", data: "preemptible:\n  labels: [=true]\n", wantErr: true},
+		{name: "negative zone multiplier", data: "zones:\n  a:\n    multiplier: -1\n", wantErr: true},
+		{name: "negative zone price", data: "zones:\n  a:\n    resources:\n      gpu: -1\n", wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Parse([]byte(tc.data))
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWithFallback(t *testing.T) {
+	model := testModel(t)
+
+	withoutPricing := testprovider.NewTestCloudProviderBuilder().Build()
+	provider := WithFallback(withoutPricing, model)
+	pricing, err := provider.Pricing()
+	assert.NoError(t, err)
+	assert.Equal(t, model, pricing)
+
+	ownModel := NewModel(&staticSource{})
+	withPricing := testprovider.NewTestCloudProviderBuilder().Build()
+	withPricing.SetPricingModel(ownModel)
+	provider = WithFallback(withPricing, model)
+	pricing, err = provider.Pricing()
+	assert.NoError(t, err)
+	assert.Equal(t, cloudprovider.PricingModel(ownModel), pricing)
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pricelist implements a cloudprovider.PricingModel backed by a
+// user supplied price list, for cloud providers that don't implement
+// pricing themselves.
+package pricelist
+
+import (
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Default prices, the same as the base prices of the GCE price model.
+const (
+	defaultCPUPrice       = 0.033174
+	defaultMemoryGiBPrice = 0.004446
+	defaultGPUPrice       = 0.700
+
+	// defaultPreemptibleMultiplier is the default preemptible discount of
+	// the GCE price model.
+	defaultPreemptibleMultiplier = 0.00698 / 0.033174
+)
+
+// defaultPreemptibleLabels are the node labels marking spot or preemptible
+// instances on the major clouds, Cluster API and the OpenShift Machine API.
+// A label without value matches any value.
+var defaultPreemptibleLabels = []string{
+	"cloud.google.com/gke-preemptible",
+	"cloud.google.com/gke-spot",
+	"eks.amazonaws.com/capacityType=SPOT",
+	"karpenter.sh/capacity-type=spot",
+	"kubernetes.azure.com/scalesetpriority=spot",
+	"cluster.x-k8s.io/interruptible",
+	"machine.openshift.io/interruptible-instance",
+}
+
+// PriceList holds the hourly prices of instance types and resources.
+type PriceList struct {
+	// InstanceTypes maps instance types to their price per hour.
+	InstanceTypes map[string]float64 `json:"instanceTypes,omitempty"`
+	// Resources are the prices of nodes whose instance type isn't listed,
+	// and of pods.
+	Resources ResourcePrices `json:"resources,omitempty"`
+	// Preemptible configures the discount of spot and preemptible nodes.
+	Preemptible Preemptible `json:"preemptible,omitempty"`
+	// Zones overrides prices per zone.
+	Zones map[string]ZonePrices `json:"zones,omitempty"`
+}
+
+// ResourcePrices holds the prices per hour of resources. Unset prices fall
+// back to defaults.
+type ResourcePrices struct {
+	CPU       *float64 `json:"cpu,omitempty"`
+	MemoryGiB *float64 `json:"memoryGiB,omitempty"`
+	GPU       *float64 `json:"gpu,omitempty"`
+}
+
+// Preemptible configures the price of spot and preemptible nodes.
+type Preemptible struct {
+	// Multiplier is applied to the price of preemptible nodes. Defaults to
+	// the preemptible discount of the GCE price model, about 0.21.
+	Multiplier *float64 `json:"multiplier,omitempty"`
+	// Labels identify preemptible nodes, as key or key=value. Defaults to
+	// the labels used by the major clouds.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// ZonePrices overrides the prices of a zone.
+type ZonePrices struct {
+	// Multiplier is applied to the prices not overridden for the zone.
+	Multiplier *float64 `json:"multiplier,omitempty"`
+	// InstanceTypes overrides the price of instance types in the zone.
+	InstanceTypes map[string]float64 `json:"instanceTypes,omitempty"`
+	// Resources overrides the resource prices in the zone.
+	Resources ResourcePrices `json:"resources,omitempty"`
+}
+
+// Parse parses a YAML or JSON price list.
+func Parse(data []byte) (*PriceList, error) {
+	priceList := &PriceList{}
+	if err := yaml.UnmarshalStrict(data, priceList); err != nil {
+		return nil, err
+	}
+	if err := priceList.validate(); err != nil {
+		return nil, err
+	}
+	return priceList, nil
+}
+
+func (l *PriceList) validate() error {
+	for instanceType, price := range l.InstanceTypes {
+		if price < 0 {
+			return fmt.Errorf("negative price %v of instance type %s", price, instanceType)
+		}
+	}
+	if err := l.Resources.validate(); err != nil {
+		return err
+	}
+	if err := validateMultiplier(l.Preemptible.Multiplier); err != nil {
+		return fmt.Errorf("preemptible: %v", err)
+	}
+	for _, label := range l.Preemptible.Labels {
+		if key, _, _ := strings.Cut(label, "="); key == "" {
+			return fmt.Errorf("invalid preemptible label %q", label)
+		}
+	}
+	for zone, prices := range l.Zones {
+		if err := validateMultiplier(prices.Multiplier); err != nil {
+			return fmt.Errorf("zone %s: %v", zone, err)
+		}
+		for instanceType, price := range prices.InstanceTypes {
+			if price < 0 {
+				return fmt.Errorf("zone %s: negative price %v of instance type %s", zone, price, instanceType)
+			}
+		}
+		if err := prices.Resources.validate(); err != nil {
+			return fmt.Errorf("zone %s: %v", zone, err)
+		}
+	}
+	return nil
+}
+
+func (p ResourcePrices) validate() error {
+	for name, price := range map[string]*float64{"cpu": p.CPU, "memoryGiB": p.MemoryGiB, "gpu": p.GPU} {
+		if price != nil && *price < 0 {
+			return fmt.Errorf("negative %s price %v", name, *price)
+		}
+	}
+	return nil
+}
+
+func validateMultiplier(multiplier *float64) error {
+	if multiplier != nil && *multiplier < 0 {
+		return fmt.Errorf("negative multiplier %v", *multiplier)
+	}
+	return nil
+}
+
+// instanceTypePrice returns the price of the instance type in the zone.
+func (l *PriceList) instanceTypePrice(instanceType, zone string) (float64, bool) {
+	zonePrices := l.Zones[zone]
+	if price, found := zonePrices.InstanceTypes[instanceType]; found {
+		return price, true
+	}
+	if price, found := l.InstanceTypes[instanceType]; found {
+		return price * multiplier(zonePrices.Multiplier), true
+	}
+	return 0, false
+}
+
+// resourceRates returns the resource prices in the zone, with defaults for
+// the unset ones. An empty zone returns the base prices.
+func (l *PriceList) resourceRates(zone string) Rates {
+	zonePrices := l.Zones[zone]
+	m := multiplier(zonePrices.Multiplier)
+	price := func(zoneOverride, base *float64, defaultPrice float64) float64 {
+		if zoneOverride != nil {
+			return *zoneOverride
+		}
+		if base != nil {
+			return *base * m
+		}
+		return defaultPrice * m
+	}
+	return Rates{
+		CPU:       price(zonePrices.Resources.CPU, l.Resources.CPU, defaultCPUPrice),
+		MemoryGiB: price(zonePrices.Resources.MemoryGiB, l.Resources.MemoryGiB, defaultMemoryGiBPrice),
+		GPU:       price(zonePrices.Resources.GPU, l.Resources.GPU, defaultGPUPrice),
+	}
+}
+
+// preemptibleMultiplier returns the multiplier of the price of a node with
+// the given labels, 1 if it isn't preemptible.
+func (l *PriceList) preemptibleMultiplier(labels map[string]string) float64 {
+	if !l.isPreemptible(labels) {
+		return 1
+	}
+	if l.Preemptible.Multiplier == nil {
+		return defaultPreemptibleMultiplier
+	}
+	return *l.Preemptible.Multiplier
+}
+
+// isPreemptible returns true if the labels match one of the preemptible
+// labels of the price list.
+func (l *PriceList) isPreemptible(labels map[string]string) bool {
+	preemptibleLabels := l.Preemptible.Labels
+	if len(preemptibleLabels) == 0 {
+		preemptibleLabels = defaultPreemptibleLabels
+	}
+	for _, label := range preemptibleLabels {
+		key, value, hasValue := strings.Cut(label, "=")
+		if actual, found := labels[key]; found && (!hasValue || actual == value) {
+			return true
+		}
+	}
+	return false
+}
+
+func multiplier(m *float64) float64 {
+	if m == nil {
+		return 1
+	}
+	return *m
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricelist
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	v1lister "k8s.io/client-go/listers/core/v1"
+	klog "k8s.io/klog/v2"
+)
+
+// ConfigMapKey is the key of the price list in its ConfigMap.
+const ConfigMapKey = "prices.yaml"
+
+// Source provides the current price list. Sources reload the price list when
+// it changes, and keep the last valid one if the new one is invalid.
+type Source interface {
+	PriceList() (*PriceList, error)
+}
+
+type fileSource struct {
+	path      string
+	mutex     sync.Mutex
+	modTime   time.Time
+	size      int64
+	priceList *PriceList
+	err       error
+}
+
+// NewFileSource returns a Source reading the price list from a file. The
+// file is read again when its modification time or size changes.
+func NewFileSource(path string) Source {
+	return &fileSource{path: path}
+}
+
+func (s *fileSource) PriceList() (*PriceList, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	info, err := os.Stat(s.path)
+	if err != nil {
+		klog.V(4).Infof("Failed to read price list %s: %v", s.path, err)
+		return s.priceList, s.errIfNone(fmt.Errorf("failed to read price list %s: %v", s.path, err))
+	}
+	if !info.ModTime().Equal(s.modTime) || info.Size() != s.size {
+		s.modTime, s.size = info.ModTime(), info.Size()
+		s.err = s.load()
+	}
+	return s.priceList, s.errIfNone(s.err)
+}
+
+func (s *fileSource) load() error {
+	data, err := os.ReadFile(s.path)
+	if err == nil {
+		var priceList *PriceList
+		if priceList, err = Parse(data); err == nil {
+			klog.V(1).Infof("Loaded price list from %s", s.path)
+			s.priceList = priceList
+			return nil
+		}
+	}
+	err = fmt.Errorf("invalid price list %s: %v", s.path, err)
+	klog.Warningf("%v, using the last valid price list", err)
+	return err
+}
+
+func (s *fileSource) errIfNone(err error) error {
+	if s.priceList != nil {
+		return nil
+	}
+	return err
+}
+
+type configMapSource struct {
+	lister          v1lister.ConfigMapNamespaceLister
+	name            string
+	mutex           sync.Mutex
+	resourceVersion string
+	priceList       *PriceList
+	err             error
+}
+
+// NewConfigMapSource returns a Source reading the price list from the
+// ConfigMapKey of a ConfigMap. It is parsed again when the ConfigMap changes.
+func NewConfigMapSource(lister v1lister.ConfigMapNamespaceLister, name string) Source {
+	return &configMapSource{lister: lister, name: name}
+}
+
+func (s *configMapSource) PriceList() (*PriceList, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	configMap, err := s.lister.Get(s.name)
+	if err != nil {
+		klog.V(4).Infof("Failed to get price list ConfigMap %s: %v", s.name, err)
+		return s.priceList, s.errIfNone(fmt.Errorf("failed to get price list ConfigMap %s: %v", s.name, err))
+	}
+	if configMap.ResourceVersion != s.resourceVersion {
+		s.resourceVersion = configMap.ResourceVersion
+		s.err = s.load(configMap.Data)
+	}
+	return s.priceList, s.errIfNone(s.err)
+}
+
+func (s *configMapSource) load(data map[string]string) error {
+	var err error
+	if value, found := data[ConfigMapKey]; !found {
+		err = fmt.Errorf("price list ConfigMap %s has no %s key", s.name, ConfigMapKey)
+	} else if priceList, parseErr := Parse([]byte(value)); parseErr != nil {
+		err = fmt.Errorf("invalid price list ConfigMap %s: %v", s.name, parseErr)
+	} else {
+		klog.V(1).Infof("Loaded price list from ConfigMap %s", s.name)
+		s.priceList = priceList
+		return nil
+	}
+	klog.Warningf("%v, using the last valid price list", err)
+	return err
+}
+
+func (s *configMapSource) errIfNone(err error) error {
+	if s.priceList != nil {
+		return nil
+	}
+	return err
+}
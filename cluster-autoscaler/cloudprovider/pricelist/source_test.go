@@ -0,0 +1,107 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pricelist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prices.yaml")
+	source := NewFileSource(path)
+
+	_, err := source.PriceList()
+	assert.Error(t, err, "missing file")
+
+	writeFile := func(data string, modTime time.Time) {
+		require.NoError(t, os.WriteFile(path, []byte(data), 0644))
+		require.NoError(t, os.Chtimes(path, modTime, modTime))
+	}
+	modTime := time.Now().Add(-time.Hour)
+
+	writeFile("instanceTypes: {a: -1}\n", modTime)
+	_, err = source.PriceList()
+	assert.Error(t, err, "no valid price list yet")
+
+	modTime = modTime.Add(time.Minute)
+	writeFile("instanceTypes: {a: 1}\n", modTime)
+	priceList, err := source.PriceList()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"a": 1}, priceList.InstanceTypes)
+
+	modTime = modTime.Add(time.Minute)
+	writeFile("instanceTypes: {a: 2}\n", modTime)
+	priceList, err = source.PriceList()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"a": 2}, priceList.InstanceTypes, "reloaded on change")
+
+	modTime = modTime.Add(time.Minute)
+	writeFile("instanceTypes: [\n", modTime)
+	priceList, err = source.PriceList()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"a": 2}, priceList.InstanceTypes, "last valid price list kept")
+
+	require.NoError(t, os.Remove(path))
+	priceList, err = source.PriceList()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"a": 2}, priceList.InstanceTypes, "last valid price list kept")
+}
+
+func TestConfigMapSource(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	lister := v1lister.NewConfigMapLister(indexer).ConfigMaps("kube-system")
+	source := NewConfigMapSource(lister, "prices")
+
+	_, err := source.PriceList()
+	assert.Error(t, err, "missing ConfigMap")
+
+	setConfigMap := func(resourceVersion string, data map[string]string) {
+		require.NoError(t, indexer.Add(&apiv1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "prices", Namespace: "kube-system", ResourceVersion: resourceVersion},
+			Data:       data,
+		}))
+	}
+
+	setConfigMap("1", map[string]string{"other": ""})
+	_, err = source.PriceList()
+	assert.Error(t, err, "missing key")
+
+	setConfigMap("2", map[string]string{ConfigMapKey: "resources: {cpu: 0.5}\n"})
+	priceList, err := source.PriceList()
+	require.NoError(t, err)
+	assert.Equal(t, 0.5, *priceList.Resources.CPU)
+
+	setConfigMap("3", map[string]string{ConfigMapKey: "resources: {cpu: 0.25}\n"})
+	priceList, err = source.PriceList()
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, *priceList.Resources.CPU, "reloaded on change")
+
+	setConfigMap("4", map[string]string{ConfigMapKey: "resources: {cpu: -1}\n"})
+	priceList, err = source.PriceList()
+	require.NoError(t, err)
+	assert.Equal(t, 0.25, *priceList.Resources.CPU, "last valid price list kept")
+}
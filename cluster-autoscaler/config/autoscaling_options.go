@@ -130,8 +130,23 @@ type AutoscalingOptions struct {
 	GRPCExpanderCert string
 	// GRPCExpanderURL is the url of the gRPC server when using the gRPC expander
 	GRPCExpanderURL string
+	// PriceListFile is the path to a price list used by cloud providers that don't implement pricing
+	PriceListFile string
+	// PriceListConfigMap is the name of a ConfigMap in ConfigNamespace holding a price list used by cloud providers that don't implement pricing
+	PriceListConfigMap string
 	// IgnoreMirrorPodsUtilization is whether CA will ignore Mirror pods when calculating resource utilization for scaling down
 	IgnoreMirrorPodsUtilization bool
+	// ScaleDownUtilizationMode defines how the actual usage of pods is combined with their requests when calculating
+	// cpu and memory utilization for scaling down: "requests", "usage" or "max"
+	ScaleDownUtilizationMode string
+	// ScaleDownUsageSource is where the usage of pods is read from: "metrics-api" or "prometheus"
+	ScaleDownUsageSource string
+	// ScaleDownUsagePrometheusURL is the URL of the Prometheus server used when ScaleDownUsageSource is "prometheus"
+	ScaleDownUsagePrometheusURL string
+	// ScaleDownUsagePercentile is the percentile of the usage of pods over ScaleDownUsageWindow used as their usage
+	ScaleDownUsagePercentile float64
+	// ScaleDownUsageWindow is the period over which the usage percentile of pods is computed
+	ScaleDownUsageWindow time.Duration
 	// MaxGracefulTerminationSec is maximum number of seconds scale down waits for pods to terminate before
 	// removing the node from cloud provider.
 	// DrainPriorityConfig takes higher precedence and MaxGracefulTerminationSec will not be applicable when the DrainPriorityConfig is set.
@@ -189,6 +204,13 @@ type AutoscalingOptions struct {
 	// ScaleDownSimulationTimeout defines the maximum time that can be
 	// spent on scale down simulation.
 	ScaleDownSimulationTimeout time.Duration
+	// ScaleDownConsolidationEnabled is used to allow CA to replace several
+	// underutilized nodes, whose pods don't fit elsewhere, with a single new
+	// node when that is cheaper or reduces the number of nodes.
+	ScaleDownConsolidationEnabled bool
+	// ScaleDownConsolidationMaxNodes is the maximum number of nodes replaced
+	// by a single new node during consolidation.
+	ScaleDownConsolidationMaxNodes int
 	// SchedulerConfig allows changing configuration of in-tree
 	// scheduler plugins acting on PreFilter and Filter extension points
 	SchedulerConfig *scheduler_config.KubeSchedulerConfiguration
@@ -200,6 +222,9 @@ type AutoscalingOptions struct {
 	StatusConfigMapName string
 	// BalanceSimilarNodeGroups enables logic that identifies node groups with similar machines and tries to balance node count between them.
 	BalanceSimilarNodeGroups bool
+	// ZoneSpreadScaleUpEnabled enables splitting the scale-up of pods spreading across zones between similar node
+	// groups in different zones, so that all of them can be accommodated in a single loop.
+	ZoneSpreadScaleUpEnabled bool
 	// ConfigNamespace is the namespace cluster-autoscaler is running in and all related configmaps live in
 	ConfigNamespace string
 	// ClusterName if available
@@ -279,6 +304,9 @@ type AutoscalingOptions struct {
 	// MaxBinpackingTime is the maximum time spend on binpacking for a single scale-up.
 	// If binpacking is limited by this, scale-up will continue with the already calculated scale-up options.
 	MaxBinpackingTime time.Duration
+	// ScaleUpEstimationParallelism is the number of node groups for which scale-up options are estimated concurrently,
+	// each on its own independent fork of the cluster snapshot. 1 means that node groups are estimated one after another.
+	ScaleUpEstimationParallelism int
 	// FastpathBinpackingEnabled tells if to use fastpath binpacking algorithm to optimize scale-ups.
 	FastpathBinpackingEnabled bool
 	// NodeDeletionBatcherInterval is a time for how long CA ScaleDown gather nodes to delete them in batch.
@@ -331,6 +359,12 @@ type AutoscalingOptions struct {
 	CSINodeAwareSchedulingEnabled bool
 	// PredicateParallelism is the number of goroutines to use for running scheduler predicates.
 	PredicateParallelism int
+	// IncrementalClusterSnapshotEnabled configures whether the cluster snapshot is updated incrementally, based on
+	// informer events, instead of being rebuilt from scratch in every loop.
+	IncrementalClusterSnapshotEnabled bool
+	// IncrementalSnapshotVerificationEnabled configures whether the incrementally updated cluster snapshot is
+	// compared with a full rebuild in every loop. The snapshot is rebuilt from scratch if they differ.
+	IncrementalSnapshotVerificationEnabled bool
 	// CheckCapacityProcessorInstance is the name of the processor instance.
 	// Only ProvisioningRequests that define this name in their parameters with the key "processorInstance" will be processed by this CA instance.
 	// It only refers to check capacity ProvisioningRequests, but if not empty, best-effort atomic ProvisioningRequests processing is disabled in this instance.
@@ -30,6 +30,8 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
 	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization/usage"
 	scheduler_util "k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/units"
 
@@ -149,18 +151,35 @@ var (
 	grpcExpanderCert = flag.String("grpc-expander-cert", "", "Path to cert used by gRPC server over TLS")
 	grpcExpanderURL  = flag.String("grpc-expander-url", "", "URL to reach gRPC expander server.")
 
+	priceListFile      = flag.String("price-list-file", "", "Path to a price list used for pricing by cloud providers that don't implement it. The file is reloaded when it changes.")
+	priceListConfigMap = flag.String("price-list-configmap", "", "Name of a ConfigMap in the --namespace holding a price list under the prices.yaml key, used for pricing by cloud providers that don't implement it. Ignored if --price-list-file is set.")
+
 	ignoreDaemonSetsUtilization = flag.Bool("ignore-daemonsets-utilization", false,
 		"Should CA ignore DaemonSet pods when calculating resource utilization for scaling down")
 	ignoreMirrorPodsUtilization = flag.Bool("ignore-mirror-pods-utilization", false,
 		"Should CA ignore Mirror pods when calculating resource utilization for scaling down")
+	scaleDownUtilizationMode = flag.String("scale-down-utilization-mode", string(utilization.ModeRequests),
+		"How the actual usage of pods is combined with their requests when calculating cpu and memory utilization for scaling down: "+
+			"'requests' ignores usage, 'usage' uses the usage of pods instead of their requests, 'max' uses the maximum of both for each pod. "+
+			"Pods with unknown usage are accounted for with their requests. Scale down simulation always uses requests.")
+	scaleDownUsageSource = flag.String("scale-down-usage-source", usage.SourceMetricsAPI,
+		"Where the usage of pods is read from when --scale-down-utilization-mode isn't 'requests': 'metrics-api' (metrics.k8s.io) or 'prometheus'")
+	scaleDownUsagePrometheusURL = flag.String("scale-down-usage-prometheus-url", "",
+		"URL of the Prometheus server used when --scale-down-usage-source is 'prometheus'")
+	scaleDownUsagePercentile = flag.Float64("scale-down-usage-percentile", 0.95,
+		"Percentile of the usage of pods over --scale-down-usage-window used as their usage")
+	scaleDownUsageWindow = flag.Duration("scale-down-usage-window", time.Hour,
+		"Period over which the usage percentile of pods is computed")
 
 	writeStatusConfigMapFlag     = flag.Bool("write-status-configmap", true, "Should CA write status information to a configmap")
 	statusConfigMapName          = flag.String("status-config-map-name", "cluster-autoscaler-status", "Status configmap name")
 	maxInactivityTimeFlag        = flag.Duration("max-inactivity", 10*time.Minute, "Maximum time from last recorded autoscaler activity before automatic restart")
 	maxBinpackingTimeFlag        = flag.Duration("max-binpacking-time", 5*time.Minute, "Maximum time spend on binpacking for a single scale-up. If binpacking is limited by this, scale-up will continue with the already calculated scale-up options.")
+	scaleUpEstimationParallelism = flag.Int("scale-up-estimation-parallelism", 1, "Number of node groups for which scale-up options are estimated concurrently. Values above 1 require the cluster snapshot to support independent forks and are ignored with dynamic resource allocation.")
 	maxFailingTimeFlag           = flag.Duration("max-failing-time", 15*time.Minute, "Maximum time from last recorded successful autoscaler run before automatic restart")
 	maxStartupTimeFlag           = flag.Duration("max-startup-time", 20*time.Minute, "Maximum time until first recorded successful autoscaler run before automatic restart")
 	balanceSimilarNodeGroupsFlag = flag.Bool("balance-similar-node-groups", false, "Detect similar node groups and balance the number of nodes between them")
+	zoneSpreadScaleUpFlag        = flag.Bool("zone-spread-scale-up", false, "Split the scale-up of pods with zonal topology spread constraints between similar node groups in different zones, so that all of them can be scheduled after a single scale-up. Requires --balance-similar-node-groups.")
 
 	unremovableNodeRecheckTimeout = flag.Duration("unremovable-node-recheck-timeout", 5*time.Minute, "The timeout before we check again a node that couldn't be removed before")
 	expendablePodsPriorityCutoff  = flag.Int("expendable-pods-priority-cutoff", -10, "Pods with priority below cutoff will be expendable. They can be killed without any consideration during scale down and they don't cause scale up. Pods with null priority (PodPriority disabled) are non expendable.")
@@ -208,6 +227,8 @@ var (
 	bspDisruptionTimeout                    = flag.Duration("blocking-system-pod-distruption-timeout", time.Hour, "The timeout after which CA will evict non-pdb-assigned blocking system pods, applicable only when --skip-nodes-with-system-pods is set to true")
 	nodeDeleteDelayAfterTaint               = flag.Duration("node-delete-delay-after-taint", 5*time.Second, "How long to wait before deleting a node after tainting it")
 	scaleDownSimulationTimeout              = flag.Duration("scale-down-simulation-timeout", 30*time.Second, "How long should we run scale down simulation.")
+	scaleDownConsolidationEnabled           = flag.Bool("scale-down-consolidation-enabled", false, "Should CA replace underutilized nodes whose pods don't fit elsewhere with a single new node, when that is cheaper or reduces the number of nodes")
+	scaleDownConsolidationMaxNodes          = flag.Int("scale-down-consolidation-max-nodes", 10, "Maximum number of nodes replaced by a single new node during scale down consolidation.")
 	maxCapacityMemoryDifferenceRatio        = flag.Float64("memory-difference-ratio", config.DefaultMaxCapacityMemoryDifferenceRatio, "Maximum difference in memory capacity between two similar node groups to be considered for balancing. Value is a ratio of the smaller node group's memory capacity.")
 	maxFreeDifferenceRatio                  = flag.Float64("max-free-difference-ratio", config.DefaultMaxFreeDifferenceRatio, "Maximum difference in free resources between two similar node groups to be considered for balancing. Value is a ratio of the smaller node group's free resource.")
 	maxAllocatableDifferenceRatio           = flag.Float64("max-allocatable-difference-ratio", config.DefaultMaxAllocatableDifferenceRatio, "Maximum difference in allocatable resources between two similar node groups to be considered for balancing. Value is a ratio of the smaller node group's allocatable resource.")
@@ -236,6 +257,8 @@ var (
 	enableDynamicResourceAllocation              = flag.Bool("enable-dynamic-resource-allocation", true, "Handle DRA (Dynamic Resource Allocation) objects, locked to true.")
 	enableCSINodeAwareScheduling                 = flag.Bool("enable-csi-node-aware-scheduling", false, "Whether logic for handling CSINode objects is enabled.")
 	predicateParallelism                         = flag.Int("predicate-parallelism", 4, "Maximum parallelism of scheduler predicate checking.")
+	incrementalClusterSnapshot                   = flag.Bool("incremental-cluster-snapshot", false, "Whether to update the cluster snapshot incrementally based on informer events, instead of rebuilding it from scratch in every loop.")
+	incrementalSnapshotVerification              = flag.Bool("incremental-cluster-snapshot-verification", false, "Whether to compare the incrementally updated cluster snapshot with a full rebuild in every loop, and rebuild it if they differ. Only for debugging, as it removes the gains of incremental updates. No-op if --incremental-cluster-snapshot is false.")
 	checkCapacityProcessorInstance               = flag.String("check-capacity-processor-instance", "", "Name of the processor instance. Only ProvisioningRequests that define this name in their parameters with the key \"processorInstance\" will be processed by this CA instance. It only refers to check capacity ProvisioningRequests, but if not empty, best-effort atomic ProvisioningRequests processing is disabled in this instance. Not recommended: Until CA 1.35, ProvisioningRequests with this name as prefix in their class will be also processed.")
 	nodeDeletionCandidateTTL                     = flag.Duration("node-deletion-candidate-ttl", time.Duration(0), "Maximum time a node can be marked as removable before the marking becomes stale. This sets the TTL of Cluster-Autoscaler's state if the Cluste-Autoscaler deployment becomes inactive")
 	capacitybufferControllerEnabled              = flag.Bool("capacity-buffer-controller-enabled", false, "Whether to enable the default controller for capacity buffers or not")
@@ -302,10 +325,28 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		}
 	}
 
+	if *scaleUpEstimationParallelism < 1 {
+		klog.Fatalf("Invalid value for --scale-up-estimation-parallelism flag: %d", *scaleUpEstimationParallelism)
+	}
 	if *predicateParallelism < 1 {
 		klog.Fatalf("Invalid value for --predicate-parallelism flag: %d", *predicateParallelism)
 	}
 
+	switch utilization.Mode(*scaleDownUtilizationMode) {
+	case utilization.ModeRequests, utilization.ModeUsage, utilization.ModeMax:
+	default:
+		klog.Fatalf("Invalid value for --scale-down-utilization-mode flag: %s", *scaleDownUtilizationMode)
+	}
+	if *scaleDownUsageSource != usage.SourceMetricsAPI && *scaleDownUsageSource != usage.SourcePrometheus {
+		klog.Fatalf("Invalid value for --scale-down-usage-source flag: %s", *scaleDownUsageSource)
+	}
+	if *scaleDownUsageSource == usage.SourcePrometheus && *scaleDownUsagePrometheusURL == "" && *scaleDownUtilizationMode != string(utilization.ModeRequests) {
+		klog.Fatalf("--scale-down-usage-prometheus-url flag is required with --scale-down-usage-source=%s", usage.SourcePrometheus)
+	}
+	if *scaleDownUsagePercentile <= 0 || *scaleDownUsagePercentile > 1 {
+		klog.Fatalf("Invalid value for --scale-down-usage-percentile flag: %v", *scaleDownUsagePercentile)
+	}
+
 	if !ptr.Deref(enableDynamicResourceAllocation, false) {
 		klog.Fatalf("--enable-dynamic-resource-allocation flag must be true: %t", ptr.Deref(enableDynamicResourceAllocation, false))
 	}
@@ -344,7 +385,14 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		ExpanderNames:                    *expanderFlag,
 		GRPCExpanderCert:                 *grpcExpanderCert,
 		GRPCExpanderURL:                  *grpcExpanderURL,
+		PriceListFile:                    *priceListFile,
+		PriceListConfigMap:               *priceListConfigMap,
 		IgnoreMirrorPodsUtilization:      *ignoreMirrorPodsUtilization,
+		ScaleDownUtilizationMode:         *scaleDownUtilizationMode,
+		ScaleDownUsageSource:             *scaleDownUsageSource,
+		ScaleDownUsagePrometheusURL:      *scaleDownUsagePrometheusURL,
+		ScaleDownUsagePercentile:         *scaleDownUsagePercentile,
+		ScaleDownUsageWindow:             *scaleDownUsageWindow,
 		MaxBulkSoftTaintCount:            *maxBulkSoftTaintCount,
 		MaxBulkSoftTaintTime:             *maxBulkSoftTaintTime,
 		MaxGracefulTerminationSec:        *maxGracefulTerminationFlag,
@@ -371,6 +419,7 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		WriteStatusConfigMap:             *writeStatusConfigMapFlag,
 		StatusConfigMapName:              *statusConfigMapName,
 		BalanceSimilarNodeGroups:         *balanceSimilarNodeGroupsFlag,
+		ZoneSpreadScaleUpEnabled:         *zoneSpreadScaleUpFlag,
 		ConfigNamespace:                  *namespace,
 		ClusterName:                      *clusterName,
 		UnremovableNodeRecheckTimeout:    *unremovableNodeRecheckTimeout,
@@ -412,6 +461,7 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		MaxNodesPerScaleUp:                 *maxNodesPerScaleUp,
 		MaxNodeGroupBinpackingDuration:     *maxNodeGroupBinpackingDuration,
 		MaxBinpackingTime:                  *maxBinpackingTimeFlag,
+		ScaleUpEstimationParallelism:       *scaleUpEstimationParallelism,
 		FastpathBinpackingEnabled:          *fastpathBinpackingEnabled,
 		NodeDeletionBatcherInterval:        *nodeDeletionBatcherInterval,
 		SkipNodesWithSystemPods:            *skipNodesWithSystemPods,
@@ -420,6 +470,8 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		BspDisruptionTimeout:               *bspDisruptionTimeout,
 		NodeDeleteDelayAfterTaint:          *nodeDeleteDelayAfterTaint,
 		ScaleDownSimulationTimeout:         *scaleDownSimulationTimeout,
+		ScaleDownConsolidationEnabled:      *scaleDownConsolidationEnabled,
+		ScaleDownConsolidationMaxNodes:     *scaleDownConsolidationMaxNodes,
 		SkipNodesWithCustomControllerPods:  *skipNodesWithCustomControllerPods,
 		NodeGroupSetRatios: config.NodeGroupDifferenceRatios{
 			MaxCapacityMemoryDifferenceRatio: *maxCapacityMemoryDifferenceRatio,
@@ -442,6 +494,8 @@ func createAutoscalingOptions() config.AutoscalingOptions {
 		DynamicResourceAllocationEnabled:             *enableDynamicResourceAllocation,
 		CSINodeAwareSchedulingEnabled:                *enableCSINodeAwareScheduling,
 		PredicateParallelism:                         *predicateParallelism,
+		IncrementalClusterSnapshotEnabled:            *incrementalClusterSnapshot,
+		IncrementalSnapshotVerificationEnabled:       *incrementalSnapshotVerification,
 		CheckCapacityProcessorInstance:               *checkCapacityProcessorInstance,
 		MaxInactivityTime:                            *maxInactivityTimeFlag,
 		MaxFailingTime:                               *maxFailingTimeFlag,
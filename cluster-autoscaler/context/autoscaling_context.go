@@ -35,6 +35,7 @@ import (
 	csinodeprovider "k8s.io/autoscaler/cluster-autoscaler/simulator/csi/provider"
 	draprovider "k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources/provider"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
@@ -77,6 +78,8 @@ type AutoscalingContext struct {
 	TemplateNodeInfoRegistry TemplateNodeInfoRegistry
 	// CsiProvider is the provider for CSI node aware scheduling.
 	CsiProvider *csinodeprovider.Provider
+	// UsageProvider provides the actual usage of pods for scale down utilization, if enabled.
+	UsageProvider utilization.UsageProvider
 }
 
 // TemplateNodeInfoRegistry is the interface for getting template node infos.
@@ -143,6 +146,7 @@ func NewAutoscalingContext(
 	draProvider *draprovider.Provider,
 	templateNodeInfoRegistry TemplateNodeInfoRegistry,
 	csiProvider *csinodeprovider.Provider,
+	usageProvider utilization.UsageProvider,
 ) *AutoscalingContext {
 	return &AutoscalingContext{
 		AutoscalingOptions:       options,
@@ -158,6 +162,7 @@ func NewAutoscalingContext(
 		DraProvider:              draProvider,
 		TemplateNodeInfoRegistry: templateNodeInfoRegistry,
 		CsiProvider:              csiProvider,
+		UsageProvider:            usageProvider,
 	}
 }
 
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	cloudBuilder "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/builder"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/pricelist"
 	ca_context "k8s.io/autoscaler/cluster-autoscaler/context"
 	coreoptions "k8s.io/autoscaler/cluster-autoscaler/core/options"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/pdb"
@@ -31,6 +32,8 @@ import (
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
 	"k8s.io/autoscaler/cluster-autoscaler/resourcequotas"
 	"k8s.io/autoscaler/cluster-autoscaler/resourcequotas/capacityquota"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/changes"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/predicate"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/store"
 	csinodeprovider "k8s.io/autoscaler/cluster-autoscaler/simulator/csi/provider"
@@ -39,6 +42,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
 	"k8s.io/client-go/informers"
 )
 
@@ -84,7 +88,9 @@ func NewAutoscaler(ctx context.Context, opts coreoptions.AutoscalerOptions, info
 		opts.QuotasTrackerOptions,
 		opts.MinQuotasTrackerOptions,
 		opts.CSIProvider,
+		opts.UsageProvider,
 		opts.CapacityBufferPodsRegistry,
+		opts.ClusterSnapshotChanges,
 	), nil
 }
 
@@ -104,7 +110,12 @@ func initializeDefaultOptions(ctx context.Context, opts *coreoptions.AutoscalerO
 		opts.FrameworkHandle = fwHandle
 	}
 	if opts.ClusterSnapshot == nil {
-		opts.ClusterSnapshot = predicate.NewPredicateSnapshot(store.NewBasicSnapshotStore(), opts.FrameworkHandle, opts.DynamicResourceAllocationEnabled, opts.PredicateParallelism, opts.CSINodeAwareSchedulingEnabled)
+		var snapshotStore clustersnapshot.ClusterSnapshotStore = store.NewBasicSnapshotStore()
+		if opts.IncrementalClusterSnapshotEnabled {
+			// Incremental updates need a store that can be forked independently.
+			snapshotStore = store.NewDeltaSnapshotStore()
+		}
+		opts.ClusterSnapshot = predicate.NewPredicateSnapshot(snapshotStore, opts.FrameworkHandle, opts.DynamicResourceAllocationEnabled, opts.PredicateParallelism, opts.CSINodeAwareSchedulingEnabled)
 	}
 	if opts.RemainingPdbTracker == nil {
 		opts.RemainingPdbTracker = pdb.NewBasicRemainingPdbTracker()
@@ -137,8 +148,21 @@ func initializeDefaultOptions(ctx context.Context, opts *coreoptions.AutoscalerO
 	if opts.DraProvider == nil && opts.DynamicResourceAllocationEnabled {
 		opts.DraProvider = draprovider.NewProviderFromInformers(informerFactory)
 	}
+	if opts.ClusterSnapshotChanges == nil && opts.IncrementalClusterSnapshotEnabled {
+		tracker, err := changes.NewTrackerFromInformers(informerFactory, opts.DynamicResourceAllocationEnabled, opts.CSINodeAwareSchedulingEnabled)
+		if err != nil {
+			return err
+		}
+		opts.ClusterSnapshotChanges = tracker
+	}
+	if opts.PriceListSource == nil {
+		opts.PriceListSource = newPriceListSource(opts)
+	}
 	if opts.CloudProvider == nil {
 		opts.CloudProvider = cloudBuilder.NewCloudProvider(opts, informerFactory)
+		if opts.PriceListSource != nil {
+			opts.CloudProvider = pricelist.WithFallback(opts.CloudProvider, pricelist.NewModel(opts.PriceListSource))
+		}
 	}
 	if opts.ExpanderStrategy == nil {
 		expanderFactory := factory.NewFactory()
@@ -181,3 +205,19 @@ func initializeDefaultOptions(ctx context.Context, opts *coreoptions.AutoscalerO
 	}
 	return nil
 }
+
+// newPriceListSource returns the source of the price list used by cloud
+// providers that don't implement pricing and by the providers that price
+// nodes from it themselves, or nil if none is configured.
+func newPriceListSource(opts *coreoptions.AutoscalerOptions) pricelist.Source {
+	if opts.PriceListFile != "" {
+		return pricelist.NewFileSource(opts.PriceListFile)
+	}
+	if opts.PriceListConfigMap != "" {
+		// Like the priority expander's lister, this one runs for the lifetime of the process.
+		stopChannel := make(chan struct{})
+		lister := kube_util.NewConfigMapListerForNamespace(opts.KubeClient, stopChannel, opts.ConfigNamespace)
+		return pricelist.NewConfigMapSource(lister.ConfigMaps(opts.ConfigNamespace), opts.PriceListConfigMap)
+	}
+	return nil
+}
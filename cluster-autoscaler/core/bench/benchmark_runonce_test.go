@@ -417,6 +417,32 @@ func setupScaleUp(nodes int) func(*integration.FakeSet) error {
 	}
 }
 
+// setupScaleUpManyNodeGroups prepares a scenario that triggers a scale-up for the specified number of nodes
+// in a cluster with many identical node groups, so that each of them is estimated.
+// Each node is designed to fit 50 pods.
+func setupScaleUpManyNodeGroups(nodeGroups, nodes int) func(*integration.FakeSet) error {
+	return func(clusterFakes *integration.FakeSet) error {
+		for i := range nodeGroups {
+			nTemplate := BuildTestNode(fmt.Sprintf("n-template-%d", i), nodeCPU, nodeMem)
+			SetNodeReadyState(nTemplate, true, time.Now())
+			clusterFakes.CloudProvider.AddNodeGroup(fmt.Sprintf("ng-%d", i),
+				testprovider.WithTemplate(framework.NewNodeInfo(nTemplate, nil)),
+				testprovider.WithNGSize(0, maxNGSize),
+			)
+		}
+
+		const podsPerNode = 50
+		for i := range nodes * podsPerNode {
+			podName := fmt.Sprintf("pod-%d", i)
+			cpu := int64(nodeCPU / podsPerNode)
+			mem := int64(nodeMem / podsPerNode)
+			pod := BuildTestPod(podName, cpu, mem, MarkUnschedulable())
+			clusterFakes.K8s.AddPod(pod)
+		}
+		return nil
+	}
+}
+
 // setupScaleDown60Percent prepares a scenario where the workload is reduced such
 // that it fits on 40% of the existing nodes, triggering a 60% scale-down.
 // Each node starts with 40 pods, each consuming 1% of the node's resources,
@@ -470,6 +496,25 @@ func verifyTargetSize(expectedTargetSize int) func(*integration.FakeSet) error {
 	}
 }
 
+// verifyTotalTargetSize returns a verification function that checks if the sum of the target sizes
+// of all node groups matches the expected value.
+func verifyTotalTargetSize(expectedTargetSize int) func(*integration.FakeSet) error {
+	return func(clusterFakes *integration.FakeSet) error {
+		total := 0
+		for _, ng := range clusterFakes.CloudProvider.NodeGroups() {
+			targetSize, err := ng.TargetSize()
+			if err != nil {
+				return err
+			}
+			total += targetSize
+		}
+		if total != expectedTargetSize {
+			return fmt.Errorf("expected total target size %d, got %d", expectedTargetSize, total)
+		}
+		return nil
+	}
+}
+
 // verifyToBeDeleted returns a verification function that checks if the number of nodes
 // marked with the ToBeDeleted taint matches the expected count.
 func verifyToBeDeleted(expectedDeletedSize int) func(*integration.FakeSet) error {
@@ -502,6 +547,24 @@ func BenchmarkRunOnceScaleUp(b *testing.B) {
 	s.run(b)
 }
 
+// BenchmarkRunOnceScaleUpManyNodeGroups compares sequential and parallel estimation of scale-up options.
+func BenchmarkRunOnceScaleUpManyNodeGroups(b *testing.B) {
+	for _, parallelism := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("parallelism-%d", parallelism), func(b *testing.B) {
+			s := scenario{
+				setup:  setupScaleUpManyNodeGroups(100, 20),
+				verify: verifyTotalTargetSize(20),
+				config: func(opts *config.AutoscalingOptions) {
+					opts.MaxNodesPerScaleUp = maxNGSize
+					opts.ScaleUpFromZero = true
+					opts.ScaleUpEstimationParallelism = parallelism
+				},
+			}
+			s.run(b)
+		})
+	}
+}
+
 func BenchmarkRunOnceScaleDown(b *testing.B) {
 	s := scenario{
 		setup:  setupScaleDown60Percent(400),
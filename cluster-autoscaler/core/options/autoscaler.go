@@ -19,6 +19,7 @@ package options
 import (
 	"k8s.io/autoscaler/cluster-autoscaler/capacitybuffer/fakepods"
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider/pricelist"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate/scaleupfailures"
 	"k8s.io/autoscaler/cluster-autoscaler/config"
 	"k8s.io/autoscaler/cluster-autoscaler/context"
@@ -31,11 +32,13 @@ import (
 	ca_processors "k8s.io/autoscaler/cluster-autoscaler/processors"
 	"k8s.io/autoscaler/cluster-autoscaler/resourcequotas"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/changes"
 	csinodeprovider "k8s.io/autoscaler/cluster-autoscaler/simulator/csi/provider"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
 	draprovider "k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources/provider"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
 	"k8s.io/client-go/informers"
 	kube_client "k8s.io/client-go/kubernetes"
@@ -50,6 +53,7 @@ type AutoscalerOptions struct {
 	InformerFactory            informers.SharedInformerFactory
 	AutoscalingKubeClients     *context.AutoscalingKubeClients
 	CloudProvider              cloudprovider.CloudProvider
+	PriceListSource            pricelist.Source
 	FrameworkHandle            *framework.Handle
 	ClusterSnapshot            clustersnapshot.ClusterSnapshot
 	ExpanderStrategy           expander.Strategy
@@ -70,4 +74,6 @@ type AutoscalerOptions struct {
 	KubeClientNew              client.Client
 	KubeCache                  cache.Cache
 	CapacityBufferPodsRegistry *fakepods.Registry
+	UsageProvider              utilization.UsageProvider
+	ClusterSnapshotChanges     *changes.Tracker
 }
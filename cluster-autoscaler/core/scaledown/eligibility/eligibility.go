@@ -17,6 +17,7 @@ limitations under the License.
 package eligibility
 
 import (
+	"fmt"
 	"time"
 
 	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
@@ -138,7 +139,7 @@ func (c *Checker) unremovableReasonAndNodeUtilization(autoscalingCtx *ca_context
 	}
 
 	gpuConfig := autoscalingCtx.CloudProvider.GetNodeGpuConfig(node)
-	utilInfo, err := utilization.Calculate(nodeInfo, ignoreDaemonSetsUtilization, autoscalingCtx.IgnoreMirrorPodsUtilization, autoscalingCtx.DynamicResourceAllocationEnabled, gpuConfig, timestamp)
+	utilInfo, err := utilization.CalculateWithUsage(nodeInfo, ignoreDaemonSetsUtilization, autoscalingCtx.IgnoreMirrorPodsUtilization, autoscalingCtx.DynamicResourceAllocationEnabled, gpuConfig, timestamp, autoscalingCtx.UsageProvider, utilization.Mode(autoscalingCtx.ScaleDownUtilizationMode))
 	if err != nil {
 		klog.Warningf("Failed to calculate utilization for %s: %v", node.Name, err)
 		return simulator.UnexpectedError, nil
@@ -159,11 +160,11 @@ func (c *Checker) unremovableReasonAndNodeUtilization(autoscalingCtx *ca_context
 		return simulator.UnexpectedError, nil
 	}
 	if !underutilized {
-		klog.V(4).Infof("Node %s unremovable: %s requested (%.6g%% of allocatable) is above the scale-down utilization threshold", node.Name, utilInfo.ResourceName, utilInfo.Utilization*100)
+		klog.V(4).Infof("Node %s unremovable: %s is above the scale-down utilization threshold", node.Name, describeUtilization(utilInfo))
 		return simulator.NotUnderutilized, &utilInfo
 	}
 
-	klogx.V(4).UpTo(utilLogsQuota).Infof("Node %s - %s requested is %.6g%% of allocatable", node.Name, utilInfo.ResourceName, utilInfo.Utilization*100)
+	klogx.V(4).UpTo(utilLogsQuota).Infof("Node %s - %s", node.Name, describeUtilization(utilInfo))
 
 	return simulator.NoReason, &utilInfo
 }
@@ -190,6 +191,19 @@ func (c *Checker) isNodeAtOrBelowUtilizationThreshold(autoscalingCtx *ca_context
 	return true, nil
 }
 
+// describeUtilization describes the utilization of the highest utilized
+// resource, with the request-based utilization when usage is accounted for.
+func describeUtilization(utilInfo utilization.Info) string {
+	if utilInfo.CpuRequestUtil == 0 && utilInfo.MemRequestUtil == 0 {
+		return fmt.Sprintf("%s requested (%.6g%% of allocatable)", utilInfo.ResourceName, utilInfo.Utilization*100)
+	}
+	requestUtil := utilInfo.CpuRequestUtil
+	if utilInfo.ResourceName == apiv1.ResourceMemory {
+		requestUtil = utilInfo.MemRequestUtil
+	}
+	return fmt.Sprintf("%s utilization based on usage (%.6g%% of allocatable, %.6g%% requested)", utilInfo.ResourceName, utilInfo.Utilization*100, requestUtil*100)
+}
+
 // HasNoScaleDownAnnotation checks whether the node has an annotation blocking it from being scaled down.
 func HasNoScaleDownAnnotation(node *apiv1.Node) bool {
 	return node.Annotations[ScaleDownDisabledKey] == "true"
@@ -0,0 +1,490 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planner
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown"
+	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/pdb"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	klog "k8s.io/klog/v2"
+)
+
+// consolidationTemplate is a node group that can be scaled up to provide a
+// replacement node, with the template of its nodes.
+type consolidationTemplate struct {
+	nodeGroup cloudprovider.NodeGroup
+	nodeInfo  *framework.NodeInfo
+}
+
+// consolidationPlan is the outcome of simulating the removal of some nodes
+// after adding a replacement node from a template.
+type consolidationPlan struct {
+	template *consolidationTemplate
+	removed  []simulator.NodeToBeRemoved
+	// savings is the price per hour saved by the replacement. It is only
+	// meaningful when priced is true.
+	savings float64
+	priced  bool
+}
+
+// betterThan returns true if the plan saves more than the other one, or
+// removes more nodes when the savings are unknown or equal.
+func (c *consolidationPlan) betterThan(other *consolidationPlan) bool {
+	if other == nil {
+		return true
+	}
+	if c.priced && other.priced && c.savings != other.savings {
+		return c.savings > other.savings
+	}
+	return len(c.removed) > len(other.removed)
+}
+
+// consolidation is a replacement node planned to consolidate some nodes.
+// The nodes are drained only once the replacement is ready, so that their
+// pods don't go pending.
+type consolidation struct {
+	nodeGroup cloudprovider.NodeGroup
+	nodes     []string
+	// requested is set once the node group was scaled up for the
+	// replacement. Consolidations not requested by the next update are
+	// forgotten.
+	requested bool
+	// knownNodes are the nodes that existed when the replacement was
+	// requested, a new ready node of the node group is the replacement.
+	knownNodes  map[string]bool
+	deadline    time.Time
+	replacement string
+}
+
+// consolidate tries to remove candidates whose pods didn't fit on the
+// existing nodes by replacing several of them with a single new node. Each
+// round simulates, for every node group that can be scaled up, adding one of
+// its nodes and moving the pods of up to ScaleDownConsolidationMaxNodes
+// candidates, least utilized first. The moves go through the removal
+// simulator, so drainability rules and PDBs are respected. A plan is accepted
+// if it is cheaper according to the pricing model, or if it removes more than
+// one node without being more expensive. The best plan is kept in the
+// snapshot, so that subsequent rounds and simulations account for it.
+// The replacement of the best plan is returned by ConsolidationReplacements,
+// for the autoscaler to scale up its node group along with the other scale
+// ups, while the nodes it replaces are kept until the replacement is ready.
+// From then on, the regular simulation finds them removable, with their pods
+// moving to the replacement.
+// Returns the candidates that can't be removed yet.
+func (p *Planner) consolidate(podDestinations map[string]bool, candidates []*simulator.UnremovableNode, timer *time.Timer, limit int) []*simulator.UnremovableNode {
+	templates := p.consolidationTemplates()
+	if len(templates) == 0 || limit <= 0 {
+		return candidates
+	}
+	var unremovable []*simulator.UnremovableNode
+	remaining := make(map[string]*simulator.UnremovableNode, len(candidates))
+	for _, candidate := range candidates {
+		if p.atomicScaleDownNode(&simulator.NodeToBeRemoved{Node: candidate.Node}) {
+			unremovable = append(unremovable, candidate)
+			continue
+		}
+		remaining[candidate.Node.Name] = candidate
+	}
+
+	consolidated := 0
+	for round := 0; len(remaining) > 0 && consolidated < limit; round++ {
+		names := p.byUtilization(remaining)
+		if maxNodes := limit - consolidated; len(names) > maxNodes {
+			names = names[:maxNodes]
+		}
+		var best *consolidationPlan
+		for _, template := range templates {
+			if timedOut(timer) {
+				klog.Warningf("Scale down consolidation stopped due to timeout.")
+				return append(unremovable, values(remaining)...)
+			}
+			plan := p.simulateConsolidation(template, names, podDestinations, round, false)
+			if plan != nil && plan.betterThan(best) {
+				best = plan
+			}
+		}
+		if best == nil {
+			break
+		}
+		plan := p.simulateConsolidation(best.template, names, podDestinations, round, true)
+		if plan == nil {
+			break
+		}
+		p.planReplacement(plan)
+		for _, node := range plan.removed {
+			delete(podDestinations, node.Node.Name)
+			delete(remaining, node.Node.Name)
+			p.autoscalingCtx.RemainingPdbTracker.RemovePods(node.PodsToReschedule)
+			unremovable = append(unremovable, &simulator.UnremovableNode{Node: node.Node, Reason: simulator.ConsolidationInProgress})
+			consolidated++
+		}
+	}
+	return append(unremovable, values(remaining)...)
+}
+
+// planReplacement starts tracking the consolidation of the plan, until its
+// replacement is requested.
+func (p *Planner) planReplacement(plan *consolidationPlan) {
+	names := removedNames(plan.removed)
+	klog.V(2).Infof("Nodes %v can be replaced with a new node from node group %s", names, plan.template.nodeGroup.Id())
+	p.consolidations = append(p.consolidations, &consolidation{
+		nodeGroup: plan.template.nodeGroup,
+		nodes:     names,
+	})
+}
+
+// ConsolidationReplacements returns the replacements of the consolidations
+// that weren't requested yet.
+func (p *Planner) ConsolidationReplacements() []*scaledown.ConsolidationReplacement {
+	var replacements []*scaledown.ConsolidationReplacement
+	for _, c := range p.consolidations {
+		if !c.requested {
+			replacements = append(replacements, &scaledown.ConsolidationReplacement{NodeGroup: c.nodeGroup, Nodes: c.nodes})
+		}
+	}
+	return replacements
+}
+
+// ConsolidationReplacementRequested marks the consolidation of the
+// replacement as requested, and starts waiting for its replacement node to be
+// ready.
+func (p *Planner) ConsolidationReplacementRequested(replacement *scaledown.ConsolidationReplacement, currentTime time.Time) {
+	for _, c := range p.consolidations {
+		if c.requested || c.nodeGroup.Id() != replacement.NodeGroup.Id() || !slices.Equal(c.nodes, replacement.Nodes) {
+			continue
+		}
+		knownNodes := make(map[string]bool)
+		if nodeInfos, err := p.autoscalingCtx.ClusterSnapshot.ListNodeInfos(); err == nil {
+			for _, nodeInfo := range nodeInfos {
+				knownNodes[nodeInfo.Node().Name] = true
+			}
+		}
+		klog.V(2).Infof("Nodes %v will be replaced with a new node from node group %s, waiting for it to be ready", c.nodes, c.nodeGroup.Id())
+		c.requested = true
+		c.knownNodes = knownNodes
+		c.deadline = currentTime.Add(p.maxNodeProvisionTime(c.nodeGroup))
+		return
+	}
+}
+
+// updateConsolidations looks for the replacements of the consolidations and
+// forgets the consolidations that weren't requested, are finished, abandoned
+// or whose replacement wasn't provisioned in time.
+func (p *Planner) updateConsolidations() {
+	p.consolidations = slices.DeleteFunc(p.consolidations, func(c *consolidation) bool {
+		if !c.requested {
+			klog.V(4).Infof("Replacement for consolidation of nodes %v wasn't requested, forgetting it", c.nodes)
+		}
+		return !c.requested
+	})
+	if len(p.consolidations) == 0 {
+		return
+	}
+	nodeInfos, err := p.autoscalingCtx.ClusterSnapshot.ListNodeInfos()
+	if err != nil {
+		klog.Errorf("Failed to list nodes for scale down consolidation: %v", err)
+		return
+	}
+	existing := make(map[string]*apiv1.Node, len(nodeInfos))
+	for _, nodeInfo := range nodeInfos {
+		existing[nodeInfo.Node().Name] = nodeInfo.Node()
+	}
+	var consolidations []*consolidation
+	for _, c := range p.consolidations {
+		if !anyExists(c.nodes, existing) {
+			klog.V(4).Infof("Consolidation of nodes %v finished", c.nodes)
+			continue
+		}
+		if c.replacement == "" {
+			c.replacement = p.findReplacement(c, existing)
+			if c.replacement == "" && p.latestUpdate.After(c.deadline) {
+				klog.Warningf("Replacement node for consolidation of nodes %v wasn't ready in time, keeping the nodes", c.nodes)
+				continue
+			}
+			if c.replacement != "" {
+				klog.V(2).Infof("Node %s is ready, nodes %v can be drained", c.replacement, c.nodes)
+			}
+		} else if _, found := existing[c.replacement]; !found {
+			klog.Warningf("Replacement node %s for consolidation of nodes %v is gone, keeping the nodes", c.replacement, c.nodes)
+			continue
+		}
+		consolidations = append(consolidations, c)
+	}
+	p.consolidations = consolidations
+}
+
+// findReplacement returns the name of a new ready node of the node group of
+// the consolidation, or an empty string if there is none yet.
+func (p *Planner) findReplacement(c *consolidation, existing map[string]*apiv1.Node) string {
+	taken := make(map[string]bool, len(p.consolidations))
+	for _, other := range p.consolidations {
+		taken[other.replacement] = true
+	}
+	for name, node := range existing {
+		if c.knownNodes[name] || taken[name] {
+			continue
+		}
+		if ready, _, _ := kube_util.GetReadinessState(node); !ready {
+			continue
+		}
+		nodeGroup, err := p.autoscalingCtx.CloudProvider.NodeGroupForNode(node)
+		if err != nil || nodeGroup == nil {
+			continue
+		}
+		if nodeGroup.Id() == c.nodeGroup.Id() {
+			return name
+		}
+	}
+	return ""
+}
+
+// dropAbandonedConsolidations forgets the consolidations whose replacement is
+// ready but none of whose nodes are unneeded or being deleted anymore, so
+// that the replacement can be scaled down like any other node.
+func (p *Planner) dropAbandonedConsolidations() {
+	deletions := asMap(merged(p.scaleDownContext.ActuationStatus.DeletionsInProgress()))
+	var consolidations []*consolidation
+	for _, c := range p.consolidations {
+		if c.replacement == "" {
+			consolidations = append(consolidations, c)
+			continue
+		}
+		for _, name := range c.nodes {
+			if p.unneededNodes.Contains(name) || deletions[name] {
+				consolidations = append(consolidations, c)
+				break
+			}
+		}
+	}
+	p.consolidations = consolidations
+}
+
+// filterOutConsolidating returns the candidates that aren't waiting for their
+// consolidation replacement, nor are the replacement of an ongoing
+// consolidation. The other ones are unremovable.
+func (p *Planner) filterOutConsolidating(candidates []*apiv1.Node) []*apiv1.Node {
+	if len(p.consolidations) == 0 {
+		return candidates
+	}
+	consolidating := make(map[string]bool)
+	for _, c := range p.consolidations {
+		if c.replacement == "" {
+			for _, name := range c.nodes {
+				consolidating[name] = true
+			}
+		} else {
+			consolidating[c.replacement] = true
+		}
+	}
+	var result []*apiv1.Node
+	for _, node := range candidates {
+		if consolidating[node.Name] {
+			p.unremovableNodes.AddReason(node, simulator.ConsolidationInProgress)
+			continue
+		}
+		result = append(result, node)
+	}
+	return result
+}
+
+func (p *Planner) maxNodeProvisionTime(nodeGroup cloudprovider.NodeGroup) time.Duration {
+	if csr := p.autoscalingCtx.ClusterStateRegistry; csr != nil {
+		if maxNodeProvisionTime, err := csr.MaxNodeProvisionTime(nodeGroup); err == nil {
+			return maxNodeProvisionTime
+		}
+	}
+	return p.autoscalingCtx.NodeGroupDefaults.MaxNodeProvisionTime
+}
+
+func anyExists(names []string, existing map[string]*apiv1.Node) bool {
+	for _, name := range names {
+		if _, found := existing[name]; found {
+			return true
+		}
+	}
+	return false
+}
+
+// simulateConsolidation adds a node from the template to the snapshot and
+// simulates the removal of the candidates, with the pods allowed to move to
+// the existing destinations and to the new node. The changes are kept in the
+// snapshot only if persist is true and the plan is worth it. Returns nil if
+// it isn't.
+func (p *Planner) simulateConsolidation(template *consolidationTemplate, candidates []string, podDestinations map[string]bool, round int, persist bool) (plan *consolidationPlan) {
+	snapshot := p.autoscalingCtx.ClusterSnapshot
+	snapshot.Fork()
+	defer func() {
+		if persist && plan != nil {
+			if err := snapshot.Commit(); err != nil {
+				klog.Fatalf("Got error when calling ClusterSnapshot.Commit(); %v", err)
+			}
+		} else {
+			snapshot.Revert()
+		}
+	}()
+
+	replacement, err := simulator.SanitizedNodeInfo(template.nodeInfo, fmt.Sprintf("consolidation-%d", round))
+	if err != nil {
+		klog.Errorf("Failed to create a replacement node from node group %s: %v", template.nodeGroup.Id(), err)
+		return nil
+	}
+	if err := snapshot.AddNodeInfo(replacement); err != nil {
+		klog.Errorf("Failed to add a replacement node from node group %s to the snapshot: %v", template.nodeGroup.Id(), err)
+		return nil
+	}
+
+	destinations := make(map[string]bool, len(podDestinations)+1)
+	for name, destination := range podDestinations {
+		destinations[name] = destination
+	}
+	destinations[replacement.Node().Name] = true
+	pdbTracker := pdb.NewBasicRemainingPdbTracker()
+	pdbTracker.SetPdbs(p.autoscalingCtx.RemainingPdbTracker.GetPdbs())
+
+	var removed []simulator.NodeToBeRemoved
+	maxNodes := p.autoscalingCtx.AutoscalingOptions.ScaleDownConsolidationMaxNodes
+	for _, candidate := range candidates {
+		if maxNodes > 0 && len(removed) >= maxNodes {
+			break
+		}
+		removable, _ := p.rs.SimulateNodeRemoval(candidate, destinations, p.latestUpdate, pdbTracker)
+		if removable == nil {
+			continue
+		}
+		if _, inParallel, _ := pdbTracker.CanRemovePods(removable.PodsToReschedule); !inParallel {
+			removable.IsRisky = true
+		}
+		pdbTracker.RemovePods(removable.PodsToReschedule)
+		delete(destinations, candidate)
+		removed = append(removed, *removable)
+	}
+
+	// Candidates that could be removed without using the replacement would
+	// have been found by the regular simulation already.
+	nodeInfo, err := snapshot.GetNodeInfo(replacement.Node().Name)
+	if err != nil || len(removed) == 0 || len(nodeInfo.Pods()) <= len(replacement.Pods()) {
+		return nil
+	}
+	plan = &consolidationPlan{template: template, removed: removed}
+	plan.savings, plan.priced = p.consolidationSavings(replacement, removed)
+	if plan.priced && (plan.savings > 0 || plan.savings == 0 && len(removed) > 1) {
+		return plan
+	}
+	if !plan.priced && len(removed) > 1 {
+		return plan
+	}
+	return nil
+}
+
+// consolidationSavings returns the price per hour of the removed nodes minus
+// the price of the replacement, and false if the nodes can't be priced.
+func (p *Planner) consolidationSavings(replacement *framework.NodeInfo, removed []simulator.NodeToBeRemoved) (float64, bool) {
+	pricingModel, err := p.autoscalingCtx.CloudProvider.Pricing()
+	if err != nil {
+		return 0, false
+	}
+	startTime := p.latestUpdate
+	endTime := startTime.Add(time.Hour)
+	replacementPrice, priceErr := pricingModel.NodePrice(replacement.Node(), startTime, endTime)
+	if priceErr != nil {
+		klog.V(4).Infof("Failed to price replacement node %s: %v", replacement.Node().Name, priceErr)
+		return 0, false
+	}
+	savings := -replacementPrice
+	for _, node := range removed {
+		price, err := pricingModel.NodePrice(node.Node, startTime, endTime)
+		if err != nil {
+			klog.V(4).Infof("Failed to price node %s: %v", node.Node.Name, err)
+			return 0, false
+		}
+		savings += price
+	}
+	return savings, true
+}
+
+// consolidationTemplates returns the node groups that can be scaled up, with
+// the same templates that scale up uses. Node groups that are unhealthy or
+// backed off, e.g. because a replacement failed to start, are skipped.
+func (p *Planner) consolidationTemplates() []*consolidationTemplate {
+	if p.autoscalingCtx.TemplateNodeInfoRegistry == nil {
+		return nil
+	}
+	var templates []*consolidationTemplate
+	for _, nodeGroup := range p.autoscalingCtx.CloudProvider.NodeGroups() {
+		size, err := nodeGroup.TargetSize()
+		if err != nil {
+			klog.Errorf("Failed to get target size of node group %s: %v", nodeGroup.Id(), err)
+			continue
+		}
+		if size >= nodeGroup.MaxSize() {
+			continue
+		}
+		if csr := p.autoscalingCtx.ClusterStateRegistry; csr != nil && !csr.NodeGroupScaleUpSafety(nodeGroup, p.latestUpdate).SafeToScale {
+			klog.V(4).Infof("Node group %s isn't safe to scale up, skipping it in scale down consolidation", nodeGroup.Id())
+			continue
+		}
+		nodeInfo, found := p.autoscalingCtx.TemplateNodeInfoRegistry.GetNodeInfo(nodeGroup.Id())
+		if !found {
+			klog.V(4).Infof("No template node info for node group %s, skipping it in scale down consolidation", nodeGroup.Id())
+			continue
+		}
+		templates = append(templates, &consolidationTemplate{nodeGroup: nodeGroup, nodeInfo: nodeInfo})
+	}
+	return templates
+}
+
+// byUtilization returns the names of the nodes sorted by utilization, least
+// utilized first.
+func (p *Planner) byUtilization(nodes map[string]*simulator.UnremovableNode) []string {
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		ui, uj := p.nodeUtilizationMap[names[i]].Utilization, p.nodeUtilizationMap[names[j]].Utilization
+		if ui != uj {
+			return ui < uj
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+func values(nodes map[string]*simulator.UnremovableNode) []*simulator.UnremovableNode {
+	result := make([]*simulator.UnremovableNode, 0, len(nodes))
+	for _, node := range nodes {
+		result = append(result, node)
+	}
+	return result
+}
+
+func removedNames(nodes []simulator.NodeToBeRemoved) []string {
+	names := make([]string, len(nodes))
+	for i, node := range nodes {
+		names[i] = node.Node.Name
+	}
+	return names
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package planner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	ca_context "k8s.io/autoscaler/cluster-autoscaler/context"
+	. "k8s.io/autoscaler/cluster-autoscaler/core/test"
+	processorstest "k8s.io/autoscaler/cluster-autoscaler/processors/test"
+	"k8s.io/autoscaler/cluster-autoscaler/resourcequotas"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const nodeTypeLabel = "node-type"
+
+type fakePricingModel struct {
+	prices map[string]float64
+}
+
+func (m *fakePricingModel) NodePrice(node *apiv1.Node, startTime time.Time, endTime time.Time) (float64, error) {
+	return m.prices[node.Labels[nodeTypeLabel]], nil
+}
+
+func (m *fakePricingModel) PodPrice(pod *apiv1.Pod, startTime time.Time, endTime time.Time) (float64, error) {
+	return 0, nil
+}
+
+func TestConsolidation(t *testing.T) {
+	testCases := []struct {
+		name             string
+		disabled         bool
+		nodes            []*apiv1.Node
+		pods             []*apiv1.Pod
+		pdbs             []*policyv1.PodDisruptionBudget
+		prices           map[string]float64
+		largeMaxSize     int
+		largeAtMaxSize   bool
+		maxNodes         int
+		wantReplaced     []string
+		wantReplacements int
+		wantUnremovable  []string
+	}{
+		{
+			name:            "disabled",
+			disabled:        true,
+			nodes:           []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000)},
+			pods:            []*apiv1.Pod{consolidationPod("p1", 600, "n1"), consolidationPod("p2", 600, "n2")},
+			wantUnremovable: []string{"n1", "n2"},
+		},
+		{
+			name:             "two nodes replaced without pricing",
+			nodes:            []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000)},
+			pods:             []*apiv1.Pod{consolidationPod("p1", 600, "n1"), consolidationPod("p2", 600, "n2")},
+			wantReplaced:     []string{"n1", "n2"},
+			wantReplacements: 1,
+		},
+		{
+			name:            "two nodes replaced by a more expensive node",
+			nodes:           []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000)},
+			pods:            []*apiv1.Pod{consolidationPod("p1", 600, "n1"), consolidationPod("p2", 600, "n2")},
+			prices:          map[string]float64{"small": 1, "large": 3},
+			wantUnremovable: []string{"n1", "n2"},
+		},
+		{
+			name:             "two nodes replaced by a cheaper node",
+			nodes:            []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000)},
+			pods:             []*apiv1.Pod{consolidationPod("p1", 600, "n1"), consolidationPod("p2", 600, "n2")},
+			prices:           map[string]float64{"small": 1, "large": 1.5},
+			wantReplaced:     []string{"n1", "n2"},
+			wantReplacements: 1,
+		},
+		{
+			name:             "expensive node replaced by a cheaper node",
+			nodes:            []*apiv1.Node{consolidationNode("n1", "xlarge", 2000), consolidationNode("n2", "small", 1000)},
+			pods:             []*apiv1.Pod{consolidationPod("p1", 1500, "n1"), consolidationPod("p2", 900, "n2")},
+			prices:           map[string]float64{"small": 1, "large": 2, "xlarge": 4},
+			wantReplaced:     []string{"n1"},
+			wantReplacements: 1,
+			// Replacing n2 with a large node would be more expensive.
+			wantUnremovable: []string{"n2"},
+		},
+		{
+			name:            "replacement node group at max size",
+			nodes:           []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000)},
+			pods:            []*apiv1.Pod{consolidationPod("p1", 600, "n1"), consolidationPod("p2", 600, "n2")},
+			largeAtMaxSize:  true,
+			wantUnremovable: []string{"n1", "n2"},
+		},
+		{
+			name:  "limited number of nodes per replacement",
+			nodes: []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000), consolidationNode("n3", "small", 1000)},
+			pods: []*apiv1.Pod{
+				consolidationPod("p1", 600, "n1"),
+				consolidationPod("p2", 600, "n2"),
+				consolidationPod("p3", 600, "n3"),
+			},
+			maxNodes:         2,
+			wantReplaced:     []string{"n1", "n2"},
+			wantReplacements: 1,
+			wantUnremovable:  []string{"n3"},
+		},
+		{
+			name:  "each replacement fits two nodes",
+			nodes: []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000), consolidationNode("n3", "small", 1000), consolidationNode("n4", "small", 1000)},
+			pods: []*apiv1.Pod{
+				consolidationPod("p1", 900, "n1"),
+				consolidationPod("p2", 900, "n2"),
+				consolidationPod("p3", 900, "n3"),
+				consolidationPod("p4", 900, "n4"),
+			},
+			largeMaxSize:     2,
+			wantReplaced:     []string{"n1", "n2", "n3", "n4"},
+			wantReplacements: 2,
+		},
+		{
+			name:  "pdb allows a single disruption",
+			nodes: []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000)},
+			pods:  []*apiv1.Pod{consolidationPod("p1", 600, "n1"), consolidationPod("p2", 600, "n2")},
+			pdbs: []*policyv1.PodDisruptionBudget{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "pdb", Namespace: "default"},
+					Spec: policyv1.PodDisruptionBudgetSpec{
+						MinAvailable: &intstr.IntOrString{Type: intstr.Int, IntVal: 1},
+						Selector:     &metav1.LabelSelector{MatchLabels: map[string]string{"app": "consolidation"}},
+					},
+					Status: policyv1.PodDisruptionBudgetStatus{DisruptionsAllowed: 1},
+				},
+			},
+			wantUnremovable: []string{"n1", "n2"},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			largeMaxSize, largeSize := 10, 0
+			if tc.largeMaxSize != 0 {
+				largeMaxSize = tc.largeMaxSize
+			}
+			if tc.largeAtMaxSize {
+				largeSize = largeMaxSize
+			}
+			env := newConsolidationTestEnv(t, tc.nodes, tc.pods, tc.prices, largeMaxSize, largeSize, tc.maxNodes, !tc.disabled)
+			assert.NoError(t, env.autoscalingCtx.RemainingPdbTracker.SetPdbs(tc.pdbs))
+			assert.NoError(t, env.update(tc.nodes))
+			wantReplaced := asMap(tc.wantReplaced)
+			wantUnremovable := asMap(tc.wantUnremovable)
+			reasons := env.unremovableReasons()
+			for _, n := range tc.nodes {
+				assert.False(t, env.p.unneededNodes.Contains(n.Name), []string{n.Name, "unneeded"})
+				assert.Equal(t, wantReplaced[n.Name] || wantUnremovable[n.Name], env.p.unremovableNodes.Contains(n.Name), []string{n.Name, "unremovable"})
+				assert.Equal(t, wantReplaced[n.Name], reasons[n.Name] == simulator.ConsolidationInProgress, []string{n.Name, "replaced"})
+			}
+			replacements := env.p.ConsolidationReplacements()
+			assert.Len(t, replacements, tc.wantReplacements)
+			for _, replacement := range replacements {
+				assert.Equal(t, "large", replacement.NodeGroup.Id())
+			}
+			assert.Empty(t, env.scaleUps, "the planner doesn't scale up node groups itself")
+			nodeInfos, err := env.autoscalingCtx.ClusterSnapshot.ListNodeInfos()
+			assert.NoError(t, err)
+			assert.Len(t, nodeInfos, len(tc.nodes), "replacement nodes are not left in the snapshot")
+		})
+	}
+}
+
+func TestConsolidationWaitsForReplacement(t *testing.T) {
+	nodes := []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000)}
+	pods := []*apiv1.Pod{consolidationPod("p1", 600, "n1"), consolidationPod("p2", 600, "n2")}
+	env := newConsolidationTestEnv(t, nodes, pods, nil, 10, 0, 0, true)
+
+	// The replacement is requested, the nodes are kept until it is ready.
+	assert.NoError(t, env.update(nodes))
+	env.requestReplacements()
+	assert.Equal(t, 1, env.scaleUps["large"])
+	for _, name := range []string{"n1", "n2"} {
+		assert.False(t, env.p.unneededNodes.Contains(name), name)
+		assert.Equal(t, simulator.ConsolidationInProgress, env.unremovableReasons()[name], name)
+	}
+
+	// The replacement registers but isn't ready yet.
+	replacement := consolidationNode("large-1", "large", 2000)
+	SetNodeReadyState(replacement, false, time.Time{})
+	env.provider.AddNode("large", replacement)
+	nodes = append(nodes, replacement)
+	assert.NoError(t, env.autoscalingCtx.ClusterSnapshot.AddNodeInfo(framework.NewTestNodeInfo(replacement)))
+	assert.NoError(t, env.update(nodes))
+	assert.Empty(t, env.p.ConsolidationReplacements(), "no other replacement is planned")
+	for _, name := range []string{"n1", "n2"} {
+		assert.False(t, env.p.unneededNodes.Contains(name), name)
+		assert.Equal(t, simulator.ConsolidationInProgress, env.unremovableReasons()[name], name)
+	}
+
+	// Once the replacement is ready, the nodes can be drained and the
+	// replacement itself isn't removed.
+	replacement = consolidationNode("large-1", "large", 2000)
+	nodes[2] = replacement
+	assert.NoError(t, env.autoscalingCtx.ClusterSnapshot.RemoveNodeInfo("large-1"))
+	assert.NoError(t, env.autoscalingCtx.ClusterSnapshot.AddNodeInfo(framework.NewTestNodeInfo(replacement)))
+	assert.NoError(t, env.update(nodes))
+	assert.Empty(t, env.p.ConsolidationReplacements())
+	for _, name := range []string{"n1", "n2"} {
+		assert.True(t, env.p.unneededNodes.Contains(name), name)
+	}
+	assert.False(t, env.p.unneededNodes.Contains("large-1"))
+	assert.Equal(t, simulator.ConsolidationInProgress, env.unremovableReasons()["large-1"])
+}
+
+func TestConsolidationReplacementTimeout(t *testing.T) {
+	nodes := []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000)}
+	pods := []*apiv1.Pod{consolidationPod("p1", 600, "n1"), consolidationPod("p2", 600, "n2")}
+	env := newConsolidationTestEnv(t, nodes, pods, nil, 10, 0, 0, true)
+
+	assert.NoError(t, env.update(nodes))
+	env.requestReplacements()
+	assert.Equal(t, 1, env.scaleUps["large"])
+	assert.Len(t, env.p.consolidations, 1)
+
+	// The replacement didn't show up within the max node provision time, so
+	// the nodes aren't waiting for it anymore and may get consolidated again.
+	env.now = env.now.Add(time.Hour)
+	assert.NoError(t, env.update(nodes))
+	assert.Len(t, env.p.ConsolidationReplacements(), 1)
+	for _, name := range []string{"n1", "n2"} {
+		assert.False(t, env.p.unneededNodes.Contains(name), name)
+	}
+}
+
+func TestConsolidationReplacementNotRequested(t *testing.T) {
+	nodes := []*apiv1.Node{consolidationNode("n1", "small", 1000), consolidationNode("n2", "small", 1000)}
+	pods := []*apiv1.Pod{consolidationPod("p1", 600, "n1"), consolidationPod("p2", 600, "n2")}
+	env := newConsolidationTestEnv(t, nodes, pods, nil, 10, 0, 0, true)
+
+	assert.NoError(t, env.update(nodes))
+	assert.Len(t, env.p.ConsolidationReplacements(), 1)
+
+	// The replacement wasn't requested, e.g. because scale down was in
+	// cooldown, so the consolidation is planned anew.
+	assert.NoError(t, env.update(nodes))
+	assert.Len(t, env.p.consolidations, 1)
+	assert.Len(t, env.p.ConsolidationReplacements(), 1)
+	assert.Empty(t, env.scaleUps)
+	for _, name := range []string{"n1", "n2"} {
+		assert.Equal(t, simulator.ConsolidationInProgress, env.unremovableReasons()[name], name)
+	}
+}
+
+type consolidationTestEnv struct {
+	t              *testing.T
+	provider       *testprovider.TestCloudProvider
+	autoscalingCtx *ca_context.AutoscalingContext
+	p              *Planner
+	scaleUps       map[string]int
+	now            time.Time
+}
+
+func newConsolidationTestEnv(t *testing.T, nodes []*apiv1.Node, pods []*apiv1.Pod, prices map[string]float64, largeMaxSize, largeSize, maxNodes int, enabled bool) *consolidationTestEnv {
+	env := &consolidationTestEnv{t: t, scaleUps: map[string]int{}, now: time.Now()}
+	rsLister, err := kube_util.NewTestReplicaSetLister(generateReplicaSets("rs", 5))
+	assert.NoError(t, err)
+	registry := kube_util.NewListerRegistry(nil, nil, nil, nil, nil, nil, nil, rsLister, nil)
+	largeTemplate := framework.NewTestNodeInfo(consolidationNode("large-template", "large", 2000))
+	env.provider = testprovider.NewTestCloudProviderBuilder().WithMachineTemplates(map[string]*framework.NodeInfo{"large": largeTemplate}).WithOnScaleUp(func(id string, delta int) error {
+		env.scaleUps[id] += delta
+		return nil
+	}).Build()
+	env.provider.AddNodeGroup("small", 0, 10, 0)
+	env.provider.AddNodeGroup("large", 0, largeMaxSize, largeSize)
+	env.provider.AddNodeGroup("xlarge", 0, 0, 0)
+	for _, node := range nodes {
+		env.provider.AddNode(node.Labels[nodeTypeLabel], node)
+	}
+	if prices != nil {
+		env.provider.SetPricingModel(&fakePricingModel{prices: prices})
+	}
+	opts := config.AutoscalingOptions{
+		NodeGroupDefaults: config.NodeGroupAutoscalingOptions{
+			ScaleDownUnneededTime: 10 * time.Minute,
+			MaxNodeProvisionTime:  15 * time.Minute,
+		},
+		ScaleDownSimulationTimeout:     1 * time.Hour,
+		MaxScaleDownParallelism:        10,
+		ScaleDownConsolidationEnabled:  enabled,
+		ScaleDownConsolidationMaxNodes: maxNodes,
+	}
+	processors, templateNodeInfoRegistry := processorstest.NewTestProcessors(opts)
+	autoscalingCtx, err := NewScaleTestAutoscalingContext(opts, &fake.Clientset{}, registry, env.provider, nil, nil, templateNodeInfoRegistry)
+	assert.NoError(t, err)
+	env.autoscalingCtx = &autoscalingCtx
+	clustersnapshot.InitializeClusterSnapshotOrDie(t, autoscalingCtx.ClusterSnapshot, nodes, pods)
+	assert.NoError(t, templateNodeInfoRegistry.Recompute(env.autoscalingCtx, nodes, nil, taints.TaintConfig{}, env.now))
+	factory := resourcequotas.NewTrackerFactory(resourcequotas.TrackerOptions{CustomResourcesProcessor: processors.CustomResourcesProcessor, QuotaProvider: resourcequotas.NewCloudMinProvider(env.provider)})
+	env.p = New(env.autoscalingCtx, processors, options.NodeDeleteOptions{}, nil, factory)
+	return env
+}
+
+// update runs a planner loop, with all the given nodes as candidates.
+func (e *consolidationTestEnv) update(nodes []*apiv1.Node) error {
+	e.p.eligibilityChecker = &fakeEligibilityChecker{eligible: asMap(nodeNames(nodes))}
+	e.now = e.now.Add(time.Minute)
+	return e.p.UpdateClusterState(nodes, nodes, &fakeActuationStatus{}, e.now)
+}
+
+// requestReplacements scales up the node groups of the planned replacements,
+// as the autoscaler does when scale down isn't in cooldown.
+func (e *consolidationTestEnv) requestReplacements() {
+	for _, replacement := range e.p.ConsolidationReplacements() {
+		assert.NoError(e.t, replacement.NodeGroup.IncreaseSize(1))
+		e.p.ConsolidationReplacementRequested(replacement, e.now)
+	}
+}
+
+func (e *consolidationTestEnv) unremovableReasons() map[string]simulator.UnremovableReason {
+	reasons := map[string]simulator.UnremovableReason{}
+	for _, node := range e.p.UnremovableNodes() {
+		reasons[node.Node.Name] = node.Reason
+	}
+	return reasons
+}
+
+func consolidationNode(name, nodeType string, cpu int64) *apiv1.Node {
+	node := BuildTestNode(name, cpu, 10)
+	node.Labels[nodeTypeLabel] = nodeType
+	SetNodeReadyState(node, true, time.Time{})
+	return node
+}
+
+func consolidationPod(name string, cpu int64, nodeName string) *apiv1.Pod {
+	pod := SetRSPodSpec(BuildScheduledTestPod(name, cpu, 1, nodeName), "rs")
+	pod.Labels = map[string]string{"app": "consolidation"}
+	return pod
+}
@@ -78,6 +78,7 @@ type Planner struct {
 	scaleDownSetProcessor nodes.ScaleDownSetProcessor
 	scaleDownContext      *nodes.ScaleDownContext
 	maxNodeSkipEvalTime   *nodeevaltracker.MaxNodeSkipEvalTime
+	consolidations        []*consolidation
 }
 
 // New creates a new Planner object.
@@ -289,6 +290,8 @@ func (p *Planner) categorizeNodes(podDestinations map[string]bool, scaleDownCand
 	var removableList []simulator.NodeToBeRemoved
 	atomicScaleDownNodesCount := 0
 	p.unremovableNodes.Update(p.autoscalingCtx.ClusterSnapshot, p.latestUpdate)
+	p.updateConsolidations()
+	scaleDownCandidates = p.filterOutConsolidating(scaleDownCandidates)
 	currentlyUnneededNodeNames, utilizationMap, ineligible := p.eligibilityChecker.FilterOutUnremovable(p.autoscalingCtx, scaleDownCandidates, p.latestUpdate, p.unremovableNodes)
 	for _, n := range ineligible {
 		p.unremovableNodes.Add(n)
@@ -296,6 +299,7 @@ func (p *Planner) categorizeNodes(podDestinations map[string]bool, scaleDownCand
 	p.nodeUtilizationMap = utilizationMap
 	timer := time.NewTimer(p.autoscalingCtx.ScaleDownSimulationTimeout)
 	var skippedNodes []string
+	var consolidationCandidates []*simulator.UnremovableNode
 
 	for i, node := range currentlyUnneededNodeNames {
 		if timedOut(timer) {
@@ -324,12 +328,31 @@ func (p *Planner) categorizeNodes(podDestinations map[string]bool, scaleDownCand
 			}
 		}
 		if unremovable != nil {
+			if p.autoscalingCtx.AutoscalingOptions.ScaleDownConsolidationEnabled && unremovable.Reason == simulator.NoPlaceToMovePods {
+				consolidationCandidates = append(consolidationCandidates, unremovable)
+				continue
+			}
+			unremovableCount += 1
+			p.unremovableNodes.AddTimeout(unremovable, unremovableTimeout)
+		}
+	}
+	if len(consolidationCandidates) > 0 {
+		// Consolidation is only attempted when all candidates were simulated.
+		if len(skippedNodes) == 0 {
+			consolidationCandidates = p.consolidate(podDestinations, consolidationCandidates, timer, p.unneededNodesLimit()-len(removableList)+atomicScaleDownNodesCount)
+		}
+		for _, unremovable := range consolidationCandidates {
+			if unremovable.Reason == simulator.ConsolidationInProgress {
+				p.unremovableNodes.Add(unremovable)
+				continue
+			}
 			unremovableCount += 1
 			p.unremovableNodes.AddTimeout(unremovable, unremovableTimeout)
 		}
 	}
 	p.handleUnprocessedNodes(skippedNodes)
 	p.unneededNodes.Update(p.autoscalingCtx, removableList, p.latestUpdate)
+	p.dropAbandonedConsolidations()
 	if unremovableCount > 0 {
 		klog.V(1).Infof("%v nodes found to be unremovable in simulation, will re-check them at %v", unremovableCount, unremovableTimeout)
 	}
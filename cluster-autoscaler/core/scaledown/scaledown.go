@@ -19,6 +19,7 @@ package scaledown
 import (
 	"time"
 
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaledown/status"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization"
@@ -47,6 +48,15 @@ type Planner interface {
 	// NodeUtilizationMap returns information about utilization of
 	// individual cluster nodes.
 	NodeUtilizationMap() map[string]utilization.Info
+	// ConsolidationReplacements returns the nodes to add to replace the nodes
+	// of the consolidations planned by the last UpdateClusterState call. The
+	// replaced nodes are only removed once their replacement is ready.
+	// Replacements that aren't requested before the next UpdateClusterState
+	// call are forgotten.
+	ConsolidationReplacements() []*ConsolidationReplacement
+	// ConsolidationReplacementRequested informs the Planner that the node
+	// group of the replacement was scaled up.
+	ConsolidationReplacementRequested(replacement *ConsolidationReplacement, currentTime time.Time)
 }
 
 // Actuator is responsible for making changes in the cluster: draining and
@@ -85,6 +95,16 @@ type ActuationStatus interface {
 	RecentEvictions() (pods []*apiv1.Pod)
 }
 
+// ConsolidationReplacement is a node to add to a node group, so that the
+// pods of the consolidated nodes can move to it.
+type ConsolidationReplacement struct {
+	// NodeGroup is the node group to scale up by one node.
+	NodeGroup cloudprovider.NodeGroup
+	// Nodes are the names of the nodes that are removed once the
+	// replacement is ready.
+	Nodes []string
+}
+
 // UnneededNode represents a node that has been identified as a candidate for scale-down,
 // paired with the specific duration it must remain in this state before deletion is triggered.
 type UnneededNode struct {
@@ -17,6 +17,7 @@ limitations under the License.
 package orchestrator
 
 import (
+	goerrors "errors"
 	"fmt"
 	"sync"
 	"time"
@@ -170,11 +171,16 @@ func (e *scaleUpExecutor) executeScaleUp(
 	if err := e.increaseSize(info.Group, increase, atomic); err != nil {
 		e.autoscalingCtx.LogRecorder.Eventf(apiv1.EventTypeWarning, "FailedToScaleUpGroup", "Scale-up failed for group %s: %v", info.Group.Id(), err)
 		aerr := errors.ToAutoscalerError(errors.CloudProviderError, err).AddPrefix("failed to increase node group size: ")
-		e.scaleStateNotifier.RegisterFailedScaleUp(info.Group, increase, cloudprovider.InstanceErrorInfo{
+		errorInfo := cloudprovider.InstanceErrorInfo{
 			ErrorClass:   cloudprovider.OtherErrorClass,
 			ErrorCode:    string(aerr.Type()),
 			ErrorMessage: aerr.Error(),
-		}, now)
+		}
+		var scaleUpErr *cloudprovider.ScaleUpError
+		if goerrors.As(err, &scaleUpErr) {
+			errorInfo = scaleUpErr.ErrorInfo
+		}
+		e.scaleStateNotifier.RegisterFailedScaleUp(info.Group, increase, errorInfo, now)
 		return aerr
 	}
 	if increase < 0 {
@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/config"
+	. "k8s.io/autoscaler/cluster-autoscaler/core/test"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroups/asyncnodegroups"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type failedScaleUpRecorder struct {
+	errorInfos []cloudprovider.InstanceErrorInfo
+}
+
+func (r *failedScaleUpRecorder) RegisterScaleUp(cloudprovider.NodeGroup, int, time.Time) {}
+
+func (r *failedScaleUpRecorder) RegisterScaleDown(cloudprovider.NodeGroup, string, time.Time, time.Time) {
+}
+
+func (r *failedScaleUpRecorder) RegisterFailedScaleUp(_ cloudprovider.NodeGroup, _ int, errorInfo cloudprovider.InstanceErrorInfo, _ time.Time) {
+	r.errorInfos = append(r.errorInfos, errorInfo)
+}
+
+func (r *failedScaleUpRecorder) RegisterFailedScaleDown(cloudprovider.NodeGroup, string, time.Time) {}
+
+func TestExecuteScaleUpFailureErrorInfo(t *testing.T) {
+	quotaErrorInfo := cloudprovider.InstanceErrorInfo{
+		ErrorClass:   cloudprovider.OutOfResourcesErrorClass,
+		ErrorCode:    "QuotaExceeded",
+		ErrorMessage: "quota exceeded",
+	}
+	testCases := []struct {
+		name              string
+		scaleUpErr        error
+		wantErrorInfo     cloudprovider.InstanceErrorInfo
+		wantScaleUpErrMsg string
+	}{
+		{
+			name:       "generic error",
+			scaleUpErr: fmt.Errorf("auth error"),
+			wantErrorInfo: cloudprovider.InstanceErrorInfo{
+				ErrorClass:   cloudprovider.OtherErrorClass,
+				ErrorCode:    string(errors.CloudProviderError),
+				ErrorMessage: "failed to increase node group size: auth error",
+			},
+			wantScaleUpErrMsg: "failed to increase node group size: auth error",
+		},
+		{
+			name:              "scale-up error with error info",
+			scaleUpErr:        fmt.Errorf("wrapped: %w", &cloudprovider.ScaleUpError{ErrorInfo: quotaErrorInfo}),
+			wantErrorInfo:     quotaErrorInfo,
+			wantScaleUpErrMsg: "failed to increase node group size: wrapped: QuotaExceeded: quota exceeded",
+		},
+	}
+	for _, tc := range testCases {
+		for _, atomic := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s, atomic=%v", tc.name, atomic), func(t *testing.T) {
+				provider := testprovider.NewTestCloudProviderBuilder().WithOnScaleUp(func(string, int) error {
+					return tc.scaleUpErr
+				}).Build()
+				provider.AddNodeGroup("ng1", 0, 10, 1)
+				nodeGroup := provider.GetNodeGroup("ng1")
+				node := BuildTestNode("ng1-template", 1000, 1000)
+
+				autoscalingCtx, err := NewScaleTestAutoscalingContext(config.AutoscalingOptions{}, &fake.Clientset{}, kube_util.NewListerRegistry(nil, nil, nil, nil, nil, nil, nil, nil, nil), provider, nil, nil, nil)
+				assert.NoError(t, err)
+				recorder := &failedScaleUpRecorder{}
+				executor := newScaleUpExecutor(&autoscalingCtx, recorder, asyncnodegroups.NewDefaultAsyncNodeGroupStateChecker())
+
+				scaleUpErr, failedGroups := executor.ExecuteScaleUps(
+					[]nodegroupset.ScaleUpInfo{{Group: nodeGroup, CurrentSize: 1, NewSize: 3, MaxSize: 10}},
+					map[string]*framework.NodeInfo{"ng1": framework.NewTestNodeInfo(node)},
+					time.Now(), atomic)
+
+				assert.Equal(t, errors.CloudProviderError, scaleUpErr.Type())
+				assert.Equal(t, tc.wantScaleUpErrMsg, scaleUpErr.Error())
+				assert.Len(t, failedGroups, 1)
+				assert.Equal(t, []cloudprovider.InstanceErrorInfo{tc.wantErrorInfo}, recorder.errorInfos)
+			})
+		}
+	}
+}
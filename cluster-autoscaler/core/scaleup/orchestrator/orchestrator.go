@@ -19,6 +19,7 @@ package orchestrator
 import (
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -37,6 +38,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
 	"k8s.io/autoscaler/cluster-autoscaler/resourcequotas"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/klogx"
@@ -175,6 +177,9 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 
 	// Execute scale up.
 	klog.V(1).Infof("Final scale-up plan: %v", plan.scaleUpInfos)
+	if len(plan.nodesPerZone) > 0 {
+		klog.V(1).Infof("Nodes requested per zone: %v", plan.nodesPerZone)
+	}
 	aErr, failedNodeGroups := o.scaleUpExecutor.ExecuteScaleUps(plan.scaleUpInfos, nodeInfos, now, allOrNothing)
 	if aErr != nil {
 		failedGroupsMap := o.buildFailedGroupsMap(failedNodeGroups, plan.scaleUpInfos)
@@ -185,6 +190,7 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 				FailedResizeNodeGroups:  failedNodeGroups,
 				PodsTriggeredScaleUp:    plan.bestOption.Pods,
 				PodsRemainUnschedulable: o.GetRemainingPods(markedEquivalenceGroups, plan.nodeGroups, skippedNodeGroups, nodeInfos),
+				NodesPerZone:            plan.nodesPerZone,
 			},
 			aErr,
 		)
@@ -199,6 +205,7 @@ func (o *ScaleUpOrchestrator) ScaleUp(
 		CreateNodeGroupResults:  plan.createNodeGroupResults,
 		PodsTriggeredScaleUp:    plan.bestOption.Pods,
 		PodsAwaitEvaluation:     GetPodsAwaitingEvaluation(podEquivalenceGroups, plan.bestOption.NodeGroup.Id()),
+		NodesPerZone:            plan.nodesPerZone,
 	}, nil
 }
 
@@ -317,6 +324,82 @@ func (o *ScaleUpOrchestrator) ScaleUpToNodeGroupMinSize(
 	}, nil
 }
 
+// ScaleUpNodeGroup tries to add the given number of nodes to the node group,
+// e.g. to replace nodes consolidated by scale down. The node group must be
+// safe to scale up, and the nodes must fit within its max size, the
+// cluster-wide node count limit and the resource quotas, otherwise no node is
+// added. Returns appropriate status or error if an unexpected error occurred.
+func (o *ScaleUpOrchestrator) ScaleUpNodeGroup(
+	nodeGroup cloudprovider.NodeGroup,
+	newNodes int,
+	nodes []*apiv1.Node,
+	nodeInfos map[string]*framework.NodeInfo,
+) (*status.ScaleUpStatus, errors.AutoscalerError) {
+	if !o.initialized {
+		return status.UpdateScaleUpError(&status.ScaleUpStatus{}, errors.NewAutoscalerError(errors.InternalError, "ScaleUpOrchestrator is not initialized"))
+	}
+
+	now := time.Now()
+	noOptions := &status.ScaleUpStatus{Result: status.ScaleUpNoOptionsAvailable, ConsideredNodeGroups: []cloudprovider.NodeGroup{nodeGroup}}
+	if skipReason := o.IsNodeGroupReadyToScaleUp(nodeGroup, now); skipReason != nil {
+		klog.V(2).Infof("ScaleUpNodeGroup: node group %s is not ready to scale up: %v", nodeGroup.Id(), skipReason)
+		return noOptions, nil
+	}
+	nodeInfo, found := nodeInfos[nodeGroup.Id()]
+	if !found {
+		return status.UpdateScaleUpError(&status.ScaleUpStatus{}, errors.NewAutoscalerErrorf(errors.InternalError, "failed to find template node for node group %s", nodeGroup.Id()))
+	}
+	targetSize, err := nodeGroup.TargetSize()
+	if err != nil {
+		return status.UpdateScaleUpError(&status.ScaleUpStatus{}, errors.ToAutoscalerError(errors.CloudProviderError, err).AddPrefix("failed to get target size of node group %s: ", nodeGroup.Id()))
+	}
+	if targetSize+newNodes > nodeGroup.MaxSize() {
+		klog.V(2).Infof("ScaleUpNodeGroup: node group %s can't be scaled up by %d nodes, max size reached", nodeGroup.Id(), newNodes)
+		return noOptions, nil
+	}
+
+	tracker, err := o.quotasTrackerFactory.NewQuotasTracker(o.autoscalingCtx, nodes)
+	if err != nil {
+		return status.UpdateScaleUpError(&status.ScaleUpStatus{}, errors.ToAutoscalerError(errors.InternalError, err).AddPrefix("could not create quotas tracker: "))
+	}
+	if skipReason := o.IsNodeGroupResourceExceeded(tracker, nodeGroup, nodeInfo, newNodes); skipReason != nil {
+		klog.V(2).Infof("ScaleUpNodeGroup: node group %s can't be scaled up by %d nodes: %v", nodeGroup.Id(), newNodes, skipReason.Reasons())
+		return noOptions, nil
+	}
+	upcomingNodes, aErr := o.UpcomingNodes(nodeInfos)
+	if aErr != nil {
+		return status.UpdateScaleUpError(&status.ScaleUpStatus{}, aErr.AddPrefix("could not get upcoming nodes: "))
+	}
+	if cappedNewNodes, err := o.GetCappedNewNodeCount(newNodes, len(nodes)+len(upcomingNodes)); err != nil || cappedNewNodes < newNodes {
+		klog.V(2).Infof("ScaleUpNodeGroup: node group %s can't be scaled up by %d nodes, max total nodes in cluster reached", nodeGroup.Id(), newNodes)
+		return &status.ScaleUpStatus{Result: status.ScaleUpLimitedByMaxNodesTotal, ConsideredNodeGroups: []cloudprovider.NodeGroup{nodeGroup}}, nil
+	}
+
+	scaleUpInfos := []nodegroupset.ScaleUpInfo{{
+		Group:       nodeGroup,
+		CurrentSize: targetSize,
+		NewSize:     targetSize + newNodes,
+		MaxSize:     nodeGroup.MaxSize(),
+	}}
+	klog.V(1).Infof("ScaleUpNodeGroup: final scale-up plan: %v", scaleUpInfos)
+	aErr, failedNodeGroups := o.scaleUpExecutor.ExecuteScaleUps(scaleUpInfos, nodeInfos, now, false /* allOrNothing disabled */)
+	if aErr != nil {
+		return status.UpdateScaleUpError(
+			&status.ScaleUpStatus{
+				FailedResizeNodeGroups: failedNodeGroups,
+			},
+			aErr,
+		)
+	}
+
+	o.clusterStateRegistry.Recalculate()
+	return &status.ScaleUpStatus{
+		Result:               status.ScaleUpSuccessful,
+		ScaleUpInfos:         scaleUpInfos,
+		ConsideredNodeGroups: []cloudprovider.NodeGroup{nodeGroup},
+	}, nil
+}
+
 // filterValidScaleUpNodeGroups filters the node groups that are valid for scale-up
 func (o *ScaleUpOrchestrator) filterValidScaleUpNodeGroups(
 	nodeGroups []cloudprovider.NodeGroup,
@@ -384,13 +467,28 @@ func (o *ScaleUpOrchestrator) ComputeExpansionOption(
 	now time.Time,
 	allOrNothing bool,
 ) expander.Option {
-	option := expander.Option{NodeGroup: nodeGroup}
+	option := o.newExpansionOption(nodeGroup, schedulablePodGroups, nodeInfos, now)
 	podGroups := schedulablePodGroups[nodeGroup.Id()]
-	nodeInfo := nodeInfos[nodeGroup.Id()]
-
 	if len(podGroups) == 0 {
 		return option
 	}
+	templates := o.zoneSpreadTemplates(&option, podGroups, nodeInfos)
+	o.estimateExpansionOption(o.autoscalingCtx.ClusterSnapshot, &option, podGroups, nodeInfos[nodeGroup.Id()], templates, currentNodeCount)
+	o.capExpansionOption(&option, allOrNothing)
+	return option
+}
+
+// newExpansionOption returns an expansion option for the node group with its similar node groups, before estimation.
+func (o *ScaleUpOrchestrator) newExpansionOption(
+	nodeGroup cloudprovider.NodeGroup,
+	schedulablePodGroups map[string][]estimator.PodEquivalenceGroup,
+	nodeInfos map[string]*framework.NodeInfo,
+	now time.Time,
+) expander.Option {
+	option := expander.Option{NodeGroup: nodeGroup}
+	if len(schedulablePodGroups[nodeGroup.Id()]) == 0 {
+		return option
+	}
 
 	option.SimilarNodeGroups = o.ComputeSimilarNodeGroups(nodeGroup, nodeInfos, schedulablePodGroups, now)
 	if option.SimilarNodeGroups != nil {
@@ -404,15 +502,43 @@ func (o *ScaleUpOrchestrator) ComputeExpansionOption(
 		// if no similar node groups are found and the flag is enabled, log about it
 		klog.V(5).Info("No similar node groups found")
 	}
+	return option
+}
 
+// estimateExpansionOption estimates the number of nodes of the option and the pods they can accommodate, using
+// the given snapshot. If templates are given, the nodes are split between their node groups when the estimator
+// supports it. It can run concurrently with other estimations as long as they use independent snapshots.
+func (o *ScaleUpOrchestrator) estimateExpansionOption(
+	snapshot clustersnapshot.ClusterSnapshot,
+	option *expander.Option,
+	podGroups []estimator.PodEquivalenceGroup,
+	nodeInfo *framework.NodeInfo,
+	templates []estimator.NodeGroupTemplate,
+	currentNodeCount int,
+) {
 	estimateStart := time.Now()
+	defer metrics.UpdateDurationFromStart(metrics.Estimate, estimateStart)
 	expansionEstimator := o.estimatorBuilder(
-		o.autoscalingCtx.ClusterSnapshot,
+		snapshot,
 		estimator.NewEstimationContext(o.autoscalingCtx.MaxNodesTotal, option.SimilarNodeGroups, currentNodeCount),
 	)
-	option.NodeCount, option.Pods = expansionEstimator.Estimate(podGroups, nodeInfo, nodeGroup)
-	metrics.UpdateDurationFromStart(metrics.Estimate, estimateStart)
+	if len(templates) > 0 {
+		if multiNodeGroupEstimator, ok := expansionEstimator.(estimator.MultiNodeGroupEstimator); ok {
+			option.SplitNodeCounts, option.Pods = multiNodeGroupEstimator.EstimateNodeGroups(podGroups, templates)
+			option.NodeCount = 0
+			for _, nodeCount := range option.SplitNodeCounts {
+				option.NodeCount += nodeCount
+			}
+			return
+		}
+		klog.V(4).Infof("Estimator %T can't split scale-ups between node groups, estimating %s alone", expansionEstimator, option.NodeGroup.Id())
+	}
+	option.NodeCount, option.Pods = expansionEstimator.Estimate(podGroups, nodeInfo, option.NodeGroup)
+}
 
+// capExpansionOption adjusts the node count of an estimated option to the constraints of its node group.
+func (o *ScaleUpOrchestrator) capExpansionOption(option *expander.Option, allOrNothing bool) {
+	nodeGroup := option.NodeGroup
 	autoscalingOptions, err := nodeGroup.GetOptions(o.autoscalingCtx.NodeGroupDefaults)
 	if err != nil && err != cloudprovider.ErrNotImplemented {
 		klog.Errorf("Failed to get autoscaling options for node group %s: %v", nodeGroup.Id(), err)
@@ -432,8 +558,79 @@ func (o *ScaleUpOrchestrator) ComputeExpansionOption(
 			option.NodeCount = nodeGroup.MaxSize()
 		}
 	}
+}
 
-	return option
+// computeExpansionOptions computes the expansion options of the node groups, in the same order. Without
+// snapshots, the options are computed one after another on the cluster snapshot. Otherwise, the estimations
+// run concurrently, one per snapshot at a time, while the rest of the computation is sequential.
+func (o *ScaleUpOrchestrator) computeExpansionOptions(
+	snapshots []clustersnapshot.ClusterSnapshot,
+	nodeGroups []cloudprovider.NodeGroup,
+	schedulablePodGroups map[string][]estimator.PodEquivalenceGroup,
+	nodeInfos map[string]*framework.NodeInfo,
+	currentNodeCount int,
+	now time.Time,
+	allOrNothing bool,
+) []expander.Option {
+	options := make([]expander.Option, len(nodeGroups))
+	if len(snapshots) == 0 {
+		for i, nodeGroup := range nodeGroups {
+			options[i] = o.ComputeExpansionOption(nodeGroup, schedulablePodGroups, nodeInfos, currentNodeCount, now, allOrNothing)
+		}
+		return options
+	}
+
+	templates := make([][]estimator.NodeGroupTemplate, len(nodeGroups))
+	for i, nodeGroup := range nodeGroups {
+		options[i] = o.newExpansionOption(nodeGroup, schedulablePodGroups, nodeInfos, now)
+		templates[i] = o.zoneSpreadTemplates(&options[i], schedulablePodGroups[nodeGroup.Id()], nodeInfos)
+	}
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for _, snapshot := range snapshots {
+		wg.Add(1)
+		go func(snapshot clustersnapshot.ClusterSnapshot) {
+			defer wg.Done()
+			for i := range indices {
+				id := nodeGroups[i].Id()
+				o.estimateExpansionOption(snapshot, &options[i], schedulablePodGroups[id], nodeInfos[id], templates[i], currentNodeCount)
+			}
+		}(snapshot)
+	}
+	for i, nodeGroup := range nodeGroups {
+		if len(schedulablePodGroups[nodeGroup.Id()]) > 0 {
+			indices <- i
+		}
+	}
+	close(indices)
+	wg.Wait()
+
+	for i, nodeGroup := range nodeGroups {
+		if len(schedulablePodGroups[nodeGroup.Id()]) > 0 {
+			o.capExpansionOption(&options[i], allOrNothing)
+		}
+	}
+	return options
+}
+
+// estimationSnapshots returns independent forks of the cluster snapshot to run estimations concurrently, or nil
+// if the estimations should run sequentially on the cluster snapshot.
+func (o *ScaleUpOrchestrator) estimationSnapshots(nodeGroupCount int) []clustersnapshot.ClusterSnapshot {
+	parallelism := min(o.autoscalingCtx.ScaleUpEstimationParallelism, nodeGroupCount)
+	if parallelism <= 1 {
+		return nil
+	}
+	forkable, ok := o.autoscalingCtx.ClusterSnapshot.(clustersnapshot.IndependentForkable)
+	if !ok {
+		klog.V(4).Infof("Cluster snapshot %T doesn't support independent forks, estimating node groups sequentially", o.autoscalingCtx.ClusterSnapshot)
+		return nil
+	}
+	snapshots, err := forkable.ForkIndependent(parallelism)
+	if err != nil {
+		klog.V(4).Infof("Failed to fork the cluster snapshot, estimating node groups sequentially: %v", err)
+		return nil
+	}
+	return snapshots
 }
 
 // CreateNodeGroup will try to create a new node group based on the initialOption.
@@ -1038,6 +1235,7 @@ type scaleUpPlan struct {
 	createNodeGroupResults []nodegroups.CreateNodeGroupResult
 	bestOption             *expander.Option
 	nodeGroups             []cloudprovider.NodeGroup
+	nodesPerZone           map[string]int
 }
 
 func (o *ScaleUpOrchestrator) prepareScaleUp(args scaleUpCtx) (scaleUpPlan, *status.ScaleUpStatus, errors.AutoscalerError) {
@@ -1050,20 +1248,29 @@ func (o *ScaleUpOrchestrator) prepareScaleUp(args scaleUpCtx) (scaleUpPlan, *sta
 		schedulablePodGroups[nodeGroup.Id()] = o.SchedulablePodGroups(args.podEquivalenceGroups, nodeGroup, args.nodeInfos[nodeGroup.Id()])
 	}
 
-	for _, nodeGroup := range args.validNodeGroups {
-		option := o.ComputeExpansionOption(nodeGroup, schedulablePodGroups, args.nodeInfos, len(args.nodes), args.now, args.allOrNothing)
-		o.processors.BinpackingLimiter.MarkProcessed(o.autoscalingCtx, nodeGroup.Id())
-
-		if len(option.Pods) == 0 || option.NodeCount == 0 {
-			klog.V(4).Infof("No pod can fit to %s", nodeGroup.Id())
-		} else if args.allOrNothing && len(option.Pods) < len(args.unschedulablePods) {
-			klog.V(4).Infof("Some pods can't fit to %s, giving up due to all-or-nothing scale-up strategy", nodeGroup.Id())
-		} else {
-			options = append(options, option)
-		}
+	// Node groups are estimated in batches, one per estimation snapshot. The binpacking limiter sees the options
+	// of a batch in the node groups order, so the result doesn't depend on which estimation finishes first.
+	snapshots := o.estimationSnapshots(len(args.validNodeGroups))
+	batchSize := max(len(snapshots), 1)
+binpacking:
+	for start := 0; start < len(args.validNodeGroups); start += batchSize {
+		batch := args.validNodeGroups[start:min(start+batchSize, len(args.validNodeGroups))]
+		batchOptions := o.computeExpansionOptions(snapshots, batch, schedulablePodGroups, args.nodeInfos, len(args.nodes), args.now, args.allOrNothing)
+		for i, nodeGroup := range batch {
+			option := batchOptions[i]
+			o.processors.BinpackingLimiter.MarkProcessed(o.autoscalingCtx, nodeGroup.Id())
+
+			if len(option.Pods) == 0 || option.NodeCount == 0 {
+				klog.V(4).Infof("No pod can fit to %s", nodeGroup.Id())
+			} else if args.allOrNothing && len(option.Pods) < len(args.unschedulablePods) {
+				klog.V(4).Infof("Some pods can't fit to %s, giving up due to all-or-nothing scale-up strategy", nodeGroup.Id())
+			} else {
+				options = append(options, option)
+			}
 
-		if o.processors.BinpackingLimiter.StopBinpacking(o.autoscalingCtx, options) {
-			break
+			if o.processors.BinpackingLimiter.StopBinpacking(o.autoscalingCtx, options) {
+				break binpacking
+			}
 		}
 	}
 
@@ -1143,7 +1350,17 @@ func (o *ScaleUpOrchestrator) prepareScaleUp(args scaleUpCtx) (scaleUpPlan, *sta
 		args.nodeGroups = appendCreatedNodeGroups(args.nodeGroups, oldId, createNodeGroupResults)
 	}
 
-	scaleUpInfos, aErr := o.balanceScaleUps(args.now, bestOption.NodeGroup, newNodes, args.nodeInfos, schedulablePodGroups, args.tracker)
+	var scaleUpInfos []nodegroupset.ScaleUpInfo
+	var zoneNodeCounts map[string]int
+	if len(bestOption.SplitNodeCounts) > 0 && newNodes == bestOption.NodeCount {
+		scaleUpInfos, aErr = o.zoneSpreadScaleUps(bestOption.SplitNodeCounts, args.nodeGroups, args.nodeInfos, args.tracker)
+		zoneNodeCounts = nodesPerZone(scaleUpInfos, args.nodeInfos)
+	} else {
+		if len(bestOption.SplitNodeCounts) > 0 {
+			klog.V(1).Infof("Not splitting scale-up of %d nodes between zones, balancing %d nodes instead", bestOption.NodeCount, newNodes)
+		}
+		scaleUpInfos, aErr = o.balanceScaleUps(args.now, bestOption.NodeGroup, newNodes, args.nodeInfos, schedulablePodGroups, args.tracker)
+	}
 	if aErr != nil {
 		markedEquivalenceGroups := markAllGroupsAsUnschedulable(args.podEquivalenceGroups, ScaleUpExecutionErrorReason)
 		st, err := status.UpdateScaleUpError(
@@ -1174,5 +1391,6 @@ func (o *ScaleUpOrchestrator) prepareScaleUp(args scaleUpCtx) (scaleUpPlan, *sta
 		createNodeGroupResults: createNodeGroupResults,
 		bestOption:             bestOption,
 		nodeGroups:             args.nodeGroups,
+		nodesPerZone:           zoneNodeCounts,
 	}, nil, nil
 }
@@ -49,6 +49,8 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/processors/status"
 	processorstest "k8s.io/autoscaler/cluster-autoscaler/processors/test"
 	"k8s.io/autoscaler/cluster-autoscaler/resourcequotas"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/predicate"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/store"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
 	kube_util "k8s.io/autoscaler/cluster-autoscaler/utils/kubernetes"
@@ -1419,6 +1421,80 @@ func TestBinpackingLimiter(t *testing.T) {
 	assert.True(t, len(expansionOptions) == 1)
 }
 
+func TestParallelEstimation(t *testing.T) {
+	runScaleUp := func(t *testing.T, parallelism int, binpackingLimiter bool) []GroupSizeChange {
+		now := time.Now()
+		provider := testprovider.NewTestCloudProviderBuilder().WithOnScaleUp(func(nodeGroup string, increase int) error {
+			return nil
+		}).Build()
+		var nodes []*apiv1.Node
+		for i := 1; i <= 6; i++ {
+			node := BuildTestNode(fmt.Sprintf("n%d", i), int64(i)*1000, 1000)
+			SetNodeReadyState(node, true, now.Add(-2*time.Minute))
+			nodes = append(nodes, node)
+			provider.AddNodeGroup(fmt.Sprintf("ng%d", i), 1, 100, 1)
+			provider.AddNode(fmt.Sprintf("ng%d", i), node)
+		}
+		var pods []*apiv1.Pod
+		for i := 0; i < 10; i++ {
+			pods = append(pods, BuildTestPod(fmt.Sprintf("p%d", i), 900, 0))
+		}
+
+		options := defaultOptions
+		options.ScaleUpEstimationParallelism = parallelism
+		listers := kube_util.NewListerRegistry(nil, nil, kube_util.NewTestPodLister(nil), nil, nil, nil, nil, nil, nil)
+		processors, templateNodeInfoRegistry := processorstest.NewTestProcessors(options)
+		if binpackingLimiter {
+			processors.BinpackingLimiter = &MockBinpackingLimiter{}
+		}
+		autoscalingCtx, err := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, listers, provider, nil, nil, templateNodeInfoRegistry)
+		assert.NoError(t, err)
+		// The default test snapshot doesn't support independent forks.
+		fwHandle, err := framework.NewTestFrameworkHandle()
+		assert.NoError(t, err)
+		autoscalingCtx.FrameworkHandle = fwHandle
+		autoscalingCtx.ClusterSnapshot = predicate.NewPredicateSnapshot(store.NewDeltaSnapshotStore(), fwHandle, false, 1, false)
+		assert.NoError(t, autoscalingCtx.ClusterSnapshot.SetClusterState(nodes, nil, nil, nil))
+		assert.NoError(t, autoscalingCtx.TemplateNodeInfoRegistry.Recompute(&autoscalingCtx, nodes, []*appsv1.DaemonSet{}, taints.TaintConfig{}, now))
+		nodeInfos := autoscalingCtx.TemplateNodeInfoRegistry.GetNodeInfos()
+
+		clusterState := clusterstate.NewClusterStateRegistry(provider, autoscalingCtx.LogRecorder, NewBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute}), autoscalingCtx.TemplateNodeInfoRegistry, clusterstate.WithScaleStateNotifier(processors.ScaleStateNotifier))
+		clusterState.UpdateNodes(nodes, now)
+		trackerFactory := resourcequotas.NewTrackerFactory(resourcequotas.TrackerOptions{
+			QuotaProvider:            resourcequotas.NewCloudQuotasProvider(provider),
+			CustomResourcesProcessor: processors.CustomResourcesProcessor,
+		})
+		suOrchestrator := New()
+		suOrchestrator.Initialize(&autoscalingCtx, processors, clusterState, newEstimatorBuilder(), taints.TaintConfig{}, trackerFactory)
+		expander := NewMockReportingStrategy(t, nil, nil)
+		autoscalingCtx.ExpanderStrategy = expander
+
+		scaleUpStatus, err := suOrchestrator.ScaleUp(pods, nodes, []*appsv1.DaemonSet{}, nodeInfos, false)
+		assert.NoError(t, err)
+		assert.True(t, scaleUpStatus.WasSuccessful())
+		return expander.LastInputOptions()
+	}
+
+	sequential := runScaleUp(t, 1, false)
+	assert.ElementsMatch(t, []GroupSizeChange{
+		{GroupName: "ng1", SizeChange: 10},
+		{GroupName: "ng2", SizeChange: 5},
+		{GroupName: "ng3", SizeChange: 4},
+		{GroupName: "ng4", SizeChange: 3},
+		{GroupName: "ng5", SizeChange: 2},
+		{GroupName: "ng6", SizeChange: 2},
+	}, sequential)
+	for _, parallelism := range []int{2, 4, 10} {
+		t.Run(fmt.Sprintf("parallelism %d", parallelism), func(t *testing.T) {
+			assert.ElementsMatch(t, sequential, runScaleUp(t, parallelism, false))
+		})
+		t.Run(fmt.Sprintf("parallelism %d with binpacking limiter", parallelism), func(t *testing.T) {
+			// The limiter stops binpacking after the first option, regardless of the other estimations in the batch.
+			assert.Len(t, runScaleUp(t, parallelism, true), 1)
+		})
+	}
+}
+
 func TestScaleUpNoHelp(t *testing.T) {
 	n1 := BuildTestNode("n1", 100, 1000)
 	now := time.Now()
@@ -1896,6 +1972,109 @@ func TestScaleUpBalanceGroupsRespectsQuota(t *testing.T) {
 	}
 }
 
+func TestScaleUpZoneSpread(t *testing.T) {
+	testCases := []struct {
+		name                 string
+		zoneSpreadScaleUp    bool
+		expectedNodeCount    int
+		expectedNodesPerZone map[string]int
+	}{
+		{
+			name:              "scale-up is split between zones",
+			zoneSpreadScaleUp: true,
+			expectedNodeCount: 3,
+			expectedNodesPerZone: map[string]int{
+				"zone-a": 1,
+				"zone-b": 1,
+				"zone-c": 1,
+			},
+		},
+		{
+			name:              "scale-up of a single zone is limited by the skew",
+			zoneSpreadScaleUp: false,
+			expectedNodeCount: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := testprovider.NewTestCloudProviderBuilder().WithOnScaleUp(func(string, int) error {
+				return nil
+			}).Build()
+
+			zones := map[string]string{"ng1": "zone-a", "ng2": "zone-b", "ng3": "zone-c"}
+			podList := make([]*apiv1.Pod, 0)
+			nodes := make([]*apiv1.Node, 0)
+
+			now := time.Now()
+
+			for gid, zone := range zones {
+				provider.AddNodeGroup(gid, 1, 10, 1)
+				nodeName := fmt.Sprintf("%v-node-0", gid)
+				node := BuildTestNode(nodeName, 1000, 1000)
+				node.Labels[apiv1.LabelTopologyZone] = zone
+				SetNodeReadyState(node, true, now.Add(-2*time.Minute))
+				nodes = append(nodes, node)
+
+				pod := BuildTestPod(fmt.Sprintf("%v-pod-0", gid), 1000, 0)
+				pod.Spec.NodeName = nodeName
+				podList = append(podList, pod)
+
+				provider.AddNode(gid, node)
+			}
+
+			podLister := kube_util.NewTestPodLister(podList)
+			listers := kube_util.NewListerRegistry(nil, nil, podLister, nil, nil, nil, nil, nil, nil)
+
+			options := config.AutoscalingOptions{
+				EstimatorName:                  estimator.BinpackingEstimatorName,
+				BalanceSimilarNodeGroups:       true,
+				ZoneSpreadScaleUpEnabled:       tc.zoneSpreadScaleUp,
+				MaxCoresTotal:                  config.DefaultMaxClusterCores,
+				MaxMemoryTotal:                 config.DefaultMaxClusterMemory,
+				MaxNodeGroupBinpackingDuration: 1 * time.Second,
+			}
+			processors, templateNodeInfoRegistry := processorstest.NewTestProcessors(options)
+			autoscalingCtx, err := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, listers, provider, nil, nil, templateNodeInfoRegistry)
+			assert.NoError(t, err)
+			err = autoscalingCtx.ClusterSnapshot.SetClusterState(nodes, podList, nil, nil)
+			assert.NoError(t, err)
+			_ = autoscalingCtx.TemplateNodeInfoRegistry.Recompute(&autoscalingCtx, nodes, []*appsv1.DaemonSet{}, taints.TaintConfig{}, now)
+			nodeInfos := autoscalingCtx.TemplateNodeInfoRegistry.GetNodeInfos()
+			clusterState := clusterstate.NewClusterStateRegistry(provider, autoscalingCtx.LogRecorder, NewBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute}), autoscalingCtx.TemplateNodeInfoRegistry, clusterstate.WithScaleStateNotifier(processors.ScaleStateNotifier))
+			clusterState.UpdateNodes(nodes, time.Now())
+
+			pods := make([]*apiv1.Pod, 0)
+			for i := 0; i < 6; i++ {
+				pods = append(pods, BuildTestPod(fmt.Sprintf("test-pod-%v", i), 400, 0,
+					WithLabels(map[string]string{"app": "estimatee"}),
+					WithMaxSkew(1, apiv1.LabelTopologyZone, 1)))
+			}
+
+			quotasProvider := resourcequotas.NewCloudQuotasProvider(provider)
+			trackerFactory := resourcequotas.NewTrackerFactory(resourcequotas.TrackerOptions{
+				QuotaProvider:            quotasProvider,
+				CustomResourcesProcessor: processors.CustomResourcesProcessor,
+			})
+			suOrchestrator := New()
+			suOrchestrator.Initialize(&autoscalingCtx, processors, clusterState, newEstimatorBuilder(), taints.TaintConfig{}, trackerFactory)
+			scaleUpStatus, typedErr := suOrchestrator.ScaleUp(pods, nodes, []*appsv1.DaemonSet{}, nodeInfos, false)
+
+			assert.NoError(t, typedErr)
+			assert.True(t, scaleUpStatus.WasSuccessful())
+			assert.Equal(t, tc.expectedNodesPerZone, scaleUpStatus.NodesPerZone)
+
+			nodeCount := 0
+			for _, group := range provider.NodeGroups() {
+				size, err := group.TargetSize()
+				assert.NoError(t, err)
+				nodeCount += size - 1
+			}
+			assert.Equal(t, tc.expectedNodeCount, nodeCount)
+		})
+	}
+}
+
 func TestScaleUpAutoprovisionedNodeGroup(t *testing.T) {
 	createdGroups := make(chan string, 10)
 	expandedGroups := make(chan string, 10)
@@ -2098,6 +2277,101 @@ func TestScaleUpToMeetNodeGroupMinSize(t *testing.T) {
 	assert.Equal(t, "ng1", scaleUpStatus.ScaleUpInfos[0].Group.Id())
 }
 
+func TestScaleUpNodeGroup(t *testing.T) {
+	testCases := []struct {
+		name          string
+		maxSize       int
+		maxNodesTotal int
+		maxCores      int64
+		wantResult    status.ScaleUpResult
+	}{
+		{
+			name:       "scaled up",
+			maxSize:    10,
+			maxCores:   48,
+			wantResult: status.ScaleUpSuccessful,
+		},
+		{
+			name:       "max size reached",
+			maxSize:    1,
+			maxCores:   48,
+			wantResult: status.ScaleUpNoOptionsAvailable,
+		},
+		{
+			name:          "max nodes total reached",
+			maxSize:       10,
+			maxNodesTotal: 2,
+			maxCores:      48,
+			wantResult:    status.ScaleUpLimitedByMaxNodesTotal,
+		},
+		{
+			name:       "cores limit exceeded",
+			maxSize:    10,
+			maxCores:   32,
+			wantResult: status.ScaleUpNoOptionsAvailable,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			podLister := kube_util.NewTestPodLister([]*apiv1.Pod{})
+			listers := kube_util.NewListerRegistry(nil, nil, podLister, nil, nil, nil, nil, nil, nil)
+			scaleUps := map[string]int{}
+			provider := testprovider.NewTestCloudProviderBuilder().WithOnScaleUp(func(nodeGroup string, increase int) error {
+				scaleUps[nodeGroup] += increase
+				return nil
+			}).Build()
+			provider.SetResourceLimiter(cloudprovider.NewResourceLimiter(
+				map[string]int64{cloudprovider.ResourceNameCores: 0, cloudprovider.ResourceNameMemory: 0},
+				map[string]int64{cloudprovider.ResourceNameCores: tc.maxCores, cloudprovider.ResourceNameMemory: 1000},
+			))
+			n1 := BuildTestNode("n1", 16000, 32)
+			SetNodeReadyState(n1, true, time.Now())
+			n2 := BuildTestNode("n2", 16000, 32)
+			SetNodeReadyState(n2, true, time.Now())
+			provider.AddNodeGroup("ng1", 0, tc.maxSize, 1)
+			provider.AddNode("ng1", n1)
+			provider.AddNodeGroup("ng2", 0, 10, 1)
+			provider.AddNode("ng2", n2)
+
+			options := config.AutoscalingOptions{
+				EstimatorName:                  estimator.BinpackingEstimatorName,
+				MaxCoresTotal:                  config.DefaultMaxClusterCores,
+				MaxMemoryTotal:                 config.DefaultMaxClusterMemory,
+				MaxNodesTotal:                  tc.maxNodesTotal,
+				MaxNodeGroupBinpackingDuration: 1 * time.Second,
+			}
+			processors, templateNodeInfoRegistry := processorstest.NewTestProcessors(options)
+			autoscalingCtx, err := NewScaleTestAutoscalingContext(options, &fake.Clientset{}, listers, provider, nil, nil, templateNodeInfoRegistry)
+			assert.NoError(t, err)
+
+			nodes := []*apiv1.Node{n1, n2}
+			err = autoscalingCtx.ClusterSnapshot.SetClusterState(nodes, nil, nil, nil)
+			assert.NoError(t, err)
+			_ = autoscalingCtx.TemplateNodeInfoRegistry.Recompute(&autoscalingCtx, nodes, []*appsv1.DaemonSet{}, taints.TaintConfig{}, time.Now())
+			nodeInfos := autoscalingCtx.TemplateNodeInfoRegistry.GetNodeInfos()
+			clusterState := clusterstate.NewClusterStateRegistry(provider, autoscalingCtx.LogRecorder, NewBackoff(), nodegroupconfig.NewDefaultNodeGroupConfigProcessor(config.NodeGroupAutoscalingOptions{MaxNodeProvisionTime: 15 * time.Minute}), autoscalingCtx.TemplateNodeInfoRegistry, clusterstate.WithScaleStateNotifier(processors.ScaleStateNotifier))
+			clusterState.UpdateNodes(nodes, time.Now())
+
+			trackerFactory := resourcequotas.NewTrackerFactory(resourcequotas.TrackerOptions{
+				QuotaProvider:            resourcequotas.NewCloudQuotasProvider(provider),
+				CustomResourcesProcessor: processors.CustomResourcesProcessor,
+			})
+			suOrchestrator := New()
+			suOrchestrator.Initialize(&autoscalingCtx, processors, clusterState, newEstimatorBuilder(), taints.TaintConfig{}, trackerFactory)
+			scaleUpStatus, err := suOrchestrator.ScaleUpNodeGroup(provider.GetNodeGroup("ng1"), 1, nodes, nodeInfos)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantResult, scaleUpStatus.Result)
+			if tc.wantResult != status.ScaleUpSuccessful {
+				assert.Empty(t, scaleUps)
+				return
+			}
+			assert.Equal(t, map[string]int{"ng1": 1}, scaleUps)
+			assert.Equal(t, 1, len(scaleUpStatus.ScaleUpInfos))
+			assert.Equal(t, 2, scaleUpStatus.ScaleUpInfos[0].NewSize)
+		})
+	}
+}
+
 func TestScaleupAsyncNodeGroupsEnabled(t *testing.T) {
 	t1 := BuildTestNode("t1", 100, 0)
 	SetNodeReadyState(t1, true, time.Time{})
@@ -0,0 +1,156 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package orchestrator
+
+import (
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/estimator"
+	"k8s.io/autoscaler/cluster-autoscaler/expander"
+	"k8s.io/autoscaler/cluster-autoscaler/processors/nodegroupset"
+	"k8s.io/autoscaler/cluster-autoscaler/resourcequotas"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/autoscaler/cluster-autoscaler/utils/errors"
+	"k8s.io/klog/v2"
+)
+
+// zoneSpreadTemplates returns the node group of the option followed by its similar node groups, if the
+// scale-up of the pod groups should be split between them. This is the case for pods spreading across
+// zones, when the node groups are in more than one zone. Returns nil otherwise.
+func (o *ScaleUpOrchestrator) zoneSpreadTemplates(
+	option *expander.Option,
+	podGroups []estimator.PodEquivalenceGroup,
+	nodeInfos map[string]*framework.NodeInfo,
+) []estimator.NodeGroupTemplate {
+	if !o.autoscalingCtx.ZoneSpreadScaleUpEnabled || len(option.SimilarNodeGroups) == 0 || !spreadAcrossZones(podGroups) {
+		return nil
+	}
+
+	var templates []estimator.NodeGroupTemplate
+	zones := sets.New[string]()
+	nodeGroups := append([]cloudprovider.NodeGroup{option.NodeGroup}, option.SimilarNodeGroups...)
+	for i, nodeGroup := range nodeGroups {
+		template, ok := newNodeGroupTemplate(nodeGroup, nodeInfos)
+		if !ok {
+			if i == 0 {
+				// The main node group can't take part in the split.
+				return nil
+			}
+			continue
+		}
+		templates = append(templates, template)
+		zones.Insert(nodeZone(template.NodeInfo.Node()))
+	}
+	if zones.Len() < 2 {
+		return nil
+	}
+	return templates
+}
+
+func newNodeGroupTemplate(nodeGroup cloudprovider.NodeGroup, nodeInfos map[string]*framework.NodeInfo) (estimator.NodeGroupTemplate, bool) {
+	// Node groups which don't exist yet get a new id once they are created, so they aren't split between.
+	if !nodeGroup.Exist() {
+		return estimator.NodeGroupTemplate{}, false
+	}
+	nodeInfo, found := nodeInfos[nodeGroup.Id()]
+	if !found {
+		return estimator.NodeGroupTemplate{}, false
+	}
+	targetSize, err := nodeGroup.TargetSize()
+	if err != nil {
+		klog.Errorf("Failed to get node group size: %v", err)
+		return estimator.NodeGroupTemplate{}, false
+	}
+	if targetSize >= nodeGroup.MaxSize() {
+		return estimator.NodeGroupTemplate{}, false
+	}
+	return estimator.NodeGroupTemplate{
+		NodeGroup:   nodeGroup,
+		NodeInfo:    nodeInfo,
+		MaxNewNodes: nodeGroup.MaxSize() - targetSize,
+	}, true
+}
+
+// zoneSpreadScaleUps returns the scale-ups of the node groups between which the estimation split the
+// scale-up, capped by their max size and quotas.
+func (o *ScaleUpOrchestrator) zoneSpreadScaleUps(
+	nodeCounts map[string]int,
+	nodeGroups []cloudprovider.NodeGroup,
+	nodeInfos map[string]*framework.NodeInfo,
+	tracker *resourcequotas.Tracker,
+) ([]nodegroupset.ScaleUpInfo, errors.AutoscalerError) {
+	var scaleUpInfos []nodegroupset.ScaleUpInfo
+	for _, nodeGroup := range nodeGroups {
+		nodeCount := nodeCounts[nodeGroup.Id()]
+		if nodeCount <= 0 {
+			continue
+		}
+		currentSize, err := nodeGroup.TargetSize()
+		if err != nil {
+			return nil, errors.NewAutoscalerErrorf(errors.CloudProviderError, "failed to get node group size: %v", err)
+		}
+		scaleUpInfos = append(scaleUpInfos, nodegroupset.ScaleUpInfo{
+			Group:       nodeGroup,
+			CurrentSize: currentSize,
+			NewSize:     min(currentSize+nodeCount, nodeGroup.MaxSize()),
+			MaxSize:     nodeGroup.MaxSize(),
+		})
+	}
+	klog.V(1).Infof("Splitting scale-up between %d node groups for pods spreading across zones: %v", len(scaleUpInfos), scaleUpInfos)
+	return o.capScaleUpsByQuota(scaleUpInfos, nodeInfos, tracker), nil
+}
+
+// nodesPerZone returns the number of nodes requested by the scale-ups in each zone.
+func nodesPerZone(scaleUpInfos []nodegroupset.ScaleUpInfo, nodeInfos map[string]*framework.NodeInfo) map[string]int {
+	result := make(map[string]int)
+	for _, sui := range scaleUpInfos {
+		nodeInfo, found := nodeInfos[sui.Group.Id()]
+		if !found {
+			continue
+		}
+		result[nodeZone(nodeInfo.Node())] += sui.NewSize - sui.CurrentSize
+	}
+	return result
+}
+
+// spreadAcrossZones returns true if the pods of any of the groups must spread across zones.
+func spreadAcrossZones(podGroups []estimator.PodEquivalenceGroup) bool {
+	for _, podGroup := range podGroups {
+		pod := podGroup.Exemplar()
+		if pod == nil {
+			continue
+		}
+		for _, constraint := range pod.Spec.TopologySpreadConstraints {
+			if constraint.WhenUnsatisfiable == apiv1.DoNotSchedule && isZoneLabel(constraint.TopologyKey) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isZoneLabel(label string) bool {
+	return label == apiv1.LabelTopologyZone || label == apiv1.LabelFailureDomainBetaZone
+}
+
+func nodeZone(node *apiv1.Node) string {
+	if zone, found := node.Labels[apiv1.LabelTopologyZone]; found {
+		return zone
+	}
+	return node.Labels[apiv1.LabelFailureDomainBetaZone]
+}
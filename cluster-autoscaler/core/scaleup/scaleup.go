@@ -19,6 +19,7 @@ package scaleup
 import (
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
 	ca_context "k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
@@ -60,4 +61,16 @@ type Orchestrator interface {
 		nodes []*apiv1.Node,
 		nodeInfos map[string]*framework.NodeInfo,
 	) (*status.ScaleUpStatus, errors.AutoscalerError)
+	// ScaleUpNodeGroup tries to add the given number of nodes to the node
+	// group, e.g. to replace nodes consolidated by scale down. The node group
+	// must be safe to scale up, and the nodes must fit within its max size,
+	// the cluster-wide node count limit and the resource quotas, otherwise no
+	// node is added. Returns appropriate status or error if an unexpected
+	// error occurred.
+	ScaleUpNodeGroup(
+		nodeGroup cloudprovider.NodeGroup,
+		newNodes int,
+		nodes []*apiv1.Node,
+		nodeInfos map[string]*framework.NodeInfo,
+	) (*status.ScaleUpStatus, errors.AutoscalerError)
 }
@@ -54,6 +54,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/resourcequotas"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/changes"
 	csinodeprovider "k8s.io/autoscaler/cluster-autoscaler/simulator/csi/provider"
 	csisnapshot "k8s.io/autoscaler/cluster-autoscaler/simulator/csi/snapshot"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
@@ -61,6 +62,7 @@ import (
 	drasnapshot "k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources/snapshot"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/options"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/annotations"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/backoff"
 	caerrors "k8s.io/autoscaler/cluster-autoscaler/utils/errors"
@@ -102,6 +104,11 @@ type StaticAutoscaler struct {
 	initialized                bool
 	taintConfig                taints.TaintConfig
 	capacityBufferPodsRegistry *fakepods.Registry
+	// clusterSnapshotChanges tracks the nodes that changed since the cluster snapshot was last updated, if the
+	// snapshot is updated incrementally.
+	clusterSnapshotChanges *changes.Tracker
+	// changedNodes are the nodes taken from clusterSnapshotChanges that aren't updated in the snapshot yet.
+	changedNodes map[string]bool
 }
 
 type staticAutoscalerProcessorCallbacks struct {
@@ -160,7 +167,9 @@ func NewStaticAutoscaler(
 	quotasTrackerOptions resourcequotas.TrackerOptions,
 	minQuotasTrackerOptions resourcequotas.TrackerOptions,
 	csiProvider *csinodeprovider.Provider,
-	capacityBufferPodsRegistry *fakepods.Registry) *StaticAutoscaler {
+	usageProvider utilization.UsageProvider,
+	capacityBufferPodsRegistry *fakepods.Registry,
+	clusterSnapshotChanges *changes.Tracker) *StaticAutoscaler {
 
 	klog.V(4).Infof("Creating new static autoscaler with opts: %v", opts)
 
@@ -186,7 +195,8 @@ func NewStaticAutoscaler(
 		clusterStateRegistry,
 		draProvider,
 		templateNodeInfoRegistry,
-		csiProvider)
+		csiProvider,
+		usageProvider)
 
 	taintConfig := taints.NewTaintConfig(opts)
 
@@ -234,6 +244,7 @@ func NewStaticAutoscaler(
 		clusterStateRegistry:       clusterStateRegistry,
 		taintConfig:                taintConfig,
 		capacityBufferPodsRegistry: capacityBufferPodsRegistry,
+		clusterSnapshotChanges:     clusterSnapshotChanges,
 	}
 }
 
@@ -277,6 +288,40 @@ func (a *StaticAutoscaler) cleanUpIfRequired() {
 	a.initialized = true
 }
 
+// takeClusterSnapshotChanges adds the nodes that changed since the previous call to the nodes that need to be updated
+// in the cluster snapshot.
+func (a *StaticAutoscaler) takeClusterSnapshotChanges() {
+	if a.clusterSnapshotChanges == nil {
+		return
+	}
+	if a.changedNodes == nil {
+		a.changedNodes = make(map[string]bool)
+	}
+	for nodeName := range a.clusterSnapshotChanges.ChangedNodes() {
+		a.changedNodes[nodeName] = true
+	}
+}
+
+// setClusterSnapshotState sets the state of the cluster snapshot, updating only the changed nodes if the snapshot is
+// updated incrementally.
+func (a *StaticAutoscaler) setClusterSnapshotState(nodes []*apiv1.Node, scheduledPods []*apiv1.Pod, draSnapshot *drasnapshot.Snapshot, csiSnapshot *csisnapshot.Snapshot) error {
+	incrementalSnapshot, ok := a.ClusterSnapshot.(clustersnapshot.IncrementalClusterSnapshot)
+	if a.clusterSnapshotChanges == nil || !ok {
+		return a.ClusterSnapshot.SetClusterState(nodes, scheduledPods, draSnapshot, csiSnapshot)
+	}
+	if err := incrementalSnapshot.UpdateClusterState(nodes, scheduledPods, draSnapshot, csiSnapshot, a.changedNodes); err != nil {
+		return err
+	}
+	a.changedNodes = nil
+	if a.IncrementalSnapshotVerificationEnabled {
+		if err := incrementalSnapshot.VerifyClusterState(nodes, scheduledPods); err != nil {
+			klog.Errorf("Incrementally updated cluster snapshot is inconsistent, rebuilding it: %v", err)
+			return a.ClusterSnapshot.SetClusterState(nodes, scheduledPods, draSnapshot, csiSnapshot)
+		}
+	}
+	return nil
+}
+
 func (a *StaticAutoscaler) initializeRemainingPdbTracker() caerrors.AutoscalerError {
 	a.RemainingPdbTracker.Clear()
 
@@ -309,6 +354,11 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 
 	stateUpdateStart := time.Now()
 
+	// Changes are taken before listing the cluster state, so that the snapshot contains at least all changes
+	// observed before listing. Listed Nodes and Pods whose events weren't handled yet are detected by their
+	// resourceVersions when the snapshot is updated.
+	a.takeClusterSnapshotChanges()
+
 	var draSnapshot *drasnapshot.Snapshot
 	if a.AutoscalingContext.DynamicResourceAllocationEnabled && a.AutoscalingContext.DraProvider != nil {
 		var err error
@@ -388,7 +438,7 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 	allocatedPods := slices.Concat(podsBySchedulability.Scheduled, podsBySchedulability.NominatedNode)
 	nonExpendableAllocatedPods := core_utils.FilterOutExpendablePods(allocatedPods, a.ExpendablePodsPriorityCutoff)
 
-	if err := a.ClusterSnapshot.SetClusterState(allNodes, nonExpendableAllocatedPods, draSnapshot, csiSnapshot); err != nil {
+	if err := a.setClusterSnapshotState(allNodes, nonExpendableAllocatedPods, draSnapshot, csiSnapshot); err != nil {
 		return caerrors.ToAutoscalerError(caerrors.InternalError, err).AddPrefix("failed to initialize ClusterSnapshot: ")
 	}
 	// Initialize Pod Disruption Budget tracking
@@ -602,7 +652,7 @@ func (a *StaticAutoscaler) RunOnce(currentTime time.Time) caerrors.AutoscalerErr
 	}
 
 	if a.ScaleDownEnabled {
-		if typedErr = a.scaleDown(currentTime, allNodes, scaleDownActuationStatus, scaleDownStatus); typedErr != nil {
+		if typedErr = a.scaleDown(currentTime, allNodes, templateNodeInfos, scaleDownActuationStatus, scaleDownStatus); typedErr != nil {
 			return typedErr
 		}
 	}
@@ -746,7 +796,7 @@ func (a *StaticAutoscaler) updateSoftDeletionTaints(allNodes []*apiv1.Node) {
 	}
 }
 
-func (a *StaticAutoscaler) scaleDown(currentTime time.Time, allNodes []*apiv1.Node, scaleDownActuationStatus scaledown.ActuationStatus, scaleDownStatus *scaledownstatus.ScaleDownStatus) caerrors.AutoscalerError {
+func (a *StaticAutoscaler) scaleDown(currentTime time.Time, allNodes []*apiv1.Node, templateNodeInfos map[string]*framework.NodeInfo, scaleDownActuationStatus scaledown.ActuationStatus, scaleDownStatus *scaledownstatus.ScaleDownStatus) caerrors.AutoscalerError {
 	unneededStart := time.Now()
 
 	klog.V(4).Infof("Calculating unneeded nodes")
@@ -842,10 +892,42 @@ func (a *StaticAutoscaler) scaleDown(currentTime time.Time, allNodes []*apiv1.No
 			a.lastScaleDownFailTime = currentTime
 			return typedErr
 		}
+		a.requestConsolidationReplacements(currentTime, templateNodeInfos)
 	}
 	return nil
 }
 
+// requestConsolidationReplacements scales up the node groups of the
+// replacements of the consolidations planned by the scale down planner. The
+// replacements go through the scale up orchestrator, so they are subject to
+// the same limits as other scale ups.
+func (a *StaticAutoscaler) requestConsolidationReplacements(currentTime time.Time, templateNodeInfos map[string]*framework.NodeInfo) {
+	replacements := a.scaleDownPlanner.ConsolidationReplacements()
+	if len(replacements) == 0 {
+		return
+	}
+	nodeInfos, err := a.ClusterSnapshot.ListNodeInfos()
+	if err != nil {
+		klog.Errorf("Failed to list nodes for consolidation replacements: %v", err)
+		return
+	}
+	nodes := make([]*apiv1.Node, len(nodeInfos))
+	for i, nodeInfo := range nodeInfos {
+		nodes[i] = nodeInfo.Node()
+	}
+	for _, replacement := range replacements {
+		scaleUpFn := func() (*status.ScaleUpStatus, caerrors.AutoscalerError) {
+			return a.scaleUpOrchestrator.ScaleUpNodeGroup(replacement.NodeGroup, 1, nodes, templateNodeInfos)
+		}
+		_, scaleUpStatus, typedErr := a.instrumentedScaleUp(currentTime, scaleUpFn)
+		if typedErr != nil || scaleUpStatus.Result != status.ScaleUpSuccessful {
+			klog.V(2).Infof("Replacement for consolidation of nodes %v not requested, node group %s can't be scaled up", replacement.Nodes, replacement.NodeGroup.Id())
+			continue
+		}
+		a.scaleDownPlanner.ConsolidationReplacementRequested(replacement, currentTime)
+	}
+}
+
 // addUpcomingNodesToClusterSnapshot generates upcoming node infos based on upcomingCounts and adds them to the ClusterSnapshot.
 func (a *StaticAutoscaler) addUpcomingNodesToClusterSnapshot(
 	upcomingCounts map[string]int,
@@ -66,6 +66,9 @@ import (
 	processorstest "k8s.io/autoscaler/cluster-autoscaler/processors/test"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/changes"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/predicate"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/store"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/drainability/rules"
 	draprovider "k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources/provider"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
@@ -79,6 +82,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/utils/scheduler"
 	"k8s.io/autoscaler/cluster-autoscaler/utils/taints"
 	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
 	v1appslister "k8s.io/client-go/listers/apps/v1"
 	kube_record "k8s.io/client-go/tools/record"
@@ -2412,6 +2416,13 @@ func (f *candidateTrackingFakePlanner) NodeUtilizationMap() map[string]utilizati
 	return nil
 }
 
+func (f *candidateTrackingFakePlanner) ConsolidationReplacements() []*scaledown.ConsolidationReplacement {
+	return nil
+}
+
+func (f *candidateTrackingFakePlanner) ConsolidationReplacementRequested(replacement *scaledown.ConsolidationReplacement, currentTime time.Time) {
+}
+
 func assertSnapshotNodeCount(t *testing.T, snapshot clustersnapshot.ClusterSnapshot, wantCount int) {
 	nodeInfos, err := snapshot.ListNodeInfos()
 	assert.NoError(t, err)
@@ -3899,3 +3910,65 @@ func TestStaticAutoscalerScaleDownCustomQuota(t *testing.T) {
 		assert.Equal(t, simulator.MinimalResourceLimitExceeded, unremovableNode.Reason)
 	}
 }
+
+func TestSetClusterSnapshotState(t *testing.T) {
+	node := BuildTestNode("node", 1000, 1000)
+	node.ResourceVersion = "1"
+	// Changed without a new resourceVersion, which can't be detected without an event.
+	relabeledNode := node.DeepCopy()
+	relabeledNode.Labels["relabeled"] = "true"
+	pod1 := BuildScheduledTestPod("pod1", 100, 100, "node")
+	pod2 := BuildScheduledTestPod("pod2", 100, 100, "node")
+
+	for _, tc := range []struct {
+		name          string
+		verification  bool
+		markChanged   bool
+		addPod        bool
+		wantPods      []string
+		wantRelabeled bool
+	}{
+		{name: "unreported change isn't applied", wantPods: []string{"pod1"}},
+		{name: "unreported new pod is applied", addPod: true, wantPods: []string{"pod1", "pod2"}, wantRelabeled: true},
+		{name: "reported change is applied", markChanged: true, wantPods: []string{"pod1"}, wantRelabeled: true},
+		{name: "unreported change is found by verification", verification: true, wantPods: []string{"pod1"}, wantRelabeled: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fwHandle, err := framework.NewTestFrameworkHandle()
+			assert.NoError(t, err)
+			tracker, err := changes.NewTrackerFromInformers(informers.NewSharedInformerFactory(fake.NewSimpleClientset(), 0), false, false)
+			assert.NoError(t, err)
+			autoscaler := &StaticAutoscaler{
+				AutoscalingContext: &ca_context.AutoscalingContext{
+					AutoscalingOptions: config.AutoscalingOptions{IncrementalSnapshotVerificationEnabled: tc.verification},
+					ClusterSnapshot:    predicate.NewPredicateSnapshot(store.NewDeltaSnapshotStore(), fwHandle, false, 1, false),
+				},
+				clusterSnapshotChanges: tracker,
+			}
+
+			autoscaler.takeClusterSnapshotChanges()
+			assert.NoError(t, autoscaler.setClusterSnapshotState([]*apiv1.Node{node}, []*apiv1.Pod{pod1}, nil, nil))
+
+			if tc.markChanged {
+				tracker.MarkChanged("node")
+			}
+			pods := []*apiv1.Pod{pod1}
+			if tc.addPod {
+				pods = append(pods, pod2)
+			}
+			autoscaler.takeClusterSnapshotChanges()
+			assert.NoError(t, autoscaler.setClusterSnapshotState([]*apiv1.Node{relabeledNode}, pods, nil, nil))
+			assert.Empty(t, autoscaler.changedNodes)
+
+			nodeInfo, err := autoscaler.ClusterSnapshot.GetNodeInfo("node")
+			assert.NoError(t, err)
+			var podNames []string
+			for _, podInfo := range nodeInfo.Pods() {
+				podNames = append(podNames, podInfo.Pod.Name)
+			}
+			assert.ElementsMatch(t, tc.wantPods, podNames)
+			_, relabeled := nodeInfo.Node().Labels["relabeled"]
+			assert.Equal(t, tc.wantRelabeled, relabeled)
+		})
+	}
+}
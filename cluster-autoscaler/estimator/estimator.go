@@ -28,10 +28,12 @@ import (
 const (
 	// BinpackingEstimatorName is the name of binpacking estimator.
 	BinpackingEstimatorName = "binpacking"
+	// VectorBinpackingEstimatorName is the name of vector binpacking estimator.
+	VectorBinpackingEstimatorName = "vector-binpacking"
 )
 
 // AvailableEstimators is a list of available estimators.
-var AvailableEstimators = []string{BinpackingEstimatorName}
+var AvailableEstimators = []string{BinpackingEstimatorName, VectorBinpackingEstimatorName}
 
 // PodEquivalenceGroup represents a group of pods, which have the same scheduling
 // requirements and are managed by the same controller.
@@ -68,12 +70,27 @@ func NewEstimatorBuilder(name string, limiter EstimationLimiter, orderer Estimat
 		return func(
 			clusterSnapshot clustersnapshot.ClusterSnapshot,
 			context EstimationContext) Estimator {
-			return NewBinpackingNodeEstimator(clusterSnapshot, limiter, orderer, context, estimationAnalyserFunc, fastpathBinpackingEnabled)
+			return NewBinpackingNodeEstimator(clusterSnapshot, estimationLimiter(limiter), orderer, context, estimationAnalyserFunc, fastpathBinpackingEnabled)
+		}, nil
+	case VectorBinpackingEstimatorName:
+		return func(
+			clusterSnapshot clustersnapshot.ClusterSnapshot,
+			context EstimationContext) Estimator {
+			return NewVectorBinpackingNodeEstimator(clusterSnapshot, estimationLimiter(limiter), orderer, context, estimationAnalyserFunc)
 		}, nil
 	}
 	return nil, fmt.Errorf("unknown estimator: %s", name)
 }
 
+// estimationLimiter returns the limiter to be used by a single estimator.
+func estimationLimiter(limiter EstimationLimiter) EstimationLimiter {
+	if cloneable, ok := limiter.(CloneableEstimationLimiter); ok {
+		// Estimators can run concurrently, each of them needs its own limiter state.
+		return cloneable.Clone()
+	}
+	return limiter
+}
+
 // EstimationLimiter controls how many nodes can be added by Estimator.
 // A limiter can be used to prevent costly estimation if an actual ability to
 // scale-up is limited by external factors.
@@ -90,6 +107,14 @@ type EstimationLimiter interface {
 	PermissionToAddNode() bool
 }
 
+// CloneableEstimationLimiter is an EstimationLimiter whose state is only kept for the duration of an estimation,
+// and which can be cloned so that estimations can run concurrently.
+type CloneableEstimationLimiter interface {
+	EstimationLimiter
+	// Clone returns a new limiter with the same configuration.
+	Clone() EstimationLimiter
+}
+
 // EstimationPodOrderer is an interface used to determine the order of the pods
 // used while binpacking during scale up estimation
 type EstimationPodOrderer interface {
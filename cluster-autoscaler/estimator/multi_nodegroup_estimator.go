@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"fmt"
+	"slices"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	"k8s.io/klog/v2"
+)
+
+// NodeGroupTemplate is a node group considered by a multi node group estimation.
+type NodeGroupTemplate struct {
+	NodeGroup cloudprovider.NodeGroup
+	// NodeInfo is the template of the nodes added to the node group.
+	NodeInfo *framework.NodeInfo
+	// MaxNewNodes is the maximum number of nodes that can be added to the node group.
+	MaxNewNodes int
+}
+
+// MultiNodeGroupEstimator is implemented by estimators that can split a scale-up between several
+// similar node groups, e.g. so that pods spreading across zones can all be scheduled in one loop.
+type MultiNodeGroupEstimator interface {
+	// EstimateNodeGroups estimates how many nodes are needed in each of the node groups to provision pods
+	// coming from the given equivalence groups. The first node group is the main one of the scale-up. It
+	// returns the number of new nodes by node group id, as well as the list of pods it managed to schedule.
+	EstimateNodeGroups([]PodEquivalenceGroup, []NodeGroupTemplate) (map[string]int, []*apiv1.Pod)
+}
+
+// EstimateNodeGroups implements MultiNodeGroupEstimator. Pods are first packed on the nodes added
+// so far, like in Estimate. When a pod doesn't fit any of them, a new node is added to the node group
+// with the fewest new nodes that can accommodate it, so that constraints such as zonal topology
+// spreading are satisfied by growing the node groups of other zones.
+func (e *BinpackingNodeEstimator) EstimateNodeGroups(
+	podsEquivalenceGroups []PodEquivalenceGroup,
+	templates []NodeGroupTemplate,
+) (map[string]int, []*apiv1.Pod) {
+	if len(templates) == 0 {
+		return map[string]int{}, nil
+	}
+	main := templates[0]
+	observeBinpackingHeterogeneity(podsEquivalenceGroups, main.NodeInfo)
+
+	e.limiter.StartEstimation(podsEquivalenceGroups, main.NodeGroup, e.context)
+	defer e.limiter.EndEstimation()
+
+	podsEquivalenceGroups = e.podOrderer.Order(podsEquivalenceGroups, main.NodeInfo, main.NodeGroup)
+
+	e.clusterSnapshot.Fork()
+	defer func() {
+		e.clusterSnapshot.Revert()
+	}()
+
+	estimationState := newEstimationState()
+	newNodeCounts := make([]int, len(templates))
+	newNodesAvailable := true
+	for _, podsEquivalenceGroup := range podsEquivalenceGroups {
+		for _, pod := range podsEquivalenceGroup.Pods {
+			// Pods are tried one by one, as a node added to another node group may allow a pod to fit
+			// one of the previous nodes again.
+			remainingPods, err := e.tryToScheduleOnExistingNodes(estimationState, []*apiv1.Pod{pod})
+			if err != nil {
+				klog.Error(err.Error())
+				return map[string]int{}, nil
+			}
+			if len(remainingPods) == 0 || !newNodesAvailable {
+				continue
+			}
+
+			var found bool
+			found, newNodesAvailable, err = e.tryToScheduleOnNewNodeOfAnyGroup(estimationState, templates, newNodeCounts, pod)
+			if err != nil {
+				klog.Error(err.Error())
+				return map[string]int{}, nil
+			}
+			if !found {
+				// The pod doesn't fit a new node of any node group, neither will the other pods of its group.
+				break
+			}
+		}
+	}
+
+	if e.estimationAnalyserFunc != nil {
+		e.estimationAnalyserFunc(e.clusterSnapshot, main.NodeGroup, estimationState.newNodesWithPods)
+	}
+	nodeCounts := make(map[string]int)
+	for i, template := range templates {
+		if newNodeCounts[i] > 0 {
+			nodeCounts[template.NodeGroup.Id()] = newNodeCounts[i]
+		}
+	}
+	return nodeCounts, estimationState.scheduledPods
+}
+
+// tryToScheduleOnNewNodeOfAnyGroup adds a node to the node group with the fewest new nodes that can
+// accommodate the pod. Nodes which the pod doesn't fit are removed from the snapshot right away. Returns
+// whether the pod was scheduled, whether it is worth adding new nodes for the following pods and error
+// in unexpected situations where whole estimation should be stopped.
+func (e *BinpackingNodeEstimator) tryToScheduleOnNewNodeOfAnyGroup(
+	estimationState *estimationState,
+	templates []NodeGroupTemplate,
+	newNodeCounts []int,
+	pod *apiv1.Pod,
+) (bool, bool, error) {
+	var candidates []int
+	for i, template := range templates {
+		if newNodeCounts[i] < template.MaxNewNodes {
+			candidates = append(candidates, i)
+		}
+	}
+	if len(candidates) == 0 {
+		return false, false, nil
+	}
+	slices.SortStableFunc(candidates, func(a, b int) int {
+		return newNodeCounts[a] - newNodeCounts[b]
+	})
+
+	// At most one node is kept, so the permission is only requested once for all the candidates.
+	if !e.limiter.PermissionToAddNode() {
+		return false, false, nil
+	}
+	for _, i := range candidates {
+		if err := e.addNewNodeToSnapshot(estimationState, templates[i].NodeInfo); err != nil {
+			return false, false, fmt.Errorf("Error while adding new node for template to ClusterSnapshot; %w", err)
+		}
+		nodeName := estimationState.lastNodeName
+		if err := e.clusterSnapshot.SchedulePod(pod, nodeName); err == nil {
+			estimationState.trackScheduledPod(pod, nodeName)
+			newNodeCounts[i]++
+			return true, true, nil
+		} else if err.Type() == clustersnapshot.SchedulingInternalError {
+			// Unexpected error.
+			return false, false, err
+		}
+		// The pod can't be scheduled on a new node of this node group, try the next one.
+		if err := e.clusterSnapshot.RemoveNodeInfo(nodeName); err != nil {
+			return false, false, fmt.Errorf("Error while removing new node %s from ClusterSnapshot; %w", nodeName, err)
+		}
+		delete(estimationState.newNodeNames, nodeName)
+		estimationState.lastNodeName = ""
+	}
+	return false, true, nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	testprovider "k8s.io/autoscaler/cluster-autoscaler/cloudprovider/test"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/testsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func TestBinpackingEstimateNodeGroups(t *testing.T) {
+	zoneSpreadPod := func(maxSkew int32) PodEquivalenceGroup {
+		return makePodEquivalenceGroup(
+			BuildTestPod(
+				"estimatee",
+				400,
+				100,
+				WithNamespace("universe"),
+				WithLabels(map[string]string{
+					"app": "estimatee",
+				}),
+				WithMaxSkew(maxSkew, "topology.kubernetes.io/zone", 1)), 12)
+	}
+	testCases := []struct {
+		name                 string
+		podsEquivalenceGroup []PodEquivalenceGroup
+		zones                []string
+		maxNewNodes          []int
+		maxNodes             int
+		expectNodeCounts     map[string]int
+		expectPodCount       int
+	}{
+		{
+			name:                 "zonal topology spreading is split between the zones of the node groups",
+			podsEquivalenceGroup: []PodEquivalenceGroup{zoneSpreadPod(1)},
+			zones:                []string{"zone-mars", "zone-venus", "zone-jupiter"},
+			maxNewNodes:          []int{10, 10, 10},
+			expectNodeCounts:     map[string]int{"ng-0": 2, "ng-1": 2, "ng-2": 2},
+			expectPodCount:       12,
+		},
+		{
+			name:                 "single zone only allows pods within the skew",
+			podsEquivalenceGroup: []PodEquivalenceGroup{zoneSpreadPod(1)},
+			zones:                []string{"zone-mars"},
+			maxNewNodes:          []int{10},
+			expectNodeCounts:     map[string]int{"ng-0": 1},
+			expectPodCount:       1,
+		},
+		{
+			name:                 "node group max size limits its share of the scale-up",
+			podsEquivalenceGroup: []PodEquivalenceGroup{zoneSpreadPod(2)},
+			zones:                []string{"zone-mars", "zone-venus", "zone-jupiter"},
+			maxNewNodes:          []int{10, 1, 10},
+			expectNodeCounts:     map[string]int{"ng-0": 2, "ng-1": 1, "ng-2": 2},
+			expectPodCount:       10,
+		},
+		{
+			name:                 "limiter cuts binpacking",
+			podsEquivalenceGroup: []PodEquivalenceGroup{zoneSpreadPod(1)},
+			zones:                []string{"zone-mars", "zone-venus", "zone-jupiter"},
+			maxNewNodes:          []int{10, 10, 10},
+			maxNodes:             4,
+			expectNodeCounts:     map[string]int{"ng-0": 2, "ng-1": 1, "ng-2": 1},
+			expectPodCount:       7,
+		},
+		{
+			name:                 "pods without spreading are split evenly between the node groups",
+			podsEquivalenceGroup: []PodEquivalenceGroup{zoneSpreadPod(0)},
+			zones:                []string{"zone-mars", "zone-venus", "zone-jupiter"},
+			maxNewNodes:          []int{10, 10, 10},
+			expectNodeCounts:     map[string]int{"ng-0": 2, "ng-1": 2, "ng-2": 2},
+			expectPodCount:       12,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			clusterSnapshot := testsnapshot.NewTestSnapshotOrDie(t)
+			// Add one node too small for the pods in one of the zones, so that it counts for topology spreading.
+			err := clusterSnapshot.AddNodeInfo(framework.NewTestNodeInfo(makeNode(100, 100, 10, "oldnode", "zone-jupiter")))
+			assert.NoError(t, err)
+			limiter := NewThresholdBasedEstimationLimiter([]Threshold{NewStaticThreshold(tc.maxNodes, time.Duration(0))})
+			estimator := NewBinpackingNodeEstimator(clusterSnapshot, limiter, NewDecreasingPodOrderer(), nil /* EstimationContext */, nil /* EstimationAnalyserFunc */, false)
+
+			var templates []NodeGroupTemplate
+			for i, zone := range tc.zones {
+				name := fmt.Sprintf("ng-%d", i)
+				templates = append(templates, NodeGroupTemplate{
+					NodeGroup:   testprovider.NewTestNodeGroup(name, 100, 0, 0, true, false, "", nil, nil),
+					NodeInfo:    framework.NewTestNodeInfo(makeNode(1000, 5000, 10, name+"-template", zone)),
+					MaxNewNodes: tc.maxNewNodes[i],
+				})
+			}
+
+			nodeCounts, estimatedPods := estimator.EstimateNodeGroups(tc.podsEquivalenceGroup, templates)
+			assert.Equal(t, tc.expectNodeCounts, nodeCounts)
+			assert.Equal(t, tc.expectPodCount, len(estimatedPods))
+		})
+	}
+}
@@ -68,6 +68,11 @@ func (tbel *thresholdBasedEstimationLimiter) PermissionToAddNode() bool {
 	return true
 }
 
+// Clone returns a new limiter with the same thresholds.
+func (tbel *thresholdBasedEstimationLimiter) Clone() EstimationLimiter {
+	return NewThresholdBasedEstimationLimiter(tbel.thresholds)
+}
+
 // NewThresholdBasedEstimationLimiter returns an EstimationLimiter that will prevent estimation
 // after either a node count of time-based threshold is reached. This is meant to prevent cases
 // where binpacking of hundreds or thousands of nodes takes extremely long time rendering CA
@@ -0,0 +1,337 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"fmt"
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	core_utils "k8s.io/autoscaler/cluster-autoscaler/simulator"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	podutils "k8s.io/autoscaler/cluster-autoscaler/utils/pod"
+	"k8s.io/klog/v2"
+)
+
+// VectorBinpackingNodeEstimator estimates the number of needed nodes with First-Fit Decreasing bin-packing of pod
+// resource requests, treated as vectors of CPU, memory, ephemeral storage, extended resources (e.g. GPUs) and the
+// number of pods. Scheduler predicates are only checked once per pod equivalence group, against the node template.
+// Pods that can't be packed by their requests alone - pods with inter-pod affinity, topology spread constraints, DRA
+// claims, host ports or persistent volumes - are packed the same way as in BinpackingNodeEstimator, on top of the
+// nodes used by the other pods. Such nodes are only added to the cluster snapshot if there are such pods.
+type VectorBinpackingNodeEstimator struct {
+	clusterSnapshot        clustersnapshot.ClusterSnapshot
+	limiter                EstimationLimiter
+	podOrderer             EstimationPodOrderer
+	context                EstimationContext
+	estimationAnalyserFunc EstimationAnalyserFunc // optional
+}
+
+// resourceBin is a new node with the resources that are still free on it, indexed like the resource dimensions.
+type resourceBin struct {
+	free []int64
+}
+
+// binPlacement is a pod placed on a bin.
+type binPlacement struct {
+	pod *apiv1.Pod
+	bin int
+}
+
+// NewVectorBinpackingNodeEstimator builds a new VectorBinpackingNodeEstimator.
+func NewVectorBinpackingNodeEstimator(
+	clusterSnapshot clustersnapshot.ClusterSnapshot,
+	limiter EstimationLimiter,
+	podOrderer EstimationPodOrderer,
+	context EstimationContext,
+	estimationAnalyserFunc EstimationAnalyserFunc,
+) *VectorBinpackingNodeEstimator {
+	return &VectorBinpackingNodeEstimator{
+		clusterSnapshot:        clusterSnapshot,
+		limiter:                limiter,
+		podOrderer:             podOrderer,
+		context:                context,
+		estimationAnalyserFunc: estimationAnalyserFunc,
+	}
+}
+
+// Estimate packs the pods in the order given by the EstimationPodOrderer, the default being DecreasingPodOrderer.
+// Each pod goes to the first new node with enough free resources, and a new node is added only if there isn't one.
+// Returns the number of nodes needed to accommodate the pods and the pods that were accommodated.
+func (e *VectorBinpackingNodeEstimator) Estimate(
+	podsEquivalenceGroups []PodEquivalenceGroup,
+	nodeTemplate *framework.NodeInfo,
+	nodeGroup cloudprovider.NodeGroup,
+) (int, []*apiv1.Pod) {
+	observeBinpackingHeterogeneity(podsEquivalenceGroups, nodeTemplate)
+
+	e.limiter.StartEstimation(podsEquivalenceGroups, nodeGroup, e.context)
+	defer e.limiter.EndEstimation()
+
+	podsEquivalenceGroups = e.podOrderer.Order(podsEquivalenceGroups, nodeTemplate, nodeGroup)
+
+	e.clusterSnapshot.Fork()
+	defer func() {
+		e.clusterSnapshot.Revert()
+	}()
+
+	var vectorGroups, predicateGroups []PodEquivalenceGroup
+	for _, podsEquivalenceGroup := range podsEquivalenceGroups {
+		if pod := podsEquivalenceGroup.Exemplar(); pod == nil {
+			continue
+		} else if requiresPredicates(pod) {
+			predicateGroups = append(predicateGroups, podsEquivalenceGroup)
+		} else {
+			vectorGroups = append(vectorGroups, podsEquivalenceGroup)
+		}
+	}
+
+	vectorGroups, err := e.filterSchedulableOnTemplate(vectorGroups, nodeTemplate)
+	if err != nil {
+		klog.Error(err.Error())
+		return 0, nil
+	}
+	placements, binCount, newNodesAvailable := e.pack(vectorGroups, nodeTemplate)
+
+	binpacking := NewBinpackingNodeEstimator(e.clusterSnapshot, e.limiter, e.podOrderer, e.context, nil, false)
+	estimationState := newEstimationState()
+	binNames := make([]string, binCount)
+	for i := range binNames {
+		if len(predicateGroups) == 0 {
+			binNames[i] = fmt.Sprintf("%s-e-%d", nodeTemplate.Node().Name, i)
+			continue
+		}
+		if err := binpacking.addNewNodeToSnapshot(estimationState, nodeTemplate); err != nil {
+			klog.Errorf("Error while adding new node for template to ClusterSnapshot; %v", err)
+			return 0, nil
+		}
+		binNames[i] = estimationState.lastNodeName
+	}
+	for _, placement := range placements {
+		if len(predicateGroups) > 0 {
+			if err := e.clusterSnapshot.ForceAddPod(placement.pod, binNames[placement.bin]); err != nil {
+				klog.Errorf("Error while adding pod to new node in ClusterSnapshot; %v", err)
+				return 0, nil
+			}
+		}
+		estimationState.trackScheduledPod(placement.pod, binNames[placement.bin])
+	}
+
+	for _, podsEquivalenceGroup := range predicateGroups {
+		remainingPods, err := binpacking.tryToScheduleOnExistingNodes(estimationState, podsEquivalenceGroup.Pods)
+		if err != nil {
+			klog.Error(err.Error())
+			return 0, nil
+		}
+		if newNodesAvailable {
+			newNodesAvailable, err = binpacking.tryToScheduleOnNewNodes(estimationState, nodeTemplate, remainingPods)
+			if err != nil {
+				klog.Error(err.Error())
+				return 0, nil
+			}
+		}
+	}
+
+	if e.estimationAnalyserFunc != nil {
+		e.estimationAnalyserFunc(e.clusterSnapshot, nodeGroup, estimationState.newNodesWithPods)
+	}
+	return len(estimationState.newNodesWithPods), estimationState.scheduledPods
+}
+
+// filterSchedulableOnTemplate returns the groups whose pods pass scheduler predicates on an empty node from the
+// template. The other groups can't be packed on new nodes at all.
+func (e *VectorBinpackingNodeEstimator) filterSchedulableOnTemplate(podsEquivalenceGroups []PodEquivalenceGroup, nodeTemplate *framework.NodeInfo) ([]PodEquivalenceGroup, error) {
+	if len(podsEquivalenceGroups) == 0 {
+		return nil, nil
+	}
+	probe, err := core_utils.SanitizedNodeInfo(nodeTemplate, "e-probe")
+	if err != nil {
+		return nil, fmt.Errorf("Error while creating new node for template; %w", err)
+	}
+	if err := e.clusterSnapshot.AddNodeInfo(probe); err != nil {
+		return nil, fmt.Errorf("Error while adding new node for template to ClusterSnapshot; %w", err)
+	}
+	var schedulable []PodEquivalenceGroup
+	for _, podsEquivalenceGroup := range podsEquivalenceGroups {
+		schedErr := e.clusterSnapshot.CheckPredicates(podsEquivalenceGroup.Exemplar(), probe.Node().Name)
+		if schedErr != nil && schedErr.Type() == clustersnapshot.SchedulingInternalError {
+			return nil, schedErr
+		} else if schedErr == nil {
+			schedulable = append(schedulable, podsEquivalenceGroup)
+		}
+	}
+	// The probe node could affect the predicates of pods packed later, e.g. topology spreading.
+	if err := e.clusterSnapshot.RemoveNodeInfo(probe.Node().Name); err != nil {
+		return nil, err
+	}
+	return schedulable, nil
+}
+
+// pack places the pods of the groups on the first bin with enough free resources, adding a new bin when there is
+// none. Returns the placements, the number of bins and whether more bins can be added.
+func (e *VectorBinpackingNodeEstimator) pack(podsEquivalenceGroups []PodEquivalenceGroup, nodeTemplate *framework.NodeInfo) ([]binPlacement, int, bool) {
+	dimensions := resourceDimensions(podsEquivalenceGroups)
+	emptyBin := templateFreeResources(nodeTemplate, dimensions)
+
+	var placements []binPlacement
+	var bins []*resourceBin
+	newNodesAvailable := true
+	for _, podsEquivalenceGroup := range podsEquivalenceGroups {
+		request := podRequestVector(podsEquivalenceGroup.Exemplar(), dimensions)
+		pods := podsEquivalenceGroup.Pods
+		// Bins only get fuller, so bins that couldn't fit a pod of the group can't fit any other pod of the group.
+		first := 0
+		for len(pods) > 0 {
+			for first < len(bins) && bins[first].capacityFor(request) == 0 {
+				first++
+			}
+			if first == len(bins) {
+				if !newNodesAvailable {
+					break
+				}
+				// The thresholdBasedEstimationLimiter implementation assumes that for each call that returns
+				// true, one node gets added.
+				if !e.limiter.PermissionToAddNode() {
+					newNodesAvailable = false
+					break
+				}
+				bin := &resourceBin{free: append([]int64(nil), emptyBin...)}
+				if bin.capacityFor(request) == 0 {
+					// Predicates passed, but the requests don't fit on an empty node, e.g. because of a resource
+					// that isn't checked by the predicates. Don't add more nodes for this group.
+					klog.V(4).Infof("Pod %s/%s doesn't fit on an empty node from template %s", podsEquivalenceGroup.Exemplar().Namespace, podsEquivalenceGroup.Exemplar().Name, nodeTemplate.Node().Name)
+					break
+				}
+				bins = append(bins, bin)
+			}
+			count := min(bins[first].capacityFor(request), len(pods))
+			bins[first].add(request, count)
+			for _, pod := range pods[:count] {
+				placements = append(placements, binPlacement{pod: pod, bin: first})
+			}
+			pods = pods[count:]
+		}
+	}
+	return placements, len(bins), newNodesAvailable
+}
+
+// capacityFor returns how many pods with the given requests fit on the bin.
+func (b *resourceBin) capacityFor(request []int64) int {
+	capacity := -1
+	for i, requested := range request {
+		if requested <= 0 {
+			continue
+		}
+		fit := int(b.free[i] / requested)
+		if capacity == -1 || fit < capacity {
+			capacity = fit
+		}
+	}
+	return max(capacity, 0)
+}
+
+func (b *resourceBin) add(request []int64, count int) {
+	for i, requested := range request {
+		b.free[i] -= requested * int64(count)
+	}
+}
+
+// resourceDimensions returns the resources the pods are packed on: CPU, memory, ephemeral storage, the number of
+// pods and all other resources requested by the pods, e.g. GPUs.
+func resourceDimensions(podsEquivalenceGroups []PodEquivalenceGroup) []apiv1.ResourceName {
+	dimensions := []apiv1.ResourceName{apiv1.ResourceCPU, apiv1.ResourceMemory, apiv1.ResourceEphemeralStorage, apiv1.ResourcePods}
+	known := make(map[apiv1.ResourceName]bool, len(dimensions))
+	for _, name := range dimensions {
+		known[name] = true
+	}
+	var extra []apiv1.ResourceName
+	for _, podsEquivalenceGroup := range podsEquivalenceGroups {
+		for name := range podutils.PodRequests(podsEquivalenceGroup.Exemplar()) {
+			if !known[name] {
+				known[name] = true
+				extra = append(extra, name)
+			}
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i] < extra[j] })
+	return append(dimensions, extra...)
+}
+
+// podRequestVector returns the requests of the pod in the dimensions. Every pod uses one pod slot.
+func podRequestVector(pod *apiv1.Pod, dimensions []apiv1.ResourceName) []int64 {
+	requests := podutils.PodRequests(pod)
+	vector := make([]int64, len(dimensions))
+	for i, name := range dimensions {
+		if name == apiv1.ResourcePods {
+			vector[i] = 1
+			continue
+		}
+		vector[i] = quantityValue(name, requests[name])
+	}
+	return vector
+}
+
+// templateFreeResources returns the allocatable resources of the template node minus the requests of its pods,
+// e.g. DaemonSets.
+func templateFreeResources(nodeTemplate *framework.NodeInfo, dimensions []apiv1.ResourceName) []int64 {
+	allocatable := nodeTemplate.Node().Status.Allocatable
+	free := make([]int64, len(dimensions))
+	for i, name := range dimensions {
+		free[i] = quantityValue(name, allocatable[name])
+	}
+	for _, podInfo := range nodeTemplate.Pods() {
+		request := podRequestVector(podInfo.Pod, dimensions)
+		for i := range free {
+			free[i] -= request[i]
+		}
+	}
+	return free
+}
+
+func quantityValue(name apiv1.ResourceName, quantity resource.Quantity) int64 {
+	if name == apiv1.ResourceCPU {
+		return quantity.MilliValue()
+	}
+	return quantity.Value()
+}
+
+// requiresPredicates returns true if scheduler predicates need to be checked for every pod like the given one,
+// because whether it fits on a node depends on more than the free resources of the node.
+func requiresPredicates(pod *apiv1.Pod) bool {
+	if pod.Spec.Affinity != nil && (pod.Spec.Affinity.PodAffinity != nil || pod.Spec.Affinity.PodAntiAffinity != nil) {
+		return true
+	}
+	if len(pod.Spec.TopologySpreadConstraints) > 0 || len(pod.Spec.ResourceClaims) > 0 {
+		return true
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil || volume.Ephemeral != nil {
+			return true
+		}
+	}
+	for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+		for _, port := range container.Ports {
+			if port.HostPort > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
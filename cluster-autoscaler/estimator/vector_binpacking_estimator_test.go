@@ -0,0 +1,297 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package estimator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot/testsnapshot"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/framework"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+func makeGpuPod(name string, cpu, mem, gpus int64) *apiv1.Pod {
+	pod := BuildTestPod(name, cpu, mem, WithNamespace("universe"), WithLabels(map[string]string{"app": name}))
+	RequestGpuForPod(pod, gpus)
+	TolerateGpuForPod(pod)
+	return pod
+}
+
+func TestVectorBinpackingEstimate(t *testing.T) {
+	smallPod := BuildTestPod("small", 100, 500, WithNamespace("universe"), WithLabels(map[string]string{"app": "small"}))
+	largePod := BuildTestPod("large", 600, 2000, WithNamespace("universe"), WithLabels(map[string]string{"app": "large"}))
+	memoryPod := BuildTestPod("memory", 100, 3000, WithNamespace("universe"), WithLabels(map[string]string{"app": "memory"}))
+	testCases := []struct {
+		name                 string
+		millicores           int64
+		memory               int64
+		gpus                 int64
+		maxNodes             int
+		podsEquivalenceGroup []PodEquivalenceGroup
+		// compareWithBinpacking is set for cases the binpacking estimator handles, so that the results can be
+		// compared. Vector binpacking should never need more nodes.
+		compareWithBinpacking bool
+		expectNodeCount       int
+		expectPodCount        int
+	}{
+		{
+			name:       "simple resource-based binpacking",
+			millicores: 350*3 - 50,
+			memory:     2 * 1000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(
+				BuildTestPod("estimatee", 350, 1000, WithNamespace("universe"), WithLabels(map[string]string{"app": "estimatee"})), 10)},
+			compareWithBinpacking: true,
+			expectNodeCount:       5,
+			expectPodCount:        10,
+		},
+		{
+			name:       "pods-per-node bound binpacking",
+			millicores: 10000,
+			memory:     20000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(
+				BuildTestPod("estimatee", 10, 100, WithNamespace("universe"), WithLabels(map[string]string{"app": "estimatee"})), 20)},
+			compareWithBinpacking: true,
+			expectNodeCount:       2,
+			expectPodCount:        20,
+		},
+		{
+			name:       "mixed pods fill the gaps left by larger pods",
+			millicores: 1000,
+			memory:     5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{
+				makePodEquivalenceGroup(smallPod, 12),
+				makePodEquivalenceGroup(largePod, 3),
+			},
+			compareWithBinpacking: true,
+			expectNodeCount:       3,
+			expectPodCount:        15,
+		},
+		{
+			name:       "memory and cpu bound pods share nodes",
+			millicores: 1000,
+			memory:     6000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{
+				makePodEquivalenceGroup(memoryPod, 4),
+				makePodEquivalenceGroup(BuildTestPod("cpu", 800, 100, WithNamespace("universe"), WithLabels(map[string]string{"app": "cpu"})), 2),
+			},
+			compareWithBinpacking: true,
+			expectNodeCount:       2,
+			expectPodCount:        6,
+		},
+		{
+			name:       "limiter cuts binpacking",
+			millicores: 1000,
+			memory:     5000,
+			maxNodes:   5,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(
+				BuildTestPod("estimatee", 500, 1000, WithNamespace("universe"), WithLabels(map[string]string{"app": "estimatee"})), 20)},
+			compareWithBinpacking: true,
+			expectNodeCount:       5,
+			expectPodCount:        10,
+		},
+		{
+			name:       "pods that don't fit on an empty node are skipped",
+			millicores: 1000,
+			memory:     5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{
+				makePodEquivalenceGroup(BuildTestPod("huge", 2000, 1000, WithNamespace("universe")), 3),
+				makePodEquivalenceGroup(smallPod, 4),
+			},
+			compareWithBinpacking: true,
+			expectNodeCount:       1,
+			expectPodCount:        4,
+		},
+		{
+			name:       "gpus are packed as a separate dimension",
+			millicores: 4000,
+			memory:     20000,
+			gpus:       2,
+			podsEquivalenceGroup: []PodEquivalenceGroup{
+				makePodEquivalenceGroup(makeGpuPod("gpu", 100, 100, 1), 5),
+			},
+			compareWithBinpacking: true,
+			expectNodeCount:       3,
+			expectPodCount:        5,
+		},
+		{
+			name:       "pods without gpus fill gpu nodes",
+			millicores: 4000,
+			memory:     20000,
+			gpus:       2,
+			podsEquivalenceGroup: []PodEquivalenceGroup{
+				makePodEquivalenceGroup(makeGpuPod("gpu", 1000, 1000, 1), 4),
+				makePodEquivalenceGroup(makeGpuPod("cpu", 1000, 1000, 0), 4),
+			},
+			compareWithBinpacking: true,
+			expectNodeCount:       2,
+			expectPodCount:        8,
+		},
+		{
+			name:       "hostport conflict falls back to predicates",
+			millicores: 1000,
+			memory:     5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(
+				BuildTestPod("estimatee", 200, 1000, WithNamespace("universe"), WithLabels(map[string]string{"app": "estimatee"}), WithHostPort(5555)), 8)},
+			compareWithBinpacking: true,
+			expectNodeCount:       8,
+			expectPodCount:        8,
+		},
+		{
+			name:       "hostname topology spreading falls back to predicates",
+			millicores: 1000,
+			memory:     5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(
+				BuildTestPod("estimatee", 200, 200, WithNamespace("universe"), WithLabels(map[string]string{"app": "estimatee"}), WithMaxSkew(2, "kubernetes.io/hostname", 1)), 8)},
+			compareWithBinpacking: true,
+			expectNodeCount:       4,
+			expectPodCount:        8,
+		},
+		{
+			name:       "zonal topology spreading falls back to predicates",
+			millicores: 1000,
+			memory:     5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{makePodEquivalenceGroup(
+				BuildTestPod("estimatee", 20, 100, WithNamespace("universe"), WithLabels(map[string]string{"app": "estimatee"}), WithMaxSkew(2, "topology.kubernetes.io/zone", 1)), 8)},
+			compareWithBinpacking: true,
+			expectNodeCount:       1,
+			expectPodCount:        2,
+		},
+		{
+			name:       "pods with predicates are packed on nodes used by the other pods",
+			millicores: 1000,
+			memory:     5000,
+			podsEquivalenceGroup: []PodEquivalenceGroup{
+				makePodEquivalenceGroup(largePod, 2),
+				makePodEquivalenceGroup(BuildTestPod("antiaffinity", 300, 1000, WithNamespace("universe"), WithLabels(map[string]string{"app": "antiaffinity"}),
+					WithPodHostnameAntiAffinity(map[string]string{"app": "antiaffinity"})), 3),
+			},
+			compareWithBinpacking: true,
+			expectNodeCount:       3,
+			expectPodCount:        5,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			node := makeNode(tc.millicores, tc.memory, 10, "template", "zone-mars")
+			if tc.gpus > 0 {
+				AddGpusToNode(node, tc.gpus)
+			}
+			nodeInfo := framework.NewTestNodeInfo(node)
+
+			estimate := func(build func(*testing.T, EstimationLimiter) Estimator) (int, []*apiv1.Pod) {
+				limiter := NewThresholdBasedEstimationLimiter([]Threshold{NewStaticThreshold(tc.maxNodes, time.Duration(0))})
+				return build(t, limiter).Estimate(tc.podsEquivalenceGroup, nodeInfo, nil)
+			}
+
+			estimatedNodes, estimatedPods := estimate(func(t *testing.T, limiter EstimationLimiter) Estimator {
+				return NewVectorBinpackingNodeEstimator(newTestEstimationSnapshot(t), limiter, NewDecreasingPodOrderer(), nil /* EstimationContext */, nil /* EstimationAnalyserFunc */)
+			})
+			assert.Equal(t, tc.expectNodeCount, estimatedNodes)
+			assert.Equal(t, tc.expectPodCount, len(estimatedPods))
+
+			if tc.compareWithBinpacking {
+				binpackingNodes, binpackingPods := estimate(func(t *testing.T, limiter EstimationLimiter) Estimator {
+					return NewBinpackingNodeEstimator(newTestEstimationSnapshot(t), limiter, NewDecreasingPodOrderer(), nil /* EstimationContext */, nil /* EstimationAnalyserFunc */, false)
+				})
+				assert.LessOrEqual(t, estimatedNodes, binpackingNodes)
+				assert.ElementsMatch(t, binpackingPods, estimatedPods)
+			}
+		})
+	}
+}
+
+func TestVectorBinpackingEstimateRevertsSnapshot(t *testing.T) {
+	clusterSnapshot := newTestEstimationSnapshot(t)
+	limiter := NewThresholdBasedEstimationLimiter(nil)
+	estimator := NewVectorBinpackingNodeEstimator(clusterSnapshot, limiter, NewDecreasingPodOrderer(), nil /* EstimationContext */, nil /* EstimationAnalyserFunc */)
+	nodeInfo := framework.NewTestNodeInfo(makeNode(1000, 5000, 10, "template", "zone-mars"))
+	podsEquivalenceGroups := []PodEquivalenceGroup{
+		makePodEquivalenceGroup(BuildTestPod("estimatee", 200, 1000, WithNamespace("universe"), WithHostPort(5555)), 3),
+		makePodEquivalenceGroup(BuildTestPod("other", 200, 1000, WithNamespace("universe")), 3),
+	}
+
+	var analysedNodes map[string]bool
+	estimator.estimationAnalyserFunc = func(_ clustersnapshot.ClusterSnapshot, _ cloudprovider.NodeGroup, nodes map[string]bool) {
+		analysedNodes = nodes
+	}
+	estimatedNodes, _ := estimator.Estimate(podsEquivalenceGroups, nodeInfo, nil)
+	assert.Equal(t, 3, estimatedNodes)
+	assert.Len(t, analysedNodes, 3)
+
+	nodeInfos, err := clusterSnapshot.ListNodeInfos()
+	assert.NoError(t, err)
+	assert.Len(t, nodeInfos, 1)
+}
+
+func newTestEstimationSnapshot(t testing.TB) clustersnapshot.ClusterSnapshot {
+	clusterSnapshot := testsnapshot.NewTestSnapshotOrDie(t)
+	// Add one node in different zone to trigger topology spread constraints
+	err := clusterSnapshot.AddNodeInfo(framework.NewTestNodeInfo(makeNode(100, 100, 10, "oldnode", "zone-jupiter")))
+	assert.NoError(t, err)
+	return clusterSnapshot
+}
+
+func BenchmarkVectorBinpackingEstimate(b *testing.B) {
+	millicores := int64(1000)
+	memory := int64(5000)
+	podsPerNode := int64(100)
+	maxNodes := 3000
+	expectNodeCount := 2595
+	expectPodCount := 51000
+	podsEquivalenceGroup := []PodEquivalenceGroup{
+		makePodEquivalenceGroup(
+			BuildTestPod(
+				"estimatee",
+				50,
+				100,
+				WithNamespace("universe"),
+				WithLabels(map[string]string{
+					"app": "estimatee",
+				})),
+			50000,
+		),
+		makePodEquivalenceGroup(
+			BuildTestPod(
+				"estimatee",
+				95,
+				190,
+				WithNamespace("universe"),
+				WithLabels(map[string]string{
+					"app": "estimatee",
+				})),
+			1000,
+		),
+	}
+
+	for i := 0; i < b.N; i++ {
+		limiter := NewThresholdBasedEstimationLimiter([]Threshold{NewStaticThreshold(maxNodes, time.Duration(0))})
+		processor := NewDecreasingPodOrderer()
+		estimator := NewVectorBinpackingNodeEstimator(newTestEstimationSnapshot(b), limiter, processor, nil /* EstimationContext */, nil /* EstimationAnalyserFunc */)
+		node := makeNode(millicores, memory, podsPerNode, "template", "zone-mars")
+		nodeInfo := framework.NewTestNodeInfo(node)
+
+		estimatedNodes, estimatedPods := estimator.Estimate(podsEquivalenceGroup, nodeInfo, nil)
+		assert.Equal(b, expectNodeCount, estimatedNodes)
+		assert.Equal(b, expectPodCount, len(estimatedPods))
+	}
+}
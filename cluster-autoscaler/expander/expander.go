@@ -49,6 +49,9 @@ type Option struct {
 	NodeCount         int
 	Debug             string
 	Pods              []*apiv1.Pod
+	// SplitNodeCounts, if set, is the number of nodes to add to NodeGroup and each of SimilarNodeGroups
+	// by node group id, estimated so that the pods can spread across their zones. NodeCount is their sum.
+	SplitNodeCounts map[string]int
 }
 
 // Strategy describes an interface for selecting the best option when scaling up
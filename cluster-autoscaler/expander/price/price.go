@@ -170,10 +170,11 @@ nextoption:
 		klog.V(5).Infof("Price expander for %s: %s", option.NodeGroup.Id(), debug)
 
 		maybeBestOption := expander.Option{
-			NodeGroup: option.NodeGroup,
-			NodeCount: option.NodeCount,
-			Debug:     fmt.Sprintf("%s | price-expander: %s", option.Debug, debug),
-			Pods:      option.Pods,
+			NodeGroup:       option.NodeGroup,
+			NodeCount:       option.NodeCount,
+			Debug:           fmt.Sprintf("%s | price-expander: %s", option.Debug, debug),
+			Pods:            option.Pods,
+			SplitNodeCounts: option.SplitNodeCounts,
 		}
 		if len(bestOptions) == 0 || bestOptionScore == optionScore {
 			bestOptions = append(bestOptions, maybeBestOption)
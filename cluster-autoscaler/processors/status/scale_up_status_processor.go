@@ -43,6 +43,9 @@ type ScaleUpStatus struct {
 	ConsideredNodeGroups     []cloudprovider.NodeGroup
 	FailedCreationNodeGroups []cloudprovider.NodeGroup
 	FailedResizeNodeGroups   []cloudprovider.NodeGroup
+	// NodesPerZone is the number of nodes requested in each zone, if the scale-up was split between
+	// similar node groups in different zones for pods spreading across zones.
+	NodesPerZone map[string]int
 }
 
 // NoScaleUpInfo contains information about a pod that didn't trigger scale-up.
@@ -21,6 +21,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
 	ca_context "k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/estimator"
@@ -111,3 +112,13 @@ func (o *provReqOrchestrator) ScaleUpToNodeGroupMinSize(
 ) (*status.ScaleUpStatus, ca_errors.AutoscalerError) {
 	return nil, nil
 }
+
+// ScaleUpNodeGroup doesn't have implementation for ProvisioningRequest Orchestrator.
+func (o *provReqOrchestrator) ScaleUpNodeGroup(
+	nodeGroup cloudprovider.NodeGroup,
+	newNodes int,
+	nodes []*apiv1.Node,
+	nodeInfos map[string]*framework.NodeInfo,
+) (*status.ScaleUpStatus, ca_errors.AutoscalerError) {
+	return nil, nil
+}
@@ -20,6 +20,7 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/autoscaler/cluster-autoscaler/apis/provisioningrequest/autoscaling.x-k8s.io/v1"
+	"k8s.io/autoscaler/cluster-autoscaler/cloudprovider"
 	"k8s.io/autoscaler/cluster-autoscaler/clusterstate"
 	ca_context "k8s.io/autoscaler/cluster-autoscaler/context"
 	"k8s.io/autoscaler/cluster-autoscaler/core/scaleup"
@@ -103,3 +104,13 @@ func (o *WrapperOrchestrator) ScaleUpToNodeGroupMinSize(
 ) (*status.ScaleUpStatus, errors.AutoscalerError) {
 	return o.podsOrchestrator.ScaleUpToNodeGroupMinSize(nodes, nodeInfos)
 }
+
+// ScaleUpNodeGroup adds nodes to the node group.
+func (o *WrapperOrchestrator) ScaleUpNodeGroup(
+	nodeGroup cloudprovider.NodeGroup,
+	newNodes int,
+	nodes []*apiv1.Node,
+	nodeInfos map[string]*framework.NodeInfo,
+) (*status.ScaleUpStatus, errors.AutoscalerError) {
+	return o.podsOrchestrator.ScaleUpNodeGroup(nodeGroup, newNodes, nodes, nodeInfos)
+}
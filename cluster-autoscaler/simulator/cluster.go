@@ -100,6 +100,8 @@ const (
 	NoNodeInfo
 	// BlockedByOnCompletionPod - node can't be removed because it has a pod with safe-to-evict=on-completion annotation
 	BlockedByOnCompletionPod
+	// ConsolidationInProgress - node can't be removed yet because it is being consolidated and its replacement isn't ready, or because it is the replacement of nodes being consolidated.
+	ConsolidationInProgress
 )
 
 // RemovalSimulator is a helper object for simulating node removal scenarios.
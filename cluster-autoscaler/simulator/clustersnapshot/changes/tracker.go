@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changes
+
+import (
+	"sync"
+
+	apiv1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/client-go/informers"
+	v1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	klog "k8s.io/klog/v2"
+)
+
+// Tracker tracks the Nodes whose state in the cluster snapshot changed, based on informer events. A Node changes
+// when the Node object, a Pod bound to or nominated for it, one of its node-local ResourceSlices, its CSINode or a
+// ResourceClaim reserved for one of its Pods changes.
+type Tracker struct {
+	podLister v1lister.PodLister
+	mutex     sync.Mutex
+	changed   map[string]bool
+}
+
+// NewTrackerFromInformers creates a Tracker and registers its event handlers in the informers of the factory. It
+// has to be called before the factory is started. ResourceSlices and ResourceClaims are only tracked with DRA
+// enabled, CSINodes only with CSI node aware scheduling enabled.
+func NewTrackerFromInformers(informerFactory informers.SharedInformerFactory, draEnabled, csiEnabled bool) (*Tracker, error) {
+	t := &Tracker{
+		podLister: informerFactory.Core().V1().Pods().Lister(),
+		changed:   make(map[string]bool),
+	}
+	handlers := map[cache.SharedIndexInformer]func(obj interface{}){
+		informerFactory.Core().V1().Nodes().Informer(): t.onNode,
+		informerFactory.Core().V1().Pods().Informer():  t.onPod,
+	}
+	if draEnabled {
+		handlers[informerFactory.Resource().V1().ResourceSlices().Informer()] = t.onResourceSlice
+		handlers[informerFactory.Resource().V1().ResourceClaims().Informer()] = t.onResourceClaim
+	}
+	if csiEnabled {
+		handlers[informerFactory.Storage().V1().CSINodes().Informer()] = t.onCSINode
+	}
+	for informer, handler := range handlers {
+		if _, err := informer.AddEventHandler(eventHandler(handler)); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// eventHandler calls handler with the object of every event. Both objects are passed on updates, so that the Nodes
+// of the previous state are marked as changed too.
+func eventHandler(handler func(obj interface{})) cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: handler,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			handler(oldObj)
+			handler(newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			handler(obj)
+		},
+	}
+}
+
+// ChangedNodes returns the names of Nodes that changed since the previous call.
+func (t *Tracker) ChangedNodes() map[string]bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	changed := t.changed
+	t.changed = make(map[string]bool)
+	return changed
+}
+
+// MarkChanged marks the Node as changed.
+func (t *Tracker) MarkChanged(nodeName string) {
+	if nodeName == "" {
+		return
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.changed[nodeName] = true
+}
+
+func (t *Tracker) onNode(obj interface{}) {
+	if node, ok := obj.(*apiv1.Node); ok {
+		t.MarkChanged(node.Name)
+	}
+}
+
+func (t *Tracker) onPod(obj interface{}) {
+	if pod, ok := obj.(*apiv1.Pod); ok {
+		t.markPodNodesChanged(pod)
+	}
+}
+
+func (t *Tracker) markPodNodesChanged(pod *apiv1.Pod) {
+	t.MarkChanged(pod.Spec.NodeName)
+	t.MarkChanged(pod.Status.NominatedNodeName)
+}
+
+func (t *Tracker) onResourceSlice(obj interface{}) {
+	// ResourceSlices that aren't node-local aren't part of NodeInfos.
+	if slice, ok := obj.(*resourceapi.ResourceSlice); ok && slice.Spec.NodeName != nil {
+		t.MarkChanged(*slice.Spec.NodeName)
+	}
+}
+
+func (t *Tracker) onResourceClaim(obj interface{}) {
+	claim, ok := obj.(*resourceapi.ResourceClaim)
+	if !ok {
+		return
+	}
+	for _, consumer := range claim.Status.ReservedFor {
+		if consumer.Resource != "pods" || consumer.APIGroup != "" {
+			continue
+		}
+		pod, err := t.podLister.Pods(claim.Namespace).Get(consumer.Name)
+		if err != nil {
+			// Deleted pods are handled by pod events.
+			klog.V(5).Infof("Couldn't get pod %s/%s reserving claim %s: %v", claim.Namespace, consumer.Name, claim.Name, err)
+			continue
+		}
+		t.markPodNodesChanged(pod)
+	}
+}
+
+func (t *Tracker) onCSINode(obj interface{}) {
+	if csiNode, ok := obj.(*storagev1.CSINode); ok {
+		t.MarkChanged(csiNode.Name)
+	}
+}
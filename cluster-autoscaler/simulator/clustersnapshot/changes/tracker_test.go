@@ -0,0 +1,109 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package changes
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	apiv1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestTracker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pod := BuildScheduledTestPod("pod", 100, 100, "node1")
+	client := fake.NewSimpleClientset(BuildTestNode("node1", 1000, 1000), pod)
+	informerFactory := informers.NewSharedInformerFactory(client, 0)
+	tracker, err := NewTrackerFromInformers(informerFactory, true, true)
+	assert.NoError(t, err)
+	informerFactory.Start(ctx.Done())
+	informerFactory.WaitForCacheSync(ctx.Done())
+
+	waitForChanges := func(want map[string]bool) {
+		t.Helper()
+		changed := map[string]bool{}
+		assert.Eventually(t, func() bool {
+			for nodeName := range tracker.ChangedNodes() {
+				changed[nodeName] = true
+			}
+			return assert.ObjectsAreEqual(want, changed)
+		}, 5*time.Second, 10*time.Millisecond, "changed nodes: %v", changed)
+	}
+	waitForChanges(map[string]bool{"node1": true})
+
+	_, err = client.CoreV1().Nodes().Create(ctx, BuildTestNode("node2", 1000, 1000), metav1.CreateOptions{})
+	assert.NoError(t, err)
+	waitForChanges(map[string]bool{"node2": true})
+
+	// Both the previous and the new node of a pod are changed.
+	movedPod := pod.DeepCopy()
+	movedPod.Spec.NodeName = ""
+	movedPod.Status.NominatedNodeName = "node3"
+	_, err = client.CoreV1().Pods(pod.Namespace).Update(ctx, movedPod, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	waitForChanges(map[string]bool{"node1": true, "node3": true})
+
+	assert.NoError(t, client.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{}))
+	waitForChanges(map[string]bool{"node3": true})
+
+	nodeName := "node4"
+	slice := &resourceapi.ResourceSlice{ObjectMeta: metav1.ObjectMeta{Name: "slice"}, Spec: resourceapi.ResourceSliceSpec{NodeName: &nodeName}}
+	_, err = client.ResourceV1().ResourceSlices().Create(ctx, slice, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	globalSlice := &resourceapi.ResourceSlice{ObjectMeta: metav1.ObjectMeta{Name: "global-slice"}, Spec: resourceapi.ResourceSliceSpec{AllNodes: ptr.To(true)}}
+	_, err = client.ResourceV1().ResourceSlices().Create(ctx, globalSlice, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	waitForChanges(map[string]bool{"node4": true})
+
+	_, err = client.StorageV1().CSINodes().Create(ctx, &storagev1.CSINode{ObjectMeta: metav1.ObjectMeta{Name: "node5"}}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	waitForChanges(map[string]bool{"node5": true})
+
+	claimPod := BuildScheduledTestPod("claim-pod", 100, 100, "node6")
+	_, err = client.CoreV1().Pods(claimPod.Namespace).Create(ctx, claimPod, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	waitForChanges(map[string]bool{"node6": true})
+	claim := &resourceapi.ResourceClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "claim", Namespace: claimPod.Namespace},
+		Status: resourceapi.ResourceClaimStatus{
+			ReservedFor: []resourceapi.ResourceClaimConsumerReference{{Resource: "pods", Name: claimPod.Name}},
+		},
+	}
+	_, err = client.ResourceV1().ResourceClaims(claim.Namespace).Create(ctx, claim, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	waitForChanges(map[string]bool{"node6": true})
+
+	assert.Empty(t, tracker.ChangedNodes())
+}
+
+func TestTrackerIgnoresPodsWithoutNode(t *testing.T) {
+	tracker := &Tracker{changed: make(map[string]bool)}
+	tracker.onPod(&apiv1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod"}})
+	assert.Empty(t, tracker.ChangedNodes())
+}
@@ -127,6 +127,38 @@ type ClusterSnapshotStore interface {
 	Clear()
 }
 
+// IndependentForkable is implemented by snapshots that can be forked into independent snapshots, which can be
+// modified and used concurrently with each other.
+type IndependentForkable interface {
+	// ForkIndependent returns n snapshots with the current state of the snapshot. The returned snapshots share
+	// the current state, so the snapshot must not be modified while they are in use. Snapshots returned by
+	// previous calls must not be used anymore. Returns an error if the snapshot can't be forked this way.
+	ForkIndependent(n int) ([]ClusterSnapshot, error)
+}
+
+// IndependentForkableStore is implemented by snapshot stores that can be forked into independent stores.
+type IndependentForkableStore interface {
+	// ForkIndependent returns a store with the current state of the store, which can be modified concurrently with
+	// other stores returned by ForkIndependent. The store must not be modified while the returned stores are in use.
+	// Commit and Revert on the returned store never affect the store it was forked from.
+	ForkIndependent() ClusterSnapshotStore
+}
+
+// IncrementalClusterSnapshot is implemented by snapshots that can update the state set in the previous loop instead
+// of rebuilding it from scratch.
+type IncrementalClusterSnapshot interface {
+	// UpdateClusterState has the same effect as SetClusterState, but only rebuilds the NodeInfos of Nodes in
+	// changedNodes, of Nodes added or removed since the previous call, and of Nodes whose object or set of scheduled
+	// Pods differs from the stored one, compared by resourceVersion. The NodeInfos of the other Nodes are reused, so
+	// changedNodes must contain every Node whose ResourceSlices or CSINode changed since the previous call. The state
+	// is rebuilt from scratch on the first call, and after SetClusterState or Clear calls.
+	UpdateClusterState(nodes []*apiv1.Node, scheduledPods []*apiv1.Pod, draSnapshot *drasnapshot.Snapshot, csiSnapshot *csisnapshot.Snapshot, changedNodes map[string]bool) error
+	// VerifyClusterState compares the state set by the last UpdateClusterState call with the state SetClusterState
+	// would set for the provided data, and returns an error describing the differences if there are any. It has to
+	// be called right after UpdateClusterState, with the same data.
+	VerifyClusterState(nodes []*apiv1.Node, scheduledPods []*apiv1.Pod) error
+}
+
 // ErrNodeNotFound means that a node wasn't found in the snapshot.
 var ErrNodeNotFound = errors.New("node not found")
 
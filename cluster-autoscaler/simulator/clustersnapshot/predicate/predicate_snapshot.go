@@ -19,9 +19,15 @@ package predicate
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	apiv1 "k8s.io/api/core/v1"
 	resourceapi "k8s.io/api/resource/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/autoscaler/cluster-autoscaler/simulator/clustersnapshot"
 	csisnapshot "k8s.io/autoscaler/cluster-autoscaler/simulator/csi/snapshot"
 	drasnapshot "k8s.io/autoscaler/cluster-autoscaler/simulator/dynamicresources/snapshot"
@@ -30,6 +36,7 @@ import (
 	"k8s.io/autoscaler/cluster-autoscaler/utils/klogx"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/dynamic-resource-allocation/resourceclaim"
+	klog "k8s.io/klog/v2"
 	schedulerinterface "k8s.io/kube-scheduler/framework"
 	schedulerimpl "k8s.io/kubernetes/pkg/scheduler/framework"
 )
@@ -44,8 +51,16 @@ type PredicateSnapshot struct {
 	parallelism                  int
 	draSnapshot                  *drasnapshot.Snapshot
 	csiSnapshot                  *csisnapshot.Snapshot
+	// forkHandles are the framework handles used by independent forks, reused between ForkIndependent calls.
+	forkHandles []*framework.Handle
+	// baseStore holds the state set by the last UpdateClusterState call. When set, the embedded store is an
+	// independent fork of it.
+	baseStore clustersnapshot.ClusterSnapshotStore
 }
 
+// maxReportedDiffs is the maximum number of differences reported by VerifyClusterState.
+const maxReportedDiffs = 10
+
 // NewPredicateSnapshot builds a PredicateSnapshot.
 func NewPredicateSnapshot(snapshotStore clustersnapshot.ClusterSnapshotStore, fwHandle *framework.Handle, draEnabled bool, parallelism int, enableCSINodeAwareScheduling bool) *PredicateSnapshot {
 	parallelism = max(parallelism, 1)
@@ -70,8 +85,247 @@ func NewPredicateSnapshot(snapshotStore clustersnapshot.ClusterSnapshotStore, fw
 // The provided draSnapshot and csiSnapshot are treated as the source of truth and are eagerly
 // loaded into the created NodeInfo/PodInfo objects.
 func (s *PredicateSnapshot) SetClusterState(nodes []*apiv1.Node, scheduledPods []*apiv1.Pod, draSnapshot *drasnapshot.Snapshot, csiSnapshot *csisnapshot.Snapshot) error {
+	s.resetIncrementalState()
 	s.ClusterSnapshotStore.Clear()
+	s.setDraAndCsiSnapshots(draSnapshot, csiSnapshot)
+
+	nodeInfos, err := s.buildNodeInfos(nodes, scheduledPods)
+	if err != nil {
+		return err
+	}
+
+	// We build NodeInfo objects with all their pods before adding them to the store.
+	// This allows parallelizing PodInfo construction and enables the store to
+	// perform bulk internal state / cache updates per-node rather than per-pod.
+	for _, ni := range nodeInfos {
+		if err := s.ClusterSnapshotStore.StoreNodeInfo(ni); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
 
+// UpdateClusterState resets the snapshot to an unforked state with the same contents SetClusterState would set, but
+// only rebuilds the NodeInfos of changed, added and removed Nodes. The state set by the previous call is kept in a base
+// store, and the snapshot works on an independent fork of it, so that modifications made until the next call never
+// reach it. Stores that can't be forked independently are always rebuilt from scratch.
+func (s *PredicateSnapshot) UpdateClusterState(nodes []*apiv1.Node, scheduledPods []*apiv1.Pod, draSnapshot *drasnapshot.Snapshot, csiSnapshot *csisnapshot.Snapshot, changedNodes map[string]bool) error {
+	if s.baseStore == nil {
+		forkableStore, ok := s.ClusterSnapshotStore.(clustersnapshot.IndependentForkableStore)
+		if err := s.SetClusterState(nodes, scheduledPods, draSnapshot, csiSnapshot); err != nil || !ok {
+			return err
+		}
+		s.baseStore = s.ClusterSnapshotStore
+		s.ClusterSnapshotStore = forkableStore.ForkIndependent()
+		return nil
+	}
+
+	s.setDraAndCsiSnapshots(draSnapshot, csiSnapshot)
+	if err := s.updateBaseStore(nodes, scheduledPods, changedNodes); err != nil {
+		// The base store may be partially updated, rebuild it from scratch next time.
+		s.resetIncrementalState()
+		s.ClusterSnapshotStore.Clear()
+		return err
+	}
+	s.ClusterSnapshotStore = s.baseStore.(clustersnapshot.IndependentForkableStore).ForkIndependent()
+	return nil
+}
+
+func (s *PredicateSnapshot) updateBaseStore(nodes []*apiv1.Node, scheduledPods []*apiv1.Pod, changedNodes map[string]bool) error {
+	storedNodeInfos, err := s.baseStore.NodeInfos().List()
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]schedulerinterface.NodeInfo, len(storedNodeInfos))
+	for _, nodeInfo := range storedNodeInfos {
+		stored[nodeInfo.Node().Name] = nodeInfo
+	}
+	listedPods := make(map[string][]*apiv1.Pod, len(nodes))
+	for _, pod := range scheduledPods {
+		nodeName := scheduledPodNodeName(pod)
+		listedPods[nodeName] = append(listedPods[nodeName], pod)
+	}
+
+	listed := make(map[string]bool, len(nodes))
+	rebuilt := make(map[string]bool)
+	var nodesToBuild []*apiv1.Node
+	missedChanges := 0
+	for _, node := range nodes {
+		listed[node.Name] = true
+		storedNodeInfo, found := stored[node.Name]
+		changed := !found || changedNodes[node.Name]
+		if !changed && !storedObjectsMatch(storedNodeInfo, node, listedPods[node.Name]) {
+			// The change wasn't reported (yet), e.g. because its event is still queued.
+			changed = true
+			missedChanges++
+		}
+		if changed {
+			rebuilt[node.Name] = true
+			nodesToBuild = append(nodesToBuild, node)
+		}
+	}
+	removed := 0
+	for nodeName := range stored {
+		if !listed[nodeName] || rebuilt[nodeName] {
+			if err := s.baseStore.RemoveNodeInfo(nodeName); err != nil {
+				return err
+			}
+		}
+		if !listed[nodeName] {
+			removed++
+		}
+	}
+
+	var podsToBuild []*apiv1.Pod
+	for _, pod := range scheduledPods {
+		if rebuilt[scheduledPodNodeName(pod)] {
+			podsToBuild = append(podsToBuild, pod)
+		}
+	}
+	nodeInfos, err := s.buildNodeInfos(nodesToBuild, podsToBuild)
+	if err != nil {
+		return err
+	}
+	for _, ni := range nodeInfos {
+		if err := s.baseStore.StoreNodeInfo(ni); err != nil {
+			return err
+		}
+	}
+	klog.V(4).Infof("Updated cluster snapshot incrementally: rebuilt %d of %d nodes, %d of them with unreported changes, removed %d nodes", len(nodeInfos), len(nodes), missedChanges, removed)
+	return nil
+}
+
+// storedObjectsMatch returns true if the stored NodeInfo holds the same versions of the Node and of its scheduled Pods
+// as the listed ones.
+func storedObjectsMatch(storedNodeInfo schedulerinterface.NodeInfo, node *apiv1.Node, scheduledPods []*apiv1.Pod) bool {
+	if !sameVersion(storedNodeInfo.Node(), node) {
+		return false
+	}
+	storedPods := storedNodeInfo.GetPods()
+	if len(storedPods) != len(scheduledPods) {
+		return false
+	}
+	storedPodsByUID := make(map[types.UID]*apiv1.Pod, len(storedPods))
+	for _, podInfo := range storedPods {
+		storedPodsByUID[podInfo.GetPod().UID] = podInfo.GetPod()
+	}
+	for _, pod := range scheduledPods {
+		storedPod, found := storedPodsByUID[pod.UID]
+		if !found || !sameVersion(storedPod, pod) {
+			return false
+		}
+	}
+	return true
+}
+
+// sameVersion returns true if both objects are the same object, or have the same resourceVersion.
+func sameVersion(stored, listed metav1.Object) bool {
+	if stored == listed {
+		return true
+	}
+	return stored.GetResourceVersion() != "" && stored.GetResourceVersion() == listed.GetResourceVersion()
+}
+
+// VerifyClusterState compares the state set by the last UpdateClusterState call with NodeInfos built from scratch
+// from the provided data. Nodes, Pods, node-local ResourceSlices and CSINodes are compared, ResourceClaims of Pods
+// aren't, since they may be stale anyway (see the comment on GetNodeInfo).
+func (s *PredicateSnapshot) VerifyClusterState(nodes []*apiv1.Node, scheduledPods []*apiv1.Pod) error {
+	if s.baseStore == nil {
+		return nil
+	}
+	expectedNodeInfos, err := s.buildNodeInfos(nodes, scheduledPods)
+	if err != nil {
+		return err
+	}
+	storedNodeInfos, err := s.baseStore.NodeInfos().List()
+	if err != nil {
+		return err
+	}
+	stored := make(map[string]*framework.NodeInfo, len(storedNodeInfos))
+	for _, schedNodeInfo := range storedNodeInfos {
+		nodeInfo, ok := schedNodeInfo.(*framework.NodeInfo)
+		if !ok {
+			return fmt.Errorf("expected: %T, got: %T in the underlying store", &framework.NodeInfo{}, schedNodeInfo)
+		}
+		stored[nodeInfo.Node().Name] = nodeInfo
+	}
+
+	var diffs []string
+	for _, expected := range expectedNodeInfos {
+		nodeName := expected.Node().Name
+		actual, found := stored[nodeName]
+		if !found {
+			diffs = append(diffs, fmt.Sprintf("node %s is missing", nodeName))
+			continue
+		}
+		delete(stored, nodeName)
+		diffs = append(diffs, diffNodeInfos(expected, actual)...)
+	}
+	for nodeName := range stored {
+		diffs = append(diffs, fmt.Sprintf("node %s doesn't exist anymore", nodeName))
+	}
+	if len(diffs) == 0 {
+		return nil
+	}
+	sort.Strings(diffs)
+	if len(diffs) > maxReportedDiffs {
+		diffs = append(diffs[:maxReportedDiffs], fmt.Sprintf("and %d more", len(diffs)-maxReportedDiffs))
+	}
+	return fmt.Errorf("snapshot differs from a full rebuild: %s", strings.Join(diffs, "; "))
+}
+
+func diffNodeInfos(expected, actual *framework.NodeInfo) []string {
+	nodeName := expected.Node().Name
+	var diffs []string
+	if !apiequality.Semantic.DeepEqual(expected.Node(), actual.Node()) {
+		diffs = append(diffs, fmt.Sprintf("node %s is outdated", nodeName))
+	}
+	if !apiequality.Semantic.DeepEqual(expected.CSINode, actual.CSINode) {
+		diffs = append(diffs, fmt.Sprintf("csi node of node %s is outdated", nodeName))
+	}
+
+	actualSlices := make(map[string]*resourceapi.ResourceSlice, len(actual.LocalResourceSlices))
+	for _, slice := range actual.LocalResourceSlices {
+		actualSlices[slice.Name] = slice
+	}
+	for _, slice := range expected.LocalResourceSlices {
+		if actualSlice, found := actualSlices[slice.Name]; !found || !apiequality.Semantic.DeepEqual(slice, actualSlice) {
+			diffs = append(diffs, fmt.Sprintf("resource slice %s of node %s is missing or outdated", slice.Name, nodeName))
+		}
+		delete(actualSlices, slice.Name)
+	}
+	for sliceName := range actualSlices {
+		diffs = append(diffs, fmt.Sprintf("resource slice %s of node %s doesn't exist anymore", sliceName, nodeName))
+	}
+
+	actualPods := make(map[string]*apiv1.Pod, len(actual.Pods()))
+	for _, podInfo := range actual.Pods() {
+		actualPods[podInfo.Pod.Namespace+"/"+podInfo.Pod.Name] = podInfo.Pod
+	}
+	for _, podInfo := range expected.Pods() {
+		key := podInfo.Pod.Namespace + "/" + podInfo.Pod.Name
+		if actualPod, found := actualPods[key]; !found || !apiequality.Semantic.DeepEqual(podInfo.Pod, actualPod) {
+			diffs = append(diffs, fmt.Sprintf("pod %s on node %s is missing or outdated", key, nodeName))
+		}
+		delete(actualPods, key)
+	}
+	for key := range actualPods {
+		diffs = append(diffs, fmt.Sprintf("pod %s on node %s doesn't exist anymore", key, nodeName))
+	}
+	return diffs
+}
+
+// resetIncrementalState makes the base store the current store again, so that the next UpdateClusterState call
+// rebuilds the state from scratch.
+func (s *PredicateSnapshot) resetIncrementalState() {
+	if s.baseStore != nil {
+		s.ClusterSnapshotStore = s.baseStore
+		s.baseStore = nil
+	}
+}
+
+func (s *PredicateSnapshot) setDraAndCsiSnapshots(draSnapshot *drasnapshot.Snapshot, csiSnapshot *csisnapshot.Snapshot) {
 	if draSnapshot == nil {
 		draSnapshot = drasnapshot.NewEmptySnapshot()
 	}
@@ -81,20 +335,24 @@ func (s *PredicateSnapshot) SetClusterState(nodes []*apiv1.Node, scheduledPods [
 		csiSnapshot = csisnapshot.NewEmptySnapshot()
 	}
 	s.csiSnapshot = csiSnapshot
+}
 
+// buildNodeInfos builds NodeInfos for the provided nodes with their scheduled pods, using the current DRA and CSI
+// snapshots.
+func (s *PredicateSnapshot) buildNodeInfos(nodes []*apiv1.Node, scheduledPods []*apiv1.Pod) ([]*framework.NodeInfo, error) {
 	nodeInfos := make([]*framework.NodeInfo, len(nodes))
 	nodeNameToIdx := make(map[string]int, len(nodes))
 	for i, node := range nodes {
 		var slices []*resourceapi.ResourceSlice
-		if s.draEnabled && draSnapshot != nil {
-			slices, _ = draSnapshot.NodeResourceSlices(node.Name)
+		if s.draEnabled {
+			slices, _ = s.draSnapshot.NodeResourceSlices(node.Name)
 		}
 		ni := framework.NewNodeInfo(node, slices)
 
-		if s.enableCSINodeAwareScheduling && csiSnapshot != nil {
-			csiNode, err := csiSnapshot.Get(node.Name)
+		if s.enableCSINodeAwareScheduling {
+			csiNode, err := s.csiSnapshot.Get(node.Name)
 			if err != nil {
-				return fmt.Errorf("couldn't obtain csi node: %v", err)
+				return nil, fmt.Errorf("couldn't obtain csi node: %v", err)
 			}
 			ni.SetCSINode(csiNode)
 		}
@@ -104,29 +362,24 @@ func (s *PredicateSnapshot) SetClusterState(nodes []*apiv1.Node, scheduledPods [
 	}
 
 	if err := s.setClusterStatePods(nodeInfos, nodeNameToIdx, scheduledPods); err != nil {
-		return err
+		return nil, err
 	}
+	return nodeInfos, nil
+}
 
-	// We build NodeInfo objects with all their pods before adding them to the store.
-	// This allows parallelizing PodInfo construction and enables the store to
-	// perform bulk internal state / cache updates per-node rather than per-pod.
-	for _, ni := range nodeInfos {
-		if err := s.ClusterSnapshotStore.StoreNodeInfo(ni); err != nil {
-			return err
-		}
+// scheduledPodNodeName returns the name of the node the pod is bound to or nominated for.
+func scheduledPodNodeName(pod *apiv1.Pod) string {
+	if pod.Spec.NodeName != "" {
+		return pod.Spec.NodeName
 	}
-
-	return nil
+	return pod.Status.NominatedNodeName
 }
 
 func (s *PredicateSnapshot) setClusterStatePods(nodeInfos []*framework.NodeInfo, nodeNameToIdx map[string]int, scheduledPods []*apiv1.Pod) error {
 	loggingQuota := klogx.PodsLoggingQuota()
 	podInfosForNode := make([][]*framework.PodInfo, len(nodeInfos))
 	for _, pod := range scheduledPods {
-		nodeName := pod.Spec.NodeName
-		if nodeName == "" {
-			nodeName = pod.Status.NominatedNodeName
-		}
+		nodeName := scheduledPodNodeName(pod)
 		nodeIdx, ok := nodeNameToIdx[nodeName]
 		if !ok {
 			klogx.V(1).UpTo(loggingQuota).Warningf("SetClusterState: node %q bound or nominated by pod \"%s/%s\" does not exist in the snapshot", nodeName, pod.Namespace, pod.Name)
@@ -381,6 +634,7 @@ func (s *PredicateSnapshot) CsiSnapshot() *csisnapshot.Snapshot {
 
 // Clear resets the snapshot to an empty, unforked state.
 func (s *PredicateSnapshot) Clear() {
+	s.resetIncrementalState()
 	s.ClusterSnapshotStore.Clear()
 	s.draSnapshot = drasnapshot.NewEmptySnapshot()
 	s.csiSnapshot = csisnapshot.NewEmptySnapshot()
@@ -410,6 +664,48 @@ func (s *PredicateSnapshot) Commit() error {
 	return nil
 }
 
+// ForkIndependent returns n snapshots with the current state of the snapshot, which can be modified and used
+// concurrently with each other. Each of them uses its own framework handle. Independent forks require the
+// underlying store to implement clustersnapshot.IndependentForkableStore, and aren't supported with DRA.
+func (s *PredicateSnapshot) ForkIndependent(n int) ([]clustersnapshot.ClusterSnapshot, error) {
+	if s.draEnabled {
+		return nil, fmt.Errorf("independent forks aren't supported with DRA enabled")
+	}
+	forkableStore, ok := s.ClusterSnapshotStore.(clustersnapshot.IndependentForkableStore)
+	if !ok {
+		return nil, fmt.Errorf("independent forks aren't supported by %T", s.ClusterSnapshotStore)
+	}
+	for len(s.forkHandles) < n {
+		fwHandle, err := s.pluginRunner.fwHandle.Copy()
+		if err != nil {
+			return nil, err
+		}
+		s.forkHandles = append(s.forkHandles, fwHandle)
+	}
+
+	var csiNodes map[string]*storagev1.CSINode
+	if s.enableCSINodeAwareScheduling {
+		csiNodeList, err := s.csiSnapshot.CSINodes().List()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't list csi nodes: %v", err)
+		}
+		csiNodes = make(map[string]*storagev1.CSINode, len(csiNodeList))
+		for _, csiNode := range csiNodeList {
+			csiNodes[csiNode.Name] = csiNode
+		}
+	}
+
+	forks := make([]clustersnapshot.ClusterSnapshot, n)
+	for i := range forks {
+		fork := NewPredicateSnapshot(forkableStore.ForkIndependent(), s.forkHandles[i], false, s.parallelism, s.enableCSINodeAwareScheduling)
+		if s.enableCSINodeAwareScheduling {
+			fork.csiSnapshot = csisnapshot.NewSnapshot(csiNodes)
+		}
+		forks[i] = fork
+	}
+	return forks, nil
+}
+
 // ResourceClaims exposes snapshot as ResourceClaimTracker
 func (s *PredicateSnapshot) ResourceClaims() schedulerinterface.ResourceClaimTracker {
 	return s.draSnapshot.ResourceClaims()
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"maps"
 	"math/rand"
+	"sync"
 	"testing"
 	"time"
 
@@ -2070,3 +2071,161 @@ func TestSetClusterStateConcurrentDRA(t *testing.T) {
 	err = snapshot.SetClusterState(nodes, pods, draSnap, nil)
 	assert.NoError(t, err)
 }
+
+func TestForkIndependent(t *testing.T) {
+	node1 := BuildTestNode("node1", 1000, 1000)
+	node2 := BuildTestNode("node2", 1000, 1000)
+	pod1 := withNodeName(BuildTestPod("pod1", 500, 500), "node1")
+	csiNode1 := &storagev1.CSINode{ObjectMeta: metav1.ObjectMeta{Name: "node1"}}
+	csiNode2 := &storagev1.CSINode{ObjectMeta: metav1.ObjectMeta{Name: "node2"}}
+
+	for _, tc := range []struct {
+		name       string
+		store      clustersnapshot.ClusterSnapshotStore
+		draEnabled bool
+		wantErr    bool
+	}{
+		{name: "delta", store: store.NewDeltaSnapshotStore()},
+		{name: "delta with DRA", store: store.NewDeltaSnapshotStore(), draEnabled: true, wantErr: true},
+		{name: "basic", store: store.NewBasicSnapshotStore(), wantErr: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fwHandle, err := framework.NewTestFrameworkHandle()
+			assert.NoError(t, err)
+			snapshot := NewPredicateSnapshot(tc.store, fwHandle, tc.draEnabled, 1, true)
+			assert.NoError(t, snapshot.SetClusterState([]*apiv1.Node{node1, node2}, []*apiv1.Pod{pod1}, nil, createCSISnapshot(csiNode1, csiNode2)))
+			initialState := getSnapshotState(t, snapshot)
+
+			forks, err := snapshot.ForkIndependent(3)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, forks, 3)
+
+			var wg sync.WaitGroup
+			for i, fork := range forks {
+				wg.Add(1)
+				go func(i int, fork clustersnapshot.ClusterSnapshot) {
+					defer wg.Done()
+					newNode := BuildTestNode(fmt.Sprintf("new-node-%d", i), 1000, 1000)
+					fork.Fork()
+					assert.NoError(t, fork.AddNodeInfo(framework.NewTestNodeInfo(newNode)))
+					assert.NoError(t, fork.SchedulePod(BuildTestPod(fmt.Sprintf("new-pod-%d", i), 500, 500), newNode.Name))
+					assert.NoError(t, fork.Commit())
+					// Committing and reverting the fork itself doesn't affect the original snapshot.
+					assert.NoError(t, fork.Commit())
+					fork.Revert()
+
+					nodeInfos, err := fork.ListNodeInfos()
+					assert.NoError(t, err)
+					assert.ElementsMatch(t, []string{"node1", "node2", newNode.Name}, nodeNames(nodeInfos))
+					_, err = fork.CsiSnapshot().Get("node1")
+					assert.NoError(t, err)
+				}(i, fork)
+			}
+			wg.Wait()
+
+			compareStates(t, initialState, getSnapshotState(t, snapshot))
+		})
+	}
+}
+
+func nodeNames(nodeInfos []*framework.NodeInfo) []string {
+	var names []string
+	for _, nodeInfo := range nodeInfos {
+		names = append(names, nodeInfo.Node().Name)
+	}
+	return names
+}
+
+func TestUpdateClusterState(t *testing.T) {
+	node1 := BuildTestNode("node1", 1000, 1000)
+	node2 := BuildTestNode("node2", 1000, 1000)
+	node3 := BuildTestNode("node3", 1000, 1000)
+	updatedNode2 := node2.DeepCopy()
+	updatedNode2.Labels["updated"] = "true"
+	pod1 := withNodeName(BuildTestPod("pod1", 100, 100), "node1")
+	pod2 := withNodeName(BuildTestPod("pod2", 100, 100), "node2")
+	pod3 := withNodeName(BuildTestPod("pod3", 100, 100), "node3")
+	pod4 := withNodeName(BuildTestPod("pod4", 100, 100), "node1")
+	pod5 := withNodeName(BuildTestPod("pod5", 100, 100), "node1")
+	csiNodes := createCSISnapshot(
+		&storagev1.CSINode{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		&storagev1.CSINode{ObjectMeta: metav1.ObjectMeta{Name: "node2"}},
+		&storagev1.CSINode{ObjectMeta: metav1.ObjectMeta{Name: "node3"}},
+	)
+
+	for _, tc := range []struct {
+		name        string
+		store       clustersnapshot.ClusterSnapshotStore
+		incremental bool
+	}{
+		{name: "delta", store: store.NewDeltaSnapshotStore(), incremental: true},
+		// Stores that can't be forked independently are rebuilt from scratch every time.
+		{name: "basic", store: store.NewBasicSnapshotStore()},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			fwHandle, err := framework.NewTestFrameworkHandle()
+			assert.NoError(t, err)
+			snapshot := NewPredicateSnapshot(tc.store, fwHandle, false, 1, true)
+			wantState := func(nodes []*apiv1.Node, pods []*apiv1.Pod) snapshotState {
+				fwHandle, err := framework.NewTestFrameworkHandle()
+				assert.NoError(t, err)
+				fullSnapshot := NewPredicateSnapshot(store.NewBasicSnapshotStore(), fwHandle, false, 1, true)
+				assert.NoError(t, fullSnapshot.SetClusterState(nodes, pods, nil, csiNodes))
+				return getSnapshotState(t, fullSnapshot)
+			}
+
+			nodes, pods := []*apiv1.Node{node1, node2}, []*apiv1.Pod{pod1, pod2}
+			assert.NoError(t, snapshot.UpdateClusterState(nodes, pods, nil, csiNodes, nil))
+			compareStates(t, wantState(nodes, pods), getSnapshotState(t, snapshot))
+			assert.NoError(t, snapshot.VerifyClusterState(nodes, pods))
+
+			// Modifications made between the updates, including commits, are discarded by the next update.
+			assert.NoError(t, snapshot.AddNodeInfo(framework.NewTestNodeInfo(BuildTestNode("new-node", 1000, 1000))))
+			assert.NoError(t, snapshot.ForceRemovePod("default", "pod1", "node1"))
+			snapshot.Fork()
+			assert.NoError(t, snapshot.SchedulePod(BuildTestPod("new-pod", 100, 100), "node2"))
+			assert.NoError(t, snapshot.Commit())
+			assert.NoError(t, snapshot.Commit())
+
+			nodes, pods = []*apiv1.Node{node1, updatedNode2, node3}, []*apiv1.Pod{pod1, pod2, pod3, pod4}
+			assert.NoError(t, snapshot.UpdateClusterState(nodes, pods, nil, csiNodes, map[string]bool{"node1": true, "node2": true}))
+			compareStates(t, wantState(nodes, pods), getSnapshotState(t, snapshot))
+			assert.NoError(t, snapshot.VerifyClusterState(nodes, pods))
+
+			// Nodes whose object or pods changed are rebuilt even if the change isn't reported.
+			updatedNode3 := node3.DeepCopy()
+			updatedNode3.ResourceVersion = "2"
+			updatedNode3.Labels["updated"] = "true"
+			updatedPod2 := pod2.DeepCopy()
+			updatedPod2.ResourceVersion = "2"
+			updatedPod2.Labels = map[string]string{"updated": "true"}
+			nodes, pods = []*apiv1.Node{node1, updatedNode2, updatedNode3}, []*apiv1.Pod{pod1, updatedPod2, pod3, pod4, pod5}
+			assert.NoError(t, snapshot.UpdateClusterState(nodes, pods, nil, csiNodes, nil))
+			compareStates(t, wantState(nodes, pods), getSnapshotState(t, snapshot))
+			assert.NoError(t, snapshot.VerifyClusterState(nodes, pods))
+
+			// Other nodes that aren't reported as changed are reused, e.g. if only their CSINode changed, or their
+			// objects changed without a new resourceVersion.
+			sameVersionPod2 := updatedPod2.DeepCopy()
+			sameVersionPod2.Labels["updated"] = "again"
+			pods = []*apiv1.Pod{pod1, sameVersionPod2, pod3, pod4, pod5}
+			assert.NoError(t, snapshot.UpdateClusterState(nodes, pods, nil, csiNodes, nil))
+			err = snapshot.VerifyClusterState(nodes, pods)
+			if tc.incremental {
+				assert.ErrorContains(t, err, "pod default/pod2 on node node2 is missing or outdated")
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// SetClusterState resets the state, so the next update rebuilds it from scratch.
+			assert.NoError(t, snapshot.SetClusterState(nil, nil, nil, nil))
+			assert.NoError(t, snapshot.UpdateClusterState(nodes, pods, nil, csiNodes, nil))
+			compareStates(t, wantState(nodes, pods), getSnapshotState(t, snapshot))
+			assert.NoError(t, snapshot.VerifyClusterState(nodes, pods))
+		})
+	}
+}
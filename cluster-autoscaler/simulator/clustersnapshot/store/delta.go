@@ -35,6 +35,7 @@ var (
 // Complexity of some notable operations:
 //
 //	fork - O(1)
+//	independent fork - O(1), cached
 //	revert - O(1)
 //	commit - O(n)
 //	list all pods (no filtering) - O(n), cached
@@ -62,6 +63,9 @@ type deltaSnapshotPodGroupStateLister DeltaSnapshotStore
 
 type internalDeltaSnapshotData struct {
 	baseData *internalDeltaSnapshotData
+	// sharedBase is true if baseData is shared with other independent forks, in which case it must not be
+	// modified, and this data can't be committed to or reverted to it.
+	sharedBase bool
 
 	addedNodeInfoMap    map[string]schedulerinterface.NodeInfo
 	modifiedNodeInfoMap map[string]schedulerinterface.NodeInfo
@@ -296,7 +300,7 @@ func (data *internalDeltaSnapshotData) fork() *internalDeltaSnapshotData {
 }
 
 func (data *internalDeltaSnapshotData) commit() (*internalDeltaSnapshotData, error) {
-	if data.baseData == nil {
+	if data.baseData == nil || data.sharedBase {
 		// do nothing... as in basic snapshot.
 		return data, nil
 	}
@@ -443,10 +447,22 @@ func (snapshot *DeltaSnapshotStore) Fork() {
 	snapshot.data = snapshot.data.fork()
 }
 
+// ForkIndependent returns a new DeltaSnapshotStore on top of the current state of the snapshot. Stores returned by
+// ForkIndependent can be modified concurrently with each other, as long as this snapshot isn't modified while they are
+// in use. They are never committed or reverted into this snapshot.
+// Time: O(1), apart from building the node info list if it isn't cached
+func (snapshot *DeltaSnapshotStore) ForkIndependent() clustersnapshot.ClusterSnapshotStore {
+	// Forks only read the shared data, but reading the node info list of the shared data caches it.
+	snapshot.data.getNodeInfoList()
+	forkedData := snapshot.data.fork()
+	forkedData.sharedBase = true
+	return &DeltaSnapshotStore{data: forkedData}
+}
+
 // Revert reverts snapshot state to moment of forking.
 // Time: O(1)
 func (snapshot *DeltaSnapshotStore) Revert() {
-	if snapshot.data.baseData != nil {
+	if snapshot.data.baseData != nil && !snapshot.data.sharedBase {
 		snapshot.data = snapshot.data.baseData
 	}
 }
@@ -40,6 +40,9 @@ var (
 type Handle struct {
 	Framework        schedulerimpl.Framework
 	DelegatingLister *DelegatingSchedulerSharedLister
+
+	// newHandle builds another Handle with the same configuration.
+	newHandle func() (*Handle, error)
 }
 
 // NewHandle builds a framework Handle based on the provided informers and scheduler config.
@@ -95,5 +98,17 @@ func NewHandle(ctx context.Context, informerFactory informers.SharedInformerFact
 	return &Handle{
 		Framework:        framework,
 		DelegatingLister: sharedLister,
+		newHandle: func() (*Handle, error) {
+			return NewHandle(ctx, informerFactory, schedConfig, draEnabled, csiEnabled)
+		},
 	}, nil
 }
+
+// Copy builds a new Handle with the same configuration. The DelegatingLister of a Handle can only point to
+// one snapshot at a time, so snapshots used concurrently need separate Handles.
+func (h *Handle) Copy() (*Handle, error) {
+	if h.newHandle == nil {
+		return nil, fmt.Errorf("framework handle wasn't created with NewHandle and can't be copied")
+	}
+	return h.newHandle()
+}
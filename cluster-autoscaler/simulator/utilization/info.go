@@ -41,6 +41,30 @@ type Info struct {
 	ResourceName apiv1.ResourceName
 	// Max(CpuUtil, MemUtil) or GpuUtils
 	Utilization float64
+	// CpuRequestUtil and MemRequestUtil are the cpu and memory utilization
+	// based on requests only. They are set when CpuUtil and MemUtil take
+	// the actual usage of pods into account.
+	CpuRequestUtil float64
+	MemRequestUtil float64
+}
+
+// Mode defines how the actual usage of pods is combined with their requests
+// when calculating cpu and memory utilization.
+type Mode string
+
+const (
+	// ModeRequests ignores usage, utilization is based on requests only.
+	ModeRequests Mode = "requests"
+	// ModeUsage uses the usage of pods instead of their requests.
+	ModeUsage Mode = "usage"
+	// ModeMax uses the maximum of the usage and the requests of each pod.
+	ModeMax Mode = "max"
+)
+
+// UsageProvider provides the actual resource usage of pods.
+type UsageProvider interface {
+	// PodUsage returns the usage of a pod, and false if it isn't known.
+	PodUsage(pod *apiv1.Pod) (apiv1.ResourceList, bool)
 }
 
 // Calculate calculates utilization of a node, defined as maximum of (cpu,
@@ -78,6 +102,51 @@ func Calculate(nodeInfo *framework.NodeInfo, skipDaemonSetPods, skipMirrorPods,
 		return Info{}, err
 	}
 
+	return cpuMemInfo(cpu, mem), nil
+}
+
+// CalculateWithUsage calculates utilization of a node like Calculate, except
+// that cpu and memory utilization combine the requests of pods with their
+// usage according to the mode. Pods with unknown usage are accounted for with
+// their requests. The utilization based on requests only is kept in
+// CpuRequestUtil and MemRequestUtil. GPU and dynamic resource utilization is
+// always based on requests.
+func CalculateWithUsage(nodeInfo *framework.NodeInfo, skipDaemonSetPods, skipMirrorPods, draEnabled bool, gpuConfig *cloudprovider.GpuConfig, currentTime time.Time, usageProvider UsageProvider, mode Mode) (Info, error) {
+	requestsInfo, err := Calculate(nodeInfo, skipDaemonSetPods, skipMirrorPods, draEnabled, gpuConfig, currentTime)
+	if err != nil || usageProvider == nil || mode == ModeRequests || mode == "" {
+		return requestsInfo, err
+	}
+	if requestsInfo.ResourceName != apiv1.ResourceCPU && requestsInfo.ResourceName != apiv1.ResourceMemory {
+		return requestsInfo, nil
+	}
+
+	podValue := func(pod *apiv1.Pod, resourceName apiv1.ResourceName, request resource.Quantity) resource.Quantity {
+		podUsage, found := usageProvider.PodUsage(pod)
+		if !found {
+			return request
+		}
+		usage, found := podUsage[resourceName]
+		if !found || mode == ModeMax && usage.Cmp(request) < 0 {
+			return request
+		}
+		return usage
+	}
+	cpu, err := calculateUtilizationOfResource(nodeInfo, apiv1.ResourceCPU, skipDaemonSetPods, skipMirrorPods, currentTime, podValue)
+	if err != nil {
+		return Info{}, err
+	}
+	mem, err := calculateUtilizationOfResource(nodeInfo, apiv1.ResourceMemory, skipDaemonSetPods, skipMirrorPods, currentTime, podValue)
+	if err != nil {
+		return Info{}, err
+	}
+
+	utilization := cpuMemInfo(cpu, mem)
+	utilization.CpuRequestUtil = requestsInfo.CpuUtil
+	utilization.MemRequestUtil = requestsInfo.MemUtil
+	return utilization, nil
+}
+
+func cpuMemInfo(cpu, mem float64) Info {
 	utilization := Info{CpuUtil: cpu, MemUtil: mem}
 
 	if cpu > mem {
@@ -88,11 +157,21 @@ func Calculate(nodeInfo *framework.NodeInfo, skipDaemonSetPods, skipMirrorPods,
 		utilization.Utilization = mem
 	}
 
-	return utilization, nil
+	return utilization
 }
 
 // CalculateUtilizationOfResource calculates utilization of a given resource for a node.
 func CalculateUtilizationOfResource(nodeInfo *framework.NodeInfo, resourceName apiv1.ResourceName, skipDaemonSetPods, skipMirrorPods bool, currentTime time.Time) (float64, error) {
+	return calculateUtilizationOfResource(nodeInfo, resourceName, skipDaemonSetPods, skipMirrorPods, currentTime, func(_ *apiv1.Pod, _ apiv1.ResourceName, request resource.Quantity) resource.Quantity {
+		return request
+	})
+}
+
+// calculateUtilizationOfResource calculates utilization of a given resource
+// for a node, with the amount of the resource used by each pod returned by
+// podValue given its request. DaemonSet and mirror pods are factored out with
+// their requests.
+func calculateUtilizationOfResource(nodeInfo *framework.NodeInfo, resourceName apiv1.ResourceName, skipDaemonSetPods, skipMirrorPods bool, currentTime time.Time, podValue func(*apiv1.Pod, apiv1.ResourceName, resource.Quantity) resource.Quantity) (float64, error) {
 	nodeAllocatable, found := nodeInfo.Node().Status.Allocatable[resourceName]
 	if !found {
 		return 0, fmt.Errorf("failed to get %v from %s", resourceName, nodeInfo.Node().Name)
@@ -108,17 +187,17 @@ func CalculateUtilizationOfResource(nodeInfo *framework.NodeInfo, resourceName a
 	daemonSetAndMirrorPodsUtilization := resource.MustParse("0")
 	for _, podInfo := range nodeInfo.Pods() {
 		podRequests := podutils.PodRequests(podInfo.Pod)
-		resourceValue := podRequests[resourceName]
+		resourceRequest := podRequests[resourceName]
 
 		// factor daemonset pods out of the utilization calculations
 		if skipDaemonSetPods && podutils.IsDaemonSetPod(podInfo.Pod) {
-			daemonSetAndMirrorPodsUtilization.Add(resourceValue)
+			daemonSetAndMirrorPodsUtilization.Add(resourceRequest)
 			continue
 		}
 
 		// factor mirror pods out of the utilization calculations
 		if skipMirrorPods && podutils.IsMirrorPod(podInfo.Pod) {
-			daemonSetAndMirrorPodsUtilization.Add(resourceValue)
+			daemonSetAndMirrorPodsUtilization.Add(resourceRequest)
 			continue
 		}
 
@@ -127,7 +206,7 @@ func CalculateUtilizationOfResource(nodeInfo *framework.NodeInfo, resourceName a
 			continue
 		}
 
-		podsRequest.Add(resourceValue)
+		podsRequest.Add(podValue(podInfo.Pod, resourceName, resourceRequest))
 	}
 
 	return float64(podsRequest.MilliValue()) / float64(nodeAllocatable.MilliValue()-daemonSetAndMirrorPodsUtilization.MilliValue()), nil
@@ -373,6 +373,95 @@ func TestCalculateWithDynamicResources(t *testing.T) {
 	}
 }
 
+type fakeUsageProvider map[string]apiv1.ResourceList
+
+func (p fakeUsageProvider) PodUsage(pod *apiv1.Pod) (apiv1.ResourceList, bool) {
+	usage, found := p[pod.Name]
+	return usage, found
+}
+
+func TestCalculateWithUsage(t *testing.T) {
+	testTime := time.Date(2020, time.December, 18, 17, 0, 0, 0, time.UTC)
+	node := BuildTestNode("node", 2000, 2000000)
+	overrequesting := BuildTestPod("overrequesting", 800, 800000)
+	underrequesting := BuildTestPod("underrequesting", 200, 200000)
+	unknown := BuildTestPod("unknown", 100, 100000)
+	ds := BuildTestPod("ds", 200, 200000)
+	ds.OwnerReferences = GenerateOwnerReferences("ds", "DaemonSet", "apps/v1", "")
+	usage := fakeUsageProvider{
+		"overrequesting":  {apiv1.ResourceCPU: *resource.NewMilliQuantity(100, resource.DecimalSI), apiv1.ResourceMemory: *resource.NewQuantity(100000, resource.DecimalSI)},
+		"underrequesting": {apiv1.ResourceCPU: *resource.NewMilliQuantity(400, resource.DecimalSI)},
+		"ds":              {apiv1.ResourceCPU: *resource.NewMilliQuantity(1000, resource.DecimalSI)},
+	}
+	nodeInfo := framework.NewTestNodeInfo(node, overrequesting, underrequesting, unknown, ds)
+
+	for _, tc := range []struct {
+		name          string
+		usageProvider UsageProvider
+		mode          Mode
+		skipDaemonSet bool
+		want          Info
+	}{
+		{
+			name:          "requests mode",
+			usageProvider: usage,
+			mode:          ModeRequests,
+			want:          Info{CpuUtil: 0.65, MemUtil: 0.65, ResourceName: apiv1.ResourceMemory, Utilization: 0.65},
+		},
+		{
+			name: "no usage provider",
+			mode: ModeUsage,
+			want: Info{CpuUtil: 0.65, MemUtil: 0.65, ResourceName: apiv1.ResourceMemory, Utilization: 0.65},
+		},
+		{
+			name:          "usage mode",
+			usageProvider: usage,
+			mode:          ModeUsage,
+			// cpu: 100 + 400 + 100 (request) + 1000, memory: 100000 + 200000 (request) + 100000 (request) + 200000 (request)
+			want: Info{CpuUtil: 0.8, MemUtil: 0.3, ResourceName: apiv1.ResourceCPU, Utilization: 0.8, CpuRequestUtil: 0.65, MemRequestUtil: 0.65},
+		},
+		{
+			name:          "usage mode without daemonsets",
+			usageProvider: usage,
+			mode:          ModeUsage,
+			skipDaemonSet: true,
+			// Allocatable is reduced by the requests of the DaemonSet pod.
+			want: Info{CpuUtil: 600.0 / 1800, MemUtil: 400000.0 / 1800000, ResourceName: apiv1.ResourceCPU, Utilization: 600.0 / 1800, CpuRequestUtil: 1100.0 / 1800, MemRequestUtil: 1100000.0 / 1800000},
+		},
+		{
+			name:          "max mode",
+			usageProvider: usage,
+			mode:          ModeMax,
+			// cpu: 800 + 400 + 100 + 1000, memory: requests
+			want: Info{CpuUtil: 1.15, MemUtil: 0.65, ResourceName: apiv1.ResourceCPU, Utilization: 1.15, CpuRequestUtil: 0.65, MemRequestUtil: 0.65},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			utilInfo, err := CalculateWithUsage(nodeInfo, tc.skipDaemonSet, false, false, nil, testTime, tc.usageProvider, tc.mode)
+			assert.NoError(t, err)
+			if diff := cmp.Diff(tc.want, utilInfo, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+				t.Errorf("CalculateWithUsage(): unexpected output (-want +got): %s", diff)
+			}
+		})
+	}
+}
+
+func TestCalculateWithUsageGpu(t *testing.T) {
+	testTime := time.Date(2020, time.December, 18, 17, 0, 0, 0, time.UTC)
+	gpuNode := BuildTestNode("gpu_node", 2000, 2000000)
+	AddGpusToNode(gpuNode, 1)
+	pod := BuildTestPod("p1", 100, 200000)
+	RequestGpuForPod(pod, 1)
+	nodeInfo := framework.NewTestNodeInfo(gpuNode, pod)
+	gpuConfig := getGpuConfigFromNode(nodeInfo.Node(), false)
+	usage := fakeUsageProvider{"p1": {apiv1.ResourceCPU: *resource.NewMilliQuantity(0, resource.DecimalSI)}}
+
+	utilInfo, err := CalculateWithUsage(nodeInfo, false, false, false, gpuConfig, testTime, usage, ModeUsage)
+	assert.NoError(t, err)
+	assert.Equal(t, apiv1.ResourceName(gpu.ResourceNvidiaGPU), utilInfo.ResourceName)
+	assert.InEpsilon(t, 1.0, utilInfo.Utilization, 0.01)
+}
+
 func getGpuConfigFromNode(node *apiv1.Node, dra bool) *cloudprovider.GpuConfig {
 	gpuLabel := "cloud.google.com/gke-accelerator"
 	gpuType, hasGpuLabel := node.Labels[gpuLabel]
@@ -0,0 +1,174 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	podMetricsPath        = "/apis/metrics.k8s.io/v1beta1/pods"
+	metricsRequestTimeout = 30 * time.Second
+	// minWindowCoverage is the part of the window the samples of a pod have
+	// to span before their percentile is used. Until then, the pod is
+	// accounted for with its requests.
+	minWindowCoverage = 0.5
+)
+
+// podMetricsList is the subset of the metrics.k8s.io PodMetricsList used here.
+type podMetricsList struct {
+	Items []podMetrics `json:"items"`
+}
+
+type podMetrics struct {
+	metav1.ObjectMeta `json:"metadata"`
+	Timestamp         metav1.Time        `json:"timestamp"`
+	Containers        []containerMetrics `json:"containers"`
+}
+
+type containerMetrics struct {
+	Name  string             `json:"name"`
+	Usage apiv1.ResourceList `json:"usage"`
+}
+
+type usageSample struct {
+	timestamp time.Time
+	usage     apiv1.ResourceList
+}
+
+type metricsAPIProvider struct {
+	client     rest.Interface
+	percentile float64
+	window     time.Duration
+	now        func() time.Time
+	mutex      sync.Mutex
+	samples    map[string][]usageSample
+}
+
+// NewMetricsAPIProvider returns a Provider reading pod usage from the
+// metrics.k8s.io API. The API only reports the current usage, so the provider
+// keeps the samples collected during the window and returns their percentile
+// once they span at least half of the window.
+func NewMetricsAPIProvider(client rest.Interface, percentile float64, window time.Duration) Provider {
+	return &metricsAPIProvider{
+		client:     client,
+		percentile: percentile,
+		window:     window,
+		now:        time.Now,
+		samples:    make(map[string][]usageSample),
+	}
+}
+
+// Refresh collects a new sample of the usage of all pods.
+func (p *metricsAPIProvider) Refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), metricsRequestTimeout)
+	defer cancel()
+	data, err := p.client.Get().AbsPath(podMetricsPath).Do(ctx).Raw()
+	if err != nil {
+		klog.Warningf("Failed to get pod metrics: %v", err)
+		p.dropOldSamples(nil)
+		return
+	}
+	var list podMetricsList
+	if err := json.Unmarshal(data, &list); err != nil {
+		klog.Warningf("Failed to parse pod metrics: %v", err)
+		p.dropOldSamples(nil)
+		return
+	}
+	p.addSamples(list.Items)
+}
+
+func (p *metricsAPIProvider) addSamples(items []podMetrics) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		key := podKey(item.Namespace, item.Name)
+		seen[key] = true
+		samples := p.samples[key]
+		// Metrics are scraped less often than the API is queried.
+		if len(samples) > 0 && !item.Timestamp.Time.After(samples[len(samples)-1].timestamp) {
+			continue
+		}
+		usage := apiv1.ResourceList{}
+		for _, container := range item.Containers {
+			for name, quantity := range container.Usage {
+				total := usage[name]
+				total.Add(quantity)
+				usage[name] = total
+			}
+		}
+		p.samples[key] = append(samples, usageSample{timestamp: item.Timestamp.Time, usage: usage})
+	}
+	p.dropOldSamplesLocked(seen)
+}
+
+func (p *metricsAPIProvider) dropOldSamples(seen map[string]bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.dropOldSamplesLocked(seen)
+}
+
+// dropOldSamplesLocked drops samples older than the window, and all samples
+// of pods that aren't seen anymore if seen isn't nil.
+func (p *metricsAPIProvider) dropOldSamplesLocked(seen map[string]bool) {
+	cutoff := p.now().Add(-p.window)
+	for key, samples := range p.samples {
+		if seen != nil && !seen[key] {
+			delete(p.samples, key)
+			continue
+		}
+		i := 0
+		for i < len(samples) && samples[i].timestamp.Before(cutoff) {
+			i++
+		}
+		if i == len(samples) {
+			delete(p.samples, key)
+		} else if i > 0 {
+			p.samples[key] = samples[i:]
+		}
+	}
+}
+
+// PodUsage returns the percentile of the usage samples of the pod, if they
+// cover enough of the window.
+func (p *metricsAPIProvider) PodUsage(pod *apiv1.Pod) (apiv1.ResourceList, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	samples := p.samples[podKey(pod.Namespace, pod.Name)]
+	if len(samples) < 2 {
+		return nil, false
+	}
+	if coverage := samples[len(samples)-1].timestamp.Sub(samples[0].timestamp); float64(coverage) < minWindowCoverage*float64(p.window) {
+		return nil, false
+	}
+	usages := make([]apiv1.ResourceList, len(samples))
+	for i, sample := range samples {
+		usages[i] = sample.usage
+	}
+	return percentile(usages, p.percentile), true
+}
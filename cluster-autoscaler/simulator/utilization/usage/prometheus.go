@@ -0,0 +1,181 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	klog "k8s.io/klog/v2"
+)
+
+const (
+	// Percentiles over a long window change slowly, there's no point in
+	// querying Prometheus every loop.
+	prometheusRefreshInterval = time.Minute
+	prometheusRequestTimeout  = 30 * time.Second
+
+	cpuQueryFormat    = `sum by (namespace, pod) (quantile_over_time(%g, rate(container_cpu_usage_seconds_total{container!="",container!="POD"}[5m])[%ds:1m]))`
+	memoryQueryFormat = `sum by (namespace, pod) (quantile_over_time(%g, container_memory_working_set_bytes{container!="",container!="POD"}[%ds]))`
+)
+
+// prometheusResponse is the subset of the Prometheus query API response used
+// here.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Value  []interface{}     `json:"value"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+type prometheusProvider struct {
+	url         string
+	client      *http.Client
+	percentile  float64
+	window      time.Duration
+	now         func() time.Time
+	mutex       sync.Mutex
+	usage       map[string]apiv1.ResourceList
+	lastRefresh time.Time
+	lastAttempt time.Time
+}
+
+// NewPrometheusProvider returns a Provider reading the percentile of pod
+// usage over the window from the Prometheus server at the given URL. The
+// usage of a pod is the sum of the percentiles of its containers, as reported
+// by cAdvisor metrics.
+func NewPrometheusProvider(prometheusURL string, percentile float64, window time.Duration) Provider {
+	return &prometheusProvider{
+		url:        strings.TrimSuffix(prometheusURL, "/"),
+		client:     &http.Client{Timeout: prometheusRequestTimeout},
+		percentile: percentile,
+		window:     window,
+		now:        time.Now,
+		usage:      make(map[string]apiv1.ResourceList),
+	}
+}
+
+// Refresh queries Prometheus if it wasn't queried recently, successfully or
+// not. The last known usage is kept if the queries fail, until it is older
+// than the window.
+func (p *prometheusProvider) Refresh() {
+	now := p.now()
+	if now.Sub(p.lastAttempt) < prometheusRefreshInterval {
+		return
+	}
+	// Failing queries are retried after the refresh interval too, rather than
+	// in every loop.
+	p.lastAttempt = now
+	windowSeconds := int64(p.window.Seconds())
+	cpu, err := p.query(fmt.Sprintf(cpuQueryFormat, p.percentile, windowSeconds))
+	if err != nil {
+		klog.Warningf("Failed to query pod cpu usage from Prometheus: %v", err)
+		p.dropStaleUsage(now)
+		return
+	}
+	memory, err := p.query(fmt.Sprintf(memoryQueryFormat, p.percentile, windowSeconds))
+	if err != nil {
+		klog.Warningf("Failed to query pod memory usage from Prometheus: %v", err)
+		p.dropStaleUsage(now)
+		return
+	}
+
+	usage := make(map[string]apiv1.ResourceList, len(cpu))
+	for key, cores := range cpu {
+		usage[key] = apiv1.ResourceList{apiv1.ResourceCPU: *resource.NewMilliQuantity(int64(cores*1000), resource.DecimalSI)}
+	}
+	for key, bytes := range memory {
+		if usage[key] == nil {
+			usage[key] = apiv1.ResourceList{}
+		}
+		usage[key][apiv1.ResourceMemory] = *resource.NewQuantity(int64(bytes), resource.BinarySI)
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.usage = usage
+	p.lastRefresh = now
+}
+
+func (p *prometheusProvider) dropStaleUsage(now time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if now.Sub(p.lastRefresh) > p.window {
+		p.usage = make(map[string]apiv1.ResourceList)
+	}
+}
+
+// query runs an instant query and returns its values by pod.
+func (p *prometheusProvider) query(query string) (map[string]float64, error) {
+	resp, err := p.client.Get(p.url + "/api/v1/query?" + url.Values{"query": []string{query}}.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var response prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to parse response with status %s: %v", resp.Status, err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("query failed with status %s: %s", resp.Status, response.Error)
+	}
+	if response.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("unexpected result type %q", response.Data.ResultType)
+	}
+
+	values := make(map[string]float64, len(response.Data.Result))
+	for _, sample := range response.Data.Result {
+		namespace, name := sample.Metric["namespace"], sample.Metric["pod"]
+		if namespace == "" || name == "" || len(sample.Value) != 2 {
+			continue
+		}
+		valueString, ok := sample.Value[1].(string)
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(valueString, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+			continue
+		}
+		values[podKey(namespace, name)] = value
+	}
+	return values, nil
+}
+
+// PodUsage returns the last known usage of the pod.
+func (p *prometheusProvider) PodUsage(pod *apiv1.Pod) (apiv1.ResourceList, bool) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	usage, found := p.usage[podKey(pod.Namespace, pod.Name)]
+	return usage, found
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"math"
+	"sort"
+
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/autoscaler/cluster-autoscaler/simulator/utilization"
+)
+
+const (
+	// SourceMetricsAPI reads pod usage from the metrics.k8s.io API.
+	SourceMetricsAPI = "metrics-api"
+	// SourcePrometheus reads pod usage from Prometheus.
+	SourcePrometheus = "prometheus"
+)
+
+// Provider provides the usage of pods. Refresh is called at the start of
+// every autoscaler loop.
+type Provider interface {
+	utilization.UsageProvider
+	Refresh()
+}
+
+func podKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// percentile returns the given percentile of the values of each resource,
+// using the nearest-rank method.
+func percentile(samples []apiv1.ResourceList, p float64) apiv1.ResourceList {
+	values := make(map[apiv1.ResourceName][]int64)
+	for _, sample := range samples {
+		for name, quantity := range sample {
+			values[name] = append(values[name], quantity.MilliValue())
+		}
+	}
+	result := make(apiv1.ResourceList, len(values))
+	for name, milliValues := range values {
+		sort.Slice(milliValues, func(i, j int) bool { return milliValues[i] < milliValues[j] })
+		rank := int(math.Ceil(p*float64(len(milliValues)))) - 1
+		if rank < 0 {
+			rank = 0
+		}
+		format := resource.DecimalSI
+		if name == apiv1.ResourceMemory {
+			format = resource.BinarySI
+		}
+		result[name] = *resource.NewMilliQuantity(milliValues[rank], format)
+	}
+	return result
+}
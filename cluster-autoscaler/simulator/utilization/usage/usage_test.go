@@ -0,0 +1,179 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package usage
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	. "k8s.io/autoscaler/cluster-autoscaler/utils/test"
+)
+
+type fakeServer struct {
+	mutex    sync.Mutex
+	status   int
+	response string
+	queries  []string
+}
+
+func (s *fakeServer) set(status int, response string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.status, s.response = status, response
+}
+
+func (s *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.queries = append(s.queries, r.URL.Query().Get("query"))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(s.status)
+	fmt.Fprint(w, s.response)
+}
+
+func podMetricsResponse(timestamp time.Time, cpu, memory string) string {
+	return fmt.Sprintf(`{"kind": "PodMetricsList", "apiVersion": "metrics.k8s.io/v1beta1", "items": [
+		{"metadata": {"name": "p1", "namespace": "default"}, "timestamp": %q, "window": "15s", "containers": [
+			{"name": "a", "usage": {"cpu": %q, "memory": %q}},
+			{"name": "b", "usage": {"cpu": "10m", "memory": "1Mi"}}
+		]}
+	]}`, timestamp.UTC().Format(time.RFC3339), cpu, memory)
+}
+
+func TestMetricsAPIProvider(t *testing.T) {
+	server := &fakeServer{}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: httpServer.URL})
+	require.NoError(t, err)
+
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+	provider := NewMetricsAPIProvider(client.Discovery().RESTClient(), 0.9, 10*time.Minute).(*metricsAPIProvider)
+	provider.now = func() time.Time { return now }
+	pod := BuildTestPod("p1", 100, 100)
+	pod.Namespace = "default"
+
+	_, found := provider.PodUsage(pod)
+	assert.False(t, found, "no samples yet")
+
+	server.set(http.StatusOK, podMetricsResponse(now.Add(-20*time.Minute), "2", "1Gi"))
+	provider.Refresh()
+	_, found = provider.PodUsage(pod)
+	assert.False(t, found, "sample older than the window")
+
+	for i, cpu := range []string{"100m", "300m", "200m", "400m", "500m", "600m", "700m", "800m", "900m", "1"} {
+		server.set(http.StatusOK, podMetricsResponse(now.Add(time.Duration(i-10)*time.Minute+time.Second), cpu, "100Mi"))
+		provider.Refresh()
+		// Duplicate samples are ignored.
+		provider.Refresh()
+		if i < 5 {
+			_, found = provider.PodUsage(pod)
+			assert.False(t, found, "samples cover less than half of the window")
+		}
+	}
+	usage, found := provider.PodUsage(pod)
+	assert.True(t, found)
+	assert.Equal(t, int64(910), usage.Cpu().MilliValue(), "90th percentile of the containers' sum")
+	assert.Equal(t, int64(101*1024*1024), usage.Memory().Value())
+
+	server.set(http.StatusInternalServerError, `{}`)
+	provider.Refresh()
+	_, found = provider.PodUsage(pod)
+	assert.True(t, found, "samples kept on errors")
+
+	server.set(http.StatusOK, `{"items": []}`)
+	provider.Refresh()
+	_, found = provider.PodUsage(pod)
+	assert.False(t, found, "samples of deleted pods dropped")
+}
+
+func prometheusResult(values map[string]string) string {
+	var results []string
+	for pod, value := range values {
+		results = append(results, fmt.Sprintf(`{"metric": {"namespace": "default", "pod": %q}, "value": [1735732800, %q]}`, pod, value))
+	}
+	return fmt.Sprintf(`{"status": "success", "data": {"resultType": "vector", "result": [%s]}}`, strings.Join(results, ","))
+}
+
+func TestPrometheusProvider(t *testing.T) {
+	server := &fakeServer{}
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("query"), "memory") {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, prometheusResult(map[string]string{"p1": "1073741824", "p2": "NaN"}))
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+
+	now := time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+	provider := NewPrometheusProvider(httpServer.URL+"/", 0.95, time.Hour).(*prometheusProvider)
+	provider.now = func() time.Time { return now }
+	p1 := BuildTestPod("p1", 100, 100)
+	p1.Namespace = "default"
+	p2 := BuildTestPod("p2", 100, 100)
+	p2.Namespace = "default"
+
+	server.set(http.StatusOK, prometheusResult(map[string]string{"p1": "0.25", "p2": "1.5"}))
+	provider.Refresh()
+	require.Len(t, server.queries, 1)
+	assert.Contains(t, server.queries[0], "quantile_over_time(0.95,")
+	assert.Contains(t, server.queries[0], "[3600s:1m]")
+
+	usage, found := provider.PodUsage(p1)
+	assert.True(t, found)
+	assert.Equal(t, apiv1.ResourceList{
+		apiv1.ResourceCPU:    *resource.NewMilliQuantity(250, resource.DecimalSI),
+		apiv1.ResourceMemory: *resource.NewQuantity(1073741824, resource.BinarySI),
+	}, usage)
+	usage, found = provider.PodUsage(p2)
+	assert.True(t, found)
+	assert.Equal(t, apiv1.ResourceList{apiv1.ResourceCPU: *resource.NewMilliQuantity(1500, resource.DecimalSI)}, usage, "invalid memory value skipped")
+
+	now = now.Add(30 * time.Second)
+	provider.Refresh()
+	assert.Len(t, server.queries, 1, "not refreshed before the refresh interval")
+
+	now = now.Add(time.Minute)
+	server.set(http.StatusBadRequest, `{"status": "error", "error": "bad query"}`)
+	provider.Refresh()
+	_, found = provider.PodUsage(p1)
+	assert.True(t, found, "usage kept on errors")
+	require.Len(t, server.queries, 2)
+
+	now = now.Add(30 * time.Second)
+	provider.Refresh()
+	assert.Len(t, server.queries, 2, "failed queries not retried before the refresh interval")
+
+	now = now.Add(2 * time.Hour)
+	provider.Refresh()
+	_, found = provider.PodUsage(p1)
+	assert.False(t, found, "stale usage dropped")
+}
@@ -0,0 +1,25 @@
+//go:build tools
+// +build tools
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tools is here, such that we can version dependencies that our helpers in the Makefile and hack/ directory
+// use with the regular go.mod mechanism. They get downloaded with `go mod vendor` and scripts can directly reference
+// the executables used e.g. for generating CRDs, clients and informers.
+package tools
+
+import _ "k8s.io/code-generator"
@@ -0,0 +1,292 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MultidimensionalPodAutoscalerList is a list of MultidimensionalPodAutoscaler objects.
+type MultidimensionalPodAutoscalerList struct {
+	metav1.TypeMeta `json:",inline"`
+	// metadata is the standard list metadata.
+	// +optional
+	metav1.ListMeta `json:"metadata"`
+
+	// items is the list of multidimensional pod autoscaler objects.
+	Items []MultidimensionalPodAutoscaler `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:storageversion
+// +kubebuilder:resource:shortName=mpa
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Mode",type="string",JSONPath=".spec.policy.updateMode"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.currentReplicas"
+// +kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=".status.desiredReplicas"
+// +kubebuilder:printcolumn:name="CPU",type="string",JSONPath=".status.recommendation.containerRecommendations[0].target.cpu"
+// +kubebuilder:printcolumn:name="Mem",type="string",JSONPath=".status.recommendation.containerRecommendations[0].target.memory"
+// +kubebuilder:printcolumn:name="Provided",type="string",JSONPath=".status.conditions[?(@.type=='RecommendationProvided')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:metadata:annotations="api-approved.kubernetes.io=https://github.com/kubernetes/kubernetes/pull/63797"
+
+// MultidimensionalPodAutoscaler is the configuration for a multidimensional pod
+// autoscaler, which automatically manages both the number of replicas and the
+// resources of the pods of a workload based on historical and real time
+// resource utilization.
+type MultidimensionalPodAutoscaler struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the behavior of the autoscaler.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status.
+	Spec MultidimensionalPodAutoscalerSpec `json:"spec"`
+
+	// Current information about the autoscaler.
+	// +optional
+	Status MultidimensionalPodAutoscalerStatus `json:"status,omitempty"`
+}
+
+// MultidimensionalPodAutoscalerSpec is the specification of the behavior of the autoscaler.
+type MultidimensionalPodAutoscalerSpec struct {
+	// ScaleTargetRef points to the controller managing the set of pods for the
+	// autoscaler to control - e.g. Deployment, StatefulSet. The controller must
+	// implement the scale subresource, which is used both to retrieve the pod
+	// set and to modify the replica count.
+	ScaleTargetRef *autoscalingv1.CrossVersionObjectReference `json:"scaleTargetRef"`
+
+	// Describes the rules on how changes are applied to the pods and to the
+	// replica count. If not specified, all fields in the `PodUpdatePolicy` are
+	// set to their default values.
+	// +optional
+	Policy *PodUpdatePolicy `json:"policy,omitempty"`
+
+	// Goals of the autoscaler. Only a single resource metric with a CPU
+	// utilization target is supported. If not specified, the number of replicas
+	// is not changed by the autoscaler.
+	// +optional
+	Goals *Goals `json:"goals,omitempty"`
+
+	// Constraints on the number of replicas and on the recommended resources
+	// of the containers.
+	// +optional
+	Constraints *ScalingConstraints `json:"constraints,omitempty"`
+
+	// Recommender responsible for generating recommendation for this object.
+	// List should be empty (then the default recommender will generate the
+	// recommendation) or contain exactly one recommender.
+	// +optional
+	Recommenders []*MultidimensionalPodAutoscalerRecommenderSelector `json:"recommenders,omitempty"`
+}
+
+// MultidimensionalPodAutoscalerRecommenderSelector points to a specific Multidimensional Pod Autoscaler recommender.
+// In the future it might pass parameters to the recommender.
+type MultidimensionalPodAutoscalerRecommenderSelector struct {
+	// Name of the recommender responsible for generating recommendation for this object.
+	Name string `json:"name"`
+}
+
+// PodUpdatePolicy describes the rules on how changes are applied to the pods.
+type PodUpdatePolicy struct {
+	// Controls when autoscaler applies changes to the pod resources and to the
+	// replica count. The default is 'Auto'.
+	// +optional
+	UpdateMode *UpdateMode `json:"updateMode,omitempty"`
+}
+
+// UpdateMode controls when autoscaler applies changes to the pods.
+// +kubebuilder:validation:Enum=Off;Auto
+type UpdateMode string
+
+const (
+	// UpdateModeOff means that autoscaler never changes the pods nor the
+	// number of replicas. The recommender still sets the recommended resources
+	// and the desired number of replicas in the status, which can be used e.g.
+	// for a dry run.
+	UpdateModeOff UpdateMode = "Off"
+	// UpdateModeAuto means that autoscaler sets the number of replicas through
+	// the scale subresource of the target and resizes the resources of the
+	// running pods in-place.
+	UpdateModeAuto UpdateMode = "Auto"
+)
+
+// Goals describes the objectives that the autoscaler tries to meet.
+type Goals struct {
+	// Metrics contains the specifications for which to use to calculate the
+	// desired replica count, in the same format as the metrics of a
+	// HorizontalPodAutoscaler. Only a resource metric for CPU with a target of
+	// type Utilization is supported.
+	// +optional
+	Metrics []autoscalingv2.MetricSpec `json:"metrics,omitempty"`
+}
+
+// ScalingConstraints describes the constraints on horizontal and vertical
+// scaling.
+type ScalingConstraints struct {
+	// Global constraints on the number of replicas.
+	// +optional
+	Global *HorizontalScalingConstraints `json:"global,omitempty"`
+
+	// Specifies the type of recommendations that will be computed
+	// (and possibly applied) by the autoscaler.
+	// If not specified, the default of [ResourceCPU, ResourceMemory] will be used.
+	// +optional
+	ContainerControlledResources []corev1.ResourceName `json:"containerControlledResources,omitempty"`
+
+	// Per-container constraints on the recommended resources.
+	// +optional
+	Container []VerticalScalingConstraints `json:"container,omitempty"`
+}
+
+// HorizontalScalingConstraints describes the constraints on the number of
+// replicas.
+type HorizontalScalingConstraints struct {
+	// Lower limit for the number of replicas to which the autoscaler can scale
+	// down. Defaults to 1 pod.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MinReplicas *int32 `json:"minReplicas,omitempty"`
+
+	// Upper limit for the number of replicas to which the autoscaler can scale
+	// up. It cannot be less than minReplicas.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxReplicas *int32 `json:"maxReplicas,omitempty"`
+}
+
+// VerticalScalingConstraints describes the constraints on the recommended
+// resources of a container.
+type VerticalScalingConstraints struct {
+	// Name of the container or DefaultContainerResourcePolicy, in which
+	// case the constraints apply to all containers that don't have their own.
+	Name string `json:"name,omitempty"`
+
+	// Whether autoscaler is enabled for the container. The default is "Auto".
+	// +optional
+	Mode *vpa_types.ContainerScalingMode `json:"mode,omitempty"`
+
+	// Constraints on the recommended requests of the container.
+	// +optional
+	Requests *ResourceConstraints `json:"requests,omitempty"`
+}
+
+// ResourceConstraints describes the range of recommended resources.
+type ResourceConstraints struct {
+	// Specifies the minimal amount of resources that will be recommended
+	// for the container. The default is no minimum.
+	// +optional
+	MinAllowed corev1.ResourceList `json:"minAllowed,omitempty"`
+
+	// Specifies the maximum amount of resources that will be recommended
+	// for the container. The default is no maximum.
+	// +optional
+	MaxAllowed corev1.ResourceList `json:"maxAllowed,omitempty"`
+}
+
+const (
+	// DefaultContainerResourcePolicy can be passed as
+	// VerticalScalingConstraints.Name to specify the default constraints.
+	DefaultContainerResourcePolicy = "*"
+)
+
+// MultidimensionalPodAutoscalerStatus describes the runtime state of the autoscaler.
+type MultidimensionalPodAutoscalerStatus struct {
+	// lastScaleTime is the last time the autoscaler changed the number of
+	// replicas.
+	// +optional
+	LastScaleTime *metav1.Time `json:"lastScaleTime,omitempty"`
+
+	// currentReplicas is the number of replicas of pods managed by this
+	// autoscaler, as last seen by the autoscaler.
+	// +optional
+	CurrentReplicas int32 `json:"currentReplicas,omitempty"`
+
+	// desiredReplicas is the number of replicas of pods managed by this
+	// autoscaler, as last calculated by the autoscaler.
+	// +optional
+	DesiredReplicas int32 `json:"desiredReplicas,omitempty"`
+
+	// The most recently computed amount of resources recommended by the
+	// autoscaler for the controlled pods.
+	// +optional
+	Recommendation *vpa_types.RecommendedPodResources `json:"recommendation,omitempty"`
+
+	// currentMetrics is the last read state of the metrics used by this
+	// autoscaler.
+	// +optional
+	CurrentMetrics []autoscalingv2.MetricStatus `json:"currentMetrics,omitempty"`
+
+	// Conditions is the set of conditions required for this autoscaler to scale its target,
+	// and indicates whether or not those conditions are met.
+	// +optional
+	// +patchMergeKey=type
+	// +patchStrategy=merge
+	Conditions []MultidimensionalPodAutoscalerCondition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+}
+
+// MultidimensionalPodAutoscalerConditionType are the valid conditions of
+// a MultidimensionalPodAutoscaler.
+type MultidimensionalPodAutoscalerConditionType string
+
+var (
+	// RecommendationProvided indicates whether the MPA recommender was able to calculate a recommendation.
+	RecommendationProvided MultidimensionalPodAutoscalerConditionType = "RecommendationProvided"
+	// LowConfidence indicates whether the MPA recommender has low confidence in the recommendation for
+	// some of containers.
+	LowConfidence MultidimensionalPodAutoscalerConditionType = "LowConfidence"
+	// NoPodsMatched indicates that label selector used with MPA object didn't match any pods.
+	NoPodsMatched MultidimensionalPodAutoscalerConditionType = "NoPodsMatched"
+	// FetchingHistory indicates that MPA recommender is in the process of loading additional history samples.
+	FetchingHistory MultidimensionalPodAutoscalerConditionType = "FetchingHistory"
+	// ConfigUnsupported indicates that this MPA configuration is unsupported
+	// and recommendations will not be provided for it.
+	ConfigUnsupported MultidimensionalPodAutoscalerConditionType = "ConfigUnsupported"
+	// ScalingLimited indicates that the desired number of replicas was capped
+	// by the minReplicas or maxReplicas constraints.
+	ScalingLimited MultidimensionalPodAutoscalerConditionType = "ScalingLimited"
+	// AbleToScale indicates whether the MPA updater was able to set the number
+	// of replicas of the target.
+	AbleToScale MultidimensionalPodAutoscalerConditionType = "AbleToScale"
+)
+
+// MultidimensionalPodAutoscalerCondition describes the state of
+// a MultidimensionalPodAutoscaler at a certain point.
+type MultidimensionalPodAutoscalerCondition struct {
+	// type describes the current condition
+	Type MultidimensionalPodAutoscalerConditionType `json:"type"`
+	// status is the status of the condition (True, False, Unknown)
+	Status corev1.ConditionStatus `json:"status"`
+	// lastTransitionTime is the last time the condition transitioned from
+	// one status to another
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// reason is the reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// message is a human-readable explanation containing details about
+	// the transition
+	// +optional
+	Message string `json:"message,omitempty"`
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package input
+
+import (
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	"k8s.io/apimachinery/pkg/labels"
+	autoscalingv2listers "k8s.io/client-go/listers/autoscaling/v2"
+
+	mpa_lister "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/listers/autoscaling.k8s.io/v1alpha1"
+	mpa_api_util "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/utils/mpa"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_lister "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/listers/autoscaling.k8s.io/v1"
+)
+
+// NewVpaLister returns a VerticalPodAutoscalerLister listing the MPA objects
+// converted to VPA objects, so that they can be fed to the VPA cluster state.
+func NewVpaLister(mpaLister mpa_lister.MultidimensionalPodAutoscalerLister) vpa_lister.VerticalPodAutoscalerLister {
+	return &vpaLister{mpaLister: mpaLister}
+}
+
+type vpaLister struct {
+	mpaLister mpa_lister.MultidimensionalPodAutoscalerLister
+}
+
+func (l *vpaLister) List(selector labels.Selector) ([]*vpa_types.VerticalPodAutoscaler, error) {
+	mpas, err := l.mpaLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	vpas := make([]*vpa_types.VerticalPodAutoscaler, 0, len(mpas))
+	for _, mpa := range mpas {
+		vpas = append(vpas, mpa_api_util.ConvertToVpa(mpa))
+	}
+	return vpas, nil
+}
+
+func (l *vpaLister) VerticalPodAutoscalers(namespace string) vpa_lister.VerticalPodAutoscalerNamespaceLister {
+	return &vpaNamespaceLister{mpaNamespaceLister: l.mpaLister.MultidimensionalPodAutoscalers(namespace)}
+}
+
+type vpaNamespaceLister struct {
+	mpaNamespaceLister mpa_lister.MultidimensionalPodAutoscalerNamespaceLister
+}
+
+func (l *vpaNamespaceLister) List(selector labels.Selector) ([]*vpa_types.VerticalPodAutoscaler, error) {
+	mpas, err := l.mpaNamespaceLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	vpas := make([]*vpa_types.VerticalPodAutoscaler, 0, len(mpas))
+	for _, mpa := range mpas {
+		vpas = append(vpas, mpa_api_util.ConvertToVpa(mpa))
+	}
+	return vpas, nil
+}
+
+func (l *vpaNamespaceLister) Get(name string) (*vpa_types.VerticalPodAutoscaler, error) {
+	mpa, err := l.mpaNamespaceLister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return mpa_api_util.ConvertToVpa(mpa), nil
+}
+
+// NewHpaLister returns a HorizontalPodAutoscalerLister listing the MPA
+// objects converted to HPA objects, so that the VPA cluster state feeder
+// coordinates the CPU recommendation with the CPU utilization goal of the MPA.
+func NewHpaLister(mpaLister mpa_lister.MultidimensionalPodAutoscalerLister) autoscalingv2listers.HorizontalPodAutoscalerLister {
+	return &hpaLister{mpaLister: mpaLister}
+}
+
+type hpaLister struct {
+	mpaLister mpa_lister.MultidimensionalPodAutoscalerLister
+}
+
+func (l *hpaLister) List(selector labels.Selector) ([]*autoscalingv2.HorizontalPodAutoscaler, error) {
+	mpas, err := l.mpaLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	hpas := make([]*autoscalingv2.HorizontalPodAutoscaler, 0, len(mpas))
+	for _, mpa := range mpas {
+		hpas = append(hpas, mpa_api_util.ConvertToHpa(mpa))
+	}
+	return hpas, nil
+}
+
+func (l *hpaLister) HorizontalPodAutoscalers(namespace string) autoscalingv2listers.HorizontalPodAutoscalerNamespaceLister {
+	return &hpaNamespaceLister{mpaNamespaceLister: l.mpaLister.MultidimensionalPodAutoscalers(namespace)}
+}
+
+type hpaNamespaceLister struct {
+	mpaNamespaceLister mpa_lister.MultidimensionalPodAutoscalerNamespaceLister
+}
+
+func (l *hpaNamespaceLister) List(selector labels.Selector) ([]*autoscalingv2.HorizontalPodAutoscaler, error) {
+	mpas, err := l.mpaNamespaceLister.List(selector)
+	if err != nil {
+		return nil, err
+	}
+	hpas := make([]*autoscalingv2.HorizontalPodAutoscaler, 0, len(mpas))
+	for _, mpa := range mpas {
+		hpas = append(hpas, mpa_api_util.ConvertToHpa(mpa))
+	}
+	return hpas, nil
+}
+
+func (l *hpaNamespaceLister) Get(name string) (*autoscalingv2.HorizontalPodAutoscaler, error) {
+	mpa, err := l.mpaNamespaceLister.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return mpa_api_util.ConvertToHpa(mpa), nil
+}
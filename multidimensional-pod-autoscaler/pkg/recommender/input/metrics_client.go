@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package input
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/metrics"
+)
+
+// RecordingMetricsClient is a MetricsClient that keeps the snapshots it
+// returned last, so that the current usage of workloads can be computed after
+// the VPA cluster state feeder loaded them.
+type RecordingMetricsClient struct {
+	client metrics.MetricsClient
+
+	mutex         sync.Mutex
+	lastSnapshots []*metrics.ContainerMetricsSnapshot
+}
+
+// NewRecordingMetricsClient returns a RecordingMetricsClient wrapping the given client.
+func NewRecordingMetricsClient(client metrics.MetricsClient) *RecordingMetricsClient {
+	return &RecordingMetricsClient{client: client}
+}
+
+// GetContainersMetrics implements metrics.MetricsClient.
+func (c *RecordingMetricsClient) GetContainersMetrics(ctx context.Context) ([]*metrics.ContainerMetricsSnapshot, error) {
+	snapshots, err := c.client.GetContainersMetrics(ctx)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.lastSnapshots = snapshots
+	return snapshots, err
+}
+
+// LastSnapshots returns the snapshots returned by the last call to GetContainersMetrics.
+func (c *RecordingMetricsClient) LastSnapshots() []*metrics.ContainerMetricsSnapshot {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.lastSnapshots
+}
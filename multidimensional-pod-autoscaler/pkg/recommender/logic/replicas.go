@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"math"
+
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+)
+
+// DefaultReplicasTolerance is the default relative difference between the
+// current and the target CPU utilization under which the number of replicas
+// is not changed, same as the HorizontalPodAutoscaler's.
+const DefaultReplicasTolerance = 0.1
+
+// WorkloadCPUUsage is the current CPU usage of the replicas of a workload.
+type WorkloadCPUUsage struct {
+	// TotalUsage is the CPU usage summed over all the replicas with metrics.
+	TotalUsage model.ResourceAmount
+	// ReplicasWithMetrics is the number of replicas that reported metrics.
+	ReplicasWithMetrics int
+}
+
+// ReplicasRecommender computes the number of replicas of a workload.
+type ReplicasRecommender interface {
+	// GetDesiredReplicas returns the number of replicas at which the average
+	// CPU utilization of the replicas would meet the target, given the CPU
+	// request of a replica. It returns the current number of replicas if the
+	// utilization is within tolerance of the target or can't be computed.
+	GetDesiredReplicas(currentReplicas int32, usage WorkloadCPUUsage, request model.ResourceAmount, targetUtilization float64) int32
+}
+
+type replicasRecommender struct {
+	tolerance float64
+}
+
+// NewReplicasRecommender returns a ReplicasRecommender which doesn't change
+// the number of replicas while the CPU utilization is within the given
+// tolerance of the target.
+func NewReplicasRecommender(tolerance float64) ReplicasRecommender {
+	return &replicasRecommender{tolerance: tolerance}
+}
+
+func (r *replicasRecommender) GetDesiredReplicas(currentReplicas int32, usage WorkloadCPUUsage, request model.ResourceAmount, targetUtilization float64) int32 {
+	if usage.ReplicasWithMetrics == 0 || request <= 0 || targetUtilization <= 0 {
+		return currentReplicas
+	}
+	targetUsage := float64(request) * targetUtilization
+	usageRatio := float64(usage.TotalUsage) / (float64(usage.ReplicasWithMetrics) * targetUsage)
+	missingReplicas := int(currentReplicas) - usage.ReplicasWithMetrics
+	if missingReplicas <= 0 {
+		if math.Abs(1.0-usageRatio) <= r.tolerance {
+			return currentReplicas
+		}
+		return int32(math.Ceil(usageRatio * float64(usage.ReplicasWithMetrics)))
+	}
+
+	// Like the HorizontalPodAutoscaler, assume that the replicas without
+	// metrics use nothing when scaling up and exactly the target when
+	// scaling down, so that they dampen the change rather than amplify it.
+	totalUsage := float64(usage.TotalUsage)
+	if usageRatio < 1.0 {
+		totalUsage += float64(missingReplicas) * targetUsage
+	}
+	newUsageRatio := totalUsage / (float64(currentReplicas) * targetUsage)
+	if math.Abs(1.0-newUsageRatio) <= r.tolerance || (usageRatio < 1.0) != (newUsageRatio < 1.0) {
+		return currentReplicas
+	}
+	newReplicas := int32(math.Ceil(newUsageRatio * float64(currentReplicas)))
+	if (newUsageRatio < 1.0 && newReplicas > currentReplicas) || (newUsageRatio > 1.0 && newReplicas < currentReplicas) {
+		return currentReplicas
+	}
+	return newReplicas
+}
+
+// CapReplicas returns the number of replicas capped to the given bounds and
+// whether it had to be capped.
+func CapReplicas(replicas, minReplicas, maxReplicas int32) (int32, bool) {
+	if replicas < minReplicas {
+		return minReplicas, true
+	}
+	if replicas > maxReplicas {
+		return maxReplicas, true
+	}
+	return replicas, false
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+)
+
+func TestGetDesiredReplicas(t *testing.T) {
+	testCases := []struct {
+		name              string
+		currentReplicas   int32
+		usage             WorkloadCPUUsage
+		request           model.ResourceAmount
+		targetUtilization float64
+		expected          int32
+	}{
+		{
+			name:              "scales up when utilization is above target",
+			currentReplicas:   4,
+			usage:             WorkloadCPUUsage{TotalUsage: model.CPUAmountFromCores(4), ReplicasWithMetrics: 4},
+			request:           model.CPUAmountFromCores(1),
+			targetUtilization: 0.5,
+			expected:          8,
+		},
+		{
+			name:              "scales down when utilization is below target",
+			currentReplicas:   4,
+			usage:             WorkloadCPUUsage{TotalUsage: model.CPUAmountFromCores(1), ReplicasWithMetrics: 4},
+			request:           model.CPUAmountFromCores(1),
+			targetUtilization: 0.5,
+			expected:          2,
+		},
+		{
+			name:              "keeps replicas within tolerance",
+			currentReplicas:   4,
+			usage:             WorkloadCPUUsage{TotalUsage: model.CPUAmountFromCores(2.1), ReplicasWithMetrics: 4},
+			request:           model.CPUAmountFromCores(1),
+			targetUtilization: 0.5,
+			expected:          4,
+		},
+		{
+			name:              "replicas without metrics use nothing when scaling up",
+			currentReplicas:   5,
+			usage:             WorkloadCPUUsage{TotalUsage: model.CPUAmountFromCores(3), ReplicasWithMetrics: 2},
+			request:           model.CPUAmountFromCores(1),
+			targetUtilization: 0.5,
+			expected:          6,
+		},
+		{
+			name:              "replicas without metrics don't turn a scale up into a scale down",
+			currentReplicas:   10,
+			usage:             WorkloadCPUUsage{TotalUsage: model.CPUAmountFromCores(3), ReplicasWithMetrics: 5},
+			request:           model.CPUAmountFromCores(1),
+			targetUtilization: 0.5,
+			expected:          10,
+		},
+		{
+			name:              "replicas without metrics use the target when scaling down",
+			currentReplicas:   10,
+			usage:             WorkloadCPUUsage{TotalUsage: model.CPUAmountFromCores(0.5), ReplicasWithMetrics: 5},
+			request:           model.CPUAmountFromCores(1),
+			targetUtilization: 0.5,
+			expected:          6,
+		},
+		{
+			name:              "keeps replicas without metrics",
+			currentReplicas:   3,
+			usage:             WorkloadCPUUsage{},
+			request:           model.CPUAmountFromCores(1),
+			targetUtilization: 0.5,
+			expected:          3,
+		},
+		{
+			name:              "keeps replicas without request",
+			currentReplicas:   3,
+			usage:             WorkloadCPUUsage{TotalUsage: model.CPUAmountFromCores(3), ReplicasWithMetrics: 3},
+			targetUtilization: 0.5,
+			expected:          3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recommender := NewReplicasRecommender(DefaultReplicasTolerance)
+			assert.Equal(t, tc.expected, recommender.GetDesiredReplicas(tc.currentReplicas, tc.usage, tc.request, tc.targetUtilization))
+		})
+	}
+}
+
+func TestCapReplicas(t *testing.T) {
+	replicas, capped := CapReplicas(0, 1, 10)
+	assert.Equal(t, int32(1), replicas)
+	assert.True(t, capped)
+
+	replicas, capped = CapReplicas(12, 1, 10)
+	assert.Equal(t, int32(10), replicas)
+	assert.True(t, capped)
+
+	replicas, capped = CapReplicas(5, 1, 10)
+	assert.Equal(t, int32(5), replicas)
+	assert.False(t, capped)
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routines
+
+import (
+	"context"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/scale"
+	"k8s.io/klog/v2"
+
+	mpa_types "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1alpha1"
+	mpa_api "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/clientset/versioned/typed/autoscaling.k8s.io/v1alpha1"
+	mpa_lister "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/listers/autoscaling.k8s.io/v1alpha1"
+	"k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/recommender/input"
+	mpa_logic "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/recommender/logic"
+	mpa_api_util "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/utils/mpa"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_input "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/metrics"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/logic"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+	vpa_routines "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/routines"
+	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/controller_fetcher"
+)
+
+// typicalCPUUsagePercentile is the percentile of the per-replica CPU usage of
+// the workload considered its typical usage, that the number of replicas is
+// computed for.
+const typicalCPUUsagePercentile = 0.5
+
+// Recommender recommends the number of replicas and the resources of the
+// containers of workloads, based on utilization periodically got from metrics api.
+type Recommender interface {
+	// RunOnce performs one iteration of recommender duties followed by update of recommendations in MPA objects.
+	RunOnce()
+	// GetClusterState returns ClusterState used by Recommender
+	GetClusterState() model.ClusterState
+	// GetClusterStateFeeder returns ClusterStateFeeder used by Recommender
+	GetClusterStateFeeder() vpa_input.ClusterStateFeeder
+	// UpdateMPAs computes recommendations and sends MPAs status updates to API Server
+	UpdateMPAs()
+}
+
+type recommender struct {
+	clusterState                model.ClusterState
+	clusterStateFeeder          vpa_input.ClusterStateFeeder
+	metricsClient               *input.RecordingMetricsClient
+	controllerFetcher           controllerfetcher.ControllerFetcher
+	mpaClient                   mpa_api.MultidimensionalPodAutoscalersGetter
+	mpaLister                   mpa_lister.MultidimensionalPodAutoscalerLister
+	scaleNamespacer             scale.ScalesGetter
+	mapper                      meta.RESTMapper
+	podResourceRecommender      logic.PodResourceRecommender
+	replicasRecommender         mpa_logic.ReplicasRecommender
+	recommendationFormat        logic.RecommendationFormat
+	recommendationPostProcessor []vpa_routines.RecommendationPostProcessor
+}
+
+func (r *recommender) GetClusterState() model.ClusterState {
+	return r.clusterState
+}
+
+func (r *recommender) GetClusterStateFeeder() vpa_input.ClusterStateFeeder {
+	return r.clusterStateFeeder
+}
+
+// UpdateMPAs updates MPA CRD objects' status.
+func (r *recommender) UpdateMPAs() {
+	ctx := context.TODO()
+	usage := r.getWorkloadCPUUsage()
+	for _, observedVpa := range r.clusterState.ObservedVPAs() {
+		key := model.VpaID{
+			Namespace: observedVpa.Namespace,
+			VpaName:   observedVpa.Name,
+		}
+		vpa, found := r.clusterState.VPAs()[key]
+		if !found {
+			continue
+		}
+		mpa, err := r.mpaLister.MultidimensionalPodAutoscalers(key.Namespace).Get(key.VpaName)
+		if err != nil {
+			klog.ErrorS(err, "Cannot get MPA", "mpa", klog.KRef(key.Namespace, key.VpaName))
+			continue
+		}
+		r.processMPAUpdate(ctx, mpa, vpa, observedVpa, usage[key])
+	}
+}
+
+func (r *recommender) processMPAUpdate(ctx context.Context, mpa *mpa_types.MultidimensionalPodAutoscaler, vpa *model.Vpa, observedVpa *vpa_types.VerticalPodAutoscaler, usage containersCPUUsage) {
+	// Vertical scaling comes first, the number of replicas is computed for
+	// the recommended resources.
+	aggregateStates := vpa_routines.GetContainerNameToAggregateStateMap(vpa)
+	resources := r.podResourceRecommender.GetRecommendedPodResources(aggregateStates)
+	listOfResourceRecommendation := logic.MapToListOfRecommendedContainerResources(resources, r.recommendationFormat)
+	for _, postProcessor := range r.recommendationPostProcessor {
+		listOfResourceRecommendation = postProcessor.Process(observedVpa, listOfResourceRecommendation)
+	}
+	vpa.UpdateRecommendation(listOfResourceRecommendation)
+	vpa.UpdateConditions(vpa.PodCount > 0)
+	if err := r.clusterState.RecordRecommendation(vpa, time.Now()); err != nil {
+		klog.V(0).InfoS("", "err", err)
+	}
+	vpaStatus := vpa.AsStatus()
+
+	status := mpa.Status.DeepCopy()
+	status.Recommendation = vpaStatus.Recommendation
+	status.Conditions = mpa_api_util.ConvertVpaConditions(vpaStatus.Conditions)
+	for _, condition := range mpa.Status.Conditions {
+		// Conditions of the horizontal scaling are kept, the recommender
+		// sets its own below and the updater sets the others.
+		if condition.Type == mpa_types.ScalingLimited || condition.Type == mpa_types.AbleToScale {
+			status.Conditions = append(status.Conditions, condition)
+		}
+	}
+	// The number of replicas is read from the scale subresource rather than
+	// counted from the matching pods, which include terminating and not yet
+	// ready ones.
+	if _, scale, err := mpa_api_util.GetScale(ctx, r.scaleNamespacer, r.mapper, mpa); err != nil {
+		klog.ErrorS(err, "Cannot get scale of MPA target, keeping the previous number of replicas", "mpa", klog.KObj(mpa))
+	} else {
+		r.recommendReplicas(mpa, scale.Spec.Replicas, usage, aggregateStates, status)
+	}
+
+	_, err := mpa_api_util.UpdateMpaStatusIfNeeded(
+		r.mpaClient.MultidimensionalPodAutoscalers(mpa.Namespace), mpa.Name, status, &mpa.Status)
+	if err != nil {
+		klog.ErrorS(err, "Cannot update MPA", "mpa", klog.KObj(mpa))
+	}
+}
+
+// recommendReplicas sets the current and desired number of replicas, as well
+// as the current CPU utilization, in the status.
+//
+// The recommended CPU requests already put the per-replica usage at the
+// percentile targeted by the recommender at the CPU utilization goal of the
+// MPA, so comparing the current usage with the goal would always find the
+// workload below it and shrink it to the minimum number of replicas. Replicas
+// are instead computed so that the current usage per replica gets back to the
+// typical usage the requests were recommended for, i.e. the number of replicas
+// follows changes of the load faster than the usage history. The goal still
+// applies when the requests are capped below it by the resource policy.
+func (r *recommender) recommendReplicas(mpa *mpa_types.MultidimensionalPodAutoscaler, currentReplicas int32, usage containersCPUUsage, aggregateStates model.ContainerNameToAggregateStateMap, status *mpa_types.MultidimensionalPodAutoscalerStatus) {
+	status.CurrentReplicas = currentReplicas
+	targetUtilization := mpa_api_util.GetTargetCPUUtilization(mpa)
+	if targetUtilization == nil || status.Recommendation == nil {
+		status.DesiredReplicas = 0
+		status.CurrentMetrics = nil
+		return
+	}
+
+	var request model.ResourceAmount
+	recommendedContainers := make(map[string]bool)
+	for _, recommendation := range status.Recommendation.ContainerRecommendations {
+		if cpu, found := recommendation.Target[corev1.ResourceCPU]; found {
+			request += model.CPUAmountFromCores(cpu.AsApproximateFloat64())
+			recommendedContainers[recommendation.ContainerName] = true
+		}
+	}
+	// Only the usage of the containers that requests are recommended for is
+	// compared with the recommended requests.
+	workloadUsage := usage.forContainers(recommendedContainers)
+	horizontalUtilization := horizontalTargetUtilization(float64(*targetUtilization)/100.0, request, aggregateStates, recommendedContainers)
+	desiredReplicas := r.replicasRecommender.GetDesiredReplicas(currentReplicas, workloadUsage, request, horizontalUtilization)
+	minReplicas, maxReplicas := mpa_api_util.GetReplicaBounds(mpa)
+	desiredReplicas, limited := mpa_logic.CapReplicas(desiredReplicas, minReplicas, maxReplicas)
+	status.DesiredReplicas = desiredReplicas
+
+	limitedCondition := mpa_types.MultidimensionalPodAutoscalerCondition{
+		Type:               mpa_types.ScalingLimited,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "DesiredWithinRange",
+	}
+	if limited {
+		limitedCondition.Status = corev1.ConditionTrue
+		limitedCondition.Reason = "DesiredOutsideRange"
+		limitedCondition.Message = "the desired replica count is outside of the range of minReplicas and maxReplicas"
+	}
+	status.Conditions = mpa_api_util.SetCondition(status.Conditions, limitedCondition)
+
+	status.CurrentMetrics = nil
+	if workloadUsage.ReplicasWithMetrics > 0 && request > 0 {
+		averageUsage := model.ScaleResource(workloadUsage.TotalUsage, 1.0/float64(workloadUsage.ReplicasWithMetrics))
+		averageUtilization := int32(100 * float64(averageUsage) / float64(request))
+		averageValue := resource.NewMilliQuantity(int64(averageUsage), resource.DecimalSI)
+		status.CurrentMetrics = []autoscalingv2.MetricStatus{{
+			Type: autoscalingv2.ResourceMetricSourceType,
+			Resource: &autoscalingv2.ResourceMetricStatus{
+				Name: corev1.ResourceCPU,
+				Current: autoscalingv2.MetricValueStatus{
+					AverageUtilization: &averageUtilization,
+					AverageValue:       averageValue,
+				},
+			},
+		}}
+	}
+}
+
+// horizontalTargetUtilization returns the CPU utilization of the given
+// requests at the typical per-replica usage of the containers, or the goal if
+// it is lower or the usage history of a container is missing.
+func horizontalTargetUtilization(goal float64, request model.ResourceAmount, aggregateStates model.ContainerNameToAggregateStateMap, containers map[string]bool) float64 {
+	if request <= 0 {
+		return goal
+	}
+	typicalUsage := 0.0
+	for containerName := range containers {
+		state, found := aggregateStates[containerName]
+		if !found || state.HorizontalScaling == nil || state.HorizontalScaling.AggregateCPUUsagePerReplica.IsEmpty() {
+			return goal
+		}
+		typicalUsage += state.HorizontalScaling.AggregateCPUUsagePerReplica.Percentile(typicalCPUUsagePercentile)
+	}
+	utilization := typicalUsage / model.CoresFromCPUAmount(request)
+	if utilization <= 0 || utilization > goal {
+		return goal
+	}
+	return utilization
+}
+
+// containersCPUUsage is the current CPU usage of the containers of the pods
+// of a workload, by pod.
+type containersCPUUsage map[model.PodID]map[string]model.ResourceAmount
+
+func (u containersCPUUsage) forContainers(containers map[string]bool) mpa_logic.WorkloadCPUUsage {
+	var result mpa_logic.WorkloadCPUUsage
+	for _, podUsage := range u {
+		found := false
+		for containerName, usage := range podUsage {
+			if containers[containerName] {
+				result.TotalUsage += usage
+				found = true
+			}
+		}
+		if found {
+			result.ReplicasWithMetrics++
+		}
+	}
+	return result
+}
+
+// getWorkloadCPUUsage returns the CPU usage of the containers of the pods
+// matching each MPA, from the last metrics loaded into the cluster state.
+func (r *recommender) getWorkloadCPUUsage() map[model.VpaID]containersCPUUsage {
+	podToVpa := make(map[model.PodID]model.VpaID)
+	for vpaID, vpa := range r.clusterState.VPAs() {
+		for _, podID := range r.clusterState.GetMatchingPods(vpa) {
+			podToVpa[podID] = vpaID
+		}
+	}
+	result := make(map[model.VpaID]containersCPUUsage)
+	for _, snapshot := range r.metricsClient.LastSnapshots() {
+		addContainerCPUUsage(result, podToVpa, snapshot)
+	}
+	return result
+}
+
+func addContainerCPUUsage(result map[model.VpaID]containersCPUUsage, podToVpa map[model.PodID]model.VpaID, snapshot *metrics.ContainerMetricsSnapshot) {
+	vpaID, found := podToVpa[snapshot.ID.PodID]
+	if !found {
+		return
+	}
+	cpu, found := snapshot.Usage[model.ResourceCPU]
+	if !found {
+		return
+	}
+	usage, found := result[vpaID]
+	if !found {
+		usage = make(containersCPUUsage)
+		result[vpaID] = usage
+	}
+	if usage[snapshot.ID.PodID] == nil {
+		usage[snapshot.ID.PodID] = make(map[string]model.ResourceAmount)
+	}
+	usage[snapshot.ID.PodID][snapshot.ID.ContainerName] += cpu
+}
+
+func (r *recommender) RunOnce() {
+	ctx := context.Background()
+
+	klog.V(3).InfoS("Recommender Run")
+
+	r.clusterStateFeeder.LoadVPAs(ctx)
+	r.clusterStateFeeder.LoadPods()
+	r.clusterStateFeeder.LoadRealTimeMetrics(ctx)
+	klog.V(3).InfoS("ClusterState is tracking", "pods", len(r.clusterState.Pods()), "mpas", len(r.clusterState.VPAs()))
+
+	r.UpdateMPAs()
+
+	r.clusterState.RateLimitedGarbageCollectAggregateCollectionStates(ctx, time.Now(), r.controllerFetcher)
+	klog.V(3).InfoS("ClusterState is tracking", "aggregateContainerStates", r.clusterState.StateMapSize())
+}
+
+// RecommenderFactory makes instances of Recommender.
+type RecommenderFactory struct {
+	ClusterState model.ClusterState
+
+	ClusterStateFeeder     vpa_input.ClusterStateFeeder
+	MetricsClient          *input.RecordingMetricsClient
+	ControllerFetcher      controllerfetcher.ControllerFetcher
+	PodResourceRecommender logic.PodResourceRecommender
+	ReplicasRecommender    mpa_logic.ReplicasRecommender
+	RecommendationFormat   logic.RecommendationFormat
+	MpaClient              mpa_api.MultidimensionalPodAutoscalersGetter
+	MpaLister              mpa_lister.MultidimensionalPodAutoscalerLister
+	ScaleNamespacer        scale.ScalesGetter
+	Mapper                 meta.RESTMapper
+
+	RecommendationPostProcessors []vpa_routines.RecommendationPostProcessor
+}
+
+// Make creates a new recommender instance,
+// which can be run in order to provide continuous recommendations for workloads.
+func (c RecommenderFactory) Make() Recommender {
+	recommender := &recommender{
+		clusterState:                c.ClusterState,
+		clusterStateFeeder:          c.ClusterStateFeeder,
+		metricsClient:               c.MetricsClient,
+		controllerFetcher:           c.ControllerFetcher,
+		mpaClient:                   c.MpaClient,
+		mpaLister:                   c.MpaLister,
+		scaleNamespacer:             c.ScaleNamespacer,
+		mapper:                      c.Mapper,
+		podResourceRecommender:      c.PodResourceRecommender,
+		replicasRecommender:         c.ReplicasRecommender,
+		recommendationFormat:        c.RecommendationFormat,
+		recommendationPostProcessor: c.RecommendationPostProcessors,
+	}
+	klog.V(3).InfoS("New Recommender created", "recommender", recommender)
+	return recommender
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routines
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	cacheddiscovery "k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	kube_client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/scale"
+	resourceclient "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+
+	mpa_clientset "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/clientset/versioned"
+	"k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/recommender/input"
+	mpa_logic "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/recommender/logic"
+	mpa_api_util "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/utils/mpa"
+	recommender_config "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/config"
+	vpa_input "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input"
+	input_metrics "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/metrics"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/logic"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+	vpa_routines "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/routines"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
+	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/controller_fetcher"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/metrics"
+)
+
+const (
+	// aggregateContainerStateGCInterval defines how often expired AggregateContainerStates are garbage collected.
+	aggregateContainerStateGCInterval               = 1 * time.Hour
+	scaleCacheEntryLifetime           time.Duration = time.Hour
+	scaleCacheEntryFreshnessTime      time.Duration = 10 * time.Minute
+	scaleCacheEntryJitterFactor       float64       = 1.
+	scaleCacheLoopPeriod                            = 7 * time.Second
+	discoveryResetPeriod                            = 5 * time.Minute
+)
+
+// RecommenderController wraps the Recommender with the full lifecycle
+// (informer setup, ticker loop, health checks) needed to run as a standalone controller.
+type RecommenderController struct {
+	recommender Recommender
+	interval    time.Duration
+	healthCheck *metrics.HealthCheck
+}
+
+// NewRecommenderController creates a RecommenderController. The recommender
+// reuses the VPA cluster state, fed with the MPA objects converted to VPA
+// objects, and reads the VPA recommender flags. The usage history is only
+// kept in memory.
+func NewRecommenderController(
+	ctx context.Context,
+	kubeConfig *rest.Config,
+	kubeClient kube_client.Interface,
+	mpaClient *mpa_clientset.Clientset,
+	factory informers.SharedInformerFactory,
+	config *recommender_config.RecommenderConfig,
+	replicasTolerance float64,
+	healthCheck *metrics.HealthCheck,
+	stopCh <-chan struct{},
+) (*RecommenderController, error) {
+	commonFlags := config.CommonFlags
+
+	clusterState := model.NewClusterState(aggregateContainerStateGCInterval)
+	controllerFetcher := controllerfetcher.NewControllerFetcher(kubeConfig, kubeClient, factory, scaleCacheEntryFreshnessTime, scaleCacheEntryLifetime, scaleCacheEntryJitterFactor, stopCh)
+	podLister, oomObserver := vpa_input.NewPodListerAndOOMObserver(ctx, kubeClient, commonFlags.VpaObjectNamespace, stopCh)
+
+	aggregationsConfig := model.NewAggregationsConfig(
+		config.MemoryAggregationInterval,
+		config.MemoryAggregationIntervalCount,
+		config.MemoryHistogramDecayHalfLife,
+		config.CpuHistogramDecayHalfLife,
+		config.OOMBumpUpRatio,
+		config.OOMMinBumpUp,
+	)
+	aggregationsConfig.SeasonalCPUHistograms = config.CPUEstimator == logic.SeasonalCPUEstimatorName
+	aggregationsConfig.SeasonalCPUHistogramDecayHalfLife = config.SeasonalCpuHistogramDecayHalfLife
+	model.InitializeAggregationsConfig(aggregationsConfig)
+
+	var postProcessors []vpa_routines.RecommendationPostProcessor
+	if config.PostProcessorCPUasInteger {
+		postProcessors = append(postProcessors, &vpa_routines.IntegerCPUPostProcessor{})
+	}
+	globalMaxAllowed := make(corev1.ResourceList)
+	if !config.MaxAllowedCPU.IsZero() {
+		globalMaxAllowed[corev1.ResourceCPU] = config.MaxAllowedCPU.Quantity
+	}
+	if !config.MaxAllowedMemory.IsZero() {
+		globalMaxAllowed[corev1.ResourceMemory] = config.MaxAllowedMemory.Quantity
+	}
+	postProcessors = append(postProcessors, vpa_routines.NewCappingRecommendationProcessor(globalMaxAllowed))
+
+	source := input_metrics.NewPodMetricsesSource(resourceclient.NewForConfigOrDie(kubeConfig))
+	metricsClient := input.NewRecordingMetricsClient(input_metrics.NewMetricsClient(source, commonFlags.VpaObjectNamespace, "default-metrics-client"))
+	mpaLister := mpa_api_util.NewMpasLister(mpaClient, stopCh, commonFlags.VpaObjectNamespace)
+
+	clusterStateFeeder := vpa_input.ClusterStateFeederFactory{
+		PodLister:          podLister,
+		HpaLister:          input.NewHpaLister(mpaLister),
+		OOMObserver:        oomObserver,
+		KubeClient:         kubeClient,
+		MetricsClient:      metricsClient,
+		VpaLister:          input.NewVpaLister(mpaLister),
+		ClusterState:       clusterState,
+		SelectorFetcher:    target.NewVpaTargetSelectorFetcher(kubeConfig, kubeClient, factory, stopCh),
+		MemorySaveMode:     config.MemorySaver,
+		ControllerFetcher:  controllerFetcher,
+		RecommenderName:    config.RecommenderName,
+		IgnoredNamespaces:  strings.Split(commonFlags.IgnoredVpaObjectNamespaces, ","),
+		VpaObjectNamespace: commonFlags.VpaObjectNamespace,
+	}.Make()
+	controllerFetcher.Start(ctx, scaleCacheLoopPeriod)
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(kubeConfig)
+	if err != nil {
+		return nil, fmt.Errorf("could not create discovery client: %v", err)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cacheddiscovery.NewMemCacheClient(discoveryClient))
+	go wait.Until(func() {
+		mapper.Reset()
+	}, discoveryResetPeriod, stopCh)
+	scaleNamespacer := scale.New(kubeClient.CoreV1().RESTClient(), mapper, dynamic.LegacyAPIPathResolverFunc, scale.NewDiscoveryScaleKindResolver(discoveryClient))
+
+	recommender := RecommenderFactory{
+		ClusterState:       clusterState,
+		ClusterStateFeeder: clusterStateFeeder,
+		MetricsClient:      metricsClient,
+		ControllerFetcher:  controllerFetcher,
+		MpaClient:          mpaClient.AutoscalingV1alpha1(),
+		MpaLister:          mpaLister,
+		ScaleNamespacer:    scaleNamespacer,
+		Mapper:             mapper,
+		PodResourceRecommender: logic.CreatePodResourceRecommender(logic.RecommendationConfig{
+			SafetyMarginFraction:       config.SafetyMarginFraction,
+			PodMinCPUMillicores:        config.PodMinCPUMillicores,
+			PodMinMemoryMb:             config.PodMinMemoryMb,
+			TargetCPUPercentile:        config.TargetCPUPercentile,
+			LowerBoundCPUPercentile:    config.LowerBoundCPUPercentile,
+			UpperBoundCPUPercentile:    config.UpperBoundCPUPercentile,
+			ConfidenceIntervalCPU:      config.ConfidenceIntervalCPU,
+			TargetMemoryPercentile:     config.TargetMemoryPercentile,
+			LowerBoundMemoryPercentile: config.LowerBoundMemoryPercentile,
+			UpperBoundMemoryPercentile: config.UpperBoundMemoryPercentile,
+			ConfidenceIntervalMemory:   config.ConfidenceIntervalMemory,
+			CPUEstimator:               config.CPUEstimator,
+		}),
+		ReplicasRecommender: mpa_logic.NewReplicasRecommender(replicasTolerance),
+		RecommendationFormat: logic.RecommendationFormat{
+			HumanizeMemory:     config.HumanizeMemory,
+			RoundCPUMillicores: config.RoundCPUMillicores,
+			RoundMemoryBytes:   config.RoundMemoryBytes,
+		},
+		RecommendationPostProcessors: postProcessors,
+	}.Make()
+
+	return &RecommenderController{
+		recommender: recommender,
+		interval:    config.MetricsFetcherInterval,
+		healthCheck: healthCheck,
+	}, nil
+}
+
+// Run starts the recommender loop and blocks until ctx is cancelled.
+func (c *RecommenderController) Run(ctx context.Context) error {
+	c.healthCheck.StartMonitoring()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.recommender.RunOnce()
+			c.healthCheck.UpdateLastActivity()
+		}
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package routines
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	mpa_types "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1alpha1"
+	mpa_logic "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/recommender/logic"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/logic"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/util"
+)
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
+
+func TestRecommendReplicas(t *testing.T) {
+	mpa := &mpa_types.MultidimensionalPodAutoscaler{
+		Spec: mpa_types.MultidimensionalPodAutoscalerSpec{
+			Goals: &mpa_types.Goals{Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: int32Ptr(50),
+					},
+				},
+			}}},
+			Constraints: &mpa_types.ScalingConstraints{
+				Global: &mpa_types.HorizontalScalingConstraints{MinReplicas: int32Ptr(2), MaxReplicas: int32Ptr(5)},
+			},
+		},
+	}
+	status := &mpa_types.MultidimensionalPodAutoscalerStatus{
+		Recommendation: &vpa_types.RecommendedPodResources{
+			ContainerRecommendations: []vpa_types.RecommendedContainerResources{{
+				ContainerName: "app",
+				Target: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("500m"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			}},
+		},
+	}
+	// The usage of the sidecar isn't compared with the recommended requests.
+	usage := make(containersCPUUsage)
+	for _, podName := range []string{"pod-1", "pod-2", "pod-3"} {
+		usage[model.PodID{Namespace: "default", PodName: podName}] = map[string]model.ResourceAmount{
+			"app":     model.CPUAmountFromCores(0.9),
+			"sidecar": model.CPUAmountFromCores(0.1),
+		}
+	}
+
+	r := &recommender{replicasRecommender: mpa_logic.NewReplicasRecommender(mpa_logic.DefaultReplicasTolerance)}
+	r.recommendReplicas(mpa, 3, usage, nil, status)
+
+	assert.Equal(t, int32(3), status.CurrentReplicas)
+	// 3 replicas at 180% of the requests need 11 replicas at 50%, capped to 5.
+	assert.Equal(t, int32(5), status.DesiredReplicas)
+	assert.Len(t, status.Conditions, 1)
+	assert.Equal(t, mpa_types.ScalingLimited, status.Conditions[0].Type)
+	assert.Equal(t, corev1.ConditionTrue, status.Conditions[0].Status)
+	assert.Len(t, status.CurrentMetrics, 1)
+	assert.Equal(t, int32(180), *status.CurrentMetrics[0].Resource.Current.AverageUtilization)
+	assert.Equal(t, int64(900), status.CurrentMetrics[0].Resource.Current.AverageValue.MilliValue())
+
+	// Without a CPU utilization goal, no number of replicas is recommended.
+	mpa.Spec.Goals = nil
+	r.recommendReplicas(mpa, 3, usage, nil, status)
+	assert.Equal(t, int32(0), status.DesiredReplicas)
+	assert.Nil(t, status.CurrentMetrics)
+}
+
+// TestRecommendReplicasWithCoordinatedRequests runs the horizontal and the
+// coordinated vertical recommendations against each other, to check that the
+// number of replicas doesn't drift while the load is steady and follows it
+// when it changes.
+func TestRecommendReplicasWithCoordinatedRequests(t *testing.T) {
+	mpa := &mpa_types.MultidimensionalPodAutoscaler{
+		Spec: mpa_types.MultidimensionalPodAutoscalerSpec{
+			Goals: &mpa_types.Goals{Metrics: []autoscalingv2.MetricSpec{{
+				Type: autoscalingv2.ResourceMetricSourceType,
+				Resource: &autoscalingv2.ResourceMetricSource{
+					Name: corev1.ResourceCPU,
+					Target: autoscalingv2.MetricTarget{
+						Type:               autoscalingv2.UtilizationMetricType,
+						AverageUtilization: int32Ptr(50),
+					},
+				},
+			}}},
+			Constraints: &mpa_types.ScalingConstraints{
+				Global: &mpa_types.HorizontalScalingConstraints{MinReplicas: int32Ptr(1), MaxReplicas: int32Ptr(100)},
+			},
+		},
+	}
+	usagePerReplica := util.NewHistogram(model.GetAggregationsConfig().CPUHistogramOptions)
+	aggregateStates := model.ContainerNameToAggregateStateMap{
+		"app": &model.AggregateContainerState{
+			HorizontalScaling: &model.HorizontalScalingState{
+				AggregateCPUUsagePerReplica: usagePerReplica,
+				TargetCPUUtilization:        0.5,
+			},
+		},
+	}
+	estimator := logic.WithHPACoordination(0.9, nil)
+	r := &recommender{replicasRecommender: mpa_logic.NewReplicasRecommender(mpa_logic.DefaultReplicasTolerance)}
+
+	// The load of the workload varies around its average at each round, by
+	// less than the tolerance.
+	variation := []float64{0.92, 1.08, 0.96, 1.04, 1.0}
+	replicas := int32(10)
+	status := &mpa_types.MultidimensionalPodAutoscalerStatus{}
+	runRounds := func(averageLoad float64, rounds int) {
+		now := time.Now()
+		for i := 0; i < rounds; i++ {
+			load := averageLoad * variation[i%len(variation)]
+			usagePerReplica.AddSample(load/float64(replicas), 1.0, now.Add(time.Duration(i)*time.Minute))
+			request := estimator.GetCPUEstimation(aggregateStates["app"])
+			status.Recommendation = &vpa_types.RecommendedPodResources{
+				ContainerRecommendations: []vpa_types.RecommendedContainerResources{{
+					ContainerName: "app",
+					Target:        corev1.ResourceList{corev1.ResourceCPU: *resource.NewMilliQuantity(int64(request), resource.DecimalSI)},
+				}},
+			}
+			usage := make(containersCPUUsage)
+			for p := int32(0); p < replicas; p++ {
+				usage[model.PodID{Namespace: "default", PodName: fmt.Sprintf("pod-%d", p)}] = map[string]model.ResourceAmount{
+					"app": model.CPUAmountFromCores(load / float64(replicas)),
+				}
+			}
+			r.recommendReplicas(mpa, replicas, usage, aggregateStates, status)
+			replicas = status.DesiredReplicas
+		}
+	}
+
+	// A steady load keeps the number of replicas, even though the current
+	// utilization is usually below the goal the requests are sized for.
+	runRounds(5.0, 500)
+	assert.InDelta(t, 10, replicas, 2)
+
+	// Doubling the load doubles the number of replicas, before the requests
+	// catch up with it.
+	runRounds(10.0, 10)
+	assert.InDelta(t, 20, replicas, 4)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	mpa_types "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1alpha1"
+	mpa_api_util "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/utils/mpa"
+)
+
+// scaleReplicas sets the number of replicas of the target of the MPA to the
+// desired one recommended by the recommender, through the scale subresource.
+func (u *updater) scaleReplicas(ctx context.Context, mpa *mpa_types.MultidimensionalPodAutoscaler) {
+	desiredReplicas := mpa.Status.DesiredReplicas
+	if desiredReplicas <= 0 || mpa.Spec.ScaleTargetRef == nil {
+		return
+	}
+	targetGR, scale, err := mpa_api_util.GetScale(ctx, u.scaleNamespacer, u.mapper, mpa)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get scale of MPA target", "mpa", klog.KObj(mpa))
+		u.setAbleToScale(mpa, corev1.ConditionFalse, "FailedGetScale", err.Error(), false)
+		return
+	}
+	currentReplicas := scale.Spec.Replicas
+	if currentReplicas == desiredReplicas {
+		return
+	}
+	if currentReplicas == 0 {
+		// Like the HPA, the MPA doesn't scale up targets which were scaled to zero.
+		klog.V(3).InfoS("Skipping scaling of MPA target with zero replicas", "mpa", klog.KObj(mpa))
+		return
+	}
+	if desiredReplicas < currentReplicas && mpa.Status.LastScaleTime != nil &&
+		u.now().Sub(mpa.Status.LastScaleTime.Time) < u.config.ScaleDownStabilization {
+		klog.V(3).InfoS("Delaying scale down of MPA target within the stabilization window", "mpa", klog.KObj(mpa),
+			"currentReplicas", currentReplicas, "desiredReplicas", desiredReplicas)
+		return
+	}
+
+	scale.Spec.Replicas = desiredReplicas
+	if _, err := u.scaleNamespacer.Scales(mpa.Namespace).Update(ctx, targetGR, scale, metav1.UpdateOptions{}); err != nil {
+		klog.ErrorS(err, "Failed to update scale of MPA target", "mpa", klog.KObj(mpa))
+		u.eventRecorder.Eventf(mpa, corev1.EventTypeWarning, "FailedRescale", "New size: %d; error: %v", desiredReplicas, err)
+		u.setAbleToScale(mpa, corev1.ConditionFalse, "FailedUpdateScale", err.Error(), false)
+		return
+	}
+	klog.V(2).InfoS("Scaled MPA target", "mpa", klog.KObj(mpa), "oldReplicas", currentReplicas, "newReplicas", desiredReplicas)
+	u.eventRecorder.Eventf(mpa, corev1.EventTypeNormal, "SuccessfulRescale", "New size: %d", desiredReplicas)
+	u.setAbleToScale(mpa, corev1.ConditionTrue, "SucceededRescale",
+		fmt.Sprintf("the MPA updater set the number of replicas to %d", desiredReplicas), true)
+}
+
+// setAbleToScale updates the AbleToScale condition of the MPA, and its last
+// scale time if it was rescaled.
+func (u *updater) setAbleToScale(mpa *mpa_types.MultidimensionalPodAutoscaler, status corev1.ConditionStatus, reason, message string, rescaled bool) {
+	newStatus := mpa.Status.DeepCopy()
+	if rescaled {
+		now := metav1.NewTime(u.now())
+		newStatus.LastScaleTime = &now
+	}
+	newStatus.Conditions = mpa_api_util.SetCondition(newStatus.Conditions, mpa_types.MultidimensionalPodAutoscalerCondition{
+		Type:               mpa_types.AbleToScale,
+		Status:             status,
+		LastTransitionTime: metav1.NewTime(u.now()),
+		Reason:             reason,
+		Message:            message,
+	})
+	_, err := mpa_api_util.UpdateMpaStatusIfNeeded(u.mpaClient.MultidimensionalPodAutoscalers(mpa.Namespace), mpa.Name, newStatus, &mpa.Status)
+	if err != nil {
+		klog.ErrorS(err, "Cannot update MPA status", "mpa", klog.KObj(mpa))
+	}
+}
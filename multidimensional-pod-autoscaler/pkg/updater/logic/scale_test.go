@@ -0,0 +1,146 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	scalefake "k8s.io/client-go/scale/fake"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+
+	mpa_types "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1alpha1"
+	mpa_fake "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/clientset/versioned/fake"
+)
+
+func TestScaleReplicas(t *testing.T) {
+	now := time.Date(2026, time.October, 19, 12, 0, 0, 0, time.UTC)
+	recentScale := metav1.NewTime(now.Add(-time.Minute))
+	oldScale := metav1.NewTime(now.Add(-time.Hour))
+	testCases := []struct {
+		name            string
+		currentReplicas int32
+		desiredReplicas int32
+		lastScaleTime   *metav1.Time
+		expectReplicas  int32
+		expectUpdate    bool
+	}{
+		{
+			name:            "scale up",
+			currentReplicas: 3,
+			desiredReplicas: 5,
+			lastScaleTime:   &recentScale,
+			expectReplicas:  5,
+			expectUpdate:    true,
+		},
+		{
+			name:            "scale down after the stabilization window",
+			currentReplicas: 5,
+			desiredReplicas: 3,
+			lastScaleTime:   &oldScale,
+			expectReplicas:  3,
+			expectUpdate:    true,
+		},
+		{
+			name:            "no scale down within the stabilization window",
+			currentReplicas: 5,
+			desiredReplicas: 3,
+			lastScaleTime:   &recentScale,
+			expectReplicas:  5,
+		},
+		{
+			name:            "no scale up from zero",
+			currentReplicas: 0,
+			desiredReplicas: 3,
+			expectReplicas:  0,
+		},
+		{
+			name:            "no recommendation",
+			currentReplicas: 3,
+			desiredReplicas: 0,
+			expectReplicas:  3,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mpa := &mpa_types.MultidimensionalPodAutoscaler{
+				ObjectMeta: metav1.ObjectMeta{Name: "mpa", Namespace: "default"},
+				Spec: mpa_types.MultidimensionalPodAutoscalerSpec{
+					ScaleTargetRef: &autoscalingv1.CrossVersionObjectReference{
+						APIVersion: "apps/v1",
+						Kind:       "Deployment",
+						Name:       "app",
+					},
+				},
+				Status: mpa_types.MultidimensionalPodAutoscalerStatus{
+					DesiredReplicas: tc.desiredReplicas,
+					LastScaleTime:   tc.lastScaleTime,
+				},
+			}
+			mpaClient := mpa_fake.NewSimpleClientset(mpa)
+
+			replicas := tc.currentReplicas
+			updated := false
+			scaleClient := &scalefake.FakeScaleClient{}
+			scaleClient.AddReactor("get", "deployments", func(action core.Action) (bool, runtime.Object, error) {
+				return true, &autoscalingv1.Scale{
+					ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"},
+					Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+				}, nil
+			})
+			scaleClient.AddReactor("update", "deployments", func(action core.Action) (bool, runtime.Object, error) {
+				scale := action.(core.UpdateAction).GetObject().(*autoscalingv1.Scale)
+				replicas = scale.Spec.Replicas
+				updated = true
+				return true, scale, nil
+			})
+
+			mapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{{Group: "apps", Version: "v1"}})
+			mapper.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+
+			u := &updater{
+				mpaClient:       mpaClient.AutoscalingV1alpha1(),
+				eventRecorder:   record.NewFakeRecorder(10),
+				scaleNamespacer: scaleClient,
+				mapper:          mapper,
+				config:          UpdaterConfig{ScaleDownStabilization: 5 * time.Minute},
+				now:             func() time.Time { return now },
+			}
+			u.scaleReplicas(context.Background(), mpa)
+
+			assert.Equal(t, tc.expectReplicas, replicas)
+			assert.Equal(t, tc.expectUpdate, updated)
+			if tc.expectUpdate {
+				got, err := mpaClient.AutoscalingV1alpha1().MultidimensionalPodAutoscalers("default").Get(context.Background(), "mpa", metav1.GetOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, now, got.Status.LastScaleTime.Time.UTC())
+				assert.Len(t, got.Status.Conditions, 1)
+				assert.Equal(t, mpa_types.AbleToScale, got.Status.Conditions[0].Type)
+				assert.Equal(t, corev1.ConditionTrue, got.Status.Conditions[0].Status)
+			}
+		})
+	}
+}
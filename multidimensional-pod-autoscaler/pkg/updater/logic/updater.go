@@ -0,0 +1,322 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logic
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/informers"
+	kube_client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/scale"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	resourceclient "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
+
+	mpa_types "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1alpha1"
+	mpa_api "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/clientset/versioned/typed/autoscaling.k8s.io/v1alpha1"
+	mpa_lister "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/listers/autoscaling.k8s.io/v1alpha1"
+	mpa_api_util "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/utils/mpa"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/patch"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
+	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/controller_fetcher"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/priority"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/restriction"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/utils"
+	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
+)
+
+// Updater performs updates on pods and replica counts if recommended by Multidimensional Pod Autoscaler
+type Updater interface {
+	// RunOnce represents single iteration in the main-loop of Updater
+	RunOnce(context.Context)
+}
+
+// UpdaterConfig holds the configuration of the updater.
+type UpdaterConfig struct {
+	MinReplicas                 int
+	InPlaceRateLimit            float64
+	InPlaceRateBurst            int
+	InPlaceSkipDisruptionBudget bool
+	DefaultUpdateThreshold      float64
+	PodLifetimeUpdateThreshold  time.Duration
+	EvictAfterOOMThreshold      time.Duration
+	// ScaleDownStabilization is how long after the last change of the number
+	// of replicas the updater waits before scaling the target down.
+	ScaleDownStabilization time.Duration
+	IgnoredNamespaces      []string
+}
+
+type updater struct {
+	mpaLister               mpa_lister.MultidimensionalPodAutoscalerLister
+	mpaClient               mpa_api.MultidimensionalPodAutoscalersGetter
+	podLister               listersv1.PodLister
+	eventRecorder           record.EventRecorder
+	restrictionFactory      restriction.PodsRestrictionFactory
+	recommendationProcessor vpa_api_util.RecommendationProcessor
+	priorityProcessor       priority.PriorityProcessor
+	inPlaceRateLimiter      *rate.Limiter
+	selectorFetcher         target.VpaTargetSelectorFetcher
+	controllerFetcher       controllerfetcher.ControllerFetcher
+	scaleNamespacer         scale.ScalesGetter
+	mapper                  meta.RESTMapper
+	config                  UpdaterConfig
+	infeasibleAttempts      map[types.UID]*vpa_types.RecommendedPodResources
+	now                     func() time.Time
+}
+
+// NewUpdater creates Updater with given configuration
+func NewUpdater(
+	kubeClient kube_client.Interface,
+	mpaClient mpa_api.MultidimensionalPodAutoscalersGetter,
+	mpaLister mpa_lister.MultidimensionalPodAutoscalerLister,
+	kubeInformerFactory informers.SharedInformerFactory,
+	podLister listersv1.PodLister,
+	scaleNamespacer scale.ScalesGetter,
+	mapper meta.RESTMapper,
+	config UpdaterConfig,
+	recommendationProcessor vpa_api_util.RecommendationProcessor,
+	selectorFetcher target.VpaTargetSelectorFetcher,
+	controllerFetcher controllerfetcher.ControllerFetcher,
+	priorityProcessor priority.PriorityProcessor,
+	patchCalculators []patch.Calculator,
+	podMetricsGetter resourceclient.PodMetricsesGetter,
+) Updater {
+	factory := restriction.NewPodsRestrictionFactory(
+		kubeClient,
+		kubeInformerFactory,
+		config.MinReplicas,
+		// Pods of an MPA are never evicted.
+		0,
+		patchCalculators,
+		config.InPlaceSkipDisruptionBudget,
+		podMetricsGetter,
+	)
+	return &updater{
+		mpaLister:               mpaLister,
+		mpaClient:               mpaClient,
+		podLister:               podLister,
+		eventRecorder:           newEventRecorder(kubeClient),
+		restrictionFactory:      factory,
+		recommendationProcessor: recommendationProcessor,
+		priorityProcessor:       priorityProcessor,
+		inPlaceRateLimiter:      getRateLimiter(config.InPlaceRateLimit, config.InPlaceRateBurst),
+		selectorFetcher:         selectorFetcher,
+		controllerFetcher:       controllerFetcher,
+		scaleNamespacer:         scaleNamespacer,
+		mapper:                  mapper,
+		config:                  config,
+		infeasibleAttempts:      make(map[types.UID]*vpa_types.RecommendedPodResources),
+		now:                     time.Now,
+	}
+}
+
+// RunOnce represents single iteration in the main-loop of Updater
+func (u *updater) RunOnce(ctx context.Context) {
+	mpaList, err := u.mpaLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to get MPA list")
+		return
+	}
+
+	vpas := make([]*vpa_api_util.VpaWithSelector, 0)
+	for _, mpa := range mpaList {
+		if slices.Contains(u.config.IgnoredNamespaces, mpa.Namespace) {
+			klog.V(3).InfoS("Skipping MPA object in ignored namespace", "mpa", klog.KObj(mpa), "namespace", mpa.Namespace)
+			continue
+		}
+		if mpa_api_util.GetUpdateMode(mpa) != mpa_types.UpdateModeAuto {
+			klog.V(3).InfoS("Skipping MPA object because its mode is not \"Auto\"", "mpa", klog.KObj(mpa))
+			continue
+		}
+		u.scaleReplicas(ctx, mpa)
+
+		vpa := mpa_api_util.ConvertToVpa(mpa)
+		if vpa.Status.Recommendation == nil {
+			continue
+		}
+		selector, err := u.selectorFetcher.Fetch(ctx, vpa)
+		if err != nil {
+			klog.V(3).InfoS("Skipping MPA object because we cannot fetch selector", "mpa", klog.KObj(mpa))
+			continue
+		}
+		vpas = append(vpas, &vpa_api_util.VpaWithSelector{
+			Vpa:      vpa,
+			Selector: selector,
+		})
+	}
+	if len(vpas) == 0 {
+		klog.V(3).InfoS("No MPA objects with recommendation to process")
+		return
+	}
+
+	podsList, err := u.podLister.List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Failed to get pods list")
+		return
+	}
+	controlledPods := make(map[*vpa_types.VerticalPodAutoscaler][]*corev1.Pod)
+	livePodUIDs := sets.New[types.UID]()
+	for _, pod := range podsList {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		livePodUIDs.Insert(pod.UID)
+		controllingVPA := vpa_api_util.GetControllingVPAForPod(ctx, pod, vpas, u.controllerFetcher)
+		if controllingVPA != nil {
+			controlledPods[controllingVPA.Vpa] = append(controlledPods[controllingVPA.Vpa], pod)
+		}
+	}
+	for podID := range u.infeasibleAttempts {
+		if !livePodUIDs.Has(podID) {
+			delete(u.infeasibleAttempts, podID)
+		}
+	}
+
+	for vpa, livePods := range controlledPods {
+		if err := u.resizePods(ctx, vpa, livePods); err != nil {
+			klog.V(0).InfoS("In-place rate limiter wait failed for in-place resize", "error", err)
+			return
+		}
+	}
+}
+
+// resizePods resizes in-place the pods of the MPA for which it is allowed,
+// in the order of their update priority. Returns error only if the rate
+// limiter stopped the loop.
+func (u *updater) resizePods(ctx context.Context, vpa *vpa_types.VerticalPodAutoscaler, livePods []*corev1.Pod) error {
+	creatorToSingleGroupStatsMap, podToReplicaCreatorMap, err := u.restrictionFactory.GetCreatorMaps(livePods, vpa)
+	if err != nil {
+		klog.ErrorS(err, "Failed to get creator maps")
+		return nil
+	}
+	inPlaceLimiter := u.restrictionFactory.NewPodsInPlaceRestriction(creatorToSingleGroupStatsMap, podToReplicaCreatorMap)
+
+	for _, pod := range u.getPodsUpdateOrder(livePods, vpa) {
+		switch inPlaceLimiter.CanInPlaceUpdate(pod, vpa, u.infeasibleAttempts) {
+		case utils.InPlaceApproved:
+			klog.V(2).InfoS("In-place update approved", "pod", klog.KObj(pod))
+		case utils.InPlaceInfeasible:
+			klog.V(2).InfoS("In-place update infeasible, retrying with new recommendation", "pod", klog.KObj(pod))
+			u.recordInfeasibleAttempt(pod, vpa)
+		default:
+			continue
+		}
+		if err := u.inPlaceRateLimiter.Wait(ctx); err != nil {
+			return err
+		}
+		if err := inPlaceLimiter.InPlaceUpdate(pod, vpa, u.eventRecorder); err != nil {
+			if isInfeasibleError(err) {
+				u.recordInfeasibleAttempt(pod, vpa)
+			}
+			klog.V(0).InfoS("In-place resize failed", "error", err, "pod", klog.KObj(pod))
+		}
+	}
+	return nil
+}
+
+// recordInfeasibleAttempt stores the recommendation that failed as infeasible
+func (u *updater) recordInfeasibleAttempt(pod *corev1.Pod, vpa *vpa_types.VerticalPodAutoscaler) {
+	u.infeasibleAttempts[pod.UID] = vpa.Status.Recommendation
+	klog.V(2).InfoS("Recorded infeasible attempt, will retry when recommendation changes", "pod", klog.KObj(pod))
+}
+
+// getPodsUpdateOrder returns list of pods that should be updated ordered by update priority
+func (u *updater) getPodsUpdateOrder(pods []*corev1.Pod, vpa *vpa_types.VerticalPodAutoscaler) []*corev1.Pod {
+	priorityCalculator := priority.NewUpdatePriorityCalculator(
+		vpa,
+		priority.UpdateConfig{
+			MinChangePriority:          u.config.DefaultUpdateThreshold,
+			PodLifetimeUpdateThreshold: u.config.PodLifetimeUpdateThreshold,
+			EvictAfterOOMThreshold:     u.config.EvictAfterOOMThreshold,
+		},
+		u.recommendationProcessor,
+		u.priorityProcessor)
+
+	for _, pod := range pods {
+		priorityCalculator.AddPod(pod, u.now(), u.infeasibleAttempts)
+	}
+
+	return priorityCalculator.GetSortedPods(priority.NewDefaultPodEvictionAdmission())
+}
+
+func getRateLimiter(rateLimit float64, rateLimitBurst int) *rate.Limiter {
+	var rateLimiter *rate.Limiter
+	if rateLimit <= 0 {
+		// As a special case if the rate is set to rate.Inf, the burst rate is ignored
+		// see https://github.com/golang/time/blob/master/rate/rate.go#L37
+		rateLimiter = rate.NewLimiter(rate.Inf, 0)
+		klog.V(1).InfoS("Rate limit disabled")
+	} else {
+		rateLimiter = rate.NewLimiter(rate.Limit(rateLimit), rateLimitBurst)
+	}
+	return rateLimiter
+}
+
+func newEventRecorder(kubeClient kube_client.Interface) record.EventRecorder {
+	eventBroadcaster := record.NewBroadcaster()
+	eventBroadcaster.StartStructuredLogging(4)
+	if _, isFake := kubeClient.(*fake.Clientset); !isFake {
+		eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: typedcorev1.New(kubeClient.CoreV1().RESTClient()).Events("")})
+	} else {
+		eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events("")})
+	}
+
+	mpascheme := scheme.Scheme
+	if err := mpa_types.AddToScheme(mpascheme); err != nil {
+		klog.ErrorS(err, "Error adding MPA scheme")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+
+	return eventBroadcaster.NewRecorder(mpascheme, corev1.EventSource{Component: "mpa-updater"})
+}
+
+// isInfeasibleError checks if an error indicates the resize is infeasible
+// due to insufficient node capacity.
+func isInfeasibleError(err error) bool {
+	var statusErr *apierrors.StatusError
+	if !errors.As(err, &statusErr) {
+		return false
+	}
+	if statusErr.ErrStatus.Reason != metav1.StatusReasonForbidden {
+		return false
+	}
+	if statusErr.ErrStatus.Details == nil {
+		return false
+	}
+	for _, cause := range statusErr.ErrStatus.Details.Causes {
+		if cause.Type == metav1.CauseType(utils.InfeasibleCauseNodeCapacity) {
+			return true
+		}
+	}
+	return false
+}
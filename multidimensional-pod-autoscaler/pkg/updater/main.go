@@ -0,0 +1,244 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/discovery"
+	cacheddiscovery "k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/informers"
+	kube_client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/scale"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	componentbaseconfig "k8s.io/component-base/config"
+	componentbaseoptions "k8s.io/component-base/config/options"
+	"k8s.io/klog/v2"
+
+	mpa_clientset "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/clientset/versioned"
+	"k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/updater/logic"
+	mpa_api_util "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/utils/mpa"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/common"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/patch"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/recommendation"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
+	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/controller_fetcher"
+	updater_config "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/config"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/inplace"
+	vpa_updater "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/logic"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/priority"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/client"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/limitrange"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/metrics"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/server"
+	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
+)
+
+const (
+	defaultResyncPeriod          time.Duration = 10 * time.Minute
+	scaleCacheEntryLifetime      time.Duration = time.Hour
+	scaleCacheEntryFreshnessTime time.Duration = 10 * time.Minute
+	scaleCacheEntryJitterFactor  float64       = 1.
+	discoveryResetPeriod         time.Duration = 5 * time.Minute
+)
+
+var scaleDownStabilization = flag.Duration("scale-down-stabilization", 5*time.Minute,
+	"Minimum time since the last change of the number of replicas of an MPA target before it is scaled down.")
+
+var config *updater_config.UpdaterConfig
+
+func main() {
+	// Leader election needs to be initialized before any other flag, because it may be used in other flag's validation.
+	leaderElection := defaultLeaderElectionConfiguration()
+	componentbaseoptions.BindLeaderElectionFlags(&leaderElection, pflag.CommandLine)
+
+	config = updater_config.InitUpdaterFlags()
+	// Pods are only ever resized in-place by the MPA updater.
+	if err := features.MutableFeatureGate.SetFromMap(map[string]bool{string(features.InPlace): true}); err != nil {
+		klog.ErrorS(err, "Failed to enable in-place updates")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+
+	klog.V(1).InfoS("Multidimensional Pod Autoscaler Updater", "version", common.VerticalPodAutoscalerVersion())
+
+	healthCheck := metrics.NewHealthCheck(config.UpdaterInterval * 5)
+	server.Initialize(&config.CommonFlags.EnableProfiling, healthCheck, &config.Address)
+
+	if !leaderElection.LeaderElect {
+		run(healthCheck, config.CommonFlags)
+	} else {
+		id, err := os.Hostname()
+		if err != nil {
+			klog.ErrorS(err, "Unable to get hostname")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+		id = id + "_" + string(uuid.NewUUID())
+
+		kubeConfig := common.CreateKubeConfigOrDie(config.CommonFlags.KubeConfig, float32(config.CommonFlags.KubeApiQps), int(config.CommonFlags.KubeApiBurst))
+		kubeClient := kube_client.NewForConfigOrDie(kubeConfig)
+
+		lock, err := resourcelock.New(
+			leaderElection.ResourceLock,
+			leaderElection.ResourceNamespace,
+			leaderElection.ResourceName,
+			kubeClient.CoreV1(),
+			kubeClient.CoordinationV1(),
+			resourcelock.ResourceLockConfig{
+				Identity: id,
+			},
+		)
+		if err != nil {
+			klog.ErrorS(err, "Unable to create leader election lock")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+
+		leaderelection.RunOrDie(context.TODO(), leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			LeaseDuration:   leaderElection.LeaseDuration.Duration,
+			RenewDeadline:   leaderElection.RenewDeadline.Duration,
+			RetryPeriod:     leaderElection.RetryPeriod.Duration,
+			ReleaseOnCancel: true,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(_ context.Context) {
+					run(healthCheck, config.CommonFlags)
+				},
+				OnStoppedLeading: func() {
+					klog.Fatal("lost master")
+				},
+			},
+		})
+	}
+}
+
+const (
+	defaultLeaseDuration = 15 * time.Second
+	defaultRenewDeadline = 10 * time.Second
+	defaultRetryPeriod   = 2 * time.Second
+)
+
+func defaultLeaderElectionConfiguration() componentbaseconfig.LeaderElectionConfiguration {
+	return componentbaseconfig.LeaderElectionConfiguration{
+		LeaderElect:       false,
+		LeaseDuration:     metav1.Duration{Duration: defaultLeaseDuration},
+		RenewDeadline:     metav1.Duration{Duration: defaultRenewDeadline},
+		RetryPeriod:       metav1.Duration{Duration: defaultRetryPeriod},
+		ResourceLock:      resourcelock.LeasesResourceLock,
+		ResourceName:      "mpa-updater",
+		ResourceNamespace: metav1.NamespaceSystem,
+	}
+}
+
+func run(healthCheck *metrics.HealthCheck, commonFlag *common.CommonFlags) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	kubeConfig := common.CreateKubeConfigOrDie(commonFlag.KubeConfig, float32(commonFlag.KubeApiQps), int(commonFlag.KubeApiBurst))
+	kubeClient := kube_client.NewForConfigOrDie(kubeConfig)
+	mpaClient := mpa_clientset.NewForConfigOrDie(kubeConfig)
+	kubeFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, defaultResyncPeriod,
+		informers.WithNamespace(commonFlag.VpaObjectNamespace),
+		informers.WithTransform(client.StripManagedFields),
+	)
+	mpaLister := mpa_api_util.NewMpasLister(mpaClient, stopCh, commonFlag.VpaObjectNamespace)
+	podLister := vpa_updater.NewPodLister(kubeClient, commonFlag.VpaObjectNamespace, stopCh)
+	targetSelectorFetcher := target.NewVpaTargetSelectorFetcher(kubeConfig, kubeClient, kubeFactory, stopCh)
+	controllerFetcher := controllerfetcher.NewControllerFetcher(kubeConfig, kubeClient, kubeFactory, scaleCacheEntryFreshnessTime, scaleCacheEntryLifetime, scaleCacheEntryJitterFactor, stopCh)
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(kubeConfig)
+	if err != nil {
+		klog.ErrorS(err, "Could not create discoveryClient")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	mapper := restmapper.NewDeferredDiscoveryRESTMapper(cacheddiscovery.NewMemCacheClient(discoveryClient))
+	go wait.Until(func() {
+		mapper.Reset()
+	}, discoveryResetPeriod, stopCh)
+	scaleNamespacer := scale.New(kubeClient.CoreV1().RESTClient(), mapper, dynamic.LegacyAPIPathResolverFunc, scale.NewDiscoveryScaleKindResolver(discoveryClient))
+
+	var limitRangeCalculator limitrange.LimitRangeCalculator
+	limitRangeCalculator, err = limitrange.NewLimitsRangeCalculator(kubeFactory)
+	if err != nil {
+		klog.ErrorS(err, "Failed to create limitRangeCalculator, falling back to not checking limits")
+		limitRangeCalculator = limitrange.NewNoopLimitsCalculator()
+	}
+
+	recommendationProvider := recommendation.NewProvider(limitRangeCalculator, vpa_api_util.NewCappingRecommendationProcessor(limitRangeCalculator))
+
+	calculators := []patch.Calculator{inplace.NewResourceInPlaceUpdatesCalculator(recommendationProvider), inplace.NewInPlaceUpdatedCalculator()}
+
+	updater := logic.NewUpdater(
+		kubeClient,
+		mpaClient.AutoscalingV1alpha1(),
+		mpaLister,
+		kubeFactory,
+		podLister,
+		scaleNamespacer,
+		mapper,
+		logic.UpdaterConfig{
+			MinReplicas:                 config.MinReplicas,
+			InPlaceRateLimit:            config.EvictionRateLimit,
+			InPlaceRateBurst:            config.EvictionRateBurst,
+			InPlaceSkipDisruptionBudget: config.InPlaceSkipDisruptionBudget,
+			DefaultUpdateThreshold:      config.DefaultUpdateThreshold,
+			PodLifetimeUpdateThreshold:  config.PodLifetimeUpdateThreshold,
+			EvictAfterOOMThreshold:      config.EvictAfterOOMThreshold,
+			ScaleDownStabilization:      *scaleDownStabilization,
+			IgnoredNamespaces:           strings.Split(commonFlag.IgnoredVpaObjectNamespaces, ","),
+		},
+		vpa_api_util.NewCappingRecommendationProcessor(limitRangeCalculator),
+		targetSelectorFetcher,
+		controllerFetcher,
+		priority.NewProcessor(),
+		calculators,
+		// The MPA updater doesn't unboost pods, so it doesn't need their metrics.
+		nil,
+	)
+
+	// Start factories
+	kubeFactory.Start(stopCh)
+	informerMap := kubeFactory.WaitForCacheSync(stopCh)
+	for kind, synced := range informerMap {
+		if !synced {
+			klog.ErrorS(nil, fmt.Sprintf("Could not sync cache for the %s informer", kind.String()))
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+	}
+
+	// Start updating health check endpoint.
+	healthCheck.StartMonitoring()
+
+	ticker := time.Tick(config.UpdaterInterval)
+	for range ticker {
+		ctx, cancel := context.WithTimeout(context.Background(), config.UpdaterInterval)
+		updater.RunOnce(ctx)
+		healthCheck.UpdateLastActivity()
+		cancel()
+	}
+}
@@ -0,0 +1,304 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+
+	mpa_types "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1alpha1"
+	mpa_clientset "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/clientset/versioned"
+	mpa_api "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/clientset/versioned/typed/autoscaling.k8s.io/v1alpha1"
+	mpa_lister "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/client/listers/autoscaling.k8s.io/v1alpha1"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/client"
+)
+
+const (
+	// DefaultMinReplicas is the number of replicas the autoscaler can scale
+	// down to if the MPA doesn't set minReplicas.
+	DefaultMinReplicas int32 = 1
+)
+
+type patchRecord struct {
+	Op    string `json:"op,inline"`
+	Path  string `json:"path,inline"`
+	Value any    `json:"value"`
+}
+
+func patchMpaStatus(mpaClient mpa_api.MultidimensionalPodAutoscalerInterface, mpaName string, patches []patchRecord) (result *mpa_types.MultidimensionalPodAutoscaler, err error) {
+	bytes, err := json.Marshal(patches)
+	if err != nil {
+		klog.ErrorS(err, "Cannot marshal MPA status patches", "patches", patches)
+		return nil, err
+	}
+
+	return mpaClient.Patch(context.TODO(), mpaName, types.JSONPatchType, bytes, metav1.PatchOptions{}, "status")
+}
+
+// UpdateMpaStatusIfNeeded updates the status field of the MPA API object.
+func UpdateMpaStatusIfNeeded(mpaClient mpa_api.MultidimensionalPodAutoscalerInterface, mpaName string, newStatus,
+	oldStatus *mpa_types.MultidimensionalPodAutoscalerStatus) (result *mpa_types.MultidimensionalPodAutoscaler, err error) {
+	patches := []patchRecord{{
+		Op:    "add",
+		Path:  "/status",
+		Value: *newStatus,
+	}}
+
+	if !apiequality.Semantic.DeepEqual(*oldStatus, *newStatus) {
+		return patchMpaStatus(mpaClient, mpaName, patches)
+	}
+	return nil, nil
+}
+
+// NewMpasLister returns MultidimensionalPodAutoscalerLister configured to fetch all MPA objects from namespace,
+// set namespace to k8sapiv1.NamespaceAll to select all namespaces.
+// The method blocks until mpaLister is initially populated.
+func NewMpasLister(mpaClient *mpa_clientset.Clientset, stopChannel <-chan struct{}, namespace string) mpa_lister.MultidimensionalPodAutoscalerLister {
+	mpaListWatch := cache.NewListWatchFromClient(mpaClient.AutoscalingV1alpha1().RESTClient(), "multidimensionalpodautoscalers", namespace, fields.Everything())
+	informerOptions := cache.InformerOptions{
+		ObjectType:    &mpa_types.MultidimensionalPodAutoscaler{},
+		ListerWatcher: mpaListWatch,
+		Handler:       &cache.ResourceEventHandlerFuncs{},
+		ResyncPeriod:  1 * time.Hour,
+		Indexers:      cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+		Transform:     client.StripManagedFields,
+	}
+
+	store, controller := cache.NewInformerWithOptions(informerOptions)
+	indexer, ok := store.(cache.Indexer)
+	if !ok {
+		klog.ErrorS(nil, "Expected Indexer, but got a Store that does not implement Indexer")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+	mpaLister := mpa_lister.NewMultidimensionalPodAutoscalerLister(indexer)
+	go controller.Run(stopChannel)
+	if !cache.WaitForCacheSync(stopChannel, controller.HasSynced) {
+		klog.ErrorS(nil, "Failed to sync MPA cache during initialization")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	} else {
+		klog.InfoS("Initial MPA synced successfully")
+	}
+	return mpaLister
+}
+
+// GetUpdateMode returns the updateMode from the MPA policy, or the default
+// value if it is not specified.
+func GetUpdateMode(mpa *mpa_types.MultidimensionalPodAutoscaler) mpa_types.UpdateMode {
+	if mpa.Spec.Policy == nil || mpa.Spec.Policy.UpdateMode == nil || *mpa.Spec.Policy.UpdateMode == "" {
+		return mpa_types.UpdateModeAuto
+	}
+	return *mpa.Spec.Policy.UpdateMode
+}
+
+// GetTargetCPUUtilization returns the average CPU utilization target (in
+// percent of the request) of the MPA goals, or nil if it doesn't have one.
+func GetTargetCPUUtilization(mpa *mpa_types.MultidimensionalPodAutoscaler) *int32 {
+	if mpa.Spec.Goals == nil {
+		return nil
+	}
+	for _, metric := range mpa.Spec.Goals.Metrics {
+		if metric.Type != autoscalingv2.ResourceMetricSourceType || metric.Resource == nil {
+			continue
+		}
+		if metric.Resource.Name != corev1.ResourceCPU || metric.Resource.Target.Type != autoscalingv2.UtilizationMetricType {
+			continue
+		}
+		if metric.Resource.Target.AverageUtilization != nil && *metric.Resource.Target.AverageUtilization > 0 {
+			return metric.Resource.Target.AverageUtilization
+		}
+	}
+	return nil
+}
+
+// GetReplicaBounds returns the minimum and maximum number of replicas the
+// autoscaler can scale the target to.
+func GetReplicaBounds(mpa *mpa_types.MultidimensionalPodAutoscaler) (int32, int32) {
+	minReplicas, maxReplicas := DefaultMinReplicas, int32(math.MaxInt32)
+	if mpa.Spec.Constraints == nil || mpa.Spec.Constraints.Global == nil {
+		return minReplicas, maxReplicas
+	}
+	if mpa.Spec.Constraints.Global.MinReplicas != nil {
+		minReplicas = *mpa.Spec.Constraints.Global.MinReplicas
+	}
+	if mpa.Spec.Constraints.Global.MaxReplicas != nil {
+		maxReplicas = max(*mpa.Spec.Constraints.Global.MaxReplicas, minReplicas)
+	}
+	return minReplicas, maxReplicas
+}
+
+// ConvertToVpa returns the VerticalPodAutoscaler equivalent to the vertical
+// scaling part of the MPA, so that the MPA can be handled by the VPA
+// recommender model and updater libraries. The VPA coordinates its CPU
+// recommendation with the horizontal scaling of the MPA if the MPA has a CPU
+// utilization goal.
+func ConvertToVpa(mpa *mpa_types.MultidimensionalPodAutoscaler) *vpa_types.VerticalPodAutoscaler {
+	vpa := &vpa_types.VerticalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              mpa.Name,
+			Namespace:         mpa.Namespace,
+			UID:               mpa.UID,
+			Generation:        mpa.Generation,
+			CreationTimestamp: mpa.CreationTimestamp,
+			Labels:            mpa.Labels,
+			Annotations:       mpa.Annotations,
+		},
+		Spec: vpa_types.VerticalPodAutoscalerSpec{
+			TargetRef:      mpa.Spec.ScaleTargetRef,
+			UpdatePolicy:   convertUpdatePolicy(mpa),
+			ResourcePolicy: convertResourcePolicy(mpa.Spec.Constraints),
+		},
+		Status: vpa_types.VerticalPodAutoscalerStatus{
+			Recommendation: mpa.Status.Recommendation,
+		},
+	}
+	for _, recommender := range mpa.Spec.Recommenders {
+		vpa.Spec.Recommenders = append(vpa.Spec.Recommenders, &vpa_types.VerticalPodAutoscalerRecommenderSelector{Name: recommender.Name})
+	}
+	if GetTargetCPUUtilization(mpa) != nil {
+		vpa.Spec.HPACoordination = &vpa_types.HPACoordination{Mode: vpa_types.HPACoordinationModeCPUUtilization}
+	}
+	for _, condition := range mpa.Status.Conditions {
+		vpa.Status.Conditions = append(vpa.Status.Conditions, vpa_types.VerticalPodAutoscalerCondition{
+			Type:               vpa_types.VerticalPodAutoscalerConditionType(condition.Type),
+			Status:             condition.Status,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return vpa
+}
+
+func convertUpdatePolicy(mpa *mpa_types.MultidimensionalPodAutoscaler) *vpa_types.PodUpdatePolicy {
+	// Pod resources of an MPA are only ever changed in-place, so that
+	// vertical scaling doesn't change the number of running replicas.
+	updateMode := vpa_types.UpdateModeInPlace
+	if GetUpdateMode(mpa) == mpa_types.UpdateModeOff {
+		updateMode = vpa_types.UpdateModeOff
+	}
+	return &vpa_types.PodUpdatePolicy{UpdateMode: &updateMode}
+}
+
+func convertResourcePolicy(constraints *mpa_types.ScalingConstraints) *vpa_types.PodResourcePolicy {
+	if constraints == nil || (len(constraints.Container) == 0 && len(constraints.ContainerControlledResources) == 0) {
+		return nil
+	}
+	policy := &vpa_types.PodResourcePolicy{}
+	hasDefault := false
+	for _, container := range constraints.Container {
+		containerPolicy := vpa_types.ContainerResourcePolicy{
+			ContainerName: container.Name,
+			Mode:          container.Mode,
+		}
+		if container.Requests != nil {
+			containerPolicy.MinAllowed = container.Requests.MinAllowed
+			containerPolicy.MaxAllowed = container.Requests.MaxAllowed
+		}
+		if container.Name == mpa_types.DefaultContainerResourcePolicy {
+			hasDefault = true
+		}
+		policy.ContainerPolicies = append(policy.ContainerPolicies, containerPolicy)
+	}
+	if len(constraints.ContainerControlledResources) == 0 {
+		return policy
+	}
+	// Controlled resources apply to all containers, so they are set on every
+	// container policy, including a default one if the MPA doesn't have it.
+	if !hasDefault {
+		policy.ContainerPolicies = append(policy.ContainerPolicies, vpa_types.ContainerResourcePolicy{
+			ContainerName: vpa_types.DefaultContainerResourcePolicy,
+		})
+	}
+	for i := range policy.ContainerPolicies {
+		controlledResources := append([]corev1.ResourceName(nil), constraints.ContainerControlledResources...)
+		policy.ContainerPolicies[i].ControlledResources = &controlledResources
+	}
+	return policy
+}
+
+// ConvertToHpa returns the HorizontalPodAutoscaler equivalent to the
+// horizontal scaling part of the MPA. It is never created in the cluster, but
+// lets the VPA recommender find the CPU utilization target to coordinate with.
+func ConvertToHpa(mpa *mpa_types.MultidimensionalPodAutoscaler) *autoscalingv2.HorizontalPodAutoscaler {
+	minReplicas, maxReplicas := GetReplicaBounds(mpa)
+	hpa := &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      mpa.Name,
+			Namespace: mpa.Namespace,
+			UID:       mpa.UID,
+		},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+		},
+	}
+	if mpa.Spec.ScaleTargetRef != nil {
+		hpa.Spec.ScaleTargetRef = autoscalingv2.CrossVersionObjectReference{
+			APIVersion: mpa.Spec.ScaleTargetRef.APIVersion,
+			Kind:       mpa.Spec.ScaleTargetRef.Kind,
+			Name:       mpa.Spec.ScaleTargetRef.Name,
+		}
+	}
+	if mpa.Spec.Goals != nil {
+		hpa.Spec.Metrics = mpa.Spec.Goals.Metrics
+	}
+	return hpa
+}
+
+// ConvertVpaConditions returns the MPA conditions equivalent to the given VPA
+// conditions.
+func ConvertVpaConditions(conditions []vpa_types.VerticalPodAutoscalerCondition) []mpa_types.MultidimensionalPodAutoscalerCondition {
+	var result []mpa_types.MultidimensionalPodAutoscalerCondition
+	for _, condition := range conditions {
+		result = append(result, mpa_types.MultidimensionalPodAutoscalerCondition{
+			Type:               mpa_types.MultidimensionalPodAutoscalerConditionType(condition.Type),
+			Status:             condition.Status,
+			LastTransitionTime: condition.LastTransitionTime,
+			Reason:             condition.Reason,
+			Message:            condition.Message,
+		})
+	}
+	return result
+}
+
+// SetCondition sets the given condition in the list of conditions. The last
+// transition time is only updated when the status of the condition changes.
+func SetCondition(conditions []mpa_types.MultidimensionalPodAutoscalerCondition, condition mpa_types.MultidimensionalPodAutoscalerCondition) []mpa_types.MultidimensionalPodAutoscalerCondition {
+	for i, existing := range conditions {
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		conditions[i] = condition
+		return conditions
+	}
+	return append(conditions, condition)
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	mpa_types "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1alpha1"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+func int32Ptr(v int32) *int32 {
+	return &v
+}
+
+func cpuUtilizationGoal(utilization int32) *mpa_types.Goals {
+	return &mpa_types.Goals{Metrics: []autoscalingv2.MetricSpec{{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: corev1.ResourceCPU,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: int32Ptr(utilization),
+			},
+		},
+	}}}
+}
+
+func TestGetReplicaBounds(t *testing.T) {
+	testCases := []struct {
+		name        string
+		global      *mpa_types.HorizontalScalingConstraints
+		expectedMin int32
+		expectedMax int32
+	}{
+		{
+			name:        "no constraints",
+			expectedMin: DefaultMinReplicas,
+			expectedMax: math.MaxInt32,
+		},
+		{
+			name:        "min and max",
+			global:      &mpa_types.HorizontalScalingConstraints{MinReplicas: int32Ptr(2), MaxReplicas: int32Ptr(5)},
+			expectedMin: 2,
+			expectedMax: 5,
+		},
+		{
+			name:        "max below min",
+			global:      &mpa_types.HorizontalScalingConstraints{MinReplicas: int32Ptr(4), MaxReplicas: int32Ptr(3)},
+			expectedMin: 4,
+			expectedMax: 4,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mpa := &mpa_types.MultidimensionalPodAutoscaler{}
+			if tc.global != nil {
+				mpa.Spec.Constraints = &mpa_types.ScalingConstraints{Global: tc.global}
+			}
+			minReplicas, maxReplicas := GetReplicaBounds(mpa)
+			assert.Equal(t, tc.expectedMin, minReplicas)
+			assert.Equal(t, tc.expectedMax, maxReplicas)
+		})
+	}
+}
+
+func TestConvertToVpa(t *testing.T) {
+	offMode := mpa_types.UpdateModeOff
+	targetRef := &autoscalingv1.CrossVersionObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "app"}
+	maxAllowed := corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")}
+
+	mpa := &mpa_types.MultidimensionalPodAutoscaler{
+		Spec: mpa_types.MultidimensionalPodAutoscalerSpec{
+			ScaleTargetRef: targetRef,
+			Goals:          cpuUtilizationGoal(60),
+			Constraints: &mpa_types.ScalingConstraints{
+				ContainerControlledResources: []corev1.ResourceName{corev1.ResourceMemory},
+				Container: []mpa_types.VerticalScalingConstraints{{
+					Name:     "app",
+					Requests: &mpa_types.ResourceConstraints{MaxAllowed: maxAllowed},
+				}},
+			},
+		},
+	}
+	vpa := ConvertToVpa(mpa)
+	assert.Equal(t, targetRef, vpa.Spec.TargetRef)
+	assert.Equal(t, vpa_types.UpdateModeInPlace, *vpa.Spec.UpdatePolicy.UpdateMode)
+	assert.Equal(t, &vpa_types.HPACoordination{Mode: vpa_types.HPACoordinationModeCPUUtilization}, vpa.Spec.HPACoordination)
+	controlledResources := []corev1.ResourceName{corev1.ResourceMemory}
+	assert.Equal(t, &vpa_types.PodResourcePolicy{ContainerPolicies: []vpa_types.ContainerResourcePolicy{
+		{ContainerName: "app", MaxAllowed: maxAllowed, ControlledResources: &controlledResources},
+		{ContainerName: vpa_types.DefaultContainerResourcePolicy, ControlledResources: &controlledResources},
+	}}, vpa.Spec.ResourcePolicy)
+
+	mpa.Spec.Policy = &mpa_types.PodUpdatePolicy{UpdateMode: &offMode}
+	mpa.Spec.Goals = nil
+	vpa = ConvertToVpa(mpa)
+	assert.Equal(t, vpa_types.UpdateModeOff, *vpa.Spec.UpdatePolicy.UpdateMode)
+	assert.Nil(t, vpa.Spec.HPACoordination)
+}
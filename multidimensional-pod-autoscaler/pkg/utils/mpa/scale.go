@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"fmt"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/scale"
+
+	mpa_types "k8s.io/autoscaler/multidimensional-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1alpha1"
+)
+
+// GetScale returns the scale subresource of the target of the MPA, along with
+// the group resource it belongs to.
+func GetScale(ctx context.Context, scaleNamespacer scale.ScalesGetter, mapper meta.RESTMapper, mpa *mpa_types.MultidimensionalPodAutoscaler) (schema.GroupResource, *autoscalingv1.Scale, error) {
+	targetRef := mpa.Spec.ScaleTargetRef
+	if targetRef == nil {
+		return schema.GroupResource{}, nil, fmt.Errorf("no scale target reference")
+	}
+	targetGV, err := schema.ParseGroupVersion(targetRef.APIVersion)
+	if err != nil {
+		return schema.GroupResource{}, nil, fmt.Errorf("invalid API version in scale target reference: %v", err)
+	}
+	targetGK := schema.GroupKind{Group: targetGV.Group, Kind: targetRef.Kind}
+	mappings, err := mapper.RESTMappings(targetGK)
+	if err != nil {
+		return schema.GroupResource{}, nil, fmt.Errorf("unable to determine resource for scale target reference: %v", err)
+	}
+	var errs []error
+	for _, mapping := range mappings {
+		targetGR := mapping.Resource.GroupResource()
+		scale, err := scaleNamespacer.Scales(mpa.Namespace).Get(ctx, targetGR, targetRef.Name, metav1.GetOptions{})
+		if err == nil {
+			return targetGR, scale, nil
+		}
+		errs = append(errs, err)
+	}
+	return schema.GroupResource{}, nil, fmt.Errorf("unable to get scale of %s %s/%s: %v", targetRef.Kind, mpa.Namespace, targetRef.Name, errs)
+}
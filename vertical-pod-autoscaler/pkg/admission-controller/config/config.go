@@ -58,7 +58,11 @@ type AdmissionControllerConfig struct {
 	WebhookLabels        string
 	RegisterByURL        bool
 
-	MaxAllowedCPUBoost resource.QuantityValue
+	MaxAllowedCPUBoost    resource.QuantityValue
+	MaxAllowedMemoryBoost resource.QuantityValue
+
+	EnableRecommendationPreview       bool
+	RecommendationPreviewClientCaFile string
 }
 
 // DefaultAdmissionControllerConfig returns a AdmissionControllerConfig with default values
@@ -85,7 +89,11 @@ func DefaultAdmissionControllerConfig() *AdmissionControllerConfig {
 		WebhookLabels:        "",
 		RegisterByURL:        false,
 
-		MaxAllowedCPUBoost: resource.QuantityValue{},
+		MaxAllowedCPUBoost:    resource.QuantityValue{},
+		MaxAllowedMemoryBoost: resource.QuantityValue{},
+
+		EnableRecommendationPreview:       false,
+		RecommendationPreviewClientCaFile: "",
 	}
 }
 
@@ -113,6 +121,9 @@ func InitAdmissionControllerFlags() *AdmissionControllerConfig {
 	flag.BoolVar(&config.RegisterByURL, "register-by-url", config.RegisterByURL, "If set to true, admission webhook will be registered by URL (webhookAddress:webhookPort) instead of by service name")
 
 	flag.Var(&config.MaxAllowedCPUBoost, "max-allowed-cpu-boost", "Maximum amount of CPU that will be applied for a container with boost.")
+	flag.Var(&config.MaxAllowedMemoryBoost, "max-allowed-memory-boost", "Maximum amount of memory that will be applied for a container with boost.")
+	flag.BoolVar(&config.EnableRecommendationPreview, "enable-recommendation-preview", config.EnableRecommendationPreview, "If set to true, serve the recommendation preview endpoint on the webhook server. Callers need a bearer token or a client certificate of a user allowed to get VPAs in the previewed namespace.")
+	flag.StringVar(&config.RecommendationPreviewClientCaFile, "recommendation-preview-client-ca-file", config.RecommendationPreviewClientCaFile, "Path to the PEM file of the CAs verifying the client certificates of recommendation preview callers, usually the client CA of the API server. If empty, callers need a bearer token.")
 
 	// These need to happen last. kube_flag.InitFlags() synchronizes and parses
 	// flags from the flag package to pflag, so feature gates must be added to
@@ -17,6 +17,8 @@ limitations under the License.
 package main
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/http"
 	"os"
@@ -31,6 +33,7 @@ import (
 	"k8s.io/autoscaler/vertical-pod-autoscaler/common"
 	admissioncontroller_config "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/config"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/logic"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/preview"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/patch"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/recommendation"
@@ -63,7 +66,6 @@ func main() {
 
 	healthCheck := metrics.NewHealthCheck(time.Minute)
 	metrics_admission.Register()
-	server.Initialize(&config.CommonFlags.EnableProfiling, healthCheck, &config.Address)
 
 	kubeConfig := common.CreateKubeConfigOrDie(config.CommonFlags.KubeConfig, float32(config.CommonFlags.KubeApiQps), int(config.CommonFlags.KubeApiBurst))
 
@@ -90,6 +92,8 @@ func main() {
 	}
 	recommendationProvider := recommendation.NewProvider(limitRangeCalculator, vpa_api_util.NewCappingRecommendationProcessor(limitRangeCalculator))
 	vpaMatcher := vpa.NewMatcher(vpaLister, targetSelectorFetcher, controllerFetcher)
+	calculators := []patch.Calculator{patch.NewResourceUpdatesCalculator(recommendationProvider, config.MaxAllowedCPUBoost, config.MaxAllowedMemoryBoost), patch.NewObservedContainersCalculator()}
+	server.Initialize(&config.CommonFlags.EnableProfiling, healthCheck, &config.Address)
 
 	factory.Start(stopCh)
 	informerMap := factory.WaitForCacheSync(stopCh)
@@ -118,12 +122,22 @@ func main() {
 		hostname,
 	)
 
-	calculators := []patch.Calculator{patch.NewResourceUpdatesCalculator(recommendationProvider, config.MaxAllowedCPUBoost), patch.NewObservedContainersCalculator()}
 	as := logic.NewAdmissionServer(podPreprocessor, vpaPreprocessor, limitRangeCalculator, vpaMatcher, calculators)
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		as.Serve(w, r)
 		healthCheck.UpdateLastActivity()
 	})
+	var previewClientCAs *x509.CertPool
+	if config.EnableRecommendationPreview {
+		if config.RecommendationPreviewClientCaFile != "" {
+			previewClientCAs = x509.NewCertPool()
+			if !previewClientCAs.AppendCertsFromPEM(readFile(config.RecommendationPreviewClientCaFile)) {
+				klog.ErrorS(nil, "No CA certificates found for recommendation preview callers", "file", config.RecommendationPreviewClientCaFile)
+				klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+			}
+		}
+		http.Handle(preview.Path, preview.NewHandler(preview.NewKubernetesAuthorizer(kubeClient, previewClientCAs), podPreprocessor, vpaMatcher, vpaLister, calculators))
+	}
 	var mutatingWebhookClient typedadmregv1.MutatingWebhookConfigurationInterface
 	if config.RegisterWebhook {
 		mutatingWebhookClient = kubeClient.AdmissionregistrationV1().MutatingWebhookConfigurations()
@@ -132,6 +146,11 @@ func main() {
 		Addr:      fmt.Sprintf(":%d", config.Port),
 		TLSConfig: configTLS(*config.CertsConfiguration, config.MinTlsVersion, config.Ciphers, stopCh, mutatingWebhookClient),
 	}
+	if previewClientCAs != nil {
+		// Client certificates are verified by the preview handler, admission
+		// reviews are still served to callers without one.
+		server.TLSConfig.ClientAuth = tls.RequestClientCert
+	}
 	url := fmt.Sprintf("%v:%v", config.WebhookAddress, config.WebhookPort)
 	ignoredNamespaces := strings.Split(config.CommonFlags.IgnoredVpaObjectNamespaces, ",")
 	go func() {
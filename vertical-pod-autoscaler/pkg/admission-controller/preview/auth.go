@@ -0,0 +1,166 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preview
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+// Credentials are the credentials the caller of a preview request presented.
+type Credentials struct {
+	// Token is the bearer token of the request.
+	Token string
+	// Certificates is the client certificate chain of the TLS connection,
+	// starting with the client certificate.
+	Certificates []*x509.Certificate
+}
+
+// credentialsFromRequest returns the credentials presented with the request.
+func credentialsFromRequest(r *http.Request) Credentials {
+	credentials := Credentials{Token: bearerToken(r)}
+	if r.TLS != nil {
+		credentials.Certificates = r.TLS.PeerCertificates
+	}
+	return credentials
+}
+
+// Authorizer checks that the caller of a preview request may read the VPAs of
+// the namespace the preview is requested for.
+type Authorizer interface {
+	// Authorize returns an error if the credentials don't authenticate a user
+	// allowed to get VPAs in the namespace.
+	Authorize(ctx context.Context, credentials Credentials, namespace string) error
+}
+
+type unauthenticatedError struct {
+	msg string
+}
+
+func (e *unauthenticatedError) Error() string {
+	return e.msg
+}
+
+type forbiddenError struct {
+	msg string
+}
+
+func (e *forbiddenError) Error() string {
+	return e.msg
+}
+
+type kubernetesAuthorizer struct {
+	kubeClient kubernetes.Interface
+	clientCAs  *x509.CertPool
+}
+
+// NewKubernetesAuthorizer returns an Authorizer authenticating the caller with
+// a TokenReview of its bearer token, or with its client certificate if it is
+// signed by one of the client CAs, and authorizing it with a
+// SubjectAccessReview. Client certificates are not accepted if clientCAs is
+// nil.
+func NewKubernetesAuthorizer(kubeClient kubernetes.Interface, clientCAs *x509.CertPool) Authorizer {
+	return &kubernetesAuthorizer{kubeClient: kubeClient, clientCAs: clientCAs}
+}
+
+func (a *kubernetesAuthorizer) Authorize(ctx context.Context, credentials Credentials, namespace string) error {
+	user, err := a.authenticate(ctx, credentials)
+	if err != nil {
+		return err
+	}
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	access, err := a.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "get",
+				Group:     vpa_types.SchemeGroupVersion.Group,
+				Resource:  "verticalpodautoscalers",
+			},
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to review access: %v", err)
+	}
+	if !access.Status.Allowed {
+		return &forbiddenError{fmt.Sprintf("user %q cannot get verticalpodautoscalers in namespace %q", user.Username, namespace)}
+	}
+	return nil
+}
+
+// authenticate returns the user of the bearer token if there is one, or the
+// user of the client certificate otherwise. Like the API server, it takes the
+// user name from the common name of the certificate and the groups from its
+// organizations.
+func (a *kubernetesAuthorizer) authenticate(ctx context.Context, credentials Credentials) (authenticationv1.UserInfo, error) {
+	if credentials.Token != "" {
+		review, err := a.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+			Spec: authenticationv1.TokenReviewSpec{Token: credentials.Token},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return authenticationv1.UserInfo{}, fmt.Errorf("failed to review token: %v", err)
+		}
+		if !review.Status.Authenticated {
+			return authenticationv1.UserInfo{}, &unauthenticatedError{"invalid bearer token"}
+		}
+		return review.Status.User, nil
+	}
+	if a.clientCAs == nil || len(credentials.Certificates) == 0 {
+		return authenticationv1.UserInfo{}, &unauthenticatedError{"a bearer token or a client certificate is required"}
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range credentials.Certificates[1:] {
+		intermediates.AddCert(cert)
+	}
+	cert := credentials.Certificates[0]
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:         a.clientCAs,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		return authenticationv1.UserInfo{}, &unauthenticatedError{fmt.Sprintf("invalid client certificate: %v", err)}
+	}
+	if cert.Subject.CommonName == "" {
+		return authenticationv1.UserInfo{}, &unauthenticatedError{"client certificate has no common name"}
+	}
+	return authenticationv1.UserInfo{Username: cert.Subject.CommonName, Groups: cert.Subject.Organization}, nil
+}
+
+func bearerToken(r *http.Request) string {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,217 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preview
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+)
+
+func newFakeAuthClient(authenticated, allowed bool, gotAccess *authorizationv1.SubjectAccessReview) *fake.Clientset {
+	client := &fake.Clientset{}
+	client.AddReactor("create", "tokenreviews", func(action core.Action) (bool, runtime.Object, error) {
+		review := action.(core.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		review.Status.Authenticated = authenticated && review.Spec.Token == "token"
+		review.Status.User = authenticationv1.UserInfo{Username: "alice", Groups: []string{"devs"}}
+		return true, review, nil
+	})
+	client.AddReactor("create", "subjectaccessreviews", func(action core.Action) (bool, runtime.Object, error) {
+		review := action.(core.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		*gotAccess = *review
+		review.Status.Allowed = allowed
+		return true, review, nil
+	})
+	return client
+}
+
+func TestKubernetesAuthorizer(t *testing.T) {
+	testCases := []struct {
+		name          string
+		token         string
+		authenticated bool
+		allowed       bool
+		expectedErr   interface{}
+	}{
+		{
+			name:          "allowed",
+			token:         "token",
+			authenticated: true,
+			allowed:       true,
+		},
+		{
+			name:          "no token",
+			authenticated: true,
+			allowed:       true,
+			expectedErr:   &unauthenticatedError{},
+		},
+		{
+			name:        "invalid token",
+			token:       "token",
+			allowed:     true,
+			expectedErr: &unauthenticatedError{},
+		},
+		{
+			name:          "forbidden",
+			token:         "token",
+			authenticated: true,
+			expectedErr:   &forbiddenError{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotAccess authorizationv1.SubjectAccessReview
+			a := NewKubernetesAuthorizer(newFakeAuthClient(tc.authenticated, tc.allowed, &gotAccess), nil)
+
+			err := a.Authorize(context.Background(), Credentials{Token: tc.token}, "default")
+
+			switch expected := tc.expectedErr.(type) {
+			case nil:
+				assert.NoError(t, err)
+				assert.Equal(t, "alice", gotAccess.Spec.User)
+				assert.Equal(t, []string{"devs"}, gotAccess.Spec.Groups)
+				assert.Equal(t, &authorizationv1.ResourceAttributes{
+					Namespace: "default",
+					Verb:      "get",
+					Group:     "autoscaling.k8s.io",
+					Resource:  "verticalpodautoscalers",
+				}, gotAccess.Spec.ResourceAttributes)
+			case *unauthenticatedError:
+				assert.True(t, errors.As(err, &expected), "expected unauthenticated error, got %v", err)
+			case *forbiddenError:
+				assert.True(t, errors.As(err, &expected), "expected forbidden error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	r := httptest.NewRequest("POST", Path, nil)
+	assert.Equal(t, "", bearerToken(r))
+	r.Header.Set("Authorization", "Basic abc")
+	assert.Equal(t, "", bearerToken(r))
+	r.Header.Set("Authorization", "Bearer abc")
+	assert.Equal(t, "abc", bearerToken(r))
+}
+
+// newTestCertificate returns a certificate for the subject, signed by the
+// parent or self-signed if parent is nil.
+func newTestCertificate(t *testing.T, subject pkix.Name, isCA bool, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               subject,
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
+
+func TestKubernetesAuthorizerClientCertificate(t *testing.T) {
+	ca, caKey := newTestCertificate(t, pkix.Name{CommonName: "client-ca"}, true, nil, nil)
+	otherCA, otherCAKey := newTestCertificate(t, pkix.Name{CommonName: "other-ca"}, true, nil, nil)
+	clientCert, _ := newTestCertificate(t, pkix.Name{CommonName: "bob", Organization: []string{"ops"}}, false, ca, caKey)
+	otherCert, _ := newTestCertificate(t, pkix.Name{CommonName: "bob"}, false, otherCA, otherCAKey)
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(ca)
+
+	testCases := []struct {
+		name         string
+		clientCAs    *x509.CertPool
+		certificates []*x509.Certificate
+		expectedErr  bool
+	}{
+		{
+			name:         "trusted certificate",
+			clientCAs:    clientCAs,
+			certificates: []*x509.Certificate{clientCert},
+		},
+		{
+			name:         "untrusted certificate",
+			clientCAs:    clientCAs,
+			certificates: []*x509.Certificate{otherCert},
+			expectedErr:  true,
+		},
+		{
+			name:         "client certificates not accepted",
+			certificates: []*x509.Certificate{clientCert},
+			expectedErr:  true,
+		},
+		{
+			name:        "no credentials",
+			clientCAs:   clientCAs,
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotAccess authorizationv1.SubjectAccessReview
+			a := NewKubernetesAuthorizer(newFakeAuthClient(true, true, &gotAccess), tc.clientCAs)
+
+			err := a.Authorize(context.Background(), Credentials{Certificates: tc.certificates}, "default")
+
+			if tc.expectedErr {
+				var unauthenticated *unauthenticatedError
+				assert.True(t, errors.As(err, &unauthenticated), "expected unauthenticated error, got %v", err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "bob", gotAccess.Spec.User)
+			assert.Equal(t, []string{"ops"}, gotAccess.Spec.Groups)
+		})
+	}
+}
+
+func TestCredentialsFromRequest(t *testing.T) {
+	cert, _ := newTestCertificate(t, pkix.Name{CommonName: "bob"}, false, nil, nil)
+	r := httptest.NewRequest("POST", Path, nil)
+	r.Header.Set("Authorization", "Bearer abc")
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+
+	credentials := credentialsFromRequest(r)
+	assert.Equal(t, "abc", credentials.Token)
+	assert.Equal(t, []*x509.Certificate{cert}, credentials.Certificates)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preview
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	jsonpatch "gopkg.in/evanphx/json-patch.v4"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	resource_admission "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource"
+)
+
+const unset = "<unset>"
+
+func applyPatches(p *corev1.Pod, patches []resource_admission.PatchRecord) (*corev1.Pod, error) {
+	original, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	rawPatch, err := json.Marshal(patches)
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := jsonpatch.DecodePatch(rawPatch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode patches: %v", err)
+	}
+	modified, err := decoded.Apply(original)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply patches: %v", err)
+	}
+	patched := &corev1.Pod{}
+	if err := json.Unmarshal(modified, patched); err != nil {
+		return nil, err
+	}
+	return patched, nil
+}
+
+// Diff returns a human-readable description of the changes to container
+// resources and annotations between the original and the patched Pod.
+func Diff(original, patched *corev1.Pod) string {
+	var sb strings.Builder
+	for i, container := range patched.Spec.Containers {
+		var before corev1.ResourceRequirements
+		if i < len(original.Spec.Containers) {
+			before = original.Spec.Containers[i].Resources
+		}
+		lines := append(resourceListDiff("requests", before.Requests, container.Resources.Requests),
+			resourceListDiff("limits", before.Limits, container.Resources.Limits)...)
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "container %q:\n", container.Name)
+		for _, line := range lines {
+			fmt.Fprintf(&sb, "  %s\n", line)
+		}
+	}
+	if lines := annotationsDiff(original.Annotations, patched.Annotations); len(lines) > 0 {
+		sb.WriteString("annotations:\n")
+		for _, line := range lines {
+			fmt.Fprintf(&sb, "  %s\n", line)
+		}
+	}
+	if sb.Len() == 0 {
+		return "No changes.\n"
+	}
+	return sb.String()
+}
+
+func resourceListDiff(kind string, before, after corev1.ResourceList) []string {
+	names := map[corev1.ResourceName]bool{}
+	for name := range before {
+		names[name] = true
+	}
+	for name := range after {
+		names[name] = true
+	}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, string(name))
+	}
+	sort.Strings(sorted)
+
+	var lines []string
+	for _, name := range sorted {
+		oldValue, hadOld := before[corev1.ResourceName(name)]
+		newValue, hasNew := after[corev1.ResourceName(name)]
+		if hadOld == hasNew && oldValue.Cmp(newValue) == 0 {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s.%s: %s -> %s", kind, name, quantityString(oldValue, hadOld), quantityString(newValue, hasNew)))
+	}
+	return lines
+}
+
+func quantityString(q resource.Quantity, found bool) string {
+	if !found {
+		return unset
+	}
+	return q.String()
+}
+
+func annotationsDiff(before, after map[string]string) []string {
+	keys := make([]string, 0, len(after))
+	for key, value := range after {
+		if oldValue, found := before[key]; !found || oldValue != value {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	lines := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if oldValue, found := before[key]; found {
+			lines = append(lines, fmt.Sprintf("~ %s: %q -> %q", key, oldValue, after[key]))
+		} else {
+			lines = append(lines, fmt.Sprintf("+ %s: %q", key, after[key]))
+		}
+	}
+	return lines
+}
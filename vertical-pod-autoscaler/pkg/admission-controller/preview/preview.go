@@ -0,0 +1,252 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package preview implements an HTTP endpoint showing the changes the
+// admission controller would make to a Pod, without admitting it.
+package preview
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	resource_admission "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/patch"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/vpa"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_lister "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/listers/autoscaling.k8s.io/v1"
+	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
+)
+
+const (
+	// Path is the path under which the preview endpoint is served.
+	Path = "/recommendation-preview"
+	// maxPreviewPayloadSize limits the size of the preview request payload.
+	maxPreviewPayloadSize = 1024 * 1024 * 5 // 5MB
+)
+
+// Request is the body of a preview request. Exactly one of Pod and
+// PodTemplate has to be set.
+type Request struct {
+	// Namespace of the Pod. Defaults to the namespace of the Pod or pod template.
+	Namespace string `json:"namespace,omitempty"`
+	// VPAName is the name of the VPA to preview. If empty, the VPA is matched
+	// the same way the admission controller matches it for new Pods.
+	VPAName string `json:"vpaName,omitempty"`
+	// Pod to preview the changes for.
+	Pod *corev1.Pod `json:"pod,omitempty"`
+	// PodTemplate to preview the changes for.
+	PodTemplate *corev1.PodTemplateSpec `json:"podTemplate,omitempty"`
+}
+
+// Response is the body of a preview response.
+type Response struct {
+	// VPAName is the name of the VPA the recommendation comes from, empty if
+	// no VPA matches the Pod.
+	VPAName string `json:"vpaName,omitempty"`
+	// UpdateMode is the update mode of the VPA.
+	UpdateMode vpa_types.UpdateMode `json:"updateMode,omitempty"`
+	// Patches are the JSON patches the admission controller would apply.
+	Patches []resource_admission.PatchRecord `json:"patches"`
+	// Diff is a human-readable summary of the patches.
+	Diff string `json:"diff"`
+}
+
+// Handler serves recommendation previews.
+type Handler struct {
+	authorizer       Authorizer
+	preProcessor     pod.PreProcessor
+	vpaMatcher       vpa.Matcher
+	vpaLister        vpa_lister.VerticalPodAutoscalerLister
+	patchCalculators []patch.Calculator
+}
+
+// NewHandler returns a new preview Handler using the same patch calculators as
+// the admission server. Callers are checked with the authorizer before their
+// request is previewed.
+func NewHandler(authorizer Authorizer, preProcessor pod.PreProcessor, vpaMatcher vpa.Matcher, vpaLister vpa_lister.VerticalPodAutoscalerLister, patchCalculators []patch.Calculator) *Handler {
+	return &Handler{
+		authorizer:       authorizer,
+		preProcessor:     preProcessor,
+		vpaMatcher:       vpaMatcher,
+		vpaLister:        vpaLister,
+		patchCalculators: patchCalculators,
+	}
+}
+
+// ServeHTTP handles a preview request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxPreviewPayloadSize+1))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxPreviewPayloadSize {
+		http.Error(w, "request too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	req := Request{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, fmt.Sprintf("failed to parse request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := h.authorizer.Authorize(r.Context(), credentialsFromRequest(r), requestNamespace(&req)); err != nil {
+		status := http.StatusInternalServerError
+		var unauthenticated *unauthenticatedError
+		var forbidden *forbiddenError
+		switch {
+		case errors.As(err, &unauthenticated):
+			status = http.StatusUnauthorized
+		case errors.As(err, &forbidden):
+			status = http.StatusForbidden
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+
+	resp, err := h.Preview(r.Context(), &req)
+	if err != nil {
+		status := http.StatusInternalServerError
+		var badRequest *badRequestError
+		if errors.As(err, &badRequest) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		klog.ErrorS(err, "Failed to write recommendation preview response")
+	}
+}
+
+type badRequestError struct {
+	msg string
+}
+
+func (e *badRequestError) Error() string {
+	return e.msg
+}
+
+// Preview computes the patches the admission controller would apply to the
+// Pod in the request.
+func (h *Handler) Preview(ctx context.Context, req *Request) (*Response, error) {
+	p, err := podFromRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	controllingVpa, err := h.getVpa(ctx, req.VPAName, p)
+	if err != nil {
+		return nil, err
+	}
+	resp := &Response{Patches: []resource_admission.PatchRecord{}}
+	if controllingVpa == nil {
+		resp.Diff = "No VPA matches the pod, it would be admitted unchanged.\n"
+		return resp, nil
+	}
+	resp.VPAName = controllingVpa.Name
+	resp.UpdateMode = vpa_api_util.GetUpdateMode(controllingVpa)
+	if resp.UpdateMode == vpa_types.UpdateModeOff {
+		// Preview what would change once the VPA is enabled. All other
+		// modes are handled the same way on admission.
+		controllingVpa = controllingVpa.DeepCopy()
+		mode := vpa_types.UpdateModeInitial
+		controllingVpa.Spec.UpdatePolicy = &vpa_types.PodUpdatePolicy{UpdateMode: &mode}
+	}
+
+	processed, err := h.preProcessor.Process(*p)
+	if err != nil {
+		return nil, err
+	}
+	if processed.Annotations == nil {
+		resp.Patches = append(resp.Patches, patch.GetAddEmptyAnnotationsPatch())
+	}
+	for _, c := range h.patchCalculators {
+		partialPatches, err := c.CalculatePatches(&processed, controllingVpa)
+		if err != nil {
+			return nil, err
+		}
+		resp.Patches = append(resp.Patches, partialPatches...)
+	}
+
+	patched, err := applyPatches(&processed, resp.Patches)
+	if err != nil {
+		return nil, err
+	}
+	resp.Diff = Diff(&processed, patched)
+	return resp, nil
+}
+
+// requestNamespace returns the namespace the Pod in the request is previewed in.
+func requestNamespace(req *Request) string {
+	switch {
+	case req.Namespace != "":
+		return req.Namespace
+	case req.Pod != nil:
+		return req.Pod.Namespace
+	case req.PodTemplate != nil:
+		return req.PodTemplate.Namespace
+	}
+	return ""
+}
+
+func podFromRequest(req *Request) (*corev1.Pod, error) {
+	var p *corev1.Pod
+	switch {
+	case req.Pod != nil && req.PodTemplate != nil:
+		return nil, &badRequestError{"only one of pod and podTemplate can be set"}
+	case req.Pod != nil:
+		p = req.Pod.DeepCopy()
+	case req.PodTemplate != nil:
+		p = &corev1.Pod{
+			ObjectMeta: *req.PodTemplate.ObjectMeta.DeepCopy(),
+			Spec:       *req.PodTemplate.Spec.DeepCopy(),
+		}
+	default:
+		return nil, &badRequestError{"one of pod and podTemplate is required"}
+	}
+	p.Namespace = requestNamespace(req)
+	if p.Namespace == "" {
+		return nil, &badRequestError{"namespace is required"}
+	}
+	if len(p.Name) == 0 {
+		p.Name = p.GenerateName + "%"
+	}
+	return p, nil
+}
+
+func (h *Handler) getVpa(ctx context.Context, vpaName string, p *corev1.Pod) (*vpa_types.VerticalPodAutoscaler, error) {
+	if vpaName == "" {
+		return h.vpaMatcher.GetMatchingVPA(ctx, p), nil
+	}
+	controllingVpa, err := h.vpaLister.VerticalPodAutoscalers(p.Namespace).Get(vpaName)
+	if apierrors.IsNotFound(err) {
+		return nil, &badRequestError{fmt.Sprintf("VPA %s/%s not found", p.Namespace, vpaName)}
+	}
+	return controllingVpa, err
+}
@@ -0,0 +1,195 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package preview
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/patch"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/recommendation"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_lister "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/listers/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/limitrange"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/test"
+	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
+)
+
+type fakeVpaMatcher struct {
+	vpa *vpa_types.VerticalPodAutoscaler
+}
+
+func (m *fakeVpaMatcher) GetMatchingVPA(_ context.Context, _ *corev1.Pod) *vpa_types.VerticalPodAutoscaler {
+	return m.vpa
+}
+
+type fakeAuthorizer struct {
+	err error
+}
+
+func (a *fakeAuthorizer) Authorize(_ context.Context, _ Credentials, _ string) error {
+	return a.err
+}
+
+func newTestHandler(t *testing.T, authErr error, matchingVpa *vpa_types.VerticalPodAutoscaler, vpas ...*vpa_types.VerticalPodAutoscaler) *Handler {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, vpa := range vpas {
+		require.NoError(t, indexer.Add(vpa))
+	}
+	limitRangeCalculator := limitrange.NewNoopLimitsCalculator()
+	recommendationProvider := recommendation.NewProvider(limitRangeCalculator, vpa_api_util.NewCappingRecommendationProcessor(limitRangeCalculator))
+	calculators := []patch.Calculator{
+		patch.NewResourceUpdatesCalculator(recommendationProvider, resource.QuantityValue{}, resource.QuantityValue{}),
+		patch.NewObservedContainersCalculator(),
+	}
+	return NewHandler(&fakeAuthorizer{err: authErr}, pod.NewDefaultPreProcessor(), &fakeVpaMatcher{vpa: matchingVpa}, vpa_lister.NewVerticalPodAutoscalerLister(indexer), calculators)
+}
+
+func TestServeHTTP(t *testing.T) {
+	container := test.Container().WithName("app").
+		WithCPURequest(resource.MustParse("100m")).
+		WithCPULimit(resource.MustParse("200m")).Get()
+	testPod := test.Pod().WithName("pod").AddContainer(container).Get()
+	template := &corev1.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Labels: map[string]string{"app": "app"}},
+		Spec:       testPod.Spec,
+	}
+	vpaOff := test.VerticalPodAutoscaler().WithName("vpa").WithNamespace("default").WithContainer("app").
+		WithUpdateMode(vpa_types.UpdateModeOff).WithTargetResource(corev1.ResourceCPU, "250m").Get()
+	expectedDiff := "container \"app\":\n" +
+		"  requests.cpu: 100m -> 250m\n" +
+		"  limits.cpu: 200m -> 500m\n" +
+		"annotations:\n" +
+		"  + vpaObservedContainers: \"app\"\n" +
+		"  + vpaUpdates: \"Pod resources updated by vpa: container 0: memory: limit NOT set since originalLimit is nil or 0, cpu request, cpu limit\"\n"
+
+	testCases := []struct {
+		name           string
+		method         string
+		request        Request
+		authErr        error
+		matchingVpa    *vpa_types.VerticalPodAutoscaler
+		vpas           []*vpa_types.VerticalPodAutoscaler
+		expectedStatus int
+		expectedVpa    string
+		expectedMode   vpa_types.UpdateMode
+		expectedDiff   string
+	}{
+		{
+			name:           "pod matching VPA in Off mode",
+			method:         http.MethodPost,
+			request:        Request{Pod: testPod},
+			matchingVpa:    vpaOff,
+			expectedStatus: http.StatusOK,
+			expectedVpa:    "vpa",
+			expectedMode:   vpa_types.UpdateModeOff,
+			expectedDiff:   expectedDiff,
+		},
+		{
+			name:           "pod template with VPA name",
+			method:         http.MethodPost,
+			request:        Request{PodTemplate: template, VPAName: "vpa"},
+			vpas:           []*vpa_types.VerticalPodAutoscaler{vpaOff},
+			expectedStatus: http.StatusOK,
+			expectedVpa:    "vpa",
+			expectedMode:   vpa_types.UpdateModeOff,
+			expectedDiff:   expectedDiff,
+		},
+		{
+			name:           "no matching VPA",
+			method:         http.MethodPost,
+			request:        Request{Pod: testPod},
+			expectedStatus: http.StatusOK,
+			expectedDiff:   "No VPA matches the pod, it would be admitted unchanged.\n",
+		},
+		{
+			name:           "VPA not found",
+			method:         http.MethodPost,
+			request:        Request{Pod: testPod, VPAName: "missing"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "neither pod nor template",
+			method:         http.MethodPost,
+			request:        Request{Namespace: "default"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "unauthenticated",
+			method:         http.MethodPost,
+			request:        Request{Pod: testPod},
+			authErr:        &unauthenticatedError{"a bearer token is required"},
+			matchingVpa:    vpaOff,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "forbidden",
+			method:         http.MethodPost,
+			request:        Request{Pod: testPod},
+			authErr:        &forbiddenError{"user cannot get verticalpodautoscalers"},
+			matchingVpa:    vpaOff,
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "GET request",
+			method:         http.MethodGet,
+			request:        Request{Pod: testPod},
+			expectedStatus: http.StatusMethodNotAllowed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newTestHandler(t, tc.authErr, tc.matchingVpa, tc.vpas...)
+			body, err := json.Marshal(tc.request)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			h.ServeHTTP(recorder, httptest.NewRequest(tc.method, Path, bytes.NewReader(body)))
+
+			assert.Equal(t, tc.expectedStatus, recorder.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			resp := Response{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+			assert.Equal(t, tc.expectedVpa, resp.VPAName)
+			assert.Equal(t, tc.expectedMode, resp.UpdateMode)
+			assert.Equal(t, tc.expectedDiff, resp.Diff)
+			if tc.expectedVpa != "" {
+				assert.NotEmpty(t, resp.Patches)
+			}
+		})
+	}
+}
+
+func TestDiffNoChanges(t *testing.T) {
+	container := test.Container().WithName("app").WithCPURequest(resource.MustParse("1")).Get()
+	p := test.Pod().WithName("pod").AddContainer(container).Get()
+	assert.Equal(t, "No changes.\n", Diff(p, p.DeepCopy()))
+}
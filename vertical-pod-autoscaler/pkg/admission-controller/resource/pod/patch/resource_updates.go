@@ -19,6 +19,7 @@ package patch
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
@@ -42,14 +43,16 @@ const (
 type resourcesUpdatesPatchCalculator struct {
 	recommendationProvider recommendation.Provider
 	maxAllowedCPUBoost     resource.Quantity
+	maxAllowedMemoryBoost  resource.Quantity
 }
 
 // NewResourceUpdatesCalculator returns a calculator for
 // resource update patches.
-func NewResourceUpdatesCalculator(recommendationProvider recommendation.Provider, maxAllowedCPUBoost resource.QuantityValue) Calculator {
+func NewResourceUpdatesCalculator(recommendationProvider recommendation.Provider, maxAllowedCPUBoost, maxAllowedMemoryBoost resource.QuantityValue) Calculator {
 	return &resourcesUpdatesPatchCalculator{
 		recommendationProvider: recommendationProvider,
 		maxAllowedCPUBoost:     maxAllowedCPUBoost.Quantity,
+		maxAllowedMemoryBoost:  maxAllowedMemoryBoost.Quantity,
 	}
 }
 
@@ -80,16 +83,22 @@ func (c *resourcesUpdatesPatchCalculator) CalculatePatches(pod *corev1.Pod, vpa
 	}
 
 	updatesAnnotation := []string{}
-	cpuStartupBoostEnabled := features.Enabled(features.CPUStartupBoost)
+	var boostedResources []corev1.ResourceName
+	if features.Enabled(features.CPUStartupBoost) {
+		boostedResources = append(boostedResources, corev1.ResourceCPU)
+	}
+	if features.Enabled(features.MemoryStartupBoost) {
+		boostedResources = append(boostedResources, corev1.ResourceMemory)
+	}
 	for i := range containersResources {
 		// Apply startup boost if configured
-		if cpuStartupBoostEnabled {
+		if len(boostedResources) > 0 {
 			// Get the container resource policy to check for scaling mode.
 			policy := vpa_api_util.GetContainerResourcePolicy(pod.Spec.Containers[i].Name, vpa.Spec.ResourcePolicy)
 			if policy != nil && policy.Mode != nil && *policy.Mode == vpa_types.ContainerScalingModeOff {
 				continue
 			}
-			boostPatches, err := c.applyCPUStartupBoost(&pod.Spec.Containers[i], vpa, &containersResources[i])
+			boostPatches, err := c.applyStartupBoost(&pod.Spec.Containers[i], vpa, &containersResources[i], boostedResources)
 			if err != nil {
 				return nil, err
 			}
@@ -142,7 +151,7 @@ func appendPatchesAndAnnotations(patches []resource_admission.PatchRecord, annot
 	return patches, annotations
 }
 
-func (c *resourcesUpdatesPatchCalculator) applyCPUStartupBoost(container *corev1.Container, vpa *vpa_types.VerticalPodAutoscaler, containerResources *vpa_api_util.ContainerResources) ([]resource_admission.PatchRecord, error) {
+func (c *resourcesUpdatesPatchCalculator) applyStartupBoost(container *corev1.Container, vpa *vpa_types.VerticalPodAutoscaler, containerResources *vpa_api_util.ContainerResources, boostedResources []corev1.ResourceName) ([]resource_admission.PatchRecord, error) {
 	var patches []resource_admission.PatchRecord
 
 	startupBoostPolicy := getContainerStartupBoostPolicy(container, vpa)
@@ -150,17 +159,29 @@ func (c *resourcesUpdatesPatchCalculator) applyCPUStartupBoost(container *corev1
 		return nil, nil
 	}
 
-	err := c.applyControlledCPUResources(container, vpa, containerResources, startupBoostPolicy)
-	if err != nil {
-		return nil, err
-	}
+	for _, resourceName := range boostedResources {
+		startupBoost := vpa_api_util.GetStartupBoostForResource(startupBoostPolicy, resourceName)
+		if startupBoost == nil {
+			continue
+		}
+		boosted, err := c.applyControlledResources(container, vpa, containerResources, resourceName, startupBoost)
+		if err != nil {
+			return nil, err
+		}
+		if !boosted {
+			continue
+		}
 
-	originalResources, err := annotations.GetOriginalResourcesAnnotationValue(container)
-	if err != nil {
-		return nil, err
+		originalResources, err := annotations.GetOriginalResourcesAnnotationValue(container)
+		if err != nil {
+			return nil, err
+		}
+		annotationKey := annotations.GetStartupCPUBoostAnnotationKey(container.Name)
+		if resourceName == corev1.ResourceMemory {
+			annotationKey = annotations.GetStartupMemoryBoostAnnotationKey(container.Name)
+		}
+		patches = append(patches, GetAddAnnotationPatch(annotationKey, originalResources))
 	}
-	annotationKey := annotations.GetStartupCPUBoostAnnotationKey(container.Name)
-	patches = append(patches, GetAddAnnotationPatch(annotationKey, originalResources))
 
 	return patches, nil
 }
@@ -174,108 +195,124 @@ func getContainerStartupBoostPolicy(container *corev1.Container, vpa *vpa_types.
 	return startupBoost
 }
 
-func (*resourcesUpdatesPatchCalculator) calculateBoostedCPUValue(baseCPU resource.Quantity, startupBoost *vpa_types.StartupBoost) (*resource.Quantity, error) {
-	boostType := startupBoost.CPU.Type
+func calculateBoostedValue(base resource.Quantity, startupBoost *vpa_types.GenericStartupBoost, resourceName corev1.ResourceName) (*resource.Quantity, error) {
+	boostType := startupBoost.Type
 	if boostType == "" {
 		boostType = vpa_types.FactorStartupBoostType
 	}
-
 	switch boostType {
 	case vpa_types.FactorStartupBoostType:
-		if startupBoost.CPU.Factor == nil {
-			return nil, errors.New("startupBoost.CPU.Factor is required when Type is Factor or not specified")
+		if startupBoost.Factor == nil {
+			return nil, fmt.Errorf("startupBoost.%s.Factor is required when Type is Factor or not specified", resourceName)
 		}
-		factor := *startupBoost.CPU.Factor
+		factor := *startupBoost.Factor
 		if factor < 1 {
 			return nil, errors.New("boost factor must be >= 1")
 		}
-		boostedCPUMilli := baseCPU.MilliValue()
-		boostedCPUMilli = int64(float64(boostedCPUMilli) * float64(factor))
-		return resource.NewMilliQuantity(boostedCPUMilli, resource.DecimalSI), nil
+		boostedMilli := int64(float64(base.MilliValue()) * float64(factor))
+		return newBoostedQuantity(boostedMilli, resourceName), nil
 	case vpa_types.QuantityStartupBoostType:
-		if startupBoost.CPU.Quantity == nil {
-			return nil, errors.New("startupBoost.CPU.Quantity is required when Type is Quantity")
+		if startupBoost.Quantity == nil {
+			return nil, fmt.Errorf("startupBoost.%s.Quantity is required when Type is Quantity", resourceName)
 		}
-		quantity := *startupBoost.CPU.Quantity
-		boostedCPUMilli := baseCPU.MilliValue() + quantity.MilliValue()
-		return resource.NewMilliQuantity(boostedCPUMilli, resource.DecimalSI), nil
+		boostedMilli := base.MilliValue() + startupBoost.Quantity.MilliValue()
+		return newBoostedQuantity(boostedMilli, resourceName), nil
 	default:
-		return nil, fmt.Errorf("unsupported startup boost type: %s", startupBoost.CPU.Type)
+		return nil, fmt.Errorf("unsupported startup boost type: %s", startupBoost.Type)
 	}
 }
 
-func (c *resourcesUpdatesPatchCalculator) calculateBoostedCPU(recommendedCPU, originalCPU resource.Quantity, startupBoost *vpa_types.StartupBoost) (*resource.Quantity, error) {
-	baseCPU := recommendedCPU
-	if baseCPU.IsZero() {
-		baseCPU = originalCPU
+// newBoostedQuantity returns the boosted quantity of the resource, rounding
+// memory up to whole bytes.
+func newBoostedQuantity(boostedMilli int64, resourceName corev1.ResourceName) *resource.Quantity {
+	if resourceName == corev1.ResourceMemory {
+		return resource.NewQuantity(int64(math.Ceil(float64(boostedMilli)/1000)), resource.BinarySI)
 	}
+	return resource.NewMilliQuantity(boostedMilli, resource.DecimalSI)
+}
 
-	if startupBoost == nil {
-		return &baseCPU, nil
+func (c *resourcesUpdatesPatchCalculator) calculateBoosted(recommended, original resource.Quantity, startupBoost *vpa_types.GenericStartupBoost, resourceName corev1.ResourceName) (*resource.Quantity, error) {
+	base := recommended
+	if base.IsZero() {
+		base = original
 	}
 
-	boostedCPU, err := c.calculateBoostedCPUValue(baseCPU, startupBoost)
+	boosted, err := calculateBoostedValue(base, startupBoost, resourceName)
 	if err != nil {
 		return nil, err
 	}
 
-	if !c.maxAllowedCPUBoost.IsZero() && boostedCPU.Cmp(c.maxAllowedCPUBoost) > 0 {
-		return &c.maxAllowedCPUBoost, nil
+	maxAllowedBoost := c.maxAllowedCPUBoost
+	if resourceName == corev1.ResourceMemory {
+		maxAllowedBoost = c.maxAllowedMemoryBoost
+	}
+	if !maxAllowedBoost.IsZero() && boosted.Cmp(maxAllowedBoost) > 0 {
+		return &maxAllowedBoost, nil
 	}
-	return boostedCPU, nil
+	return boosted, nil
 }
 
-// capStartupBoostToContainerLimit makes sure startup boost recommendation is not above current limit for the container for CPU.
-// It attempts to keep the request 1m below the limit to maintain QoS.
-func capStartupBoostToContainerLimit(recommendation corev1.ResourceList, containerLimits corev1.ResourceList) {
-	limit, found := containerLimits[corev1.ResourceCPU]
+// capStartupBoostToContainerLimit makes sure startup boost recommendation is not above current limit for the container.
+// It attempts to keep the request one unit (1m of CPU, 1 byte of memory) below the limit to maintain QoS, as the
+// QoS class of the pod can't be changed when it is unboosted in-place.
+func capStartupBoostToContainerLimit(recommendation corev1.ResourceList, containerLimits corev1.ResourceList, resourceName corev1.ResourceName) {
+	limit, found := containerLimits[resourceName]
 	if !found {
 		return
 	}
 
-	recommendedValue, found := recommendation[corev1.ResourceCPU]
+	unit := resource.MustParse("1m")
+	if resourceName == corev1.ResourceMemory {
+		unit = resource.MustParse("1")
+	}
+	recommendedValue, found := recommendation[resourceName]
 	if found && recommendedValue.MilliValue() > limit.MilliValue() {
 		newRecommended := limit.DeepCopy()
-		if limit.Cmp(resource.MustParse("1m")) > 0 {
-			newRecommended.Sub(resource.MustParse("1m"))
+		if limit.Cmp(unit) > 0 {
+			newRecommended.Sub(unit)
 		}
-		recommendation[corev1.ResourceCPU] = newRecommended
+		recommendation[resourceName] = newRecommended
 	}
 }
 
-func (c *resourcesUpdatesPatchCalculator) applyControlledCPUResources(container *corev1.Container, vpa *vpa_types.VerticalPodAutoscaler, containerResources *vpa_api_util.ContainerResources, startupBoostPolicy *vpa_types.StartupBoost) error {
+// applyControlledResources boosts the given resource of the container. Returns false if there is neither a recommendation
+// nor an original request of the resource to boost, except for CPU which is always boosted.
+func (c *resourcesUpdatesPatchCalculator) applyControlledResources(container *corev1.Container, vpa *vpa_types.VerticalPodAutoscaler, containerResources *vpa_api_util.ContainerResources, resourceName corev1.ResourceName, startupBoost *vpa_types.GenericStartupBoost) (bool, error) {
 	controlledValues := vpa_api_util.GetContainerControlledValues(container.Name, vpa.Spec.ResourcePolicy)
 
-	recommendedRequest := containerResources.Requests[corev1.ResourceCPU]
-	originalRequest := container.Resources.Requests[corev1.ResourceCPU]
-	boostedRequest, err := c.calculateBoostedCPU(recommendedRequest, originalRequest, startupBoostPolicy)
+	recommendedRequest := containerResources.Requests[resourceName]
+	originalRequest := container.Resources.Requests[resourceName]
+	if resourceName == corev1.ResourceMemory && recommendedRequest.IsZero() && originalRequest.IsZero() {
+		return false, nil
+	}
+	boostedRequest, err := c.calculateBoosted(recommendedRequest, originalRequest, startupBoost, resourceName)
 	if err != nil {
-		return err
+		return false, err
 	}
 
 	if containerResources.Requests == nil {
 		containerResources.Requests = corev1.ResourceList{}
 	}
-	containerResources.Requests[corev1.ResourceCPU] = *boostedRequest
+	containerResources.Requests[resourceName] = *boostedRequest
 
 	switch controlledValues {
 	case vpa_types.ContainerControlledValuesRequestsOnly:
-		capStartupBoostToContainerLimit(containerResources.Requests, container.Resources.Limits)
+		capStartupBoostToContainerLimit(containerResources.Requests, container.Resources.Limits, resourceName)
 	case vpa_types.ContainerControlledValuesRequestsAndLimits:
 		if containerResources.Limits == nil {
 			containerResources.Limits = corev1.ResourceList{}
 		}
 		newLimits, _ := vpa_api_util.GetProportionalLimit(
-			container.Resources.Limits,                               // originalLimits
-			container.Resources.Requests,                             // originalRequests
-			corev1.ResourceList{corev1.ResourceCPU: *boostedRequest}, // newRequests
-			corev1.ResourceList{},                                    // defaultLimit
+			container.Resources.Limits,                         // originalLimits
+			container.Resources.Requests,                       // originalRequests
+			corev1.ResourceList{resourceName: *boostedRequest}, // newRequests
+			corev1.ResourceList{},                              // defaultLimit
 		)
-		if newLimit, ok := newLimits[corev1.ResourceCPU]; ok {
-			containerResources.Limits[corev1.ResourceCPU] = newLimit
+		if newLimit, ok := newLimits[resourceName]; ok {
+			containerResources.Limits[resourceName] = newLimit
 		}
 	default:
 		// Do nothing
 	}
-	return nil
+	return true, nil
 }
@@ -37,6 +37,7 @@ import (
 
 const (
 	cpu        = "cpu"
+	memory     = "memory"
 	unobtanium = "unobtanium"
 	limit      = "limit"
 	request    = "request"
@@ -308,7 +309,7 @@ func TestCalculatePatches_ResourceUpdates(t *testing.T) {
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			frp := fakeRecommendationProvider{tc.recommendResources, tc.recommendAnnotations, tc.recommendError}
-			c := NewResourceUpdatesCalculator(&frp, resource.QuantityValue{})
+			c := NewResourceUpdatesCalculator(&frp, resource.QuantityValue{}, resource.QuantityValue{})
 			patches, err := c.CalculatePatches(tc.pod, test.VerticalPodAutoscaler().WithContainer("test").WithName("name").Get())
 			if tc.expectError == nil {
 				assert.NoError(t, err)
@@ -350,7 +351,7 @@ func TestGetPatches_TwoReplacementResources(t *testing.T) {
 	}
 	recommendAnnotations := vpa_api_util.ContainerToAnnotationsMap{}
 	frp := fakeRecommendationProvider{recommendResources, recommendAnnotations, nil}
-	c := NewResourceUpdatesCalculator(&frp, resource.QuantityValue{})
+	c := NewResourceUpdatesCalculator(&frp, resource.QuantityValue{}, resource.QuantityValue{})
 	patches, err := c.CalculatePatches(pod, test.VerticalPodAutoscaler().WithName("name").WithContainer("test").Get())
 	assert.NoError(t, err)
 	// Order of updates for cpu and unobtanium depends on order of iterating a map, both possible results are valid.
@@ -372,6 +373,7 @@ func TestCalculatePatches_StartupBoost(t *testing.T) {
 	quantity := resource.MustParse("500m")
 	invalidFactor := int32(0)
 	invalidQuantity := resource.MustParse("200m")
+	memoryQuantity := resource.MustParse("512Mi")
 	tests := []struct {
 		name                 string
 		pod                  *corev1.Pod
@@ -380,9 +382,11 @@ func TestCalculatePatches_StartupBoost(t *testing.T) {
 		recommendAnnotations vpa_api_util.ContainerToAnnotationsMap
 		recommendError       error
 		maxAllowedCpu        resource.QuantityValue
+		maxAllowedMemory     resource.QuantityValue
 		expectPatches        []resource_admission.PatchRecord
 		expectError          error
 		featureGateEnabled   bool
+		memoryGateEnabled    bool
 	}{
 		{
 			name: "startup boost factor",
@@ -903,13 +907,212 @@ func TestCalculatePatches_StartupBoost(t *testing.T) {
 				GetAddAnnotationPatch(ResourceUpdatesAnnotation, "Pod resources updated by name: container 0: cpu request, cpu limit"),
 			},
 		},
+		{
+			name: "memory startup boost factor",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "container1",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									memory: resource.MustParse("100Mi"),
+								},
+								Limits: corev1.ResourceList{
+									memory: resource.MustParse("200Mi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			vpa: test.VerticalPodAutoscaler().WithName("name").WithContainer("container1").WithMemoryStartupBoost(vpa_types.FactorStartupBoostType, &factor2, nil, 10).Get(),
+			recommendResources: []vpa_api_util.ContainerResources{
+				{
+					Requests: corev1.ResourceList{
+						memory: resource.MustParse("200Mi"),
+					},
+					Limits: corev1.ResourceList{
+						memory: resource.MustParse("400Mi"),
+					},
+				},
+			},
+			memoryGateEnabled: true,
+			expectPatches: []resource_admission.PatchRecord{
+				GetAddAnnotationPatch(annotations.GetStartupMemoryBoostAnnotationKey("container1"), "{\"requests\":{\"memory\":\"100Mi\"},\"limits\":{\"memory\":\"200Mi\"}}"),
+				addResourceRequestPatch(0, memory, "400Mi"),
+				addResourceLimitPatch(0, memory, "800Mi"),
+				GetAddAnnotationPatch(ResourceUpdatesAnnotation, "Pod resources updated by name: container 0: memory request, memory limit"),
+			},
+		},
+		{
+			name: "memory startup boost factor rounded to whole bytes",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "container1",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									memory: resource.MustParse("100Mi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			vpa: test.VerticalPodAutoscaler().WithName("name").WithContainer("container1").WithMemoryStartupBoost(vpa_types.FactorStartupBoostType, &factor3, nil, 10).Get(),
+			recommendResources: []vpa_api_util.ContainerResources{
+				{
+					Requests: corev1.ResourceList{
+						memory: resource.MustParse("1000500m"),
+					},
+				},
+			},
+			memoryGateEnabled: true,
+			expectPatches: []resource_admission.PatchRecord{
+				GetAddAnnotationPatch(annotations.GetStartupMemoryBoostAnnotationKey("container1"), "{\"requests\":{\"memory\":\"100Mi\"},\"limits\":{}}"),
+				addResourceRequestPatch(0, memory, "3002"),
+				GetAddAnnotationPatch(ResourceUpdatesAnnotation, "Pod resources updated by name: container 0: memory request"),
+			},
+		},
+		{
+			name: "memory startup boost quantity with max allowed boost",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "container1",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									memory: resource.MustParse("100Mi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			vpa: test.VerticalPodAutoscaler().WithName("name").WithContainer("container1").WithMemoryStartupBoost(vpa_types.QuantityStartupBoostType, nil, &memoryQuantity, 10).Get(),
+			recommendResources: []vpa_api_util.ContainerResources{
+				{
+					Requests: corev1.ResourceList{
+						memory: resource.MustParse("200Mi"),
+					},
+				},
+			},
+			maxAllowedMemory:  resource.QuantityValue{Quantity: resource.MustParse("600Mi")},
+			memoryGateEnabled: true,
+			expectPatches: []resource_admission.PatchRecord{
+				GetAddAnnotationPatch(annotations.GetStartupMemoryBoostAnnotationKey("container1"), "{\"requests\":{\"memory\":\"100Mi\"},\"limits\":{}}"),
+				addResourceRequestPatch(0, memory, "600Mi"),
+				GetAddAnnotationPatch(ResourceUpdatesAnnotation, "Pod resources updated by name: container 0: memory request"),
+			},
+		},
+		{
+			name: "memory startup boost request capped below the limit",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "container1",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									memory: resource.MustParse("100Mi"),
+								},
+								Limits: corev1.ResourceList{
+									memory: resource.MustParse("150Mi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			vpa: test.VerticalPodAutoscaler().WithName("name").WithContainer("container1").
+				WithControlledValues("container1", vpa_types.ContainerControlledValuesRequestsOnly).
+				WithMemoryStartupBoost(vpa_types.FactorStartupBoostType, &factor2, nil, 10).Get(),
+			recommendResources: []vpa_api_util.ContainerResources{
+				{
+					Requests: corev1.ResourceList{
+						memory: resource.MustParse("100Mi"),
+					},
+				},
+			},
+			memoryGateEnabled: true,
+			expectPatches: []resource_admission.PatchRecord{
+				GetAddAnnotationPatch(annotations.GetStartupMemoryBoostAnnotationKey("container1"), "{\"requests\":{\"memory\":\"100Mi\"},\"limits\":{\"memory\":\"150Mi\"}}"),
+				addResourceRequestPatch(0, memory, "157286399"),
+				GetAddAnnotationPatch(ResourceUpdatesAnnotation, "Pod resources updated by name: container 0: memory request"),
+			},
+		},
+		{
+			name: "memory startup boost feature gate disabled",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "container1",
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									memory: resource.MustParse("100Mi"),
+								},
+							},
+						},
+					},
+				},
+			},
+			vpa: test.VerticalPodAutoscaler().WithName("name").WithContainer("container1").WithMemoryStartupBoost(vpa_types.FactorStartupBoostType, &factor2, nil, 10).Get(),
+			recommendResources: []vpa_api_util.ContainerResources{
+				{
+					Requests: corev1.ResourceList{
+						memory: resource.MustParse("200Mi"),
+					},
+				},
+			},
+			featureGateEnabled: true,
+			expectPatches: []resource_admission.PatchRecord{
+				addResourceRequestPatch(0, memory, "200Mi"),
+				GetAddAnnotationPatch(ResourceUpdatesAnnotation, "Pod resources updated by name: container 0: memory request"),
+			},
+		},
+		{
+			name: "memory startup boost without memory to boost",
+			pod: &corev1.Pod{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name: "container1",
+						},
+					},
+				},
+			},
+			vpa: test.VerticalPodAutoscaler().WithName("name").WithContainer("container1").
+				WithCPUStartupBoost(vpa_types.FactorStartupBoostType, &factor2, nil, 10).
+				WithMemoryStartupBoost(vpa_types.FactorStartupBoostType, &factor3, nil, 10).Get(),
+			recommendResources: []vpa_api_util.ContainerResources{
+				{
+					Requests: corev1.ResourceList{
+						cpu: resource.MustParse("100m"),
+					},
+				},
+			},
+			featureGateEnabled: true,
+			memoryGateEnabled:  true,
+			expectPatches: []resource_admission.PatchRecord{
+				GetAddAnnotationPatch(annotations.GetStartupCPUBoostAnnotationKey("container1"), "{\"requests\":{},\"limits\":{}}"),
+				GetPatchInitializingEmptyResources(0),
+				GetPatchInitializingEmptyResourcesSubfield(0, "requests"),
+				addResourceRequestPatch(0, cpu, "200m"),
+				GetAddAnnotationPatch(ResourceUpdatesAnnotation, "Pod resources updated by name: container 0: cpu request"),
+			},
+		},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
 			featuregatetesting.SetFeatureGateDuringTest(t, features.MutableFeatureGate, features.CPUStartupBoost, tc.featureGateEnabled)
+			featuregatetesting.SetFeatureGateDuringTest(t, features.MutableFeatureGate, features.MemoryStartupBoost, tc.memoryGateEnabled)
 
 			frp := fakeRecommendationProvider{tc.recommendResources, tc.recommendAnnotations, tc.recommendError}
-			c := NewResourceUpdatesCalculator(&frp, tc.maxAllowedCpu)
+			c := NewResourceUpdatesCalculator(&frp, tc.maxAllowedCpu, tc.maxAllowedMemory)
 			patches, err := c.CalculatePatches(tc.pod, tc.vpa)
 			if tc.expectError == nil {
 				assert.NoError(t, err)
@@ -23,6 +23,7 @@ import (
 	"k8s.io/klog/v2"
 
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/limitrange"
 	resourcehelpers "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/resources"
 	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
@@ -120,6 +121,21 @@ func (p *recommendationProvider) GetContainersResourcesForPod(pod *corev1.Pod, v
 	}
 	klog.V(2).InfoS("Updating requirements for pod", "pod", klog.KObj(pod))
 
+	if features.Enabled(features.CanaryRollout) {
+		rollback, err := vpa_api_util.GetCanaryRollback(vpa)
+		if err != nil {
+			klog.ErrorS(err, "Ignoring canary rollback of VPA", "vpa", klog.KObj(vpa))
+		} else if rollback != nil {
+			if previousResources, isCanary := rollback.ResourcesForPod(pod.Name); isCanary || rollback.Recommendation == nil {
+				klog.V(2).InfoS("Using resources from before the rolled back canary update", "pod", klog.KObj(pod), "vpa", klog.KObj(vpa))
+				return canaryRollbackResources(pod, previousResources), nil, nil
+			}
+			klog.V(2).InfoS("Using the last stable recommendation after the rolled back canary update", "pod", klog.KObj(pod), "vpa", klog.KObj(vpa))
+			vpa = vpa.DeepCopy()
+			vpa.Status.Recommendation = rollback.Recommendation
+		}
+	}
+
 	var annotations vpa_api_util.ContainerToAnnotationsMap
 	recommendedPodResources := &vpa_types.RecommendedPodResources{}
 
@@ -150,3 +166,17 @@ func (p *recommendationProvider) GetContainersResourcesForPod(pod *corev1.Pod, v
 
 	return containerResources, annotations, nil
 }
+
+// canaryRollbackResources returns the resources of the containers of the pod
+// from before a rolled back canary update. Containers without them are left
+// unchanged.
+func canaryRollbackResources(pod *corev1.Pod, previousResources map[string]corev1.ResourceRequirements) []vpa_api_util.ContainerResources {
+	resources := make([]vpa_api_util.ContainerResources, len(pod.Spec.Containers))
+	for i, container := range pod.Spec.Containers {
+		if previous, found := previousResources[container.Name]; found {
+			resources[i].Requests = previous.Requests
+			resources[i].Limits = previous.Limits
+		}
+	}
+	return resources
+}
@@ -24,8 +24,10 @@ import (
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	featuregatetesting "k8s.io/component-base/featuregate/testing"
 
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/limitrange"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/test"
 	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
@@ -572,3 +574,50 @@ func TestGetContainersResources(t *testing.T) {
 		})
 	}
 }
+
+func TestGetContainersResourcesForPodAfterCanaryRollback(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, features.MutableFeatureGate, features.CanaryRollout, true)
+	vpa := test.VerticalPodAutoscaler().WithName("vpa").WithContainer("app").WithTarget("2", "200Mi").Get()
+	vpa.Annotations = map[string]string{
+		vpa_api_util.CanaryRollbackAnnotation: `{"pods":{"web-0":{"app":{"requests":{"cpu":"500m"}}},"web-1":{"app":{"requests":{"cpu":"1"}}}}}`,
+	}
+	provider := &recommendationProvider{
+		recommendationProcessor: vpa_api_util.NewCappingRecommendationProcessor(limitrange.NewNoopLimitsCalculator()),
+		limitsRangeCalculator:   &fakeLimitRangeCalculator{},
+	}
+	pod := func(name string) *corev1.Pod {
+		return test.Pod().WithName(name).
+			AddContainer(test.Container().WithName("app").Get()).
+			AddContainer(test.Container().WithName("sidecar").Get()).Get()
+	}
+
+	for name, expectedCPU := range map[string]string{"web-1": "1", "web-0": "500m"} {
+		resources, _, err := provider.GetContainersResourcesForPod(pod(name), vpa)
+
+		assert.NoError(t, err)
+		if assert.Len(t, resources, 2) {
+			assert.Equal(t, resource.MustParse(expectedCPU), resources[0].Requests[corev1.ResourceCPU], "pod %s", name)
+			assert.Nil(t, resources[1].Requests, "containers without previous resources are unchanged")
+		}
+	}
+
+	// Other pods keep the resources of their spec without a stable
+	// recommendation.
+	resources, _, err := provider.GetContainersResourcesForPod(pod("web-abcde"), vpa)
+	assert.NoError(t, err)
+	if assert.Len(t, resources, 2) {
+		assert.Nil(t, resources[0].Requests)
+		assert.Nil(t, resources[1].Requests)
+	}
+
+	// With one, they get the stable recommendation rather than the current
+	// one.
+	vpa.Annotations[vpa_api_util.CanaryRollbackAnnotation] = `{"pods":{"web-0":{"app":{"requests":{"cpu":"500m"}}}},"recommendation":{"containerRecommendations":[{"containerName":"app","target":{"cpu":"750m"}}]}}`
+	resources, _, err = provider.GetContainersResourcesForPod(pod("web-abcde"), vpa)
+	assert.NoError(t, err)
+	if assert.Len(t, resources, 2) {
+		assert.Equal(t, resource.MustParse("750m"), resources[0].Requests[corev1.ResourceCPU])
+		assert.Nil(t, resources[1].Requests)
+	}
+	assert.Equal(t, resource.MustParse("2"), vpa.Status.Recommendation.ContainerRecommendations[0].Target[corev1.ResourceCPU], "the VPA isn't modified")
+}
@@ -18,6 +18,8 @@ package vpa
 
 import (
 	"fmt"
+	"slices"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apires "k8s.io/apimachinery/pkg/api/resource"
@@ -25,22 +27,31 @@ import (
 
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
+	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
 )
 
 // VPAValidationOptions contains the different settings for VPA validation
 type VPAValidationOptions struct {
-	IsVPACreate          bool
-	AllowCPUStartupBoost bool
-	AllowPerVPAConfig    bool
-	AllowInPlace         bool
+	IsVPACreate             bool
+	AllowCPUStartupBoost    bool
+	AllowMemoryStartupBoost bool
+	AllowPerVPAConfig       bool
+	AllowInPlace            bool
+	AllowHPACoordination    bool
+	AllowCanaryRollout      bool
+	AllowMaintenanceWindows bool
 }
 
 func getValidationOptionsForVPA(oldObj *vpa_types.VerticalPodAutoscaler) VPAValidationOptions {
 	opts := VPAValidationOptions{
-		IsVPACreate:          oldObj == nil,
-		AllowCPUStartupBoost: allowCPUBoost(oldObj),
-		AllowPerVPAConfig:    allowPerVPAConfig(oldObj),
-		AllowInPlace:         allowInPlace(oldObj),
+		IsVPACreate:             oldObj == nil,
+		AllowCPUStartupBoost:    allowCPUBoost(oldObj),
+		AllowMemoryStartupBoost: allowMemoryBoost(oldObj),
+		AllowPerVPAConfig:       allowPerVPAConfig(oldObj),
+		AllowInPlace:            allowInPlace(oldObj),
+		AllowHPACoordination:    allowHPACoordination(oldObj),
+		AllowCanaryRollout:      allowCanaryRollout(oldObj),
+		AllowMaintenanceWindows: allowMaintenanceWindows(oldObj),
 	}
 
 	return opts
@@ -70,6 +81,30 @@ func allowCPUBoost(oldObj *vpa_types.VerticalPodAutoscaler) bool {
 	return false
 }
 
+func allowMemoryBoost(oldObj *vpa_types.VerticalPodAutoscaler) bool {
+	if features.Enabled(features.MemoryStartupBoost) {
+		return true
+	}
+
+	if oldObj == nil {
+		return false
+	}
+
+	if oldObj.Spec.StartupBoost != nil && oldObj.Spec.StartupBoost.Memory != nil {
+		return true
+	}
+
+	if oldObj.Spec.ResourcePolicy != nil {
+		for _, policy := range oldObj.Spec.ResourcePolicy.ContainerPolicies {
+			if policy.StartupBoost != nil && policy.StartupBoost.Memory != nil {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func allowPerVPAConfig(oldObj *vpa_types.VerticalPodAutoscaler) bool {
 	if features.Enabled(features.PerVPAConfig) {
 		return true
@@ -87,6 +122,9 @@ func allowPerVPAConfig(oldObj *vpa_types.VerticalPodAutoscaler) bool {
 			if policy.OOMBumpUpRatio != nil || policy.OOMMinBumpUp != nil || policy.MemoryAggregationIntervalCount != nil || policy.MemoryAggregationIntervalSeconds != nil {
 				return true
 			}
+			if hasEstimationPolicy(&policy) {
+				return true
+			}
 		}
 	}
 
@@ -109,6 +147,42 @@ func allowInPlace(oldObj *vpa_types.VerticalPodAutoscaler) bool {
 	return false
 }
 
+func allowHPACoordination(oldObj *vpa_types.VerticalPodAutoscaler) bool {
+	if features.Enabled(features.HPACoordination) {
+		return true
+	}
+
+	if oldObj == nil {
+		return false
+	}
+
+	return oldObj.Spec.HPACoordination != nil
+}
+
+func allowCanaryRollout(oldObj *vpa_types.VerticalPodAutoscaler) bool {
+	if features.Enabled(features.CanaryRollout) {
+		return true
+	}
+
+	if oldObj == nil {
+		return false
+	}
+
+	return oldObj.Spec.UpdatePolicy != nil && oldObj.Spec.UpdatePolicy.CanaryRollout != nil
+}
+
+func allowMaintenanceWindows(oldObj *vpa_types.VerticalPodAutoscaler) bool {
+	if features.Enabled(features.MaintenanceWindows) {
+		return true
+	}
+
+	if oldObj == nil {
+		return false
+	}
+
+	return oldObj.Spec.UpdatePolicy != nil && len(oldObj.Spec.UpdatePolicy.MaintenanceWindows) > 0
+}
+
 func validateVPA(vpa *vpa_types.VerticalPodAutoscaler, opts VPAValidationOptions) field.ErrorList {
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateVPASpec(&vpa.Spec, field.NewPath("spec"), opts)...)
@@ -135,6 +209,10 @@ func validateVPASpec(spec *vpa_types.VerticalPodAutoscalerSpec, fldPath *field.P
 		allErrs = append(allErrs, validateVPASpecStartupBoost(spec.StartupBoost, fldPath.Child("startupBoost"), opts)...)
 	}
 
+	if spec.HPACoordination != nil {
+		allErrs = append(allErrs, validateVPASpecHPACoordination(spec.HPACoordination, fldPath.Child("hpaCoordination"), opts)...)
+	}
+
 	if len(spec.Recommenders) > 1 {
 		allErrs = append(allErrs, field.TooMany(fldPath.Child("recommenders"), len(spec.Recommenders), 1))
 	}
@@ -171,6 +249,65 @@ func validateVPASpecUpdatePolicy(updatePolicy *vpa_types.PodUpdatePolicy, fldPat
 		}
 	}
 
+	if updatePolicy.CanaryRollout != nil {
+		allErrs = append(allErrs, validateCanaryRollout(updatePolicy.CanaryRollout, fldPath.Child("canaryRollout"), opts)...)
+	}
+
+	if len(updatePolicy.MaintenanceWindows) > 0 {
+		allErrs = append(allErrs, validateMaintenanceWindows(updatePolicy.MaintenanceWindows, fldPath.Child("maintenanceWindows"), opts)...)
+	}
+
+	return allErrs
+}
+
+func validateCanaryRollout(canaryRollout *vpa_types.CanaryRollout, fldPath *field.Path, opts VPAValidationOptions) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !opts.AllowCanaryRollout {
+		return append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("in order to use canaryRollout, you must enable feature gate %s in the admission-controller args", features.CanaryRollout)))
+	}
+
+	if canaryRollout.Pods < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("pods"), canaryRollout.Pods, "must be greater than or equal to 1"))
+	}
+	if soakPeriodSeconds := canaryRollout.SoakPeriodSeconds; soakPeriodSeconds != nil && *soakPeriodSeconds < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("soakPeriodSeconds"), *soakPeriodSeconds, "must be greater than or equal to 1"))
+	}
+	return allErrs
+}
+
+func validateMaintenanceWindows(windows []vpa_types.MaintenanceWindow, fldPath *field.Path, opts VPAValidationOptions) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !opts.AllowMaintenanceWindows {
+		return append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("in order to use maintenanceWindows, you must enable feature gate %s in the admission-controller args", features.MaintenanceWindows)))
+	}
+
+	supportedDirections := []string{string(vpa_types.ScalingDirectionUp), string(vpa_types.ScalingDirectionDown)}
+	for i, window := range windows {
+		windowPath := fldPath.Index(i)
+		if window.TimeZone != nil {
+			if _, err := time.LoadLocation(*window.TimeZone); err != nil {
+				allErrs = append(allErrs, field.Invalid(windowPath.Child("timeZone"), *window.TimeZone, "unknown time zone"))
+			}
+		}
+		if window.Schedule == "" {
+			allErrs = append(allErrs, field.Required(windowPath.Child("schedule"), ""))
+		} else if _, err := vpa_api_util.ParseMaintenanceWindowSchedule(vpa_types.MaintenanceWindow{Schedule: window.Schedule}); err != nil {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("schedule"), window.Schedule, err.Error()))
+		}
+		if window.DurationSeconds < 1 {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("durationSeconds"), window.DurationSeconds, "must be greater than or equal to 1"))
+		}
+		if len(window.Directions) == 0 {
+			allErrs = append(allErrs, field.Required(windowPath.Child("directions"), ""))
+		}
+		for j, direction := range window.Directions {
+			if !slices.Contains(supportedDirections, string(direction)) {
+				allErrs = append(allErrs, field.NotSupported(windowPath.Child("directions").Index(j), direction, supportedDirections))
+			}
+		}
+	}
 	return allErrs
 }
 
@@ -252,6 +389,14 @@ func validateVPASpecResourcePolicy(resourcePolicy *vpa_types.PodResourcePolicy,
 			}
 		}
 
+		if hasEstimationPolicy(&policy) {
+			if opts.AllowPerVPAConfig {
+				allErrs = append(allErrs, validateEstimationPolicy(&policy, policyPath)...)
+			} else {
+				allErrs = append(allErrs, field.Forbidden(policyPath, fmt.Sprintf("percentiles, safetyMarginFraction and cpuEstimator are not supported when feature flag %s is disabled", features.PerVPAConfig)))
+			}
+		}
+
 		if policy.StartupBoost != nil {
 			allErrs = append(allErrs, validateVPASpecStartupBoost(policy.StartupBoost, policyPath.Child("startupBoost"), opts)...)
 		}
@@ -260,39 +405,125 @@ func validateVPASpecResourcePolicy(resourcePolicy *vpa_types.PodResourcePolicy,
 	return allErrs
 }
 
+func hasEstimationPolicy(policy *vpa_types.ContainerResourcePolicy) bool {
+	return policy.TargetCPUPercentile != nil || policy.LowerBoundCPUPercentile != nil || policy.UpperBoundCPUPercentile != nil ||
+		policy.TargetMemoryPercentile != nil || policy.LowerBoundMemoryPercentile != nil || policy.UpperBoundMemoryPercentile != nil ||
+		policy.SafetyMarginFraction != nil || policy.CPUEstimator != nil
+}
+
+func validateEstimationPolicy(policy *vpa_types.ContainerResourcePolicy, policyPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	allErrs = append(allErrs, validatePercentiles(policyPath, "CPU", policy.LowerBoundCPUPercentile, policy.TargetCPUPercentile, policy.UpperBoundCPUPercentile)...)
+	allErrs = append(allErrs, validatePercentiles(policyPath, "Memory", policy.LowerBoundMemoryPercentile, policy.TargetMemoryPercentile, policy.UpperBoundMemoryPercentile)...)
+
+	if policy.SafetyMarginFraction != nil {
+		margin := float64(policy.SafetyMarginFraction.MilliValue()) / 1000.0
+		if margin < 0 {
+			allErrs = append(allErrs, field.Invalid(policyPath.Child("safetyMarginFraction"), margin, "must be greater than or equal to 0"))
+		}
+	}
+
+	if policy.CPUEstimator != nil {
+		if _, found := vpa_types.GetCPUEstimators()[*policy.CPUEstimator]; !found {
+			allErrs = append(allErrs, field.NotSupported(policyPath.Child("cpuEstimator"), *policy.CPUEstimator, vpa_types.GetPossibleCPUEstimators()))
+		}
+	}
+
+	return allErrs
+}
+
+// validatePercentiles checks that the percentiles set for a resource are
+// between 0 and 1, and that the lower bound, target and upper bound
+// percentiles that are set don't decrease in this order.
+func validatePercentiles(policyPath *field.Path, resourceName string, lowerBound, target, upperBound *apires.Quantity) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	names := []string{"lowerBound" + resourceName + "Percentile", "target" + resourceName + "Percentile", "upperBound" + resourceName + "Percentile"}
+	previous := -1
+	var previousValue float64
+	for i, quantity := range []*apires.Quantity{lowerBound, target, upperBound} {
+		if quantity == nil {
+			continue
+		}
+		value := float64(quantity.MilliValue()) / 1000.0
+		if value < 0 || value > 1 {
+			allErrs = append(allErrs, field.Invalid(policyPath.Child(names[i]), value, "must be between 0 and 1"))
+			continue
+		}
+		if previous >= 0 && value < previousValue {
+			allErrs = append(allErrs, field.Invalid(policyPath.Child(names[i]), value, fmt.Sprintf("must be greater than or equal to %s", names[previous])))
+		}
+		previous, previousValue = i, value
+	}
+
+	return allErrs
+}
+
+func validateVPASpecHPACoordination(hpaCoordination *vpa_types.HPACoordination, fldPath *field.Path, opts VPAValidationOptions) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if !opts.AllowHPACoordination {
+		return append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("in order to use hpaCoordination, you must enable feature gate %s in the admission-controller args", features.HPACoordination)))
+	}
+
+	if hpaCoordination.Mode == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("mode"), ""))
+	} else if _, found := vpa_types.GetHPACoordinationModes()[hpaCoordination.Mode]; !found {
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("mode"), hpaCoordination.Mode, vpa_types.GetPossibleHPACoordinationModes()))
+	}
+	return allErrs
+}
+
 func validateVPASpecStartupBoost(startupBoost *vpa_types.StartupBoost, fldPath *field.Path, opts VPAValidationOptions) field.ErrorList {
 	allErrs := field.ErrorList{}
 
-	if !opts.AllowCPUStartupBoost {
-		return append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("in order to use startupBoost, you must enable feature gate %s in the admission-controller args", features.CPUStartupBoost)))
+	if !opts.AllowCPUStartupBoost && !opts.AllowMemoryStartupBoost {
+		return append(allErrs, field.Forbidden(fldPath, fmt.Sprintf("in order to use startupBoost, you must enable feature gate %s or %s in the admission-controller args", features.CPUStartupBoost, features.MemoryStartupBoost)))
 	}
 
-	cpuBoost := startupBoost.CPU
-	if cpuBoost == nil {
-		return allErrs
+	if startupBoost.CPU != nil {
+		cpuPath := fldPath.Child("cpu")
+		if !opts.AllowCPUStartupBoost {
+			allErrs = append(allErrs, field.Forbidden(cpuPath, fmt.Sprintf("in order to use CPU startup boost, you must enable feature gate %s in the admission-controller args", features.CPUStartupBoost)))
+		} else {
+			allErrs = append(allErrs, validateGenericStartupBoost(startupBoost.CPU, cpuPath, validateCPUResolution)...)
+		}
 	}
-	cpuPath := fldPath.Child("cpu")
-	boostType := cpuBoost.Type
+	if startupBoost.Memory != nil {
+		memoryPath := fldPath.Child("memory")
+		if !opts.AllowMemoryStartupBoost {
+			allErrs = append(allErrs, field.Forbidden(memoryPath, fmt.Sprintf("in order to use memory startup boost, you must enable feature gate %s in the admission-controller args", features.MemoryStartupBoost)))
+		} else {
+			allErrs = append(allErrs, validateGenericStartupBoost(startupBoost.Memory, memoryPath, validateMemoryResolution)...)
+		}
+	}
+	return allErrs
+}
+
+func validateGenericStartupBoost(boost *vpa_types.GenericStartupBoost, fldPath *field.Path, validateResolution func(apires.Quantity, *field.Path) field.ErrorList) field.ErrorList {
+	allErrs := field.ErrorList{}
+	boostType := boost.Type
 	if boostType == "" {
-		allErrs = append(allErrs, field.Required(cpuPath.Child("type"), fmt.Sprintf("must be either %s or %s", vpa_types.FactorStartupBoostType, vpa_types.QuantityStartupBoostType)))
+		allErrs = append(allErrs, field.Required(fldPath.Child("type"), fmt.Sprintf("must be either %s or %s", vpa_types.FactorStartupBoostType, vpa_types.QuantityStartupBoostType)))
 		return allErrs
 	}
 
 	switch boostType {
 	case vpa_types.FactorStartupBoostType:
-		if cpuBoost.Factor == nil {
-			allErrs = append(allErrs, field.Required(cpuPath.Child("factor"), "required when type is Factor"))
-		} else if *cpuBoost.Factor < 1 {
-			allErrs = append(allErrs, field.Invalid(cpuPath.Child("factor"), *cpuBoost.Factor, "must be >= 1 for type Factor"))
+		if boost.Factor == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("factor"), "required when type is Factor"))
+		} else if *boost.Factor < 1 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("factor"), *boost.Factor, "must be >= 1 for type Factor"))
 		}
 	case vpa_types.QuantityStartupBoostType:
-		if cpuBoost.Quantity == nil {
-			allErrs = append(allErrs, field.Required(cpuPath.Child("quantity"), "required when type is Quantity"))
+		if boost.Quantity == nil {
+			allErrs = append(allErrs, field.Required(fldPath.Child("quantity"), "required when type is Quantity"))
 		} else {
-			allErrs = append(allErrs, validateCPUResolution(*cpuBoost.Quantity, cpuPath.Child("quantity"))...)
+			allErrs = append(allErrs, validateResolution(*boost.Quantity, fldPath.Child("quantity"))...)
 		}
 	default:
-		allErrs = append(allErrs, field.NotSupported(cpuPath.Child("type"), boostType, []string{string(vpa_types.FactorStartupBoostType), string(vpa_types.QuantityStartupBoostType)}))
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("type"), boostType, []string{string(vpa_types.FactorStartupBoostType), string(vpa_types.QuantityStartupBoostType)}))
 	}
 	return allErrs
 }
@@ -206,6 +206,23 @@ func TestAllowPerVPAConfig(t *testing.T) {
 			featureFlag: false,
 			expected:    true,
 		},
+		{
+			name: "feature disabled but CPUEstimator set returns true",
+			oldObj: &vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					ResourcePolicy: &vpa_types.PodResourcePolicy{
+						ContainerPolicies: []vpa_types.ContainerResourcePolicy{
+							{
+								ContainerName: "app",
+								CPUEstimator:  ptr.To(vpa_types.CPUEstimatorSeasonal),
+							},
+						},
+					},
+				},
+			},
+			featureFlag: false,
+			expected:    true,
+		},
 		{
 			name: "feature disabled and no per-vpa config returns false",
 			oldObj: &vpa_types.VerticalPodAutoscaler{
@@ -264,6 +281,8 @@ func TestValidateVPA(t *testing.T) {
 	badCPUBoostType := vpa_types.StartupBoostType("bad")
 	validCPUBoostTypeFactor := vpa_types.FactorStartupBoostType
 	validCPUBoostTypeQuantity := vpa_types.QuantityStartupBoostType
+	badMemoryBoostQuantity := resource.MustParse("500m")
+	validMemoryBoostQuantity := resource.MustParse("100Mi")
 
 	tests := []struct {
 		name        string
@@ -585,7 +604,7 @@ func TestValidateVPA(t *testing.T) {
 				},
 			},
 			opts:        VPAValidationOptions{IsVPACreate: true, AllowCPUStartupBoost: false},
-			expectError: fmt.Errorf("spec.startupBoost: Forbidden: in order to use startupBoost, you must enable feature gate %s in the admission-controller args", features.CPUStartupBoost),
+			expectError: fmt.Errorf("spec.startupBoost: Forbidden: in order to use startupBoost, you must enable feature gate %s or %s in the admission-controller args", features.CPUStartupBoost, features.MemoryStartupBoost),
 		},
 		{
 			name: "container startupBoost with feature gate disabled",
@@ -610,7 +629,7 @@ func TestValidateVPA(t *testing.T) {
 				},
 			},
 			opts:        VPAValidationOptions{IsVPACreate: true, AllowCPUStartupBoost: false},
-			expectError: fmt.Errorf("spec.resourcePolicy.containerPolicies[0].startupBoost: Forbidden: in order to use startupBoost, you must enable feature gate %s in the admission-controller args", features.CPUStartupBoost),
+			expectError: fmt.Errorf("spec.resourcePolicy.containerPolicies[0].startupBoost: Forbidden: in order to use startupBoost, you must enable feature gate %s or %s in the admission-controller args", features.CPUStartupBoost, features.MemoryStartupBoost),
 		},
 		{
 			name: "top-level startupBoost with bad factor",
@@ -785,6 +804,95 @@ func TestValidateVPA(t *testing.T) {
 			opts:        VPAValidationOptions{IsVPACreate: true, AllowCPUStartupBoost: true},
 			expectError: fmt.Errorf("spec.resourcePolicy.containerPolicies[0].startupBoost.cpu.type: Required value: must be either %s or %s", vpa_types.FactorStartupBoostType, vpa_types.QuantityStartupBoostType),
 		},
+		{
+			name: "top-level memory startupBoost with only the memory feature gate",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					StartupBoost: &vpa_types.StartupBoost{
+						Memory: &vpa_types.GenericStartupBoost{
+							Type:     validCPUBoostTypeQuantity,
+							Quantity: &validMemoryBoostQuantity,
+						},
+					},
+				},
+			},
+			opts: VPAValidationOptions{IsVPACreate: true, AllowMemoryStartupBoost: true},
+		},
+		{
+			name: "top-level memory startupBoost with only the CPU feature gate",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					StartupBoost: &vpa_types.StartupBoost{
+						Memory: &vpa_types.GenericStartupBoost{
+							Type:   validCPUBoostTypeFactor,
+							Factor: &validCPUBoostFactor,
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowCPUStartupBoost: true},
+			expectError: fmt.Errorf("spec.startupBoost.memory: Forbidden: in order to use memory startup boost, you must enable feature gate %s in the admission-controller args", features.MemoryStartupBoost),
+		},
+		{
+			name: "container CPU startupBoost with only the memory feature gate",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					ResourcePolicy: &vpa_types.PodResourcePolicy{
+						ContainerPolicies: []vpa_types.ContainerResourcePolicy{
+							{
+								ContainerName: "loot box",
+								StartupBoost: &vpa_types.StartupBoost{
+									CPU: &vpa_types.GenericStartupBoost{
+										Type:   validCPUBoostTypeFactor,
+										Factor: &validCPUBoostFactor,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowMemoryStartupBoost: true},
+			expectError: fmt.Errorf("spec.resourcePolicy.containerPolicies[0].startupBoost.cpu: Forbidden: in order to use CPU startup boost, you must enable feature gate %s in the admission-controller args", features.CPUStartupBoost),
+		},
+		{
+			name: "container memory startupBoost with bad quantity",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					ResourcePolicy: &vpa_types.PodResourcePolicy{
+						ContainerPolicies: []vpa_types.ContainerResourcePolicy{
+							{
+								ContainerName: "loot box",
+								StartupBoost: &vpa_types.StartupBoost{
+									Memory: &vpa_types.GenericStartupBoost{
+										Type:     validCPUBoostTypeQuantity,
+										Quantity: &badMemoryBoostQuantity,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowMemoryStartupBoost: true},
+			expectError: fmt.Errorf("spec.resourcePolicy.containerPolicies[0].startupBoost.memory.quantity: Invalid value: \"%v\": must be a whole number of bytes", &badMemoryBoostQuantity),
+		},
 		{
 			name: "top-level startupBoost with valid factor",
 			vpa: vpa_types.VerticalPodAutoscaler{
@@ -1358,6 +1466,397 @@ func TestValidateVPA(t *testing.T) {
 			},
 			opts: VPAValidationOptions{IsVPACreate: true, AllowInPlace: true},
 		},
+		{
+			name: "creating VPA with hpaCoordination not allowed by disabled feature gate",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					HPACoordination: &vpa_types.HPACoordination{
+						Mode: vpa_types.HPACoordinationModeCPUUtilization,
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowHPACoordination: false},
+			expectError: fmt.Errorf("spec.hpaCoordination: Forbidden: in order to use hpaCoordination, you must enable feature gate %s in the admission-controller args", features.HPACoordination),
+		},
+		{
+			name: "hpaCoordination with unsupported mode",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					HPACoordination: &vpa_types.HPACoordination{
+						Mode: "Memory",
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowHPACoordination: true},
+			expectError: errors.New("spec.hpaCoordination.mode: Unsupported value: \"Memory\": supported values: \"CPUUtilization\", \"Off\""),
+		},
+		{
+			name: "hpaCoordination without mode",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					HPACoordination: &vpa_types.HPACoordination{},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowHPACoordination: true},
+			expectError: errors.New("spec.hpaCoordination.mode: Required value"),
+		},
+		{
+			name: "hpaCoordination with CPUUtilization mode",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					HPACoordination: &vpa_types.HPACoordination{
+						Mode: vpa_types.HPACoordinationModeCPUUtilization,
+					},
+				},
+			},
+			opts: VPAValidationOptions{IsVPACreate: true, AllowHPACoordination: true},
+		},
+		{
+			name: "creating VPA with canaryRollout not allowed by disabled feature gate",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode:    &validUpdateMode,
+						CanaryRollout: &vpa_types.CanaryRollout{Pods: 1},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowCanaryRollout: false},
+			expectError: fmt.Errorf("spec.updatePolicy.canaryRollout: Forbidden: in order to use canaryRollout, you must enable feature gate %s in the admission-controller args", features.CanaryRollout),
+		},
+		{
+			name: "canaryRollout with no pods",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode:    &validUpdateMode,
+						CanaryRollout: &vpa_types.CanaryRollout{Pods: 0},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowCanaryRollout: true},
+			expectError: errors.New("spec.updatePolicy.canaryRollout.pods: Invalid value: 0: must be greater than or equal to 1"),
+		},
+		{
+			name: "canaryRollout with non-positive soak period",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode:    &validUpdateMode,
+						CanaryRollout: &vpa_types.CanaryRollout{Pods: 1, SoakPeriodSeconds: ptr.To(int32(0))},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowCanaryRollout: true},
+			expectError: errors.New("spec.updatePolicy.canaryRollout.soakPeriodSeconds: Invalid value: 0: must be greater than or equal to 1"),
+		},
+		{
+			name: "valid canaryRollout",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode:    &validUpdateMode,
+						CanaryRollout: &vpa_types.CanaryRollout{Pods: 2, SoakPeriodSeconds: ptr.To(int32(300))},
+					},
+				},
+			},
+			opts: VPAValidationOptions{IsVPACreate: true, AllowCanaryRollout: true},
+		},
+		{
+			name: "creating VPA with maintenanceWindows not allowed by disabled feature gate",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode: &validUpdateMode,
+						MaintenanceWindows: []vpa_types.MaintenanceWindow{
+							{Schedule: "0 22 * * *", DurationSeconds: 28800, Directions: []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown}},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowMaintenanceWindows: false},
+			expectError: fmt.Errorf("spec.updatePolicy.maintenanceWindows: Forbidden: in order to use maintenanceWindows, you must enable feature gate %s in the admission-controller args", features.MaintenanceWindows),
+		},
+		{
+			name: "maintenanceWindows with invalid schedule",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode: &validUpdateMode,
+						MaintenanceWindows: []vpa_types.MaintenanceWindow{
+							{Schedule: "0 25 * * *", DurationSeconds: 28800, Directions: []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown}},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowMaintenanceWindows: true},
+			expectError: errors.New("spec.updatePolicy.maintenanceWindows[0].schedule: Invalid value: \"0 25 * * *\": end of range (25) above maximum (23): 25"),
+		},
+		{
+			name: "maintenanceWindows with schedule that never starts",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode: &validUpdateMode,
+						MaintenanceWindows: []vpa_types.MaintenanceWindow{
+							{Schedule: "0 0 31 2 *", DurationSeconds: 28800, Directions: []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown}},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowMaintenanceWindows: true},
+			expectError: errors.New("spec.updatePolicy.maintenanceWindows[0].schedule: Invalid value: \"0 0 31 2 *\": schedule \"0 0 31 2 *\" never starts"),
+		},
+		{
+			name: "maintenanceWindows with unknown time zone",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode: &validUpdateMode,
+						MaintenanceWindows: []vpa_types.MaintenanceWindow{
+							{Schedule: "0 22 * * *", DurationSeconds: 28800, TimeZone: ptr.To("Mars/Olympus_Mons"), Directions: []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown}},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowMaintenanceWindows: true},
+			expectError: errors.New("spec.updatePolicy.maintenanceWindows[0].timeZone: Invalid value: \"Mars/Olympus_Mons\": unknown time zone"),
+		},
+		{
+			name: "maintenanceWindows with non-positive duration",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode: &validUpdateMode,
+						MaintenanceWindows: []vpa_types.MaintenanceWindow{
+							{Schedule: "0 22 * * *", DurationSeconds: 0, Directions: []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown}},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowMaintenanceWindows: true},
+			expectError: errors.New("spec.updatePolicy.maintenanceWindows[0].durationSeconds: Invalid value: 0: must be greater than or equal to 1"),
+		},
+		{
+			name: "maintenanceWindows with unsupported direction",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode: &validUpdateMode,
+						MaintenanceWindows: []vpa_types.MaintenanceWindow{
+							{Schedule: "0 22 * * *", DurationSeconds: 28800, Directions: []vpa_types.ScalingDirection{"Sideways"}},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowMaintenanceWindows: true},
+			expectError: errors.New("spec.updatePolicy.maintenanceWindows[0].directions[0]: Unsupported value: \"Sideways\": supported values: \"Up\", \"Down\""),
+		},
+		{
+			name: "valid maintenanceWindows",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{
+						UpdateMode: &validUpdateMode,
+						MaintenanceWindows: []vpa_types.MaintenanceWindow{
+							{Schedule: "0 22 * * *", DurationSeconds: 28800, TimeZone: ptr.To("Europe/Berlin"), Directions: []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown}},
+						},
+					},
+				},
+			},
+			opts: VPAValidationOptions{IsVPACreate: true, AllowMaintenanceWindows: true},
+		},
+		{
+			name: "per-vpa config active with percentiles, safety margin and CPU estimator",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					ResourcePolicy: &vpa_types.PodResourcePolicy{
+						ContainerPolicies: []vpa_types.ContainerResourcePolicy{
+							{
+								ContainerName:           "loot box",
+								LowerBoundCPUPercentile: ptr.To(resource.MustParse("0.5")),
+								TargetCPUPercentile:     ptr.To(resource.MustParse("0.95")),
+								UpperBoundCPUPercentile: ptr.To(resource.MustParse("0.99")),
+								TargetMemoryPercentile:  ptr.To(resource.MustParse("0.9")),
+								SafetyMarginFraction:    ptr.To(resource.MustParse("0.05")),
+								CPUEstimator:            ptr.To(vpa_types.CPUEstimatorSeasonal),
+							},
+						},
+					},
+				},
+			},
+			opts: VPAValidationOptions{IsVPACreate: true, AllowPerVPAConfig: true},
+		},
+		{
+			name: "per-vpa config disabled and percentile used",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					ResourcePolicy: &vpa_types.PodResourcePolicy{
+						ContainerPolicies: []vpa_types.ContainerResourcePolicy{
+							{
+								ContainerName:       "loot box",
+								TargetCPUPercentile: ptr.To(resource.MustParse("0.95")),
+							},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowPerVPAConfig: false},
+			expectError: fmt.Errorf("spec.resourcePolicy.containerPolicies[0]: Forbidden: percentiles, safetyMarginFraction and cpuEstimator are not supported when feature flag %s is disabled", features.PerVPAConfig),
+		},
+		{
+			name: "percentile above 1",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					ResourcePolicy: &vpa_types.PodResourcePolicy{
+						ContainerPolicies: []vpa_types.ContainerResourcePolicy{
+							{
+								ContainerName:          "loot box",
+								TargetMemoryPercentile: ptr.To(resource.MustParse("1.5")),
+							},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowPerVPAConfig: true},
+			expectError: errors.New("spec.resourcePolicy.containerPolicies[0].targetMemoryPercentile: Invalid value: 1.5: must be between 0 and 1"),
+		},
+		{
+			name: "target percentile below lower bound percentile",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					ResourcePolicy: &vpa_types.PodResourcePolicy{
+						ContainerPolicies: []vpa_types.ContainerResourcePolicy{
+							{
+								ContainerName:           "loot box",
+								LowerBoundCPUPercentile: ptr.To(resource.MustParse("0.9")),
+								UpperBoundCPUPercentile: ptr.To(resource.MustParse("0.8")),
+							},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowPerVPAConfig: true},
+			expectError: errors.New("spec.resourcePolicy.containerPolicies[0].upperBoundCPUPercentile: Invalid value: 0.8: must be greater than or equal to lowerBoundCPUPercentile"),
+		},
+		{
+			name: "negative safety margin",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					ResourcePolicy: &vpa_types.PodResourcePolicy{
+						ContainerPolicies: []vpa_types.ContainerResourcePolicy{
+							{
+								ContainerName:        "loot box",
+								SafetyMarginFraction: ptr.To(resource.MustParse("-0.1")),
+							},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowPerVPAConfig: true},
+			expectError: errors.New("spec.resourcePolicy.containerPolicies[0].safetyMarginFraction: Invalid value: -0.1: must be greater than or equal to 0"),
+		},
+		{
+			name: "unsupported CPU estimator",
+			vpa: vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					TargetRef: &autoscalingv1.CrossVersionObjectReference{
+						Kind: "Deployment",
+						Name: "my-app",
+					},
+					ResourcePolicy: &vpa_types.PodResourcePolicy{
+						ContainerPolicies: []vpa_types.ContainerResourcePolicy{
+							{
+								ContainerName: "loot box",
+								CPUEstimator:  ptr.To(vpa_types.CPUEstimator("Average")),
+							},
+						},
+					},
+				},
+			},
+			opts:        VPAValidationOptions{IsVPACreate: true, AllowPerVPAConfig: true},
+			expectError: errors.New("spec.resourcePolicy.containerPolicies[0].cpuEstimator: Unsupported value: \"Average\": supported values: \"Percentile\", \"Seasonal\""),
+		},
 	}
 	for _, tc := range tests {
 		t.Run(fmt.Sprintf("test case: %s", tc.name), func(t *testing.T) {
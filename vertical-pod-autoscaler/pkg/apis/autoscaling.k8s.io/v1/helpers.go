@@ -62,3 +62,41 @@ func GetPossibleScalingModes() []string {
 	sort.Strings(result)
 	return result
 }
+
+// GetHPACoordinationModes returns all supported HPACoordinationModes
+func GetHPACoordinationModes() map[HPACoordinationMode]any {
+	return map[HPACoordinationMode]any{
+		HPACoordinationModeOff:            nil,
+		HPACoordinationModeCPUUtilization: nil,
+	}
+}
+
+// GetPossibleHPACoordinationModes returns all supported HPACoordinationModes as a slice of strings
+func GetPossibleHPACoordinationModes() []string {
+	modes := GetHPACoordinationModes()
+	result := make([]string, 0, len(modes))
+	for mode := range modes {
+		result = append(result, string(mode))
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GetCPUEstimators returns all supported CPUEstimators
+func GetCPUEstimators() map[CPUEstimator]any {
+	return map[CPUEstimator]any{
+		CPUEstimatorPercentile: nil,
+		CPUEstimatorSeasonal:   nil,
+	}
+}
+
+// GetPossibleCPUEstimators returns all supported CPUEstimators as a slice of strings
+func GetPossibleCPUEstimators() []string {
+	estimators := GetCPUEstimators()
+	result := make([]string, 0, len(estimators))
+	for estimator := range estimators {
+		result = append(result, string(estimator))
+	}
+	sort.Strings(result)
+	return result
+}
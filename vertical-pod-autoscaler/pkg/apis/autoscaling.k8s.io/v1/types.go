@@ -114,14 +114,55 @@ type VerticalPodAutoscalerSpec struct {
 	// startupBoost specifies the startup boost policy for the pod.
 	// +optional
 	StartupBoost *StartupBoost `json:"startupBoost,omitempty"`
+
+	// hpaCoordination enables coordination with a HorizontalPodAutoscaler
+	// that scales the same target on CPU utilization. When enabled, the CPU
+	// recommendation is computed from the usage of the whole workload, so that
+	// the observed per-replica usage divided by the request stays close to the
+	// CPU utilization target of the HorizontalPodAutoscaler.
+	// Requires VPA level feature gate "HPACoordination" to be enabled
+	// on the admission-controller and recommender pods.
+	// +optional
+	HPACoordination *HPACoordination `json:"hpaCoordination,omitempty"`
+}
+
+// HPACoordination defines how the autoscaler coordinates its recommendations
+// with a HorizontalPodAutoscaler targeting the same controller.
+type HPACoordination struct {
+	// mode specifies whether the coordination is enabled.
+	// Supported values are: "Off", "CPUUtilization".
+	// +required
+	Mode HPACoordinationMode `json:"mode"`
 }
 
+// HPACoordinationMode controls whether the autoscaler coordinates its
+// recommendations with a HorizontalPodAutoscaler.
+// +kubebuilder:validation:Enum=Off;CPUUtilization
+type HPACoordinationMode string
+
+const (
+	// HPACoordinationModeOff means that recommendations are computed without
+	// taking any HorizontalPodAutoscaler into account.
+	HPACoordinationModeOff HPACoordinationMode = "Off"
+	// HPACoordinationModeCPUUtilization means that the CPU recommendation is
+	// computed so that the CPU utilization target of the HorizontalPodAutoscaler
+	// scaling the same controller is kept stable.
+	HPACoordinationModeCPUUtilization HPACoordinationMode = "CPUUtilization"
+)
+
 // StartupBoost defines the startup boost policy.
 type StartupBoost struct {
 	// cpu specifies the CPU startup boost policy.
-	// If this field is not set, no startup boost is applied.
+	// If this field is not set, no CPU startup boost is applied.
 	// +optional
 	CPU *GenericStartupBoost `json:"cpu,omitempty"`
+
+	// memory specifies the memory startup boost policy.
+	// If this field is not set, no memory startup boost is applied.
+	// Requires VPA level feature gate "MemoryStartupBoost" to be enabled
+	// on the admission-controller and updater pods.
+	// +optional
+	Memory *GenericStartupBoost `json:"memory,omitempty"`
 }
 
 // GenericStartupBoost defines the startup boost policy for a resource.
@@ -211,6 +252,72 @@ type PodUpdatePolicy struct {
 	// +optional
 	// +kubebuilder:validation:Minimum=1
 	EvictAfterOOMSeconds *int32 `json:"evictAfterOOMSeconds,omitempty"`
+
+	// canaryRollout makes the updater apply recommendations to a few canary
+	// pods first and watch them for a soak period before updating the
+	// remaining pods.
+	// +optional
+	CanaryRollout *CanaryRollout `json:"canaryRollout,omitempty"`
+
+	// maintenanceWindows restricts the scaling directions of pod updates to
+	// the given time windows. An update scaling the resources of a pod in a
+	// direction is only applied while a window allowing that direction is
+	// open. Directions not allowed by any window are never restricted.
+	// Requires VPA level feature gate "MaintenanceWindows" to be enabled
+	// on the admission-controller and updater pods.
+	// +optional
+	// +listType=atomic
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
+}
+
+// MaintenanceWindow is a recurring time window in which pod updates scaling
+// resources in the given directions are allowed.
+type MaintenanceWindow struct {
+	// schedule is the start of the window in the cron format, e.g.
+	// "0 22 * * *" for every day at 22:00.
+	Schedule string `json:"schedule"`
+
+	// durationSeconds is how long the window stays open after each start.
+	// +kubebuilder:validation:Minimum=1
+	DurationSeconds int32 `json:"durationSeconds"`
+
+	// timeZone is the name of the time zone of the schedule, e.g.
+	// "Europe/Berlin". The default is UTC.
+	// +optional
+	TimeZone *string `json:"timeZone,omitempty"`
+
+	// directions are the scaling directions allowed in the window.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	Directions []ScalingDirection `json:"directions"`
+}
+
+// ScalingDirection is the direction in which a pod update changes the
+// resources of the pod.
+// +kubebuilder:validation:Enum=Up;Down
+type ScalingDirection string
+
+const (
+	// ScalingDirectionUp is an update raising the requests of a container
+	// resource.
+	ScalingDirectionUp ScalingDirection = "Up"
+	// ScalingDirectionDown is an update lowering the requests of a container
+	// resource.
+	ScalingDirectionDown ScalingDirection = "Down"
+)
+
+// CanaryRollout controls the canary stage of pod updates.
+type CanaryRollout struct {
+	// pods is the number of pods updated in the canary stage.
+	// +kubebuilder:validation:Minimum=1
+	Pods int32 `json:"pods"`
+
+	// soakPeriodSeconds is the time in seconds the canary pods are watched
+	// for OOMs, restarts and readiness failures before the remaining pods
+	// are updated. The default is 600.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	SoakPeriodSeconds *int32 `json:"soakPeriodSeconds,omitempty"`
 }
 
 // UpdateMode controls when autoscaler applies changes to the pod resources.
@@ -319,6 +426,46 @@ type ContainerResourcePolicy struct {
 	// +kubebuilder:validation:Minimum=1
 	MemoryAggregationIntervalCount *int64 `json:"memoryAggregationIntervalCount,omitempty"`
 
+	// targetCPUPercentile is the usage percentile used to compute the CPU
+	// target recommendation, between 0 and 1.
+	// +optional
+	TargetCPUPercentile *resource.Quantity `json:"targetCPUPercentile,omitempty"`
+
+	// lowerBoundCPUPercentile is the usage percentile used to compute the CPU
+	// lower bound recommendation, between 0 and 1.
+	// +optional
+	LowerBoundCPUPercentile *resource.Quantity `json:"lowerBoundCPUPercentile,omitempty"`
+
+	// upperBoundCPUPercentile is the usage percentile used to compute the CPU
+	// upper bound recommendation, between 0 and 1.
+	// +optional
+	UpperBoundCPUPercentile *resource.Quantity `json:"upperBoundCPUPercentile,omitempty"`
+
+	// targetMemoryPercentile is the memory peaks percentile used to compute
+	// the memory target recommendation, between 0 and 1.
+	// +optional
+	TargetMemoryPercentile *resource.Quantity `json:"targetMemoryPercentile,omitempty"`
+
+	// lowerBoundMemoryPercentile is the memory peaks percentile used to
+	// compute the memory lower bound recommendation, between 0 and 1.
+	// +optional
+	LowerBoundMemoryPercentile *resource.Quantity `json:"lowerBoundMemoryPercentile,omitempty"`
+
+	// upperBoundMemoryPercentile is the memory peaks percentile used to
+	// compute the memory upper bound recommendation, between 0 and 1.
+	// +optional
+	UpperBoundMemoryPercentile *resource.Quantity `json:"upperBoundMemoryPercentile,omitempty"`
+
+	// safetyMarginFraction is the fraction of the usage added to all
+	// recommendations as a safety margin.
+	// +optional
+	SafetyMarginFraction *resource.Quantity `json:"safetyMarginFraction,omitempty"`
+
+	// cpuEstimator selects the estimator used to compute CPU recommendations.
+	// The default is the estimator configured in the recommender.
+	// +optional
+	CPUEstimator *CPUEstimator `json:"cpuEstimator,omitempty"`
+
 	// startupBoost specifies the startup boost policy for the container.
 	// This overrides any pod-level startup boost policy.
 	// The startup boost policy takes precedence over the rest of the fields in
@@ -345,6 +492,20 @@ const (
 	ContainerScalingModeOff ContainerScalingMode = "Off"
 )
 
+// CPUEstimator selects how CPU recommendations are computed from the usage
+// history of a container.
+// +kubebuilder:validation:Enum=Percentile;Seasonal
+type CPUEstimator string
+
+const (
+	// CPUEstimatorPercentile means that CPU recommendations are percentiles
+	// of all CPU usage samples.
+	CPUEstimatorPercentile CPUEstimator = "Percentile"
+	// CPUEstimatorSeasonal means that CPU recommendations are the highest
+	// percentiles of CPU usage among the hours of the week.
+	CPUEstimatorSeasonal CPUEstimator = "Seasonal"
+)
+
 // ContainerControlledValues controls which resource value should be autoscaled.
 // +kubebuilder:validation:Enum=RequestsAndLimits;RequestsOnly
 type ContainerControlledValues string
@@ -435,6 +596,15 @@ var (
 	// ConfigUnsupported indicates that this VPA configuration is unsupported
 	// and recommendations will not be provided for it.
 	ConfigUnsupported VerticalPodAutoscalerConditionType = "ConfigUnsupported"
+	// CanaryRolledBack indicates that the updater rolled back the canary pods
+	// of a recommendation rollout because they regressed.
+	CanaryRolledBack VerticalPodAutoscalerConditionType = "CanaryRolledBack"
+	// UpdatesDeferred indicates that the updater deferred pod updates until
+	// the next maintenance window allowing their scaling direction.
+	UpdatesDeferred VerticalPodAutoscalerConditionType = "UpdatesDeferred"
+	// RecommendationSeeded indicates that the recommendation is partly based on
+	// the usage history of similar workloads, as this one has too little of its own.
+	RecommendationSeeded VerticalPodAutoscalerConditionType = "RecommendationSeeded"
 )
 
 // VerticalPodAutoscalerCondition describes the state of
@@ -530,6 +700,25 @@ type VerticalPodAutoscalerCheckpointStatus struct {
 
 	// Total number of samples in the histograms.
 	TotalSamplesCount int `json:"totalSamplesCount,omitempty"`
+
+	// Checkpoints of histograms for consumption of CPU in each hour of the week.
+	// Only stored by recommenders using the seasonal CPU estimator.
+	// +optional
+	// +listType=map
+	// +listMapKey=hourOfWeek
+	SeasonalCPUHistograms []HourOfWeekHistogramCheckpoint `json:"seasonalCPUHistograms,omitempty"`
+}
+
+// HourOfWeekHistogramCheckpoint contains the checkpoint of the histogram of
+// samples collected in a single hour of the week.
+type HourOfWeekHistogramCheckpoint struct {
+	// Hour of the week, in UTC, counted from Sunday 00:00.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=167
+	HourOfWeek int `json:"hourOfWeek"`
+
+	// Checkpoint of the histogram for this hour of the week.
+	Histogram HistogramCheckpoint `json:"histogram"`
 }
 
 // HistogramCheckpoint contains data needed to reconstruct the histogram.
@@ -27,6 +27,27 @@ import (
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryRollout) DeepCopyInto(out *CanaryRollout) {
+	*out = *in
+	if in.SoakPeriodSeconds != nil {
+		in, out := &in.SoakPeriodSeconds, &out.SoakPeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRollout.
+func (in *CanaryRollout) DeepCopy() *CanaryRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerResourcePolicy) DeepCopyInto(out *ContainerResourcePolicy) {
 	*out = *in
@@ -83,6 +104,46 @@ func (in *ContainerResourcePolicy) DeepCopyInto(out *ContainerResourcePolicy) {
 		*out = new(int64)
 		**out = **in
 	}
+	if in.TargetCPUPercentile != nil {
+		in, out := &in.TargetCPUPercentile, &out.TargetCPUPercentile
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.LowerBoundCPUPercentile != nil {
+		in, out := &in.LowerBoundCPUPercentile, &out.LowerBoundCPUPercentile
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.UpperBoundCPUPercentile != nil {
+		in, out := &in.UpperBoundCPUPercentile, &out.UpperBoundCPUPercentile
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.TargetMemoryPercentile != nil {
+		in, out := &in.TargetMemoryPercentile, &out.TargetMemoryPercentile
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.LowerBoundMemoryPercentile != nil {
+		in, out := &in.LowerBoundMemoryPercentile, &out.LowerBoundMemoryPercentile
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.UpperBoundMemoryPercentile != nil {
+		in, out := &in.UpperBoundMemoryPercentile, &out.UpperBoundMemoryPercentile
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.SafetyMarginFraction != nil {
+		in, out := &in.SafetyMarginFraction, &out.SafetyMarginFraction
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.CPUEstimator != nil {
+		in, out := &in.CPUEstimator, &out.CPUEstimator
+		*out = new(CPUEstimator)
+		**out = **in
+	}
 	if in.StartupBoost != nil {
 		in, out := &in.StartupBoost, &out.StartupBoost
 		*out = new(StartupBoost)
@@ -153,6 +214,22 @@ func (in *GenericStartupBoost) DeepCopy() *GenericStartupBoost {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HPACoordination) DeepCopyInto(out *HPACoordination) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HPACoordination.
+func (in *HPACoordination) DeepCopy() *HPACoordination {
+	if in == nil {
+		return nil
+	}
+	out := new(HPACoordination)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HistogramCheckpoint) DeepCopyInto(out *HistogramCheckpoint) {
 	*out = *in
@@ -177,6 +254,49 @@ func (in *HistogramCheckpoint) DeepCopy() *HistogramCheckpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HourOfWeekHistogramCheckpoint) DeepCopyInto(out *HourOfWeekHistogramCheckpoint) {
+	*out = *in
+	in.Histogram.DeepCopyInto(&out.Histogram)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HourOfWeekHistogramCheckpoint.
+func (in *HourOfWeekHistogramCheckpoint) DeepCopy() *HourOfWeekHistogramCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(HourOfWeekHistogramCheckpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.TimeZone != nil {
+		in, out := &in.TimeZone, &out.TimeZone
+		*out = new(string)
+		**out = **in
+	}
+	if in.Directions != nil {
+		in, out := &in.Directions, &out.Directions
+		*out = make([]ScalingDirection, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodResourcePolicy) DeepCopyInto(out *PodResourcePolicy) {
 	*out = *in
@@ -229,6 +349,18 @@ func (in *PodUpdatePolicy) DeepCopyInto(out *PodUpdatePolicy) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.CanaryRollout != nil {
+		in, out := &in.CanaryRollout, &out.CanaryRollout
+		*out = new(CanaryRollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -317,6 +449,11 @@ func (in *StartupBoost) DeepCopyInto(out *StartupBoost) {
 		*out = new(GenericStartupBoost)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(GenericStartupBoost)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -443,6 +580,13 @@ func (in *VerticalPodAutoscalerCheckpointStatus) DeepCopyInto(out *VerticalPodAu
 	in.MemoryHistogram.DeepCopyInto(&out.MemoryHistogram)
 	in.FirstSampleStart.DeepCopyInto(&out.FirstSampleStart)
 	in.LastSampleStart.DeepCopyInto(&out.LastSampleStart)
+	if in.SeasonalCPUHistograms != nil {
+		in, out := &in.SeasonalCPUHistograms, &out.SeasonalCPUHistograms
+		*out = make([]HourOfWeekHistogramCheckpoint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -556,6 +700,11 @@ func (in *VerticalPodAutoscalerSpec) DeepCopyInto(out *VerticalPodAutoscalerSpec
 		*out = new(StartupBoost)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HPACoordination != nil {
+		in, out := &in.HPACoordination, &out.HPACoordination
+		*out = new(HPACoordination)
+		**out = **in
+	}
 	return
 }
 
@@ -40,12 +40,41 @@ const (
 	// In each feature gate description, you must specify "components".
 	// The feature must be enabled by the --feature-gates argument on each listed component.
 
+	// alpha: v1.8.0
+	// components: admission-controller, updater
+
+	// CanaryRollout enables updating a few canary pods first and soaking them
+	// before updating the remaining pods of a VPA, rolling the canaries back
+	// on regressions.
+	CanaryRollout featuregate.Feature = "CanaryRollout"
+
 	// alpha: v1.7.0
 	// components: admission-controller, updater
 
 	// CPUStartupBoost enables the CPU startup boost feature.
 	CPUStartupBoost featuregate.Feature = "CPUStartupBoost"
 
+	// alpha: v1.8.0
+	// components: admission-controller, recommender
+
+	// HPACoordination enables computing the CPU recommendation so that the CPU
+	// utilization target of a HorizontalPodAutoscaler scaling the same workload
+	// stays stable, allowing VPA and HPA to be used together on CPU.
+	HPACoordination featuregate.Feature = "HPACoordination"
+
+	// alpha: v1.8.0
+	// components: admission-controller, updater
+
+	// MaintenanceWindows enables restricting the scaling directions of pod
+	// updates to maintenance windows.
+	MaintenanceWindows featuregate.Feature = "MaintenanceWindows"
+
+	// alpha: v1.8.0
+	// components: admission-controller, updater
+
+	// MemoryStartupBoost enables the memory startup boost feature.
+	MemoryStartupBoost featuregate.Feature = "MemoryStartupBoost"
+
 	// alpha: v1.5.0
 	// components: admission-controller, recommender, updater
 
@@ -55,6 +84,27 @@ const (
 	// same cluster.
 	PerVPAConfig featuregate.Feature = "PerVPAConfig"
 
+	// alpha: v1.8.0
+	// components: recommender
+
+	// RecommendationSeeding enables seeding the recommendation of a VPA without
+	// usage history from the history of similar workloads.
+	RecommendationSeeding featuregate.Feature = "RecommendationSeeding"
+
+	// alpha: v1.8.0
+	// components: recommender
+
+	// RecommenderSharding enables running the recommender as several shards,
+	// each handling a part of the VPAs, coordinated through Leases.
+	RecommenderSharding featuregate.Feature = "RecommenderSharding"
+
+	// alpha: v1.8.0
+	// components: updater
+
+	// SchedulabilityCheck enables checking that a pod still fits on a node with
+	// its recommended resources before the updater evicts or resizes it up.
+	SchedulabilityCheck featuregate.Feature = "SchedulabilityCheck"
+
 	// alpha: v1.7.0
 	// components: admission-controller, updater
 
@@ -27,13 +27,34 @@ import (
 
 // Entries are alphabetized.
 var defaultVersionedFeatureGates = map[featuregate.Feature]featuregate.VersionedSpecs{
+	CanaryRollout: {
+		{Version: version.MustParse("1.8"), Default: false, PreRelease: featuregate.Alpha},
+	},
 	CPUStartupBoost: {
 		{Version: version.MustParse("1.6"), Default: false, PreRelease: featuregate.Alpha},
 	},
+	HPACoordination: {
+		{Version: version.MustParse("1.8"), Default: false, PreRelease: featuregate.Alpha},
+	},
 	InPlace: {
 		{Version: version.MustParse("1.7"), Default: false, PreRelease: featuregate.Alpha},
 	},
+	MaintenanceWindows: {
+		{Version: version.MustParse("1.8"), Default: false, PreRelease: featuregate.Alpha},
+	},
+	MemoryStartupBoost: {
+		{Version: version.MustParse("1.8"), Default: false, PreRelease: featuregate.Alpha},
+	},
 	PerVPAConfig: {
 		{Version: version.MustParse("1.5"), Default: false, PreRelease: featuregate.Alpha},
 	},
+	RecommendationSeeding: {
+		{Version: version.MustParse("1.8"), Default: false, PreRelease: featuregate.Alpha},
+	},
+	RecommenderSharding: {
+		{Version: version.MustParse("1.8"), Default: false, PreRelease: featuregate.Alpha},
+	},
+	SchedulabilityCheck: {
+		{Version: version.MustParse("1.8"), Default: false, PreRelease: featuregate.Alpha},
+	},
 }
@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/backtest"
+	recommender_config "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/config"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/history"
+)
+
+const backtestUsage = `Usage: kubectl vpa backtest (-f <history> | --prometheus-address <address>) [flags]
+
+Replays the historical usage of containers through the VPA recommender with
+several configurations, and reports for each one how often usage reached the
+recommended target, risking CPU throttling or OOMs, and the average total
+resources requested and used.
+
+Usage history is read from Prometheus, like the recommender does with
+--storage=prometheus, or from a file written with --export. Containers of pods
+with the same value of --workload-label are aggregated together, like the
+containers of the pods of a VPA.
+
+Configurations are read from a YAML list, parameters missing from a
+configuration keep the default of the recommender flag:
+  - name: default
+  - name: p95
+    targetCPUPercentile: 0.95
+    safetyMarginFraction: 0.1
+    cpuHistogramDecayHalfLife: 12h
+
+Flags:
+`
+
+func runBacktest(args []string, stdin io.Reader, stdout io.Writer) error {
+	defaults := recommender_config.DefaultRecommenderConfig()
+	flags := flag.NewFlagSet("backtest", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), backtestUsage)
+		flags.PrintDefaults()
+	}
+	filename := flags.String("f", "", "Usage history file written with --export, - for stdin.")
+	configsFilename := flags.String("configs", "", "File with the configurations to backtest. Defaults to the default configuration of the recommender.")
+	interval := flags.Duration("interval", time.Hour, "How often recommendations are recomputed and applied to the replayed usage.")
+	warmup := flags.Duration("warmup", 24*time.Hour, "How long after the first sample of a workload its recommendations start being evaluated.")
+	workloadLabel := flags.String("workload-label", "app", "Pod label whose value groups pods into workloads. Pods without it are workloads of their own.")
+	export := flags.String("export", "", "Write the usage history to this file, - for stdout, instead of backtesting.")
+	output := flags.String("o", "table", "Output format, one of: table, json.")
+	prometheusAddress := flags.String("prometheus-address", "", "Address of the Prometheus server to read the usage history from.")
+	prometheusInsecure := flags.Bool("prometheus-insecure", defaults.PrometheusInsecure, "Skip TLS verification if https is used in the Prometheus address.")
+	prometheusJobName := flags.String("prometheus-cadvisor-job-name", defaults.PrometheusJobName, "Name of the Prometheus job scraping the cAdvisor metrics.")
+	historyLength := flags.String("history-length", defaults.HistoryLength, "How much history to read from Prometheus.")
+	historyResolution := flags.String("history-resolution", defaults.HistoryResolution, "Resolution of the history read from Prometheus.")
+	namespace := flags.String("namespace", "", "Only read the history of pods in this namespace from Prometheus.")
+	queryTimeout := flags.Duration("prometheus-query-timeout", 5*time.Minute, "Timeout of Prometheus queries.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if (*filename == "") == (*prometheusAddress == "") {
+		flags.Usage()
+		return fmt.Errorf("exactly one of -f and --prometheus-address is required")
+	}
+	if *output != "table" && *output != "json" {
+		return fmt.Errorf("unsupported output format %q", *output)
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	configurations := []backtest.Configuration{backtest.DefaultConfiguration()}
+	if *configsFilename != "" {
+		data, err := os.ReadFile(*configsFilename)
+		if err != nil {
+			return err
+		}
+		configurations, err = backtest.ReadConfigurations(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse configurations: %v", err)
+		}
+	}
+
+	var containers []backtest.ContainerHistory
+	if *filename != "" {
+		data, err := readInput(*filename, stdin)
+		if err != nil {
+			return err
+		}
+		containers, err = backtest.ReadHistory(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to parse usage history: %v", err)
+		}
+	} else {
+		provider, err := history.NewPrometheusHistoryProvider(history.PrometheusHistoryProviderConfig{
+			Address:                *prometheusAddress,
+			Insecure:               *prometheusInsecure,
+			QueryTimeout:           *queryTimeout,
+			HistoryLength:          *historyLength,
+			HistoryResolution:      *historyResolution,
+			PodLabelPrefix:         defaults.PodLabelPrefix,
+			PodLabelsMetricName:    defaults.PodLabelsMetricName,
+			PodNamespaceLabel:      defaults.PodNamespaceLabel,
+			PodNameLabel:           defaults.PodNameLabel,
+			CtrNamespaceLabel:      defaults.CtrNamespaceLabel,
+			CtrPodNameLabel:        defaults.CtrPodNameLabel,
+			CtrNameLabel:           defaults.CtrNameLabel,
+			CadvisorMetricsJobName: *prometheusJobName,
+			Namespace:              *namespace,
+			CPUMetricName:          defaults.HistoryCPUMetric,
+			MemoryMetricName:       defaults.HistoryMemoryMetric,
+		})
+		if err != nil {
+			return err
+		}
+		pods, err := provider.GetClusterHistory()
+		if err != nil {
+			return fmt.Errorf("failed to read usage history from Prometheus: %v", err)
+		}
+		containers = backtest.FromPodHistory(pods, *workloadLabel)
+	}
+
+	if *export != "" {
+		if *export == "-" {
+			return backtest.WriteHistory(stdout, containers)
+		}
+		file, err := os.Create(*export)
+		if err != nil {
+			return err
+		}
+		if err := backtest.WriteHistory(file, containers); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+
+	results := backtest.Run(containers, configurations, backtest.Options{RecommendationInterval: *interval, Warmup: *warmup})
+	if *output == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+	printBacktestResults(stdout, results)
+	return nil
+}
+
+func printBacktestResults(w io.Writer, results []backtest.Result) {
+	table := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(table, "CONFIGURATION\tCPU THROTTLING RISK\tCPU REQUESTED\tCPU USED\tMEMORY OOM RISK\tMEMORY REQUESTED\tMEMORY USED")
+	for _, result := range results {
+		fmt.Fprintf(table, "%s\t%.2f%%\t%.3f\t%.3f\t%.2f%%\t%.0fMi\t%.0fMi\n",
+			result.Configuration,
+			100*result.CPU.Risk(), result.CPU.Requested, result.CPU.Used,
+			100*result.Memory.Risk(), result.Memory.Requested/(1<<20), result.Memory.Used/(1<<20))
+	}
+	table.Flush()
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/backtest"
+)
+
+func makeBacktestHistory(t *testing.T) []byte {
+	start := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+	container := backtest.ContainerHistory{Namespace: "default", Workload: "web", Pod: "web-1", Container: "app"}
+	for i := range 2 * 24 {
+		timestamp := start.Add(time.Duration(i) * time.Hour)
+		container.CPU = append(container.CPU, backtest.UsageSample{Time: timestamp, Value: 0.5})
+		container.Memory = append(container.Memory, backtest.UsageSample{Time: timestamp, Value: 100 << 20})
+	}
+	var history bytes.Buffer
+	require.NoError(t, backtest.WriteHistory(&history, []backtest.ContainerHistory{container}))
+	return history.Bytes()
+}
+
+func TestRunBacktest(t *testing.T) {
+	configsFilename := filepath.Join(t.TempDir(), "configs.yaml")
+	require.NoError(t, os.WriteFile(configsFilename, []byte("- name: default\n- name: p50\n  targetCPUPercentile: 0.5\n"), 0o600))
+
+	var stdout bytes.Buffer
+	err := runBacktest([]string{"-f", "-", "--configs", configsFilename, "-o", "json"}, bytes.NewReader(makeBacktestHistory(t)), &stdout)
+	require.NoError(t, err)
+
+	var results []backtest.Result
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+	require.Len(t, results, 2)
+	assert.Equal(t, "default", results[0].Configuration)
+	assert.Equal(t, "p50", results[1].Configuration)
+	assert.Equal(t, 24, results[0].CPU.Samples)
+	assert.InDelta(t, 0.5, results[0].CPU.Used, 0.001)
+
+	stdout.Reset()
+	err = runBacktest([]string{"-f", "-"}, bytes.NewReader(makeBacktestHistory(t)), &stdout)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "CONFIGURATION"))
+	assert.True(t, strings.HasPrefix(lines[1], "default "))
+}
+
+func TestRunBacktestRequiresOneSource(t *testing.T) {
+	var stdout bytes.Buffer
+	assert.Error(t, runBacktest([]string{}, nil, &stdout))
+	assert.Error(t, runBacktest([]string{"-f", "-", "--prometheus-address", "http://prometheus"}, nil, &stdout))
+}
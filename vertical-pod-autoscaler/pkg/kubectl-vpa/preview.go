@@ -0,0 +1,221 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/preview"
+)
+
+const previewUsage = `Usage: kubectl vpa preview -f <manifest> [flags]
+
+Shows the resources the VPA admission controller would set on pods of the
+workload in the manifest. The manifest can contain a Pod, a PodTemplate or any
+workload with a pod template (Deployment, StatefulSet, DaemonSet, ReplicaSet,
+Job, CronJob, ReplicationController).
+
+The admission controller has to run with --enable-recommendation-preview. The
+endpoint is served by its webhook server, which can be reached with e.g.:
+  kubectl -n kube-system port-forward deployment/vpa-admission-controller 8000
+
+Requests are authenticated with the bearer token of the current kubeconfig
+context, or the one passed with -token. The user has to be allowed to get
+VPAs in the namespace of the workload.
+
+Flags:
+`
+
+// manifest holds the fields of a Kubernetes object needed to build a preview
+// request.
+type manifest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              struct {
+		Template    *corev1.PodTemplateSpec `json:"template,omitempty"`
+		JobTemplate *struct {
+			Spec struct {
+				Template *corev1.PodTemplateSpec `json:"template,omitempty"`
+			} `json:"spec"`
+		} `json:"jobTemplate,omitempty"`
+	} `json:"spec"`
+	// Template is set for PodTemplate objects.
+	Template *corev1.PodTemplateSpec `json:"template,omitempty"`
+}
+
+func runPreview(args []string, stdin io.Reader, stdout io.Writer) error {
+	flags := flag.NewFlagSet("preview", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), previewUsage)
+		flags.PrintDefaults()
+	}
+	filename := flags.String("f", "", "Manifest of the workload, - for stdin.")
+	namespace := flags.String("n", "", "Namespace of the workload. Defaults to the namespace in the manifest.")
+	vpaName := flags.String("vpa", "", "Name of the VPA to preview. Defaults to the VPA matching the workload.")
+	server := flags.String("server", "https://localhost:8000", "Address of the VPA admission controller webhook server.")
+	token := flags.String("token", "", "Bearer token to authenticate with. Defaults to the token of the current kubeconfig context.")
+	caFile := flags.String("ca-file", "", "CA certificate to verify the webhook server certificate with.")
+	insecure := flags.Bool("insecure-skip-tls-verify", false, "If true, the webhook server certificate is not verified.")
+	output := flags.String("o", "diff", "Output format, one of: diff, json.")
+	timeout := flags.Duration("timeout", 30*time.Second, "Timeout of the preview request.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *filename == "" {
+		flags.Usage()
+		return fmt.Errorf("-f is required")
+	}
+	if *output != "diff" && *output != "json" {
+		return fmt.Errorf("unsupported output format %q", *output)
+	}
+
+	data, err := readInput(*filename, stdin)
+	if err != nil {
+		return err
+	}
+	req, err := buildPreviewRequest(data)
+	if err != nil {
+		return err
+	}
+	if *namespace != "" {
+		req.Namespace = *namespace
+	}
+	req.VPAName = *vpaName
+
+	client, err := newPreviewClient(*token, *caFile, *insecure, *timeout)
+	if err != nil {
+		return err
+	}
+	resp, err := requestPreview(client, *server, req)
+	if err != nil {
+		return err
+	}
+	if *output == "json" {
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(resp)
+	}
+	if resp.VPAName != "" {
+		fmt.Fprintf(stdout, "VPA %s (update mode %s)\n", resp.VPAName, resp.UpdateMode)
+	}
+	fmt.Fprint(stdout, resp.Diff)
+	return nil
+}
+
+// buildPreviewRequest builds a preview request from a manifest. Pod templates
+// of workloads get an owner reference to the workload, so the admission
+// controller can match them to a VPA the same way it matches their Pods.
+func buildPreviewRequest(data []byte) (*preview.Request, error) {
+	obj := manifest{}
+	if err := yaml.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %v", err)
+	}
+	req := &preview.Request{Namespace: obj.Namespace}
+	switch obj.Kind {
+	case "Pod":
+		p := &corev1.Pod{}
+		if err := yaml.Unmarshal(data, p); err != nil {
+			return nil, fmt.Errorf("failed to parse pod: %v", err)
+		}
+		req.Pod = p
+		return req, nil
+	case "PodTemplate":
+		req.PodTemplate = obj.Template
+	case "CronJob":
+		if obj.Spec.JobTemplate != nil {
+			req.PodTemplate = obj.Spec.JobTemplate.Spec.Template
+		}
+	default:
+		req.PodTemplate = obj.Spec.Template
+	}
+	if req.PodTemplate == nil {
+		return nil, fmt.Errorf("%s %q has no pod template", obj.Kind, obj.Name)
+	}
+	if obj.Kind != "PodTemplate" {
+		req.PodTemplate.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: obj.APIVersion,
+			Kind:       obj.Kind,
+			Name:       obj.Name,
+			Controller: ptr.To(true),
+		}}
+	}
+	return req, nil
+}
+
+// newPreviewClient returns an HTTP client authenticating with the token, or with
+// the credentials of the current kubeconfig context if the token is empty.
+func newPreviewClient(token, caFile string, insecure bool, timeout time.Duration) (*http.Client, error) {
+	config := &rest.Config{BearerToken: token}
+	if token == "" {
+		loaded, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+		}
+		config = rest.CopyConfig(loaded)
+	}
+	// Verify the certificate of the admission controller, not the one of the
+	// API server, and present the client certificate of the kubeconfig.
+	config.TLSClientConfig = rest.TLSClientConfig{
+		CertFile: config.CertFile,
+		KeyFile:  config.KeyFile,
+		CertData: config.CertData,
+		KeyData:  config.KeyData,
+		CAFile:   caFile,
+		Insecure: insecure,
+	}
+	config.Timeout = timeout
+	return rest.HTTPClientFor(config)
+}
+
+func requestPreview(client *http.Client, server string, req *preview.Request) (*preview.Response, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	url := strings.TrimSuffix(server, "/") + preview.Path
+	httpResp, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("preview request failed with %s: %s", httpResp.Status, strings.TrimSpace(string(respBody)))
+	}
+	resp := &preview.Response{}
+	if err := json.Unmarshal(respBody, resp); err != nil {
+		return nil, fmt.Errorf("failed to parse preview response: %v", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,184 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	certutil "k8s.io/client-go/util/cert"
+
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/preview"
+)
+
+const deploymentManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+  namespace: default
+spec:
+  template:
+    metadata:
+      labels:
+        app: app
+    spec:
+      containers:
+      - name: app
+        image: app
+`
+
+const cronJobManifest = `
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: job
+  namespace: default
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+          - name: job
+            image: job
+`
+
+const podManifest = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: pod
+  namespace: default
+spec:
+  containers:
+  - name: app
+    image: app
+`
+
+func TestBuildPreviewRequest(t *testing.T) {
+	req, err := buildPreviewRequest([]byte(deploymentManifest))
+	require.NoError(t, err)
+	assert.Equal(t, "default", req.Namespace)
+	assert.Nil(t, req.Pod)
+	require.NotNil(t, req.PodTemplate)
+	assert.Equal(t, "app", req.PodTemplate.Spec.Containers[0].Name)
+	require.Len(t, req.PodTemplate.OwnerReferences, 1)
+	owner := req.PodTemplate.OwnerReferences[0]
+	assert.Equal(t, "apps/v1", owner.APIVersion)
+	assert.Equal(t, "Deployment", owner.Kind)
+	assert.Equal(t, "app", owner.Name)
+	assert.True(t, *owner.Controller)
+
+	req, err = buildPreviewRequest([]byte(cronJobManifest))
+	require.NoError(t, err)
+	require.NotNil(t, req.PodTemplate)
+	assert.Equal(t, "job", req.PodTemplate.Spec.Containers[0].Name)
+	assert.Equal(t, "CronJob", req.PodTemplate.OwnerReferences[0].Kind)
+
+	req, err = buildPreviewRequest([]byte(podManifest))
+	require.NoError(t, err)
+	require.NotNil(t, req.Pod)
+	assert.Nil(t, req.PodTemplate)
+	assert.Equal(t, "pod", req.Pod.Name)
+
+	_, err = buildPreviewRequest([]byte("kind: ConfigMap\nmetadata:\n  name: cm\n"))
+	assert.Error(t, err)
+}
+
+func TestRunPreview(t *testing.T) {
+	var received preview.Request
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, preview.Path, r.URL.Path)
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		assert.NoError(t, json.NewEncoder(w).Encode(preview.Response{
+			VPAName:    "vpa",
+			UpdateMode: "Off",
+			Diff:       "container \"app\":\n  requests.cpu: 100m -> 250m\n",
+		}))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	err := runPreview([]string{"-f", "-", "-n", "other", "-vpa", "vpa", "-server", server.URL, "-token", "token", "-insecure-skip-tls-verify"}, strings.NewReader(deploymentManifest), &out)
+
+	require.NoError(t, err)
+	assert.Equal(t, "other", received.Namespace)
+	assert.Equal(t, "vpa", received.VPAName)
+	assert.Equal(t, "VPA vpa (update mode Off)\ncontainer \"app\":\n  requests.cpu: 100m -> 250m\n", out.String())
+}
+
+func TestRunPreviewClientCertificate(t *testing.T) {
+	clientCert, clientKey, err := certutil.GenerateSelfSignedCertKey("alice", nil, nil)
+	require.NoError(t, err)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Authorization"))
+		if assert.NotEmpty(t, r.TLS.PeerCertificates) {
+			assert.True(t, strings.HasPrefix(r.TLS.PeerCertificates[0].Subject.CommonName, "alice"), "unexpected client certificate %q", r.TLS.PeerCertificates[0].Subject.CommonName)
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(preview.Response{VPAName: "vpa", UpdateMode: "Off"}))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	// The certificate authority of the API server must not be used to verify
+	// the admission controller.
+	kubeconfig := filepath.Join(dir, "kubeconfig")
+	require.NoError(t, os.WriteFile(kubeconfig, []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: https://apiserver.example.com
+    certificate-authority-data: %s
+users:
+- name: alice
+  user:
+    client-certificate-data: %s
+    client-key-data: %s
+contexts:
+- name: alice
+  context:
+    cluster: cluster
+    user: alice
+current-context: alice
+`, base64.StdEncoding.EncodeToString(clientCert), base64.StdEncoding.EncodeToString(clientCert), base64.StdEncoding.EncodeToString(clientKey))), 0600))
+	t.Setenv("KUBECONFIG", kubeconfig)
+
+	var out bytes.Buffer
+	err = runPreview([]string{"-f", "-", "-server", server.URL, "-ca-file", caFile}, strings.NewReader(deploymentManifest), &out)
+
+	require.NoError(t, err)
+	assert.Equal(t, "VPA vpa (update mode Off)\n", out.String())
+}
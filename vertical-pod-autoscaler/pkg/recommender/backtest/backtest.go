@@ -0,0 +1,310 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backtest replays the historical usage of containers through the
+// recommender model and estimators, to compare the quality of recommendations
+// of several recommender configurations.
+package backtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	recommender_config "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/config"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/logic"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+	metrics_quality "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/metrics/quality"
+)
+
+// Configuration is a set of recommender parameters to backtest.
+type Configuration struct {
+	Name                              string          `json:"name"`
+	SafetyMarginFraction              float64         `json:"safetyMarginFraction"`
+	PodMinCPUMillicores               float64         `json:"podMinCPUMillicores"`
+	PodMinMemoryMb                    float64         `json:"podMinMemoryMb"`
+	TargetCPUPercentile               float64         `json:"targetCPUPercentile"`
+	TargetMemoryPercentile            float64         `json:"targetMemoryPercentile"`
+	CPUEstimator                      string          `json:"cpuEstimator"`
+	CPUHistogramDecayHalfLife         metav1.Duration `json:"cpuHistogramDecayHalfLife"`
+	MemoryHistogramDecayHalfLife      metav1.Duration `json:"memoryHistogramDecayHalfLife"`
+	SeasonalCPUHistogramDecayHalfLife metav1.Duration `json:"seasonalCPUHistogramDecayHalfLife"`
+	MemoryAggregationInterval         metav1.Duration `json:"memoryAggregationInterval"`
+	MemoryAggregationIntervalCount    int64           `json:"memoryAggregationIntervalCount"`
+}
+
+// DefaultConfiguration returns the configuration with the defaults of the
+// recommender flags.
+func DefaultConfiguration() Configuration {
+	defaults := recommender_config.DefaultRecommenderConfig()
+	return Configuration{
+		Name:                              "default",
+		SafetyMarginFraction:              defaults.SafetyMarginFraction,
+		PodMinCPUMillicores:               defaults.PodMinCPUMillicores,
+		PodMinMemoryMb:                    defaults.PodMinMemoryMb,
+		TargetCPUPercentile:               defaults.TargetCPUPercentile,
+		TargetMemoryPercentile:            defaults.TargetMemoryPercentile,
+		CPUEstimator:                      defaults.CPUEstimator,
+		CPUHistogramDecayHalfLife:         metav1.Duration{Duration: defaults.CpuHistogramDecayHalfLife},
+		MemoryHistogramDecayHalfLife:      metav1.Duration{Duration: defaults.MemoryHistogramDecayHalfLife},
+		SeasonalCPUHistogramDecayHalfLife: metav1.Duration{Duration: defaults.SeasonalCpuHistogramDecayHalfLife},
+		MemoryAggregationInterval:         metav1.Duration{Duration: defaults.MemoryAggregationInterval},
+		MemoryAggregationIntervalCount:    defaults.MemoryAggregationIntervalCount,
+	}
+}
+
+// ReadConfigurations parses a YAML or JSON list of configurations. Parameters
+// missing from a configuration keep their default value.
+func ReadConfigurations(data []byte) ([]Configuration, error) {
+	var items []json.RawMessage
+	if err := yaml.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	configurations := make([]Configuration, 0, len(items))
+	names := make(map[string]bool)
+	for i, item := range items {
+		configuration := DefaultConfiguration()
+		configuration.Name = ""
+		if err := json.Unmarshal(item, &configuration); err != nil {
+			return nil, fmt.Errorf("configuration %d: %w", i, err)
+		}
+		if configuration.Name == "" {
+			return nil, fmt.Errorf("configuration %d: missing name", i)
+		}
+		if names[configuration.Name] {
+			return nil, fmt.Errorf("configuration %d: duplicate name %q", i, configuration.Name)
+		}
+		if configuration.CPUEstimator != logic.PercentileCPUEstimatorName && configuration.CPUEstimator != logic.SeasonalCPUEstimatorName {
+			return nil, fmt.Errorf("configuration %q: unsupported cpuEstimator %q", configuration.Name, configuration.CPUEstimator)
+		}
+		names[configuration.Name] = true
+		configurations = append(configurations, configuration)
+	}
+	return configurations, nil
+}
+
+func (c Configuration) recommendationConfig() logic.RecommendationConfig {
+	defaults := recommender_config.DefaultRecommenderConfig()
+	return logic.RecommendationConfig{
+		SafetyMarginFraction:       c.SafetyMarginFraction,
+		PodMinCPUMillicores:        c.PodMinCPUMillicores,
+		PodMinMemoryMb:             c.PodMinMemoryMb,
+		TargetCPUPercentile:        c.TargetCPUPercentile,
+		LowerBoundCPUPercentile:    defaults.LowerBoundCPUPercentile,
+		UpperBoundCPUPercentile:    defaults.UpperBoundCPUPercentile,
+		ConfidenceIntervalCPU:      defaults.ConfidenceIntervalCPU,
+		TargetMemoryPercentile:     c.TargetMemoryPercentile,
+		LowerBoundMemoryPercentile: defaults.LowerBoundMemoryPercentile,
+		UpperBoundMemoryPercentile: defaults.UpperBoundMemoryPercentile,
+		ConfidenceIntervalMemory:   defaults.ConfidenceIntervalMemory,
+		CPUEstimator:               c.CPUEstimator,
+	}
+}
+
+func (c Configuration) aggregationsConfig() *model.AggregationsConfig {
+	defaults := recommender_config.DefaultRecommenderConfig()
+	config := model.NewAggregationsConfig(
+		c.MemoryAggregationInterval.Duration,
+		c.MemoryAggregationIntervalCount,
+		c.MemoryHistogramDecayHalfLife.Duration,
+		c.CPUHistogramDecayHalfLife.Duration,
+		defaults.OOMBumpUpRatio,
+		defaults.OOMMinBumpUp,
+	)
+	config.SeasonalCPUHistograms = c.CPUEstimator == logic.SeasonalCPUEstimatorName
+	config.SeasonalCPUHistogramDecayHalfLife = c.SeasonalCPUHistogramDecayHalfLife.Duration
+	return config
+}
+
+// Options control how usage is replayed.
+type Options struct {
+	// RecommendationInterval is how often recommendations are computed. A
+	// recommendation applies to the usage until the next one.
+	RecommendationInterval time.Duration
+	// Warmup is how long after the first sample of a workload its
+	// recommendations start being compared with usage.
+	Warmup time.Duration
+}
+
+// Result is the quality of the recommendations of a configuration.
+type Result struct {
+	Configuration string         `json:"configuration"`
+	CPU           ResourceResult `json:"cpu"`
+	Memory        ResourceResult `json:"memory"`
+}
+
+// ResourceResult is the quality of the recommendations of a resource. CPU is
+// in cores and memory in bytes.
+type ResourceResult struct {
+	// Samples is the number of usage samples compared with a recommendation.
+	Samples int `json:"samples"`
+	// SamplesNotOverUsage is the number of usage samples at or above the
+	// recommended target, which risk CPU throttling or OOMs.
+	SamplesNotOverUsage int `json:"samplesNotOverUsage"`
+	// MeanRelativeDiff is the mean diff between usage and target, normalized
+	// by the target.
+	MeanRelativeDiff float64 `json:"meanRelativeDiff"`
+	// Requested is the average total target of the running containers.
+	Requested float64 `json:"requested"`
+	// Used is the average total usage of the running containers.
+	Used float64 `json:"used"`
+
+	relativeDiffSum  float64
+	relativeDiffs    int
+	targetSum        float64
+	usageSum         float64
+	sampleTimestamps map[time.Time]bool
+}
+
+// Risk returns the fraction of usage samples at or above the target.
+func (r ResourceResult) Risk() float64 {
+	if r.Samples == 0 {
+		return 0
+	}
+	return float64(r.SamplesNotOverUsage) / float64(r.Samples)
+}
+
+func (r *ResourceResult) add(usage, target float64, timestamp time.Time) {
+	r.Samples++
+	if !metrics_quality.RecommendationOverUsage(usage, target) {
+		r.SamplesNotOverUsage++
+	}
+	if diff, ok := metrics_quality.UsageRecommendationRelativeDiff(usage, target); ok {
+		r.relativeDiffSum += diff
+		r.relativeDiffs++
+	}
+	r.targetSum += target
+	r.usageSum += usage
+	if r.sampleTimestamps == nil {
+		r.sampleTimestamps = make(map[time.Time]bool)
+	}
+	r.sampleTimestamps[timestamp] = true
+}
+
+func (r *ResourceResult) finish() {
+	if r.relativeDiffs > 0 {
+		r.MeanRelativeDiff = r.relativeDiffSum / float64(r.relativeDiffs)
+	}
+	// Samples are taken at the same timestamps for all containers, the
+	// resolution of the history, so the totals at each timestamp are averaged.
+	if timestamps := len(r.sampleTimestamps); timestamps > 0 {
+		r.Requested = r.targetSum / float64(timestamps)
+		r.Used = r.usageSum / float64(timestamps)
+	}
+}
+
+type workloadID struct {
+	namespace string
+	name      string
+}
+
+// usageEvent is a usage sample of a container to replay.
+type usageEvent struct {
+	sample        model.ContainerUsageSample
+	containerName string
+	container     *model.ContainerState
+}
+
+// Run replays the usage of containers with each configuration. Before the
+// usage of a sample is added to the model, it is compared with the target
+// recommended for its container.
+//
+// Run changes the global aggregations config of the model package, so it must
+// not run alongside other users of the model.
+func Run(containers []ContainerHistory, configurations []Configuration, options Options) []Result {
+	workloads := make(map[workloadID][]ContainerHistory)
+	for _, container := range containers {
+		id := workloadID{namespace: container.Namespace, name: container.Workload}
+		workloads[id] = append(workloads[id], container)
+	}
+
+	results := make([]Result, 0, len(configurations))
+	for _, configuration := range configurations {
+		model.InitializeAggregationsConfig(configuration.aggregationsConfig())
+		recommender := logic.CreatePodResourceRecommender(configuration.recommendationConfig())
+		result := Result{Configuration: configuration.Name}
+		for _, workloadContainers := range workloads {
+			replayWorkload(workloadContainers, recommender, options, &result)
+		}
+		result.CPU.finish()
+		result.Memory.finish()
+		results = append(results, result)
+	}
+	return results
+}
+
+func replayWorkload(containers []ContainerHistory, recommender logic.PodResourceRecommender, options Options, result *Result) {
+	aggregates := make(model.ContainerNameToAggregateStateMap)
+	var events []usageEvent
+	for _, container := range containers {
+		aggregate, found := aggregates[container.Container]
+		if !found {
+			aggregate = model.NewAggregateContainerState()
+			aggregates[container.Container] = aggregate
+		}
+		containerState := model.NewContainerState(nil, aggregate)
+		for _, sample := range container.CPU {
+			events = append(events, usageEvent{
+				sample:        model.ContainerUsageSample{MeasureStart: sample.Time, Usage: model.CPUAmountFromCores(sample.Value), Resource: model.ResourceCPU},
+				containerName: container.Container,
+				container:     containerState,
+			})
+		}
+		for _, sample := range container.Memory {
+			events = append(events, usageEvent{
+				sample:        model.ContainerUsageSample{MeasureStart: sample.Time, Usage: model.MemoryAmountFromBytes(sample.Value), Resource: model.ResourceMemory},
+				containerName: container.Container,
+				container:     containerState,
+			})
+		}
+	}
+	if len(events) == 0 {
+		return
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].sample.MeasureStart.Before(events[j].sample.MeasureStart)
+	})
+
+	var recommendation logic.RecommendedPodResources
+	nextRecommendation := events[0].sample.MeasureStart.Add(options.Warmup)
+	for i := range events {
+		event := &events[i]
+		timestamp := event.sample.MeasureStart
+		if !timestamp.Before(nextRecommendation) {
+			recommendation = recommender.GetRecommendedPodResources(aggregates)
+			if options.RecommendationInterval > 0 {
+				intervals := timestamp.Sub(nextRecommendation)/options.RecommendationInterval + 1
+				nextRecommendation = nextRecommendation.Add(intervals * options.RecommendationInterval)
+			} else {
+				// Recommendations are computed at every sample timestamp.
+				nextRecommendation = timestamp.Add(time.Nanosecond)
+			}
+		}
+		if target, found := recommendation[event.containerName].Target[event.sample.Resource]; found {
+			switch event.sample.Resource {
+			case model.ResourceCPU:
+				result.CPU.add(model.CoresFromCPUAmount(event.sample.Usage), model.CoresFromCPUAmount(target), timestamp)
+			case model.ResourceMemory:
+				result.Memory.add(model.BytesFromMemoryAmount(event.sample.Usage), model.BytesFromMemoryAmount(target), timestamp)
+			}
+		}
+		event.container.AddSample(&event.sample)
+	}
+}
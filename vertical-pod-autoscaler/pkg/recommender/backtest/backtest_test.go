@@ -0,0 +1,145 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/history"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+)
+
+var testStart = time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+
+// makeContainer returns the history of a container alternately using 0.4 and
+// 0.6 cores, and 100MB of memory, sampled every 10 minutes.
+func makeContainer(workload, pod string, samples int) ContainerHistory {
+	container := ContainerHistory{Namespace: "default", Workload: workload, Pod: pod, Container: "app"}
+	for i := range samples {
+		timestamp := testStart.Add(time.Duration(i) * 10 * time.Minute)
+		cpu := 0.4
+		if i%2 == 1 {
+			cpu = 0.6
+		}
+		container.CPU = append(container.CPU, UsageSample{Time: timestamp, Value: cpu})
+		container.Memory = append(container.Memory, UsageSample{Time: timestamp, Value: 100e6})
+	}
+	return container
+}
+
+func TestRun(t *testing.T) {
+	defer model.InitializeAggregationsConfig(model.NewAggregationsConfig(model.DefaultMemoryAggregationInterval, model.DefaultMemoryAggregationIntervalCount,
+		model.DefaultMemoryHistogramDecayHalfLife, model.DefaultCPUHistogramDecayHalfLife, model.DefaultOOMBumpUpRatio, model.DefaultOOMMinBumpUp))
+
+	containers := []ContainerHistory{
+		makeContainer("web", "web-1", 3*24*6),
+		makeContainer("web", "web-2", 3*24*6),
+	}
+	tight := DefaultConfiguration()
+	tight.Name = "tight"
+	tight.TargetCPUPercentile = 0.3
+	tight.SafetyMarginFraction = 0
+	configurations := []Configuration{DefaultConfiguration(), tight}
+
+	results := Run(containers, configurations, Options{RecommendationInterval: time.Hour, Warmup: 24 * time.Hour})
+
+	assert.Len(t, results, 2)
+	defaultResult, tightResult := results[0], results[1]
+	assert.Equal(t, "default", defaultResult.Configuration)
+	assert.Equal(t, "tight", tightResult.Configuration)
+
+	// Samples of the first day are only used to build the model.
+	assert.Equal(t, 2*2*24*6, defaultResult.CPU.Samples)
+	assert.Equal(t, 2*2*24*6, defaultResult.Memory.Samples)
+	// The 90th percentile with a margin covers all usage, the 30th percentile
+	// without margin only covers the lower half.
+	assert.Zero(t, defaultResult.CPU.SamplesNotOverUsage)
+	assert.Zero(t, defaultResult.CPU.Risk())
+	assert.InDelta(t, 0.5, tightResult.CPU.Risk(), 0.001)
+	assert.Greater(t, defaultResult.CPU.Requested, tightResult.CPU.Requested)
+	assert.Less(t, defaultResult.CPU.MeanRelativeDiff, tightResult.CPU.MeanRelativeDiff)
+	assert.InDelta(t, 2*0.5, defaultResult.CPU.Used, 0.001)
+	assert.Zero(t, defaultResult.Memory.SamplesNotOverUsage)
+	assert.InDelta(t, 2*100e6, defaultResult.Memory.Used, 1)
+	assert.Greater(t, defaultResult.Memory.Requested, defaultResult.Memory.Used)
+}
+
+func TestRunWarmupLongerThanHistory(t *testing.T) {
+	results := Run([]ContainerHistory{makeContainer("web", "web-1", 6)}, []Configuration{DefaultConfiguration()}, Options{RecommendationInterval: time.Hour, Warmup: 24 * time.Hour})
+
+	assert.Len(t, results, 1)
+	assert.Zero(t, results[0].CPU.Samples)
+	assert.Zero(t, results[0].CPU.Requested)
+	assert.Zero(t, results[0].CPU.Risk())
+}
+
+func TestReadConfigurations(t *testing.T) {
+	configurations, err := ReadConfigurations([]byte(`
+- name: default
+- name: p95
+  targetCPUPercentile: 0.95
+  cpuHistogramDecayHalfLife: 12h
+`))
+	assert.NoError(t, err)
+	p95 := DefaultConfiguration()
+	p95.Name = "p95"
+	p95.TargetCPUPercentile = 0.95
+	p95.CPUHistogramDecayHalfLife.Duration = 12 * time.Hour
+	assert.Equal(t, []Configuration{DefaultConfiguration(), p95}, configurations)
+
+	for name, data := range map[string]string{
+		"missing name":      `[{"targetCPUPercentile": 0.95}]`,
+		"duplicate name":    `[{"name": "a"}, {"name": "a"}]`,
+		"unknown estimator": `[{"name": "a", "cpuEstimator": "max"}]`,
+		"not a list":        `name: a`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadConfigurations([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestFromPodHistory(t *testing.T) {
+	pods := map[model.PodID]*history.PodHistory{
+		{Namespace: "default", PodName: "web-2"}: {
+			LastLabels: map[string]string{"app": "web"},
+			Samples: map[string][]model.ContainerUsageSample{
+				"app": {
+					{MeasureStart: testStart, Usage: model.CPUAmountFromCores(0.5), Resource: model.ResourceCPU},
+					{MeasureStart: testStart, Usage: model.MemoryAmountFromBytes(100e6), Resource: model.ResourceMemory},
+				},
+			},
+		},
+		{Namespace: "default", PodName: "job"}: {
+			LastLabels: map[string]string{},
+			Samples: map[string][]model.ContainerUsageSample{
+				"main": {{MeasureStart: testStart, Usage: model.CPUAmountFromCores(1), Resource: model.ResourceCPU}},
+			},
+		},
+	}
+
+	containers := FromPodHistory(pods, "app")
+
+	assert.Equal(t, []ContainerHistory{
+		{Namespace: "default", Workload: "job", Pod: "job", Container: "main", CPU: []UsageSample{{Time: testStart, Value: 1}}},
+		{Namespace: "default", Workload: "web", Pod: "web-2", Container: "app", CPU: []UsageSample{{Time: testStart, Value: 0.5}}, Memory: []UsageSample{{Time: testStart, Value: 100e6}}},
+	}, containers)
+}
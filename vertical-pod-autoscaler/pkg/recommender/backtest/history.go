@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backtest
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"time"
+
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/history"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+)
+
+// ContainerHistory is the usage history of a container of a pod.
+type ContainerHistory struct {
+	Namespace string `json:"namespace"`
+	// Workload groups the containers whose usage is aggregated together,
+	// like the containers of the pods of a VPA.
+	Workload  string `json:"workload"`
+	Pod       string `json:"pod"`
+	Container string `json:"container"`
+	// CPU usage samples in cores.
+	CPU []UsageSample `json:"cpu,omitempty"`
+	// Memory usage samples in bytes.
+	Memory []UsageSample `json:"memory,omitempty"`
+}
+
+// UsageSample is the usage of a resource by a container at a point in time.
+type UsageSample struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// ReadHistory reads container histories in the JSON format written by
+// WriteHistory.
+func ReadHistory(r io.Reader) ([]ContainerHistory, error) {
+	var containers []ContainerHistory
+	if err := json.NewDecoder(r).Decode(&containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
+
+// WriteHistory writes container histories as JSON.
+func WriteHistory(w io.Writer, containers []ContainerHistory) error {
+	return json.NewEncoder(w).Encode(containers)
+}
+
+// FromPodHistory converts the history of pods returned by a
+// history.HistoryProvider. Pods are grouped into workloads by the value of
+// workloadLabel, pods without the label are workloads of their own.
+func FromPodHistory(pods map[model.PodID]*history.PodHistory, workloadLabel string) []ContainerHistory {
+	var containers []ContainerHistory
+	for podID, podHistory := range pods {
+		workload := podHistory.LastLabels[workloadLabel]
+		if workload == "" {
+			workload = podID.PodName
+		}
+		for containerName, samples := range podHistory.Samples {
+			container := ContainerHistory{
+				Namespace: podID.Namespace,
+				Workload:  workload,
+				Pod:       podID.PodName,
+				Container: containerName,
+			}
+			for _, sample := range samples {
+				switch sample.Resource {
+				case model.ResourceCPU:
+					container.CPU = append(container.CPU, UsageSample{Time: sample.MeasureStart, Value: model.CoresFromCPUAmount(sample.Usage)})
+				case model.ResourceMemory:
+					container.Memory = append(container.Memory, UsageSample{Time: sample.MeasureStart, Value: model.BytesFromMemoryAmount(sample.Usage)})
+				}
+			}
+			containers = append(containers, container)
+		}
+	}
+	sort.Slice(containers, func(i, j int) bool {
+		a, b := containers[i], containers[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Pod != b.Pod {
+			return a.Pod < b.Pod
+		}
+		return a.Container < b.Container
+	})
+	return containers
+}
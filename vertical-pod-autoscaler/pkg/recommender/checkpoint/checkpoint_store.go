@@ -0,0 +1,78 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_api "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/typed/autoscaling.k8s.io/v1"
+	vpa_lister "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/listers/autoscaling.k8s.io/v1"
+	api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
+)
+
+const (
+	// APIStoreName is the name of the store keeping checkpoints as
+	// VerticalPodAutoscalerCheckpoint objects in the Kubernetes API.
+	APIStoreName = "api"
+	// FileStoreName is the name of the store keeping checkpoints in a local file.
+	FileStoreName = "file"
+)
+
+// CheckpointStore persistently stores VerticalPodAutoscalerCheckpoints.
+type CheckpointStore interface {
+	// List returns the checkpoints stored in the namespace, or in all
+	// namespaces if the namespace is empty.
+	List(namespace string) ([]*vpa_types.VerticalPodAutoscalerCheckpoint, error)
+	// Save creates or updates the checkpoint.
+	Save(ctx context.Context, checkpoint *vpa_types.VerticalPodAutoscalerCheckpoint) error
+	// Delete removes the checkpoint with the given namespace and name.
+	Delete(ctx context.Context, namespace, name string) error
+}
+
+type apiCheckpointStore struct {
+	vpaCheckpointClient vpa_api.VerticalPodAutoscalerCheckpointsGetter
+	vpaCheckpointLister vpa_lister.VerticalPodAutoscalerCheckpointLister
+}
+
+// NewAPICheckpointStore returns a CheckpointStore keeping checkpoints as
+// VerticalPodAutoscalerCheckpoint objects. Checkpoints are listed from the
+// lister and written with the client.
+func NewAPICheckpointStore(vpaCheckpointClient vpa_api.VerticalPodAutoscalerCheckpointsGetter, vpaCheckpointLister vpa_lister.VerticalPodAutoscalerCheckpointLister) CheckpointStore {
+	return &apiCheckpointStore{
+		vpaCheckpointClient: vpaCheckpointClient,
+		vpaCheckpointLister: vpaCheckpointLister,
+	}
+}
+
+func (s *apiCheckpointStore) List(namespace string) ([]*vpa_types.VerticalPodAutoscalerCheckpoint, error) {
+	if namespace == "" {
+		return s.vpaCheckpointLister.List(labels.Everything())
+	}
+	return s.vpaCheckpointLister.VerticalPodAutoscalerCheckpoints(namespace).List(labels.Everything())
+}
+
+func (s *apiCheckpointStore) Save(_ context.Context, checkpoint *vpa_types.VerticalPodAutoscalerCheckpoint) error {
+	return api_util.CreateOrUpdateVpaCheckpoint(s.vpaCheckpointClient.VerticalPodAutoscalerCheckpoints(checkpoint.Namespace), checkpoint)
+}
+
+func (s *apiCheckpointStore) Delete(ctx context.Context, namespace, name string) error {
+	return s.vpaCheckpointClient.VerticalPodAutoscalerCheckpoints(namespace).Delete(ctx, name, metav1.DeleteOptions{})
+}
@@ -27,9 +27,7 @@ import (
 	"k8s.io/klog/v2"
 
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
-	vpa_api "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/typed/autoscaling.k8s.io/v1"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
-	api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
 )
 
 // CheckpointWriter persistently stores aggregated historical usage of containers
@@ -41,15 +39,15 @@ type CheckpointWriter interface {
 }
 
 type checkpointWriter struct {
-	vpaCheckpointClient vpa_api.VerticalPodAutoscalerCheckpointsGetter
-	cluster             model.ClusterState
+	checkpointStore CheckpointStore
+	cluster         model.ClusterState
 }
 
 // NewCheckpointWriter returns new instance of a CheckpointWriter
-func NewCheckpointWriter(cluster model.ClusterState, vpaCheckpointClient vpa_api.VerticalPodAutoscalerCheckpointsGetter) CheckpointWriter {
+func NewCheckpointWriter(cluster model.ClusterState, checkpointStore CheckpointStore) CheckpointWriter {
 	return &checkpointWriter{
-		vpaCheckpointClient: vpaCheckpointClient,
-		cluster:             cluster,
+		checkpointStore: checkpointStore,
+		cluster:         cluster,
 	}
 }
 
@@ -72,7 +70,7 @@ func getVpasToCheckpoint(clusterVpas map[model.VpaID]*model.Vpa) []*model.Vpa {
 	return vpas
 }
 
-func processCheckpointUpdateForVPA(vpa *model.Vpa, writer *checkpointWriter) {
+func processCheckpointUpdateForVPA(ctx context.Context, vpa *model.Vpa, writer *checkpointWriter) {
 	now := time.Now()
 	aggregateContainerStateMap := buildAggregateContainerStateMap(vpa, writer.cluster, now)
 	for container, aggregatedContainerState := range aggregateContainerStateMap {
@@ -83,14 +81,14 @@ func processCheckpointUpdateForVPA(vpa *model.Vpa, writer *checkpointWriter) {
 		}
 		checkpointName := fmt.Sprintf("%s-%s", vpa.ID.VpaName, container)
 		vpaCheckpoint := vpa_types.VerticalPodAutoscalerCheckpoint{
-			ObjectMeta: metav1.ObjectMeta{Name: checkpointName},
+			ObjectMeta: metav1.ObjectMeta{Namespace: vpa.ID.Namespace, Name: checkpointName},
 			Spec: vpa_types.VerticalPodAutoscalerCheckpointSpec{
 				ContainerName: container,
 				VPAObjectName: vpa.ID.VpaName,
 			},
 			Status: *containerCheckpoint,
 		}
-		err = api_util.CreateOrUpdateVpaCheckpoint(writer.vpaCheckpointClient.VerticalPodAutoscalerCheckpoints(vpa.ID.Namespace), &vpaCheckpoint)
+		err = writer.checkpointStore.Save(ctx, &vpaCheckpoint)
 		if err != nil {
 			klog.ErrorS(err, "Cannot save checkpoint for VPA", "vpa", klog.KRef(vpa.ID.Namespace, vpaCheckpoint.Spec.VPAObjectName), "container", vpaCheckpoint.Spec.ContainerName)
 		} else {
@@ -112,7 +110,7 @@ func (writer *checkpointWriter) StoreCheckpoints(ctx context.Context, concurrent
 	for range concurrentWorkers {
 		wg.Go(func() {
 			for vpaToCheckpoint := range vpaCheckpointUpdates {
-				processCheckpointUpdateForVPA(vpaToCheckpoint, writer)
+				processCheckpointUpdateForVPA(ctx, vpaToCheckpoint, writer)
 				select {
 				case <-ctx.Done():
 					return
@@ -236,7 +236,7 @@ func TestStoreCheckpointsMakesProgressEvenForCancelledContext(t *testing.T) {
 		return true, nil, nil
 	})
 
-	writer := NewCheckpointWriter(clusterState, checkpointClient)
+	writer := NewCheckpointWriter(clusterState, NewAPICheckpointStore(checkpointClient, nil))
 	writer.StoreCheckpoints(ctx, concurrentWorkers)
 
 	// Because we have 2 concurrent workers, expect 2 VPAs to get processed. Each worker picks a VPA to process before checking if the context has been cancelled.
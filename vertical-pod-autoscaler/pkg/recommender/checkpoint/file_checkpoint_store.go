@@ -0,0 +1,235 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"k8s.io/klog/v2"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+const (
+	// The log is compacted once it holds compactionFactor times more records
+	// than there are checkpoints, and at least minCompactionRecords records.
+	compactionFactor     = 2
+	minCompactionRecords = 1000
+)
+
+type fileStoreKey struct {
+	namespace string
+	name      string
+}
+
+// fileStoreRecord is a line of the checkpoint log.
+type fileStoreRecord struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	// Checkpoint is nil if the checkpoint was deleted.
+	Checkpoint *vpa_types.VerticalPodAutoscalerCheckpoint `json:"checkpoint,omitempty"`
+}
+
+// fileCheckpointStore keeps checkpoints in memory and persists them to an
+// append-only log of JSON records, one per line, which is rewritten with only
+// the current checkpoints when it grows too large.
+type fileCheckpointStore struct {
+	path        string
+	mutex       sync.Mutex
+	file        *os.File
+	records     int
+	checkpoints map[fileStoreKey]*vpa_types.VerticalPodAutoscalerCheckpoint
+}
+
+// NewFileCheckpointStore returns a CheckpointStore keeping checkpoints in the
+// file at path, e.g. on a PersistentVolume, loading the checkpoints already
+// stored there.
+func NewFileCheckpointStore(path string) (CheckpointStore, error) {
+	s := &fileCheckpointStore{
+		path:        path,
+		checkpoints: make(map[fileStoreKey]*vpa_types.VerticalPodAutoscalerCheckpoint),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	// Compacting right away also drops a record partially written before a crash,
+	// which would otherwise corrupt the next record appended to the log.
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileCheckpointStore) load() error {
+	file, err := os.Open(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("cannot open checkpoint file %s: %w", s.path, err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			var record fileStoreRecord
+			if decodeErr := json.Unmarshal(data, &record); decodeErr != nil {
+				klog.ErrorS(decodeErr, "Skipping invalid record in checkpoint file", "file", s.path, "line", line)
+			} else {
+				s.apply(record)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("cannot read checkpoint file %s: %w", s.path, err)
+		}
+	}
+}
+
+func (s *fileCheckpointStore) apply(record fileStoreRecord) {
+	key := fileStoreKey{namespace: record.Namespace, name: record.Name}
+	if record.Checkpoint == nil {
+		delete(s.checkpoints, key)
+	} else {
+		s.checkpoints[key] = record.Checkpoint
+	}
+}
+
+// compact rewrites the log with a record per checkpoint and reopens it for
+// appending.
+func (s *fileCheckpointStore) compact() error {
+	if s.file != nil {
+		if err := s.file.Close(); err != nil {
+			klog.ErrorS(err, "Cannot close checkpoint file", "file", s.path)
+		}
+		s.file = nil
+	}
+
+	tmpPath := s.path + ".tmp"
+	tmpFile, err := os.Create(tmpPath)
+	if err != nil {
+		return fmt.Errorf("cannot create checkpoint file %s: %w", tmpPath, err)
+	}
+	writer := bufio.NewWriter(tmpFile)
+	encoder := json.NewEncoder(writer)
+	for key, checkpoint := range s.checkpoints {
+		if err := encoder.Encode(fileStoreRecord{Namespace: key.namespace, Name: key.name, Checkpoint: checkpoint}); err != nil {
+			tmpFile.Close()
+			return fmt.Errorf("cannot write checkpoint file %s: %w", tmpPath, err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("cannot write checkpoint file %s: %w", tmpPath, err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("cannot sync checkpoint file %s: %w", tmpPath, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("cannot close checkpoint file %s: %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		return fmt.Errorf("cannot replace checkpoint file %s: %w", s.path, err)
+	}
+	if dir, err := os.Open(filepath.Dir(s.path)); err == nil {
+		if err := dir.Sync(); err != nil {
+			klog.V(4).InfoS("Cannot sync checkpoint directory", "dir", dir.Name(), "err", err)
+		}
+		dir.Close()
+	}
+
+	s.file, err = os.OpenFile(s.path, os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("cannot open checkpoint file %s: %w", s.path, err)
+	}
+	s.records = len(s.checkpoints)
+	klog.V(3).InfoS("Compacted checkpoint file", "file", s.path, "checkpoints", len(s.checkpoints))
+	return nil
+}
+
+// append writes the record to the log and applies it to the checkpoints kept
+// in memory, compacting the log if needed.
+func (s *fileCheckpointStore) append(record fileStoreRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("cannot marshal checkpoint %s/%s: %w", record.Namespace, record.Name, err)
+	}
+	if s.file == nil {
+		// A previous compaction failed, retry it before writing.
+		if err := s.compact(); err != nil {
+			return err
+		}
+	}
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		// The record may be partially written, rewrite the log before the next write.
+		s.file.Close()
+		s.file = nil
+		return fmt.Errorf("cannot write checkpoint %s/%s to %s: %w", record.Namespace, record.Name, s.path, err)
+	}
+	s.apply(record)
+	s.records++
+	if s.records > compactionFactor*max(len(s.checkpoints), minCompactionRecords) {
+		if err := s.compact(); err != nil {
+			klog.ErrorS(err, "Cannot compact checkpoint file", "file", s.path)
+		}
+	}
+	return nil
+}
+
+func (s *fileCheckpointStore) List(namespace string) ([]*vpa_types.VerticalPodAutoscalerCheckpoint, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	var checkpoints []*vpa_types.VerticalPodAutoscalerCheckpoint
+	for key, checkpoint := range s.checkpoints {
+		if namespace == "" || key.namespace == namespace {
+			checkpoints = append(checkpoints, checkpoint)
+		}
+	}
+	return checkpoints, nil
+}
+
+func (s *fileCheckpointStore) Save(_ context.Context, checkpoint *vpa_types.VerticalPodAutoscalerCheckpoint) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.append(fileStoreRecord{Namespace: checkpoint.Namespace, Name: checkpoint.Name, Checkpoint: checkpoint.DeepCopy()})
+}
+
+func (s *fileCheckpointStore) Delete(_ context.Context, namespace, name string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, found := s.checkpoints[fileStoreKey{namespace: namespace, name: name}]; !found {
+		return nil
+	}
+	return s.append(fileStoreRecord{Namespace: namespace, Name: name})
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+func makeCheckpoint(namespace, name string, totalSamplesCount int) *vpa_types.VerticalPodAutoscalerCheckpoint {
+	return &vpa_types.VerticalPodAutoscalerCheckpoint{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Spec: vpa_types.VerticalPodAutoscalerCheckpointSpec{
+			VPAObjectName: name,
+			ContainerName: "container",
+		},
+		Status: vpa_types.VerticalPodAutoscalerCheckpointStatus{
+			TotalSamplesCount: totalSamplesCount,
+		},
+	}
+}
+
+func checkpointNames(checkpoints []*vpa_types.VerticalPodAutoscalerCheckpoint) []string {
+	names := make([]string, 0, len(checkpoints))
+	for _, checkpoint := range checkpoints {
+		names = append(names, fmt.Sprintf("%s/%s:%d", checkpoint.Namespace, checkpoint.Name, checkpoint.Status.TotalSamplesCount))
+	}
+	return names
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "checkpoints")
+
+	store, err := NewFileCheckpointStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(ctx, makeCheckpoint("ns1", "a", 1)))
+	assert.NoError(t, store.Save(ctx, makeCheckpoint("ns1", "b", 1)))
+	assert.NoError(t, store.Save(ctx, makeCheckpoint("ns2", "a", 1)))
+	assert.NoError(t, store.Save(ctx, makeCheckpoint("ns1", "a", 2)))
+	assert.NoError(t, store.Delete(ctx, "ns1", "b"))
+	assert.NoError(t, store.Delete(ctx, "ns1", "missing"))
+
+	checkpoints, err := store.List("")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ns1/a:2", "ns2/a:1"}, checkpointNames(checkpoints))
+	checkpoints, err = store.List("ns2")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ns2/a:1"}, checkpointNames(checkpoints))
+
+	// A new store, e.g. after a restart, loads the checkpoints from the file.
+	reopened, err := NewFileCheckpointStore(path)
+	assert.NoError(t, err)
+	checkpoints, err = reopened.List("")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ns1/a:2", "ns2/a:1"}, checkpointNames(checkpoints))
+}
+
+func TestFileCheckpointStoreIgnoresPartialRecord(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "checkpoints")
+
+	store, err := NewFileCheckpointStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Save(ctx, makeCheckpoint("ns", "a", 1)))
+
+	// Simulate a crash in the middle of writing a record.
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o600)
+	assert.NoError(t, err)
+	_, err = file.WriteString(`{"namespace":"ns","name":"b","checkpoint":{"metad`)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	reopened, err := NewFileCheckpointStore(path)
+	assert.NoError(t, err)
+	assert.NoError(t, reopened.Save(ctx, makeCheckpoint("ns", "c", 1)))
+
+	reopened, err = NewFileCheckpointStore(path)
+	assert.NoError(t, err)
+	checkpoints, err := reopened.List("ns")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ns/a:1", "ns/c:1"}, checkpointNames(checkpoints))
+}
+
+func TestFileCheckpointStoreCompaction(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "checkpoints")
+
+	store, err := NewFileCheckpointStore(path)
+	assert.NoError(t, err)
+	for i := range 3 * minCompactionRecords {
+		assert.NoError(t, store.Save(ctx, makeCheckpoint("ns", fmt.Sprintf("vpa-%d", i%10), i)))
+	}
+
+	fileStore := store.(*fileCheckpointStore)
+	assert.LessOrEqual(t, fileStore.records, compactionFactor*minCompactionRecords)
+
+	reopened, err := NewFileCheckpointStore(path)
+	assert.NoError(t, err)
+	checkpoints, err := reopened.List("ns")
+	assert.NoError(t, err)
+	assert.Len(t, checkpoints, 10)
+	for _, checkpoint := range checkpoints {
+		assert.GreaterOrEqual(t, checkpoint.Status.TotalSamplesCount, 3*minCompactionRecords-10)
+	}
+}
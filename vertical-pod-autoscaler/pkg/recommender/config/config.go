@@ -24,12 +24,15 @@ import (
 
 	"github.com/spf13/pflag"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kube_flag "k8s.io/component-base/cli/flag"
 	"k8s.io/klog/v2"
 
 	"k8s.io/autoscaler/vertical-pod-autoscaler/common"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/checkpoint"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/logic"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
 )
 
@@ -43,6 +46,8 @@ type RecommenderConfig struct {
 	MetricsFetcherInterval  time.Duration
 	CheckpointsGCInterval   time.Duration
 	CheckpointsWriteTimeout time.Duration
+	CheckpointStore         string
+	CheckpointStorePath     string
 	Address                 string
 	Storage                 string
 	MemorySaver             bool
@@ -61,6 +66,7 @@ type RecommenderConfig struct {
 	LowerBoundMemoryPercentile float64
 	UpperBoundMemoryPercentile float64
 	ConfidenceIntervalMemory   time.Duration
+	CPUEstimator               string
 	HumanizeMemory             bool
 	RoundCPUMillicores         int
 	RoundMemoryBytes           int
@@ -92,17 +98,25 @@ type RecommenderConfig struct {
 	ExternalMemoryMetric string
 
 	// Aggregation configuration
-	MemoryAggregationInterval      time.Duration
-	MemoryAggregationIntervalCount int64
-	MemoryHistogramDecayHalfLife   time.Duration
-	CpuHistogramDecayHalfLife      time.Duration
-	OOMBumpUpRatio                 float64
-	OOMMinBumpUp                   float64
+	MemoryAggregationInterval         time.Duration
+	MemoryAggregationIntervalCount    int64
+	MemoryHistogramDecayHalfLife      time.Duration
+	CpuHistogramDecayHalfLife         time.Duration
+	SeasonalCpuHistogramDecayHalfLife time.Duration
+	OOMBumpUpRatio                    float64
+	OOMMinBumpUp                      float64
 
 	// Post processors configuration
 	PostProcessorCPUasInteger bool
 	MaxAllowedCPU             resource.QuantityValue
 	MaxAllowedMemory          resource.QuantityValue
+
+	// Sharding configuration
+	ShardLeaseNamespace string
+	ShardLeaseDuration  time.Duration
+
+	// Seeding configuration
+	RecommendationSeedWeight float64
 }
 
 // DefaultRecommenderConfig returns a RecommenderConfig with default values
@@ -115,6 +129,8 @@ func DefaultRecommenderConfig() *RecommenderConfig {
 		MetricsFetcherInterval:  1 * time.Minute,
 		CheckpointsGCInterval:   10 * time.Minute,
 		CheckpointsWriteTimeout: time.Minute,
+		CheckpointStore:         checkpoint.APIStoreName,
+		CheckpointStorePath:     "",
 		Address:                 ":8942",
 		Storage:                 "",
 		MemorySaver:             false,
@@ -133,6 +149,7 @@ func DefaultRecommenderConfig() *RecommenderConfig {
 		LowerBoundMemoryPercentile: 0.5,
 		UpperBoundMemoryPercentile: 0.95,
 		ConfidenceIntervalMemory:   24 * time.Hour,
+		CPUEstimator:               logic.PercentileCPUEstimatorName,
 		HumanizeMemory:             false,
 		RoundCPUMillicores:         1,
 		RoundMemoryBytes:           1,
@@ -164,17 +181,25 @@ func DefaultRecommenderConfig() *RecommenderConfig {
 		ExternalMemoryMetric: "",
 
 		// Aggregation configuration flags
-		MemoryAggregationInterval:      model.DefaultMemoryAggregationInterval,
-		MemoryAggregationIntervalCount: model.DefaultMemoryAggregationIntervalCount,
-		MemoryHistogramDecayHalfLife:   model.DefaultMemoryHistogramDecayHalfLife,
-		CpuHistogramDecayHalfLife:      model.DefaultCPUHistogramDecayHalfLife,
-		OOMBumpUpRatio:                 model.DefaultOOMBumpUpRatio,
-		OOMMinBumpUp:                   model.DefaultOOMMinBumpUp,
+		MemoryAggregationInterval:         model.DefaultMemoryAggregationInterval,
+		MemoryAggregationIntervalCount:    model.DefaultMemoryAggregationIntervalCount,
+		MemoryHistogramDecayHalfLife:      model.DefaultMemoryHistogramDecayHalfLife,
+		CpuHistogramDecayHalfLife:         model.DefaultCPUHistogramDecayHalfLife,
+		SeasonalCpuHistogramDecayHalfLife: model.DefaultSeasonalCPUHistogramDecayHalfLife,
+		OOMBumpUpRatio:                    model.DefaultOOMBumpUpRatio,
+		OOMMinBumpUp:                      model.DefaultOOMMinBumpUp,
 
 		// Post processors flags
 		PostProcessorCPUasInteger: false,
 		MaxAllowedCPU:             resource.QuantityValue{},
 		MaxAllowedMemory:          resource.QuantityValue{},
+
+		// Sharding flags
+		ShardLeaseNamespace: metav1.NamespaceSystem,
+		ShardLeaseDuration:  15 * time.Second,
+
+		// Seeding flags
+		RecommendationSeedWeight: 0.1,
 	}
 }
 
@@ -187,6 +212,8 @@ func InitRecommenderFlags() *RecommenderConfig {
 	flag.DurationVar(&config.MetricsFetcherInterval, "recommender-interval", config.MetricsFetcherInterval, `How often metrics should be fetched`)
 	flag.DurationVar(&config.CheckpointsGCInterval, "checkpoints-gc-interval", config.CheckpointsGCInterval, `How often orphaned checkpoints should be garbage collected`)
 	flag.DurationVar(&config.CheckpointsWriteTimeout, "checkpoints-timeout", config.CheckpointsWriteTimeout, `Timeout for writing checkpoints since the start of the recommender's main loop`)
+	flag.StringVar(&config.CheckpointStore, "checkpoint-store", config.CheckpointStore, `Where checkpoints are stored. Supported values: api (VerticalPodAutoscalerCheckpoint objects), file (a local file at checkpoint-store-path, e.g. on a PersistentVolume)`)
+	flag.StringVar(&config.CheckpointStorePath, "checkpoint-store-path", config.CheckpointStorePath, `Path of the file checkpoints are stored in when checkpoint-store is file`)
 	flag.StringVar(&config.Address, "address", config.Address, "The address to expose Prometheus metrics.")
 	flag.StringVar(&config.Storage, "storage", config.Storage, `Specifies storage mode. Supported values: prometheus, checkpoint (default)`)
 	flag.BoolVar(&config.MemorySaver, "memory-saver", config.MemorySaver, `If true, only track pods which have an associated VPA`)
@@ -195,17 +222,18 @@ func InitRecommenderFlags() *RecommenderConfig {
 	flag.IntVar(&config.MinCheckpointsPerRun, "min-checkpoints", config.MinCheckpointsPerRun, "Minimum number of checkpoints to write per recommender's main loop. WARNING: this flag is deprecated and doesn't have any effect. It will be removed in a future release. Refer to update-worker-count to influence the minimum number of checkpoints written per loop.")
 
 	// Recommendation configuration flags
-	flag.Float64Var(&config.SafetyMarginFraction, "recommendation-margin-fraction", config.SafetyMarginFraction, `Fraction of usage added as the safety margin to the recommended request`)
+	flag.Float64Var(&config.SafetyMarginFraction, "recommendation-margin-fraction", config.SafetyMarginFraction, `Fraction of usage added as the safety margin to the recommended request. This value applies to all VPAs unless overridden in the VPA spec.`)
 	flag.Float64Var(&config.PodMinCPUMillicores, "pod-recommendation-min-cpu-millicores", config.PodMinCPUMillicores, `Minimum CPU recommendation for a pod`)
 	flag.Float64Var(&config.PodMinMemoryMb, "pod-recommendation-min-memory-mb", config.PodMinMemoryMb, `Minimum memory recommendation for a pod`)
-	flag.Float64Var(&config.TargetCPUPercentile, "target-cpu-percentile", config.TargetCPUPercentile, "CPU usage percentile that will be used as a base for CPU target recommendation. Doesn't affect CPU lower bound, CPU upper bound nor memory recommendations.")
-	flag.Float64Var(&config.LowerBoundCPUPercentile, "recommendation-lower-bound-cpu-percentile", config.LowerBoundCPUPercentile, `CPU usage percentile that will be used for the lower bound on CPU recommendation.`)
-	flag.Float64Var(&config.UpperBoundCPUPercentile, "recommendation-upper-bound-cpu-percentile", config.UpperBoundCPUPercentile, `CPU usage percentile that will be used for the upper bound on CPU recommendation.`)
+	flag.Float64Var(&config.TargetCPUPercentile, "target-cpu-percentile", config.TargetCPUPercentile, "CPU usage percentile that will be used as a base for CPU target recommendation. Doesn't affect CPU lower bound, CPU upper bound nor memory recommendations. This value applies to all VPAs unless overridden in the VPA spec.")
+	flag.Float64Var(&config.LowerBoundCPUPercentile, "recommendation-lower-bound-cpu-percentile", config.LowerBoundCPUPercentile, `CPU usage percentile that will be used for the lower bound on CPU recommendation. This value applies to all VPAs unless overridden in the VPA spec.`)
+	flag.Float64Var(&config.UpperBoundCPUPercentile, "recommendation-upper-bound-cpu-percentile", config.UpperBoundCPUPercentile, `CPU usage percentile that will be used for the upper bound on CPU recommendation. This value applies to all VPAs unless overridden in the VPA spec.`)
 	flag.DurationVar(&config.ConfidenceIntervalCPU, "confidence-interval-cpu", config.ConfidenceIntervalCPU, "The time interval used for computing the confidence multiplier for the CPU lower and upper bound. Default: 24h")
-	flag.Float64Var(&config.TargetMemoryPercentile, "target-memory-percentile", config.TargetMemoryPercentile, "Memory usage percentile that will be used as a base for memory target recommendation. Doesn't affect memory lower bound nor memory upper bound.")
-	flag.Float64Var(&config.LowerBoundMemoryPercentile, "recommendation-lower-bound-memory-percentile", config.LowerBoundMemoryPercentile, `Memory usage percentile that will be used for the lower bound on memory recommendation.`)
-	flag.Float64Var(&config.UpperBoundMemoryPercentile, "recommendation-upper-bound-memory-percentile", config.UpperBoundMemoryPercentile, `Memory usage percentile that will be used for the upper bound on memory recommendation.`)
+	flag.Float64Var(&config.TargetMemoryPercentile, "target-memory-percentile", config.TargetMemoryPercentile, "Memory usage percentile that will be used as a base for memory target recommendation. Doesn't affect memory lower bound nor memory upper bound. This value applies to all VPAs unless overridden in the VPA spec.")
+	flag.Float64Var(&config.LowerBoundMemoryPercentile, "recommendation-lower-bound-memory-percentile", config.LowerBoundMemoryPercentile, `Memory usage percentile that will be used for the lower bound on memory recommendation. This value applies to all VPAs unless overridden in the VPA spec.`)
+	flag.Float64Var(&config.UpperBoundMemoryPercentile, "recommendation-upper-bound-memory-percentile", config.UpperBoundMemoryPercentile, `Memory usage percentile that will be used for the upper bound on memory recommendation. This value applies to all VPAs unless overridden in the VPA spec.`)
 	flag.DurationVar(&config.ConfidenceIntervalMemory, "confidence-interval-memory", config.ConfidenceIntervalMemory, "The time interval used for computing the confidence multiplier for the memory lower and upper bound. Default: 24h")
+	flag.StringVar(&config.CPUEstimator, "cpu-estimator", config.CPUEstimator, `Estimator used for CPU recommendations. Supported values: percentile (percentile of all CPU usage samples), seasonal (highest percentile of CPU usage among the hours of the week, keeps a separate CPU usage histogram for every hour of the week). This value applies to all VPAs unless overridden in the VPA spec.`)
 	flag.BoolVar(&config.HumanizeMemory, "humanize-memory", config.HumanizeMemory, "DEPRECATED: Convert memory values in recommendations to the highest appropriate SI unit with up to 2 decimal places for better readability. This flag is deprecated and will be removed in a future version. Use --round-memory-bytes instead.")
 	flag.IntVar(&config.RoundCPUMillicores, "round-cpu-millicores", config.RoundCPUMillicores, `CPU recommendation rounding factor in millicores. The CPU value will always be rounded up to the nearest multiple of this factor.`)
 	flag.IntVar(&config.RoundMemoryBytes, "round-memory-bytes", config.RoundMemoryBytes, `Memory recommendation rounding factor in bytes. The Memory value will always be rounded up to the nearest multiple of this factor.`)
@@ -241,6 +269,7 @@ func InitRecommenderFlags() *RecommenderConfig {
 	flag.Int64Var(&config.MemoryAggregationIntervalCount, "memory-aggregation-interval-count", config.MemoryAggregationIntervalCount, `Default number of consecutive memory-aggregation-intervals which make up the MemoryAggregationWindowLength which in turn is the period for memory usage aggregation by VPA. In other words, MemoryAggregationWindowLength = memory-aggregation-interval * memory-aggregation-interval-count. This value applies to all VPAs unless overridden in the VPA spec. Default is 8.`)
 	flag.DurationVar(&config.MemoryHistogramDecayHalfLife, "memory-histogram-decay-half-life", config.MemoryHistogramDecayHalfLife, `The amount of time it takes a historical memory usage sample to lose half of its weight. In other words, a fresh usage sample is twice as 'important' as one with age equal to the half life period.`)
 	flag.DurationVar(&config.CpuHistogramDecayHalfLife, "cpu-histogram-decay-half-life", config.CpuHistogramDecayHalfLife, `The amount of time it takes a historical CPU usage sample to lose half of its weight.`)
+	flag.DurationVar(&config.SeasonalCpuHistogramDecayHalfLife, "seasonal-cpu-histogram-decay-half-life", config.SeasonalCpuHistogramDecayHalfLife, `The amount of time it takes a historical CPU usage sample in the histogram of a single hour of the week to lose half of its weight. Only used for containers using the seasonal CPU estimator.`)
 	flag.Float64Var(&config.OOMBumpUpRatio, "oom-bump-up-ratio", config.OOMBumpUpRatio, `Default memory bump up ratio when OOM occurs. This value applies to all VPAs unless overridden in the VPA spec. Default is 1.2.`)
 	flag.Float64Var(&config.OOMMinBumpUp, "oom-min-bump-up-bytes", config.OOMMinBumpUp, `Default minimal increase of memory (in bytes) when OOM occurs. This value applies to all VPAs unless overridden in the VPA spec. Default is 100 * 1024 * 1024 (100Mi).`)
 
@@ -250,6 +279,13 @@ func InitRecommenderFlags() *RecommenderConfig {
 	flag.Var(&config.MaxAllowedCPU, "container-recommendation-max-allowed-cpu", "Maximum amount of CPU that will be recommended for a container. VerticalPodAutoscaler-level maximum allowed takes precedence over the global maximum allowed.")
 	flag.Var(&config.MaxAllowedMemory, "container-recommendation-max-allowed-memory", "Maximum amount of memory that will be recommended for a container. VerticalPodAutoscaler-level maximum allowed takes precedence over the global maximum allowed.")
 
+	// Sharding flags
+	flag.StringVar(&config.ShardLeaseNamespace, "shard-lease-namespace", config.ShardLeaseNamespace, "Namespace of the Leases through which recommender shards coordinate. Only used when the RecommenderSharding feature gate is enabled.")
+	flag.DurationVar(&config.ShardLeaseDuration, "shard-lease-duration", config.ShardLeaseDuration, "How long after its last renewal the Lease of a recommender shard expires, and the other shards take over its VPAs. Only used when the RecommenderSharding feature gate is enabled.")
+
+	// Seeding flags
+	flag.Float64Var(&config.RecommendationSeedWeight, "recommendation-seed-weight", config.RecommendationSeedWeight, "Weight of the usage samples a VPA without usage history of its own borrows from a similar workload, relative to its own samples. Only used when the RecommendationSeeding feature gate is enabled.")
+
 	// These need to happen last. kube_flag.InitFlags() synchronizes and parses
 	// flags from the flag package to pflag, so feature gates must be added to
 	// pflag before InitFlags() is called.
@@ -271,6 +307,43 @@ func ValidateRecommenderConfig(config *RecommenderConfig) {
 		klog.InfoS("DEPRECATION WARNING: The 'min-checkpoints' flag is deprecated and has no effect. It will be removed in a future release.")
 	}
 
+	if config.CPUEstimator != logic.PercentileCPUEstimatorName && config.CPUEstimator != logic.SeasonalCPUEstimatorName {
+		klog.ErrorS(nil, "Unsupported --cpu-estimator", "cpuEstimator", config.CPUEstimator)
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+
+	switch config.CheckpointStore {
+	case checkpoint.APIStoreName:
+	case checkpoint.FileStoreName:
+		if config.CheckpointStorePath == "" {
+			klog.ErrorS(nil, "--checkpoint-store-path is required with --checkpoint-store=file")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+	default:
+		klog.ErrorS(nil, "Unsupported --checkpoint-store", "checkpointStore", config.CheckpointStore)
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+
+	if features.Enabled(features.RecommenderSharding) {
+		if config.CheckpointStore != checkpoint.APIStoreName {
+			klog.ErrorS(nil, "The RecommenderSharding feature gate requires --checkpoint-store=api, shards take over the history of VPAs from checkpoints written by other shards.")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+		if config.Storage == "prometheus" {
+			klog.ErrorS(nil, "The RecommenderSharding feature gate requires the checkpoint storage, shards take over the history of VPAs from their checkpoints.")
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+		if config.ShardLeaseDuration < 3*time.Second {
+			klog.ErrorS(nil, "--shard-lease-duration must be at least 3s", "shardLeaseDuration", config.ShardLeaseDuration)
+			klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+		}
+	}
+
+	if features.Enabled(features.RecommendationSeeding) && (config.RecommendationSeedWeight <= 0 || config.RecommendationSeedWeight > 1) {
+		klog.ErrorS(nil, "--recommendation-seed-weight must be greater than 0 and at most 1", "recommendationSeedWeight", config.RecommendationSeedWeight)
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+
 	if config.PrometheusBearerToken != "" && config.PrometheusBearerTokenFile != "" && config.Username != "" {
 		klog.ErrorS(nil, "--bearer-token, --bearer-token-file and --username are mutually exclusive and can't be set together.")
 		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
@@ -30,22 +30,25 @@ import (
 	"k8s.io/apimachinery/pkg/watch"
 	kube_client "k8s.io/client-go/kubernetes"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	autoscalingv2listers "k8s.io/client-go/listers/autoscaling/v2"
 	listersv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
-	vpa_api "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/typed/autoscaling.k8s.io/v1"
 	vpa_lister "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/listers/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/checkpoint"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/history"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/metrics"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/oom"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/spec"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/sharding"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
 	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/controller_fetcher"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/client"
 	metrics_recommender "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/metrics/recommender"
+	api_utils "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
 )
 
 const (
@@ -78,39 +81,41 @@ type ClusterStateFeeder interface {
 
 // ClusterStateFeederFactory makes instances of ClusterStateFeeder.
 type ClusterStateFeederFactory struct {
-	ClusterState        model.ClusterState
-	KubeClient          kube_client.Interface
-	MetricsClient       metrics.MetricsClient
-	VpaCheckpointClient vpa_api.VerticalPodAutoscalerCheckpointsGetter
-	VpaCheckpointLister vpa_lister.VerticalPodAutoscalerCheckpointLister
-	VpaLister           vpa_lister.VerticalPodAutoscalerLister
-	PodLister           listersv1.PodLister
-	OOMObserver         oom.Observer
-	SelectorFetcher     target.VpaTargetSelectorFetcher
-	MemorySaveMode      bool
-	ControllerFetcher   controllerfetcher.ControllerFetcher
-	RecommenderName     string
-	IgnoredNamespaces   []string
-	VpaObjectNamespace  string
+	ClusterState       model.ClusterState
+	KubeClient         kube_client.Interface
+	MetricsClient      metrics.MetricsClient
+	CheckpointStore    checkpoint.CheckpointStore
+	VpaLister          vpa_lister.VerticalPodAutoscalerLister
+	PodLister          listersv1.PodLister
+	HpaLister          autoscalingv2listers.HorizontalPodAutoscalerLister
+	OOMObserver        oom.Observer
+	SelectorFetcher    target.VpaTargetSelectorFetcher
+	MemorySaveMode     bool
+	ControllerFetcher  controllerfetcher.ControllerFetcher
+	RecommenderName    string
+	IgnoredNamespaces  []string
+	VpaObjectNamespace string
+	Sharder            sharding.Sharder
 }
 
 // Make creates new ClusterStateFeeder with internal data providers, based on kube client.
 func (m ClusterStateFeederFactory) Make() *clusterStateFeeder {
 	return &clusterStateFeeder{
-		coreClient:          m.KubeClient.CoreV1(),
-		metricsClient:       m.MetricsClient,
-		oomChan:             m.OOMObserver.GetObservedOomsChannel(),
-		vpaCheckpointClient: m.VpaCheckpointClient,
-		vpaCheckpointLister: m.VpaCheckpointLister,
-		vpaLister:           m.VpaLister,
-		clusterState:        m.ClusterState,
-		specClient:          spec.NewSpecClient(m.PodLister),
-		selectorFetcher:     m.SelectorFetcher,
-		memorySaveMode:      m.MemorySaveMode,
-		controllerFetcher:   m.ControllerFetcher,
-		recommenderName:     m.RecommenderName,
-		ignoredNamespaces:   m.IgnoredNamespaces,
-		vpaObjectNamespace:  m.VpaObjectNamespace,
+		coreClient:         m.KubeClient.CoreV1(),
+		metricsClient:      m.MetricsClient,
+		oomChan:            m.OOMObserver.GetObservedOomsChannel(),
+		checkpointStore:    m.CheckpointStore,
+		vpaLister:          m.VpaLister,
+		hpaLister:          m.HpaLister,
+		clusterState:       m.ClusterState,
+		specClient:         spec.NewSpecClient(m.PodLister),
+		selectorFetcher:    m.SelectorFetcher,
+		memorySaveMode:     m.MemorySaveMode,
+		controllerFetcher:  m.ControllerFetcher,
+		recommenderName:    m.RecommenderName,
+		ignoredNamespaces:  m.IgnoredNamespaces,
+		vpaObjectNamespace: m.VpaObjectNamespace,
+		sharder:            m.Sharder,
 	}
 }
 
@@ -209,20 +214,25 @@ func NewPodListerAndOOMObserver(ctx context.Context, kubeClient kube_client.Inte
 }
 
 type clusterStateFeeder struct {
-	coreClient          typedcorev1.CoreV1Interface
-	specClient          spec.SpecClient
-	metricsClient       metrics.MetricsClient
-	oomChan             <-chan oom.OomInfo
-	vpaCheckpointClient vpa_api.VerticalPodAutoscalerCheckpointsGetter
-	vpaCheckpointLister vpa_lister.VerticalPodAutoscalerCheckpointLister
-	vpaLister           vpa_lister.VerticalPodAutoscalerLister
-	clusterState        model.ClusterState
-	selectorFetcher     target.VpaTargetSelectorFetcher
-	memorySaveMode      bool
-	controllerFetcher   controllerfetcher.ControllerFetcher
-	recommenderName     string
-	ignoredNamespaces   []string
-	vpaObjectNamespace  string
+	coreClient         typedcorev1.CoreV1Interface
+	specClient         spec.SpecClient
+	metricsClient      metrics.MetricsClient
+	oomChan            <-chan oom.OomInfo
+	checkpointStore    checkpoint.CheckpointStore
+	vpaLister          vpa_lister.VerticalPodAutoscalerLister
+	hpaLister          autoscalingv2listers.HorizontalPodAutoscalerLister
+	clusterState       model.ClusterState
+	selectorFetcher    target.VpaTargetSelectorFetcher
+	memorySaveMode     bool
+	controllerFetcher  controllerfetcher.ControllerFetcher
+	recommenderName    string
+	ignoredNamespaces  []string
+	vpaObjectNamespace string
+	// sharder decides which VPAs this recommender handles when it runs as
+	// one of several shards. It's nil otherwise.
+	sharder sharding.Sharder
+	// checkpointsLoaded is set once the initial checkpoints were loaded.
+	checkpointsLoaded bool
 }
 
 func (feeder *clusterStateFeeder) InitFromHistoryProvider(historyProvider history.HistoryProvider) {
@@ -268,6 +278,9 @@ func (feeder *clusterStateFeeder) setVpaCheckpoint(checkpoint *vpa_types.Vertica
 	}
 
 	cs := model.NewAggregateContainerState()
+	// The resource policy decides which histograms the state keeps, so it has
+	// to be applied before loading them.
+	cs.UpdateFromPolicy(api_utils.GetContainerResourcePolicy(checkpoint.Spec.ContainerName, vpa.ResourcePolicy))
 	err := cs.LoadFromCheckpoint(&checkpoint.Status)
 	if err != nil {
 		return fmt.Errorf("cannot load checkpoint for VPA %s/%s. Reason: %v", vpaID.Namespace, vpaID.VpaName, err)
@@ -281,7 +294,7 @@ func (feeder *clusterStateFeeder) InitFromCheckpoints(ctx context.Context) {
 	feeder.LoadVPAs(ctx)
 
 	klog.V(3).InfoS("Fetching VPA checkpoints")
-	checkpointList, err := feeder.vpaCheckpointLister.List(labels.Everything())
+	checkpointList, err := feeder.checkpointStore.List("")
 	if err != nil {
 		klog.ErrorS(err, "Cannot list VPA checkpoints")
 	}
@@ -298,6 +311,9 @@ func (feeder *clusterStateFeeder) InitFromCheckpoints(ctx context.Context) {
 		}
 
 		for _, checkpoint := range checkpointList {
+			if !feeder.ownsVPA(checkpoint.Namespace, checkpoint.Spec.VPAObjectName) {
+				continue
+			}
 			klog.V(3).InfoS("Loading checkpoint for VPA", "checkpoint", klog.KRef(checkpoint.Namespace, checkpoint.Spec.VPAObjectName), "container", checkpoint.Spec.ContainerName)
 			err = feeder.setVpaCheckpoint(checkpoint)
 			if err != nil {
@@ -305,6 +321,32 @@ func (feeder *clusterStateFeeder) InitFromCheckpoints(ctx context.Context) {
 			}
 		}
 	}
+	feeder.checkpointsLoaded = true
+}
+
+// loadVPACheckpoints loads the checkpoints of a VPA this shard took over from
+// another shard.
+func (feeder *clusterStateFeeder) loadVPACheckpoints(vpaID model.VpaID) {
+	checkpointList, err := feeder.checkpointStore.List(vpaID.Namespace)
+	if err != nil {
+		klog.ErrorS(err, "Cannot list VPA checkpoints", "namespace", vpaID.Namespace)
+		return
+	}
+	for _, checkpoint := range checkpointList {
+		if checkpoint.Spec.VPAObjectName != vpaID.VpaName {
+			continue
+		}
+		klog.V(3).InfoS("Loading checkpoint for VPA taken over from another shard", "checkpoint", klog.KRef(checkpoint.Namespace, checkpoint.Spec.VPAObjectName), "container", checkpoint.Spec.ContainerName)
+		if err := feeder.setVpaCheckpoint(checkpoint); err != nil {
+			klog.ErrorS(err, "Error while loading checkpoint")
+		}
+	}
+}
+
+// ownsVPA returns true if the VPA is handled by this recommender shard, which
+// is always the case without sharding.
+func (feeder *clusterStateFeeder) ownsVPA(namespace, name string) bool {
+	return feeder.sharder == nil || feeder.sharder.Owns(namespace, name)
 }
 
 func (feeder *clusterStateFeeder) GarbageCollectCheckpoints(ctx context.Context) {
@@ -331,8 +373,10 @@ func (feeder *clusterStateFeeder) GarbageCollectCheckpoints(ctx context.Context)
 		return
 	}
 
+	existingNamespaces := make(map[string]bool, len(namespaceList.Items))
 	for _, namespaceItem := range namespaceList.Items {
 		namespace := namespaceItem.Name
+		existingNamespaces[namespace] = true
 		// Clean the namespace if any of the following conditions are true:
 		// 1. `vpaObjectNamespace` is set and matches the current namespace.
 		// 2. `ignoredNamespaces` is set, but the current namespace is not in the list.
@@ -346,6 +390,31 @@ func (feeder *clusterStateFeeder) GarbageCollectCheckpoints(ctx context.Context)
 			klog.ErrorS(err, "error cleaning checkpoints")
 		}
 	}
+	if err := feeder.cleanupCheckpointsOfDeletedNamespaces(ctx, existingNamespaces); err != nil {
+		klog.ErrorS(err, "error cleaning checkpoints of deleted namespaces")
+	}
+}
+
+// cleanupCheckpointsOfDeletedNamespaces deletes the checkpoints stored in
+// namespaces that no longer exist. Checkpoints kept in the API are deleted
+// along with their namespace, but those kept in a file store are not.
+func (feeder *clusterStateFeeder) cleanupCheckpointsOfDeletedNamespaces(ctx context.Context, existingNamespaces map[string]bool) error {
+	checkpointList, err := feeder.checkpointStore.List("")
+	if err != nil {
+		return err
+	}
+	for _, checkpoint := range checkpointList {
+		namespace := checkpoint.Namespace
+		if existingNamespaces[namespace] || feeder.shouldIgnoreNamespace(namespace) || !feeder.ownsVPA(namespace, checkpoint.Spec.VPAObjectName) {
+			continue
+		}
+		if errDelete := feeder.checkpointStore.Delete(ctx, namespace, checkpoint.Name); errDelete != nil {
+			err = fmt.Errorf("failed to delete checkpoint %s of deleted namespace: %w", klog.KRef(namespace, checkpoint.Name), errDelete)
+			continue
+		}
+		klog.V(3).InfoS("Deleted namespace VPA checkpoint cleanup - deleting", "checkpoint", klog.KRef(namespace, checkpoint.Name))
+	}
+	return err
 }
 
 func (feeder *clusterStateFeeder) shouldIgnoreNamespace(namespace string) bool {
@@ -362,15 +431,16 @@ func (feeder *clusterStateFeeder) shouldIgnoreNamespace(namespace string) bool {
 
 func (feeder *clusterStateFeeder) cleanupCheckpointsForNamespace(ctx context.Context, namespace string, allVPAKeys map[model.VpaID]bool) error {
 	var err error
-	checkpointList, err := feeder.vpaCheckpointLister.VerticalPodAutoscalerCheckpoints(namespace).List(labels.Everything())
+	checkpointList, err := feeder.checkpointStore.List(namespace)
 
 	if err != nil {
 		return err
 	}
 	for _, checkpoint := range checkpointList {
 		vpaID := model.VpaID{Namespace: checkpoint.Namespace, VpaName: checkpoint.Spec.VPAObjectName}
-		if !allVPAKeys[vpaID] {
-			if errFeeder := feeder.vpaCheckpointClient.VerticalPodAutoscalerCheckpoints(namespace).Delete(ctx, checkpoint.Name, metav1.DeleteOptions{}); errFeeder != nil {
+		// Each shard cleans up the checkpoints of the VPAs it would own.
+		if !allVPAKeys[vpaID] && feeder.ownsVPA(vpaID.Namespace, vpaID.VpaName) {
+			if errFeeder := feeder.checkpointStore.Delete(ctx, namespace, checkpoint.Name); errFeeder != nil {
 				err = fmt.Errorf("failed to delete orphaned checkpoint %s: %w", klog.KRef(namespace, checkpoint.Name), err)
 				continue
 			}
@@ -419,6 +489,11 @@ func filterVPAs(feeder *clusterStateFeeder, allVpaCRDs []*vpa_types.VerticalPodA
 			continue
 		}
 
+		if !feeder.ownsVPA(vpaCRD.Namespace, vpaCRD.Name) {
+			klog.V(6).InfoS("Ignoring vpaCRD as it's handled by another recommender shard", "vpaCRD", klog.KObj(vpaCRD))
+			continue
+		}
+
 		vpaCRDs = append(vpaCRDs, vpaCRD)
 	}
 	return vpaCRDs
@@ -448,9 +523,15 @@ func (feeder *clusterStateFeeder) LoadVPAs(ctx context.Context) {
 		selector, conditions := feeder.getSelector(ctx, vpaCRD)
 		klog.V(4).InfoS("Using selector", "selector", selector.String(), "vpa", klog.KObj(vpaCRD))
 
+		_, known := feeder.clusterState.VPAs()[vpaID]
 		if feeder.clusterState.AddOrUpdateVpa(vpaCRD, selector) == nil {
 			// Successfully added VPA to the model.
 			vpaKeys[vpaID] = true
+			if !known && feeder.sharder != nil && feeder.checkpointsLoaded {
+				// The VPA may have been handled by another shard until now.
+				feeder.loadVPACheckpoints(vpaID)
+			}
+			feeder.loadHPATargetCPUUtilization(feeder.clusterState.VPAs()[vpaID], vpaCRD)
 
 			for _, condition := range conditions {
 				if condition.delete {
@@ -465,7 +546,13 @@ func (feeder *clusterStateFeeder) LoadVPAs(ctx context.Context) {
 	for vpaID := range feeder.clusterState.VPAs() {
 		if _, exists := vpaKeys[vpaID]; !exists {
 			klog.V(3).InfoS("Deleting VPA", "vpa", klog.KRef(vpaID.Namespace, vpaID.VpaName))
-			if err := feeder.clusterState.DeleteVpa(vpaID); err != nil {
+			deleteVpa := feeder.clusterState.DeleteVpa
+			if feeder.sharder != nil {
+				// The history of the VPA is kept by the shard taking it over
+				// and would be counted twice if this shard gets it back.
+				deleteVpa = feeder.clusterState.DeleteVpaAndAggregateStates
+			}
+			if err := deleteVpa(vpaID); err != nil {
 				klog.ErrorS(err, "Deleting VPA failed", "vpa", klog.KRef(vpaID.Namespace, vpaID.VpaName))
 			}
 		}
@@ -491,13 +578,19 @@ func (feeder *clusterStateFeeder) LoadPods() {
 		}
 	}
 	for _, pod := range pods {
-		if feeder.memorySaveMode && !feeder.matchesVPA(pod) {
+		if (feeder.memorySaveMode || feeder.sharder != nil) && !feeder.matchesVPA(pod) {
+			if _, tracked := feeder.clusterState.Pods()[pod.ID]; tracked && feeder.sharder != nil {
+				// The VPA of the pod is handled by another shard now.
+				feeder.clusterState.DeletePod(pod.ID)
+			}
 			continue
 		}
 		feeder.clusterState.AddOrUpdatePod(pod.ID, pod.PodLabels, pod.Phase)
 		for _, container := range pod.Containers {
 			if err = feeder.clusterState.AddOrUpdateContainer(container.ID, container.Request); err != nil {
 				klog.V(0).InfoS("Failed to add container", "container", container.ID, "error", err)
+			} else if containerState := feeder.clusterState.GetContainer(container.ID); containerState != nil {
+				containerState.Image = container.Image
 			}
 		}
 		initContainerNames := make([]string, 0, len(pod.InitContainers))
@@ -529,8 +622,8 @@ func (feeder *clusterStateFeeder) LoadRealTimeMetrics(ctx context.Context) {
 		}
 		for _, sample := range newContainerUsageSamplesWithKey(containerMetrics) {
 			if err := feeder.clusterState.AddSample(sample); err != nil {
-				// Not all pod states are tracked in memory saver mode.
-				if _, isKeyError := err.(model.KeyError); isKeyError && (feeder.memorySaveMode || sample.Container.ContainerName == "POD") {
+				// Not all pod states are tracked in memory saver mode or by shards.
+				if _, isKeyError := err.(model.KeyError); isKeyError && (feeder.memorySaveMode || feeder.sharder != nil || sample.Container.ContainerName == "POD") {
 					continue
 				}
 				klog.V(0).InfoS("Error adding metric sample", "sample", sample, "error", err)
@@ -541,6 +634,7 @@ func (feeder *clusterStateFeeder) LoadRealTimeMetrics(ctx context.Context) {
 		}
 	}
 	klog.V(3).InfoS("ClusterSpec fed with ContainerUsageSamples", "sampleCount", sampleCount, "containerCount", len(containersMetrics), "droppedSampleCount", droppedSampleCount)
+	feeder.recordWorkloadCPUUsage(containersMetrics)
 Loop:
 	for {
 		select {
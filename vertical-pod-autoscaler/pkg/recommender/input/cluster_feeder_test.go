@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -36,6 +37,7 @@ import (
 
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	fakeautoscalingv1 "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/typed/autoscaling.k8s.io/v1/fake"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/checkpoint"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/history"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/metrics"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/spec"
@@ -501,6 +503,7 @@ func TestClusterStateFeeder_LoadPods_ContainerTracking(t *testing.T) {
 	assert.Equal(t, len(feeder.clusterState.Pods()[podWithInitContainersID].InitContainers), 2)
 	assert.Equal(t, len(feeder.clusterState.Pods()[podWithoutInitContainersID].Containers), 2)
 	assert.Equal(t, len(feeder.clusterState.Pods()[podWithoutInitContainersID].InitContainers), 0)
+	assert.Equal(t, "container2Image", feeder.clusterState.Pods()[podWithoutInitContainersID].Containers["container2"].Image)
 
 	// Re-loading the same pods must not cause the init container list to grow.
 	// This guards against a regression where LoadPods appended to the existing
@@ -885,6 +888,30 @@ func TestFilterVPAsIgnoreNamespaces(t *testing.T) {
 	assert.ElementsMatch(t, expectedResult, result)
 }
 
+type fakeSharder struct {
+	owned map[string]bool
+}
+
+func (s *fakeSharder) Owns(namespace, name string) bool {
+	return s.owned[namespace+"/"+name]
+}
+
+func TestFilterVPAsSharding(t *testing.T) {
+	vpaBuilder := test.VerticalPodAutoscaler().WithContainer("container").WithNamespace("default").WithRecommender(DefaultRecommenderName)
+	vpa1 := vpaBuilder.WithName("vpa1").Get()
+	vpa2 := vpaBuilder.WithName("vpa2").Get()
+	vpa3 := vpaBuilder.WithName("vpa3").Get()
+
+	feeder := &clusterStateFeeder{
+		recommenderName: DefaultRecommenderName,
+		sharder:         &fakeSharder{owned: map[string]bool{"default/vpa1": true, "default/vpa3": true}},
+	}
+
+	result := filterVPAs(feeder, []*vpa_types.VerticalPodAutoscaler{vpa1, vpa2, vpa3})
+
+	assert.ElementsMatch(t, []*vpa_types.VerticalPodAutoscaler{vpa1, vpa3}, result)
+}
+
 func TestCanCleanupCheckpoints(t *testing.T) {
 	_, tctx := ktesting.NewTestContext(t)
 	client := fake.NewClientset()
@@ -948,14 +975,14 @@ func TestCanCleanupCheckpoints(t *testing.T) {
 	// Create main checkpoint mock that will return the namespace lister
 	checkpointLister := &test.VerticalPodAutoscalerCheckPointListerMock{}
 	checkpointLister.On("VerticalPodAutoscalerCheckpoints", namespace).Return(checkpointNamespaceLister)
+	checkpointLister.On("List").Return(vpacheckpoints, nil)
 
 	feeder := clusterStateFeeder{
-		coreClient:          client.CoreV1(),
-		vpaLister:           vpaLister,
-		vpaCheckpointClient: checkpointClient,
-		vpaCheckpointLister: checkpointLister,
-		clusterState:        model.NewClusterState(testGcPeriod),
-		recommenderName:     "default",
+		coreClient:      client.CoreV1(),
+		vpaLister:       vpaLister,
+		checkpointStore: checkpoint.NewAPICheckpointStore(checkpointClient, checkpointLister),
+		clusterState:    model.NewClusterState(testGcPeriod),
+		recommenderName: "default",
 	}
 
 	feeder.GarbageCollectCheckpoints(tctx)
@@ -966,3 +993,50 @@ func TestCanCleanupCheckpoints(t *testing.T) {
 		assert.NotContains(t, deletedCheckpoints, vpa.Name)
 	}
 }
+
+func TestCleanupCheckpointsOfDeletedNamespaces(t *testing.T) {
+	_, tctx := ktesting.NewTestContext(t)
+	client := fake.NewClientset()
+	_, err := client.CoreV1().Namespaces().Create(tctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "existing"}}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	vpa := test.VerticalPodAutoscaler().WithContainer("container").WithNamespace("existing").WithName("vpa").WithTargetRef(&autoscalingv1.CrossVersionObjectReference{
+		Kind:       kind,
+		Name:       name1,
+		APIVersion: apiVersion,
+	}).Get()
+	vpaLister := &test.VerticalPodAutoscalerListerMock{}
+	vpaLister.On("List").Return([]*vpa_types.VerticalPodAutoscaler{vpa}, nil)
+
+	store, err := checkpoint.NewFileCheckpointStore(filepath.Join(t.TempDir(), "checkpoints"))
+	assert.NoError(t, err)
+	for _, ref := range []struct{ namespace, name string }{
+		{"existing", "vpa"},
+		{"deleted", "vpa"},
+		{"ignored", "vpa"},
+	} {
+		assert.NoError(t, store.Save(tctx, &vpa_types.VerticalPodAutoscalerCheckpoint{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ref.namespace, Name: ref.name},
+			Spec:       vpa_types.VerticalPodAutoscalerCheckpointSpec{VPAObjectName: ref.name},
+		}))
+	}
+
+	feeder := clusterStateFeeder{
+		coreClient:        client.CoreV1(),
+		vpaLister:         vpaLister,
+		checkpointStore:   store,
+		clusterState:      model.NewClusterState(testGcPeriod),
+		recommenderName:   DefaultRecommenderName,
+		ignoredNamespaces: []string{"ignored"},
+	}
+
+	feeder.GarbageCollectCheckpoints(tctx)
+
+	checkpoints, err := store.List("")
+	assert.NoError(t, err)
+	var remaining []string
+	for _, c := range checkpoints {
+		remaining = append(remaining, c.Namespace+"/"+c.Name)
+	}
+	assert.ElementsMatch(t, []string{"existing/vpa", "ignored/vpa"}, remaining)
+}
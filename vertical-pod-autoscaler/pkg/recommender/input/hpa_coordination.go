@@ -0,0 +1,142 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package input
+
+import (
+	"time"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/metrics"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+)
+
+// loadHPATargetCPUUtilization sets the CPU utilization target of the
+// HorizontalPodAutoscaler scaling the same target as the given VPA.
+func (feeder *clusterStateFeeder) loadHPATargetCPUUtilization(vpa *model.Vpa, vpaCRD *vpa_types.VerticalPodAutoscaler) {
+	if !vpa.HPACoordination || feeder.hpaLister == nil {
+		return
+	}
+	vpa.HPATargetCPUUtilization = 0
+	hpas, err := feeder.hpaLister.HorizontalPodAutoscalers(vpaCRD.Namespace).List(labels.Everything())
+	if err != nil {
+		klog.ErrorS(err, "Cannot list HorizontalPodAutoscalers", "vpa", klog.KObj(vpaCRD))
+		return
+	}
+	for _, hpa := range hpas {
+		if !hpaTargetsSameController(hpa, vpaCRD) {
+			continue
+		}
+		if utilization := getHPATargetCPUUtilization(hpa); utilization != nil {
+			vpa.HPATargetCPUUtilization = float64(*utilization) / 100.0
+			return
+		}
+		klog.V(4).InfoS("HorizontalPodAutoscaler has no CPU utilization target, not coordinating", "vpa", klog.KObj(vpaCRD), "hpa", klog.KObj(hpa))
+		return
+	}
+	klog.V(4).InfoS("No HorizontalPodAutoscaler found for VPA with HPA coordination", "vpa", klog.KObj(vpaCRD))
+}
+
+func hpaTargetsSameController(hpa *autoscalingv2.HorizontalPodAutoscaler, vpa *vpa_types.VerticalPodAutoscaler) bool {
+	if vpa.Spec.TargetRef == nil {
+		return false
+	}
+	hpaRef := hpa.Spec.ScaleTargetRef
+	vpaRef := vpa.Spec.TargetRef
+	if hpaRef.Kind != vpaRef.Kind || hpaRef.Name != vpaRef.Name {
+		return false
+	}
+	hpaGroupVersion, err := schema.ParseGroupVersion(hpaRef.APIVersion)
+	if err != nil {
+		return false
+	}
+	vpaGroupVersion, err := schema.ParseGroupVersion(vpaRef.APIVersion)
+	if err != nil {
+		return false
+	}
+	return hpaGroupVersion.Group == vpaGroupVersion.Group
+}
+
+// getHPATargetCPUUtilization returns the average CPU utilization target (in
+// percent of the request) of the HPA, or nil if it doesn't scale on it.
+func getHPATargetCPUUtilization(hpa *autoscalingv2.HorizontalPodAutoscaler) *int32 {
+	for _, metric := range hpa.Spec.Metrics {
+		if metric.Type != autoscalingv2.ResourceMetricSourceType || metric.Resource == nil {
+			continue
+		}
+		if metric.Resource.Name != corev1.ResourceCPU || metric.Resource.Target.Type != autoscalingv2.UtilizationMetricType {
+			continue
+		}
+		if metric.Resource.Target.AverageUtilization != nil && *metric.Resource.Target.AverageUtilization > 0 {
+			return metric.Resource.Target.AverageUtilization
+		}
+	}
+	return nil
+}
+
+// recordWorkloadCPUUsage sums the CPU usage of containers over all pods of
+// every VPA with HPA coordination and records it per replica.
+func (feeder *clusterStateFeeder) recordWorkloadCPUUsage(containersMetrics []*metrics.ContainerMetricsSnapshot) {
+	podToVpa := make(map[model.PodID]*model.Vpa)
+	for _, vpa := range feeder.clusterState.VPAs() {
+		if !vpa.HPACoordination {
+			continue
+		}
+		for _, podID := range feeder.clusterState.GetMatchingPods(vpa) {
+			podToVpa[podID] = vpa
+		}
+	}
+	if len(podToVpa) == 0 {
+		return
+	}
+
+	type workloadContainer struct {
+		vpa           *model.Vpa
+		containerName string
+	}
+	usage := make(map[workloadContainer]model.ResourceAmount)
+	measureStart := make(map[workloadContainer]time.Time)
+	replicas := make(map[*model.Vpa]map[model.PodID]bool)
+	for _, containerMetrics := range containersMetrics {
+		vpa, found := podToVpa[containerMetrics.ID.PodID]
+		if !found {
+			continue
+		}
+		cpu, found := containerMetrics.Usage[model.ResourceCPU]
+		if !found {
+			continue
+		}
+		key := workloadContainer{vpa: vpa, containerName: containerMetrics.ID.ContainerName}
+		usage[key] += cpu
+		if containerMetrics.SnapshotTime.After(measureStart[key]) {
+			measureStart[key] = containerMetrics.SnapshotTime
+		}
+		if replicas[vpa] == nil {
+			replicas[vpa] = make(map[model.PodID]bool)
+		}
+		replicas[vpa][containerMetrics.ID.PodID] = true
+	}
+	for key, totalUsage := range usage {
+		usagePerReplica := model.ScaleResource(totalUsage, 1.0/float64(len(replicas[key.vpa])))
+		key.vpa.AddWorkloadCPUSample(key.containerName, usagePerReplica, measureStart[key])
+	}
+}
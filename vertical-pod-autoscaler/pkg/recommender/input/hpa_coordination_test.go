@@ -0,0 +1,132 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package input
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	autoscalingv2listers "k8s.io/client-go/listers/autoscaling/v2"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+)
+
+func newHPA(name, targetKind, targetName string, metrics ...autoscalingv2.MetricSpec) *autoscalingv2.HorizontalPodAutoscaler {
+	return &autoscalingv2.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: autoscalingv2.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv2.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       targetKind,
+				Name:       targetName,
+			},
+			Metrics: metrics,
+		},
+	}
+}
+
+func cpuUtilizationMetric(utilization int32) autoscalingv2.MetricSpec {
+	return autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: corev1.ResourceCPU,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: ptr.To(utilization),
+			},
+		},
+	}
+}
+
+func TestLoadHPATargetCPUUtilization(t *testing.T) {
+	vpaCRD := &vpa_types.VerticalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "vpa"},
+		Spec: vpa_types.VerticalPodAutoscalerSpec{
+			TargetRef: &autoscalingv1.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       "app",
+			},
+		},
+	}
+	memoryMetric := autoscalingv2.MetricSpec{
+		Type: autoscalingv2.ResourceMetricSourceType,
+		Resource: &autoscalingv2.ResourceMetricSource{
+			Name: corev1.ResourceMemory,
+			Target: autoscalingv2.MetricTarget{
+				Type:               autoscalingv2.UtilizationMetricType,
+				AverageUtilization: ptr.To(int32(80)),
+			},
+		},
+	}
+
+	testCases := []struct {
+		name                string
+		hpaCoordination     bool
+		hpas                []*autoscalingv2.HorizontalPodAutoscaler
+		expectedUtilization float64
+	}{
+		{
+			name:                "HPA with CPU utilization target",
+			hpaCoordination:     true,
+			hpas:                []*autoscalingv2.HorizontalPodAutoscaler{newHPA("hpa", "Deployment", "app", cpuUtilizationMetric(60))},
+			expectedUtilization: 0.6,
+		},
+		{
+			name:            "coordination disabled",
+			hpaCoordination: false,
+			hpas:            []*autoscalingv2.HorizontalPodAutoscaler{newHPA("hpa", "Deployment", "app", cpuUtilizationMetric(60))},
+		},
+		{
+			name:            "HPA targets another controller",
+			hpaCoordination: true,
+			hpas:            []*autoscalingv2.HorizontalPodAutoscaler{newHPA("hpa", "Deployment", "other", cpuUtilizationMetric(60))},
+		},
+		{
+			name:            "HPA without CPU utilization target",
+			hpaCoordination: true,
+			hpas:            []*autoscalingv2.HorizontalPodAutoscaler{newHPA("hpa", "Deployment", "app", memoryMetric)},
+		},
+		{
+			name:            "no HPA",
+			hpaCoordination: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+			for _, hpa := range tc.hpas {
+				assert.NoError(t, indexer.Add(hpa))
+			}
+			feeder := clusterStateFeeder{hpaLister: autoscalingv2listers.NewHorizontalPodAutoscalerLister(indexer)}
+			vpa := model.NewVpa(model.VpaID{Namespace: "default", VpaName: "vpa"}, nil, time.Unix(0, 0))
+			vpa.SetHPACoordination(tc.hpaCoordination)
+
+			feeder.loadHPATargetCPUUtilization(vpa, vpaCRD)
+
+			assert.Equal(t, tc.expectedUtilization, vpa.HPATargetCPUUtilization)
+		})
+	}
+}
@@ -54,6 +54,10 @@ type percentileMemoryEstimator struct {
 	percentile float64
 }
 
+type seasonalCPUEstimator struct {
+	percentile float64
+}
+
 // margins
 
 type cpuMarginEstimator struct {
@@ -80,6 +84,11 @@ type memoryConfidenceMultiplier struct {
 	confidenceInterval time.Duration
 }
 
+type hpaCoordinatedCPUEstimator struct {
+	percentile    float64
+	baseEstimator CPUEstimator
+}
+
 type cpuMinResourceEstimator struct {
 	minResource   model.ResourceAmount
 	baseEstimator CPUEstimator
@@ -100,6 +109,11 @@ func NewPercentileCPUEstimator(percentile float64) CPUEstimator {
 	return &percentileCPUEstimator{percentile}
 }
 
+// NewSeasonalCPUEstimator returns a new seasonalCPUEstimator that uses provided percentile.
+func NewSeasonalCPUEstimator(percentile float64) CPUEstimator {
+	return &seasonalCPUEstimator{percentile}
+}
+
 // NewPercentileMemoryEstimator returns a new percentileMemoryEstimator that uses provided percentile.
 func NewPercentileMemoryEstimator(percentile float64) MemoryEstimator {
 	return &percentileMemoryEstimator{percentile}
@@ -158,6 +172,24 @@ func (e *percentileCPUEstimator) GetCPUEstimation(s *model.AggregateContainerSta
 	return model.CPUAmountFromCores(s.AggregateCPUUsage.Percentile(e.percentile))
 }
 
+// GetCPUEstimation returns the highest percentile of CPU usage among all hours
+// of the week, so that recurring peaks (e.g. a nightly batch) are covered even
+// if they are rare in the overall usage. Falls back to the percentile of all
+// samples if no per-hour histograms were collected.
+func (e *seasonalCPUEstimator) GetCPUEstimation(s *model.AggregateContainerState) model.ResourceAmount {
+	peak := -1.0
+	for _, histogram := range s.AggregateCPUUsageByHourOfWeek {
+		if histogram == nil || histogram.IsEmpty() {
+			continue
+		}
+		peak = math.Max(peak, histogram.Percentile(e.percentile))
+	}
+	if peak < 0 {
+		return model.CPUAmountFromCores(s.AggregateCPUUsage.Percentile(e.percentile))
+	}
+	return model.CPUAmountFromCores(peak)
+}
+
 func (e *percentileMemoryEstimator) GetMemoryEstimation(s *model.AggregateContainerState) model.ResourceAmount {
 	return model.MemoryAmountFromBytes(s.AggregateMemoryPeaks.Percentile(e.percentile))
 }
@@ -204,6 +236,24 @@ func (e *memoryConfidenceMultiplier) GetMemoryEstimation(s *model.AggregateConta
 	return model.ScaleResource(base, math.Pow(1.+e.multiplier/confidence, e.exponent))
 }
 
+// WithHPACoordination returns a CPUEstimator that, for containers whose
+// recommendation is coordinated with a HorizontalPodAutoscaler, returns the
+// request at which the given percentile of the per-replica workload CPU usage
+// equals the HPA CPU utilization target. Other containers are estimated with
+// the base estimator.
+func WithHPACoordination(percentile float64, baseEstimator CPUEstimator) CPUEstimator {
+	return &hpaCoordinatedCPUEstimator{percentile: percentile, baseEstimator: baseEstimator}
+}
+
+func (e *hpaCoordinatedCPUEstimator) GetCPUEstimation(s *model.AggregateContainerState) model.ResourceAmount {
+	h := s.HorizontalScaling
+	if h == nil || h.TargetCPUUtilization <= 0 || h.AggregateCPUUsagePerReplica.IsEmpty() {
+		return e.baseEstimator.GetCPUEstimation(s)
+	}
+	usagePerReplica := h.AggregateCPUUsagePerReplica.Percentile(e.percentile)
+	return model.CPUAmountFromCores(usagePerReplica / h.TargetCPUUtilization)
+}
+
 // WithCPUMinResource returns a CPUEstimator that returns at least minResource
 func WithCPUMinResource(minResource model.ResourceAmount, baseEstimator CPUEstimator) CPUEstimator {
 	return &cpuMinResourceEstimator{minResource, baseEstimator}
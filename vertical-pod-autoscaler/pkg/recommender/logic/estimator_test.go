@@ -190,3 +190,57 @@ func TestMinResourcesEstimator(t *testing.T) {
 	memoryEstimation := memoryEstimator.GetMemoryEstimation(s)
 	assert.Equal(t, 4e8, model.BytesFromMemoryAmount(memoryEstimation))
 }
+
+// Verifies that the HPA coordinated estimator recommends the request at which
+// the per-replica workload usage matches the HPA CPU utilization target and
+// falls back to the base estimator otherwise.
+func TestHPACoordinatedCPUEstimator(t *testing.T) {
+	config := model.GetAggregationsConfig()
+	usagePerReplica := util.NewHistogram(config.CPUHistogramOptions)
+	usagePerReplica.AddSample(1.0, 1.0, anyTime)
+	usagePerReplica.AddSample(2.0, 1.0, anyTime)
+	usagePerReplica.AddSample(3.0, 1.0, anyTime)
+
+	baseEstimator := NewConstCPUEstimator(model.CPUAmountFromCores(5.0))
+	estimator := WithHPACoordination(0.5, baseEstimator)
+
+	s := model.NewAggregateContainerState()
+	assert.Equal(t, 5.0, model.CoresFromCPUAmount(estimator.GetCPUEstimation(s)))
+
+	s.HorizontalScaling = &model.HorizontalScalingState{
+		AggregateCPUUsagePerReplica: usagePerReplica,
+		TargetCPUUtilization:        0.5,
+	}
+	maxRelativeError := 0.05 // Allow 5% relative error to account for histogram rounding.
+	assert.InEpsilon(t, 4.0, model.CoresFromCPUAmount(estimator.GetCPUEstimation(s)), maxRelativeError)
+
+	s.HorizontalScaling.AggregateCPUUsagePerReplica = util.NewHistogram(config.CPUHistogramOptions)
+	assert.Equal(t, 5.0, model.CoresFromCPUAmount(estimator.GetCPUEstimation(s)))
+}
+
+// Verifies that the seasonalCPUEstimator returns the highest percentile among
+// the hours of the week and falls back to all samples without per-hour data.
+func TestSeasonalCPUEstimator(t *testing.T) {
+	config := model.GetAggregationsConfig()
+	cpuHistogram := util.NewHistogram(config.CPUHistogramOptions)
+	for range 20 {
+		cpuHistogram.AddSample(1.0, 1.0, anyTime)
+	}
+	cpuHistogram.AddSample(4.0, 1.0, anyTime)
+	estimator := NewSeasonalCPUEstimator(0.9)
+	maxRelativeError := 0.05 // Allow 5% relative error to account for histogram rounding.
+
+	s := &model.AggregateContainerState{AggregateCPUUsage: cpuHistogram}
+	assert.InEpsilon(t, 1.0, model.CoresFromCPUAmount(estimator.GetCPUEstimation(s)), maxRelativeError)
+
+	// The rare peak happens every night at 2am, so it dominates that hour.
+	s.AggregateCPUUsageByHourOfWeek = make([]util.Histogram, model.HoursPerWeek)
+	daytime := util.NewHistogram(config.CPUHistogramOptions)
+	daytime.AddSample(1.0, 1.0, anyTime)
+	night := util.NewHistogram(config.CPUHistogramOptions)
+	night.AddSample(4.0, 1.0, anyTime)
+	s.AggregateCPUUsageByHourOfWeek[12] = daytime
+	s.AggregateCPUUsageByHourOfWeek[2] = night
+	s.AggregateCPUUsageByHourOfWeek[26] = util.NewHistogram(config.CPUHistogramOptions)
+	assert.InEpsilon(t, 4.0, model.CoresFromCPUAmount(estimator.GetCPUEstimation(s)), maxRelativeError)
+}
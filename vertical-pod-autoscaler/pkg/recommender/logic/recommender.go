@@ -37,8 +37,20 @@ type RecommendationConfig struct {
 	LowerBoundMemoryPercentile float64
 	UpperBoundMemoryPercentile float64
 	ConfidenceIntervalMemory   time.Duration
+	// CPUEstimator is the name of the estimator used for CPU, one of
+	// PercentileCPUEstimatorName (default) and SeasonalCPUEstimatorName.
+	CPUEstimator string
 }
 
+const (
+	// PercentileCPUEstimatorName is the name of the estimator recommending a
+	// percentile of all CPU usage samples.
+	PercentileCPUEstimatorName = "percentile"
+	// SeasonalCPUEstimatorName is the name of the estimator recommending the
+	// highest percentile of CPU usage among the hours of the week.
+	SeasonalCPUEstimatorName = "seasonal"
+)
+
 // RecommendationFormat controls how numeric values are rendered in outputs.
 type RecommendationFormat struct {
 	HumanizeMemory     bool
@@ -74,6 +86,9 @@ type podResourceRecommender struct {
 	upperBoundMemory MemoryEstimator
 	minCPUMillicores float64
 	minMemoryMb      float64
+	// config is used to build the estimators of containers with their own
+	// estimation policy.
+	config RecommendationConfig
 }
 
 func (r *podResourceRecommender) GetRecommendedPodResources(containerNameToAggregateStateMap model.ContainerNameToAggregateStateMap) RecommendedPodResources {
@@ -86,7 +101,23 @@ func (r *podResourceRecommender) GetRecommendedPodResources(containerNameToAggre
 	minCPU := model.ScaleResource(model.CPUAmountFromCores(r.minCPUMillicores*0.001), fraction)
 	minMemory := model.ScaleResource(model.MemoryAmountFromBytes(r.minMemoryMb*1024*1024), fraction)
 
-	recommender := &podResourceRecommender{
+	recommender := r.withMinResources(minCPU, minMemory)
+
+	for containerName, aggregatedContainerState := range containerNameToAggregateStateMap {
+		containerRecommender := recommender
+		if aggregatedContainerState.EstimationPolicy != nil {
+			config := r.config.withEstimationPolicy(aggregatedContainerState.EstimationPolicy)
+			containerRecommender = newPodResourceRecommender(config).withMinResources(minCPU, minMemory)
+		}
+		recommendation[containerName] = containerRecommender.estimateContainerResources(aggregatedContainerState)
+	}
+	return recommendation
+}
+
+// withMinResources returns a copy of the recommender with all estimations
+// raised to at least the given minimum resources.
+func (r *podResourceRecommender) withMinResources(minCPU, minMemory model.ResourceAmount) *podResourceRecommender {
+	return &podResourceRecommender{
 		targetCPU:        WithCPUMinResource(minCPU, r.targetCPU),
 		targetMemory:     WithMemoryMinResource(minMemory, r.targetMemory),
 		lowerBoundCPU:    WithCPUMinResource(minCPU, r.lowerBoundCPU),
@@ -95,12 +126,8 @@ func (r *podResourceRecommender) GetRecommendedPodResources(containerNameToAggre
 		upperBoundMemory: WithMemoryMinResource(minMemory, r.upperBoundMemory),
 		minCPUMillicores: r.minCPUMillicores,
 		minMemoryMb:      r.minMemoryMb,
+		config:           r.config,
 	}
-
-	for containerName, aggregatedContainerState := range containerNameToAggregateStateMap {
-		recommendation[containerName] = recommender.estimateContainerResources(aggregatedContainerState)
-	}
-	return recommendation
 }
 
 // Takes AggregateContainerState and returns a container recommendation.
@@ -127,11 +154,45 @@ func FilterControlledResources(estimation model.Resources, controlledResources [
 	return result
 }
 
+// withEstimationPolicy returns a copy of the config with the parameters set in
+// the estimation policy of a container.
+func (config RecommendationConfig) withEstimationPolicy(policy *model.EstimationPolicy) RecommendationConfig {
+	override := func(value *float64, policyValue *float64) {
+		if policyValue != nil {
+			*value = *policyValue
+		}
+	}
+	override(&config.TargetCPUPercentile, policy.TargetCPUPercentile)
+	override(&config.LowerBoundCPUPercentile, policy.LowerBoundCPUPercentile)
+	override(&config.UpperBoundCPUPercentile, policy.UpperBoundCPUPercentile)
+	override(&config.TargetMemoryPercentile, policy.TargetMemoryPercentile)
+	override(&config.LowerBoundMemoryPercentile, policy.LowerBoundMemoryPercentile)
+	override(&config.UpperBoundMemoryPercentile, policy.UpperBoundMemoryPercentile)
+	override(&config.SafetyMarginFraction, policy.SafetyMarginFraction)
+	if policy.CPUEstimator != nil {
+		switch *policy.CPUEstimator {
+		case vpa_types.CPUEstimatorPercentile:
+			config.CPUEstimator = PercentileCPUEstimatorName
+		case vpa_types.CPUEstimatorSeasonal:
+			config.CPUEstimator = SeasonalCPUEstimatorName
+		}
+	}
+	return config
+}
+
 // CreatePodResourceRecommender returns the primary recommender.
 func CreatePodResourceRecommender(config RecommendationConfig) PodResourceRecommender {
-	targetCPU := NewPercentileCPUEstimator(config.TargetCPUPercentile)
-	lowerBoundCPU := NewPercentileCPUEstimator(config.LowerBoundCPUPercentile)
-	upperBoundCPU := NewPercentileCPUEstimator(config.UpperBoundCPUPercentile)
+	return newPodResourceRecommender(config)
+}
+
+func newPodResourceRecommender(config RecommendationConfig) *podResourceRecommender {
+	newCPUEstimator := NewPercentileCPUEstimator
+	if config.CPUEstimator == SeasonalCPUEstimatorName {
+		newCPUEstimator = NewSeasonalCPUEstimator
+	}
+	targetCPU := newCPUEstimator(config.TargetCPUPercentile)
+	lowerBoundCPU := newCPUEstimator(config.LowerBoundCPUPercentile)
+	upperBoundCPU := newCPUEstimator(config.UpperBoundCPUPercentile)
 
 	// Create base memory estimators
 	targetMemory := NewPercentileMemoryEstimator(config.TargetMemoryPercentile)
@@ -147,6 +208,14 @@ func CreatePodResourceRecommender(config RecommendationConfig) PodResourceRecomm
 	lowerBoundMemory = WithMemoryMargin(config.SafetyMarginFraction, lowerBoundMemory)
 	upperBoundMemory = WithMemoryMargin(config.SafetyMarginFraction, upperBoundMemory)
 
+	// For containers coordinated with a HorizontalPodAutoscaler the CPU is
+	// estimated from the usage of the whole workload instead. The utilization
+	// target of the HPA already provides the headroom, so no safety margin is
+	// added on top of it.
+	targetCPU = WithHPACoordination(config.TargetCPUPercentile, targetCPU)
+	lowerBoundCPU = WithHPACoordination(config.LowerBoundCPUPercentile, lowerBoundCPU)
+	upperBoundCPU = WithHPACoordination(config.UpperBoundCPUPercentile, upperBoundCPU)
+
 	// Apply confidence multiplier to the upper bound estimator. This means
 	// that the updater will be less eager to evict pods with short history
 	// in order to reclaim unused resources.
@@ -185,6 +254,7 @@ func CreatePodResourceRecommender(config RecommendationConfig) PodResourceRecomm
 		upperBoundMemory,
 		config.PodMinCPUMillicores,
 		config.PodMinMemoryMb,
+		config,
 	}
 }
 
@@ -18,9 +18,12 @@ package logic
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
 
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
 )
 
@@ -137,6 +140,56 @@ func TestControlledResourcesFilteredDefault(t *testing.T) {
 	assert.Contains(t, recommendedResources[containerName].UpperBound, model.ResourceCPU)
 }
 
+func TestEstimationPolicyOverridesConfig(t *testing.T) {
+	config := RecommendationConfig{
+		SafetyMarginFraction:       0.15,
+		TargetCPUPercentile:        0.9,
+		LowerBoundCPUPercentile:    0.5,
+		UpperBoundCPUPercentile:    0.95,
+		ConfidenceIntervalCPU:      24 * time.Hour,
+		TargetMemoryPercentile:     0.9,
+		LowerBoundMemoryPercentile: 0.5,
+		UpperBoundMemoryPercentile: 0.95,
+		ConfidenceIntervalMemory:   24 * time.Hour,
+		CPUEstimator:               PercentileCPUEstimatorName,
+	}
+	newState := func() *model.AggregateContainerState {
+		s := model.NewAggregateContainerState()
+		for i := range 20 {
+			sampleTime := anyTime.Add(time.Duration(i) * time.Hour)
+			usage := 1.0
+			if i%4 == 0 {
+				usage = 4.0
+			}
+			s.AddSample(&model.ContainerUsageSample{MeasureStart: sampleTime, Usage: model.CPUAmountFromCores(usage), Resource: model.ResourceCPU})
+			s.AddSample(&model.ContainerUsageSample{MeasureStart: sampleTime, Usage: model.MemoryAmountFromBytes(usage * 1e9), Resource: model.ResourceMemory})
+		}
+		return s
+	}
+	customState := newState()
+	customState.EstimationPolicy = &model.EstimationPolicy{
+		TargetCPUPercentile:  ptr.To(0.5),
+		SafetyMarginFraction: ptr.To(0.0),
+		CPUEstimator:         ptr.To(vpa_types.CPUEstimatorSeasonal),
+	}
+
+	recommendation := CreatePodResourceRecommender(config).GetRecommendedPodResources(model.ContainerNameToAggregateStateMap{
+		"default": newState(),
+		"custom":  customState,
+	})
+
+	expectedConfig := config
+	expectedConfig.TargetCPUPercentile = 0.5
+	expectedConfig.SafetyMarginFraction = 0
+	expectedConfig.CPUEstimator = SeasonalCPUEstimatorName
+	expected := CreatePodResourceRecommender(expectedConfig).GetRecommendedPodResources(model.ContainerNameToAggregateStateMap{
+		"custom": newState(),
+	})
+	assert.Equal(t, expected["custom"], recommendation["custom"])
+	assert.Less(t, recommendation["custom"].Target[model.ResourceCPU], recommendation["default"].Target[model.ResourceCPU])
+	assert.Less(t, recommendation["custom"].Target[model.ResourceMemory], recommendation["default"].Target[model.ResourceMemory])
+}
+
 func TestMapToListOfRecommendedContainerResources(t *testing.T) {
 	cases := []struct {
 		name         string
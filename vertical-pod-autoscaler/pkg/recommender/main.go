@@ -35,6 +35,7 @@ import (
 
 	"k8s.io/autoscaler/vertical-pod-autoscaler/common"
 	vpa_clientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
 	recommender_config "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/config"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/routines"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/client"
@@ -59,6 +60,11 @@ func main() {
 
 	config = recommender_config.InitRecommenderFlags()
 
+	if leaderElection.LeaderElect && features.Enabled(features.RecommenderSharding) {
+		klog.ErrorS(nil, "--leader-elect can't be used with the RecommenderSharding feature gate, all shards need to run.")
+		klog.FlushAndExit(klog.ExitFlushTimeout, 1)
+	}
+
 	klog.V(1).InfoS("Vertical Pod Autoscaler Recommender", "version", common.VerticalPodAutoscalerVersion(), "recommenderName", config.RecommenderName)
 
 	ctx := context.Background()
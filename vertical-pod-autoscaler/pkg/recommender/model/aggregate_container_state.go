@@ -60,6 +60,10 @@ const (
 	// version of the recommender binary can't initialize from the old checkpoint format or the
 	// previous version of the recommender binary can't initialize from the new checkpoint format.
 	SupportedCheckpointVersion = "v3"
+
+	// HoursPerWeek is the number of hours of the week that have separate CPU
+	// usage histograms when seasonal CPU histograms are enabled.
+	HoursPerWeek = 7 * 24
 )
 
 var (
@@ -104,6 +108,10 @@ type AggregateContainerState struct {
 	// AggregateMemoryPeaks is a distribution of memory peaks from all containers:
 	// each container should add one peak per memory aggregation interval (e.g. once every 24h).
 	AggregateMemoryPeaks util.Histogram
+	// AggregateCPUUsageByHourOfWeek holds a distribution of CPU samples for every
+	// hour of the week (see HourOfWeek()). It is nil unless seasonal CPU
+	// histograms are enabled, and histograms of hours without samples are nil.
+	AggregateCPUUsageByHourOfWeek []util.Histogram
 	// Note: first/last sample timestamps as well as the sample count are based only on CPU samples.
 	FirstSampleStart  time.Time
 	LastSampleStart   time.Time
@@ -125,9 +133,43 @@ type AggregateContainerState struct {
 	MemoryAggregationIntervalCount    int64
 	ControlledResources               *[]ResourceName
 
+	// HorizontalScaling is set if the recommendation for this container should
+	// be coordinated with a HorizontalPodAutoscaler. Nil otherwise.
+	HorizontalScaling *HorizontalScalingState
+
+	// EstimationPolicy holds the estimation parameters set in the container
+	// resource policy of the VPA. Nil if the policy doesn't set any.
+	EstimationPolicy *EstimationPolicy
+
 	mutex sync.RWMutex
 }
 
+// EstimationPolicy holds per-container overrides of the parameters used to
+// compute the recommendation. Fields that are nil use the recommender
+// defaults.
+type EstimationPolicy struct {
+	TargetCPUPercentile        *float64
+	LowerBoundCPUPercentile    *float64
+	UpperBoundCPUPercentile    *float64
+	TargetMemoryPercentile     *float64
+	LowerBoundMemoryPercentile *float64
+	UpperBoundMemoryPercentile *float64
+	SafetyMarginFraction       *float64
+	CPUEstimator               *vpa_types.CPUEstimator
+}
+
+// HorizontalScalingState holds the signals needed to coordinate the CPU
+// recommendation with a HorizontalPodAutoscaler scaling the same workload.
+type HorizontalScalingState struct {
+	// AggregateCPUUsagePerReplica is a distribution of the CPU usage of the
+	// container summed over the whole workload and divided by the number of
+	// replicas observed at the time of the sample.
+	AggregateCPUUsagePerReplica util.Histogram
+	// TargetCPUUtilization is the CPU utilization target of the
+	// HorizontalPodAutoscaler, as a fraction of the request.
+	TargetCPUUtilization float64
+}
+
 // GetLastRecommendation returns last recorded recommendation in a thread-safe manner.
 func (a *AggregateContainerState) GetLastRecommendation() corev1.ResourceList {
 	a.mutex.RLock()
@@ -197,6 +239,14 @@ func (a *AggregateContainerState) MarkNotAutoscaled() {
 func (a *AggregateContainerState) MergeContainerState(other *AggregateContainerState) {
 	a.AggregateCPUUsage.Merge(other.AggregateCPUUsage)
 	a.AggregateMemoryPeaks.Merge(other.AggregateMemoryPeaks)
+	if other.AggregateCPUUsageByHourOfWeek != nil {
+		a.enableSeasonalCPUHistograms()
+		for hour, histogram := range other.AggregateCPUUsageByHourOfWeek {
+			if histogram != nil {
+				a.cpuHistogramForHourOfWeek(hour).Merge(histogram)
+			}
+		}
+	}
 
 	if a.FirstSampleStart.IsZero() ||
 		(!other.FirstSampleStart.IsZero() && other.FirstSampleStart.Before(a.FirstSampleStart)) {
@@ -211,7 +261,7 @@ func (a *AggregateContainerState) MergeContainerState(other *AggregateContainerS
 // NewAggregateContainerState returns a new, empty AggregateContainerState.
 func NewAggregateContainerState() *AggregateContainerState {
 	config := GetAggregationsConfig()
-	return &AggregateContainerState{
+	a := &AggregateContainerState{
 		AggregateCPUUsage:                 util.NewDecayingHistogram(config.CPUHistogramOptions, config.CPUHistogramDecayHalfLife),
 		AggregateMemoryPeaks:              util.NewDecayingHistogram(config.MemoryHistogramOptions, config.MemoryHistogramDecayHalfLife),
 		CreationTime:                      time.Now(),
@@ -220,6 +270,36 @@ func NewAggregateContainerState() *AggregateContainerState {
 		MemoryAggregationIntervalDuration: config.MemoryAggregationIntervalDuration,
 		MemoryAggregationIntervalCount:    config.MemoryAggregationIntervalCount,
 	}
+	if config.SeasonalCPUHistograms {
+		a.enableSeasonalCPUHistograms()
+	}
+	return a
+}
+
+// HourOfWeek returns the hour of the week of the given time in UTC, counted
+// from Sunday 00:00.
+func HourOfWeek(t time.Time) int {
+	t = t.UTC()
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+// enableSeasonalCPUHistograms starts collecting CPU usage histograms for
+// every hour of the week, if they aren't collected yet.
+func (a *AggregateContainerState) enableSeasonalCPUHistograms() {
+	if a.AggregateCPUUsageByHourOfWeek == nil {
+		a.AggregateCPUUsageByHourOfWeek = make([]util.Histogram, HoursPerWeek)
+	}
+}
+
+// cpuHistogramForHourOfWeek returns the CPU usage histogram of the given hour
+// of the week, creating it if needed. Must only be called if seasonal CPU
+// histograms are enabled for the state.
+func (a *AggregateContainerState) cpuHistogramForHourOfWeek(hour int) util.Histogram {
+	if a.AggregateCPUUsageByHourOfWeek[hour] == nil {
+		config := GetAggregationsConfig()
+		a.AggregateCPUUsageByHourOfWeek[hour] = util.NewDecayingHistogram(config.CPUHistogramOptions, config.SeasonalCPUHistogramDecayHalfLife)
+	}
+	return a.AggregateCPUUsageByHourOfWeek[hour]
 }
 
 // AddSample aggregates a single usage sample.
@@ -252,6 +332,10 @@ func (a *AggregateContainerState) addCPUSample(sample *ContainerUsageSample) {
 	cpuUsageCores := CoresFromCPUAmount(sample.Usage)
 	a.AggregateCPUUsage.AddSample(
 		cpuUsageCores, minSampleWeight, sample.MeasureStart)
+	if a.AggregateCPUUsageByHourOfWeek != nil {
+		a.cpuHistogramForHourOfWeek(HourOfWeek(sample.MeasureStart)).AddSample(
+			cpuUsageCores, minSampleWeight, sample.MeasureStart)
+	}
 	if sample.MeasureStart.After(a.LastSampleStart) {
 		a.LastSampleStart = sample.MeasureStart
 	}
@@ -272,14 +356,26 @@ func (a *AggregateContainerState) SaveToCheckpoint() (*vpa_types.VerticalPodAuto
 	if err != nil {
 		return nil, err
 	}
+	var seasonalCPU []vpa_types.HourOfWeekHistogramCheckpoint
+	for hour, histogram := range a.AggregateCPUUsageByHourOfWeek {
+		if histogram == nil || histogram.IsEmpty() {
+			continue
+		}
+		checkpoint, err := histogram.SaveToChekpoint()
+		if err != nil {
+			return nil, err
+		}
+		seasonalCPU = append(seasonalCPU, vpa_types.HourOfWeekHistogramCheckpoint{HourOfWeek: hour, Histogram: *checkpoint})
+	}
 	return &vpa_types.VerticalPodAutoscalerCheckpointStatus{
-		LastUpdateTime:    metav1.NewTime(time.Now()),
-		FirstSampleStart:  metav1.NewTime(a.FirstSampleStart),
-		LastSampleStart:   metav1.NewTime(a.LastSampleStart),
-		TotalSamplesCount: a.TotalSamplesCount,
-		MemoryHistogram:   *memory,
-		CPUHistogram:      *cpu,
-		Version:           SupportedCheckpointVersion,
+		LastUpdateTime:        metav1.NewTime(time.Now()),
+		FirstSampleStart:      metav1.NewTime(a.FirstSampleStart),
+		LastSampleStart:       metav1.NewTime(a.LastSampleStart),
+		TotalSamplesCount:     a.TotalSamplesCount,
+		MemoryHistogram:       *memory,
+		CPUHistogram:          *cpu,
+		SeasonalCPUHistograms: seasonalCPU,
+		Version:               SupportedCheckpointVersion,
 	}, nil
 }
 
@@ -300,6 +396,21 @@ func (a *AggregateContainerState) LoadFromCheckpoint(checkpoint *vpa_types.Verti
 	if err != nil {
 		return err
 	}
+	// Checkpoints written without seasonal CPU histograms leave them empty,
+	// they get filled by new samples.
+	if a.AggregateCPUUsageByHourOfWeek == nil {
+		return nil
+	}
+	for i := range checkpoint.SeasonalCPUHistograms {
+		hourCheckpoint := &checkpoint.SeasonalCPUHistograms[i]
+		if hourCheckpoint.HourOfWeek < 0 || hourCheckpoint.HourOfWeek >= HoursPerWeek {
+			return fmt.Errorf("invalid hour of week %d in seasonal CPU histograms", hourCheckpoint.HourOfWeek)
+		}
+		err = a.cpuHistogramForHourOfWeek(hourCheckpoint.HourOfWeek).LoadFromCheckpoint(&hourCheckpoint.Histogram)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -374,6 +485,56 @@ func (a *AggregateContainerState) UpdateFromPolicy(resourcePolicy *vpa_types.Con
 			}
 		}
 	}
+
+	a.EstimationPolicy = nil
+	if estimationPolicy := estimationPolicyFromResourcePolicy(resourcePolicy); estimationPolicy != nil {
+		if features.Enabled(features.PerVPAConfig) {
+			a.EstimationPolicy = estimationPolicy
+		} else {
+			klog.InfoS("estimation parameters are set but feature gate is disabled, falling back to default values", "flagName", features.PerVPAConfig)
+		}
+	}
+
+	// Histograms for every hour of the week are only kept for containers
+	// using the seasonal CPU estimator.
+	seasonalCPU := GetAggregationsConfig().SeasonalCPUHistograms
+	if a.EstimationPolicy != nil && a.EstimationPolicy.CPUEstimator != nil {
+		seasonalCPU = *a.EstimationPolicy.CPUEstimator == vpa_types.CPUEstimatorSeasonal
+	}
+	if seasonalCPU {
+		a.enableSeasonalCPUHistograms()
+	} else {
+		a.AggregateCPUUsageByHourOfWeek = nil
+	}
+}
+
+// estimationPolicyFromResourcePolicy returns the estimation parameters set in
+// the container resource policy, or nil if none are set.
+func estimationPolicyFromResourcePolicy(resourcePolicy *vpa_types.ContainerResourcePolicy) *EstimationPolicy {
+	if resourcePolicy == nil {
+		return nil
+	}
+	quantityToFloat64 := func(quantity *resource.Quantity) *float64 {
+		if quantity == nil {
+			return nil
+		}
+		value := float64(quantity.MilliValue()) / 1000.0
+		return &value
+	}
+	policy := &EstimationPolicy{
+		TargetCPUPercentile:        quantityToFloat64(resourcePolicy.TargetCPUPercentile),
+		LowerBoundCPUPercentile:    quantityToFloat64(resourcePolicy.LowerBoundCPUPercentile),
+		UpperBoundCPUPercentile:    quantityToFloat64(resourcePolicy.UpperBoundCPUPercentile),
+		TargetMemoryPercentile:     quantityToFloat64(resourcePolicy.TargetMemoryPercentile),
+		LowerBoundMemoryPercentile: quantityToFloat64(resourcePolicy.LowerBoundMemoryPercentile),
+		UpperBoundMemoryPercentile: quantityToFloat64(resourcePolicy.UpperBoundMemoryPercentile),
+		SafetyMarginFraction:       quantityToFloat64(resourcePolicy.SafetyMarginFraction),
+		CPUEstimator:               resourcePolicy.CPUEstimator,
+	}
+	if *policy == (EstimationPolicy{}) {
+		return nil
+	}
+	return policy
 }
 
 // AggregateStateByContainerName takes a set of AggregateContainerStates and merge them
@@ -22,9 +22,11 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
 
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
@@ -200,6 +202,61 @@ func TestAggregateContainerStateLoadFromCheckpoint(t *testing.T) {
 	assert.False(t, cs.AggregateMemoryPeaks.IsEmpty())
 }
 
+func TestAggregateContainerStateSeasonalCPUHistograms(t *testing.T) {
+	defaultConfig := GetAggregationsConfig()
+	seasonalConfig := *defaultConfig
+	seasonalConfig.SeasonalCPUHistograms = true
+	InitializeAggregationsConfig(&seasonalConfig)
+	defer InitializeAggregationsConfig(defaultConfig)
+
+	// Monday 02:30 UTC and Monday 14:30 UTC.
+	night := time.Date(2018, time.January, 1, 2, 30, 0, 0, time.UTC)
+	day := night.Add(12 * time.Hour)
+	assert.Equal(t, 26, HourOfWeek(night))
+	assert.Equal(t, 38, HourOfWeek(day))
+
+	cs := NewAggregateContainerState()
+	cs.AddSample(&ContainerUsageSample{MeasureStart: night, Usage: CPUAmountFromCores(4.0), Resource: ResourceCPU})
+	cs.AddSample(&ContainerUsageSample{MeasureStart: day, Usage: CPUAmountFromCores(0.1), Resource: ResourceCPU})
+	assert.False(t, cs.AggregateCPUUsageByHourOfWeek[26].IsEmpty())
+	assert.False(t, cs.AggregateCPUUsageByHourOfWeek[38].IsEmpty())
+	assert.Nil(t, cs.AggregateCPUUsageByHourOfWeek[0])
+
+	merged := NewAggregateContainerState()
+	merged.MergeContainerState(cs)
+	assert.True(t, merged.AggregateCPUUsageByHourOfWeek[26].Equals(cs.AggregateCPUUsageByHourOfWeek[26]))
+
+	checkpoint, err := cs.SaveToCheckpoint()
+	assert.NoError(t, err)
+	assert.Equal(t, SupportedCheckpointVersion, checkpoint.Version)
+	assert.Len(t, checkpoint.SeasonalCPUHistograms, 2)
+
+	loaded := NewAggregateContainerState()
+	assert.NoError(t, loaded.LoadFromCheckpoint(checkpoint))
+	assert.False(t, loaded.AggregateCPUUsageByHourOfWeek[26].IsEmpty())
+	assert.False(t, loaded.AggregateCPUUsageByHourOfWeek[38].IsEmpty())
+
+	// Checkpoints without seasonal histograms still load.
+	checkpoint.SeasonalCPUHistograms = nil
+	loaded = NewAggregateContainerState()
+	assert.NoError(t, loaded.LoadFromCheckpoint(checkpoint))
+	assert.Nil(t, loaded.AggregateCPUUsageByHourOfWeek[26])
+	assert.False(t, loaded.AggregateCPUUsage.IsEmpty())
+
+	checkpoint.SeasonalCPUHistograms = []vpa_types.HourOfWeekHistogramCheckpoint{{HourOfWeek: HoursPerWeek}}
+	assert.Error(t, NewAggregateContainerState().LoadFromCheckpoint(checkpoint))
+}
+
+func TestAggregateContainerStateSeasonalCPUHistogramsDisabled(t *testing.T) {
+	cs := NewAggregateContainerState()
+	cs.AddSample(&ContainerUsageSample{MeasureStart: testTimestamp, Usage: CPUAmountFromCores(1.0), Resource: ResourceCPU})
+	assert.Nil(t, cs.AggregateCPUUsageByHourOfWeek)
+
+	checkpoint, err := cs.SaveToCheckpoint()
+	assert.NoError(t, err)
+	assert.Empty(t, checkpoint.SeasonalCPUHistograms)
+}
+
 func TestAggregateContainerStateIsExpired(t *testing.T) {
 	cs := NewAggregateContainerState()
 	cs.LastSampleStart = testTimestamp
@@ -392,6 +449,65 @@ func TestUpdateFromPolicyMemoryAggregationIntervalCount(t *testing.T) {
 	}
 }
 
+func TestUpdateFromPolicyEstimationPolicy(t *testing.T) {
+	seasonal := vpa_types.CPUEstimatorSeasonal
+	testCases := []struct {
+		name             string
+		policy           *vpa_types.ContainerResourcePolicy
+		featureEnabled   bool
+		expectedPolicy   *EstimationPolicy
+		expectedSeasonal bool
+	}{
+		{
+			name: "Estimation parameters with feature enabled",
+			policy: &vpa_types.ContainerResourcePolicy{
+				TargetCPUPercentile:  ptr.To(resource.MustParse("0.95")),
+				SafetyMarginFraction: ptr.To(resource.MustParse("0.1")),
+				CPUEstimator:         &seasonal,
+			},
+			featureEnabled: true,
+			expectedPolicy: &EstimationPolicy{
+				TargetCPUPercentile:  ptr.To(0.95),
+				SafetyMarginFraction: ptr.To(0.1),
+				CPUEstimator:         &seasonal,
+			},
+			expectedSeasonal: true,
+		},
+		{
+			name: "Estimation parameters with feature disabled - falls back to default",
+			policy: &vpa_types.ContainerResourcePolicy{
+				TargetCPUPercentile: ptr.To(resource.MustParse("0.95")),
+				CPUEstimator:        &seasonal,
+			},
+			featureEnabled: false,
+		},
+		{
+			name:           "No estimation parameters",
+			policy:         &vpa_types.ContainerResourcePolicy{OOMBumpUpRatio: ptr.To(resource.MustParse("2"))},
+			featureEnabled: true,
+		},
+		{
+			name:           "Nil policy",
+			policy:         nil,
+			featureEnabled: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			featuregatetesting.SetFeatureGateDuringTest(t, features.MutableFeatureGate, features.PerVPAConfig, tc.featureEnabled)
+			cs := NewAggregateContainerState()
+			cs.UpdateFromPolicy(tc.policy)
+			assert.Equal(t, tc.expectedPolicy, cs.EstimationPolicy)
+			assert.Equal(t, tc.expectedSeasonal, cs.AggregateCPUUsageByHourOfWeek != nil)
+
+			// Switching back to the default estimator drops the per-hour histograms.
+			cs.UpdateFromPolicy(nil)
+			assert.Nil(t, cs.EstimationPolicy)
+			assert.Nil(t, cs.AggregateCPUUsageByHourOfWeek)
+		})
+	}
+}
+
 func TestAggregateContainerStateIsExpiredWithCustomIntervalCount(t *testing.T) {
 	defaultInterval := GetAggregationsConfig().MemoryAggregationIntervalDuration
 	customCount := int64(4)
@@ -51,6 +51,13 @@ type AggregationsConfig struct {
 	// CPUHistogramDecayHalfLife is the amount of time it takes a historical
 	// CPU usage sample to lose half of its weight.
 	CPUHistogramDecayHalfLife time.Duration
+	// SeasonalCPUHistograms enables collecting a separate CPU usage histogram
+	// for every hour of the week.
+	SeasonalCPUHistograms bool
+	// SeasonalCPUHistogramDecayHalfLife is the amount of time it takes a
+	// historical CPU usage sample in a histogram of a single hour of the week
+	// to lose half of its weight.
+	SeasonalCPUHistogramDecayHalfLife time.Duration
 	// OOMBumpUpRatio specifies the memory bump up ratio when OOM occurred.
 	OOMBumpUpRatio float64
 	// OOMMinBumpUp specifies the minimal increase of memory when OOM occurred in bytes.
@@ -75,6 +82,9 @@ const (
 	// DefaultCPUHistogramDecayHalfLife is the default value for CPUHistogramDecayHalfLife.
 	// CPU usage sample to lose half of its weight.
 	DefaultCPUHistogramDecayHalfLife = time.Hour * 24
+	// DefaultSeasonalCPUHistogramDecayHalfLife is the default value for SeasonalCPUHistogramDecayHalfLife.
+	// Each hour of the week only gets samples once a week, so they need to decay slower.
+	DefaultSeasonalCPUHistogramDecayHalfLife = time.Hour * 24 * 14
 	// DefaultOOMBumpUpRatio is the default value for OOMBumpUpRatio.
 	DefaultOOMBumpUpRatio float64 = 1.2 // Memory is increased by 20% after an OOMKill.
 	// DefaultOOMMinBumpUp is the default value for OOMMinBumpUp.
@@ -118,6 +128,7 @@ func NewAggregationsConfig(memoryAggregationIntervalDuration time.Duration, memo
 		HistogramBucketSizeGrowth:         DefaultHistogramBucketSizeGrowth,
 		MemoryHistogramDecayHalfLife:      memoryHistogramDecayHalfLife,
 		CPUHistogramDecayHalfLife:         cpuHistogramDecayHalfLife,
+		SeasonalCPUHistogramDecayHalfLife: DefaultSeasonalCPUHistogramDecayHalfLife,
 		OOMBumpUpRatio:                    oomBumpUpRatio,
 		OOMMinBumpUp:                      oomMinBumpUp,
 	}
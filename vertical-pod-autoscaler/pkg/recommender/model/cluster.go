@@ -28,6 +28,7 @@ import (
 	"k8s.io/klog/v2"
 
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
 	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/controller_fetcher"
 	vpa_utils "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
 )
@@ -53,6 +54,7 @@ type ClusterState interface {
 	RecordOOM(containerID ContainerID, timestamp time.Time, requestedMemory ResourceAmount) error
 	AddOrUpdateVpa(apiObject *vpa_types.VerticalPodAutoscaler, selector labels.Selector) error
 	DeleteVpa(vpaID VpaID) error
+	DeleteVpaAndAggregateStates(vpaID VpaID) error
 	MakeAggregateStateKey(pod *PodState, containerName string) AggregateStateKey
 	RateLimitedGarbageCollectAggregateCollectionStates(ctx context.Context, now time.Time, controllerFetcher controllerfetcher.ControllerFetcher)
 	RecordRecommendation(vpa *Vpa, now time.Time) error
@@ -325,6 +327,7 @@ func (cluster *clusterState) AddOrUpdateVpa(apiObject *vpa_types.VerticalPodAuto
 	vpa.SetUpdateMode(apiObject.Spec.UpdatePolicy)
 	vpa.SetResourcePolicy(apiObject.Spec.ResourcePolicy)
 	vpa.SetAPIVersion(apiObject.GetObjectKind().GroupVersionKind().Version)
+	vpa.SetHPACoordination(features.Enabled(features.HPACoordination) && vpa_utils.HasHPACoordination(apiObject))
 	vpa.Generation = apiObject.Generation
 	return nil
 }
@@ -345,6 +348,31 @@ func (cluster *clusterState) DeleteVpa(vpaID VpaID) error {
 	return nil
 }
 
+// DeleteVpaAndAggregateStates removes a VPA with the given ID and the
+// aggregated states of its containers from the clusterState. DeleteVpa keeps
+// the states until they are garbage collected; this is for VPAs whose history
+// is kept elsewhere from now on, e.g. by another recommender shard.
+func (cluster *clusterState) DeleteVpaAndAggregateStates(vpaID VpaID) error {
+	vpa, vpaExists := cluster.vpas[vpaID]
+	if !vpaExists {
+		return NewKeyError(vpaID)
+	}
+	keys := make([]AggregateStateKey, 0, len(vpa.aggregateContainerStates))
+	for key := range vpa.aggregateContainerStates {
+		keys = append(keys, key)
+	}
+	if err := cluster.DeleteVpa(vpaID); err != nil {
+		return err
+	}
+	for _, key := range keys {
+		delete(cluster.aggregateStateMap, key)
+		for _, otherVpa := range cluster.vpas {
+			otherVpa.DeleteAggregation(key)
+		}
+	}
+	return nil
+}
+
 func (cluster *clusterState) VPAs() map[VpaID]*Vpa {
 	return cluster.vpas
 }
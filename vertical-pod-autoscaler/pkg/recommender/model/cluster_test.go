@@ -121,6 +121,25 @@ func TestClusterGCAggregateContainerStateDeletesOld(t *testing.T) {
 	assert.Empty(t, vpa.aggregateContainerStates)
 }
 
+func TestClusterDeleteVpaAndAggregateStates(t *testing.T) {
+	cluster := NewClusterState(testGcPeriod)
+	addTestVpa(cluster)
+	addTestPod(cluster)
+	assert.NoError(t, cluster.AddOrUpdateContainer(testContainerID, testRequest))
+	assert.NoError(t, cluster.AddSample(makeTestUsageSample()))
+	assert.NotEmpty(t, cluster.aggregateStateMap)
+
+	assert.NoError(t, cluster.DeleteVpaAndAggregateStates(testVpaID))
+	assert.Empty(t, cluster.VPAs())
+	assert.Empty(t, cluster.aggregateStateMap)
+
+	// A VPA added again doesn't get the old states.
+	vpa := addTestVpa(cluster)
+	assert.Empty(t, vpa.aggregateContainerStates)
+
+	assert.Error(t, cluster.DeleteVpaAndAggregateStates(VpaID{"namespace-1", "missing"}))
+}
+
 func TestClusterGCAggregateContainerStateDeletesOldEmpty(t *testing.T) {
 	ctx := context.Background()
 
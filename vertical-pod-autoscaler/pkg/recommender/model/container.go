@@ -47,6 +47,8 @@ type ContainerUsageSample struct {
 type ContainerState struct {
 	// Current request.
 	Request Resources
+	// Name of the image running within the container.
+	Image string
 	// Start of the latest CPU usage sample that was aggregated.
 	LastCPUSampleStart time.Time
 	// Max memory usage observed in the current aggregation interval.
@@ -0,0 +1,298 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// SeedSimilarity describes what a VPA has in common with the VPA it borrows
+// usage history from.
+type SeedSimilarity string
+
+const (
+	// SameOwnerLabels means that both VPAs select pods with the same labels,
+	// in different namespaces.
+	SameOwnerLabels SeedSimilarity = "SameOwnerLabels"
+	// SameImageRepository means that both containers run images from the same
+	// repository.
+	SameImageRepository SeedSimilarity = "SameImageRepository"
+)
+
+// ContainerSeed is the usage history a container of a VPA without history of
+// its own borrows from a container of a similar VPA.
+type ContainerSeed struct {
+	// State is the aggregated state of the source container, with the weight
+	// of its samples discounted.
+	State *AggregateContainerState
+	// Source is the VPA the history is borrowed from.
+	Source VpaID
+	// Similarity is what the source VPA has in common with the seeded one.
+	Similarity SeedSimilarity
+}
+
+// seedTarget is a container of a VPA that needs a seed.
+type seedTarget struct {
+	vpa           *Vpa
+	containerName string
+}
+
+// seedSource is a container of a VPA the history of which can be borrowed.
+type seedSource struct {
+	vpa           *Vpa
+	containerName string
+}
+
+func (s seedSource) less(other seedSource) bool {
+	if s.vpa.ID != other.vpa.ID {
+		return vpaIDLess(s.vpa.ID, other.vpa.ID)
+	}
+	return s.containerName < other.containerName
+}
+
+type vpaSeeder struct {
+	cluster ClusterState
+	weight  float64
+	// Aggregated state of the VPAs by container name, computed on demand.
+	states map[VpaID]ContainerNameToAggregateStateMap
+}
+
+// SeedVPAs borrows usage history from similar VPAs for the containers of VPAs
+// that have no usage history of their own, so that brand-new workloads get a
+// recommendation with a reasonable confidence right away.
+// A VPA is similar if it selects pods with the same labels in another
+// namespace or, for a single container, if a container of its pods runs an
+// image from the same repository. The weight of the borrowed samples is
+// multiplied by weight, and the borrowed history is dropped once the container
+// collected more samples of its own.
+func SeedVPAs(cluster ClusterState, weight float64) {
+	seeder := &vpaSeeder{
+		cluster: cluster,
+		weight:  weight,
+		states:  make(map[VpaID]ContainerNameToAggregateStateMap),
+	}
+
+	var targets []seedTarget
+	for _, vpa := range cluster.VPAs() {
+		states := seeder.aggregateState(vpa)
+		for containerName, seed := range vpa.ContainerSeeds {
+			state, found := states[containerName]
+			if !found || state.TotalSamplesCount > seed.State.TotalSamplesCount {
+				klog.V(4).InfoS("Dropping recommendation seed", "vpa", klog.KRef(vpa.ID.Namespace, vpa.ID.VpaName), "container", containerName)
+				delete(vpa.ContainerSeeds, containerName)
+			}
+		}
+		for containerName, state := range states {
+			if _, seeded := vpa.ContainerSeeds[containerName]; !seeded && state.isEmpty() {
+				targets = append(targets, seedTarget{vpa: vpa, containerName: containerName})
+			}
+		}
+	}
+	if len(targets) == 0 {
+		return
+	}
+	sort.Slice(targets, func(i, j int) bool {
+		if targets[i].vpa.ID != targets[j].vpa.ID {
+			return vpaIDLess(targets[i].vpa.ID, targets[j].vpa.ID)
+		}
+		return targets[i].containerName < targets[j].containerName
+	})
+
+	targetRepositories := make(map[seedTarget]string)
+	for _, target := range targets {
+		if repository := seeder.imageRepository(target); repository != "" {
+			targetRepositories[target] = repository
+		}
+	}
+	imageSources := seeder.imageSources(targetRepositories)
+
+	for _, target := range targets {
+		source, found := seeder.ownerLabelsSource(target)
+		similarity := SameOwnerLabels
+		if !found {
+			source, found = seeder.bestSource(target, imageSources[targetRepositories[target]])
+			similarity = SameImageRepository
+		}
+		if !found {
+			continue
+		}
+		state, err := discountedCopy(seeder.aggregateState(source.vpa)[source.containerName], weight)
+		if err != nil {
+			klog.ErrorS(err, "Cannot seed recommendation", "vpa", klog.KRef(target.vpa.ID.Namespace, target.vpa.ID.VpaName), "container", target.containerName)
+			continue
+		}
+		if state.isEmpty() {
+			continue
+		}
+		klog.V(3).InfoS("Seeding recommendation from a similar VPA", "vpa", klog.KRef(target.vpa.ID.Namespace, target.vpa.ID.VpaName), "container", target.containerName,
+			"source", klog.KRef(source.vpa.ID.Namespace, source.vpa.ID.VpaName), "sourceContainer", source.containerName, "similarity", similarity)
+		target.vpa.ContainerSeeds[target.containerName] = &ContainerSeed{
+			State:      state,
+			Source:     source.vpa.ID,
+			Similarity: similarity,
+		}
+	}
+}
+
+// aggregateState returns the aggregated state of the VPA by container name,
+// without seeds.
+func (s *vpaSeeder) aggregateState(vpa *Vpa) ContainerNameToAggregateStateMap {
+	states, found := s.states[vpa.ID]
+	if !found {
+		states = vpa.AggregateStateByContainerName()
+		s.states[vpa.ID] = states
+	}
+	return states
+}
+
+// ownerLabelsSource returns the container with the same name as the target
+// container of a VPA in another namespace selecting pods with the same labels
+// as the target VPA.
+func (s *vpaSeeder) ownerLabelsSource(target seedTarget) (seedSource, bool) {
+	if target.vpa.PodSelector == nil || target.vpa.PodSelector.Empty() {
+		return seedSource{}, false
+	}
+	selector := target.vpa.PodSelector.String()
+	var candidates []seedSource
+	for _, vpa := range s.cluster.VPAs() {
+		if vpa.ID.Namespace != target.vpa.ID.Namespace && vpa.PodSelector != nil && vpa.PodSelector.String() == selector {
+			candidates = append(candidates, seedSource{vpa: vpa, containerName: target.containerName})
+		}
+	}
+	return s.bestSource(target, candidates)
+}
+
+// bestSource returns the candidate with the most samples, if any has samples.
+func (s *vpaSeeder) bestSource(target seedTarget, candidates []seedSource) (seedSource, bool) {
+	var best seedSource
+	bestSamples := 0
+	for _, candidate := range candidates {
+		if candidate.vpa.ID == target.vpa.ID {
+			continue
+		}
+		state, found := s.aggregateState(candidate.vpa)[candidate.containerName]
+		if !found || state.isEmpty() {
+			continue
+		}
+		if state.TotalSamplesCount > bestSamples || (state.TotalSamplesCount == bestSamples && candidate.less(best)) {
+			best = candidate
+			bestSamples = state.TotalSamplesCount
+		}
+	}
+	return best, bestSamples > 0
+}
+
+// imageRepository returns the repository of the image run by the target
+// container in the pods of its VPA.
+func (s *vpaSeeder) imageRepository(target seedTarget) string {
+	pods := s.cluster.Pods()
+	for _, podID := range s.cluster.GetMatchingPods(target.vpa) {
+		pod, found := pods[podID]
+		if !found {
+			continue
+		}
+		if container, found := pod.Containers[target.containerName]; found && container.Image != "" {
+			return imageRepository(container.Image)
+		}
+	}
+	return ""
+}
+
+// imageSources returns the containers running images from the given
+// repositories in pods controlled by a VPA, keyed by the repository.
+func (s *vpaSeeder) imageSources(targetRepositories map[seedTarget]string) map[string][]seedSource {
+	repositories := make(map[string]bool)
+	for _, repository := range targetRepositories {
+		repositories[repository] = true
+	}
+	sources := make(map[string][]seedSource)
+	if len(repositories) == 0 {
+		return sources
+	}
+	added := make(map[string]map[seedSource]bool)
+	for _, pod := range s.cluster.Pods() {
+		var controllingVPA *Vpa
+		for containerName, container := range pod.Containers {
+			repository := imageRepository(container.Image)
+			if container.Image == "" || !repositories[repository] {
+				continue
+			}
+			if controllingVPA == nil {
+				if controllingVPA = s.cluster.GetControllingVPA(pod); controllingVPA == nil {
+					break
+				}
+			}
+			source := seedSource{vpa: controllingVPA, containerName: containerName}
+			if added[repository] == nil {
+				added[repository] = make(map[seedSource]bool)
+			}
+			if !added[repository][source] {
+				added[repository][source] = true
+				sources[repository] = append(sources[repository], source)
+			}
+		}
+	}
+	return sources
+}
+
+// imageRepository strips the tag and the digest from an image reference.
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
+
+// discountedCopy returns a copy of the aggregated state with the weight of the
+// samples, their count and the time they span multiplied by weight, so that
+// the copy contributes accordingly less to the confidence of a recommendation.
+func discountedCopy(state *AggregateContainerState, weight float64) (*AggregateContainerState, error) {
+	checkpoint, err := state.SaveToCheckpoint()
+	if err != nil {
+		return nil, fmt.Errorf("cannot save aggregated state: %w", err)
+	}
+	checkpoint.CPUHistogram.TotalWeight *= weight
+	checkpoint.MemoryHistogram.TotalWeight *= weight
+	for i := range checkpoint.SeasonalCPUHistograms {
+		checkpoint.SeasonalCPUHistograms[i].Histogram.TotalWeight *= weight
+	}
+	checkpoint.TotalSamplesCount = int(float64(checkpoint.TotalSamplesCount) * weight)
+	lifespan := checkpoint.LastSampleStart.Sub(checkpoint.FirstSampleStart.Time)
+	checkpoint.FirstSampleStart = metav1.NewTime(checkpoint.LastSampleStart.Add(-time.Duration(float64(lifespan) * weight)))
+
+	discounted := NewAggregateContainerState()
+	if err := discounted.LoadFromCheckpoint(checkpoint); err != nil {
+		return nil, fmt.Errorf("cannot load aggregated state: %w", err)
+	}
+	return discounted, nil
+}
+
+func vpaIDLess(a, b VpaID) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.VpaName < b.VpaName
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+// addSeedTestWorkload adds a VPA selecting pods with the app label set to app
+// and a pod of it running a container with the given image, which reports the
+// given number of CPU usage samples, one per minute.
+func addSeedTestWorkload(t *testing.T, cluster ClusterState, id VpaID, app, containerName, image string, samples int) *Vpa {
+	vpa := addVpa(cluster, id, testAnnotations, "app = "+app, testTargetRef)
+	podID := PodID{Namespace: id.Namespace, PodName: id.VpaName + "-pod"}
+	cluster.AddOrUpdatePod(podID, map[string]string{"app": app}, corev1.PodRunning)
+	containerID := ContainerID{PodID: podID, ContainerName: containerName}
+	assert.NoError(t, cluster.AddOrUpdateContainer(containerID, testRequest))
+	cluster.GetContainer(containerID).Image = image
+	addSeedTestSamples(t, cluster, containerID, testTimestamp, samples)
+	return vpa
+}
+
+func addSeedTestSamples(t *testing.T, cluster ClusterState, containerID ContainerID, start time.Time, samples int) {
+	for i := 0; i < samples; i++ {
+		assert.NoError(t, cluster.AddSample(&ContainerUsageSampleWithKey{
+			ContainerUsageSample: ContainerUsageSample{
+				MeasureStart: start.Add(time.Duration(i) * time.Minute),
+				Usage:        CPUAmountFromCores(0.5),
+				Resource:     ResourceCPU,
+			},
+			Container: containerID,
+		}))
+	}
+}
+
+func TestSeedVPAsFromOwnerLabels(t *testing.T) {
+	cluster := NewClusterState(testGcPeriod)
+	source := addSeedTestWorkload(t, cluster, VpaID{"production", "web"}, "web", "app", "registry.example.com/web:v1", 100)
+	// Another VPA running the same image has more samples, but owner labels take precedence.
+	addSeedTestWorkload(t, cluster, VpaID{"production", "web-canary"}, "web-canary", "app", "registry.example.com/web:v1", 200)
+	target := addSeedTestWorkload(t, cluster, VpaID{"staging", "web"}, "web", "app", "registry.example.com/web:v2", 0)
+
+	SeedVPAs(cluster, 0.1)
+
+	assert.Empty(t, source.ContainerSeeds)
+	seed := target.ContainerSeeds["app"]
+	if assert.NotNil(t, seed) {
+		assert.Equal(t, source.ID, seed.Source)
+		assert.Equal(t, SameOwnerLabels, seed.Similarity)
+		assert.Equal(t, 10, seed.State.TotalSamplesCount)
+		sourceState := source.AggregateStateByContainerName()["app"]
+		assert.Equal(t, sourceState.LastSampleStart, seed.State.LastSampleStart)
+		assert.Equal(t, sourceState.LastSampleStart.Sub(sourceState.FirstSampleStart)/10, seed.State.LastSampleStart.Sub(seed.State.FirstSampleStart))
+	}
+
+	// Seeds are used for the recommendation, but not stored in checkpoints.
+	assert.True(t, target.AggregateStateByContainerName()["app"].isEmpty())
+	states := target.AggregateStateByContainerName()
+	target.MergeSeededState(states)
+	assert.Equal(t, 10, states["app"].TotalSamplesCount)
+
+	target.UpdateConditions(true)
+	assert.True(t, target.ConditionActive(vpa_types.RecommendationSeeded))
+	assert.Contains(t, target.GetConditionsMap()[vpa_types.RecommendationSeeded].Message, "container app from production/web (SameOwnerLabels)")
+}
+
+func TestSeedVPAsFromImageRepository(t *testing.T) {
+	cluster := NewClusterState(testGcPeriod)
+	addSeedTestWorkload(t, cluster, VpaID{"default", "cache"}, "cache", "redis", "redis:7.2", 300)
+	source := addSeedTestWorkload(t, cluster, VpaID{"default", "web"}, "web", "server", "registry.example.com/web:v1", 100)
+	target := addSeedTestWorkload(t, cluster, VpaID{"default", "web-v2"}, "web-v2", "app", "registry.example.com/web@sha256:0123", 0)
+
+	SeedVPAs(cluster, 0.5)
+
+	seed := target.ContainerSeeds["app"]
+	if assert.NotNil(t, seed) {
+		assert.Equal(t, source.ID, seed.Source)
+		assert.Equal(t, SameImageRepository, seed.Similarity)
+		assert.Equal(t, 50, seed.State.TotalSamplesCount)
+	}
+}
+
+func TestSeedVPAsWithoutSimilarWorkloads(t *testing.T) {
+	cluster := NewClusterState(testGcPeriod)
+	addSeedTestWorkload(t, cluster, VpaID{"default", "cache"}, "cache", "redis", "redis:7.2", 100)
+	// A similar workload without usage history can't seed the recommendation.
+	addSeedTestWorkload(t, cluster, VpaID{"production", "web"}, "web", "app", "registry.example.com/web:v1", 0)
+	target := addSeedTestWorkload(t, cluster, VpaID{"staging", "web"}, "web", "app", "registry.example.com/web:v2", 0)
+
+	SeedVPAs(cluster, 0.1)
+
+	assert.Empty(t, target.ContainerSeeds)
+	target.UpdateConditions(true)
+	assert.NotContains(t, target.GetConditionsMap(), vpa_types.RecommendationSeeded)
+}
+
+func TestSeedVPAsDropsSeed(t *testing.T) {
+	cluster := NewClusterState(testGcPeriod)
+	addSeedTestWorkload(t, cluster, VpaID{"production", "web"}, "web", "app", "registry.example.com/web:v1", 100)
+	target := addSeedTestWorkload(t, cluster, VpaID{"staging", "web"}, "web", "app", "registry.example.com/web:v1", 0)
+	SeedVPAs(cluster, 0.1)
+	assert.NotNil(t, target.ContainerSeeds["app"])
+	target.UpdateConditions(true)
+	assert.True(t, target.ConditionActive(vpa_types.RecommendationSeeded))
+
+	// The seed is kept until the container has more samples of its own.
+	containerID := ContainerID{PodID: PodID{Namespace: "staging", PodName: "web-pod"}, ContainerName: "app"}
+	addSeedTestSamples(t, cluster, containerID, testTimestamp.Add(time.Hour), 10)
+	SeedVPAs(cluster, 0.1)
+	assert.NotNil(t, target.ContainerSeeds["app"])
+
+	addSeedTestSamples(t, cluster, containerID, testTimestamp.Add(2*time.Hour), 1)
+	SeedVPAs(cluster, 0.1)
+	assert.Empty(t, target.ContainerSeeds)
+	target.UpdateConditions(true)
+	assert.NotContains(t, target.GetConditionsMap(), vpa_types.RecommendationSeeded)
+}
+
+func TestImageRepository(t *testing.T) {
+	for image, repository := range map[string]string{
+		"nginx":                              "nginx",
+		"nginx:1.25":                         "nginx",
+		"nginx@sha256:0123":                  "nginx",
+		"nginx:1.25@sha256:0123":             "nginx",
+		"registry.example.com:5000/nginx":    "registry.example.com:5000/nginx",
+		"registry.example.com:5000/nginx:v1": "registry.example.com:5000/nginx",
+	} {
+		assert.Equal(t, repository, imageRepository(image), image)
+	}
+}
@@ -17,8 +17,10 @@ limitations under the License.
 package model
 
 import (
+	"fmt"
 	"maps"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -28,6 +30,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/util"
 	metrics_quality "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/metrics/quality"
 	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
 )
@@ -151,6 +154,28 @@ func (vpa *Vpa) updateConditionsLocked(podsMatched bool) {
 	} else {
 		vpa.conditions.Set(vpa_types.RecommendationProvided, false, reason, msg)
 	}
+
+	if len(vpa.ContainerSeeds) > 0 {
+		vpa.conditions.Set(vpa_types.RecommendationSeeded, true, "SeededFromSimilarWorkloads", vpa.seedsMessageLocked())
+	} else {
+		delete(vpa.conditions, vpa_types.RecommendationSeeded)
+	}
+}
+
+// seedsMessageLocked describes the VPAs the containers borrow history from.
+// Caller must hold vpa.mutex (read or write lock).
+func (vpa *Vpa) seedsMessageLocked() string {
+	containerNames := make([]string, 0, len(vpa.ContainerSeeds))
+	for containerName := range vpa.ContainerSeeds {
+		containerNames = append(containerNames, containerName)
+	}
+	sort.Strings(containerNames)
+	descriptions := make([]string, 0, len(containerNames))
+	for _, containerName := range containerNames {
+		seed := vpa.ContainerSeeds[containerName]
+		descriptions = append(descriptions, fmt.Sprintf("container %s from %s/%s (%s)", containerName, seed.Source.Namespace, seed.Source.VpaName, seed.Similarity))
+	}
+	return "Recommendation seeded with usage history of similar workloads: " + strings.Join(descriptions, ", ")
 }
 
 // updateRecommendationLocked updates the recommendation and metrics.
@@ -193,6 +218,9 @@ type Vpa struct {
 	// Initial checkpoints of AggregateContainerStates for containers.
 	// The key is container name.
 	ContainersInitialAggregateState ContainerNameToAggregateStateMap
+	// Usage history borrowed from similar VPAs by containers without history of
+	// their own. The key is container name. Seeds are not stored in checkpoints.
+	ContainerSeeds map[string]*ContainerSeed
 	// UpdateMode describes how recommendations will be applied to pods
 	UpdateMode *vpa_types.UpdateMode
 	// Created denotes timestamp of the original VPA object creation
@@ -205,6 +233,17 @@ type Vpa struct {
 	TargetRef *autoscalingv1.CrossVersionObjectReference
 	// PodCount contains number of live Pods matching a given VPA object.
 	PodCount int
+	// HPACoordination is true if the CPU recommendation should be coordinated
+	// with the HorizontalPodAutoscaler scaling the same target.
+	HPACoordination bool
+	// HPATargetCPUUtilization is the CPU utilization target of the
+	// HorizontalPodAutoscaler scaling the same target, as a fraction of the
+	// request. Zero if no such HorizontalPodAutoscaler was found.
+	HPATargetCPUUtilization float64
+	// workloadCPUUsage maps container name to the distribution of the CPU usage
+	// of that container summed over all pods matching the VPA and divided by the
+	// number of those pods. Only populated if HPACoordination is set.
+	workloadCPUUsage map[string]util.Histogram
 
 	// mutex protects concurrent access to conditions and recommendation fields
 	mutex sync.RWMutex
@@ -221,9 +260,11 @@ func NewVpa(id VpaID, selector labels.Selector, created time.Time) *Vpa {
 		PodSelector:                     selector,
 		aggregateContainerStates:        make(aggregateContainerStatesMap),
 		ContainersInitialAggregateState: make(ContainerNameToAggregateStateMap),
+		ContainerSeeds:                  make(map[string]*ContainerSeed),
 		Created:                         created,
 		Annotations:                     make(vpaAnnotationsMap),
 		conditions:                      make(vpaConditionsMap),
+		workloadCPUUsage:                make(map[string]util.Histogram),
 		// APIVersion defaults to the version of the client used to read resources.
 		// If a new version is introduced that needs to be differentiated beyond the
 		// client conversion, this needs to be done based on the resource content.
@@ -312,14 +353,65 @@ func (vpa *Vpa) MergeCheckpointedState(aggregateContainerStateMap ContainerNameT
 	}
 }
 
+// MergeSeededState adds the usage history borrowed from similar VPAs to the
+// aggregations of the given aggregateStateMap.
+func (vpa *Vpa) MergeSeededState(aggregateContainerStateMap ContainerNameToAggregateStateMap) {
+	for containerName, seed := range vpa.ContainerSeeds {
+		if aggregateContainerState, found := aggregateContainerStateMap[containerName]; found {
+			aggregateContainerState.MergeContainerState(seed.State)
+		}
+	}
+}
+
 // AggregateStateByContainerName returns a map from container name to the aggregated state
 // of all containers with that name, belonging to pods matched by the VPA.
 func (vpa *Vpa) AggregateStateByContainerName() ContainerNameToAggregateStateMap {
 	containerNameToAggregateStateMap := AggregateStateByContainerName(vpa.aggregateContainerStates)
 	vpa.MergeCheckpointedState(containerNameToAggregateStateMap)
+	vpa.setHorizontalScalingState(containerNameToAggregateStateMap)
 	return containerNameToAggregateStateMap
 }
 
+// setHorizontalScalingState attaches the workload CPU usage and the HPA CPU
+// utilization target to the aggregations of containers that have them.
+func (vpa *Vpa) setHorizontalScalingState(aggregateContainerStateMap ContainerNameToAggregateStateMap) {
+	if !vpa.HPACoordination || vpa.HPATargetCPUUtilization <= 0 {
+		return
+	}
+	for containerName, aggregation := range aggregateContainerStateMap {
+		usage, found := vpa.workloadCPUUsage[containerName]
+		if !found || usage.IsEmpty() {
+			continue
+		}
+		aggregation.HorizontalScaling = &HorizontalScalingState{
+			AggregateCPUUsagePerReplica: usage,
+			TargetCPUUtilization:        vpa.HPATargetCPUUtilization,
+		}
+	}
+}
+
+// SetHPACoordination enables or disables coordination of the CPU recommendation
+// with a HorizontalPodAutoscaler. Disabling it drops the collected workload usage.
+func (vpa *Vpa) SetHPACoordination(enabled bool) {
+	vpa.HPACoordination = enabled
+	if !enabled {
+		vpa.HPATargetCPUUtilization = 0
+		vpa.workloadCPUUsage = make(map[string]util.Histogram)
+	}
+}
+
+// AddWorkloadCPUSample aggregates a single sample of the CPU usage of the given
+// container averaged over all replicas of the workload.
+func (vpa *Vpa) AddWorkloadCPUSample(containerName string, usagePerReplica ResourceAmount, measureStart time.Time) {
+	usage, found := vpa.workloadCPUUsage[containerName]
+	if !found {
+		config := GetAggregationsConfig()
+		usage = util.NewDecayingHistogram(config.CPUHistogramOptions, config.CPUHistogramDecayHalfLife)
+		vpa.workloadCPUUsage[containerName] = usage
+	}
+	usage.AddSample(CoresFromCPUAmount(usagePerReplica), minSampleWeight, measureStart)
+}
+
 // HasRecommendation returns if the VPA object contains any recommendation
 func (vpa *Vpa) HasRecommendation() bool {
 	vpa.mutex.RLock()
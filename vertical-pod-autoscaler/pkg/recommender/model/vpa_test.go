@@ -591,3 +591,27 @@ func (k mockAggregateStateKey) Labels() labels.Labels {
 	labels, _ := labels.ConvertSelectorToLabelsMap(k.labels)
 	return labels
 }
+
+func TestAggregateStateByContainerNameWithHPACoordination(t *testing.T) {
+	vpa := NewVpa(VpaID{Namespace: "ns", VpaName: "vpa"}, labels.Everything(), anyTime)
+	vpa.ContainersInitialAggregateState["app"] = NewAggregateContainerState()
+	vpa.ContainersInitialAggregateState["sidecar"] = NewAggregateContainerState()
+	vpa.SetHPACoordination(true)
+	vpa.AddWorkloadCPUSample("app", CPUAmountFromCores(2.0), anyTime)
+
+	// No HPA CPU utilization target known yet.
+	aggregations := vpa.AggregateStateByContainerName()
+	assert.Nil(t, aggregations["app"].HorizontalScaling)
+
+	vpa.HPATargetCPUUtilization = 0.5
+	aggregations = vpa.AggregateStateByContainerName()
+	if assert.NotNil(t, aggregations["app"].HorizontalScaling) {
+		assert.Equal(t, 0.5, aggregations["app"].HorizontalScaling.TargetCPUUtilization)
+		assert.False(t, aggregations["app"].HorizontalScaling.AggregateCPUUsagePerReplica.IsEmpty())
+	}
+	assert.Nil(t, aggregations["sidecar"].HorizontalScaling)
+
+	vpa.SetHPACoordination(false)
+	aggregations = vpa.AggregateStateByContainerName()
+	assert.Nil(t, aggregations["app"].HorizontalScaling)
+}
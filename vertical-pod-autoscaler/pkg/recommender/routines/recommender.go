@@ -65,6 +65,7 @@ type recommender struct {
 	lastAggregateContainerStateGC time.Time
 	recommendationPostProcessor   []RecommendationPostProcessor
 	updateWorkerCount             int
+	recommendationSeedWeight      float64
 }
 
 func (r *recommender) GetClusterState() model.ClusterState {
@@ -180,6 +181,11 @@ func (r *recommender) RunOnce() {
 	timer.ObserveStep("LoadMetrics")
 	klog.V(3).InfoS("ClusterState is tracking", "pods", len(r.clusterState.Pods()), "vpas", len(r.clusterState.VPAs()))
 
+	if r.recommendationSeedWeight > 0 {
+		model.SeedVPAs(r.clusterState, r.recommendationSeedWeight)
+		timer.ObserveStep("SeedVPAs")
+	}
+
 	r.UpdateVPAs()
 	timer.ObserveStep("UpdateVPAs")
 
@@ -210,6 +216,9 @@ type RecommenderFactory struct {
 	CheckpointsWriteTimeout time.Duration
 	UseCheckpoints          bool
 	UpdateWorkerCount       int
+	// RecommendationSeedWeight is the weight of the usage history VPAs without
+	// history of their own borrow from similar VPAs. Zero disables seeding.
+	RecommendationSeedWeight float64
 }
 
 // Make creates a new recommender instance,
@@ -230,6 +239,7 @@ func (c RecommenderFactory) Make() Recommender {
 		lastAggregateContainerStateGC: time.Now(),
 		lastCheckpointGC:              time.Now(),
 		updateWorkerCount:             c.UpdateWorkerCount,
+		recommendationSeedWeight:      c.RecommendationSeedWeight,
 	}
 	klog.V(3).InfoS("New Recommender created", "recommender", recommender)
 	return recommender
@@ -18,17 +18,21 @@ package routines
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/informers"
 	kube_client "k8s.io/client-go/kubernetes"
+	autoscalingv2listers "k8s.io/client-go/listers/autoscaling/v2"
 	"k8s.io/client-go/rest"
 	"k8s.io/klog/v2"
 	resourceclient "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 
 	vpa_clientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/checkpoint"
 	recommender_config "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/config"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input"
@@ -36,6 +40,7 @@ import (
 	input_metrics "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/input/metrics"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/logic"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/model"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/recommender/sharding"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
 	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/controller_fetcher"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/metrics"
@@ -77,14 +82,17 @@ func NewRecommenderController(
 	controllerFetcher := controllerfetcher.NewControllerFetcher(kubeConfig, kubeClient, factory, scaleCacheEntryFreshnessTime, scaleCacheEntryLifetime, scaleCacheEntryJitterFactor, stopCh)
 	podLister, oomObserver := input.NewPodListerAndOOMObserver(ctx, kubeClient, commonFlags.VpaObjectNamespace, stopCh)
 
-	model.InitializeAggregationsConfig(model.NewAggregationsConfig(
+	aggregationsConfig := model.NewAggregationsConfig(
 		config.MemoryAggregationInterval,
 		config.MemoryAggregationIntervalCount,
 		config.MemoryHistogramDecayHalfLife,
 		config.CpuHistogramDecayHalfLife,
 		config.OOMBumpUpRatio,
 		config.OOMMinBumpUp,
-	))
+	)
+	aggregationsConfig.SeasonalCPUHistograms = config.CPUEstimator == logic.SeasonalCPUEstimatorName
+	aggregationsConfig.SeasonalCPUHistogramDecayHalfLife = config.SeasonalCpuHistogramDecayHalfLife
+	model.InitializeAggregationsConfig(aggregationsConfig)
 
 	useCheckpoints := config.Storage != "prometheus"
 
@@ -118,21 +126,57 @@ func NewRecommenderController(
 
 	ignoredNamespaces := strings.Split(commonFlags.IgnoredVpaObjectNamespaces, ",")
 
+	var hpaLister autoscalingv2listers.HorizontalPodAutoscalerLister
+	if features.Enabled(features.HPACoordination) {
+		hpaLister = factory.Autoscaling().V2().HorizontalPodAutoscalers().Lister()
+	}
+
+	var checkpointStore checkpoint.CheckpointStore
+	if config.CheckpointStore == checkpoint.FileStoreName {
+		fileStore, err := checkpoint.NewFileCheckpointStore(config.CheckpointStorePath)
+		if err != nil {
+			return nil, err
+		}
+		checkpointStore = fileStore
+	} else {
+		checkpointStore = checkpoint.NewAPICheckpointStore(vpaClient.AutoscalingV1(), vpa_api_util.NewVpaCheckpointLister(vpaClient, stopCh, commonFlags.VpaObjectNamespace))
+	}
+
+	var sharder sharding.Sharder
+	if features.Enabled(features.RecommenderSharding) {
+		identity, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("unable to get hostname for the shard identity: %w", err)
+		}
+		leaseSharder := sharding.NewLeaseSharder(kubeClient, config.ShardLeaseNamespace, config.RecommenderName, identity, config.ShardLeaseDuration)
+		if err := leaseSharder.Start(ctx); err != nil {
+			return nil, err
+		}
+		klog.V(1).InfoS("Running as a recommender shard", "shard", identity, "shards", leaseSharder.Members())
+		sharder = leaseSharder
+	}
+
+	var recommendationSeedWeight float64
+	if features.Enabled(features.RecommendationSeeding) {
+		recommendationSeedWeight = config.RecommendationSeedWeight
+	}
+
 	clusterStateFeeder := input.ClusterStateFeederFactory{
-		PodLister:           podLister,
-		OOMObserver:         oomObserver,
-		KubeClient:          kubeClient,
-		MetricsClient:       input_metrics.NewMetricsClient(source, commonFlags.VpaObjectNamespace, "default-metrics-client"),
-		VpaCheckpointClient: vpaClient.AutoscalingV1(),
-		VpaLister:           vpa_api_util.NewVpasLister(vpaClient, stopCh, commonFlags.VpaObjectNamespace),
-		VpaCheckpointLister: vpa_api_util.NewVpaCheckpointLister(vpaClient, stopCh, commonFlags.VpaObjectNamespace),
-		ClusterState:        clusterState,
-		SelectorFetcher:     target.NewVpaTargetSelectorFetcher(kubeConfig, kubeClient, factory, stopCh),
-		MemorySaveMode:      config.MemorySaver,
-		ControllerFetcher:   controllerFetcher,
-		RecommenderName:     config.RecommenderName,
-		IgnoredNamespaces:   ignoredNamespaces,
-		VpaObjectNamespace:  commonFlags.VpaObjectNamespace,
+		PodLister:          podLister,
+		HpaLister:          hpaLister,
+		OOMObserver:        oomObserver,
+		KubeClient:         kubeClient,
+		MetricsClient:      input_metrics.NewMetricsClient(source, commonFlags.VpaObjectNamespace, "default-metrics-client"),
+		VpaLister:          vpa_api_util.NewVpasLister(vpaClient, stopCh, commonFlags.VpaObjectNamespace),
+		CheckpointStore:    checkpointStore,
+		ClusterState:       clusterState,
+		SelectorFetcher:    target.NewVpaTargetSelectorFetcher(kubeConfig, kubeClient, factory, stopCh),
+		MemorySaveMode:     config.MemorySaver,
+		ControllerFetcher:  controllerFetcher,
+		RecommenderName:    config.RecommenderName,
+		IgnoredNamespaces:  ignoredNamespaces,
+		VpaObjectNamespace: commonFlags.VpaObjectNamespace,
+		Sharder:            sharder,
 	}.Make()
 	controllerFetcher.Start(ctx, scaleCacheLoopPeriod)
 
@@ -140,7 +184,7 @@ func NewRecommenderController(
 		ClusterState:       clusterState,
 		ClusterStateFeeder: clusterStateFeeder,
 		ControllerFetcher:  controllerFetcher,
-		CheckpointWriter:   checkpoint.NewCheckpointWriter(clusterState, vpaClient.AutoscalingV1()),
+		CheckpointWriter:   checkpoint.NewCheckpointWriter(clusterState, checkpointStore),
 		VpaClient:          vpaClient.AutoscalingV1(),
 		PodResourceRecommender: logic.CreatePodResourceRecommender(logic.RecommendationConfig{
 			SafetyMarginFraction:       config.SafetyMarginFraction,
@@ -154,6 +198,7 @@ func NewRecommenderController(
 			LowerBoundMemoryPercentile: config.LowerBoundMemoryPercentile,
 			UpperBoundMemoryPercentile: config.UpperBoundMemoryPercentile,
 			ConfidenceIntervalMemory:   config.ConfidenceIntervalMemory,
+			CPUEstimator:               config.CPUEstimator,
 		}),
 		RecommendationFormat: logic.RecommendationFormat{
 			HumanizeMemory:     config.HumanizeMemory,
@@ -165,6 +210,7 @@ func NewRecommenderController(
 		CheckpointsWriteTimeout:      config.CheckpointsWriteTimeout,
 		UseCheckpoints:               useCheckpoints,
 		UpdateWorkerCount:            config.UpdateWorkerCount,
+		RecommendationSeedWeight:     recommendationSeedWeight,
 	}.Make()
 
 	if err := initHistoryProvider(ctx, recommender, config); err != nil {
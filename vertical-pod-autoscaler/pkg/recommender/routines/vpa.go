@@ -25,6 +25,7 @@ import (
 // GetContainerNameToAggregateStateMap returns ContainerNameToAggregateStateMap for pods.
 func GetContainerNameToAggregateStateMap(vpa *model.Vpa) model.ContainerNameToAggregateStateMap {
 	containerNameToAggregateStateMap := vpa.AggregateStateByContainerName()
+	vpa.MergeSeededState(containerNameToAggregateStateMap)
 	filteredContainerNameToAggregateStateMap := make(model.ContainerNameToAggregateStateMap)
 
 	for containerName, aggregatedContainerState := range containerNameToAggregateStateMap {
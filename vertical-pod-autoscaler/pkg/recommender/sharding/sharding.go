@@ -0,0 +1,254 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sharding splits the VPAs handled by a recommender between several
+// shards. Each shard holds a Lease labeled with the name of the recommender,
+// and owns the VPAs that hash to it among the shards with live Leases.
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kube_client "k8s.io/client-go/kubernetes"
+	typedcoordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// ShardGroupLabel is the label of the Leases of recommender shards. Its
+	// value is the name of the recommender.
+	ShardGroupLabel = "autoscaling.k8s.io/vpa-recommender-shard-group"
+
+	// expiredLeaseDeletionFactor is how many lease durations after its last
+	// renewal the Lease of a shard that went away is deleted.
+	expiredLeaseDeletionFactor = 10
+)
+
+// Sharder decides which VPAs a recommender shard handles.
+type Sharder interface {
+	// Owns returns true if the VPA is handled by this shard.
+	Owns(namespace, name string) bool
+}
+
+// LeaseSharder is a Sharder coordinating shards through Leases.
+type LeaseSharder struct {
+	client        typedcoordinationv1.LeaseInterface
+	group         string
+	identity      string
+	leaseDuration time.Duration
+	clock         clock.Clock
+
+	mutex   sync.RWMutex
+	members []string
+}
+
+// NewLeaseSharder returns a LeaseSharder for the shard with the given
+// identity, among the shards of the recommender group. The Leases of shards
+// are kept in the namespace.
+func NewLeaseSharder(kubeClient kube_client.Interface, namespace, group, identity string, leaseDuration time.Duration) *LeaseSharder {
+	return &LeaseSharder{
+		client:        kubeClient.CoordinationV1().Leases(namespace),
+		group:         group,
+		identity:      identity,
+		leaseDuration: leaseDuration,
+		clock:         clock.RealClock{},
+		members:       []string{identity},
+	}
+}
+
+// Start registers the shard, waits for shards started at the same time to
+// register too and refreshes the members. It then keeps the Lease of the
+// shard and the members up to date in the background until ctx is done, and
+// deletes the Lease of the shard, so that the other shards take over its VPAs
+// without waiting for it to expire.
+func (s *LeaseSharder) Start(ctx context.Context) error {
+	if err := s.renew(ctx); err != nil {
+		return fmt.Errorf("failed to register shard %s: %w", s.identity, err)
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.clock.After(s.renewInterval()):
+	}
+	if err := s.Sync(ctx); err != nil {
+		return err
+	}
+	go func() {
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			if err := s.Sync(ctx); err != nil {
+				klog.ErrorS(err, "Failed to sync recommender shards")
+			}
+		}, s.renewInterval())
+		deleteCtx, cancel := context.WithTimeout(context.Background(), s.renewInterval())
+		defer cancel()
+		if err := s.client.Delete(deleteCtx, s.leaseName(s.identity), metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			klog.ErrorS(err, "Failed to delete the Lease of the recommender shard", "shard", s.identity)
+		}
+	}()
+	return nil
+}
+
+// Sync renews the Lease of the shard and refreshes the members from the live
+// Leases of the group.
+func (s *LeaseSharder) Sync(ctx context.Context) error {
+	if err := s.renew(ctx); err != nil {
+		return fmt.Errorf("failed to renew the Lease of shard %s: %w", s.identity, err)
+	}
+	leases, err := s.client.List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{ShardGroupLabel: s.group}).String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list the Leases of recommender shards: %w", err)
+	}
+	now := s.clock.Now()
+	members := []string{s.identity}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == s.identity {
+			continue
+		}
+		expiry := leaseExpiry(lease)
+		if expiry.After(now) {
+			members = append(members, *lease.Spec.HolderIdentity)
+			continue
+		}
+		if expiry.Add(expiredLeaseDeletionFactor * s.leaseDuration).Before(now) {
+			klog.V(2).InfoS("Deleting the expired Lease of a recommender shard", "lease", klog.KObj(lease))
+			if err := s.client.Delete(ctx, lease.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+				klog.ErrorS(err, "Failed to delete the expired Lease of a recommender shard", "lease", klog.KObj(lease))
+			}
+		}
+	}
+	slices.Sort(members)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !slices.Equal(s.members, members) {
+		klog.V(1).InfoS("Recommender shards changed", "shards", members)
+		s.members = members
+	}
+	return nil
+}
+
+// Members returns the identities of the live shards, sorted.
+func (s *LeaseSharder) Members() []string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return slices.Clone(s.members)
+}
+
+// Owns returns true if the VPA is handled by this shard.
+func (s *LeaseSharder) Owns(namespace, name string) bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return Owner(s.members, namespace+"/"+name) == s.identity
+}
+
+func (s *LeaseSharder) renew(ctx context.Context) error {
+	now := metav1.NewMicroTime(s.clock.Now())
+	lease, err := s.client.Get(ctx, s.leaseName(s.identity), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = s.client.Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   s.leaseName(s.identity),
+				Labels: map[string]string{ShardGroupLabel: s.group},
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(s.identity),
+				LeaseDurationSeconds: ptr.To(int32(s.leaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+	lease.Spec.HolderIdentity = ptr.To(s.identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(s.leaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+	_, err = s.client.Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+func (s *LeaseSharder) leaseName(identity string) string {
+	return "vpa-recommender-" + s.group + "-" + identity
+}
+
+// renewInterval is how often the Lease of the shard is renewed. A shard is
+// considered gone after missing a few renewals.
+func (s *LeaseSharder) renewInterval() time.Duration {
+	return s.leaseDuration / 3
+}
+
+func leaseExpiry(lease *coordinationv1.Lease) time.Time {
+	renewTime := lease.CreationTimestamp.Time
+	if lease.Spec.RenewTime != nil {
+		renewTime = lease.Spec.RenewTime.Time
+	}
+	var duration time.Duration
+	if lease.Spec.LeaseDurationSeconds != nil {
+		duration = time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	}
+	return renewTime.Add(duration)
+}
+
+// Owner returns the member owning the key, using rendezvous hashing: the
+// owner is the member with the highest hash of the member and the key. When a
+// member joins or leaves, only the keys it takes over or owned change owners.
+func Owner(members []string, key string) string {
+	var owner string
+	var ownerScore uint64
+	for _, member := range members {
+		score := rendezvousScore(member, key)
+		if owner == "" || score > ownerScore || score == ownerScore && member < owner {
+			owner = member
+			ownerScore = score
+		}
+	}
+	return owner
+}
+
+func rendezvousScore(member, key string) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(member))
+	_, _ = hash.Write([]byte{0})
+	_, _ = hash.Write([]byte(key))
+	return mix(hash.Sum64())
+}
+
+// mix is the finalizer of MurmurHash3. FNV alone spreads keys that differ only
+// in their last characters, like names of VPAs, poorly.
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	testNamespace     = "kube-system"
+	testGroup         = "default"
+	testLeaseDuration = 15 * time.Second
+)
+
+func newShardLease(identity, group string, renewTime time.Time) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vpa-recommender-" + group + "-" + identity,
+			Namespace: testNamespace,
+			Labels:    map[string]string{ShardGroupLabel: group},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(identity),
+			LeaseDurationSeconds: ptr.To(int32(testLeaseDuration.Seconds())),
+			RenewTime:            &metav1.MicroTime{Time: renewTime},
+		},
+	}
+}
+
+func TestOwner(t *testing.T) {
+	members := []string{"shard-a", "shard-b", "shard-c"}
+	owned := map[string]int{}
+	owners := map[string]string{}
+	for i := range 3000 {
+		key := fmt.Sprintf("namespace/vpa-%d", i)
+		owner := Owner(members, key)
+		assert.Equal(t, owner, Owner([]string{"shard-c", "shard-a", "shard-b"}, key), "the owner doesn't depend on the order of members")
+		owners[key] = owner
+		owned[owner]++
+	}
+	for _, member := range members {
+		assert.InDelta(t, 1000, owned[member], 150, "keys are spread evenly, member %s", member)
+	}
+
+	// Only keys taken over by a new member change owners.
+	withNewMember := []string{"shard-a", "shard-b", "shard-c", "shard-d"}
+	moved := 0
+	for key, owner := range owners {
+		newOwner := Owner(withNewMember, key)
+		if newOwner != owner {
+			assert.Equal(t, "shard-d", newOwner)
+			moved++
+		}
+	}
+	assert.InDelta(t, 750, moved, 150)
+
+	assert.Empty(t, Owner(nil, "namespace/vpa"))
+}
+
+func TestSync(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	client := fake.NewClientset(
+		newShardLease("shard-b", testGroup, now.Add(-time.Second)),
+		newShardLease("shard-c", testGroup, now.Add(-time.Minute)),
+		newShardLease("shard-d", testGroup, now.Add(-time.Hour)),
+		newShardLease("shard-e", "other", now),
+	)
+	sharder := NewLeaseSharder(client, testNamespace, testGroup, "shard-a", testLeaseDuration)
+	sharder.clock = clocktesting.NewFakeClock(now)
+
+	assert.NoError(t, sharder.Sync(context.Background()))
+
+	// shard-c expired, shard-e is in another group.
+	assert.Equal(t, []string{"shard-a", "shard-b"}, sharder.Members())
+	lease, err := client.CoordinationV1().Leases(testNamespace).Get(context.Background(), "vpa-recommender-default-shard-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, testGroup, lease.Labels[ShardGroupLabel])
+	assert.Equal(t, now, lease.Spec.RenewTime.Time)
+	// The Lease of shard-c is kept for a while, the one of shard-d is deleted.
+	_, err = client.CoordinationV1().Leases(testNamespace).Get(context.Background(), "vpa-recommender-default-shard-c", metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = client.CoordinationV1().Leases(testNamespace).Get(context.Background(), "vpa-recommender-default-shard-d", metav1.GetOptions{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	for i := range 100 {
+		name := fmt.Sprintf("vpa-%d", i)
+		assert.Equal(t, Owner([]string{"shard-a", "shard-b"}, "namespace/"+name) == "shard-a", sharder.Owns("namespace", name))
+	}
+
+	// The Lease of the shard is renewed.
+	later := now.Add(5 * time.Second)
+	sharder.clock = clocktesting.NewFakeClock(later)
+	assert.NoError(t, sharder.Sync(context.Background()))
+	lease, err = client.CoordinationV1().Leases(testNamespace).Get(context.Background(), "vpa-recommender-default-shard-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, later, lease.Spec.RenewTime.Time)
+}
+
+func TestOwnsWithoutOtherShards(t *testing.T) {
+	sharder := NewLeaseSharder(fake.NewClientset(), testNamespace, testGroup, "shard-a", testLeaseDuration)
+	assert.True(t, sharder.Owns("namespace", "vpa"))
+}
@@ -0,0 +1,590 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package canary implements the canary stage of pod updates. The updater
+// applies a recommendation to a few canary pods first, watches them for a soak
+// period and only then updates the remaining pods of the VPA. Canaries that
+// regress are rolled back to their previous resources. The state of the canary
+// stage is kept in an annotation of the VPA, so it survives updater restarts.
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	kube_client "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+
+	resource_updates "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource"
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_api "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/typed/autoscaling.k8s.io/v1"
+	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
+)
+
+const (
+	// DefaultSoakPeriod is the soak period of canary pods used when the VPA
+	// doesn't specify one.
+	DefaultSoakPeriod = 10 * time.Minute
+
+	// ReasonCanaryRegressed is the reason of the CanaryRolledBack condition
+	// and event when canary pods were rolled back.
+	ReasonCanaryRegressed = "CanaryRegressed"
+	// ReasonCanaryPromoted is the reason of the CanaryRolledBack condition
+	// when a later rollout passed its canary stage.
+	ReasonCanaryPromoted = "CanaryPromoted"
+
+	// RolloutAnnotation is the VPA annotation holding the state of the canary
+	// stage.
+	RolloutAnnotation = "vpa-updater.autoscaling.k8s.io/canary-rollout"
+
+	oomKilledReason = "OOMKilled"
+)
+
+// RolloutController gates the pod updates of VPAs with a canary rollout.
+type RolloutController interface {
+	// FilterPods returns the pods of the VPA that can be updated in place and
+	// evicted in this loop. It also checks the health of the canary pods and
+	// rolls them back if they regressed.
+	FilterPods(vpa *vpa_types.VerticalPodAutoscaler, livePods, podsForInPlace, podsForEviction []*corev1.Pod) ([]*corev1.Pod, []*corev1.Pod)
+	// RecordUpdate records that the pod was updated in place or evicted.
+	RecordUpdate(vpa *vpa_types.VerticalPodAutoscaler, pod *corev1.Pod, evicted bool)
+	// CleanUp drops the state of rollouts of VPAs not in the given set.
+	CleanUp(liveVPAs set.Set[k8stypes.UID])
+}
+
+// rollout is the state of the rollout of a recommendation to the pods of a
+// single VPA, stored in the RolloutAnnotation of the VPA.
+type rollout struct {
+	// Canaries maps the pods updated in place in the canary stage to their
+	// state at the time of the update.
+	Canaries map[k8stypes.UID]*canary `json:"canaries,omitempty"`
+	// Evicted maps the names of the pods evicted in the canary stage to the
+	// resources of their containers before the eviction.
+	Evicted map[string]map[string]corev1.ResourceRequirements `json:"evicted,omitempty"`
+	// FirstEviction is the time of the first eviction in the canary stage.
+	// Pods created since then got the new recommendation from the admission
+	// controller and are watched as canaries too.
+	FirstEviction metav1.Time `json:"firstEviction,omitempty"`
+	// LastUpdate is the time of the last update in the canary stage. The soak
+	// period starts then.
+	LastUpdate metav1.Time `json:"lastUpdate,omitempty"`
+	// Promoted is set when the canary stage passed, and the remaining pods
+	// can be updated.
+	Promoted bool `json:"promoted,omitempty"`
+	// Recommendation is the recommendation of the VPA when the canary stage
+	// passed. A different recommendation goes through a canary stage again.
+	Recommendation *vpa_types.RecommendedPodResources `json:"recommendation,omitempty"`
+	// StableRecommendation is the recommendation promoted by the previous
+	// rollout, if any. Pods other than the canaries are admitted with it after
+	// a rollback.
+	StableRecommendation *vpa_types.RecommendedPodResources `json:"stableRecommendation,omitempty"`
+	// RolledBackAt is the time the canaries were rolled back. Canaries whose
+	// rollback failed are retried until the rollback backoff passes.
+	RolledBackAt metav1.Time `json:"rolledBackAt,omitempty"`
+}
+
+// canary is a pod updated in place in the canary stage.
+type canary struct {
+	Name string `json:"name"`
+	// RestartCounts are the restart counts of the containers at the time of
+	// the update.
+	RestartCounts map[string]int32 `json:"restartCounts,omitempty"`
+	// PreviousResources are the resources of the containers before the update.
+	PreviousResources map[string]corev1.ResourceRequirements `json:"previousResources,omitempty"`
+}
+
+func newRollout() *rollout {
+	return &rollout{
+		Canaries: make(map[k8stypes.UID]*canary),
+		Evicted:  make(map[string]map[string]corev1.ResourceRequirements),
+	}
+}
+
+func (r *rollout) updatedPods() int {
+	return len(r.Canaries) + len(r.Evicted)
+}
+
+func (r *rollout) rolledBack() bool {
+	return !r.RolledBackAt.IsZero()
+}
+
+// rollback returns the resources of the canary pods before the update and the
+// last stable recommendation, which the admission controller applies to pods
+// of the VPA after the rollback.
+func (r *rollout) rollback() *vpa_api_util.CanaryRollback {
+	result := &vpa_api_util.CanaryRollback{
+		Pods:           make(map[string]map[string]corev1.ResourceRequirements, r.updatedPods()),
+		Recommendation: r.StableRecommendation,
+	}
+	for _, c := range r.Canaries {
+		result.Pods[c.Name] = c.PreviousResources
+	}
+	for name, resources := range r.Evicted {
+		result.Pods[name] = resources
+	}
+	return result
+}
+
+type rolloutController struct {
+	client          kube_client.Interface
+	vpaClient       vpa_api.VerticalPodAutoscalersGetter
+	eventRecorder   record.EventRecorder
+	clock           clock.Clock
+	rollbackBackoff time.Duration
+	// rollouts caches the rollouts stored in the VPA annotations, which may
+	// not be visible in the lister yet.
+	rollouts map[k8stypes.UID]*rollout
+}
+
+// NewRolloutController returns a RolloutController. After a rollback, no pods
+// of the VPA are updated for rollbackBackoff.
+func NewRolloutController(client kube_client.Interface, vpaClient vpa_api.VerticalPodAutoscalersGetter, eventRecorder record.EventRecorder, rollbackBackoff time.Duration) RolloutController {
+	return &rolloutController{
+		client:          client,
+		vpaClient:       vpaClient,
+		eventRecorder:   eventRecorder,
+		clock:           clock.RealClock{},
+		rollbackBackoff: rollbackBackoff,
+		rollouts:        make(map[k8stypes.UID]*rollout),
+	}
+}
+
+// FilterPods implements RolloutController.
+func (c *rolloutController) FilterPods(vpa *vpa_types.VerticalPodAutoscaler, livePods, podsForInPlace, podsForEviction []*corev1.Pod) ([]*corev1.Pod, []*corev1.Pod) {
+	canaryRollout := vpa_api_util.GetCanaryRollout(vpa)
+	if canaryRollout == nil {
+		c.dropRollout(vpa)
+		return podsForInPlace, podsForEviction
+	}
+	now := c.clock.Now()
+	r := c.getRollout(vpa)
+	if until, rolledBack := c.rolledBackUntil(vpa, r); rolledBack && now.Before(until) {
+		if r != nil && r.rolledBack() {
+			c.rollBackCanaries(vpa, r, c.canaryPods(r, livePods))
+		}
+		klog.V(4).InfoS("Not updating pods of VPA after canary rollback", "vpa", klog.KObj(vpa), "until", until)
+		return nil, nil
+	}
+	var stable *vpa_types.RecommendedPodResources
+	if r != nil && r.rolledBack() {
+		// The rollback backoff passed, new pods get the current
+		// recommendation again, which goes through a new canary stage.
+		stable = r.StableRecommendation
+		c.dropRollout(vpa)
+		r = nil
+	}
+
+	if r != nil && r.Promoted {
+		if apiequality.Semantic.DeepEqual(r.Recommendation, vpa.Status.Recommendation) || len(podsForInPlace)+len(podsForEviction) == 0 {
+			return podsForInPlace, podsForEviction
+		}
+		// The recommendation changed since the canary stage passed, the
+		// new one goes through a canary stage again.
+		klog.V(4).InfoS("Recommendation changed since the canary stage passed, starting a new one", "vpa", klog.KObj(vpa))
+		stable = r.Recommendation
+		r = nil
+	}
+	if r == nil {
+		if len(podsForInPlace)+len(podsForEviction) == 0 {
+			return nil, nil
+		}
+		r = newRollout()
+		r.StableRecommendation = stable
+		c.rollouts[vpa.UID] = r
+	}
+
+	canaryPods := c.canaryPods(r, livePods)
+	if regression := findRegression(r, canaryPods); regression != "" {
+		c.rollBack(vpa, r, canaryPods, regression)
+		return nil, nil
+	}
+
+	remaining := int(canaryRollout.Pods) - r.updatedPods()
+	if remaining > 0 {
+		inPlace, evict := c.selectCanaries(r, remaining, podsForInPlace, podsForEviction)
+		if len(inPlace)+len(evict) > 0 {
+			return inPlace, evict
+		}
+	}
+	if r.updatedPods() == 0 {
+		// Nothing to update and no canaries, the rollout didn't start.
+		c.dropRollout(vpa)
+		return nil, nil
+	}
+
+	if now.Before(r.LastUpdate.Add(soakPeriod(canaryRollout))) {
+		return nil, nil
+	}
+	if len(canaryPods) == 0 {
+		klog.V(4).InfoS("Waiting for canary pods to be recreated", "vpa", klog.KObj(vpa))
+		return nil, nil
+	}
+	for _, pod := range canaryPods {
+		if !isPodReady(pod) {
+			c.rollBack(vpa, r, canaryPods, fmt.Sprintf("pod %s is not ready at the end of the soak period", pod.Name))
+			return nil, nil
+		}
+	}
+
+	klog.V(2).InfoS("Canary pods passed the soak period, updating the remaining pods", "vpa", klog.KObj(vpa), "canaries", len(canaryPods))
+	r.Promoted = true
+	r.Recommendation = vpa.Status.Recommendation.DeepCopy()
+	c.saveRollout(vpa, r)
+	c.setCondition(vpa, corev1.ConditionFalse, ReasonCanaryPromoted, "Canary pods of the last rollout passed the soak period.")
+	return podsForInPlace, podsForEviction
+}
+
+// selectCanaries returns up to count pods that aren't canaries yet, in the
+// order of the update priority.
+func (c *rolloutController) selectCanaries(r *rollout, count int, podsForInPlace, podsForEviction []*corev1.Pod) ([]*corev1.Pod, []*corev1.Pod) {
+	inPlace := make([]*corev1.Pod, 0, count)
+	for _, pod := range podsForInPlace {
+		if len(inPlace) == count {
+			break
+		}
+		if _, isCanary := r.Canaries[pod.UID]; !isCanary {
+			inPlace = append(inPlace, pod)
+		}
+	}
+	evict := make([]*corev1.Pod, 0, count-len(inPlace))
+	for _, pod := range podsForEviction {
+		if len(inPlace)+len(evict) == count {
+			break
+		}
+		if _, isCanary := r.Canaries[pod.UID]; !isCanary {
+			evict = append(evict, pod)
+		}
+	}
+	return inPlace, evict
+}
+
+// RecordUpdate implements RolloutController.
+func (c *rolloutController) RecordUpdate(vpa *vpa_types.VerticalPodAutoscaler, pod *corev1.Pod, evicted bool) {
+	r, found := c.rollouts[vpa.UID]
+	if !found || r.Promoted {
+		return
+	}
+	now := metav1.NewTime(c.clock.Now())
+	r.LastUpdate = now
+	previousResources := containerResources(pod)
+	if existing, isCanary := r.Canaries[pod.UID]; isCanary {
+		// The pod was updated in place before, keep its resources from
+		// before that.
+		previousResources = existing.PreviousResources
+	}
+	if evicted {
+		if len(r.Evicted) == 0 {
+			r.FirstEviction = now
+		}
+		r.Evicted[pod.Name] = previousResources
+		delete(r.Canaries, pod.UID)
+	} else {
+		restartCounts := make(map[string]int32, len(pod.Status.ContainerStatuses))
+		for _, status := range pod.Status.ContainerStatuses {
+			restartCounts[status.Name] = status.RestartCount
+		}
+		r.Canaries[pod.UID] = &canary{
+			Name:              pod.Name,
+			RestartCounts:     restartCounts,
+			PreviousResources: previousResources,
+		}
+	}
+	c.saveRollout(vpa, r)
+}
+
+// CleanUp implements RolloutController.
+func (c *rolloutController) CleanUp(liveVPAs set.Set[k8stypes.UID]) {
+	for uid := range c.rollouts {
+		if !liveVPAs.Has(uid) {
+			delete(c.rollouts, uid)
+		}
+	}
+}
+
+// getRollout returns the rollout of the VPA, from the cache or from its
+// annotation after an updater restart. It returns nil if there is none.
+func (c *rolloutController) getRollout(vpa *vpa_types.VerticalPodAutoscaler) *rollout {
+	if r, found := c.rollouts[vpa.UID]; found {
+		return r
+	}
+	value, found := vpa.Annotations[RolloutAnnotation]
+	if !found {
+		return nil
+	}
+	r := newRollout()
+	if err := json.Unmarshal([]byte(value), r); err != nil {
+		klog.ErrorS(err, "Ignoring invalid canary rollout state of VPA", "vpa", klog.KObj(vpa))
+		return nil
+	}
+	if r.Canaries == nil {
+		r.Canaries = make(map[k8stypes.UID]*canary)
+	}
+	if r.Evicted == nil {
+		r.Evicted = make(map[string]map[string]corev1.ResourceRequirements)
+	}
+	c.rollouts[vpa.UID] = r
+	return r
+}
+
+// saveRollout stores the rollout in the annotation of the VPA.
+func (c *rolloutController) saveRollout(vpa *vpa_types.VerticalPodAutoscaler, r *rollout) {
+	value, err := json.Marshal(r)
+	if err != nil {
+		klog.ErrorS(err, "Failed to encode canary rollout state", "vpa", klog.KObj(vpa))
+		return
+	}
+	c.patchAnnotations(vpa, map[string]*string{RolloutAnnotation: ptr.To(string(value))})
+}
+
+// dropRollout removes the rollout and the rollback of the VPA.
+func (c *rolloutController) dropRollout(vpa *vpa_types.VerticalPodAutoscaler) {
+	_, cached := c.rollouts[vpa.UID]
+	delete(c.rollouts, vpa.UID)
+	_, hasRollout := vpa.Annotations[RolloutAnnotation]
+	_, hasRollback := vpa.Annotations[vpa_api_util.CanaryRollbackAnnotation]
+	if cached || hasRollout || hasRollback {
+		c.patchAnnotations(vpa, map[string]*string{RolloutAnnotation: nil, vpa_api_util.CanaryRollbackAnnotation: nil})
+	}
+}
+
+// patchAnnotations sets the annotations of the VPA, removing those with nil
+// values.
+func (c *rolloutController) patchAnnotations(vpa *vpa_types.VerticalPodAutoscaler, annotations map[string]*string) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": annotations},
+	})
+	if err == nil {
+		_, err = c.vpaClient.VerticalPodAutoscalers(vpa.Namespace).Patch(context.TODO(), vpa.Name, k8stypes.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		klog.ErrorS(err, "Failed to store canary rollout state of VPA", "vpa", klog.KObj(vpa))
+	}
+}
+
+// canaryPods returns the live pods updated in place in the canary stage and
+// the pods created since the first canary eviction.
+func (c *rolloutController) canaryPods(r *rollout, livePods []*corev1.Pod) []*corev1.Pod {
+	result := make([]*corev1.Pod, 0, r.updatedPods())
+	for _, pod := range livePods {
+		if _, isCanary := r.Canaries[pod.UID]; isCanary {
+			result = append(result, pod)
+		} else if len(r.Evicted) > 0 && !pod.CreationTimestamp.Time.Before(r.FirstEviction.Truncate(time.Second)) {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+// findRegression returns a description of the first regression of the canary
+// pods, or an empty string if they are healthy.
+func findRegression(r *rollout, canaryPods []*corev1.Pod) string {
+	for _, pod := range canaryPods {
+		// Pods recreated after an eviction start with no restarts.
+		var restartCounts map[string]int32
+		if c, isCanary := r.Canaries[pod.UID]; isCanary {
+			restartCounts = c.RestartCounts
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.RestartCount <= restartCounts[status.Name] {
+				continue
+			}
+			if terminated := status.LastTerminationState.Terminated; terminated != nil && terminated.Reason == oomKilledReason {
+				return fmt.Sprintf("container %s of pod %s was OOM killed", status.Name, pod.Name)
+			}
+			return fmt.Sprintf("container %s of pod %s restarted", status.Name, pod.Name)
+		}
+	}
+	return ""
+}
+
+// rollBack stores the resources of the canary pods before the update for the
+// admission controller, rolls the canary pods back and marks the VPA with the
+// CanaryRolledBack condition.
+func (c *rolloutController) rollBack(vpa *vpa_types.VerticalPodAutoscaler, r *rollout, canaryPods []*corev1.Pod, regression string) {
+	klog.V(0).InfoS("Rolling back canary pods", "vpa", klog.KObj(vpa), "regression", regression, "canaries", len(canaryPods))
+	// Stored with the precision of pod creation timestamps.
+	r.RolledBackAt = metav1.NewTime(c.clock.Now().Truncate(time.Second))
+	rolloutValue, err := json.Marshal(r)
+	if err != nil {
+		klog.ErrorS(err, "Failed to encode canary rollout state", "vpa", klog.KObj(vpa))
+	}
+	rollbackValue, err := json.Marshal(r.rollback())
+	if err != nil {
+		klog.ErrorS(err, "Failed to encode canary rollback", "vpa", klog.KObj(vpa))
+	}
+	// Recreated pods get the previous resources from the admission controller
+	// from now on.
+	c.patchAnnotations(vpa, map[string]*string{
+		RolloutAnnotation:                     ptr.To(string(rolloutValue)),
+		vpa_api_util.CanaryRollbackAnnotation: ptr.To(string(rollbackValue)),
+	})
+	c.rollBackCanaries(vpa, r, canaryPods)
+
+	message := fmt.Sprintf("Canary pods were rolled back: %s.", regression)
+	c.eventRecorder.Event(vpa, corev1.EventTypeWarning, ReasonCanaryRegressed, message)
+	c.setCondition(vpa, corev1.ConditionTrue, ReasonCanaryRegressed, message)
+}
+
+// rollBackCanaries resizes the canary pods updated in place back to their own
+// previous resources, and evicts the canary pods recreated after an eviction,
+// so that they are admitted with the previous resources. Canaries already
+// rolled back are skipped, so this can be retried.
+func (c *rolloutController) rollBackCanaries(vpa *vpa_types.VerticalPodAutoscaler, r *rollout, canaryPods []*corev1.Pod) {
+	for _, pod := range canaryPods {
+		if canary, isCanary := r.Canaries[pod.UID]; isCanary {
+			if hasResources(pod, canary.PreviousResources) {
+				continue
+			}
+			err := c.resizeToPreviousResources(pod, canary.PreviousResources)
+			if err == nil {
+				c.eventRecorder.Event(pod, corev1.EventTypeNormal, "CanaryRolledBackByVPA", "Pod was resized back to its resources before the canary update by VPA Updater.")
+				continue
+			}
+			klog.V(0).InfoS("Failed to resize canary pod back, evicting it", "pod", klog.KObj(pod), "error", err)
+		} else if pod.CreationTimestamp.Time.After(r.RolledBackAt.Time) {
+			// Admitted with the previous resources.
+			continue
+		}
+		if err := c.evict(pod); err != nil {
+			klog.ErrorS(err, "Failed to roll back canary pod, retrying in the next loop", "pod", klog.KObj(pod), "vpa", klog.KObj(vpa))
+			continue
+		}
+		c.eventRecorder.Event(pod, corev1.EventTypeNormal, "CanaryRolledBackByVPA", "Pod was evicted by VPA Updater to recreate it with its resources before the canary update.")
+	}
+}
+
+func (c *rolloutController) evict(pod *corev1.Pod) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+		},
+	}
+	return c.client.CoreV1().Pods(pod.Namespace).EvictV1(context.TODO(), eviction)
+}
+
+func (c *rolloutController) resizeToPreviousResources(pod *corev1.Pod, previousResources map[string]corev1.ResourceRequirements) error {
+	patches := []resource_updates.PatchRecord{}
+	for i, container := range pod.Spec.Containers {
+		resources, found := previousResources[container.Name]
+		if !found {
+			continue
+		}
+		patches = append(patches, resource_updates.PatchRecord{
+			Op:    "add",
+			Path:  fmt.Sprintf("/spec/containers/%d/resources", i),
+			Value: resources,
+		})
+	}
+	if len(patches) == 0 {
+		return nil
+	}
+	patch, err := json.Marshal(patches)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.CoreV1().Pods(pod.Namespace).Patch(context.TODO(), pod.Name, k8stypes.JSONPatchType, patch, metav1.PatchOptions{}, "resize")
+	return err
+}
+
+func (c *rolloutController) setCondition(vpa *vpa_types.VerticalPodAutoscaler, status corev1.ConditionStatus, reason, message string) {
+	if status == corev1.ConditionFalse {
+		// Only clear a condition set by an earlier rollback.
+		if _, rolledBack := c.rolledBackUntil(vpa, nil); !rolledBack {
+			return
+		}
+	}
+	condition := vpa_types.VerticalPodAutoscalerCondition{
+		Type:               vpa_types.CanaryRolledBack,
+		Status:             status,
+		LastTransitionTime: metav1.NewTime(c.clock.Now()),
+		Reason:             reason,
+		Message:            message,
+	}
+	if _, err := vpa_api_util.SetVpaConditionIfNeeded(c.vpaClient.VerticalPodAutoscalers(vpa.Namespace), vpa, condition); err != nil {
+		klog.ErrorS(err, "Failed to set VPA condition", "vpa", klog.KObj(vpa), "condition", vpa_types.CanaryRolledBack)
+	}
+}
+
+// rolledBackUntil returns the time until which pods of the VPA aren't updated
+// after a rollback, and whether the VPA was rolled back, either in the rollout
+// or in a condition of the VPA.
+func (c *rolloutController) rolledBackUntil(vpa *vpa_types.VerticalPodAutoscaler, r *rollout) (time.Time, bool) {
+	var rolledBackAt time.Time
+	rolledBack := false
+	if r != nil && r.rolledBack() {
+		rolledBackAt = r.RolledBackAt.Time
+		rolledBack = true
+	}
+	for _, condition := range vpa.Status.Conditions {
+		if condition.Type == vpa_types.CanaryRolledBack && condition.Status == corev1.ConditionTrue {
+			if condition.LastTransitionTime.Time.After(rolledBackAt) {
+				rolledBackAt = condition.LastTransitionTime.Time
+			}
+			rolledBack = true
+		}
+	}
+	if !rolledBack {
+		return time.Time{}, false
+	}
+	return rolledBackAt.Add(c.rollbackBackoff), true
+}
+
+func containerResources(pod *corev1.Pod) map[string]corev1.ResourceRequirements {
+	result := make(map[string]corev1.ResourceRequirements, len(pod.Spec.Containers))
+	for _, container := range pod.Spec.Containers {
+		result[container.Name] = *container.Resources.DeepCopy()
+	}
+	return result
+}
+
+// hasResources returns whether the containers of the pod have the given
+// resources.
+func hasResources(pod *corev1.Pod, resources map[string]corev1.ResourceRequirements) bool {
+	for _, container := range pod.Spec.Containers {
+		if expected, found := resources[container.Name]; found && !apiequality.Semantic.DeepEqual(expected, container.Resources) {
+			return false
+		}
+	}
+	return true
+}
+
+func soakPeriod(canaryRollout *vpa_types.CanaryRollout) time.Duration {
+	if canaryRollout.SoakPeriodSeconds == nil {
+		return DefaultSoakPeriod
+	}
+	return time.Duration(*canaryRollout.SoakPeriodSeconds) * time.Second
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
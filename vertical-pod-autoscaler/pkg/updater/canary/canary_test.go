@@ -0,0 +1,415 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package canary
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	core "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	baseclocktest "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+	"k8s.io/utils/set"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_fake "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/fake"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/test"
+	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
+)
+
+const soakSeconds = 300
+
+var startTime = time.Date(2025, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+type testEnv struct {
+	controller    *rolloutController
+	clock         *baseclocktest.FakeClock
+	kubeClient    *fake.Clientset
+	vpaClient     *vpa_fake.Clientset
+	eventRecorder *record.FakeRecorder
+	vpa           *vpa_types.VerticalPodAutoscaler
+}
+
+func newTestEnv(pods ...*corev1.Pod) *testEnv {
+	vpa := test.VerticalPodAutoscaler().WithName("vpa").WithNamespace("default").WithContainer("app").
+		WithCanaryRollout(&vpa_types.CanaryRollout{Pods: 1, SoakPeriodSeconds: ptr.To(int32(soakSeconds))}).Get()
+	vpa.UID = "vpa-uid"
+	objects := make([]runtime.Object, 0, len(pods))
+	for _, pod := range pods {
+		objects = append(objects, pod)
+	}
+	env := &testEnv{
+		clock:         baseclocktest.NewFakeClock(startTime),
+		kubeClient:    fake.NewClientset(objects...),
+		vpaClient:     vpa_fake.NewSimpleClientset(vpa),
+		eventRecorder: record.NewFakeRecorder(10),
+		vpa:           vpa,
+	}
+	env.controller = NewRolloutController(env.kubeClient, env.vpaClient.AutoscalingV1(), env.eventRecorder, time.Hour).(*rolloutController)
+	env.controller.clock = env.clock
+	return env
+}
+
+// refreshVpa returns the VPA as stored by the API server, like the lister
+// would in the next loop.
+func (e *testEnv) refreshVpa(t *testing.T) *vpa_types.VerticalPodAutoscaler {
+	vpa, err := e.vpaClient.AutoscalingV1().VerticalPodAutoscalers("default").Get(context.TODO(), "vpa", metav1.GetOptions{})
+	require.NoError(t, err)
+	return vpa
+}
+
+func canaryCondition(vpa *vpa_types.VerticalPodAutoscaler) *vpa_types.VerticalPodAutoscalerCondition {
+	for i := range vpa.Status.Conditions {
+		if vpa.Status.Conditions[i].Type == vpa_types.CanaryRolledBack {
+			return &vpa.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+func resizePatches(client *fake.Clientset) []string {
+	var names []string
+	for _, action := range client.Actions() {
+		if patch, ok := action.(core.PatchAction); ok && action.GetSubresource() == "resize" {
+			names = append(names, patch.GetName())
+		}
+	}
+	return names
+}
+
+func evictions(client *fake.Clientset) []string {
+	var names []string
+	for _, action := range client.Actions() {
+		if create, ok := action.(core.CreateAction); ok && action.GetSubresource() == "eviction" {
+			names = append(names, create.GetObject().(*policyv1.Eviction).Name)
+		}
+	}
+	return names
+}
+
+func rollbackAnnotation(t *testing.T, vpa *vpa_types.VerticalPodAutoscaler) *vpa_api_util.CanaryRollback {
+	rollback, err := vpa_api_util.GetCanaryRollback(vpa)
+	require.NoError(t, err)
+	return rollback
+}
+
+func cpuResources(cpu string) map[string]corev1.ResourceRequirements {
+	return map[string]corev1.ResourceRequirements{
+		"app": {Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+	}
+}
+
+func testPod(name string, created time.Time, restarts int32, ready bool) *corev1.Pod {
+	return testPodWithCPU(name, "100m", created, restarts, ready)
+}
+
+func testPodWithCPU(name, cpu string, created time.Time, restarts int32, ready bool) *corev1.Pod {
+	container := test.Container().WithName("app").WithCPURequest(resource.MustParse(cpu)).Get()
+	readyStatus := corev1.ConditionFalse
+	if ready {
+		readyStatus = corev1.ConditionTrue
+	}
+	pod := test.Pod().WithName(name).WithUID(k8stypes.UID(name)).AddContainer(container).
+		AddContainerStatus(corev1.ContainerStatus{Name: "app", RestartCount: restarts, Ready: ready}).
+		WithPodConditions([]corev1.PodCondition{{Type: corev1.PodReady, Status: readyStatus}}).Get()
+	pod.Namespace = "default"
+	pod.CreationTimestamp = metav1.NewTime(created)
+	return pod
+}
+
+func TestFilterPodsWithoutCanaryRollout(t *testing.T) {
+	env := newTestEnv()
+	vpa := test.VerticalPodAutoscaler().WithName("vpa").WithContainer("app").Get()
+	pods := []*corev1.Pod{testPod("a", startTime, 0, true), testPod("b", startTime, 0, true)}
+
+	inPlace, evict := env.controller.FilterPods(vpa, pods, pods[:1], pods[1:])
+
+	assert.Equal(t, pods[:1], inPlace)
+	assert.Equal(t, pods[1:], evict)
+}
+
+func TestFilterPodsPromotesHealthyCanary(t *testing.T) {
+	created := startTime.Add(-time.Hour)
+	pods := []*corev1.Pod{testPod("a", created, 0, true), testPod("b", created, 0, true), testPod("c", created, 0, true)}
+	env := newTestEnv(pods...)
+
+	inPlace, evict := env.controller.FilterPods(env.vpa, pods, nil, pods)
+	assert.Empty(t, inPlace)
+	require.Equal(t, []*corev1.Pod{pods[0]}, evict)
+	env.controller.RecordUpdate(env.vpa, pods[0], true)
+
+	// The replacement of the evicted pod is soaking.
+	env.clock.Step(time.Minute)
+	replacement := testPod("a2", env.clock.Now(), 0, true)
+	live := []*corev1.Pod{replacement, pods[1], pods[2]}
+	inPlace, evict = env.controller.FilterPods(env.vpa, live, nil, pods[1:])
+	assert.Empty(t, inPlace)
+	assert.Empty(t, evict)
+
+	env.clock.Step(soakSeconds * time.Second)
+	inPlace, evict = env.controller.FilterPods(env.vpa, live, nil, pods[1:])
+	assert.Empty(t, inPlace)
+	assert.Equal(t, pods[1:], evict)
+	assert.Nil(t, canaryCondition(env.refreshVpa(t)))
+
+	// Pods needing an update for the promoted recommendation, e.g. after a
+	// failed eviction, are updated without a canary stage.
+	inPlace, evict = env.controller.FilterPods(env.vpa, live, nil, pods[2:])
+	assert.Empty(t, inPlace)
+	assert.Equal(t, pods[2:], evict)
+}
+
+func TestFilterPodsRollsBackRestartedCanary(t *testing.T) {
+	created := startTime.Add(-time.Hour)
+	pods := []*corev1.Pod{testPod("a", created, 2, true), testPod("b", created, 0, true)}
+	env := newTestEnv(pods...)
+
+	inPlace, evict := env.controller.FilterPods(env.vpa, pods, pods, nil)
+	require.Equal(t, []*corev1.Pod{pods[0]}, inPlace)
+	assert.Empty(t, evict)
+	env.controller.RecordUpdate(env.vpa, pods[0], false)
+
+	env.clock.Step(time.Minute)
+	oomKilled := testPodWithCPU("a", "250m", created, 3, true)
+	oomKilled.Status.ContainerStatuses[0].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{Reason: "OOMKilled"}
+	inPlace, evict = env.controller.FilterPods(env.vpa, []*corev1.Pod{oomKilled, pods[1]}, []*corev1.Pod{pods[1]}, nil)
+	assert.Empty(t, inPlace)
+	assert.Empty(t, evict)
+
+	assert.Equal(t, []string{"a"}, resizePatches(env.kubeClient))
+	assert.Empty(t, evictions(env.kubeClient))
+	vpa := env.refreshVpa(t)
+	previous, isCanary := rollbackAnnotation(t, vpa).ResourcesForPod("a")
+	assert.True(t, isCanary)
+	assert.Equal(t, cpuResources("100m"), previous)
+	condition := canaryCondition(vpa)
+	require.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonCanaryRegressed, condition.Reason)
+	assert.Contains(t, condition.Message, "container app of pod a was OOM killed")
+	require.Len(t, env.eventRecorder.Events, 2)
+	<-env.eventRecorder.Events
+	assert.Contains(t, <-env.eventRecorder.Events, "Warning CanaryRegressed")
+
+	// Pods aren't updated during the rollback backoff.
+	env.clock.Step(30 * time.Minute)
+	inPlace, evict = env.controller.FilterPods(vpa, pods, pods, nil)
+	assert.Empty(t, inPlace)
+	assert.Empty(t, evict)
+
+	env.clock.Step(time.Hour)
+	inPlace, _ = env.controller.FilterPods(vpa, pods, pods, nil)
+	assert.Len(t, inPlace, 1)
+	// New pods get the current recommendation again.
+	assert.Nil(t, rollbackAnnotation(t, env.refreshVpa(t)))
+}
+
+func TestFilterPodsRollsBackUnreadyCanary(t *testing.T) {
+	created := startTime.Add(-time.Hour)
+	pods := []*corev1.Pod{testPod("a", created, 0, true), testPod("b", created, 0, true)}
+	env := newTestEnv(pods...)
+	failEvictions := true
+	env.kubeClient.PrependReactor("create", "pods", func(action core.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "eviction" || !failEvictions {
+			return false, nil, nil
+		}
+		return true, nil, fmt.Errorf("cannot evict pod, it would violate the pod's disruption budget")
+	})
+
+	_, evict := env.controller.FilterPods(env.vpa, pods, nil, pods)
+	require.Len(t, evict, 1)
+	env.controller.RecordUpdate(env.vpa, pods[0], true)
+
+	env.clock.Step(soakSeconds * time.Second)
+	replacement := testPodWithCPU("a2", "250m", env.clock.Now(), 0, false)
+	env.kubeClient.Tracker().Add(replacement)
+	live := []*corev1.Pod{replacement, pods[1]}
+	inPlace, evict := env.controller.FilterPods(env.vpa, live, nil, pods[1:])
+	assert.Empty(t, inPlace)
+	assert.Empty(t, evict)
+
+	// The recreated canary is evicted rather than resized, and its
+	// replacement is admitted with the resources of the evicted pod.
+	assert.Empty(t, resizePatches(env.kubeClient))
+	assert.Equal(t, []string{"a2"}, evictions(env.kubeClient))
+	vpa := env.refreshVpa(t)
+	condition := canaryCondition(vpa)
+	require.NotNil(t, condition)
+	assert.Contains(t, condition.Message, "pod a2 is not ready at the end of the soak period")
+	rollback := rollbackAnnotation(t, vpa)
+	require.NotNil(t, rollback)
+	previous, isCanary := rollback.ResourcesForPod("a")
+	assert.True(t, isCanary)
+	assert.Equal(t, cpuResources("100m"), previous)
+	// Without a stable recommendation, other pods keep their resources.
+	_, isCanary = rollback.ResourcesForPod("a3")
+	assert.False(t, isCanary)
+	assert.Nil(t, rollback.Recommendation)
+
+	// The failed eviction is retried in the next loop.
+	failEvictions = false
+	env.clock.Step(time.Minute)
+	env.controller.FilterPods(vpa, live, nil, pods[1:])
+	assert.Equal(t, []string{"a2", "a2"}, evictions(env.kubeClient))
+
+	// The replacement created after the rollback isn't evicted again.
+	env.clock.Step(time.Minute)
+	live = []*corev1.Pod{testPod("a3", env.clock.Now(), 0, true), pods[1]}
+	env.controller.FilterPods(vpa, live, nil, pods[1:])
+	assert.Len(t, evictions(env.kubeClient), 2)
+}
+
+func TestFilterPodsRollsBackEachCanaryToItsOwnResources(t *testing.T) {
+	created := startTime.Add(-time.Hour)
+	pods := []*corev1.Pod{testPodWithCPU("a", "100m", created, 0, true), testPodWithCPU("b", "200m", created, 0, true), testPod("c", created, 0, true)}
+	env := newTestEnv(pods...)
+	env.vpa.Spec.UpdatePolicy.CanaryRollout.Pods = 2
+
+	inPlace, _ := env.controller.FilterPods(env.vpa, pods, pods, nil)
+	require.Equal(t, pods[:2], inPlace)
+	env.controller.RecordUpdate(env.vpa, pods[0], false)
+	env.controller.RecordUpdate(env.vpa, pods[1], false)
+
+	env.clock.Step(time.Minute)
+	live := []*corev1.Pod{testPodWithCPU("a", "250m", created, 1, true), testPodWithCPU("b", "250m", created, 0, true), pods[2]}
+	env.controller.FilterPods(env.vpa, live, pods[2:], nil)
+
+	previous := map[string]string{}
+	for _, action := range env.kubeClient.Actions() {
+		if patch, ok := action.(core.PatchAction); ok && action.GetSubresource() == "resize" {
+			previous[patch.GetName()] = string(patch.GetPatch())
+		}
+	}
+	require.Len(t, previous, 2)
+	assert.Contains(t, previous["a"], `"cpu":"100m"`)
+	assert.Contains(t, previous["b"], `"cpu":"200m"`)
+}
+
+func TestFilterPodsResumesAfterRestart(t *testing.T) {
+	created := startTime.Add(-time.Hour)
+	pods := []*corev1.Pod{testPod("a", created, 0, true), testPod("b", created, 0, true)}
+	env := newTestEnv(pods...)
+
+	inPlace, _ := env.controller.FilterPods(env.vpa, pods, pods, nil)
+	require.Len(t, inPlace, 1)
+	env.controller.RecordUpdate(env.vpa, pods[0], false)
+
+	// A new updater picks up the canary stage from the VPA annotation.
+	restarted := NewRolloutController(env.kubeClient, env.vpaClient.AutoscalingV1(), env.eventRecorder, time.Hour).(*rolloutController)
+	restarted.clock = env.clock
+	vpa := env.refreshVpa(t)
+	require.Contains(t, vpa.Annotations, RolloutAnnotation)
+
+	env.clock.Step(time.Minute)
+	inPlace, evict := restarted.FilterPods(vpa, pods, pods[1:], nil)
+	assert.Empty(t, inPlace, "no new canaries during the soak period")
+	assert.Empty(t, evict)
+
+	env.clock.Step(soakSeconds * time.Second)
+	inPlace, _ = restarted.FilterPods(vpa, pods, pods[1:], nil)
+	assert.Equal(t, pods[1:], inPlace)
+
+	// The promoted recommendation is kept in the annotation.
+	restarted.FilterPods(vpa, pods, nil, nil)
+	restarted = NewRolloutController(env.kubeClient, env.vpaClient.AutoscalingV1(), env.eventRecorder, time.Hour).(*rolloutController)
+	r := restarted.getRollout(env.refreshVpa(t))
+	require.NotNil(t, r)
+	assert.True(t, r.Promoted)
+}
+
+func TestFilterPodsRestartsCanaryStageOnNewRecommendation(t *testing.T) {
+	created := startTime.Add(-time.Hour)
+	pods := []*corev1.Pod{testPod("a", created, 0, true), testPod("b", created, 0, true), testPod("c", created, 0, true)}
+	env := newTestEnv(pods...)
+	promoted := test.Recommendation().WithContainer("app").WithTargetResource(corev1.ResourceCPU, "200m").Get()
+	env.vpa.Status.Recommendation = promoted
+
+	inPlace, _ := env.controller.FilterPods(env.vpa, pods, pods, nil)
+	require.Equal(t, pods[:1], inPlace)
+	env.controller.RecordUpdate(env.vpa, pods[0], false)
+	env.clock.Step(soakSeconds * time.Second)
+	inPlace, _ = env.controller.FilterPods(env.vpa, pods, pods[1:], nil)
+	require.Equal(t, pods[1:], inPlace)
+	env.controller.RecordUpdate(env.vpa, pods[1], false)
+
+	// A new recommendation while the remaining pods are updated starts a new
+	// canary stage.
+	env.vpa.Status.Recommendation = test.Recommendation().WithContainer("app").WithTargetResource(corev1.ResourceCPU, "300m").Get()
+	inPlace, _ = env.controller.FilterPods(env.vpa, pods, pods, nil)
+	require.Equal(t, pods[:1], inPlace)
+	env.controller.RecordUpdate(env.vpa, pods[0], false)
+	inPlace, _ = env.controller.FilterPods(env.vpa, pods, pods[1:], nil)
+	assert.Empty(t, inPlace, "no new canaries during the soak period")
+
+	// On a regression, other pods are admitted with the promoted
+	// recommendation.
+	env.clock.Step(time.Minute)
+	restarted := testPodWithCPU("a", "300m", created, 1, true)
+	env.controller.FilterPods(env.vpa, []*corev1.Pod{restarted, pods[1], pods[2]}, pods[1:], nil)
+	rollback := rollbackAnnotation(t, env.refreshVpa(t))
+	require.NotNil(t, rollback)
+	assert.Equal(t, promoted, rollback.Recommendation)
+	_, isCanary := rollback.ResourcesForPod("b")
+	assert.False(t, isCanary)
+}
+
+func TestFilterPodsClearsRolledBackCondition(t *testing.T) {
+	created := startTime.Add(-time.Hour)
+	pods := []*corev1.Pod{testPod("a", created, 0, true), testPod("b", created, 0, true)}
+	env := newTestEnv(pods...)
+	env.vpa.Status.Conditions = []vpa_types.VerticalPodAutoscalerCondition{{
+		Type:               vpa_types.CanaryRolledBack,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(startTime.Add(-2 * time.Hour)),
+		Reason:             ReasonCanaryRegressed,
+	}}
+
+	inPlace, _ := env.controller.FilterPods(env.vpa, pods, pods, nil)
+	require.Len(t, inPlace, 1)
+	env.controller.RecordUpdate(env.vpa, pods[0], false)
+	env.clock.Step(soakSeconds * time.Second)
+	inPlace, _ = env.controller.FilterPods(env.vpa, pods, pods[1:], nil)
+	assert.Equal(t, pods[1:], inPlace)
+
+	condition := canaryCondition(env.refreshVpa(t))
+	require.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonCanaryPromoted, condition.Reason)
+}
+
+func TestCleanUp(t *testing.T) {
+	env := newTestEnv()
+	env.controller.rollouts["live"] = &rollout{}
+	env.controller.rollouts["deleted"] = &rollout{}
+
+	env.controller.CleanUp(set.New[k8stypes.UID]("live"))
+
+	assert.Contains(t, env.controller.rollouts, k8stypes.UID("live"))
+	assert.NotContains(t, env.controller.rollouts, k8stypes.UID("deleted"))
+}
@@ -47,6 +47,9 @@ type UpdaterConfig struct {
 	DefaultUpdateThreshold     float64
 	PodLifetimeUpdateThreshold time.Duration
 	EvictAfterOOMThreshold     time.Duration
+	CanaryRollbackBackoff      time.Duration
+
+	SchedulabilityCheckAssumeScaleUp bool
 }
 
 // DefaultUpdaterConfig returns a UpdaterConfig with default values
@@ -66,6 +69,9 @@ func DefaultUpdaterConfig() *UpdaterConfig {
 		DefaultUpdateThreshold:     0.1,
 		PodLifetimeUpdateThreshold: time.Hour * 12,
 		EvictAfterOOMThreshold:     10 * time.Minute,
+		CanaryRollbackBackoff:      24 * time.Hour,
+
+		SchedulabilityCheckAssumeScaleUp: true,
 	}
 }
 
@@ -86,6 +92,8 @@ func InitUpdaterFlags() *UpdaterConfig {
 	flag.Float64Var(&config.DefaultUpdateThreshold, "pod-update-threshold", config.DefaultUpdateThreshold, "Ignore updates that have priority lower than the value of this flag")
 	flag.DurationVar(&config.PodLifetimeUpdateThreshold, "in-recommendation-bounds-eviction-lifetime-threshold", config.PodLifetimeUpdateThreshold, "Pods that live for at least that long can be evicted even if their request is within the [MinRecommended...MaxRecommended] range")
 	flag.DurationVar(&config.EvictAfterOOMThreshold, "evict-after-oom-threshold", config.EvictAfterOOMThreshold, `The default duration to evict pods that have OOMed in less than evict-after-oom-threshold since start.`)
+	flag.DurationVar(&config.CanaryRollbackBackoff, "canary-rollback-backoff", config.CanaryRollbackBackoff, "How long updater doesn't update pods of a VPA after rolling back its canary pods. Only used when the CanaryRollout feature gate is enabled.")
+	flag.BoolVar(&config.SchedulabilityCheckAssumeScaleUp, "schedulability-check-assume-scale-up", config.SchedulabilityCheckAssumeScaleUp, "If true, pods that fit on an empty node like one of the existing nodes are considered schedulable, as Cluster Autoscaler can add such a node. Only used when the SchedulabilityCheck feature gate is enabled.")
 
 	// These need to happen last. kube_flag.InitFlags() synchronizes and parses
 	// flags from the flag package to pflag, so feature gates must be added to
@@ -26,6 +26,7 @@ import (
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/patch"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/recommendation"
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/utils"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/annotations"
 	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
 )
@@ -51,7 +52,7 @@ func (*resourcesInplaceUpdatesPatchCalculator) PatchResourceTarget() patch.Patch
 func (c *resourcesInplaceUpdatesPatchCalculator) CalculatePatches(pod *corev1.Pod, vpa *vpa_types.VerticalPodAutoscaler) ([]resource_admission.PatchRecord, error) {
 	result := []resource_admission.PatchRecord{}
 
-	expiredAnnotations := vpa_api_util.GetExpiredStartupCPUBoostAnnotations(pod, vpa)
+	expiredAnnotations := vpa_api_util.GetExpiredStartupBoostAnnotations(pod, vpa)
 
 	updateMode := vpa_api_util.GetUpdateMode(vpa)
 	var recommendedResources []vpa_api_util.ContainerResources
@@ -69,18 +70,25 @@ func (c *resourcesInplaceUpdatesPatchCalculator) CalculatePatches(pod *corev1.Po
 
 	for i, c := range pod.Spec.Containers {
 		var targetResources vpa_api_util.ContainerResources
-		annotationKey := annotations.GetStartupCPUBoostAnnotationKey(c.Name)
-		_, boosted := pod.Annotations[annotationKey]
-		expired := slices.Contains(expiredAnnotations, annotationKey)
+		boostedResources, expiredResources := getBoostedAndExpiredResources(pod, c.Name, expiredAnnotations)
 
 		switch {
-		case expired:
+		case len(expiredResources) > 0:
 			var err error
 			targetResources, err = getContainerResourcesForUnboost(pod, c, recommendedResources[i])
 			if err != nil {
 				return []resource_admission.PatchRecord{}, err
 			}
-		case boosted:
+			// Resources whose boost hasn't expired yet stay boosted. So does
+			// memory if resizing it restarts the container, only its boost
+			// annotation is removed.
+			for _, resourceName := range boostedResources {
+				if !slices.Contains(expiredResources, resourceName) ||
+					(resourceName == corev1.ResourceMemory && utils.MemoryResizeRestartsContainer(c)) {
+					targetResources = keepCurrentResource(c, targetResources, resourceName)
+				}
+			}
+		case len(boostedResources) > 0:
 			continue // currently boosted pod so we skip creating patches
 		case updateMode == vpa_types.UpdateModeOff:
 			continue // Nothing to do for pods when VPA is Off.
@@ -134,6 +142,52 @@ func getContainerResourcesForUnboost(pod *corev1.Pod, c corev1.Container, recomm
 	return recommendedResources, nil
 }
 
+// getBoostedAndExpiredResources returns the resources of the container that are
+// boosted, and the ones among them whose boost has expired.
+func getBoostedAndExpiredResources(pod *corev1.Pod, containerName string, expiredAnnotations []string) (boosted, expired []corev1.ResourceName) {
+	boostAnnotationKeys := map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    annotations.GetStartupCPUBoostAnnotationKey(containerName),
+		corev1.ResourceMemory: annotations.GetStartupMemoryBoostAnnotationKey(containerName),
+	}
+	for _, resourceName := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		annotationKey := boostAnnotationKeys[resourceName]
+		if _, ok := pod.Annotations[annotationKey]; !ok {
+			continue
+		}
+		boosted = append(boosted, resourceName)
+		if slices.Contains(expiredAnnotations, annotationKey) {
+			expired = append(expired, resourceName)
+		}
+	}
+	return boosted, expired
+}
+
+// keepCurrentResource sets the request and limit of the given resource in the
+// target resources to the current values of the container.
+func keepCurrentResource(c corev1.Container, target vpa_api_util.ContainerResources, resourceName corev1.ResourceName) vpa_api_util.ContainerResources {
+	result := vpa_api_util.ContainerResources{
+		Requests: target.Requests.DeepCopy(),
+		Limits:   target.Limits.DeepCopy(),
+	}
+	if request, ok := c.Resources.Requests[resourceName]; ok {
+		if result.Requests == nil {
+			result.Requests = corev1.ResourceList{}
+		}
+		result.Requests[resourceName] = request
+	} else {
+		delete(result.Requests, resourceName)
+	}
+	if limit, ok := c.Resources.Limits[resourceName]; ok {
+		if result.Limits == nil {
+			result.Limits = corev1.ResourceList{}
+		}
+		result.Limits[resourceName] = limit
+	} else {
+		delete(result.Limits, resourceName)
+	}
+	return result
+}
+
 func areResourcesEmpty(resources vpa_api_util.ContainerResources) bool {
 	return len(resources.Requests) == 0 && len(resources.Limits) == 0
 }
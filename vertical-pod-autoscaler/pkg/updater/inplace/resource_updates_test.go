@@ -234,3 +234,111 @@ func TestCalculatePatches_MultiContainerResourceUpdates(t *testing.T) {
 		})
 	}
 }
+
+func TestCalculatePatches_PerResourceUnboost(t *testing.T) {
+	now := metav1.Now()
+	past := metav1.Time{Time: now.Add(-5 * time.Minute)}
+	ten := int32(10)
+	thousand := int32(1000)
+	original := "{\"requests\":{\"cpu\":\"100m\",\"memory\":\"100Mi\"}}"
+
+	tests := []struct {
+		name          string
+		cpuDuration   *int32
+		memDuration   *int32
+		resizePolicy  []corev1.ContainerResizePolicy
+		expectPatches []resource_admission.PatchRecord
+	}{
+		{
+			name:        "CPU boost expired, memory boost still in progress",
+			cpuDuration: &ten,
+			memDuration: &thousand,
+			expectPatches: []resource_admission.PatchRecord{
+				{Op: "add", Path: "/spec/containers/0/resources/requests/cpu", Value: "200m"},
+				{Op: "add", Path: "/spec/containers/0/resources/requests/memory", Value: "400Mi"},
+			},
+		},
+		{
+			name:        "memory boost expired, CPU boost still in progress",
+			cpuDuration: &thousand,
+			memDuration: &ten,
+			expectPatches: []resource_admission.PatchRecord{
+				{Op: "add", Path: "/spec/containers/0/resources/requests/cpu", Value: "1"},
+				{Op: "add", Path: "/spec/containers/0/resources/requests/memory", Value: "200Mi"},
+			},
+		},
+		{
+			name:        "both boosts expired",
+			cpuDuration: &ten,
+			memDuration: &ten,
+			expectPatches: []resource_admission.PatchRecord{
+				{Op: "add", Path: "/spec/containers/0/resources/requests/cpu", Value: "200m"},
+				{Op: "add", Path: "/spec/containers/0/resources/requests/memory", Value: "200Mi"},
+			},
+		},
+		{
+			name:        "both boosts expired, memory resize restarts the container",
+			cpuDuration: &ten,
+			memDuration: &ten,
+			resizePolicy: []corev1.ContainerResizePolicy{
+				{ResourceName: corev1.ResourceMemory, RestartPolicy: corev1.RestartContainer},
+			},
+			expectPatches: []resource_admission.PatchRecord{
+				{Op: "add", Path: "/spec/containers/0/resources/requests/cpu", Value: "200m"},
+				{Op: "add", Path: "/spec/containers/0/resources/requests/memory", Value: "400Mi"},
+			},
+		},
+		{
+			name:          "no boost expired",
+			cpuDuration:   &thousand,
+			memDuration:   &thousand,
+			expectPatches: []resource_admission.PatchRecord{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotations.GetStartupCPUBoostAnnotationKey("c1"):    original,
+						annotations.GetStartupMemoryBoostAnnotationKey("c1"): original,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "c1", ResizePolicy: tc.resizePolicy, Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1"),
+							corev1.ResourceMemory: resource.MustParse("400Mi"),
+						}}},
+					},
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: past},
+					},
+				},
+			}
+			vpa := &vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					UpdatePolicy: &vpa_types.PodUpdatePolicy{UpdateMode: &[]vpa_types.UpdateMode{vpa_types.UpdateModeInPlaceOrRecreate}[0]},
+					StartupBoost: &vpa_types.StartupBoost{
+						CPU:    &vpa_types.GenericStartupBoost{DurationSeconds: tc.cpuDuration},
+						Memory: &vpa_types.GenericStartupBoost{DurationSeconds: tc.memDuration},
+					},
+				},
+			}
+			frp := fakeRecommendationProvider{resources: []vpa_api_util.ContainerResources{
+				{Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("200m"),
+					corev1.ResourceMemory: resource.MustParse("200Mi"),
+				}},
+			}}
+			calculator := resourcesInplaceUpdatesPatchCalculator{recommendationProvider: &frp}
+
+			patches, err := calculator.CalculatePatches(pod, vpa)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectPatches, patches)
+		})
+	}
+}
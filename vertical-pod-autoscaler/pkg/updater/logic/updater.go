@@ -19,11 +19,13 @@ package logic
 import (
 	"context"
 	"errors"
+	"fmt"
 	"slices"
 	"time"
 
 	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -38,6 +40,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	resourceclient "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 	"k8s.io/utils/set"
 
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/patch"
@@ -48,8 +51,10 @@ import (
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
 	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/controller_fetcher"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/canary"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/priority"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/restriction"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/schedulability"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/utils"
 	metrics_updater "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/metrics/updater"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/status"
@@ -66,6 +71,10 @@ func logDeprecationWarnings(vpa *vpa_types.VerticalPodAutoscaler) {
 	}
 }
 
+// infeasibleRecommendationReason is the reason of events emitted when a pod
+// doesn't fit on a node with its recommended resources.
+const infeasibleRecommendationReason = "InfeasibleRecommendation"
+
 // Updater performs updates on pods if recommended by Vertical Pod Autoscaler
 type Updater interface {
 	// RunOnce represents single iteration in the main-loop of Updater
@@ -91,6 +100,8 @@ type updater struct {
 	defaultUpdateThreshold       float64
 	podLifetimeUpdateThreshold   time.Duration
 	evictAfterOOMThreshold       time.Duration
+	canaryController             canary.RolloutController
+	schedulabilityChecker        schedulability.Checker
 }
 
 // NewUpdater creates Updater with given configuration
@@ -108,6 +119,7 @@ func NewUpdater(
 	defaultUpdateThreshold float64,
 	podLifetimeUpdateThreshold time.Duration,
 	evictAfterOOMThreshold time.Duration,
+	canaryRollbackBackoff time.Duration,
 	statusNamespace string,
 	recommendationProcessor vpa_api_util.RecommendationProcessor,
 	evictionAdmission priority.PodEvictionAdmission,
@@ -117,6 +129,8 @@ func NewUpdater(
 	namespace string,
 	ignoredNamespaces []string,
 	patchCalculators []patch.Calculator,
+	schedulabilityChecker schedulability.Checker,
+	podMetricsGetter resourceclient.PodMetricsesGetter,
 ) (Updater, error) {
 	evictionRateLimiter := getRateLimiter(evictionRateLimit, evictionRateBurst)
 	// TODO: Create in-place rate limits for the in-place rate limiter
@@ -128,12 +142,15 @@ func NewUpdater(
 		evictionToleranceFraction,
 		patchCalculators,
 		inPlaceSkipDisruptionBudget,
+		podMetricsGetter,
 	)
 
+	eventRecorder := newEventRecorder(kubeClient)
+
 	return &updater{
 		vpaLister:                    vpa_api_util.NewVpasLister(vpaClient, make(chan struct{}), namespace),
 		podLister:                    podLister,
-		eventRecorder:                newEventRecorder(kubeClient),
+		eventRecorder:                eventRecorder,
 		restrictionFactory:           factory,
 		recommendationProcessor:      recommendationProcessor,
 		evictionRateLimiter:          evictionRateLimiter,
@@ -153,6 +170,8 @@ func NewUpdater(
 		defaultUpdateThreshold:     defaultUpdateThreshold,
 		podLifetimeUpdateThreshold: podLifetimeUpdateThreshold,
 		evictAfterOOMThreshold:     evictAfterOOMThreshold,
+		canaryController:           canary.NewRolloutController(kubeClient, vpaClient.AutoscalingV1(), eventRecorder, canaryRollbackBackoff),
+		schedulabilityChecker:      schedulabilityChecker,
 	}, nil
 }
 
@@ -183,7 +202,7 @@ func (u *updater) RunOnce(ctx context.Context) {
 	vpas := make([]*vpa_api_util.VpaWithSelector, 0)
 
 	inPlaceFeatureEnabled := features.Enabled(features.InPlace)
-	cpuStartupBoostFeatureEnabled := features.Enabled(features.CPUStartupBoost)
+	startupBoostFeatureEnabled := features.Enabled(features.CPUStartupBoost) || features.Enabled(features.MemoryStartupBoost)
 	for _, vpa := range vpaList {
 		if slices.Contains(u.ignoredNamespaces, vpa.Namespace) {
 			klog.V(3).InfoS("Skipping VPA object in ignored namespace", "vpa", klog.KObj(vpa), "namespace", vpa.Namespace)
@@ -218,6 +237,9 @@ func (u *updater) RunOnce(ctx context.Context) {
 		if u.evictionAdmission != nil {
 			u.evictionAdmission.CleanUp()
 		}
+		if u.canaryController != nil {
+			u.canaryController.CleanUp(set.New[types.UID]())
+		}
 		return
 	}
 
@@ -243,6 +265,14 @@ func (u *updater) RunOnce(ctx context.Context) {
 	if len(u.infeasibleAttempts) > 0 {
 		u.cleanupStaleInfeasibleAttempts(livePodUIDs)
 	}
+	canaryRolloutFeatureEnabled := features.Enabled(features.CanaryRollout) && u.canaryController != nil
+	if canaryRolloutFeatureEnabled {
+		liveVpaUIDs := set.New[types.UID]()
+		for _, vpa := range vpas {
+			liveVpaUIDs.Insert(vpa.Vpa.UID)
+		}
+		u.canaryController.CleanUp(liveVpaUIDs)
+	}
 	timer.ObserveStep("FilterPods")
 
 	if u.evictionAdmission != nil {
@@ -250,6 +280,15 @@ func (u *updater) RunOnce(ctx context.Context) {
 	}
 	timer.ObserveStep("AdmissionInit")
 
+	checkSchedulability := u.schedulabilityChecker != nil
+	if checkSchedulability {
+		if err := u.schedulabilityChecker.LoopInit(); err != nil {
+			klog.ErrorS(err, "Failed to take a snapshot of nodes, skipping schedulability checks")
+			checkSchedulability = false
+		}
+	}
+	timer.ObserveStep("SchedulabilityInit")
+
 	// wrappers for metrics which are computed every loop run
 	controlledPodsCounter := metrics_updater.NewControlledPodsCounter()
 	evictablePodsCounter := metrics_updater.NewEvictablePodsCounter()
@@ -286,13 +325,13 @@ func (u *updater) RunOnce(ctx context.Context) {
 		podsToUnboost := make([]*corev1.Pod, 0)
 		withInPlaceUpdated := false
 
-		if cpuStartupBoostFeatureEnabled && vpa_api_util.HasStartupBoost(vpa) {
+		if startupBoostFeatureEnabled && vpa_api_util.HasStartupBoost(vpa) {
 			// First, handle unboosting for pods that have finished their startup period.
 			for _, pod := range livePods {
-				if len(vpa_api_util.GetExpiredStartupCPUBoostAnnotations(pod, vpa)) > 0 {
+				if len(vpa_api_util.GetExpiredStartupBoostAnnotations(pod, vpa)) > 0 {
 					podsToUnboost = append(podsToUnboost, pod)
 				}
-				if !vpa_api_util.PodHasCPUBoostInProgressAnnotation(pod) {
+				if !vpa_api_util.PodHasBoostInProgressAnnotation(pod) {
 					podsAvailableForUpdate = append(podsAvailableForUpdate, pod)
 				}
 			}
@@ -318,7 +357,7 @@ func (u *updater) RunOnce(ctx context.Context) {
 				}
 			}
 		} else {
-			// CPU Startup Boost is not enabled or configured for this VPA,
+			// Startup boost is not enabled or configured for this VPA,
 			// so all live pods are available for potential standard VPA updates.
 			podsAvailableForUpdate = livePods
 		}
@@ -353,10 +392,23 @@ func (u *updater) RunOnce(ctx context.Context) {
 			}
 		}
 
+		useCanary := canaryRolloutFeatureEnabled && vpa_api_util.GetCanaryRollout(vpa) != nil
+		if useCanary {
+			// Only canary pods are updated until they pass the soak period.
+			podsForInPlace, podsForEviction = u.canaryController.FilterPods(vpa, livePods, podsForInPlace, podsForEviction)
+		}
+
 		withEvicted := false
 
 		for _, pod := range podsForInPlace {
 			decision := inPlaceLimiter.CanInPlaceUpdate(pod, vpa, u.infeasibleAttempts)
+			if checkSchedulability && (decision == utils.InPlaceApproved || decision == utils.InPlaceInfeasible) {
+				// In InPlace mode the pod must fit on its node, otherwise it
+				// may fit anywhere as it's evicted if the resize is infeasible.
+				if !u.fitsWithRecommendation(pod, vpa, updateMode == vpa_types.UpdateModeInPlace) {
+					continue
+				}
+			}
 
 			switch decision {
 			case utils.InPlaceDeferred:
@@ -420,13 +472,22 @@ func (u *updater) RunOnce(ctx context.Context) {
 				continue
 			}
 			withInPlaceUpdated = true
+			if checkSchedulability {
+				u.reserveRecommendation(pod, vpa, true)
+			}
 			metrics_updater.AddInPlaceUpdatedPod(vpaSize, vpa.Name, vpa.Namespace)
+			if useCanary {
+				u.canaryController.RecordUpdate(vpa, pod, false)
+			}
 		}
 
 		for _, pod := range podsForEviction {
 			if !evictionLimiter.CanEvict(pod) {
 				continue
 			}
+			if checkSchedulability && !u.fitsWithRecommendation(pod, vpa, false) {
+				continue
+			}
 			err = u.evictionRateLimiter.Wait(ctx)
 			if err != nil {
 				klog.V(0).InfoS("Eviction rate limiter wait failed", "error", err)
@@ -440,7 +501,13 @@ func (u *updater) RunOnce(ctx context.Context) {
 				metrics_updater.RecordFailedEviction(vpaSize, vpa.Name, vpa.Namespace, updateMode, "EvictionError")
 			} else {
 				withEvicted = true
+				if checkSchedulability {
+					u.reserveRecommendation(pod, vpa, false)
+				}
 				metrics_updater.AddEvictedPod(vpaSize, vpa.Name, vpa.Namespace, updateMode)
+				if useCanary {
+					u.canaryController.RecordUpdate(vpa, pod, true)
+				}
 			}
 		}
 
@@ -474,6 +541,55 @@ func (u *updater) recordInfeasibleAttempt(pod *corev1.Pod, vpa *vpa_types.Vertic
 	klog.V(2).InfoS("Recorded infeasible attempt, will retry when recommendation changes", "pod", klog.KObj(pod))
 }
 
+// fitsWithRecommendation returns true if the pod fits on a node with the
+// recommended resources, on its current node if currentNodeOnly is set.
+// Otherwise the recommendation is recorded as an infeasible attempt, and an
+// event is emitted once per recommendation.
+func (u *updater) fitsWithRecommendation(pod *corev1.Pod, vpa *vpa_types.VerticalPodAutoscaler, currentNodeOnly bool) bool {
+	recommendation, _, err := u.recommendationProcessor.Apply(vpa, pod)
+	if err != nil {
+		klog.V(2).ErrorS(err, "Cannot process recommendation for pod, skipping schedulability check", "pod", klog.KObj(pod))
+		return true
+	}
+	var fits bool
+	var message string
+	if currentNodeOnly {
+		fits = u.schedulabilityChecker.FitsOnCurrentNode(pod, recommendation)
+		message = fmt.Sprintf("Recommended resources don't fit on node %s, deferring in-place update.", pod.Spec.NodeName)
+	} else {
+		fits = u.schedulabilityChecker.FitsOnAnyNode(pod, recommendation)
+		message = "Recommended resources don't fit on any node, deferring update."
+	}
+	if fits {
+		return true
+	}
+	if lastAttempt, found := u.infeasibleAttempts[pod.UID]; found && apiequality.Semantic.DeepEqual(lastAttempt, vpa.Status.Recommendation) {
+		klog.V(4).InfoS("Recommendation still doesn't fit, skipping pod", "pod", klog.KObj(pod))
+		return false
+	}
+	klog.V(2).InfoS("Recommendation doesn't fit, deferring update", "pod", klog.KObj(pod), "currentNodeOnly", currentNodeOnly)
+	u.recordInfeasibleAttempt(pod, vpa)
+	u.eventRecorder.Event(pod, corev1.EventTypeWarning, infeasibleRecommendationReason, message)
+	u.eventRecorder.Eventf(vpa, corev1.EventTypeWarning, infeasibleRecommendationReason, "Pod %s: %s", pod.Name, message)
+	return false
+}
+
+// reserveRecommendation updates the schedulability snapshot with the pod
+// updated to its recommendation, resized in place or recreated, so that the
+// pods checked next don't count on the resources it takes.
+func (u *updater) reserveRecommendation(pod *corev1.Pod, vpa *vpa_types.VerticalPodAutoscaler, inPlace bool) {
+	recommendation, _, err := u.recommendationProcessor.Apply(vpa, pod)
+	if err != nil {
+		klog.V(2).ErrorS(err, "Cannot process recommendation for pod, not reserving its resources", "pod", klog.KObj(pod))
+		return
+	}
+	if inPlace {
+		u.schedulabilityChecker.ReserveOnCurrentNode(pod, recommendation)
+	} else {
+		u.schedulabilityChecker.ReserveOnAnyNode(pod, recommendation)
+	}
+}
+
 func getRateLimiter(rateLimit float64, rateLimitBurst int) *rate.Limiter {
 	var rateLimiter *rate.Limiter
 	if rateLimit <= 0 {
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/time/rate"
 	autoscalingv1 "k8s.io/api/autoscaling/v1"
@@ -35,13 +36,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
 	featuregatetesting "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/set"
 
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_fake "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/fake"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
 	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/controller_fetcher"
 	target_mock "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/mock"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/canary"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/priority"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/restriction"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/utils"
@@ -874,6 +878,257 @@ func TestRunOnce_AutoUnboostThenInPlace(t *testing.T) {
 	eviction.AssertNumberOfCalls(t, "Evict", 0)
 }
 
+func TestRunOnce_CanaryRollout(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, features.MutableFeatureGate, features.CanaryRollout, true)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	replicas := int32(5)
+	selector := parseLabelSelector("app = testingApp")
+	containerName := "container1"
+	rc := corev1.ReplicationController{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ReplicationController",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "rc", Namespace: "default"},
+		Spec:       corev1.ReplicationControllerSpec{Replicas: &replicas},
+	}
+	vpaObj := test.VerticalPodAutoscaler().
+		WithContainer(containerName).
+		WithTarget("2", "200M").
+		WithTargetRef(&autoscalingv1.CrossVersionObjectReference{Kind: rc.Kind, Name: rc.Name, APIVersion: rc.APIVersion}).
+		WithCanaryRollout(&vpa_types.CanaryRollout{Pods: 2}).
+		Get()
+
+	eviction := &test.PodsEvictionRestrictionMock{}
+	pods := make([]*corev1.Pod, replicas)
+	for i := range pods {
+		pods[i] = test.Pod().WithName("test_"+strconv.Itoa(i)).WithUID(types.UID("test_"+strconv.Itoa(i))).
+			AddContainer(test.Container().WithName(containerName).WithCPURequest(resource.MustParse("1")).WithMemRequest(resource.MustParse("100M")).Get()).
+			WithCreator(&rc.ObjectMeta, &rc.TypeMeta).
+			Get()
+		pods[i].Labels = map[string]string{"app": "testingApp"}
+		eviction.On("CanEvict", pods[i]).Return(true)
+		eviction.On("Evict", pods[i], nil).Return(nil)
+	}
+
+	vpaLister := &test.VerticalPodAutoscalerListerMock{}
+	vpaLister.On("List").Return([]*vpa_types.VerticalPodAutoscaler{vpaObj}, nil)
+	podLister := &test.PodListerMock{}
+	podLister.On("List").Return(pods, nil)
+	mockSelectorFetcher := target_mock.NewMockVpaTargetSelectorFetcher(ctrl)
+	mockSelectorFetcher.EXPECT().Fetch(gomock.Eq(vpaObj)).Return(selector, nil).Times(2)
+
+	updater := &updater{
+		vpaLister:               vpaLister,
+		podLister:               podLister,
+		restrictionFactory:      &restriction.FakePodsRestrictionFactory{Eviction: eviction, InPlace: &test.PodsInPlaceRestrictionMock{}},
+		evictionRateLimiter:     rate.NewLimiter(rate.Inf, 0),
+		inPlaceRateLimiter:      rate.NewLimiter(rate.Inf, 0),
+		evictionAdmission:       priority.NewDefaultPodEvictionAdmission(),
+		recommendationProcessor: &test.FakeRecommendationProcessor{},
+		selectorFetcher:         mockSelectorFetcher,
+		controllerFetcher:       controllerfetcher.FakeControllerFetcher{},
+		priorityProcessor:       priority.NewProcessor(),
+		canaryController:        canary.NewRolloutController(fake.NewClientset(), vpa_fake.NewSimpleClientset().AutoscalingV1(), record.NewFakeRecorder(10), time.Hour),
+	}
+
+	updater.RunOnce(context.Background())
+	eviction.AssertNumberOfCalls(t, "Evict", 2)
+
+	// The remaining pods wait until the canary pods pass the soak period.
+	updater.RunOnce(context.Background())
+	eviction.AssertNumberOfCalls(t, "Evict", 2)
+}
+
+type fakeSchedulabilityChecker struct {
+	infeasible set.Set[types.UID]
+	// resized and recreated hold the pods whose resources were reserved.
+	resized   []types.UID
+	recreated []types.UID
+}
+
+func (f *fakeSchedulabilityChecker) LoopInit() error {
+	return nil
+}
+
+func (f *fakeSchedulabilityChecker) FitsOnCurrentNode(pod *corev1.Pod, _ *vpa_types.RecommendedPodResources) bool {
+	return !f.infeasible.Has(pod.UID)
+}
+
+func (f *fakeSchedulabilityChecker) FitsOnAnyNode(pod *corev1.Pod, _ *vpa_types.RecommendedPodResources) bool {
+	return !f.infeasible.Has(pod.UID)
+}
+
+func (f *fakeSchedulabilityChecker) ReserveOnCurrentNode(pod *corev1.Pod, _ *vpa_types.RecommendedPodResources) {
+	f.resized = append(f.resized, pod.UID)
+}
+
+func (f *fakeSchedulabilityChecker) ReserveOnAnyNode(pod *corev1.Pod, _ *vpa_types.RecommendedPodResources) {
+	f.recreated = append(f.recreated, pod.UID)
+}
+
+func TestRunOnce_SchedulabilityCheck(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	replicas := int32(3)
+	selector := parseLabelSelector("app = testingApp")
+	containerName := "container1"
+	rc := corev1.ReplicationController{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ReplicationController",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "rc", Namespace: "default"},
+		Spec:       corev1.ReplicationControllerSpec{Replicas: &replicas},
+	}
+	vpaObj := test.VerticalPodAutoscaler().
+		WithContainer(containerName).
+		WithTarget("2", "200M").
+		WithTargetRef(&autoscalingv1.CrossVersionObjectReference{Kind: rc.Kind, Name: rc.Name, APIVersion: rc.APIVersion}).
+		Get()
+
+	eviction := &test.PodsEvictionRestrictionMock{}
+	pods := make([]*corev1.Pod, replicas)
+	for i := range pods {
+		pods[i] = test.Pod().WithName("test_"+strconv.Itoa(i)).WithUID(types.UID("test_"+strconv.Itoa(i))).
+			AddContainer(test.Container().WithName(containerName).WithCPURequest(resource.MustParse("1")).WithMemRequest(resource.MustParse("100M")).Get()).
+			WithCreator(&rc.ObjectMeta, &rc.TypeMeta).
+			Get()
+		pods[i].Labels = map[string]string{"app": "testingApp"}
+		eviction.On("CanEvict", pods[i]).Return(true)
+		eviction.On("Evict", pods[i], mock.Anything).Return(nil)
+	}
+
+	vpaLister := &test.VerticalPodAutoscalerListerMock{}
+	vpaLister.On("List").Return([]*vpa_types.VerticalPodAutoscaler{vpaObj}, nil)
+	podLister := &test.PodListerMock{}
+	podLister.On("List").Return(pods, nil)
+	mockSelectorFetcher := target_mock.NewMockVpaTargetSelectorFetcher(ctrl)
+	mockSelectorFetcher.EXPECT().Fetch(gomock.Eq(vpaObj)).Return(selector, nil).Times(2)
+	eventRecorder := record.NewFakeRecorder(10)
+	schedulabilityChecker := &fakeSchedulabilityChecker{infeasible: set.New(pods[0].UID)}
+
+	updater := &updater{
+		vpaLister:               vpaLister,
+		podLister:               podLister,
+		eventRecorder:           eventRecorder,
+		restrictionFactory:      &restriction.FakePodsRestrictionFactory{Eviction: eviction, InPlace: &test.PodsInPlaceRestrictionMock{}},
+		evictionRateLimiter:     rate.NewLimiter(rate.Inf, 0),
+		inPlaceRateLimiter:      rate.NewLimiter(rate.Inf, 0),
+		evictionAdmission:       priority.NewDefaultPodEvictionAdmission(),
+		recommendationProcessor: &test.FakeRecommendationProcessor{},
+		selectorFetcher:         mockSelectorFetcher,
+		controllerFetcher:       controllerfetcher.FakeControllerFetcher{},
+		priorityProcessor:       priority.NewProcessor(),
+		infeasibleAttempts:      make(map[types.UID]*vpa_types.RecommendedPodResources),
+		schedulabilityChecker:   schedulabilityChecker,
+	}
+
+	updater.RunOnce(context.Background())
+	eviction.AssertNumberOfCalls(t, "Evict", 2)
+	eviction.AssertNotCalled(t, "Evict", pods[0], mock.Anything)
+	assert.Equal(t, vpaObj.Status.Recommendation, updater.infeasibleAttempts[pods[0].UID])
+	// The evicted pods take their recommended resources in the snapshot.
+	assert.ElementsMatch(t, []types.UID{pods[1].UID, pods[2].UID}, schedulabilityChecker.recreated)
+	// One event for the pod and one for the VPA.
+	assert.Len(t, eventRecorder.Events, 2)
+
+	// The same recommendation is not reported again.
+	updater.RunOnce(context.Background())
+	eviction.AssertNumberOfCalls(t, "Evict", 4)
+	eviction.AssertNotCalled(t, "Evict", pods[0], mock.Anything)
+	assert.Len(t, eventRecorder.Events, 2)
+}
+
+func TestRunOnce_SchedulabilityCheckInPlace(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, features.MutableFeatureGate, features.InPlace, true)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	replicas := int32(3)
+	selector := parseLabelSelector("app = testingApp")
+	containerName := "container1"
+	rc := corev1.ReplicationController{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "ReplicationController",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: "rc", Namespace: "default"},
+		Spec:       corev1.ReplicationControllerSpec{Replicas: &replicas},
+	}
+	vpaObj := test.VerticalPodAutoscaler().
+		WithContainer(containerName).
+		WithUpdateMode(vpa_types.UpdateModeInPlace).
+		WithTarget("2", "200M").
+		WithTargetRef(&autoscalingv1.CrossVersionObjectReference{Kind: rc.Kind, Name: rc.Name, APIVersion: rc.APIVersion}).
+		Get()
+
+	inplace := &test.PodsInPlaceRestrictionMock{}
+	pods := make([]*corev1.Pod, replicas)
+	for i := range pods {
+		pods[i] = test.Pod().WithName("test_"+strconv.Itoa(i)).WithUID(types.UID("test_"+strconv.Itoa(i))).
+			AddContainer(test.Container().WithName(containerName).WithCPURequest(resource.MustParse("1")).WithMemRequest(resource.MustParse("100M")).Get()).
+			WithCreator(&rc.ObjectMeta, &rc.TypeMeta).
+			Get()
+		pods[i].Labels = map[string]string{"app": "testingApp"}
+		inplace.On("CanUnboost", pods[i], vpaObj).Return(false)
+		inplace.On("CanInPlaceUpdate", pods[i]).Return(utils.InPlaceApproved)
+		inplace.On("InPlaceUpdate", pods[i], mock.Anything).Return(nil)
+	}
+
+	vpaLister := &test.VerticalPodAutoscalerListerMock{}
+	vpaLister.On("List").Return([]*vpa_types.VerticalPodAutoscaler{vpaObj}, nil)
+	podLister := &test.PodListerMock{}
+	podLister.On("List").Return(pods, nil)
+	mockSelectorFetcher := target_mock.NewMockVpaTargetSelectorFetcher(ctrl)
+	mockSelectorFetcher.EXPECT().Fetch(gomock.Any()).Return(selector, nil).Times(3)
+	schedulabilityChecker := &fakeSchedulabilityChecker{infeasible: set.New(pods[0].UID)}
+
+	updater := &updater{
+		vpaLister:               vpaLister,
+		podLister:               podLister,
+		eventRecorder:           record.NewFakeRecorder(10),
+		restrictionFactory:      &restriction.FakePodsRestrictionFactory{Eviction: &test.PodsEvictionRestrictionMock{}, InPlace: inplace},
+		evictionRateLimiter:     rate.NewLimiter(rate.Inf, 0),
+		inPlaceRateLimiter:      rate.NewLimiter(rate.Inf, 0),
+		evictionAdmission:       priority.NewDefaultPodEvictionAdmission(),
+		recommendationProcessor: &test.FakeRecommendationProcessor{},
+		selectorFetcher:         mockSelectorFetcher,
+		controllerFetcher:       controllerfetcher.FakeControllerFetcher{},
+		priorityProcessor:       priority.NewProcessor(),
+		infeasibleAttempts:      make(map[types.UID]*vpa_types.RecommendedPodResources),
+		schedulabilityChecker:   schedulabilityChecker,
+	}
+
+	// The recommendation doesn't fit on the node of the first pod.
+	updater.RunOnce(context.Background())
+	inplace.AssertNumberOfCalls(t, "InPlaceUpdate", 2)
+	inplace.AssertNotCalled(t, "InPlaceUpdate", pods[0], mock.Anything)
+	assert.Equal(t, vpaObj.Status.Recommendation, updater.infeasibleAttempts[pods[0].UID])
+	assert.ElementsMatch(t, []types.UID{pods[1].UID, pods[2].UID}, schedulabilityChecker.resized)
+
+	// As for infeasible resizes, the same recommendation isn't tried again.
+	schedulabilityChecker.infeasible = set.New[types.UID]()
+	updater.RunOnce(context.Background())
+	inplace.AssertNotCalled(t, "InPlaceUpdate", pods[0], mock.Anything)
+
+	// A lower recommendation is.
+	lowerVpa := test.VerticalPodAutoscaler().
+		WithContainer(containerName).
+		WithUpdateMode(vpa_types.UpdateModeInPlace).
+		WithTarget("1500m", "200M").
+		WithTargetRef(&autoscalingv1.CrossVersionObjectReference{Kind: rc.Kind, Name: rc.Name, APIVersion: rc.APIVersion}).
+		Get()
+	inplace.On("CanUnboost", pods[0], lowerVpa).Return(false)
+	vpaLister.ExpectedCalls = nil
+	vpaLister.On("List").Return([]*vpa_types.VerticalPodAutoscaler{lowerVpa}, nil)
+	updater.RunOnce(context.Background())
+	inplace.AssertCalled(t, "InPlaceUpdate", pods[0], mock.Anything)
+}
+
 func TestIsInfeasibleError(t *testing.T) {
 	makeStatusErr := func(reason metav1.StatusReason, causeType metav1.CauseType) error {
 		return &apierrors.StatusError{
@@ -33,17 +33,20 @@ import (
 	componentbaseconfig "k8s.io/component-base/config"
 	componentbaseoptions "k8s.io/component-base/config/options"
 	"k8s.io/klog/v2"
+	resourceclient "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 
 	"k8s.io/autoscaler/vertical-pod-autoscaler/common"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/patch"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/recommendation"
 	vpa_clientset "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target"
 	controllerfetcher "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/target/controller_fetcher"
 	updater_config "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/config"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/inplace"
 	updater "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/logic"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/priority"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/schedulability"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/client"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/limitrange"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/metrics"
@@ -173,6 +176,30 @@ func run(healthCheck *metrics.HealthCheck, commonFlag *common.CommonFlags) {
 
 	calculators := []patch.Calculator{inplace.NewResourceInPlaceUpdatesCalculator(recommendationProvider), inplace.NewInPlaceUpdatedCalculator(), inplace.NewUnboostAnnotationCalculator()}
 
+	var evictionAdmission priority.PodEvictionAdmission = priority.NewScalingDirectionPodEvictionAdmission()
+	if features.Enabled(features.MaintenanceWindows) {
+		evictionAdmission = priority.NewSequentialPodEvictionAdmission([]priority.PodEvictionAdmission{
+			evictionAdmission,
+			priority.NewMaintenanceWindowPodEvictionAdmission(vpaClient.AutoscalingV1()),
+		})
+	}
+
+	var schedulabilityChecker schedulability.Checker
+	if features.Enabled(features.SchedulabilityCheck) {
+		// Free resources of nodes depend on the pods of all namespaces.
+		nodePodLister := podLister
+		if commonFlag.VpaObjectNamespace != "" {
+			nodePodLister = updater.NewPodLister(kubeClient, "", stopCh)
+		}
+		schedulabilityChecker = schedulability.NewChecker(kubeFactory.Core().V1().Nodes().Lister(), nodePodLister, config.SchedulabilityCheckAssumeScaleUp)
+	}
+
+	// The memory usage of pods is checked before their memory boost is removed.
+	var podMetricsGetter resourceclient.PodMetricsesGetter
+	if features.Enabled(features.MemoryStartupBoost) {
+		podMetricsGetter = resourceclient.NewForConfigOrDie(kubeConfig)
+	}
+
 	updater, err := updater.NewUpdater(
 		kubeClient,
 		vpaClient,
@@ -187,15 +214,18 @@ func run(healthCheck *metrics.HealthCheck, commonFlag *common.CommonFlags) {
 		config.DefaultUpdateThreshold,
 		config.PodLifetimeUpdateThreshold,
 		config.EvictAfterOOMThreshold,
+		config.CanaryRollbackBackoff,
 		admissionControllerStatusNamespace,
 		vpa_api_util.NewCappingRecommendationProcessor(limitRangeCalculator),
-		priority.NewScalingDirectionPodEvictionAdmission(),
+		evictionAdmission,
 		targetSelectorFetcher,
 		controllerFetcher,
 		priority.NewProcessor(),
 		commonFlag.VpaObjectNamespace,
 		ignoredNamespaces,
 		calculators,
+		schedulabilityChecker,
+		podMetricsGetter,
 	)
 	if err != nil {
 		klog.ErrorS(err, "Failed to create updater")
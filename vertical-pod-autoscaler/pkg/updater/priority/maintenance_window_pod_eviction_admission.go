@@ -0,0 +1,193 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_api "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/typed/autoscaling.k8s.io/v1"
+	resourcehelpers "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/resources"
+	vpa_utils "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
+)
+
+const (
+	// ReasonOutsideMaintenanceWindow is the reason of the UpdatesDeferred
+	// condition when pod updates were deferred to a maintenance window.
+	ReasonOutsideMaintenanceWindow = "OutsideMaintenanceWindow"
+	// ReasonNoUpdatesDeferred is the reason of the UpdatesDeferred condition
+	// when no pod updates were deferred in the last updater loop.
+	ReasonNoUpdatesDeferred = "NoUpdatesDeferred"
+)
+
+// NewMaintenanceWindowPodEvictionAdmission creates a PodEvictionAdmission object.
+// It admits Pods for update only if the maintenance windows of their VPA allow the scaling direction of the update,
+// i.e. if resources are scaled up (recommendation > requests) or scaled down (recommendation < requests).
+// VPAs with updates deferred in an updater loop get the UpdatesDeferred condition in the next one.
+func NewMaintenanceWindowPodEvictionAdmission(vpaClient vpa_api.VerticalPodAutoscalersGetter) PodEvictionAdmission {
+	return &maintenanceWindowPodEvictionAdmission{
+		vpaClient: vpaClient,
+		clock:     clock.RealClock{},
+		deferred:  make(map[k8stypes.UID]map[vpa_types.ScalingDirection]time.Time),
+	}
+}
+
+type maintenanceWindowPodEvictionAdmission struct {
+	vpaClient vpa_api.VerticalPodAutoscalersGetter
+	clock     clock.Clock
+	podsVPA   map[*corev1.Pod]*vpa_types.VerticalPodAutoscaler
+	// deferred maps VPAs with updates deferred in the current loop to the
+	// scaling directions of the deferred updates and the times the next
+	// windows allowing them open.
+	deferred map[k8stypes.UID]map[vpa_types.ScalingDirection]time.Time
+}
+
+// Admit admits a Pod for update if its VPA has no maintenance windows, or if
+// the windows allow all scaling directions of the update at this time.
+func (m *maintenanceWindowPodEvictionAdmission) Admit(pod *corev1.Pod, resources *vpa_types.RecommendedPodResources) bool {
+	vpa, found := m.podsVPA[pod]
+	if !found {
+		return true
+	}
+	windows := vpa_utils.GetMaintenanceWindows(vpa)
+	if len(windows) == 0 {
+		return true
+	}
+	now := m.clock.Now()
+	admit := true
+	for _, direction := range getScalingDirections(pod, resources) {
+		allowed, next := vpa_utils.ScalingDirectionAllowed(windows, direction, now)
+		if allowed {
+			continue
+		}
+		admit = false
+		if m.deferred[vpa.UID] == nil {
+			m.deferred[vpa.UID] = make(map[vpa_types.ScalingDirection]time.Time)
+		}
+		m.deferred[vpa.UID][direction] = next
+	}
+	if !admit {
+		klog.V(2).InfoS("Pod update deferred to a maintenance window", "pod", klog.KObj(pod), "vpa", klog.KObj(vpa))
+	}
+	return admit
+}
+
+// getScalingDirections returns the directions in which the recommendation
+// scales the resource requests of the containers of the pod.
+func getScalingDirections(pod *corev1.Pod, resources *vpa_types.RecommendedPodResources) []vpa_types.ScalingDirection {
+	up, down := false, false
+	for _, container := range pod.Spec.Containers {
+		recommendedResources := vpa_utils.GetRecommendationForContainer(container.Name, resources)
+		if recommendedResources == nil {
+			continue
+		}
+		containerRequests, _ := resourcehelpers.ContainerRequestsAndLimits(container.Name, pod)
+		for resourceName, recommended := range recommendedResources.Target {
+			requested, found := containerRequests[resourceName]
+			if !found {
+				up = true
+				continue
+			}
+			switch recommended.Cmp(requested) {
+			case 1:
+				up = true
+			case -1:
+				down = true
+			}
+		}
+	}
+	var directions []vpa_types.ScalingDirection
+	if up {
+		directions = append(directions, vpa_types.ScalingDirectionUp)
+	}
+	if down {
+		directions = append(directions, vpa_types.ScalingDirectionDown)
+	}
+	return directions
+}
+
+// LoopInit reports the updates deferred in the previous loop in the conditions of the VPAs and
+// creates a map holding the VPA of each Pod.
+func (m *maintenanceWindowPodEvictionAdmission) LoopInit(_ []*corev1.Pod, vpaControlledPods map[*vpa_types.VerticalPodAutoscaler][]*corev1.Pod) {
+	m.podsVPA = make(map[*corev1.Pod]*vpa_types.VerticalPodAutoscaler)
+	for vpa, pods := range vpaControlledPods {
+		m.updateCondition(vpa)
+		for _, pod := range pods {
+			m.podsVPA[pod] = vpa
+		}
+	}
+	m.deferred = make(map[k8stypes.UID]map[vpa_types.ScalingDirection]time.Time)
+}
+
+func (m *maintenanceWindowPodEvictionAdmission) updateCondition(vpa *vpa_types.VerticalPodAutoscaler) {
+	condition := vpa_types.VerticalPodAutoscalerCondition{
+		Type:               vpa_types.UpdatesDeferred,
+		LastTransitionTime: metav1.NewTime(m.clock.Now()),
+	}
+	if deferred, found := m.deferred[vpa.UID]; found {
+		condition.Status = corev1.ConditionTrue
+		condition.Reason = ReasonOutsideMaintenanceWindow
+		condition.Message = deferredUpdatesMessage(deferred)
+	} else {
+		if !hasDeferredUpdatesCondition(vpa) {
+			return
+		}
+		condition.Status = corev1.ConditionFalse
+		condition.Reason = ReasonNoUpdatesDeferred
+	}
+	if _, err := vpa_utils.SetVpaConditionIfNeeded(m.vpaClient.VerticalPodAutoscalers(vpa.Namespace), vpa, condition); err != nil {
+		klog.ErrorS(err, "Failed to set VPA condition", "vpa", klog.KObj(vpa), "condition", vpa_types.UpdatesDeferred)
+	}
+}
+
+func hasDeferredUpdatesCondition(vpa *vpa_types.VerticalPodAutoscaler) bool {
+	for _, condition := range vpa.Status.Conditions {
+		if condition.Type == vpa_types.UpdatesDeferred && condition.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func deferredUpdatesMessage(deferred map[vpa_types.ScalingDirection]time.Time) string {
+	var messages []string
+	for _, direction := range []vpa_types.ScalingDirection{vpa_types.ScalingDirectionUp, vpa_types.ScalingDirectionDown} {
+		next, found := deferred[direction]
+		if !found {
+			continue
+		}
+		if next.IsZero() {
+			messages = append(messages, fmt.Sprintf("scale %s updates deferred, no maintenance window allowing them opens", strings.ToLower(string(direction))))
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("scale %s updates deferred until %s", strings.ToLower(string(direction)), next.UTC().Format(time.RFC3339)))
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (m *maintenanceWindowPodEvictionAdmission) CleanUp() {
+	m.podsVPA = make(map[*corev1.Pod]*vpa_types.VerticalPodAutoscaler)
+	m.deferred = make(map[k8stypes.UID]map[vpa_types.ScalingDirection]time.Time)
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package priority
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	baseclocktest "k8s.io/utils/clock/testing"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_fake "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/client/clientset/versioned/fake"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/test"
+)
+
+func nightlyScaleDownVPA(containerName string) *vpa_types.VerticalPodAutoscaler {
+	vpa := test.VerticalPodAutoscaler().WithName("test-vpa").WithNamespace("default").WithContainer(containerName).
+		WithMaintenanceWindows([]vpa_types.MaintenanceWindow{{
+			Schedule:        "0 22 * * *",
+			DurationSeconds: 8 * 3600,
+			Directions:      []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown},
+		}}).Get()
+	vpa.UID = "test-vpa-uid"
+	return vpa
+}
+
+func recommendationFor(containerName, cpu, memory string) *vpa_types.RecommendedPodResources {
+	return &vpa_types.RecommendedPodResources{
+		ContainerRecommendations: []vpa_types.RecommendedContainerResources{
+			test.Recommendation().WithContainer(containerName).WithTarget(cpu, memory).GetContainerResources(),
+		},
+	}
+}
+
+func TestMaintenanceWindowAdmit(t *testing.T) {
+	containerName := "test-container"
+	pod := test.Pod().WithName("test-pod").
+		AddContainer(test.Container().WithName(containerName).WithCPURequest(resource.MustParse("500m")).WithMemRequest(resource.MustParse("1Gi")).Get()).
+		Get()
+	day := time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC)
+	night := time.Date(2026, 10, 19, 23, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		vpa            *vpa_types.VerticalPodAutoscaler
+		now            time.Time
+		recommendation *vpa_types.RecommendedPodResources
+		expectAdmit    bool
+	}{
+		{
+			name:           "no maintenance windows",
+			vpa:            test.VerticalPodAutoscaler().WithName("test-vpa").WithContainer(containerName).Get(),
+			now:            day,
+			recommendation: recommendationFor(containerName, "250m", "512Mi"),
+			expectAdmit:    true,
+		},
+		{
+			name:           "scale up outside of a scale down window",
+			vpa:            nightlyScaleDownVPA(containerName),
+			now:            day,
+			recommendation: recommendationFor(containerName, "1", "2Gi"),
+			expectAdmit:    true,
+		},
+		{
+			name:           "scale down outside of a scale down window",
+			vpa:            nightlyScaleDownVPA(containerName),
+			now:            day,
+			recommendation: recommendationFor(containerName, "250m", "512Mi"),
+			expectAdmit:    false,
+		},
+		{
+			name:           "scale up and down outside of a scale down window",
+			vpa:            nightlyScaleDownVPA(containerName),
+			now:            day,
+			recommendation: recommendationFor(containerName, "1", "512Mi"),
+			expectAdmit:    false,
+		},
+		{
+			name:           "scale down in a scale down window",
+			vpa:            nightlyScaleDownVPA(containerName),
+			now:            night,
+			recommendation: recommendationFor(containerName, "250m", "512Mi"),
+			expectAdmit:    true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			admission := NewMaintenanceWindowPodEvictionAdmission(vpa_fake.NewSimpleClientset().AutoscalingV1()).(*maintenanceWindowPodEvictionAdmission)
+			admission.clock = baseclocktest.NewFakeClock(tc.now)
+			admission.LoopInit(nil, map[*vpa_types.VerticalPodAutoscaler][]*corev1.Pod{tc.vpa: {pod}})
+			assert.Equal(t, tc.expectAdmit, admission.Admit(pod, tc.recommendation))
+		})
+	}
+}
+
+func TestMaintenanceWindowUpdatesDeferredCondition(t *testing.T) {
+	containerName := "test-container"
+	pod := test.Pod().WithName("test-pod").
+		AddContainer(test.Container().WithName(containerName).WithCPURequest(resource.MustParse("500m")).WithMemRequest(resource.MustParse("1Gi")).Get()).
+		Get()
+	vpa := nightlyScaleDownVPA(containerName)
+	vpaClient := vpa_fake.NewSimpleClientset(vpa)
+	clock := baseclocktest.NewFakeClock(time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC))
+	admission := NewMaintenanceWindowPodEvictionAdmission(vpaClient.AutoscalingV1()).(*maintenanceWindowPodEvictionAdmission)
+	admission.clock = clock
+
+	getCondition := func() *vpa_types.VerticalPodAutoscalerCondition {
+		stored, err := vpaClient.AutoscalingV1().VerticalPodAutoscalers("default").Get(context.TODO(), "test-vpa", metav1.GetOptions{})
+		require.NoError(t, err)
+		for i := range stored.Status.Conditions {
+			if stored.Status.Conditions[i].Type == vpa_types.UpdatesDeferred {
+				return &stored.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	admission.LoopInit(nil, map[*vpa_types.VerticalPodAutoscaler][]*corev1.Pod{vpa: {pod}})
+	assert.False(t, admission.Admit(pod, recommendationFor(containerName, "250m", "512Mi")))
+	assert.Nil(t, getCondition())
+
+	// The deferred update is reported in the next loop.
+	admission.LoopInit(nil, map[*vpa_types.VerticalPodAutoscaler][]*corev1.Pod{vpa: {pod}})
+	condition := getCondition()
+	require.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionTrue, condition.Status)
+	assert.Equal(t, ReasonOutsideMaintenanceWindow, condition.Reason)
+	assert.Equal(t, "scale down updates deferred until 2026-10-19T22:00:00Z", condition.Message)
+
+	// The window opens and the update is admitted.
+	clock.SetTime(time.Date(2026, 10, 19, 22, 30, 0, 0, time.UTC))
+	vpa.Status.Conditions = []vpa_types.VerticalPodAutoscalerCondition{*condition}
+	assert.True(t, admission.Admit(pod, recommendationFor(containerName, "250m", "512Mi")))
+	admission.LoopInit(nil, map[*vpa_types.VerticalPodAutoscaler][]*corev1.Pod{vpa: {pod}})
+	condition = getCondition()
+	require.NotNil(t, condition)
+	assert.Equal(t, corev1.ConditionFalse, condition.Status)
+	assert.Equal(t, ReasonNoUpdatesDeferred, condition.Reason)
+}
@@ -21,14 +21,17 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	kube_client "k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
+	resourceclient "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 	"k8s.io/utils/clock"
 
 	resource_updates "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource"
@@ -36,6 +39,7 @@ import (
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/utils"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/annotations"
 	resourcehelpers "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/resources"
 	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
 )
@@ -83,6 +87,7 @@ type PodsInPlaceRestrictionImpl struct {
 	clock                        clock.Clock
 	lastInPlaceAttemptTimeMap    map[string]time.Time
 	inPlaceSkipDisruptionBudget  bool
+	podMetricsGetter             resourceclient.PodMetricsesGetter
 }
 
 // CanInPlaceUpdate checks if pod can be safely updated
@@ -188,13 +193,13 @@ func (ip *PodsInPlaceRestrictionImpl) CanInPlaceUpdate(pod *corev1.Pod, vpa *vpa
 
 // CanUnboost checks if a pod can be safely unboosted.
 func (ip *PodsInPlaceRestrictionImpl) CanUnboost(pod *corev1.Pod, vpa *vpa_types.VerticalPodAutoscaler) bool {
-	if !features.Enabled(features.CPUStartupBoost) {
+	if !features.Enabled(features.CPUStartupBoost) && !features.Enabled(features.MemoryStartupBoost) {
 		return false
 	}
 	if pod.Status.Phase == corev1.PodPending {
 		return false
 	}
-	expiredAnnotations := vpa_api_util.GetExpiredStartupCPUBoostAnnotations(pod, vpa)
+	expiredAnnotations := vpa_api_util.GetExpiredStartupBoostAnnotations(pod, vpa)
 	if len(expiredAnnotations) == 0 {
 		return false
 	}
@@ -203,7 +208,15 @@ func (ip *PodsInPlaceRestrictionImpl) CanUnboost(pod *corev1.Pod, vpa *vpa_types
 	if present {
 		singleGroupStats, present := ip.creatorToSingleGroupStatsMap[cr]
 		if present {
-			if isInPlaceUpdating(pod) {
+			switch resizeStatus := getResizeStatus(pod); resizeStatus {
+			case utils.ResizeStatusNone:
+			case utils.ResizeStatusInfeasible, utils.ResizeStatusDeferred:
+				klog.V(2).InfoS("Previous resize of pod not applied by the kubelet, deferring unboost", "pod", klog.KObj(pod), "status", resizeStatus)
+				return false
+			default:
+				return false
+			}
+			if !ip.canUnboostMemory(pod, vpa, expiredAnnotations) {
 				return false
 			}
 			return singleGroupStats.isPodDisruptable()
@@ -212,6 +225,94 @@ func (ip *PodsInPlaceRestrictionImpl) CanUnboost(pod *corev1.Pod, vpa *vpa_types
 	return false
 }
 
+// canUnboostMemory checks that the kubelet can lower the boosted memory of the
+// containers of the pod without setting a limit below their current usage,
+// which the kubelet refuses. Containers whose memory resize restarts them keep
+// their boosted memory, as restarting them would lose what they warmed up.
+func (ip *PodsInPlaceRestrictionImpl) canUnboostMemory(pod *corev1.Pod, vpa *vpa_types.VerticalPodAutoscaler, expiredAnnotations []string) bool {
+	unboostedContainers := make(map[string]bool)
+	for _, c := range pod.Spec.Containers {
+		if !slices.Contains(expiredAnnotations, annotations.GetStartupMemoryBoostAnnotationKey(c.Name)) {
+			continue
+		}
+		if utils.MemoryResizeRestartsContainer(c) {
+			klog.V(2).InfoS("Keeping boosted memory of container with RestartContainer resize policy", "pod", klog.KObj(pod), "container", c.Name)
+			continue
+		}
+		unboostedContainers[c.Name] = true
+	}
+	if len(unboostedContainers) == 0 {
+		return true
+	}
+
+	newLimits, err := ip.getNewMemoryLimits(pod, vpa, unboostedContainers)
+	if err != nil {
+		klog.V(2).InfoS("Can't calculate memory limits of unboosted pod, deferring unboost", "pod", klog.KObj(pod), "error", err)
+		return false
+	}
+	if len(newLimits) == 0 {
+		return true
+	}
+	if ip.podMetricsGetter == nil {
+		klog.V(4).InfoS("No metrics client, not checking memory usage before unboosting", "pod", klog.KObj(pod))
+		return true
+	}
+	podMetrics, err := ip.podMetricsGetter.PodMetricses(pod.Namespace).Get(context.TODO(), pod.Name, metav1.GetOptions{})
+	if err != nil {
+		klog.V(2).InfoS("Can't get memory usage of pod, deferring unboost", "pod", klog.KObj(pod), "error", err)
+		return false
+	}
+	for _, containerMetrics := range podMetrics.Containers {
+		limit, found := newLimits[containerMetrics.Name]
+		if !found {
+			continue
+		}
+		if usage, found := containerMetrics.Usage[corev1.ResourceMemory]; found && usage.Cmp(limit) > 0 {
+			klog.V(2).InfoS("Memory usage of container above its unboosted limit, deferring unboost", "pod", klog.KObj(pod),
+				"container", containerMetrics.Name, "usage", usage.String(), "limit", limit.String())
+			return false
+		}
+	}
+	return true
+}
+
+// getNewMemoryLimits returns the memory limits the resize patches set for the
+// given containers.
+func (ip *PodsInPlaceRestrictionImpl) getNewMemoryLimits(pod *corev1.Pod, vpa *vpa_types.VerticalPodAutoscaler, containers map[string]bool) (map[string]resource.Quantity, error) {
+	limitPaths := make(map[string]string)
+	for i, c := range pod.Spec.Containers {
+		if containers[c.Name] {
+			limitPaths[fmt.Sprintf("/spec/containers/%d/resources/limits/%s", i, corev1.ResourceMemory)] = c.Name
+		}
+	}
+	newLimits := make(map[string]resource.Quantity)
+	for _, calculator := range ip.patchCalculators {
+		if calculator.PatchResourceTarget() != patch.Resize {
+			continue
+		}
+		patches, err := calculator.CalculatePatches(pod, vpa)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range patches {
+			containerName, found := limitPaths[p.Path]
+			if !found {
+				continue
+			}
+			value, ok := p.Value.(string)
+			if !ok {
+				continue
+			}
+			limit, err := resource.ParseQuantity(value)
+			if err != nil {
+				return nil, err
+			}
+			newLimits[containerName] = limit
+		}
+	}
+	return newLimits, nil
+}
+
 // InPlaceUpdate sends calculates patches and sends resize request to api client. Returns error if client returned error.
 // Does not check if pod was actually in-place updated after grace period.
 // CanInPlaceUpdate / CanUnboost should be called first.
@@ -25,15 +25,22 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	core "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/record"
 	featuregatetesting "k8s.io/component-base/featuregate/testing"
+	metricsv1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	metricsfake "k8s.io/metrics/pkg/client/clientset/versioned/fake"
 	baseclocktest "k8s.io/utils/clock/testing"
 	"k8s.io/utils/ptr"
 
+	resource_admission "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/patch"
 	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/features"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/updater/utils"
+	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/annotations"
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/test"
 )
 
@@ -1302,3 +1309,139 @@ func TestInPlaceModeAllowsRetryForInfeasible(t *testing.T) {
 	err = inplace.InPlaceUpdate(pod, vpa, test.FakeEventRecorder())
 	assert.NoError(t, err, "InPlace mode should allow retry for infeasible pods")
 }
+
+func TestCanUnboostMemory(t *testing.T) {
+	featuregatetesting.SetFeatureGateDuringTest(t, features.MutableFeatureGate, features.MemoryStartupBoost, true)
+
+	replicas := int32(5)
+	rc := corev1.ReplicationController{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "rc",
+			Namespace: "default",
+		},
+		TypeMeta: metav1.TypeMeta{
+			Kind: "ReplicationController",
+		},
+		Spec: corev1.ReplicationControllerSpec{
+			Replicas: &replicas,
+		},
+	}
+	vpa := test.VerticalPodAutoscaler().WithName("vpa").WithContainer("container1").
+		WithMemoryStartupBoost(vpa_types.FactorStartupBoostType, ptr.To(int32(2)), nil, 10).Get()
+	readyCondition := corev1.PodCondition{
+		Type:               corev1.PodReady,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Hour)),
+	}
+	unboostLimitPatch := resource_admission.PatchRecord{
+		Op:    "add",
+		Path:  "/spec/containers/0/resources/limits/memory",
+		Value: "200Mi",
+	}
+
+	testCases := []struct {
+		name             string
+		resizePolicy     []corev1.ContainerResizePolicy
+		resizeCondition  *corev1.PodCondition
+		patches          []resource_admission.PatchRecord
+		memoryUsage      string
+		metricsErr       error
+		expectCanUnboost bool
+	}{
+		{
+			name:             "usage below the unboosted limit",
+			patches:          []resource_admission.PatchRecord{unboostLimitPatch},
+			memoryUsage:      "150Mi",
+			expectCanUnboost: true,
+		},
+		{
+			name:             "usage above the unboosted limit",
+			patches:          []resource_admission.PatchRecord{unboostLimitPatch},
+			memoryUsage:      "250Mi",
+			expectCanUnboost: false,
+		},
+		{
+			name:             "unboosted limit unchanged",
+			memoryUsage:      "250Mi",
+			expectCanUnboost: true,
+		},
+		{
+			name:             "usage unknown",
+			patches:          []resource_admission.PatchRecord{unboostLimitPatch},
+			metricsErr:       fmt.Errorf("metrics unavailable"),
+			expectCanUnboost: false,
+		},
+		{
+			name: "memory resize restarts the container",
+			resizePolicy: []corev1.ContainerResizePolicy{
+				{ResourceName: corev1.ResourceMemory, RestartPolicy: corev1.RestartContainer},
+			},
+			patches:          []resource_admission.PatchRecord{unboostLimitPatch},
+			memoryUsage:      "250Mi",
+			expectCanUnboost: true,
+		},
+		{
+			name: "cpu resize restarts the container",
+			resizePolicy: []corev1.ContainerResizePolicy{
+				{ResourceName: corev1.ResourceCPU, RestartPolicy: corev1.RestartContainer},
+				{ResourceName: corev1.ResourceMemory, RestartPolicy: corev1.NotRequired},
+			},
+			patches:          []resource_admission.PatchRecord{unboostLimitPatch},
+			memoryUsage:      "150Mi",
+			expectCanUnboost: true,
+		},
+		{
+			name:             "previous resize infeasible",
+			resizeCondition:  &corev1.PodCondition{Type: corev1.PodResizePending, Status: corev1.ConditionTrue, Reason: corev1.PodReasonInfeasible},
+			patches:          []resource_admission.PatchRecord{unboostLimitPatch},
+			memoryUsage:      "150Mi",
+			expectCanUnboost: false,
+		},
+		{
+			name:             "previous resize deferred",
+			resizeCondition:  &corev1.PodCondition{Type: corev1.PodResizePending, Status: corev1.ConditionTrue, Reason: corev1.PodReasonDeferred},
+			patches:          []resource_admission.PatchRecord{unboostLimitPatch},
+			memoryUsage:      "150Mi",
+			expectCanUnboost: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions := []corev1.PodCondition{readyCondition}
+			if tc.resizeCondition != nil {
+				conditions = append(conditions, *tc.resizeCondition)
+			}
+			pods := make([]*corev1.Pod, replicas)
+			for i := range pods {
+				pods[i] = test.Pod().WithName(getTestPodName(i)).WithCreator(&rc.ObjectMeta, &rc.TypeMeta).
+					AddContainer(test.Container().WithName("container1").WithContainerResizePolicy(tc.resizePolicy).Get()).
+					WithAnnotations(map[string]string{annotations.GetStartupMemoryBoostAnnotationKey("container1"): "{}"}).
+					WithPodConditions(conditions).Get()
+			}
+
+			metricsClient := &metricsfake.Clientset{}
+			metricsClient.AddReactor("get", "pods", func(action core.Action) (bool, runtime.Object, error) {
+				if tc.metricsErr != nil {
+					return true, nil, tc.metricsErr
+				}
+				return true, &metricsv1beta1.PodMetrics{
+					ObjectMeta: metav1.ObjectMeta{Name: pods[0].Name, Namespace: pods[0].Namespace},
+					Containers: []metricsv1beta1.ContainerMetrics{{
+						Name:  "container1",
+						Usage: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(tc.memoryUsage)},
+					}},
+				}, nil
+			})
+
+			calculators := []patch.Calculator{&fakeResizePatchCalculator{patches: tc.patches}}
+			factory, err := getRestrictionFactory(&rc, nil, nil, nil, 2, 0.5, nil, nil, calculators, false)
+			assert.NoError(t, err)
+			factory.(*PodsRestrictionFactoryImpl).podMetricsGetter = metricsClient.MetricsV1beta1()
+			creatorToSingleGroupStatsMap, podToReplicaCreatorMap, err := factory.GetCreatorMaps(pods, vpa)
+			assert.NoError(t, err)
+			inplace := factory.NewPodsInPlaceRestriction(creatorToSingleGroupStatsMap, podToReplicaCreatorMap)
+
+			assert.Equal(t, tc.expectCanUnboost, inplace.CanUnboost(pods[0], vpa))
+		})
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"k8s.io/client-go/informers"
 	kube_client "k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
+	resourceclient "k8s.io/metrics/pkg/client/clientset/versioned/typed/metrics/v1beta1"
 	"k8s.io/utils/clock"
 
 	"k8s.io/autoscaler/vertical-pod-autoscaler/pkg/admission-controller/resource/pod/patch"
@@ -70,10 +71,13 @@ type PodsRestrictionFactoryImpl struct {
 	lastInPlaceAttemptTimeMap   map[string]time.Time
 	patchCalculators            []patch.Calculator
 	inPlaceSkipDisruptionBudget bool
+	podMetricsGetter            resourceclient.PodMetricsesGetter
 }
 
-// NewPodsRestrictionFactory creates a new PodsRestrictionFactory.
-func NewPodsRestrictionFactory(client kube_client.Interface, informerFactory informers.SharedInformerFactory, minReplicas int, evictionToleranceFraction float64, patchCalculators []patch.Calculator, inPlaceSkipDisruptionBudget bool) PodsRestrictionFactory {
+// NewPodsRestrictionFactory creates a new PodsRestrictionFactory. The pod
+// metrics getter is used to check the memory usage of pods before unboosting
+// them, it may be nil.
+func NewPodsRestrictionFactory(client kube_client.Interface, informerFactory informers.SharedInformerFactory, minReplicas int, evictionToleranceFraction float64, patchCalculators []patch.Calculator, inPlaceSkipDisruptionBudget bool, podMetricsGetter resourceclient.PodMetricsesGetter) PodsRestrictionFactory {
 	return &PodsRestrictionFactoryImpl{
 		client:                      client,
 		informerFactory:             informerFactory,
@@ -83,6 +87,7 @@ func NewPodsRestrictionFactory(client kube_client.Interface, informerFactory inf
 		lastInPlaceAttemptTimeMap:   make(map[string]time.Time),
 		patchCalculators:            patchCalculators,
 		inPlaceSkipDisruptionBudget: inPlaceSkipDisruptionBudget,
+		podMetricsGetter:            podMetricsGetter,
 	}
 }
 
@@ -196,7 +201,7 @@ func (f *PodsRestrictionFactoryImpl) GetCreatorMaps(pods []*corev1.Pod, vpa *vpa
 		if actual < required {
 			// If checking for unboost, we want to process even if we have fewer replicas than required.
 			hasBoostedPod := slices.ContainsFunc(replicas, func(pod *corev1.Pod) bool {
-				return vpa_api_util.PodHasCPUBoostInProgressAnnotation(pod)
+				return vpa_api_util.PodHasBoostInProgressAnnotation(pod)
 			})
 			if !hasBoostedPod && !skipReplicaCheck {
 				klog.V(2).InfoS("Too few replicas", "kind", creator.Kind, "object", klog.KRef(creator.Namespace, creator.Name), "livePods", actual, "requiredPods", required, "globalMinReplicas", f.minReplicas)
@@ -261,6 +266,7 @@ func (f *PodsRestrictionFactoryImpl) NewPodsInPlaceRestriction(creatorToSingleGr
 		lastInPlaceAttemptTimeMap:    f.lastInPlaceAttemptTimeMap,
 		patchCalculators:             f.patchCalculators,
 		inPlaceSkipDisruptionBudget:  f.inPlaceSkipDisruptionBudget,
+		podMetricsGetter:             f.podMetricsGetter,
 	}
 }
 
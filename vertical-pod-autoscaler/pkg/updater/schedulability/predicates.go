@@ -0,0 +1,229 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulability
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/klog/v2"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+	vpa_api_util "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/utils/vpa"
+)
+
+// The predicates below are a subset of the ones of kube-scheduler, covering
+// what decides whether a pod can land on a node at all: its node selector,
+// required node affinity and tolerations.
+
+// nodeSelectorOperators maps the operators of node selector requirements to
+// the operators of label selectors.
+var nodeSelectorOperators = map[corev1.NodeSelectorOperator]selection.Operator{
+	corev1.NodeSelectorOpIn:           selection.In,
+	corev1.NodeSelectorOpNotIn:        selection.NotIn,
+	corev1.NodeSelectorOpExists:       selection.Exists,
+	corev1.NodeSelectorOpDoesNotExist: selection.DoesNotExist,
+	corev1.NodeSelectorOpGt:           selection.GreaterThan,
+	corev1.NodeSelectorOpLt:           selection.LessThan,
+}
+
+// matchesNodeSelectorAndAffinity returns true if the pod's node selector and
+// required node affinity match a node with the given name and labels.
+func matchesNodeSelectorAndAffinity(pod *corev1.Pod, nodeName string, nodeLabels map[string]string) bool {
+	if len(pod.Spec.NodeSelector) > 0 && !labels.SelectorFromSet(pod.Spec.NodeSelector).Matches(labels.Set(nodeLabels)) {
+		return false
+	}
+	affinity := pod.Spec.Affinity
+	if affinity == nil || affinity.NodeAffinity == nil || affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution == nil {
+		return true
+	}
+	// Terms are ORed.
+	for _, term := range affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms {
+		if matchesNodeSelectorTerm(term, nodeName, nodeLabels) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesNodeSelectorTerm returns true if all requirements of the term match.
+// An empty term matches no node.
+func matchesNodeSelectorTerm(term corev1.NodeSelectorTerm, nodeName string, nodeLabels map[string]string) bool {
+	if len(term.MatchExpressions) == 0 && len(term.MatchFields) == 0 {
+		return false
+	}
+	for _, expr := range term.MatchExpressions {
+		op, found := nodeSelectorOperators[expr.Operator]
+		if !found {
+			return false
+		}
+		requirement, err := labels.NewRequirement(expr.Key, op, expr.Values)
+		if err != nil {
+			klog.V(4).InfoS("Invalid node selector requirement", "requirement", expr, "error", err)
+			return false
+		}
+		if !requirement.Matches(labels.Set(nodeLabels)) {
+			return false
+		}
+	}
+	for _, field := range term.MatchFields {
+		// metadata.name is the only field supported in node selector terms.
+		if field.Key != "metadata.name" {
+			return false
+		}
+		nameMatches := nodeName != "" && len(field.Values) == 1 && field.Values[0] == nodeName
+		switch field.Operator {
+		case corev1.NodeSelectorOpIn:
+			if !nameMatches {
+				return false
+			}
+		case corev1.NodeSelectorOpNotIn:
+			if nameMatches {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// toleratesTaints returns true if the pod tolerates all taints preventing
+// scheduling of new pods, except for the ignored ones.
+func toleratesTaints(pod *corev1.Pod, taints []corev1.Taint, ignored func(corev1.Taint) bool) bool {
+	for i := range taints {
+		taint := &taints[i]
+		if taint.Effect == corev1.TaintEffectPreferNoSchedule {
+			continue
+		}
+		if ignored != nil && ignored(*taint) {
+			continue
+		}
+		if !toleratesTaint(pod.Spec.Tolerations, taint) {
+			return false
+		}
+	}
+	return true
+}
+
+func toleratesTaint(tolerations []corev1.Toleration, taint *corev1.Taint) bool {
+	for i := range tolerations {
+		if tolerations[i].ToleratesTaint(klog.Background(), taint, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// podRequests returns the resources the scheduler reserves for the pod: the
+// sum of the requests of its containers and sidecars, or of the largest init
+// container if that's more, plus the pod overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		addResources(requests, container.Resources.Requests)
+	}
+	sidecars := corev1.ResourceList{}
+	for _, container := range pod.Spec.InitContainers {
+		if isSidecar(container) {
+			addResources(requests, container.Resources.Requests)
+			addResources(sidecars, container.Resources.Requests)
+			continue
+		}
+		// An init container runs alongside the sidecars started before it.
+		initRequests := corev1.ResourceList{}
+		addResources(initRequests, sidecars)
+		addResources(initRequests, container.Resources.Requests)
+		maxResources(requests, initRequests)
+	}
+	if pod.Spec.Resources != nil {
+		// Pod level requests take precedence over the containers' ones.
+		for resourceName, quantity := range pod.Spec.Resources.Requests {
+			requests[resourceName] = quantity.DeepCopy()
+		}
+	}
+	addResources(requests, pod.Spec.Overhead)
+	return requests
+}
+
+// recommendedPodRequests returns the requests of the pod once its containers
+// are resized to the recommendation.
+func recommendedPodRequests(pod *corev1.Pod, recommendation *vpa_types.RecommendedPodResources) corev1.ResourceList {
+	recommended := pod.DeepCopy()
+	applyRecommendation(recommended.Spec.Containers, recommendation)
+	applyRecommendation(recommended.Spec.InitContainers, recommendation)
+	return podRequests(recommended)
+}
+
+func applyRecommendation(containers []corev1.Container, recommendation *vpa_types.RecommendedPodResources) {
+	for i := range containers {
+		containerRecommendation := vpa_api_util.GetRecommendationForContainer(containers[i].Name, recommendation)
+		if containerRecommendation == nil {
+			continue
+		}
+		if containers[i].Resources.Requests == nil {
+			containers[i].Resources.Requests = corev1.ResourceList{}
+		}
+		for resourceName, quantity := range containerRecommendation.Target {
+			containers[i].Resources.Requests[resourceName] = quantity.DeepCopy()
+		}
+	}
+}
+
+func isSidecar(container corev1.Container) bool {
+	return container.RestartPolicy != nil && *container.RestartPolicy == corev1.ContainerRestartPolicyAlways
+}
+
+func addResources(sum, resources corev1.ResourceList) {
+	for resourceName, quantity := range resources {
+		value := sum[resourceName]
+		value.Add(quantity)
+		sum[resourceName] = value
+	}
+}
+
+func subtractResources(diff, resources corev1.ResourceList) {
+	for resourceName, quantity := range resources {
+		value := diff[resourceName]
+		value.Sub(quantity)
+		diff[resourceName] = value
+	}
+}
+
+func maxResources(result, resources corev1.ResourceList) {
+	for resourceName, quantity := range resources {
+		if value, found := result[resourceName]; !found || quantity.Cmp(value) > 0 {
+			result[resourceName] = quantity.DeepCopy()
+		}
+	}
+}
+
+// fitsResources returns true if every request fits in the free resources.
+func fitsResources(requests, free corev1.ResourceList) bool {
+	return !hasHigherResource(requests, free)
+}
+
+// hasHigherResource returns true if any resource in a is higher than in b.
+// Resources missing from b count as zero.
+func hasHigherResource(a, b corev1.ResourceList) bool {
+	for resourceName, quantity := range a {
+		if quantity.Cmp(b[resourceName]) > 0 {
+			return true
+		}
+	}
+	return false
+}
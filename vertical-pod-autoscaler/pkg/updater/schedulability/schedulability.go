@@ -0,0 +1,291 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package schedulability checks that pods still fit in the cluster with their
+// recommended resources. The updater uses it to avoid evicting pods that would
+// stay pending afterwards, or resizing pods beyond what their node can fit.
+package schedulability
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+const (
+	// toBeDeletedTaint is the taint Cluster Autoscaler adds to nodes it's
+	// about to delete.
+	toBeDeletedTaint = "ToBeDeletedByClusterAutoscaler"
+	// deletionCandidateTaint is the taint Cluster Autoscaler adds to nodes it
+	// considers for deletion.
+	deletionCandidateTaint = "DeletionCandidateOfClusterAutoscaler"
+)
+
+// transientTaints are the taints a new node of a node group doesn't have,
+// even if existing nodes do.
+var transientTaints = map[string]bool{
+	corev1.TaintNodeNotReady:           true,
+	corev1.TaintNodeUnreachable:        true,
+	corev1.TaintNodeUnschedulable:      true,
+	corev1.TaintNodeMemoryPressure:     true,
+	corev1.TaintNodeDiskPressure:       true,
+	corev1.TaintNodePIDPressure:        true,
+	corev1.TaintNodeNetworkUnavailable: true,
+	toBeDeletedTaint:                   true,
+	deletionCandidateTaint:             true,
+}
+
+// Checker checks whether pods fit in the cluster with their recommended
+// resources.
+type Checker interface {
+	// LoopInit takes a snapshot of the nodes and of the pods running on them.
+	// It must be called at the beginning of each updater loop.
+	LoopInit() error
+	// FitsOnCurrentNode returns true if the pod fits on the node it's running
+	// on once its containers are resized to the recommendation.
+	FitsOnCurrentNode(pod *corev1.Pod, recommendation *vpa_types.RecommendedPodResources) bool
+	// FitsOnAnyNode returns true if the pod, recreated with the
+	// recommendation, fits on any node of the cluster. If scale-ups are
+	// assumed, a new node like one of the existing nodes counts too.
+	FitsOnAnyNode(pod *corev1.Pod, recommendation *vpa_types.RecommendedPodResources) bool
+	// ReserveOnCurrentNode updates the snapshot with the pod resized in place
+	// to the recommendation, so that pods checked afterwards don't count on
+	// the resources it takes.
+	ReserveOnCurrentNode(pod *corev1.Pod, recommendation *vpa_types.RecommendedPodResources)
+	// ReserveOnAnyNode updates the snapshot with the pod recreated with the
+	// recommendation on a node it fits on, so that pods checked afterwards
+	// don't count on the resources it takes. A pod that only fits on a new
+	// node isn't added to any node.
+	ReserveOnAnyNode(pod *corev1.Pod, recommendation *vpa_types.RecommendedPodResources)
+}
+
+// nodeInfo is the state of a node in the snapshot.
+type nodeInfo struct {
+	node *corev1.Node
+	// requested is the sum of requests of the pods on the node.
+	requested corev1.ResourceList
+	pods      int64
+	// daemonSetRequested is the sum of requests of the DaemonSet pods on the
+	// node, which a new node like this one would run too.
+	daemonSetRequested corev1.ResourceList
+	daemonSetPods      int64
+}
+
+type checker struct {
+	nodeLister    listersv1.NodeLister
+	podLister     listersv1.PodLister
+	assumeScaleUp bool
+	nodes         map[string]*nodeInfo
+}
+
+// NewChecker returns a Checker using the nodes and pods of the listers. The
+// pod lister must list the pods of all namespaces, or the free resources of
+// nodes are overestimated. If assumeScaleUp is true, pods that fit on an empty
+// node like one of the existing ones are considered schedulable, because
+// Cluster Autoscaler can add such a node.
+func NewChecker(nodeLister listersv1.NodeLister, podLister listersv1.PodLister, assumeScaleUp bool) Checker {
+	return &checker{
+		nodeLister:    nodeLister,
+		podLister:     podLister,
+		assumeScaleUp: assumeScaleUp,
+		nodes:         make(map[string]*nodeInfo),
+	}
+}
+
+func (c *checker) LoopInit() error {
+	nodes, err := c.nodeLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	pods, err := c.podLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	c.nodes = make(map[string]*nodeInfo, len(nodes))
+	for _, node := range nodes {
+		c.nodes[node.Name] = &nodeInfo{
+			node:               node,
+			requested:          corev1.ResourceList{},
+			daemonSetRequested: corev1.ResourceList{},
+		}
+	}
+	for _, pod := range pods {
+		info, found := c.nodes[pod.Spec.NodeName]
+		if !found || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		requests := podRequests(pod)
+		addResources(info.requested, requests)
+		info.pods++
+		if isDaemonSetPod(pod) {
+			addResources(info.daemonSetRequested, requests)
+			info.daemonSetPods++
+		}
+	}
+	return nil
+}
+
+func (c *checker) FitsOnCurrentNode(pod *corev1.Pod, recommendation *vpa_types.RecommendedPodResources) bool {
+	currentRequests := podRequests(pod)
+	recommendedRequests := recommendedPodRequests(pod, recommendation)
+	if !hasHigherResource(recommendedRequests, currentRequests) {
+		return true
+	}
+	info, found := c.nodes[pod.Spec.NodeName]
+	if !found {
+		// Without the node we can't tell, let the kubelet decide.
+		klog.V(4).InfoS("Node of pod not found, skipping schedulability check", "pod", klog.KObj(pod), "node", pod.Spec.NodeName)
+		return true
+	}
+	return c.fitsOnNode(pod, recommendedRequests, currentRequests, info)
+}
+
+func (c *checker) FitsOnAnyNode(pod *corev1.Pod, recommendation *vpa_types.RecommendedPodResources) bool {
+	currentRequests := podRequests(pod)
+	recommendedRequests := recommendedPodRequests(pod, recommendation)
+	if !hasHigherResource(recommendedRequests, currentRequests) {
+		return true
+	}
+	if c.findNode(pod, recommendedRequests, currentRequests) != nil {
+		return true
+	}
+	if !c.assumeScaleUp {
+		return false
+	}
+	for _, info := range c.nodes {
+		if c.fitsOnNewNodeLike(pod, recommendedRequests, info) {
+			klog.V(4).InfoS("Pod fits on a new node like an existing one", "pod", klog.KObj(pod), "node", info.node.Name)
+			return true
+		}
+	}
+	return false
+}
+
+func (c *checker) ReserveOnCurrentNode(pod *corev1.Pod, recommendation *vpa_types.RecommendedPodResources) {
+	info, found := c.nodes[pod.Spec.NodeName]
+	if !found {
+		return
+	}
+	currentRequests := podRequests(pod)
+	recommendedRequests := recommendedPodRequests(pod, recommendation)
+	subtractResources(info.requested, currentRequests)
+	addResources(info.requested, recommendedRequests)
+	if isDaemonSetPod(pod) {
+		subtractResources(info.daemonSetRequested, currentRequests)
+		addResources(info.daemonSetRequested, recommendedRequests)
+	}
+}
+
+func (c *checker) ReserveOnAnyNode(pod *corev1.Pod, recommendation *vpa_types.RecommendedPodResources) {
+	if isDaemonSetPod(pod) {
+		// A DaemonSet pod is recreated on its node.
+		c.ReserveOnCurrentNode(pod, recommendation)
+		return
+	}
+	currentRequests := podRequests(pod)
+	recommendedRequests := recommendedPodRequests(pod, recommendation)
+	target := c.findNode(pod, recommendedRequests, currentRequests)
+	if info, found := c.nodes[pod.Spec.NodeName]; found {
+		subtractResources(info.requested, currentRequests)
+		info.pods--
+	}
+	if target != nil {
+		addResources(target.requested, recommendedRequests)
+		target.pods++
+	}
+}
+
+// findNode returns an existing node the pod fits on with the recommended
+// requests, or nil if there is none.
+func (c *checker) findNode(pod *corev1.Pod, recommendedRequests, currentRequests corev1.ResourceList) *nodeInfo {
+	for _, info := range c.nodes {
+		if !isSchedulable(info.node) {
+			continue
+		}
+		if !matchesNodeSelectorAndAffinity(pod, info.node.Name, info.node.Labels) || !toleratesTaints(pod, info.node.Spec.Taints, nil) {
+			continue
+		}
+		if c.fitsOnNode(pod, recommendedRequests, currentRequests, info) {
+			return info
+		}
+	}
+	return nil
+}
+
+// fitsOnNode returns true if the pod fits on the node with the recommended
+// requests. The current requests of the pod are freed if it runs there.
+func (c *checker) fitsOnNode(pod *corev1.Pod, recommendedRequests, currentRequests corev1.ResourceList, info *nodeInfo) bool {
+	free := info.node.Status.Allocatable.DeepCopy()
+	subtractResources(free, info.requested)
+	pods := info.pods
+	if pod.Spec.NodeName == info.node.Name {
+		addResources(free, currentRequests)
+		pods--
+	}
+	return fitsPodCount(info.node, pods) && fitsResources(recommendedRequests, free)
+}
+
+// fitsOnNewNodeLike returns true if the pod fits on a new node like the given
+// one, running only the DaemonSet pods.
+func (c *checker) fitsOnNewNodeLike(pod *corev1.Pod, recommendedRequests corev1.ResourceList, info *nodeInfo) bool {
+	templateLabels := make(map[string]string, len(info.node.Labels))
+	for key, value := range info.node.Labels {
+		if key != corev1.LabelHostname {
+			templateLabels[key] = value
+		}
+	}
+	if !matchesNodeSelectorAndAffinity(pod, "", templateLabels) {
+		return false
+	}
+	if !toleratesTaints(pod, info.node.Spec.Taints, func(taint corev1.Taint) bool { return transientTaints[taint.Key] }) {
+		return false
+	}
+	free := info.node.Status.Allocatable.DeepCopy()
+	subtractResources(free, info.daemonSetRequested)
+	return fitsPodCount(info.node, info.daemonSetPods) && fitsResources(recommendedRequests, free)
+}
+
+// fitsPodCount returns true if one more pod fits on a node already running
+// the given number of pods.
+func fitsPodCount(node *corev1.Node, pods int64) bool {
+	allocatable, found := node.Status.Allocatable[corev1.ResourcePods]
+	if !found {
+		return true
+	}
+	return pods+1 <= allocatable.Value()
+}
+
+// isSchedulable returns true if new pods can be scheduled on the node.
+func isSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable || node.DeletionTimestamp != nil {
+		return false
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func isDaemonSetPod(pod *corev1.Pod) bool {
+	controller := metav1.GetControllerOf(pod)
+	return controller != nil && controller.Kind == "DaemonSet"
+}
@@ -0,0 +1,340 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schedulability
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	listersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+const containerName = "container"
+
+func newNode(name, cpu string, modifiers ...func(*corev1.Node)) *corev1.Node {
+	node := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{corev1.LabelHostname: name}},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse("8Gi"),
+				corev1.ResourcePods:   resource.MustParse("110"),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionTrue}},
+		},
+	}
+	for _, modify := range modifiers {
+		modify(node)
+	}
+	return node
+}
+
+func newPod(name, nodeName, cpu string, modifiers ...func(*corev1.Pod)) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name: containerName,
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceCPU:    resource.MustParse(cpu),
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+			}},
+		},
+	}
+	for _, modify := range modifiers {
+		modify(pod)
+	}
+	return pod
+}
+
+func cpuRecommendation(cpu string) *vpa_types.RecommendedPodResources {
+	return &vpa_types.RecommendedPodResources{
+		ContainerRecommendations: []vpa_types.RecommendedContainerResources{{
+			ContainerName: containerName,
+			Target: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse("1Gi"),
+			},
+		}},
+	}
+}
+
+func newTestChecker(t *testing.T, nodes []*corev1.Node, pods []*corev1.Pod, assumeScaleUp bool) Checker {
+	nodeIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, node := range nodes {
+		assert.NoError(t, nodeIndexer.Add(node))
+	}
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, pod := range pods {
+		assert.NoError(t, podIndexer.Add(pod))
+	}
+	checker := NewChecker(listersv1.NewNodeLister(nodeIndexer), listersv1.NewPodLister(podIndexer), assumeScaleUp)
+	assert.NoError(t, checker.LoopInit())
+	return checker
+}
+
+func withTaint(key string) func(*corev1.Node) {
+	return func(node *corev1.Node) {
+		node.Spec.Taints = append(node.Spec.Taints, corev1.Taint{Key: key, Effect: corev1.TaintEffectNoSchedule})
+	}
+}
+
+func withLabel(key, value string) func(*corev1.Node) {
+	return func(node *corev1.Node) {
+		node.Labels[key] = value
+	}
+}
+
+func notReady(node *corev1.Node) {
+	node.Status.Conditions[0].Status = corev1.ConditionFalse
+}
+
+func unschedulable(node *corev1.Node) {
+	node.Spec.Unschedulable = true
+}
+
+func daemonSetPod(pod *corev1.Pod) {
+	isController := true
+	pod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", Name: "ds", Controller: &isController}}
+}
+
+func TestFitsOnAnyNode(t *testing.T) {
+	pod := newPod("pod", "node-1", "1")
+	testCases := []struct {
+		name           string
+		pod            *corev1.Pod
+		nodes          []*corev1.Node
+		pods           []*corev1.Pod
+		recommendation *vpa_types.RecommendedPodResources
+		assumeScaleUp  bool
+		expectFits     bool
+	}{
+		{
+			name:           "scaling down always fits",
+			pod:            pod,
+			recommendation: cpuRecommendation("500m"),
+			expectFits:     true,
+		},
+		{
+			name:           "fits on current node once the pod is gone",
+			pod:            pod,
+			nodes:          []*corev1.Node{newNode("node-1", "4")},
+			pods:           []*corev1.Pod{pod, newPod("other", "node-1", "2")},
+			recommendation: cpuRecommendation("2"),
+			expectFits:     true,
+		},
+		{
+			name:           "doesn't fit on full node",
+			pod:            pod,
+			nodes:          []*corev1.Node{newNode("node-1", "4")},
+			pods:           []*corev1.Pod{pod, newPod("other", "node-1", "2")},
+			recommendation: cpuRecommendation("3"),
+			expectFits:     false,
+		},
+		{
+			name:           "fits on another node",
+			pod:            pod,
+			nodes:          []*corev1.Node{newNode("node-1", "4"), newNode("node-2", "4")},
+			pods:           []*corev1.Pod{pod, newPod("other", "node-1", "2")},
+			recommendation: cpuRecommendation("3"),
+			expectFits:     true,
+		},
+		{
+			name:           "not ready node is skipped",
+			pod:            pod,
+			nodes:          []*corev1.Node{newNode("node-1", "1"), newNode("node-2", "4", notReady)},
+			pods:           []*corev1.Pod{pod},
+			recommendation: cpuRecommendation("3"),
+			expectFits:     false,
+		},
+		{
+			name:           "unschedulable node is skipped",
+			pod:            pod,
+			nodes:          []*corev1.Node{newNode("node-1", "1"), newNode("node-2", "4", unschedulable)},
+			pods:           []*corev1.Pod{pod},
+			recommendation: cpuRecommendation("3"),
+			expectFits:     false,
+		},
+		{
+			name:           "node with taint not tolerated is skipped",
+			pod:            pod,
+			nodes:          []*corev1.Node{newNode("node-1", "1"), newNode("node-2", "4", withTaint("dedicated"))},
+			pods:           []*corev1.Pod{pod},
+			recommendation: cpuRecommendation("3"),
+			expectFits:     false,
+		},
+		{
+			name: "node with tolerated taint",
+			pod: newPod("pod", "node-1", "1", func(pod *corev1.Pod) {
+				pod.Spec.Tolerations = []corev1.Toleration{{Key: "dedicated", Operator: corev1.TolerationOpExists}}
+			}),
+			nodes:          []*corev1.Node{newNode("node-1", "1"), newNode("node-2", "4", withTaint("dedicated"))},
+			recommendation: cpuRecommendation("3"),
+			expectFits:     true,
+		},
+		{
+			name: "node not matching node selector is skipped",
+			pod: newPod("pod", "node-1", "1", func(pod *corev1.Pod) {
+				pod.Spec.NodeSelector = map[string]string{"pool": "a"}
+			}),
+			nodes:          []*corev1.Node{newNode("node-1", "1", withLabel("pool", "a")), newNode("node-2", "4", withLabel("pool", "b"))},
+			recommendation: cpuRecommendation("3"),
+			expectFits:     false,
+		},
+		{
+			name: "node matching required node affinity",
+			pod: newPod("pod", "node-1", "1", func(pod *corev1.Pod) {
+				pod.Spec.Affinity = &corev1.Affinity{NodeAffinity: &corev1.NodeAffinity{
+					RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+						NodeSelectorTerms: []corev1.NodeSelectorTerm{
+							{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"c"}}}},
+							{MatchExpressions: []corev1.NodeSelectorRequirement{{Key: "pool", Operator: corev1.NodeSelectorOpIn, Values: []string{"a", "b"}}}},
+						},
+					},
+				}}
+			}),
+			nodes:          []*corev1.Node{newNode("node-1", "1", withLabel("pool", "a")), newNode("node-2", "4", withLabel("pool", "b"))},
+			recommendation: cpuRecommendation("3"),
+			expectFits:     true,
+		},
+		{
+			name: "node without room for more pods is skipped",
+			pod:  pod,
+			nodes: []*corev1.Node{newNode("node-1", "1"), newNode("node-2", "4", func(node *corev1.Node) {
+				node.Status.Allocatable[corev1.ResourcePods] = resource.MustParse("1")
+			})},
+			pods:           []*corev1.Pod{pod, newPod("other", "node-2", "0")},
+			recommendation: cpuRecommendation("3"),
+			expectFits:     false,
+		},
+		{
+			name:           "fits on a new node like a full one",
+			pod:            pod,
+			nodes:          []*corev1.Node{newNode("node-1", "4", withTaint(corev1.TaintNodeUnschedulable))},
+			pods:           []*corev1.Pod{pod, newPod("other", "node-1", "2"), newPod("ds", "node-1", "500m", daemonSetPod)},
+			recommendation: cpuRecommendation("3"),
+			assumeScaleUp:  true,
+			expectFits:     true,
+		},
+		{
+			name:           "doesn't fit on a new node with the DaemonSet pods",
+			pod:            pod,
+			nodes:          []*corev1.Node{newNode("node-1", "4")},
+			pods:           []*corev1.Pod{pod, newPod("other", "node-1", "2"), newPod("ds", "node-1", "2", daemonSetPod)},
+			recommendation: cpuRecommendation("3"),
+			assumeScaleUp:  true,
+			expectFits:     false,
+		},
+		{
+			name:           "doesn't fit on a new node with a taint not tolerated",
+			pod:            pod,
+			nodes:          []*corev1.Node{newNode("node-1", "1"), newNode("node-2", "4", withTaint("dedicated"))},
+			pods:           []*corev1.Pod{pod},
+			recommendation: cpuRecommendation("3"),
+			assumeScaleUp:  true,
+			expectFits:     false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checker := newTestChecker(t, tc.nodes, tc.pods, tc.assumeScaleUp)
+			assert.Equal(t, tc.expectFits, checker.FitsOnAnyNode(tc.pod, tc.recommendation))
+		})
+	}
+}
+
+func TestFitsOnCurrentNode(t *testing.T) {
+	pod := newPod("pod", "node-1", "1")
+	nodes := []*corev1.Node{newNode("node-1", "4"), newNode("node-2", "8")}
+	pods := []*corev1.Pod{pod, newPod("other", "node-1", "2")}
+	checker := newTestChecker(t, nodes, pods, true)
+
+	assert.True(t, checker.FitsOnCurrentNode(pod, cpuRecommendation("2")))
+	assert.False(t, checker.FitsOnCurrentNode(pod, cpuRecommendation("3")))
+	assert.True(t, checker.FitsOnAnyNode(pod, cpuRecommendation("3")))
+}
+
+func TestReserveOnCurrentNode(t *testing.T) {
+	podA := newPod("pod-a", "node-1", "1")
+	podB := newPod("pod-b", "node-1", "1")
+	checker := newTestChecker(t, []*corev1.Node{newNode("node-1", "4")}, []*corev1.Pod{podA, podB}, true)
+
+	assert.True(t, checker.FitsOnCurrentNode(podA, cpuRecommendation("2500m")))
+	assert.True(t, checker.FitsOnCurrentNode(podB, cpuRecommendation("2500m")))
+	checker.ReserveOnCurrentNode(podA, cpuRecommendation("2500m"))
+	assert.False(t, checker.FitsOnCurrentNode(podB, cpuRecommendation("2500m")))
+}
+
+func TestReserveOnAnyNode(t *testing.T) {
+	t.Run("recreated on an existing node", func(t *testing.T) {
+		podA := newPod("pod-a", "node-1", "1")
+		podB := newPod("pod-b", "node-1", "1")
+		checker := newTestChecker(t, []*corev1.Node{newNode("node-1", "4")}, []*corev1.Pod{podA, podB}, false)
+
+		assert.True(t, checker.FitsOnAnyNode(podB, cpuRecommendation("3")))
+		checker.ReserveOnAnyNode(podA, cpuRecommendation("3"))
+		assert.False(t, checker.FitsOnAnyNode(podB, cpuRecommendation("3")))
+	})
+	t.Run("recreated on a new node", func(t *testing.T) {
+		podA := newPod("pod-a", "node-1", "3")
+		podB := newPod("pod-b", "node-1", "1")
+		checker := newTestChecker(t, []*corev1.Node{newNode("node-1", "4")}, []*corev1.Pod{podA, podB}, true)
+
+		assert.True(t, checker.FitsOnAnyNode(podA, cpuRecommendation("3500m")))
+		assert.False(t, checker.FitsOnCurrentNode(podB, cpuRecommendation("3")))
+		checker.ReserveOnAnyNode(podA, cpuRecommendation("3500m"))
+		// The requests of the first pod are freed on its node.
+		assert.True(t, checker.FitsOnCurrentNode(podB, cpuRecommendation("3")))
+	})
+}
+
+func TestPodRequests(t *testing.T) {
+	always := corev1.ContainerRestartPolicyAlways
+	pod := newPod("pod", "node-1", "1", func(pod *corev1.Pod) {
+		pod.Spec.InitContainers = []corev1.Container{
+			{
+				Name:          "sidecar",
+				RestartPolicy: &always,
+				Resources:     corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m")}},
+			},
+			{
+				Name:      "init",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")}},
+			},
+		}
+		pod.Spec.Overhead = corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("100m")}
+	})
+
+	requests := podRequests(pod)
+	// The init container runs with the sidecar: 2 + 0.5 CPU, plus the overhead.
+	assert.Equal(t, int64(2600), requests.Cpu().MilliValue())
+	assert.Equal(t, int64(1<<30), requests.Memory().Value())
+
+	requests = recommendedPodRequests(pod, cpuRecommendation("3"))
+	// The container and sidecar take more than the init container now.
+	assert.Equal(t, int64(3600), requests.Cpu().MilliValue())
+}
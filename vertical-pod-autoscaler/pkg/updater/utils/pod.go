@@ -30,6 +30,17 @@ func GetPodCondition(pod *corev1.Pod, conditionType corev1.PodConditionType) (co
 	return corev1.PodCondition{}, false
 }
 
+// MemoryResizeRestartsContainer returns whether resizing the memory of the
+// container restarts it.
+func MemoryResizeRestartsContainer(c corev1.Container) bool {
+	for _, policy := range c.ResizePolicy {
+		if policy.ResourceName == corev1.ResourceMemory {
+			return policy.RestartPolicy == corev1.RestartContainer
+		}
+	}
+	return false
+}
+
 // IsNonDisruptiveResize checks if all containers in the pod have NotRequired
 // resize policy for the resources being resized. If any container requires
 // restart for any resource, returns false.
@@ -26,6 +26,9 @@ const (
 	// StartupCPUBoostAnnotationPrefix is the prefix for the annotation set on a pod when a CPU boost is applied.
 	// The value of the annotation is the original resource specification of the container.
 	StartupCPUBoostAnnotationPrefix = "vpaCpuStartupBoost/"
+	// StartupMemoryBoostAnnotationPrefix is the prefix for the annotation set on a pod when a memory boost is applied.
+	// The value of the annotation is the original resource specification of the container.
+	StartupMemoryBoostAnnotationPrefix = "vpaMemoryStartupBoost/"
 )
 
 // OriginalResources contains the original resources of a container.
@@ -61,9 +64,18 @@ func GetStartupCPUBoostAnnotationKey(containerName string) string {
 	return StartupCPUBoostAnnotationPrefix + containerName
 }
 
+// GetStartupMemoryBoostAnnotationKey returns the memory boost annotation key for a given container.
+func GetStartupMemoryBoostAnnotationKey(containerName string) string {
+	return StartupMemoryBoostAnnotationPrefix + containerName
+}
+
 // GetOriginalResourcesFromAnnotation returns the original resources from the annotation for a specific container.
+// The CPU and memory boost annotations both hold the original resources of the container.
 func GetOriginalResourcesFromAnnotation(pod *corev1.Pod, containerName string) (*OriginalResources, error) {
 	val, ok := pod.Annotations[GetStartupCPUBoostAnnotationKey(containerName)]
+	if !ok {
+		val, ok = pod.Annotations[GetStartupMemoryBoostAnnotationKey(containerName)]
+	}
 	if !ok {
 		return nil, nil
 	}
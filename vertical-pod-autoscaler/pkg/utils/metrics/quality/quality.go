@@ -131,11 +131,28 @@ func Register() {
 	prometheus.MustRegister(relativeRecommendationChange)
 }
 
+// UsageRecommendationRelativeDiff returns the diff between usage and
+// recommendation, normalized by the recommendation value. It returns false if
+// the recommendation doesn't have a positive value.
+func UsageRecommendationRelativeDiff(usage, recommendation float64) (float64, bool) {
+	if recommendation > 0 {
+		return (usage - recommendation) / recommendation, true
+	}
+	return 0, false
+}
+
+// RecommendationOverUsage returns true if the recommendation is above the
+// usage. Usage at or above the recommendation may cause CPU throttling or an
+// OOM.
+func RecommendationOverUsage(usage, recommendation float64) bool {
+	return recommendation > usage
+}
+
 // observeUsageRecommendationRelativeDiff records relative diff between usage and
 // recommendation if recommendation has a positive value.
 func observeUsageRecommendationRelativeDiff(usage, recommendation float64, isOOM bool, resource corev1.ResourceName, updateMode *vpa_types.UpdateMode) {
-	if recommendation > 0 {
-		usageRecommendationRelativeDiff.WithLabelValues(updateModeToString(updateMode), string(resource), strconv.FormatBool(isOOM)).Observe((usage - recommendation) / recommendation)
+	if diff, ok := UsageRecommendationRelativeDiff(usage, recommendation); ok {
+		usageRecommendationRelativeDiff.WithLabelValues(updateModeToString(updateMode), string(resource), strconv.FormatBool(isOOM)).Observe(diff)
 	}
 }
 
@@ -147,7 +164,7 @@ func observeMissingRecommendation(isOOM bool, resource corev1.ResourceName, upda
 // observeUsageRecommendationDiff records absolute diff between usage and
 // recommendation.
 func observeUsageRecommendationDiff(usage, recommendation float64, isRecommendationMissing, isOOM bool, resource corev1.ResourceName, updateMode *vpa_types.UpdateMode) {
-	recommendationOverUsage := recommendation > usage
+	recommendationOverUsage := RecommendationOverUsage(usage, recommendation)
 	diff := math.Abs(usage - recommendation)
 	switch resource {
 	case corev1.ResourceCPU:
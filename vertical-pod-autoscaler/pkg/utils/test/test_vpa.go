@@ -49,10 +49,13 @@ type VerticalPodAutoscalerBuilder interface {
 	WithGroupVersion(gv metav1.GroupVersion) VerticalPodAutoscalerBuilder
 	WithEvictionRequirements([]*vpa_types.EvictionRequirement) VerticalPodAutoscalerBuilder
 	WithMinReplicas(minReplicas *int32) VerticalPodAutoscalerBuilder
+	WithCanaryRollout(canaryRollout *vpa_types.CanaryRollout) VerticalPodAutoscalerBuilder
+	WithMaintenanceWindows(windows []vpa_types.MaintenanceWindow) VerticalPodAutoscalerBuilder
 	WithOOMBumpUpRatio(ratio *resource.Quantity) VerticalPodAutoscalerBuilder
 	WithOOMMinBumpUp(minBumpUp *resource.Quantity) VerticalPodAutoscalerBuilder
 	WithCPUStartupBoost(boostType vpa_types.StartupBoostType, factor *int32, quantity *resource.Quantity, durationSeconds int32) VerticalPodAutoscalerBuilder
 	WithContainerCPUStartupBoost(containerName string, boostType vpa_types.StartupBoostType, factor *int32, quantity *resource.Quantity, durationSeconds int32) VerticalPodAutoscalerBuilder
+	WithMemoryStartupBoost(boostType vpa_types.StartupBoostType, factor *int32, quantity *resource.Quantity, durationSeconds int32) VerticalPodAutoscalerBuilder
 	AppendCondition(conditionType vpa_types.VerticalPodAutoscalerConditionType,
 		status corev1.ConditionStatus, reason, message string, lastTransitionTime time.Time) VerticalPodAutoscalerBuilder
 	AppendRecommendation(vpa_types.RecommendedContainerResources) VerticalPodAutoscalerBuilder
@@ -234,6 +237,26 @@ func (b *verticalPodAutoscalerBuilder) WithMinReplicas(minReplicas *int32) Verti
 	return &c
 }
 
+func (b *verticalPodAutoscalerBuilder) WithCanaryRollout(canaryRollout *vpa_types.CanaryRollout) VerticalPodAutoscalerBuilder {
+	updateModeAuto := vpa_types.UpdateModeRecreate
+	c := *b
+	if c.updatePolicy == nil {
+		c.updatePolicy = &vpa_types.PodUpdatePolicy{UpdateMode: &updateModeAuto}
+	}
+	c.updatePolicy.CanaryRollout = canaryRollout
+	return &c
+}
+
+func (b *verticalPodAutoscalerBuilder) WithMaintenanceWindows(windows []vpa_types.MaintenanceWindow) VerticalPodAutoscalerBuilder {
+	updateModeAuto := vpa_types.UpdateModeRecreate
+	c := *b
+	if c.updatePolicy == nil {
+		c.updatePolicy = &vpa_types.PodUpdatePolicy{UpdateMode: &updateModeAuto}
+	}
+	c.updatePolicy.MaintenanceWindows = windows
+	return &c
+}
+
 func (b *verticalPodAutoscalerBuilder) WithOOMBumpUpRatio(ratio *resource.Quantity) VerticalPodAutoscalerBuilder {
 	c := *b
 	c.oomBumpUpRatio = ratio
@@ -300,6 +323,23 @@ func (b *verticalPodAutoscalerBuilder) WithContainerCPUStartupBoost(containerNam
 	return &c
 }
 
+func (b *verticalPodAutoscalerBuilder) WithMemoryStartupBoost(boostType vpa_types.StartupBoostType, factor *int32, quantity *resource.Quantity, durationSeconds int32) VerticalPodAutoscalerBuilder {
+	c := *b
+	memoryStartupBoost := &vpa_types.GenericStartupBoost{
+		Type:            boostType,
+		DurationSeconds: &durationSeconds,
+		Factor:          factor,
+		Quantity:        quantity,
+	}
+	startupBoost := &vpa_types.StartupBoost{}
+	if c.startupBoost != nil {
+		startupBoost.CPU = c.startupBoost.CPU
+	}
+	startupBoost.Memory = memoryStartupBoost
+	c.startupBoost = startupBoost
+	return &c
+}
+
 func (b *verticalPodAutoscalerBuilder) Get() *vpa_types.VerticalPodAutoscaler {
 	if len(b.containerNames) == 0 {
 		panic("Must call WithContainer() before Get()")
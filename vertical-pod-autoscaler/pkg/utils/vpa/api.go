@@ -79,6 +79,39 @@ func UpdateVpaStatusIfNeeded(vpaClient vpa_api.VerticalPodAutoscalerInterface, v
 	return nil, nil
 }
 
+// SetVpaConditionIfNeeded sets the given condition in the status of the VPA API
+// object, keeping its other conditions. The last transition time is only
+// updated when the status of the condition changes.
+func SetVpaConditionIfNeeded(vpaClient vpa_api.VerticalPodAutoscalerInterface, vpa *vpa_types.VerticalPodAutoscaler,
+	condition vpa_types.VerticalPodAutoscalerCondition) (result *vpa_types.VerticalPodAutoscaler, err error) {
+	conditions := make([]vpa_types.VerticalPodAutoscalerCondition, 0, len(vpa.Status.Conditions)+1)
+	found := false
+	for _, existing := range vpa.Status.Conditions {
+		if existing.Type != condition.Type {
+			conditions = append(conditions, existing)
+			continue
+		}
+		found = true
+		if existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message {
+			return nil, nil
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		}
+		conditions = append(conditions, condition)
+	}
+	if !found {
+		conditions = append(conditions, condition)
+	}
+
+	patches := []patchRecord{{
+		Op:    "add",
+		Path:  "/status/conditions",
+		Value: conditions,
+	}}
+	return patchVpaStatus(vpaClient, vpa.Name, patches)
+}
+
 // NewVpasLister returns VerticalPodAutoscalerLister configured to fetch all VPA objects from namespace,
 // set namespace to k8sapiv1.NamespaceAll to select all namespaces.
 // The method blocks until vpaLister is initially populated.
@@ -260,6 +293,21 @@ func HasStartupBoost(vpa *vpa_types.VerticalPodAutoscaler) bool {
 	return false
 }
 
+// HasHPACoordination returns true if VPA requests its CPU recommendation to be
+// coordinated with the HorizontalPodAutoscaler scaling the same target.
+func HasHPACoordination(vpa *vpa_types.VerticalPodAutoscaler) bool {
+	return vpa.Spec.HPACoordination != nil && vpa.Spec.HPACoordination.Mode == vpa_types.HPACoordinationModeCPUUtilization
+}
+
+// GetCanaryRollout returns the canary rollout settings of the VPA, or nil if
+// pod updates don't go through a canary stage.
+func GetCanaryRollout(vpa *vpa_types.VerticalPodAutoscaler) *vpa_types.CanaryRollout {
+	if vpa.Spec.UpdatePolicy == nil {
+		return nil
+	}
+	return vpa.Spec.UpdatePolicy.CanaryRollout
+}
+
 // GetContainerResourcePolicy returns the ContainerResourcePolicy for a given policy
 // and container name. It returns nil if there is no policy specified for the container.
 func GetContainerResourcePolicy(containerName string, policy *vpa_types.PodResourcePolicy) *vpa_types.ContainerResourcePolicy {
@@ -310,8 +358,9 @@ func CreateOrUpdateVpaCheckpoint(vpaCheckpointClient vpa_api.VerticalPodAutoscal
 	return nil
 }
 
-// GetExpiredStartupCPUBoostAnnotations returns the names of the containers that have passed their startup boost duration.
-func GetExpiredStartupCPUBoostAnnotations(pod *corev1.Pod, vpa *vpa_types.VerticalPodAutoscaler) []string {
+// GetExpiredStartupBoostAnnotations returns the CPU and memory startup boost annotations of the containers that
+// have passed their startup boost duration.
+func GetExpiredStartupBoostAnnotations(pod *corev1.Pod, vpa *vpa_types.VerticalPodAutoscaler) []string {
 	var expiredAnnotations []string
 
 	_, readyCond := GetPodCondition(&pod.Status, corev1.PodReady)
@@ -321,39 +370,60 @@ func GetExpiredStartupCPUBoostAnnotations(pod *corev1.Pod, vpa *vpa_types.Vertic
 	readyTime := readyCond.LastTransitionTime.Time
 
 	for k := range pod.Annotations {
+		var boostDuration int32
 		if containerName, found := strings.CutPrefix(k, annotations.StartupCPUBoostAnnotationPrefix); found {
-			boostDuration := getContainerCPUStartupBoostDuration(containerName, vpa)
-			if boostDuration == 0 || time.Since(readyTime) > time.Duration(boostDuration)*time.Second {
-				expiredAnnotations = append(expiredAnnotations, k)
-			}
+			boostDuration = getContainerStartupBoostDuration(containerName, vpa, corev1.ResourceCPU)
+		} else if containerName, found := strings.CutPrefix(k, annotations.StartupMemoryBoostAnnotationPrefix); found {
+			boostDuration = getContainerStartupBoostDuration(containerName, vpa, corev1.ResourceMemory)
+		} else {
+			continue
+		}
+		if boostDuration == 0 || time.Since(readyTime) > time.Duration(boostDuration)*time.Second {
+			expiredAnnotations = append(expiredAnnotations, k)
 		}
 	}
 
 	return expiredAnnotations
 }
 
-func getContainerCPUStartupBoostDuration(containerName string, vpa *vpa_types.VerticalPodAutoscaler) int32 {
+// GetStartupBoostForResource returns the startup boost policy of the given resource, or nil if there isn't one.
+func GetStartupBoostForResource(startupBoost *vpa_types.StartupBoost, resourceName corev1.ResourceName) *vpa_types.GenericStartupBoost {
+	if startupBoost == nil {
+		return nil
+	}
+	switch resourceName {
+	case corev1.ResourceCPU:
+		return startupBoost.CPU
+	case corev1.ResourceMemory:
+		return startupBoost.Memory
+	}
+	return nil
+}
+
+func getContainerStartupBoostDuration(containerName string, vpa *vpa_types.VerticalPodAutoscaler, resourceName corev1.ResourceName) int32 {
 	var boostDuration int32
-	if vpa.Spec.StartupBoost != nil && vpa.Spec.StartupBoost.CPU != nil && vpa.Spec.StartupBoost.CPU.DurationSeconds != nil {
-		boostDuration = *vpa.Spec.StartupBoost.CPU.DurationSeconds // Default to pod-level
+	if boost := GetStartupBoostForResource(vpa.Spec.StartupBoost, resourceName); boost != nil && boost.DurationSeconds != nil {
+		boostDuration = *boost.DurationSeconds // Default to pod-level
 	}
 
 	if vpa.Spec.ResourcePolicy != nil {
 		crp := GetContainerResourcePolicy(containerName, vpa.Spec.ResourcePolicy)
-		if crp != nil && crp.StartupBoost != nil && crp.StartupBoost.CPU != nil && crp.StartupBoost.CPU.DurationSeconds != nil {
-			boostDuration = *crp.StartupBoost.CPU.DurationSeconds
+		if crp != nil {
+			if boost := GetStartupBoostForResource(crp.StartupBoost, resourceName); boost != nil && boost.DurationSeconds != nil {
+				boostDuration = *boost.DurationSeconds
+			}
 		}
 	}
 	return boostDuration
 }
 
-// PodHasCPUBoostInProgressAnnotation returns true if the pod has any CPU boost in progress annotation.
-func PodHasCPUBoostInProgressAnnotation(pod *corev1.Pod) bool {
+// PodHasBoostInProgressAnnotation returns true if the pod has any CPU or memory boost in progress annotation.
+func PodHasBoostInProgressAnnotation(pod *corev1.Pod) bool {
 	if pod.Annotations == nil {
 		return false
 	}
 	for k := range pod.Annotations {
-		if strings.HasPrefix(k, annotations.StartupCPUBoostAnnotationPrefix) {
+		if strings.HasPrefix(k, annotations.StartupCPUBoostAnnotationPrefix) || strings.HasPrefix(k, annotations.StartupMemoryBoostAnnotationPrefix) {
 			return true
 		}
 	}
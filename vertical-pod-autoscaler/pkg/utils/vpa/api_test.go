@@ -111,6 +111,68 @@ func TestUpdateVpaIfNeeded(t *testing.T) {
 	}
 }
 
+func TestSetVpaConditionIfNeeded(t *testing.T) {
+	earlier := metav1.NewTime(time.Unix(1000, 0))
+	later := metav1.NewTime(time.Unix(2000, 0))
+	vpaBuilder := test.VerticalPodAutoscaler().WithName("vpa").WithNamespace("test").WithContainer(containerName).
+		AppendCondition(vpa_types.RecommendationProvided, corev1.ConditionTrue, "reason", "msg", earlier.Time)
+
+	testCases := []struct {
+		caseName               string
+		vpa                    *vpa_types.VerticalPodAutoscaler
+		condition              vpa_types.VerticalPodAutoscalerCondition
+		expectedUpdate         bool
+		expectedTransitionTime metav1.Time
+	}{
+		{
+			caseName:               "Adds a missing condition.",
+			vpa:                    vpaBuilder.Get(),
+			condition:              vpa_types.VerticalPodAutoscalerCondition{Type: vpa_types.CanaryRolledBack, Status: corev1.ConditionTrue, Reason: "reason", LastTransitionTime: later},
+			expectedUpdate:         true,
+			expectedTransitionTime: later,
+		}, {
+			caseName:  "Doesn't update an unchanged condition.",
+			vpa:       vpaBuilder.AppendCondition(vpa_types.CanaryRolledBack, corev1.ConditionTrue, "reason", "", earlier.Time).Get(),
+			condition: vpa_types.VerticalPodAutoscalerCondition{Type: vpa_types.CanaryRolledBack, Status: corev1.ConditionTrue, Reason: "reason", LastTransitionTime: later},
+		}, {
+			caseName:               "Keeps the transition time if the status doesn't change.",
+			vpa:                    vpaBuilder.AppendCondition(vpa_types.CanaryRolledBack, corev1.ConditionTrue, "reason", "", earlier.Time).Get(),
+			condition:              vpa_types.VerticalPodAutoscalerCondition{Type: vpa_types.CanaryRolledBack, Status: corev1.ConditionTrue, Reason: "other", LastTransitionTime: later},
+			expectedUpdate:         true,
+			expectedTransitionTime: earlier,
+		}, {
+			caseName:               "Updates the transition time if the status changes.",
+			vpa:                    vpaBuilder.AppendCondition(vpa_types.CanaryRolledBack, corev1.ConditionTrue, "reason", "", earlier.Time).Get(),
+			condition:              vpa_types.VerticalPodAutoscalerCondition{Type: vpa_types.CanaryRolledBack, Status: corev1.ConditionFalse, Reason: "reason", LastTransitionTime: later},
+			expectedUpdate:         true,
+			expectedTransitionTime: later,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.caseName, func(t *testing.T) {
+			fakeClient := vpa_fake.NewSimpleClientset(tc.vpa) //nolint:staticcheck // https://github.com/kubernetes/autoscaler/issues/8954
+			vpaClient := fakeClient.AutoscalingV1().VerticalPodAutoscalers(tc.vpa.Namespace)
+			_, err := SetVpaConditionIfNeeded(vpaClient, tc.vpa, tc.condition)
+			assert.NoError(t, err, "Unexpected error occurred.")
+			if !tc.expectedUpdate {
+				assert.Empty(t, fakeClient.Actions(), "Unexpected number of actions")
+				return
+			}
+			assert.Equal(t, 1, len(fakeClient.Actions()), "Unexpected number of actions")
+			updated, err := vpaClient.Get(context.TODO(), tc.vpa.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Len(t, updated.Status.Conditions, 2)
+			for _, condition := range updated.Status.Conditions {
+				if condition.Type == tc.condition.Type {
+					assert.Equal(t, tc.condition.Status, condition.Status)
+					assert.Equal(t, tc.condition.Reason, condition.Reason)
+					assert.True(t, tc.expectedTransitionTime.Equal(&condition.LastTransitionTime))
+				}
+			}
+		})
+	}
+}
+
 func TestPodMatchesVPA(t *testing.T) {
 	type testCase struct {
 		pod             *corev1.Pod
@@ -400,7 +462,7 @@ func TestFindParentControllerForPod(t *testing.T) {
 	}
 }
 
-func TestGetExpiredStartupCPUBoostAnnotations(t *testing.T) {
+func TestGetExpiredStartupBoostAnnotations(t *testing.T) {
 	now := metav1.Now()
 	past := metav1.Time{Time: now.Add(-2 * time.Minute)}
 	duration60 := int32(60)
@@ -663,16 +725,54 @@ func TestGetExpiredStartupCPUBoostAnnotations(t *testing.T) {
 			},
 			expected: []string{"vpaCpuStartupBoost/c1"},
 		},
+		{
+			name: "CPU and memory boosts with different durations",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"vpaCpuStartupBoost/c1":    "",
+						"vpaMemoryStartupBoost/c1": "",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{Name: "c1"},
+					},
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{
+							Type:               corev1.PodReady,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: past,
+						},
+					},
+				},
+			},
+			vpa: &vpa_types.VerticalPodAutoscaler{
+				Spec: vpa_types.VerticalPodAutoscalerSpec{
+					StartupBoost: &vpa_types.StartupBoost{
+						CPU: &vpa_types.GenericStartupBoost{
+							DurationSeconds: &duration60,
+						},
+						Memory: &vpa_types.GenericStartupBoost{
+							DurationSeconds: &duration300,
+						},
+					},
+				},
+			},
+			expected: []string{"vpaCpuStartupBoost/c1"},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			actual := GetExpiredStartupCPUBoostAnnotations(tc.pod, tc.vpa)
+			actual := GetExpiredStartupBoostAnnotations(tc.pod, tc.vpa)
 			assert.ElementsMatch(t, tc.expected, actual)
 		})
 	}
 }
 
-func TestPodHasCPUBoostInProgressAnnotation(t *testing.T) {
+func TestPodHasBoostInProgressAnnotation(t *testing.T) {
 	testCases := []struct {
 		name     string
 		pod      *corev1.Pod
@@ -694,6 +794,17 @@ func TestPodHasCPUBoostInProgressAnnotation(t *testing.T) {
 			},
 			expected: true,
 		},
+		{
+			name: "Memory annotation present",
+			pod: &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"vpaMemoryStartupBoost/c1": "",
+					},
+				},
+			},
+			expected: true,
+		},
 		{
 			name: "Annotation not present",
 			pod: &corev1.Pod{
@@ -708,7 +819,7 @@ func TestPodHasCPUBoostInProgressAnnotation(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			assert.Equal(t, tc.expected, PodHasCPUBoostInProgressAnnotation(tc.pod))
+			assert.Equal(t, tc.expected, PodHasBoostInProgressAnnotation(tc.pod))
 		})
 	}
 }
@@ -0,0 +1,64 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+// CanaryRollbackAnnotation is the VPA annotation holding the resources pods of
+// the VPA are admitted with after its canary pods were rolled back.
+const CanaryRollbackAnnotation = "vpa-updater.autoscaling.k8s.io/canary-rollback"
+
+// CanaryRollback are the container resources of canary pods before the
+// rolled back update.
+type CanaryRollback struct {
+	// Pods maps the names of canary pods to the resources of their containers
+	// before the update. Pods recreated with the same name, e.g. by a
+	// StatefulSet, are admitted with these.
+	Pods map[string]map[string]corev1.ResourceRequirements `json:"pods,omitempty"`
+	// Recommendation is the last recommendation that passed a canary stage.
+	// Other pods of the VPA are admitted with it, or with the resources of
+	// their spec if there is none.
+	Recommendation *vpa_types.RecommendedPodResources `json:"recommendation,omitempty"`
+}
+
+// GetCanaryRollback returns the canary rollback of the VPA, nil if its canary
+// pods aren't rolled back.
+func GetCanaryRollback(vpa *vpa_types.VerticalPodAutoscaler) (*CanaryRollback, error) {
+	value, found := vpa.Annotations[CanaryRollbackAnnotation]
+	if !found {
+		return nil, nil
+	}
+	rollback := &CanaryRollback{}
+	if err := json.Unmarshal([]byte(value), rollback); err != nil {
+		return nil, fmt.Errorf("failed to parse %s annotation: %v", CanaryRollbackAnnotation, err)
+	}
+	return rollback, nil
+}
+
+// ResourcesForPod returns the container resources a canary pod with the given
+// name had before the update, and false if the pod isn't a canary.
+func (r *CanaryRollback) ResourcesForPod(name string) (map[string]corev1.ResourceRequirements, bool) {
+	resources, found := r.Pods[name]
+	return resources, found
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/klog/v2"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+// GetMaintenanceWindows returns the maintenance windows of the VPA, or nil if
+// pod updates aren't restricted to maintenance windows.
+func GetMaintenanceWindows(vpa *vpa_types.VerticalPodAutoscaler) []vpa_types.MaintenanceWindow {
+	if vpa.Spec.UpdatePolicy == nil {
+		return nil
+	}
+	return vpa.Spec.UpdatePolicy.MaintenanceWindows
+}
+
+// ParseMaintenanceWindowSchedule parses the cron schedule of the maintenance
+// window in its time zone.
+func ParseMaintenanceWindowSchedule(window vpa_types.MaintenanceWindow) (cron.Schedule, error) {
+	location := time.UTC
+	if window.TimeZone != nil {
+		var err error
+		location, err = time.LoadLocation(*window.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("unknown time zone %q: %w", *window.TimeZone, err)
+		}
+	}
+	schedule, err := cron.ParseStandard(window.Schedule)
+	if err != nil {
+		return nil, err
+	}
+	specSchedule, ok := schedule.(*cron.SpecSchedule)
+	if !ok {
+		return nil, fmt.Errorf("schedule %q doesn't start at fixed times", window.Schedule)
+	}
+	// Schedules without a time zone are parsed in the local time zone of the
+	// component, use the one of the window instead.
+	if window.TimeZone != nil || specSchedule.Location == time.Local {
+		specSchedule.Location = location
+	}
+	// Schedules like "0 0 31 2 *" parse, but never start a window.
+	if specSchedule.Next(time.Now()).IsZero() {
+		return nil, fmt.Errorf("schedule %q never starts", window.Schedule)
+	}
+	return specSchedule, nil
+}
+
+// ScalingDirectionAllowed returns whether pod updates scaling resources in the
+// direction are allowed at the given time by the maintenance windows. If they
+// aren't, it also returns the time the next window allowing them opens, or the
+// zero time if no such window is known.
+func ScalingDirectionAllowed(windows []vpa_types.MaintenanceWindow, direction vpa_types.ScalingDirection, now time.Time) (bool, time.Time) {
+	restricted := false
+	var next time.Time
+	for _, window := range windows {
+		if !slices.Contains(window.Directions, direction) {
+			continue
+		}
+		restricted = true
+		schedule, err := ParseMaintenanceWindowSchedule(window)
+		if err != nil {
+			klog.V(4).InfoS("Ignoring maintenance window with an invalid schedule", "schedule", window.Schedule, "error", err)
+			continue
+		}
+		duration := time.Duration(window.DurationSeconds) * time.Second
+		// The window is open if it last started less than its duration ago.
+		// Next returns the zero time if the schedule doesn't start anymore.
+		if lastStart := schedule.Next(now.Add(-duration)); !lastStart.IsZero() && !lastStart.After(now) {
+			return true, time.Time{}
+		}
+		start := schedule.Next(now)
+		if start.IsZero() {
+			continue
+		}
+		if next.IsZero() || start.Before(next) {
+			next = start
+		}
+	}
+	if !restricted {
+		return true, time.Time{}
+	}
+	return false, next
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/ptr"
+
+	vpa_types "k8s.io/autoscaler/vertical-pod-autoscaler/pkg/apis/autoscaling.k8s.io/v1"
+)
+
+func TestParseMaintenanceWindowSchedule(t *testing.T) {
+	testCases := []struct {
+		name      string
+		window    vpa_types.MaintenanceWindow
+		expectErr bool
+	}{
+		{
+			name:   "cron expression",
+			window: vpa_types.MaintenanceWindow{Schedule: "0 22 * * 1-5"},
+		},
+		{
+			name:   "descriptor",
+			window: vpa_types.MaintenanceWindow{Schedule: "@daily"},
+		},
+		{
+			name:   "time zone",
+			window: vpa_types.MaintenanceWindow{Schedule: "0 22 * * *", TimeZone: ptr.To("Europe/Berlin")},
+		},
+		{
+			name:      "invalid expression",
+			window:    vpa_types.MaintenanceWindow{Schedule: "0 25 * * *"},
+			expectErr: true,
+		},
+		{
+			name:      "constant delay",
+			window:    vpa_types.MaintenanceWindow{Schedule: "@every 1h"},
+			expectErr: true,
+		},
+		{
+			name:      "never starts",
+			window:    vpa_types.MaintenanceWindow{Schedule: "0 0 31 2 *"},
+			expectErr: true,
+		},
+		{
+			name:      "unknown time zone",
+			window:    vpa_types.MaintenanceWindow{Schedule: "0 22 * * *", TimeZone: ptr.To("Mars/Olympus_Mons")},
+			expectErr: true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseMaintenanceWindowSchedule(tc.window)
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestScalingDirectionAllowed(t *testing.T) {
+	nightlyScaleDown := vpa_types.MaintenanceWindow{
+		Schedule:        "0 22 * * *",
+		DurationSeconds: 8 * 3600,
+		Directions:      []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown},
+	}
+	testCases := []struct {
+		name          string
+		windows       []vpa_types.MaintenanceWindow
+		direction     vpa_types.ScalingDirection
+		now           time.Time
+		expectAllowed bool
+		expectNext    time.Time
+	}{
+		{
+			name:          "no windows",
+			direction:     vpa_types.ScalingDirectionDown,
+			now:           time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC),
+			expectAllowed: true,
+		},
+		{
+			name:          "direction not restricted by any window",
+			windows:       []vpa_types.MaintenanceWindow{nightlyScaleDown},
+			direction:     vpa_types.ScalingDirectionUp,
+			now:           time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC),
+			expectAllowed: true,
+		},
+		{
+			name:          "outside of the window",
+			windows:       []vpa_types.MaintenanceWindow{nightlyScaleDown},
+			direction:     vpa_types.ScalingDirectionDown,
+			now:           time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC),
+			expectAllowed: false,
+			expectNext:    time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "in the window before midnight",
+			windows:       []vpa_types.MaintenanceWindow{nightlyScaleDown},
+			direction:     vpa_types.ScalingDirectionDown,
+			now:           time.Date(2026, 10, 19, 22, 0, 0, 0, time.UTC),
+			expectAllowed: true,
+		},
+		{
+			name:          "in the window after midnight",
+			windows:       []vpa_types.MaintenanceWindow{nightlyScaleDown},
+			direction:     vpa_types.ScalingDirectionDown,
+			now:           time.Date(2026, 10, 20, 5, 59, 0, 0, time.UTC),
+			expectAllowed: true,
+		},
+		{
+			name:          "right after the window",
+			windows:       []vpa_types.MaintenanceWindow{nightlyScaleDown},
+			direction:     vpa_types.ScalingDirectionDown,
+			now:           time.Date(2026, 10, 20, 6, 0, 0, 0, time.UTC),
+			expectAllowed: false,
+			expectNext:    time.Date(2026, 10, 20, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "earliest of several windows",
+			windows: []vpa_types.MaintenanceWindow{
+				nightlyScaleDown,
+				{
+					Schedule:        "0 18 * * 1",
+					DurationSeconds: 3600,
+					Directions:      []vpa_types.ScalingDirection{vpa_types.ScalingDirectionUp, vpa_types.ScalingDirectionDown},
+				},
+			},
+			direction:     vpa_types.ScalingDirectionDown,
+			now:           time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC),
+			expectAllowed: false,
+			expectNext:    time.Date(2026, 10, 19, 18, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "time zone of the window",
+			windows: []vpa_types.MaintenanceWindow{{
+				Schedule:        "0 22 * * *",
+				DurationSeconds: 3600,
+				TimeZone:        ptr.To("Europe/Berlin"),
+				Directions:      []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown},
+			}},
+			direction:     vpa_types.ScalingDirectionDown,
+			now:           time.Date(2026, 10, 19, 20, 30, 0, 0, time.UTC),
+			expectAllowed: true,
+		},
+		{
+			name: "invalid schedule",
+			windows: []vpa_types.MaintenanceWindow{{
+				Schedule:        "not a schedule",
+				DurationSeconds: 3600,
+				Directions:      []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown},
+			}},
+			direction:     vpa_types.ScalingDirectionDown,
+			now:           time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC),
+			expectAllowed: false,
+		},
+		{
+			name: "schedule that never starts",
+			windows: []vpa_types.MaintenanceWindow{{
+				Schedule:        "0 0 31 2 *",
+				DurationSeconds: 3600,
+				Directions:      []vpa_types.ScalingDirection{vpa_types.ScalingDirectionDown},
+			}},
+			direction:     vpa_types.ScalingDirectionDown,
+			now:           time.Date(2026, 10, 19, 14, 0, 0, 0, time.UTC),
+			expectAllowed: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, next := ScalingDirectionAllowed(tc.windows, tc.direction, tc.now)
+			assert.Equal(t, tc.expectAllowed, allowed)
+			assert.True(t, tc.expectNext.Equal(next), "expected next window at %v, got %v", tc.expectNext, next)
+		})
+	}
+}